@@ -29,7 +29,7 @@ fi
 
 log "Analytics sync started (every ${SYNC_INTERVAL}s)"
 log "  Source: $ANALYTICS_DIR"
-log "  Destination: s3://$BUCKET/prompts/$PROFILE/"
+log "  Destination: s3://$BUCKET/prompts/YYYY/MM/DD/$PROFILE/"
 
 mkdir -p "$SYNCED_DIR"
 
@@ -44,7 +44,10 @@ sync_files() {
     while IFS= read -r -d '' file; do
         # Get path relative to analytics dir
         local rel_path="${file#$ANALYTICS_DIR/}"
-        local s3_key="prompts/${PROFILE}/${rel_path}"
+        # Partition by the day the file was written: prompts/YYYY/MM/DD/profile/...
+        local day
+        day="$(date -u -r "$file" '+%Y/%m/%d')"
+        local s3_key="prompts/${day}/${PROFILE}/${rel_path}"
         local synced_marker="$SYNCED_DIR/$rel_path"
 
         # Skip if already synced
@@ -67,7 +70,7 @@ sync_files() {
     done < <(find "$ANALYTICS_DIR" -name '*.json' -not -path '*/.synced/*' -print0 2>/dev/null)
 
     if [ "$count" -gt 0 ]; then
-        log "Synced $count file(s) to s3://$BUCKET/prompts/$PROFILE/"
+        log "Synced $count file(s) to s3://$BUCKET/prompts/"
     fi
 }
 
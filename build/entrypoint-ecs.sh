@@ -14,6 +14,33 @@ STATUS_PORT="${STATUS_PORT:-7683}"
 # When set, ttyd serves on /<prefix> instead of /claude
 URL_PREFIX="${URL_PREFIX:-}"
 
+# Warm pool (frank ecs pool): wait until 'frank ecs start' claims this task
+# and writes the profile's environment over ECS Exec, then carry on as that
+# profile. A stand-in answers the container health check in the meantime.
+if [ "$FRANK_WARM_POOL" = "1" ]; then
+    BOOTSTRAP_FILE="/tmp/frank/bootstrap.env"
+    echo "=== Warm pool task waiting to be claimed ==="
+    python3 -c '
+import http.server, sys
+class H(http.server.BaseHTTPRequestHandler):
+    def do_GET(self):
+        self.send_response(200); self.end_headers(); self.wfile.write(b"warm\n")
+    def log_message(self, *args):
+        pass
+http.server.HTTPServer(("", int(sys.argv[1])), H).serve_forever()
+' "$STATUS_PORT" &
+    WARM_HEALTH_PID=$!
+    while [ ! -f "$BOOTSTRAP_FILE" ]; do
+        sleep 1
+    done
+    kill "$WARM_HEALTH_PID" 2>/dev/null || true
+    wait "$WARM_HEALTH_PID" 2>/dev/null || true
+    echo "Claimed; loading profile environment"
+    # shellcheck disable=SC1090
+    . "$BOOTSTRAP_FILE"
+    rm -f "$BOOTSTRAP_FILE"
+fi
+
 # Get container name (used for worktree naming)
 # In ECS, use ECS_CONTAINER_METADATA or CONTAINER_NAME env var
 CONTAINER_NAME="${CONTAINER_NAME:-$(hostname)}"
@@ -278,10 +305,39 @@ echo "Starting analytics sync daemon..."
 /usr/local/bin/analytics-sync.sh &
 echo "Analytics sync started for profile: ${CONTAINER_NAME:-unknown}"
 
+# Record the agent terminal when the profile opts in (FRANK_RECORD=1).
+# Recordings live on EFS and are uploaded to s3://<bucket>/recordings/<profile>/
+RECORD_CMD=""
+if [ "$FRANK_RECORD" = "1" ]; then
+    export FRANK_RECORDINGS_DIR="/workspace/.recordings/${CONTAINER_NAME:-default}"
+    mkdir -p "$FRANK_RECORDINGS_DIR"
+    RECORD_CMD="record-session.py"
+    /usr/local/bin/recording-sync.sh &
+    echo "Recording terminal sessions to $FRANK_RECORDINGS_DIR"
+fi
+
 # AWS credentials come automatically from ECS task IAM role
 echo "AWS credentials: using ECS task IAM role"
 echo "  Region: ${AWS_REGION:-us-east-1}"
 
+# Provider keys (FRANK_SECRETS: comma-separated NAME=secret-id pairs) are read
+# from Secrets Manager here so they never appear in task overrides
+if [ -n "$FRANK_SECRETS" ]; then
+    IFS=',' read -ra SECRET_SPECS <<< "$FRANK_SECRETS"
+    for spec in "${SECRET_SPECS[@]}"; do
+        secret_name="${spec%%=*}"
+        secret_id="${spec#*=}"
+        if secret_value=$(aws secretsmanager get-secret-value --secret-id "$secret_id" \
+                --query SecretString --output text 2>/dev/null); then
+            export "$secret_name=$secret_value"
+            echo "  Loaded $secret_name from $secret_id"
+        else
+            echo "WARNING: Could not read secret $secret_id for $secret_name"
+        fi
+    done
+    unset secret_value
+fi
+
 # Configure git
 if [ -z "$(git config --global user.name 2>/dev/null)" ]; then
     git config --global user.name "${GIT_USER_NAME:-Developer}"
@@ -293,6 +349,35 @@ git config --global init.defaultBranch main
 git config --global --add safe.directory /workspace
 git config --global --add safe.directory '*'
 
+# GitLab/Bitbucket credential helper for the repo host. It reads the token
+# variable (loaded through FRANK_SECRETS) when git asks.
+if [ -n "$GIT_CREDENTIAL_HOST" ] && [ -n "$GIT_CREDENTIAL_HELPER" ]; then
+    git config --global "credential.https://${GIT_CREDENTIAL_HOST}.helper" "$GIT_CREDENTIAL_HELPER"
+fi
+
+# -----------------------------------------------------------------------------
+# Scheduled Prewarm
+# Tasks started by 'frank ecs prewarm schedule' only pre-warm repos and exit.
+# PREWARM_SPECS holds ';'-separated "profile repo workers branch" entries.
+# -----------------------------------------------------------------------------
+if [ -n "$PREWARM_SPECS" ]; then
+    # The shutdown trap exits 0, which would hide failures
+    trap - SIGTERM SIGINT EXIT
+    echo "=== Scheduled prewarm ==="
+    PREWARM_STATUS=0
+    IFS=';' read -ra PREWARM_ENTRIES <<< "$PREWARM_SPECS"
+    for entry in "${PREWARM_ENTRIES[@]}"; do
+        read -r p_name p_repo p_workers p_branch <<< "$entry"
+        [ -z "$p_name" ] && continue
+        if ! /usr/local/bin/prewarm.sh "$p_name" "$p_repo" "$p_workers" "$p_branch"; then
+            echo "ERROR: Prewarm of $p_name failed"
+            PREWARM_STATUS=1
+        fi
+        echo ""
+    done
+    exit $PREWARM_STATUS
+fi
+
 # -----------------------------------------------------------------------------
 # Claude Code Plugins Setup
 # Clones official plugins repo and installs selected plugins to ~/.claude/
@@ -712,9 +797,39 @@ setup_worktree_from_local() {
     cd "$WORKTREE_PATH"
 }
 
+# Reattach the worktree of a stopped task (frank ecs start --resume)
+# Returns 0 if successful, 1 if the recorded workspace is missing
+setup_resumed_worktree() {
+    if [ "$RESUME_SESSION" != "1" ] || [ -z "$WORKSPACE_PATH" ]; then
+        return 1
+    fi
+
+    if [ ! -d "$WORKSPACE_PATH" ] || [ ! -e "$WORKSPACE_PATH/.git" ]; then
+        echo "WARNING: Workspace $WORKSPACE_PATH not found - starting fresh"
+        RESUME_SESSION=""
+        return 1
+    fi
+
+    echo "=== Resuming workspace ==="
+    echo "Workspace: $WORKSPACE_PATH"
+    echo "Branch: $(git -C "$WORKSPACE_PATH" branch --show-current 2>/dev/null || echo unknown)"
+
+    # Leave the tree as it was: no clone, pull or .claude refresh, so
+    # uncommitted work survives the restart
+    WORKTREE_PATH="$WORKSPACE_PATH"
+    export WORKTREE_PATH
+
+    echo "Working directory: $WORKTREE_PATH"
+    cd "$WORKTREE_PATH"
+}
+
 # Setup worktree based on configuration
-# Priority: 1) Pre-warmed worktree, 2) Clone from URL, 3) Existing local repo
-if setup_prewarmed_worktree; then
+# Priority: 1) Resumed workspace, 2) Pre-warmed worktree, 3) Clone from URL,
+# 4) Existing local repo
+if setup_resumed_worktree; then
+    # Same worktree as the stopped task
+    WORK_DIR="$WORKTREE_PATH"
+elif setup_prewarmed_worktree; then
     # Successfully using pre-warmed worktree (fastest path)
     WORK_DIR="$WORKTREE_PATH"
 elif [ -n "$GIT_REPO" ]; then
@@ -866,8 +981,85 @@ else
     echo "  .claude directory: MISSING"
 fi
 
-# Start Claude terminal (foreground) with tmux persistence
-echo "Starting Claude terminal on port $TTYD_PORT (path: $CLAUDE_BASE_PATH)..."
+# The profile picks the agent (FRANK_AGENT: claude or codex), its model
+# provider (FRANK_PROVIDER) and its model (FRANK_MODEL). The provider's own
+# settings arrive as environment variables, plus FRANK_AGENT_ARGS.
+AGENT="${FRANK_AGENT:-claude}"
+case "$AGENT" in
+    claude|codex) ;;
+    *)
+        echo "WARNING: Unknown agent '$AGENT', using claude"
+        AGENT=claude
+        ;;
+esac
+if [ -n "$FRANK_PROVIDER" ]; then
+    echo "Model provider: $FRANK_PROVIDER"
+fi
+
+# Codex reads Azure OpenAI settings from a model_providers table
+if [ "$FRANK_PROVIDER" = "azure" ]; then
+    mkdir -p "$HOME/.codex"
+    if ! grep -q '^\[model_providers.azure\]' "$HOME/.codex/config.toml" 2>/dev/null; then
+        cat >> "$HOME/.codex/config.toml" << EOF
+
+[model_providers.azure]
+name = "Azure OpenAI"
+base_url = "${AZURE_OPENAI_ENDPOINT}/openai"
+env_key = "AZURE_OPENAI_API_KEY"
+query_params = { api-version = "${AZURE_OPENAI_API_VERSION}" }
+wire_api = "responses"
+EOF
+    fi
+fi
+
+# Keep agent conversations on EFS next to the worktree so a resumed task
+# can continue them (the home directory is lost when the task stops)
+SESSIONS_DIR="/workspace/.sessions/${CONTAINER_NAME:-default}"
+mkdir -p "$SESSIONS_DIR/claude" "$SESSIONS_DIR/codex" "$HOME/.claude" "$HOME/.codex"
+rm -rf "$HOME/.claude/projects" "$HOME/.codex/sessions"
+ln -s "$SESSIONS_DIR/claude" "$HOME/.claude/projects"
+ln -s "$SESSIONS_DIR/codex" "$HOME/.codex/sessions"
+
+AGENT_CMD="$AGENT"
+if [ "$RESUME_SESSION" = "1" ]; then
+    if [ "$AGENT" = "codex" ] && [ -n "$(ls -A "$SESSIONS_DIR/codex" 2>/dev/null)" ]; then
+        echo "Resuming last codex session"
+        AGENT_CMD="codex resume --last"
+    elif [ "$AGENT" = "claude" ] && [ -n "$(ls -A "$SESSIONS_DIR/claude" 2>/dev/null)" ]; then
+        echo "Resuming last claude session"
+        AGENT_CMD="claude --continue"
+    else
+        echo "No previous $AGENT session found - starting a new one"
+    fi
+fi
+if [ -n "$FRANK_MODEL" ]; then
+    AGENT_CMD="$AGENT_CMD --model $FRANK_MODEL"
+fi
+if [ -n "$FRANK_AGENT_ARGS" ]; then
+    AGENT_CMD="$AGENT_CMD $FRANK_AGENT_ARGS"
+fi
+
+# Send TASK_PROMPT (frank ecs run --task-prompt) as the agent's first message.
+# tmux runs the command without a shell, so a wrapper script passes the prompt
+# as a single argument; it is removed after the first launch so a restarted
+# agent doesn't repeat it.
+if [ -n "$TASK_PROMPT" ] && [ "$RESUME_SESSION" != "1" ]; then
+    printf '%s' "$TASK_PROMPT" > /tmp/frank-task-prompt
+    cat > /tmp/frank-agent.sh <<EOF
+#!/bin/bash
+if [ -f /tmp/frank-task-prompt ]; then
+    prompt="\$(cat /tmp/frank-task-prompt)"
+    rm -f /tmp/frank-task-prompt
+    exec $AGENT_CMD "\$prompt"
+fi
+exec $AGENT_CMD
+EOF
+    chmod +x /tmp/frank-agent.sh
+    AGENT_CMD=/tmp/frank-agent.sh
+fi
+
+# Start agent terminal (foreground) with tmux persistence
+echo "Starting $AGENT terminal on port $TTYD_PORT (path: $CLAUDE_BASE_PATH)..."
 echo "=== Frank ECS Container Ready ==="
 
 # Note: user-session.sh is available for per-user workspace isolation
@@ -881,4 +1073,4 @@ exec ttyd -p "${TTYD_PORT}" -W \
     -t theme="${TTYD_THEME}" \
     --ping-interval 5 \
     --base-path "$CLAUDE_BASE_PATH" \
-    tmux-session.sh frank-claude claude
+    tmux-session.sh frank-claude $RECORD_CMD $AGENT_CMD
@@ -51,6 +51,12 @@ git config --global init.defaultBranch main
 git config --global --add safe.directory /workspace
 git config --global --add safe.directory '*'
 
+# GitLab/Bitbucket credential helper for the repo host (reads the token
+# variable when git asks, so the helper itself holds no secret)
+if [ -n "$GIT_CREDENTIAL_HOST" ] && [ -n "$GIT_CREDENTIAL_HELPER" ]; then
+    git config --global "credential.https://${GIT_CREDENTIAL_HOST}.helper" "$GIT_CREDENTIAL_HELPER"
+fi
+
 # Fix git remote if it points to Windows path (C:\ or D:\)
 if [ -d /workspace/.git ]; then
     REMOTE_URL=$(git -C /workspace remote get-url origin 2>/dev/null || true)
@@ -78,7 +84,7 @@ else
 fi
 
 # Check AWS configuration
-if [ -n "$AWS_ACCESS_KEY_ID" ] || [ -d "$HOME/.aws" ]; then
+if [ -n "$AWS_ACCESS_KEY_ID" ] || [ -n "$AWS_SHARED_CREDENTIALS_FILE" ] || [ -d "$HOME/.aws" ]; then
     echo "AWS credentials configured (profile: ${AWS_PROFILE:-default}, region: ${AWS_REGION:-us-east-1})"
 fi
 
@@ -200,6 +206,9 @@ fi
 cd "$WORK_DIR"
 echo "Current directory: $(pwd)"
 
+# Let exec'd tools such as the pre-stop hook find the working directory
+echo "$WORK_DIR" > /tmp/frank-workdir
+
 # Common ttyd theme settings
 TTYD_THEME='{"background":"#1e1e1e","foreground":"#d4d4d4","cursor":"#d4d4d4","selectionBackground":"#264f78","black":"#1e1e1e","red":"#f44747","green":"#6a9955","yellow":"#dcdcaa","blue":"#569cd6","magenta":"#c586c0","cyan":"#4ec9b0","white":"#d4d4d4","brightBlack":"#808080","brightRed":"#f44747","brightGreen":"#6a9955","brightYellow":"#dcdcaa","brightBlue":"#569cd6","brightMagenta":"#c586c0","brightCyan":"#4ec9b0","brightWhite":"#ffffff"}'
 
@@ -210,24 +219,36 @@ mkdir -p "$WEB_DIR"
 sed "s|CLAUDE_URL|http://localhost:${HOST_CLAUDE_PORT:-8081}|g; s|BASH_URL|http://localhost:${HOST_BASH_PORT:-8082}|g; s|STATUS_ENDPOINT|http://localhost:${HOST_STATUS_PORT:-8083}/status|g" \
     /usr/local/share/frank/index.html > "$WEB_DIR/index.html"
 
+# Run a background service, restarting it whenever it exits. The Claude
+# terminal runs in the foreground, so if it dies the container exits and the
+# runtime's restart policy brings it back; these keep the other URLs alive.
+supervise() {
+    local name="$1"
+    shift
+    while true; do
+        "$@" || true
+        echo "$name exited, restarting in 2s..."
+        sleep 2
+    done
+}
+
 # Start status server (background, with logging)
 echo "Starting status server on port $STATUS_PORT..."
-python3 /usr/local/bin/status-server.py >> /tmp/status-server-stdout.log 2>&1 &
-STATUS_PID=$!
+supervise "Status server" python3 /usr/local/bin/status-server.py >> /tmp/status-server-stdout.log 2>&1 &
 sleep 1
-if kill -0 $STATUS_PID 2>/dev/null; then
-    echo "Status server started (PID: $STATUS_PID)"
+if curl -fsS -o /dev/null "http://localhost:$STATUS_PORT/health" 2>/dev/null; then
+    echo "Status server started"
 else
-    echo "WARNING: Status server failed to start! Check /tmp/status-server-stdout.log"
+    echo "WARNING: Status server not responding yet! Check /tmp/status-server-stdout.log"
 fi
 
 # Start the combined web view (background)
 echo "Starting combined web view on port $WEB_PORT..."
-python3 -m http.server "$WEB_PORT" --directory "$WEB_DIR" &> /dev/null &
+supervise "Web view" python3 -m http.server "$WEB_PORT" --directory "$WEB_DIR" &> /dev/null &
 
 # Start bash terminal on secondary port (background)
 echo "Starting bash terminal on port $BASH_PORT..."
-ttyd \
+supervise "Bash terminal" ttyd \
     -p "${BASH_PORT}" \
     -W \
     -t fontSize=16 \
@@ -239,6 +260,14 @@ ttyd \
     --ping-interval 60 \
     bash &
 
+# Record the Claude terminal when started with --record (FRANK_RECORD=1);
+# frank mounts ~/.frank/recordings at /recordings
+RECORD_CMD=""
+if [ "$FRANK_RECORD" = "1" ]; then
+    RECORD_CMD="record-session.py"
+    echo "Recording terminal sessions to ${FRANK_RECORDINGS_DIR:-/recordings}"
+fi
+
 # Start ttyd with Claude Code (foreground)
 echo "Starting Claude terminal on port $TTYD_PORT..."
 exec ttyd \
@@ -251,4 +280,4 @@ exec ttyd \
     -t cursorStyle=bar \
     -t theme="${TTYD_THEME}" \
     --ping-interval 60 \
-    claude
+    $RECORD_CMD claude
@@ -0,0 +1,32 @@
+#!/bin/bash
+# pre-stop.sh - Run by 'frank stop' before the container is stopped
+#
+# Asks Claude to write a checkpoint of the session it is working in, so the
+# task can be picked up after a restart, then flushes the filesystems. frank
+# stops the container regardless once container.preStop.timeout passes.
+
+WORK_DIR="$(cat /tmp/frank-workdir 2>/dev/null || echo /workspace)"
+CHECKPOINT_FILE="${FRANK_CHECKPOINT_FILE:-.frank/checkpoint.md}"
+
+CHECKPOINT_PROMPT="This container is being stopped. Write a short checkpoint of \
+the task you were working on to $CHECKPOINT_FILE: the goal, what is done, what \
+is in progress and the next steps. Overwrite the file if it exists. Make no \
+other changes."
+
+cd "$WORK_DIR" 2>/dev/null || cd /workspace
+
+# Only checkpoint when a Claude session is running in this container
+if pgrep -f '(^|/)claude( |$)' > /dev/null 2>&1; then
+    echo "Asking Claude to checkpoint to $WORK_DIR/$CHECKPOINT_FILE..."
+    mkdir -p "$(dirname "$CHECKPOINT_FILE")"
+    if claude -p --continue "$CHECKPOINT_PROMPT" > /tmp/frank-pre-stop.log 2>&1; then
+        echo "Checkpoint written"
+    else
+        echo "Checkpoint failed, see /tmp/frank-pre-stop.log"
+    fi
+else
+    echo "No Claude session running, skipping checkpoint"
+fi
+
+# Flush file data so a snapshot or stop doesn't lose writes
+sync
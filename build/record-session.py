@@ -0,0 +1,166 @@
+#!/usr/bin/env python3
+"""
+record-session.py - Runs a command in a pseudo-terminal and records its output
+as an asciinema v2 cast file.
+
+Usage: record-session.py <command> [args...]
+
+Recordings go to $FRANK_RECORDINGS_DIR (default /recordings) and are named
+<container>-<UTC timestamp>.cast, so `frank recordings play` can replay them.
+Recording is best-effort: if the cast file can't be written the command still
+runs, unrecorded.
+"""
+
+import codecs
+import errno
+import fcntl
+import json
+import os
+import pty
+import select
+import signal
+import struct
+import sys
+import termios
+import time
+import tty
+
+RECORDINGS_DIR = os.environ.get("FRANK_RECORDINGS_DIR", "/recordings")
+CONTAINER_NAME = os.environ.get("CONTAINER_NAME", "frank")
+
+
+def get_size(fd):
+    """Returns the (rows, cols) of a terminal, 24x80 when it has none."""
+    try:
+        rows, cols, _, _ = struct.unpack("HHHH", fcntl.ioctl(fd, termios.TIOCGWINSZ, b"\0" * 8))
+        if rows and cols:
+            return rows, cols
+    except OSError:
+        pass
+    return 24, 80
+
+
+def set_size(fd, rows, cols):
+    try:
+        fcntl.ioctl(fd, termios.TIOCSWINSZ, struct.pack("HHHH", rows, cols, 0, 0))
+    except OSError:
+        pass
+
+
+class Recorder:
+    """Writes asciicast v2: a JSON header line, then [time, type, data] events."""
+
+    def __init__(self, rows, cols, command):
+        self.start = time.time()
+        self.file = None
+        self.decoder = None
+        try:
+            os.makedirs(RECORDINGS_DIR, exist_ok=True)
+            stamp = time.strftime("%Y%m%d-%H%M%S", time.gmtime(self.start))
+            path = os.path.join(RECORDINGS_DIR, f"{CONTAINER_NAME}-{stamp}.cast")
+            self.file = open(path, "w", encoding="utf-8")
+        except OSError as e:
+            print(f"record-session: not recording: {e}", file=sys.stderr)
+            return
+
+        self.decoder = codecs.getincrementaldecoder("utf-8")("replace")
+        header = {
+            "version": 2,
+            "width": cols,
+            "height": rows,
+            "timestamp": int(self.start),
+            "title": f"{CONTAINER_NAME}: {' '.join(command)}",
+            "env": {"SHELL": os.environ.get("SHELL", "/bin/bash"), "TERM": os.environ.get("TERM", "xterm-256color")},
+        }
+        self.file.write(json.dumps(header) + "\n")
+        self.file.flush()
+
+    def event(self, kind, data):
+        if not self.file or not data:
+            return
+        try:
+            self.file.write(json.dumps([round(time.time() - self.start, 6), kind, data]) + "\n")
+            self.file.flush()
+        except OSError:
+            self.file = None
+
+    def output(self, data):
+        if self.decoder:
+            self.event("o", self.decoder.decode(data))
+
+    def resize(self, rows, cols):
+        self.event("r", f"{cols}x{rows}")
+
+    def close(self):
+        if self.file:
+            self.file.close()
+
+
+def main():
+    if len(sys.argv) < 2:
+        print(__doc__.strip(), file=sys.stderr)
+        return 2
+    command = sys.argv[1:]
+
+    stdin = sys.stdin.fileno()
+    stdout = sys.stdout.fileno()
+    rows, cols = get_size(stdin)
+
+    pid, master = pty.fork()
+    if pid == 0:
+        try:
+            os.execvp(command[0], command)
+        except OSError as e:
+            print(f"record-session: {command[0]}: {e}", file=sys.stderr)
+            os._exit(127)
+
+    set_size(master, rows, cols)
+    recorder = Recorder(rows, cols, command)
+
+    def on_resize(signum, frame):
+        rows, cols = get_size(stdin)
+        set_size(master, rows, cols)
+        recorder.resize(rows, cols)
+
+    signal.signal(signal.SIGWINCH, on_resize)
+
+    saved = None
+    if os.isatty(stdin):
+        saved = termios.tcgetattr(stdin)
+        tty.setraw(stdin)
+
+    try:
+        fds = [master, stdin]
+        while True:
+            try:
+                ready, _, _ = select.select(fds, [], [])
+            except InterruptedError:
+                continue
+            if master in ready:
+                try:
+                    data = os.read(master, 65536)
+                except OSError as e:
+                    if e.errno != errno.EIO:
+                        raise
+                    data = b""
+                if not data:
+                    break
+                os.write(stdout, data)
+                recorder.output(data)
+            if stdin in ready:
+                data = os.read(stdin, 65536)
+                if not data:
+                    fds.remove(stdin)
+                else:
+                    os.write(master, data)
+    finally:
+        if saved is not None:
+            termios.tcsetattr(stdin, termios.TCSAFLUSH, saved)
+        recorder.close()
+
+    _, status = os.waitpid(pid, 0)
+    return os.waitstatus_to_exitcode(status) if hasattr(os, "waitstatus_to_exitcode") else status >> 8
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,68 @@
+#!/bin/bash
+# recording-sync.sh - Uploads terminal recordings to S3
+# Runs as a background daemon while FRANK_RECORD=1; best-effort, failures
+# don't affect container operation. Recordings that are still growing are
+# uploaded again on each pass. On ECS the recordings directory is on EFS, so
+# anything written after the last pass is uploaded by the profile's next task.
+
+set -o pipefail
+
+RECORDINGS_DIR="${FRANK_RECORDINGS_DIR:-/recordings}"
+SYNCED_DIR="$RECORDINGS_DIR/.synced"
+LOG_FILE="/tmp/recording-sync.log"
+SYNC_INTERVAL=60
+REGION="${AWS_REGION:-us-east-1}"
+BUCKET="${RECORDINGS_BUCKET:-$ANALYTICS_BUCKET}"
+PROFILE="${URL_PREFIX#/}"
+PROFILE="${PROFILE:-${CONTAINER_NAME:-unknown}}"
+
+log() {
+    echo "[$(date -u '+%Y-%m-%d %H:%M:%S')] $*" >> "$LOG_FILE"
+}
+
+if [ -z "$BUCKET" ]; then
+    log "Neither RECORDINGS_BUCKET nor ANALYTICS_BUCKET set - recordings stay in $RECORDINGS_DIR"
+    exit 0
+fi
+
+log "Recording sync started (every ${SYNC_INTERVAL}s)"
+log "  Source: $RECORDINGS_DIR"
+log "  Destination: s3://$BUCKET/recordings/$PROFILE/"
+
+mkdir -p "$SYNCED_DIR"
+
+sync_files() {
+    local count=0
+
+    while IFS= read -r -d '' file; do
+        local name
+        name="$(basename "$file")"
+        local synced_marker="$SYNCED_DIR/$name"
+
+        # Skip if unchanged since the last upload
+        if [ -f "$synced_marker" ] && [ ! "$file" -nt "$synced_marker" ]; then
+            continue
+        fi
+
+        touch "$synced_marker.new"
+        if aws s3 cp "$file" "s3://$BUCKET/recordings/$PROFILE/$name" \
+            --region "$REGION" \
+            --content-type "application/x-asciicast" \
+            --quiet 2>/dev/null; then
+            mv "$synced_marker.new" "$synced_marker"
+            count=$((count + 1))
+        else
+            rm -f "$synced_marker.new"
+            log "Failed to upload: $name"
+        fi
+    done < <(find "$RECORDINGS_DIR" -maxdepth 1 -name '*.cast' -print0 2>/dev/null)
+
+    if [ "$count" -gt 0 ]; then
+        log "Synced $count recording(s) to s3://$BUCKET/recordings/$PROFILE/"
+    fi
+}
+
+while true; do
+    sync_files
+    sleep "$SYNC_INTERVAL"
+done
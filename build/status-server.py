@@ -15,9 +15,10 @@ import threading
 import uuid
 import hashlib
 import base64
-from http.server import HTTPServer, BaseHTTPRequestHandler
+import queue
+from http.server import HTTPServer, ThreadingHTTPServer, BaseHTTPRequestHandler
 from pathlib import Path
-from datetime import datetime
+from datetime import datetime, timezone
 from collections import deque
 
 # Try to import boto3 for S3 uploads (optional)
@@ -80,6 +81,9 @@ active_users_lock = threading.Lock()
 ACTIVE_USERS_FILE = '/workspace/.active-users.json'
 USER_TIMEOUT_SECONDS = 120  # Remove users after 2 minutes of inactivity
 
+# Activity tracking for idle shutdown (frank ecs autostop)
+last_activity = time.time()
+
 # Version tracking for update detection
 VERSION_CACHE = {
     'current_revision': None,
@@ -319,6 +323,111 @@ def get_claude_state(session='frank-claude'):
     }
 
 
+# =========================================================================
+# Event Stream (consumed by frank's notification monitor)
+# =========================================================================
+
+EVENT_TYPES = ('awaiting_input', 'task_complete', 'error')
+EVENT_POLL_SECONDS = 3
+EVENT_KEEPALIVE_SECONDS = 15
+
+event_subscribers = []  # One queue per connected /events client
+event_subscribers_lock = threading.Lock()
+
+# Prompts Claude shows mid-task when it needs an answer or a permission grant
+AWAITING_INPUT_PATTERNS = [
+    re.compile(r'Do you want to'),
+    re.compile(r'❯\s*1\.\s*Yes'),
+    re.compile(r'\(y/n\)', re.IGNORECASE),
+]
+
+# Errors Claude Code prints in the conversation
+ERROR_PATTERNS = [
+    re.compile(r'API Error'),
+    re.compile(r'^\s*⎿\s*Error:'),
+]
+
+
+def publish_event(event_type, message=''):
+    """Send an event to every /events subscriber."""
+    event = {
+        'type': event_type,
+        'message': message,
+        'timestamp': datetime.now(timezone.utc).isoformat(),
+    }
+    log(f"Event: {event_type} {message[:80]}")
+    with event_subscribers_lock:
+        for q in event_subscribers:
+            try:
+                q.put_nowait(event)
+            except queue.Full:
+                pass  # Slow subscriber; drop rather than block the watcher
+
+
+def _capture_pane_lines(session, count=15):
+    """Return the last non-empty lines of a tmux pane, or None on failure."""
+    import subprocess
+
+    try:
+        result = subprocess.run(
+            ['tmux', 'capture-pane', '-t', session, '-p'],
+            capture_output=True, text=True, timeout=5
+        )
+        if result.returncode != 0:
+            return None
+        lines = [l for l in result.stdout.split('\n') if l.strip()]
+        return lines[-count:]
+    except Exception:
+        return None
+
+
+def watch_claude_events(session='frank-claude'):
+    """
+    Publish awaiting_input, task_complete and error events from Claude's
+    tmux pane. Only polls while someone is subscribed to /events.
+    """
+    state = None
+    seen_errors = deque(maxlen=50)
+
+    while True:
+        time.sleep(EVENT_POLL_SECONDS)
+
+        with event_subscribers_lock:
+            subscribed = bool(event_subscribers)
+        if not subscribed:
+            state = None
+            continue
+
+        lines = _capture_pane_lines(session)
+        if lines is None:
+            continue
+
+        prompt = next((l.strip() for l in lines if any(p.search(l) for p in AWAITING_INPUT_PATTERNS)), None)
+        if prompt:
+            new_state = 'awaiting_input'
+        elif is_claude_idle(session):
+            new_state = 'idle'
+        else:
+            new_state = 'busy'
+
+        # The first poll only establishes a baseline
+        if state is not None:
+            if new_state == 'awaiting_input' and state != 'awaiting_input':
+                publish_event('awaiting_input', prompt)
+            elif new_state == 'idle' and state == 'busy':
+                publish_event('task_complete', 'Claude finished and is ready for the next prompt')
+
+        for line in lines:
+            stripped = line.strip()
+            if stripped in seen_errors or not any(p.search(line) for p in ERROR_PATTERNS):
+                continue
+            seen_errors.append(stripped)
+            if state is not None:
+                publish_event('error', stripped)
+
+        state = new_state
+
+
 # =========================================================================
 # EnkaiRelay Tick Functions (sends /enkai-relay to Claude when idle)
 # =========================================================================
@@ -1075,6 +1184,22 @@ def persist_active_users():
         log(f"Error persisting active users: {e}")
 
 
+def mark_activity():
+    """Record that a user or Claude did something."""
+    global last_activity
+    last_activity = time.time()
+
+
+def get_activity():
+    """Get the last activity time. A busy Claude counts as activity."""
+    if not is_claude_idle('frank-claude'):
+        mark_activity()
+    return {
+        'last_activity': datetime.fromtimestamp(last_activity, tz=timezone.utc).isoformat(),
+        'idle_seconds': int(time.time() - last_activity),
+    }
+
+
 def get_active_users_list():
     """Get list of active users (for API response)."""
     with active_users_lock:
@@ -1425,6 +1550,15 @@ class StatusHandler(BaseHTTPRequestHandler):
                 }).encode())
                 return
 
+            # Activity endpoint (used by frank ecs autostop)
+            if path == '/status/activity':
+                self.send_response(200)
+                self.send_header('Content-Type', 'application/json')
+                self.send_header('Access-Control-Allow-Origin', '*')
+                self.end_headers()
+                self.wfile.write(json.dumps(get_activity()).encode())
+                return
+
             # Claude state endpoint
             if path == '/status/claude-state':
                 self.send_response(200)
@@ -1494,6 +1628,7 @@ class StatusHandler(BaseHTTPRequestHandler):
                 path = path[len(URL_PREFIX):] or '/'
 
             if path == '/status/send-prompt':
+                mark_activity()
                 content_length = int(self.headers.get('Content-Length', 0))
                 body = self.rfile.read(content_length).decode('utf-8')
 
@@ -1687,6 +1822,8 @@ class StatusHandler(BaseHTTPRequestHandler):
                     with prompt_textbox_lock:
                         prompt_textbox_state['has_text'] = bool(data['promptHasText'])
                         prompt_textbox_state['last_updated'] = time.time()
+                    if data['promptHasText']:
+                        mark_activity()
 
                 user_info = extract_user_from_headers(dict(self.headers))
                 if user_info:
@@ -2870,8 +3007,8 @@ def upload_buffered_prompts():
             now = datetime.now()
             profile = CONTAINER_NAME or 'unknown'
 
-            # S3 key: prompts/{profile}/{year}/{month}/{day}/{session}_{timestamp}.json
-            key = f"prompts/{profile}/{now.year}/{now.month:02d}/{now.day:02d}/{current_session_id}_{int(now.timestamp())}.json"
+            # S3 key: prompts/{year}/{month}/{day}/{profile}/{session}_{timestamp}.json
+            key = f"prompts/{now.year}/{now.month:02d}/{now.day:02d}/{profile}/{current_session_id}_{int(now.timestamp())}.json"
 
             body = json.dumps(prompts_to_upload, indent=2)
 
@@ -2966,15 +3103,71 @@ class HealthOnlyHandler(BaseHTTPRequestHandler):
             self.send_header('Access-Control-Allow-Origin', '*')
             self.end_headers()
             self.wfile.write(json.dumps(health).encode())
+        elif self.path == '/events':
+            self.stream_events()
         else:
             self.send_response(404)
             self.end_headers()
 
+    def do_POST(self):
+        """Accept events from scripts in the container (e.g. Claude hooks)."""
+        if self.path != '/events':
+            self.send_response(404)
+            self.end_headers()
+            return
+
+        content_length = int(self.headers.get('Content-Length', 0))
+        body = self.rfile.read(content_length).decode('utf-8') if content_length > 0 else '{}'
+        try:
+            data = json.loads(body)
+        except json.JSONDecodeError:
+            data = {}
+
+        event_type = data.get('type')
+        if event_type not in EVENT_TYPES:
+            self.send_response(400)
+            self.send_header('Content-Type', 'application/json')
+            self.end_headers()
+            self.wfile.write(json.dumps({'error': f"type must be one of {', '.join(EVENT_TYPES)}"}).encode())
+            return
+
+        publish_event(event_type, str(data.get('message', '')))
+        self.send_response(202)
+        self.end_headers()
+
+    def stream_events(self):
+        """Serve events as Server-Sent Events until the client disconnects."""
+        q = queue.Queue(maxsize=100)
+        with event_subscribers_lock:
+            event_subscribers.append(q)
+
+        try:
+            self.send_response(200)
+            self.send_header('Content-Type', 'text/event-stream')
+            self.send_header('Cache-Control', 'no-cache')
+            self.end_headers()
+            self.wfile.flush()
+
+            while True:
+                try:
+                    event = q.get(timeout=EVENT_KEEPALIVE_SECONDS)
+                    self.wfile.write(f"event: {event['type']}\ndata: {json.dumps(event)}\n\n".encode())
+                except queue.Empty:
+                    self.wfile.write(b": keepalive\n\n")
+                self.wfile.flush()
+        except (BrokenPipeError, ConnectionResetError):
+            pass
+        finally:
+            with event_subscribers_lock:
+                event_subscribers.remove(q)
+
 
 def run_health_server():
     """Run a dedicated health check server on STATUS_PORT (7683)."""
     try:
-        health_server = HTTPServer(('0.0.0.0', STATUS_PORT), HealthOnlyHandler)
+        # Threaded so long-lived /events streams don't block health checks
+        health_server = ThreadingHTTPServer(('0.0.0.0', STATUS_PORT), HealthOnlyHandler)
+        health_server.daemon_threads = True
         log(f"Health server started on port {STATUS_PORT}")
         health_server.serve_forever()
     except Exception as e:
@@ -3019,6 +3212,10 @@ def main():
         if not HAS_BOTO3:
             log("boto3 not available - S3 uploads disabled")
 
+    # Watch Claude's pane for events streamed on /events
+    events_thread = threading.Thread(target=watch_claude_events, daemon=True)
+    events_thread.start()
+
     # Start dedicated health server on STATUS_PORT (7683) for ECS health checks
     health_thread = threading.Thread(target=run_health_server, daemon=True)
     health_thread.start()
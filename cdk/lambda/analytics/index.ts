@@ -81,8 +81,8 @@ export async function handler(event: ScheduledEvent): Promise<void> {
 
     console.log(`Processing analytics for date: ${dateStr}`);
 
-    // Get all profiles from prompts
-    const profiles = await discoverProfiles();
+    // Get the profiles that recorded prompts that day
+    const profiles = await discoverProfiles(year, month, day);
     console.log(`Found profiles: ${profiles.join(', ')}`);
 
     // Process each profile
@@ -100,19 +100,21 @@ export async function handler(event: ScheduledEvent): Promise<void> {
   }
 }
 
-async function discoverProfiles(): Promise<string[]> {
+// Prompts are partitioned by date first: prompts/{year}/{month}/{day}/{profile}/
+async function discoverProfiles(year: string, month: string, day: string): Promise<string[]> {
   const profiles = new Set<string>();
+  const dayPrefix = `prompts/${year}/${month}/${day}/`;
 
   try {
     const result = await s3.send(new ListObjectsV2Command({
       Bucket: BUCKET,
-      Prefix: 'prompts/',
+      Prefix: dayPrefix,
       Delimiter: '/',
     }));
 
     for (const prefix of result.CommonPrefixes || []) {
       if (prefix.Prefix) {
-        const profile = prefix.Prefix.replace('prompts/', '').replace('/', '');
+        const profile = prefix.Prefix.slice(dayPrefix.length).replace('/', '');
         if (profile) profiles.add(profile);
       }
     }
@@ -154,7 +156,7 @@ async function collectPrompts(
   day: string
 ): Promise<PromptRecord[]> {
   const prompts: PromptRecord[] = [];
-  const prefix = `prompts/${profile}/${year}/${month}/${day}/`;
+  const prefix = `prompts/${year}/${month}/${day}/${profile}/`;
 
   try {
     const result = await s3.send(new ListObjectsV2Command({
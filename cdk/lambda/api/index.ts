@@ -53,6 +53,13 @@ interface Profile {
   description?: string;
   category?: string;
   site_url?: string;
+  agent?: string;
+  model?: string;
+  provider?: string;
+  endpoint?: string;
+  // Resolved worker environment and FRANK_SECRETS, written by 'frank profile sync'
+  env?: Record<string, string>;
+  secrets?: string;
 }
 
 interface ProfileStatus extends Profile {
@@ -837,6 +844,13 @@ async function startProfile(profileName: string): Promise<ApiResponse> {
               { name: 'GIT_REPO', value: profile.repo },
               { name: 'GIT_BRANCH', value: profile.branch || 'main' },
               { name: 'URL_PREFIX', value: `/${profileName}` },
+              ...(profile.env
+                ? Object.entries(profile.env).map(([name, value]) => ({ name, value }))
+                : [
+                    ...(profile.agent ? [{ name: 'FRANK_AGENT', value: profile.agent }] : []),
+                    ...(profile.model ? [{ name: 'FRANK_MODEL', value: profile.model }] : []),
+                  ]),
+              ...(profile.secrets ? [{ name: 'FRANK_SECRETS', value: profile.secrets }] : []),
             ],
           },
         ],
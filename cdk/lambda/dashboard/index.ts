@@ -220,8 +220,8 @@ async function getPrompts(profile: string, date: string): Promise<PromptRecord[]
   const [year, month, day] = date.split('-');
 
   const prefix = profile === 'all'
-    ? `prompts/`
-    : `prompts/${profile}/${year}/${month}/${day}/`;
+    ? `prompts/${year}/${month}/${day}/`
+    : `prompts/${year}/${month}/${day}/${profile}/`;
 
   try {
     const listResult = await s3.send(new ListObjectsV2Command({
@@ -47,6 +47,8 @@ export class FrankStack extends cdk.Stack {
       vpc,
       clusterName: 'frank',
       containerInsights: true,
+      // FARGATE_SPOT backs `frank ecs run --capacity spot|auto`
+      enableFargateCapacityProviders: true,
     });
 
     // Secrets - stored in Secrets Manager
@@ -65,6 +67,12 @@ export class FrankStack extends cdk.Stack {
       description: 'OpenAI API key for Codex CLI',
     });
 
+    // Read by the entrypoint for profiles using the azure provider (FRANK_SECRETS)
+    const azureOpenaiApiKeySecret = new secretsmanager.Secret(this, 'AzureOpenAIApiKey', {
+      secretName: '/frank/azure-openai-api-key',
+      description: 'Azure OpenAI API key for Codex CLI',
+    });
+
     // GitHub App credentials (preferred over PAT - auto-refreshing tokens)
     const githubAppIdSecret = new secretsmanager.Secret(this, 'GitHubAppId', {
       secretName: '/frank/github-app-id',
@@ -648,7 +656,57 @@ export class FrankStack extends cdk.Stack {
       description: 'Daily analytics aggregation for Frank prompts',
     });
 
+    // =========================================================================
+    // Scheduled Prewarm
+    // Role assumed by the EventBridge Scheduler schedule that
+    // 'frank ecs prewarm schedule' creates to run prewarm tasks
+    // =========================================================================
+    const schedulerRole = new iam.Role(this, 'FrankSchedulerRole', {
+      roleName: 'frank-scheduler',
+      assumedBy: new iam.ServicePrincipal('scheduler.amazonaws.com'),
+      description: 'Runs scheduled Frank prewarm tasks',
+    });
+    schedulerRole.addToPrincipalPolicy(new iam.PolicyStatement({
+      actions: ['ecs:RunTask'],
+      resources: [`arn:aws:ecs:${this.region}:${this.account}:task-definition/${taskDefinition.family}:*`],
+      conditions: {
+        ArnEquals: { 'ecs:cluster': cluster.clusterArn },
+      },
+    }));
+    schedulerRole.addToPrincipalPolicy(new iam.PolicyStatement({
+      actions: ['iam:PassRole'],
+      resources: [
+        taskDefinition.taskRole.roleArn,
+        taskDefinition.executionRole!.roleArn,
+      ],
+    }));
+
+    // =========================================================================
+    // Task Notifications
+    // Role the 'frank notify setup' rule uses to post task state changes to
+    // its Slack API destination
+    // =========================================================================
+    const notifyRole = new iam.Role(this, 'FrankNotifyRole', {
+      roleName: 'frank-notify',
+      assumedBy: new iam.ServicePrincipal('events.amazonaws.com'),
+      description: 'Delivers Frank task state notifications to Slack',
+    });
+    notifyRole.addToPrincipalPolicy(new iam.PolicyStatement({
+      actions: ['events:InvokeApiDestination'],
+      resources: [`arn:aws:events:${this.region}:${this.account}:api-destination/frank-*`],
+    }));
+
     // Outputs
+    new cdk.CfnOutput(this, 'SchedulerRoleArn', {
+      value: schedulerRole.roleArn,
+      description: 'Role used by the prewarm schedule (frank ecs prewarm schedule)',
+    });
+
+    new cdk.CfnOutput(this, 'NotifyRoleArn', {
+      value: notifyRole.roleArn,
+      description: 'Role used by task notifications to Slack (frank notify setup)',
+    });
+
     new cdk.CfnOutput(this, 'ServiceUrl', {
       value: `https://${props.domainName}`,
       description: 'Frank service URL',
@@ -689,6 +747,11 @@ export class FrankStack extends cdk.Stack {
       description: 'OpenAI API key secret ARN - update with: aws secretsmanager put-secret-value --secret-id /frank/openai-api-key --secret-string "sk-..."',
     });
 
+    new cdk.CfnOutput(this, 'AzureOpenAIApiKeySecretArn', {
+      value: azureOpenaiApiKeySecret.secretArn,
+      description: 'Azure OpenAI API key secret ARN - update with: aws secretsmanager put-secret-value --secret-id /frank/azure-openai-api-key --secret-string "<key>"',
+    });
+
     new cdk.CfnOutput(this, 'AnalyticsBucketName', {
       value: analyticsBucket.bucketName,
       description: 'S3 bucket for prompt analytics',
@@ -3,18 +3,26 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
-	"sort"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/barff/frank/internal/analytics"
+	frankaws "github.com/barff/frank/internal/aws"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/barff/frank/internal/render"
 	"github.com/fatih/color"
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
@@ -27,19 +35,34 @@ Analytics captures prompts from your Claude Code sessions and uploads
 them to S3 for analysis. The dashboard shows prompt patterns, skill
 opportunities, and effectiveness metrics.
 
+Session starts, stops, restarts and pull requests are recorded locally in
+~/.frank/analytics and synced in the background when a bucket is configured.
+
 Examples:
   frank analytics status                    # Show analytics status
   frank analytics list                      # List recent prompts
+  frank analytics list --search "refactor"  # Find prompts by text
   frank analytics sync                      # Sync local analytics to S3
+  frank analytics migrate                   # Move prompts to the dated layout
   frank analytics report                    # Generate local report`,
 }
 
 // Flags
 var (
-	analyticsBucket string
-	analyticsRegion string
-	analyticsDays   int
-	analyticsFormat string
+	analyticsBucket  string
+	analyticsRegion  string
+	analyticsDays    int
+	analyticsFormat  string
+	analyticsProfile string
+	analyticsSince   string
+	analyticsSearch  string
+	analyticsDelete  bool
+	analyticsWorkers int
+	analyticsOutput  render.Options
+
+	analyticsMigrateDryRun  bool
+	analyticsMigrateKeep    bool
+	analyticsMigrateWorkers int
 )
 
 func init() {
@@ -49,12 +72,22 @@ func init() {
 	analyticsCmd.AddCommand(analyticsStatusCmd)
 	analyticsCmd.AddCommand(analyticsListCmd)
 	analyticsCmd.AddCommand(analyticsSyncCmd)
+	analyticsCmd.AddCommand(analyticsMigrateCmd)
 	analyticsCmd.AddCommand(analyticsReportCmd)
 
 	// Common flags
-	analyticsCmd.PersistentFlags().StringVar(&analyticsBucket, "bucket", "", "S3 bucket name (default: from AWS_ANALYTICS_BUCKET)")
-	analyticsCmd.PersistentFlags().StringVar(&analyticsRegion, "region", "us-east-1", "AWS region")
+	analyticsCmd.PersistentFlags().StringVar(&analyticsBucket, "bucket", "", "S3 bucket name (default: analytics.bucket or ANALYTICS_BUCKET)")
+	analyticsCmd.PersistentFlags().StringVar(&analyticsRegion, "region", "", "AWS region (default: analytics.region)")
 	analyticsListCmd.Flags().IntVar(&analyticsDays, "days", 7, "Number of days to list")
+	analyticsListCmd.Flags().StringVar(&analyticsProfile, "profile", "", "Only list prompts from this profile")
+	analyticsListCmd.Flags().StringVar(&analyticsSince, "since", "", "List prompts since a duration, days or date (e.g. 48h, 3d, 2026-10-01; overrides --days)")
+	analyticsListCmd.Flags().StringVar(&analyticsSearch, "search", "", "Only list prompts containing this text (case-insensitive)")
+	render.AddFlags(analyticsListCmd, &analyticsOutput)
+	analyticsSyncCmd.Flags().BoolVar(&analyticsDelete, "delete", false, "Remove uploaded objects whose local file no longer exists")
+	analyticsSyncCmd.Flags().IntVar(&analyticsWorkers, "concurrency", analytics.DefaultSyncConcurrency, "Number of parallel uploads")
+	analyticsMigrateCmd.Flags().BoolVar(&analyticsMigrateDryRun, "dry-run", false, "Show the objects that would move without copying them")
+	analyticsMigrateCmd.Flags().BoolVar(&analyticsMigrateKeep, "keep", false, "Keep the old objects after copying them")
+	analyticsMigrateCmd.Flags().IntVar(&analyticsMigrateWorkers, "concurrency", analytics.DefaultConcurrency, "Number of parallel copies")
 	analyticsReportCmd.Flags().StringVar(&analyticsFormat, "format", "html", "Output format (html, json)")
 }
 
@@ -81,34 +114,43 @@ func runAnalyticsStatus(cmd *cobra.Command, args []string) error {
 	bucket := getBucket()
 	if bucket == "" {
 		fmt.Printf("S3 Bucket:     %s\n", red("Not configured"))
-		fmt.Println("\nTo enable analytics, set ANALYTICS_BUCKET environment variable")
-		fmt.Println("or use --bucket flag.")
+		fmt.Println("\nTo enable analytics, set analytics.bucket in the config file,")
+		fmt.Println("the ANALYTICS_BUCKET environment variable, or use --bucket flag.")
 	} else {
 		fmt.Printf("S3 Bucket:     %s\n", green(bucket))
 	}
 
-	fmt.Printf("AWS Region:    %s\n", analyticsRegion)
+	fmt.Printf("AWS Region:    %s\n", getAnalyticsRegion())
 
 	// Check local analytics directory
 	localDir := getLocalAnalyticsDir()
 	if _, err := os.Stat(localDir); os.IsNotExist(err) {
 		fmt.Printf("Local Storage: %s (not created)\n", yellow(localDir))
 	} else {
-		// Count files
-		count := 0
-		filepath.Walk(localDir, func(path string, info os.FileInfo, err error) error {
-			if err == nil && !info.IsDir() && strings.HasSuffix(path, ".json") {
-				count++
+		files, _ := analytics.ListFiles(localDir)
+		fmt.Printf("Local Storage: %s (%d files)\n", green(localDir), len(files))
+
+		if state, err := analytics.LoadSyncState(localDir); err == nil {
+			lastSync := "never"
+			if !state.LastSync.IsZero() {
+				lastSync = state.LastSync.Local().Format("2006-01-02 15:04")
 			}
-			return nil
-		})
-		fmt.Printf("Local Storage: %s (%d files)\n", green(localDir), count)
+			fmt.Printf("Last Sync:     %s (%d files pending)\n", lastSync, len(state.Pending(files)))
+		}
 	}
 
+	recording := green("enabled")
+	if !cfg.Analytics.Enabled {
+		recording = yellow("disabled")
+	} else if cfg.Analytics.AutoSync && bucket != "" && !cfg.AWS.ReadOnly {
+		recording += fmt.Sprintf(", syncing every %s", cfg.Analytics.SyncInterval)
+	}
+	fmt.Printf("Recording:     %s\n", recording)
+
 	// Check if S3 is accessible
 	if bucket != "" {
 		ctx := context.Background()
-		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(analyticsRegion))
+		cfg, err := config.LoadDefaultConfig(ctx, frankaws.WithRetries(), config.WithRegion(getAnalyticsRegion()))
 		if err == nil {
 			client := s3.NewFromConfig(cfg)
 			_, err := client.HeadBucket(ctx, &s3.HeadBucketInput{
@@ -135,147 +177,102 @@ func runAnalyticsStatus(cmd *cobra.Command, args []string) error {
 var analyticsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List recent prompts",
-	Long:  `List prompts captured in the analytics system.`,
-	RunE:  runAnalyticsList,
+	Long: `List prompts captured in the analytics system.
+
+Prompts are stored by day (prompts/YYYY/MM/DD/<profile>/), so only the days in
+range are read, and objects are downloaded in parallel.
+
+Examples:
+  frank analytics list --since 48h
+  frank analytics list --profile dev --search "migration"`,
+	RunE: runAnalyticsList,
 }
 
 func runAnalyticsList(cmd *cobra.Command, args []string) error {
+	if err := analyticsOutput.Validate(); err != nil {
+		return err
+	}
 	bucket := getBucket()
 	if bucket == "" {
 		return fmt.Errorf("S3 bucket not configured. Set ANALYTICS_BUCKET or use --bucket flag")
 	}
 
+	since := time.Now().AddDate(0, 0, -analyticsDays)
+	if analyticsSince != "" {
+		var err error
+		since, err = parseAnalyticsSince(analyticsSince)
+		if err != nil {
+			return err
+		}
+	}
+
 	ctx := context.Background()
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(analyticsRegion))
+	cfg, err := config.LoadDefaultConfig(ctx, frankaws.WithRetries(), config.WithRegion(getAnalyticsRegion()))
 	if err != nil {
 		return fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
 	client := s3.NewFromConfig(cfg)
 
-	// Calculate date range
-	endDate := time.Now()
-	startDate := endDate.AddDate(0, 0, -analyticsDays)
-
-	// List prompts from S3
-	prefix := "prompts/"
-
-	result, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
-		Bucket:  aws.String(bucket),
-		Prefix:  aws.String(prefix),
-		MaxKeys: aws.Int32(100),
+	summaries, err := analytics.ListPrompts(ctx, client, bucket, analytics.Query{
+		Profile: analyticsProfile,
+		Since:   since,
+		Search:  analyticsSearch,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to list S3 objects: %w", err)
+		return fmt.Errorf("failed to list prompts: %w", err)
 	}
 
-	if len(result.Contents) == 0 {
-		fmt.Println("No prompts found in analytics bucket.")
+	if len(summaries) == 0 && !analyticsOutput.Structured() {
+		fmt.Printf("No prompts found since %s.\n", since.Format("2006-01-02 15:04"))
 		fmt.Println("\nPrompts will appear here after using Frank with analytics enabled.")
 		return nil
 	}
 
-	// Parse and display prompts
-	type PromptSummary struct {
-		Time    time.Time
-		Profile string
-		Text    string
-		Turns   int
+	// JSON and YAML get every prompt with full timestamps; the table the
+	// latest 20
+	shown, timeLayout := summaries[:min(20, len(summaries))], "Jan 02 15:04"
+	if analyticsOutput.Structured() {
+		shown, timeLayout = summaries, time.RFC3339
 	}
 
-	var summaries []PromptSummary
-
-	for _, obj := range result.Contents {
-		if obj.LastModified != nil && obj.LastModified.After(startDate) {
-			// Extract profile from key
-			parts := strings.Split(*obj.Key, "/")
-			profile := "unknown"
-			if len(parts) >= 2 {
-				profile = parts[1]
-			}
-
-			// Fetch and parse
-			getResult, err := client.GetObject(ctx, &s3.GetObjectInput{
-				Bucket: aws.String(bucket),
-				Key:    obj.Key,
-			})
-			if err != nil {
-				continue
-			}
-			defer getResult.Body.Close()
-
-			var prompts []map[string]interface{}
-			decoder := json.NewDecoder(getResult.Body)
-			if err := decoder.Decode(&prompts); err != nil {
-				continue
-			}
-
-			for _, p := range prompts {
-				promptData, ok := p["prompt"].(map[string]interface{})
-				if !ok {
-					continue
-				}
-
-				text, _ := promptData["text"].(string)
-				if len(text) > 60 {
-					text = text[:57] + "..."
-				}
-
-				timestamp, _ := p["timestamp"].(string)
-				t, _ := time.Parse(time.RFC3339, timestamp)
-
-				outcomeData, _ := p["outcome"].(map[string]interface{})
-				turns, _ := outcomeData["next_turn_count"].(float64)
-
-				summaries = append(summaries, PromptSummary{
-					Time:    t,
-					Profile: profile,
-					Text:    text,
-					Turns:   int(turns),
-				})
-			}
-		}
-	}
-
-	if len(summaries) == 0 {
-		fmt.Printf("No prompts found in the last %d days.\n", analyticsDays)
-		return nil
+	table := render.NewTable(analyticsOutput,
+		render.Column{Header: "TIME"},
+		render.Column{Header: "PROFILE", Hide: 1},
+		render.Column{Header: "PROMPT", MaxWidth: 60},
+		render.Column{Header: "TURNS", Hide: 2},
+	)
+	for _, s := range shown {
+		table.Append(s.Time.Local().Format(timeLayout), s.Profile, s.Text, fmt.Sprintf("%d", s.Turns))
 	}
-
-	// Sort by time descending
-	sort.Slice(summaries, func(i, j int) bool {
-		return summaries[i].Time.After(summaries[j].Time)
-	})
-
-	// Display as table
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"TIME", "PROFILE", "PROMPT", "TURNS"})
-	table.SetBorder(false)
-	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-	table.SetAlignment(tablewriter.ALIGN_LEFT)
-	table.SetCenterSeparator("")
-	table.SetColumnSeparator("  ")
-	table.SetRowSeparator("")
-	table.SetColWidth(60)
-
-	for _, s := range summaries[:min(20, len(summaries))] {
-		table.Append([]string{
-			s.Time.Format("Jan 02 15:04"),
-			s.Profile,
-			s.Text,
-			fmt.Sprintf("%d", s.Turns),
-		})
+	if err := table.Render(os.Stdout); err != nil {
+		return err
 	}
 
-	table.Render()
-
-	if len(summaries) > 20 {
+	if len(summaries) > len(shown) {
 		fmt.Printf("\nShowing 20 of %d prompts. View all at: https://frank.digitaldevops.io/dashboard\n", len(summaries))
 	}
 
 	return nil
 }
 
+// parseAnalyticsSince accepts a duration (48h), a number of days (3d) or a
+// date (2026-10-01)
+func parseAnalyticsSince(value string) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return time.Now().AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q (use a duration like 48h, days like 3d, or a date like 2006-01-02)", value)
+}
+
 // ============================================================================
 // analytics sync - Sync local analytics to S3
 // ============================================================================
@@ -283,8 +280,18 @@ func runAnalyticsList(cmd *cobra.Command, args []string) error {
 var analyticsSyncCmd = &cobra.Command{
 	Use:   "sync",
 	Short: "Sync local analytics to S3",
-	Long:  `Upload locally captured analytics to S3 for dashboard viewing.`,
-	RunE:  runAnalyticsSync,
+	Long: `Upload locally captured analytics to S3 for dashboard viewing.
+
+Uploaded files are recorded with their checksums in a manifest in the
+analytics directory, so only new and changed files are uploaded again, and
+an interrupted sync resumes where it stopped.
+
+Examples:
+  frank analytics sync                   # Upload new and changed files
+  frank analytics sync --concurrency 8   # Upload more files at once
+  frank analytics sync --delete          # Also remove objects deleted locally`,
+	Annotations: mutates("s3:PutObject"),
+	RunE:        runAnalyticsSync,
 }
 
 func runAnalyticsSync(cmd *cobra.Command, args []string) error {
@@ -299,60 +306,132 @@ func runAnalyticsSync(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	ctx := context.Background()
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(analyticsRegion))
+	if analyticsWorkers < 1 {
+		return exitcode.Errorf(exitcode.Usage, "--concurrency must be at least 1")
+	}
+	if analyticsDelete {
+		if err := guardActions(cmd, "s3:DeleteObject"); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	cfg, err := config.LoadDefaultConfig(ctx, frankaws.WithRetries(), config.WithRegion(getAnalyticsRegion()))
 	if err != nil {
 		return fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
 	client := s3.NewFromConfig(cfg)
 
-	// Walk local directory and upload files
-	uploaded := 0
-	err = filepath.Walk(localDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if info.IsDir() || !strings.HasSuffix(path, ".json") {
-			return nil
-		}
+	result, err := analytics.Sync(ctx, client, bucket, localDir, analytics.SyncOptions{
+		Concurrency: analyticsWorkers,
+		Delete:      analyticsDelete,
+	})
+	if errors.Is(err, context.Canceled) {
+		fmt.Printf("Sync interrupted after %d files; run it again to resume.\n", result.Uploaded)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("sync failed: %w", err)
+	}
 
-		// Read file
-		data, err := os.ReadFile(path)
-		if err != nil {
-			return err
-		}
+	for _, err := range result.Failed {
+		PrintError("Failed to sync %v", err)
+	}
 
-		// Determine S3 key
-		relPath, _ := filepath.Rel(localDir, path)
-		key := "prompts/local/" + strings.ReplaceAll(relPath, "\\", "/")
+	if result.Uploaded == 0 {
+		fmt.Println("No files to sync.")
+	} else {
+		fmt.Printf("Synced %d files to s3://%s/%s\n", result.Uploaded, bucket, analytics.PromptsPrefix)
+	}
+	if result.Skipped > 0 {
+		PrintVerbose("%d files unchanged since the last sync", result.Skipped)
+	}
+	if result.Deleted > 0 {
+		fmt.Printf("Removed %d objects whose local file is gone\n", result.Deleted)
+	}
+	if result.Stale > 0 {
+		fmt.Printf("%d uploaded files no longer exist locally; run with --delete to remove them from S3\n", result.Stale)
+	}
 
-		// Upload
-		_, err = client.PutObject(ctx, &s3.PutObjectInput{
-			Bucket:      aws.String(bucket),
-			Key:         aws.String(key),
-			Body:        strings.NewReader(string(data)),
-			ContentType: aws.String("application/json"),
-		})
-		if err != nil {
-			fmt.Printf("Failed to upload %s: %v\n", path, err)
-			return nil
-		}
+	return nil
+}
 
-		uploaded++
-		return nil
-	})
+// ============================================================================
+// analytics migrate - Move prompts to the date-partitioned layout
+// ============================================================================
+
+var analyticsMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move prompts from the older per-profile layout",
+	Long: `Copy prompts uploaded under the older prompts/<profile>/YYYY/MM/DD/ and
+prompts/<profile>/ layouts to prompts/YYYY/MM/DD/<profile>/, where 'analytics
+list', the dashboard and the analytics API look for them.
+
+Each old object is deleted once its copy succeeds, unless --keep is given.
+Running it again only moves what is left in an older layout.
+
+Examples:
+  frank analytics migrate --dry-run   # Show what would move
+  frank analytics migrate             # Move everything to the dated layout
+  frank analytics migrate --keep      # Copy, leaving the old objects`,
+	Args:        cobra.NoArgs,
+	Annotations: mutates("s3:GetObject", "s3:PutObject", "s3:DeleteObject"),
+	RunE:        runAnalyticsMigrate,
+}
+
+func runAnalyticsMigrate(cmd *cobra.Command, args []string) error {
+	bucket := getBucket()
+	if bucket == "" {
+		return fmt.Errorf("S3 bucket not configured. Set ANALYTICS_BUCKET or use --bucket flag")
+	}
+	if analyticsMigrateWorkers < 1 {
+		return exitcode.Errorf(exitcode.Usage, "--concurrency must be at least 1")
+	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	cfg, err := config.LoadDefaultConfig(ctx, frankaws.WithRetries(), config.WithRegion(getAnalyticsRegion()))
 	if err != nil {
-		return fmt.Errorf("sync failed: %w", err)
+		return fmt.Errorf("failed to load AWS config: %w", err)
 	}
+	client := s3.NewFromConfig(cfg)
 
-	if uploaded == 0 {
-		fmt.Println("No files to sync.")
-	} else {
-		fmt.Printf("Synced %d files to s3://%s/prompts/local/\n", uploaded, bucket)
+	moves, err := analytics.PlanMigration(ctx, client, bucket)
+	if err != nil {
+		return err
+	}
+	if len(moves) == 0 {
+		fmt.Println("No prompts in an older layout.")
+		return nil
+	}
+
+	if analyticsMigrateDryRun {
+		for _, m := range moves {
+			fmt.Printf("  %s -> %s\n", m.From, m.To)
+		}
+		fmt.Printf("\n%d objects would move. Run without --dry-run to migrate.\n", len(moves))
+		return nil
 	}
 
+	fmt.Printf("Migrating %d objects in s3://%s...\n", len(moves), bucket)
+	result := analytics.Migrate(ctx, client, bucket, moves, analyticsMigrateWorkers, analyticsMigrateKeep)
+	for _, err := range result.Failed {
+		PrintError("%v", err)
+	}
+	if ctx.Err() != nil {
+		fmt.Printf("Migration interrupted after %d objects; run it again to resume.\n", result.Copied)
+		return nil
+	}
+
+	fmt.Printf("%s Copied %d objects to %sYYYY/MM/DD/<profile>/\n", color.GreenString("✓"), result.Copied, analytics.PromptsPrefix)
+	if result.Deleted > 0 {
+		fmt.Printf("Removed %d objects from the older layout\n", result.Deleted)
+	}
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d of %d objects failed to migrate", len(result.Failed), len(moves))
+	}
 	return nil
 }
 
@@ -374,24 +453,32 @@ func runAnalyticsReport(cmd *cobra.Command, args []string) error {
 	}
 
 	ctx := context.Background()
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(analyticsRegion))
+	cfg, err := config.LoadDefaultConfig(ctx, frankaws.WithRetries(), config.WithRegion(getAnalyticsRegion()))
 	if err != nil {
 		return fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
 	client := s3.NewFromConfig(cfg)
 
-	// Fetch aggregates
-	result, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
-		Bucket:  aws.String(bucket),
-		Prefix:  aws.String("aggregates/daily/"),
-		MaxKeys: aws.Int32(30),
+	// Fetch aggregates. Keys are dated, so the last 30 listed are the most
+	// recent days.
+	var objects []s3types.Object
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String("aggregates/daily/"),
 	})
-	if err != nil {
-		return fmt.Errorf("failed to list aggregates: %w", err)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list aggregates: %w", err)
+		}
+		objects = append(objects, page.Contents...)
+	}
+	if len(objects) > 30 {
+		objects = objects[len(objects)-30:]
 	}
 
-	if len(result.Contents) == 0 {
+	if len(objects) == 0 {
 		fmt.Println("No aggregated data available yet.")
 		fmt.Println("Aggregation runs daily at 2 AM UTC.")
 		return nil
@@ -400,7 +487,7 @@ func runAnalyticsReport(cmd *cobra.Command, args []string) error {
 	// Collect aggregate data
 	var aggregates []map[string]interface{}
 
-	for _, obj := range result.Contents {
+	for _, obj := range objects {
 		getResult, err := client.GetObject(ctx, &s3.GetObjectInput{
 			Bucket: aws.String(bucket),
 			Key:    obj.Key,
@@ -442,14 +529,82 @@ func getBucket() string {
 	if analyticsBucket != "" {
 		return analyticsBucket
 	}
+	if cfg.Analytics.Bucket != "" {
+		return cfg.Analytics.Bucket
+	}
 	return os.Getenv("ANALYTICS_BUCKET")
 }
 
+func getAnalyticsRegion() string {
+	if analyticsRegion != "" {
+		return analyticsRegion
+	}
+	if cfg.Analytics.Region != "" {
+		return cfg.Analytics.Region
+	}
+	return "us-east-1"
+}
+
 func getLocalAnalyticsDir() string {
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, ".frank", "analytics")
 }
 
+// recordAnalytics appends a session record to the local analytics log and
+// kicks off a background sync when one is due. Failures never affect the
+// command being recorded.
+func recordAnalytics(r analytics.Record) {
+	if !cfg.Analytics.Enabled {
+		return
+	}
+
+	collector := analytics.NewCollector(getLocalAnalyticsDir())
+	if err := collector.Record(r); err != nil {
+		PrintVerbose("Warning: failed to record analytics: %v", err)
+		return
+	}
+	if err := collector.Flush(); err != nil {
+		PrintVerbose("Warning: failed to record analytics: %v", err)
+		return
+	}
+
+	if cfg.Analytics.AutoSync {
+		startBackgroundSync(collector.Dir())
+	}
+}
+
+// startBackgroundSync runs 'frank analytics sync' as a detached process when
+// a bucket is configured, frank isn't read-only and the sync interval has
+// passed. The sync time is claimed up front so concurrent commands don't
+// start a second upload.
+func startBackgroundSync(dir string) {
+	bucket := getBucket()
+	if bucket == "" || cfg.AWS.ReadOnly {
+		return
+	}
+
+	state, err := analytics.LoadSyncState(dir)
+	if err != nil || !state.Due(cfg.Analytics.SyncInterval, time.Now()) {
+		return
+	}
+	state.LastSync = time.Now().UTC()
+	if err := state.Save(dir); err != nil {
+		return
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return
+	}
+	sync := exec.Command(exe, "analytics", "sync", "--bucket", bucket, "--region", getAnalyticsRegion())
+	if err := sync.Start(); err != nil {
+		PrintVerbose("Warning: failed to start analytics sync: %v", err)
+		return
+	}
+	PrintVerbose("Syncing analytics in the background (pid %d)", sync.Process.Pid)
+	sync.Process.Release()
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/barff/frank/internal/audit"
+	"github.com/barff/frank/internal/config"
+	"github.com/barff/frank/internal/render"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the AWS changes frank has made",
+	Long: `Show the audit log of AWS changes made by frank.
+
+Every RunTask, StopTask, UpdateService, target group, listener rule, SSM
+parameter and Secrets Manager write is appended to audit.jsonl in the frank
+config directory, with the caller identity and the command that made it.
+Set audit.cloudWatchLogGroup to also send entries to CloudWatch Logs.
+
+Examples:
+  frank audit list                         # Recent changes
+  frank audit list --since 24h --failed    # Failed calls in the last day
+  frank audit list --operation RunTask     # Only task launches
+  frank audit list --json                  # Raw entries`,
+}
+
+var (
+	auditListSince     string
+	auditListOperation string
+	auditListFailed    bool
+	auditListLimit     int
+	auditListJSON      bool
+)
+
+// commandPath is the running frank command, recorded in audit entries
+var commandPath string
+
+// auditLogger is created on first use from the loaded configuration
+var auditLogger *audit.Logger
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditListCmd)
+
+	auditListCmd.Flags().StringVar(&auditListSince, "since", "", "Only show entries since a duration, days or date (e.g. 24h, 7d, 2026-10-01)")
+	auditListCmd.Flags().StringVar(&auditListOperation, "operation", "", "Only show this operation (e.g. RunTask)")
+	auditListCmd.Flags().BoolVar(&auditListFailed, "failed", false, "Only show calls that returned an error")
+	auditListCmd.Flags().IntVarP(&auditListLimit, "limit", "n", 50, "Maximum number of entries to show (0 for all)")
+	auditListCmd.Flags().BoolVar(&auditListJSON, "json", false, "Print entries as JSON lines")
+}
+
+// ============================================================================
+// audit list - Show audit log entries
+// ============================================================================
+
+var auditListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List audited AWS changes",
+	RunE:  runAuditList,
+}
+
+func runAuditList(cmd *cobra.Command, args []string) error {
+	path := audit.Path(config.GetConfigDir())
+	entries, err := audit.Read(path)
+	if err != nil {
+		return err
+	}
+
+	var since time.Time
+	if auditListSince != "" {
+		if since, err = parseAnalyticsSince(auditListSince); err != nil {
+			return err
+		}
+	}
+
+	var matched []audit.Entry
+	for _, e := range entries {
+		if e.Timestamp.Before(since) {
+			continue
+		}
+		if auditListOperation != "" && !strings.EqualFold(e.Operation, auditListOperation) {
+			continue
+		}
+		if auditListFailed && !e.Failed() {
+			continue
+		}
+		matched = append(matched, e)
+	}
+
+	// Newest entries are at the end of the log
+	if auditListLimit > 0 && len(matched) > auditListLimit {
+		matched = matched[len(matched)-auditListLimit:]
+	}
+
+	if auditListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for _, e := range matched {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(matched) == 0 {
+		fmt.Println("No audit entries found")
+		PrintVerbose("Audit log: %s", path)
+		return nil
+	}
+
+	table := render.NewTable(render.Options{},
+		render.Column{Header: "TIME"},
+		render.Column{Header: "OPERATION"},
+		render.Column{Header: "RESOURCE"},
+		render.Column{Header: "CALLER"},
+		render.Column{Header: "COMMAND"},
+		render.Column{Header: "RESULT"},
+	)
+
+	for _, e := range matched {
+		result := color.GreenString("ok")
+		if e.Failed() {
+			result = color.RedString("failed")
+		}
+		table.Append(
+			e.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			e.Operation,
+			shortARN(e.Resource),
+			shortARN(e.Caller),
+			strings.TrimPrefix(e.Command, "frank "),
+			result,
+		)
+	}
+
+	return table.Render(os.Stdout)
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// getAuditLogger returns the logger for this invocation, or nil when
+// auditing is disabled
+func getAuditLogger() *audit.Logger {
+	if !cfg.Audit.Enabled {
+		return nil
+	}
+	if auditLogger == nil {
+		auditLogger = audit.New(config.GetConfigDir(), audit.Options{
+			LogGroup: cfg.Audit.CloudWatchLogGroup,
+			Command:  commandPath,
+		})
+	}
+	return auditLogger
+}
+
+// withAudit is an AWS config load option that records mutations made by
+// clients created from the config
+func withAudit() func(*awsconfig.LoadOptions) error {
+	logger := getAuditLogger()
+	if logger == nil {
+		return func(*awsconfig.LoadOptions) error { return nil }
+	}
+	return awsconfig.WithAPIOptions(logger.APIOptions())
+}
+
+// shortARN trims an ARN to its resource part for display
+func shortARN(arn string) string {
+	if !strings.HasPrefix(arn, "arn:") {
+		return arn
+	}
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 {
+		return arn
+	}
+	return parts[5]
+}
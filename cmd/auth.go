@@ -2,15 +2,23 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/barff/frank/internal/authstore"
 	"github.com/barff/frank/internal/aws"
+	"github.com/barff/frank/internal/claude"
+	"github.com/barff/frank/internal/git"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var authCmd = &cobra.Command{
@@ -41,9 +49,16 @@ automatically mount your GitHub CLI config.`,
 var authStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show authentication status",
-	RunE:  runAuthStatus,
+	Long: `Show which credentials frank can pass to containers.
+
+The Claude OAuth token in ~/.claude/.credentials.json expires. Status warns
+when it has expired or expires within --warn-within; run
+'frank auth claude --refresh' to renew it.`,
+	RunE: runAuthStatus,
 }
 
+var authStatusWarnWithin time.Duration
+
 var authClaudeCmd = &cobra.Command{
 	Use:   "claude",
 	Short: "Configure Claude authentication",
@@ -57,7 +72,12 @@ To get your token:
   2. Complete the browser authentication
   3. The token is stored at ~/.claude/.credentials.json
 
-Alternatively, set the CLAUDE_ACCESS_TOKEN environment variable.`,
+Alternatively, set the CLAUDE_ACCESS_TOKEN environment variable.
+
+The token in ~/.claude/.credentials.json expires after a few hours. Use
+--refresh to renew it with the file's refresh token, the way Claude does; the
+new tokens are written back to the file, and the token stored for frank is
+updated too when it was copied from there.`,
 	RunE: runAuthClaude,
 }
 
@@ -85,6 +105,47 @@ var (
 	authEnkaiRelayClear bool
 )
 
+var authGitLabCmd = &cobra.Command{
+	Use:   "gitlab",
+	Short: "Configure GitLab authentication",
+	Long: `Configure GitLab authentication for frank containers.
+
+Provide a GitLab Personal Access Token which will be stored locally and
+passed to containers as GITLAB_TOKEN whenever the repository is hosted on
+GitLab. A git credential helper is configured so HTTPS clones and pushes
+use the token automatically.
+
+To create a token:
+  1. Go to https://gitlab.com/-/user_settings/personal_access_tokens
+  2. Select scopes: api, read_repository, write_repository
+  3. Copy the token (format: glpat-...)`,
+	RunE: runAuthGitLab,
+}
+
+var authBitbucketCmd = &cobra.Command{
+	Use:   "bitbucket",
+	Short: "Configure Bitbucket authentication",
+	Long: `Configure Bitbucket authentication for frank containers.
+
+Provide a Bitbucket repository or workspace access token which will be stored
+locally and passed to containers as BITBUCKET_TOKEN whenever the repository is
+hosted on Bitbucket. A git credential helper is configured so HTTPS clones
+and pushes use the token automatically.
+
+To create a token:
+  1. Open Repository settings → Security → Access tokens
+  2. Grant Repositories: Read and Write
+  3. Copy the token`,
+	RunE: runAuthBitbucket,
+}
+
+var (
+	authGitLabToken    string
+	authGitLabClear    bool
+	authBitbucketToken string
+	authBitbucketClear bool
+)
+
 var authPushCmd = &cobra.Command{
 	Use:   "push",
 	Short: "Push local credentials to AWS Secrets Manager",
@@ -95,11 +156,37 @@ Only credentials that are configured locally will be pushed.
 
 Secrets updated:
   /frank/github-token       ← frank auth github
+  /frank/gitlab-token       ← frank auth gitlab
+  /frank/bitbucket-token    ← frank auth bitbucket
   /frank/claude-credentials ← ~/.claude/.credentials.json
-  /frank/enkai-relay-api-key       ← frank auth enkai-relay`,
-	RunE: runAuthPush,
+  /frank/enkai-relay-api-key       ← frank auth enkai-relay
+
+Secrets that don't exist yet are created, encrypted with --kms-key when
+given (existing secrets move to that key) and tagged with --tag. Use --only
+to push some of the credentials and --dry-run to see which secrets would
+change, with masked values, without pushing.
+
+Claude credentials that have expired, or expire within the hour, are
+refreshed before they're pushed so ECS tasks don't start with a stale token.
+
+Pushes use the Secrets Manager API with your AWS config and credentials; the
+AWS CLI isn't needed.
+
+Examples:
+  frank auth push --dry-run
+  frank auth push --only github,claude
+  frank auth push --kms-key alias/frank --tag team=platform`,
+	Annotations: mutates("secretsmanager:PutSecretValue", "secretsmanager:CreateSecret"),
+	RunE:        runAuthPush,
 }
 
+var (
+	authPushOnly   []string
+	authPushDryRun bool
+	authPushKMSKey string
+	authPushTags   []string
+)
+
 var authAWSCmd = &cobra.Command{
 	Use:   "aws [profile]",
 	Short: "Generate temporary AWS credentials",
@@ -124,6 +211,45 @@ Examples:
 	RunE: runAuthAWS,
 }
 
+var authLockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Encrypt stored tokens",
+	Long: `Encrypt the tokens stored in ~/.config/frank/auth.
+
+By default tokens are encrypted with a passphrase, which frank asks for the
+first time it needs a token (or reads from FRANK_AUTH_PASSPHRASE). With --key
+they are encrypted to an X25519 key in ~/.config/frank/auth.key (or
+FRANK_AUTH_KEY_FILE), created if it doesn't exist; frank reads tokens without
+asking while the key file is there.
+
+Tokens stored while the store is locked are encrypted the same way. Run
+'frank auth unlock' to decrypt them again.
+
+Examples:
+  frank auth lock                  # Encrypt with a passphrase
+  frank auth lock --key            # Encrypt to a key file
+  FRANK_AUTH_KEY_FILE=/media/usb/frank.key frank auth lock --key`,
+	Args: cobra.NoArgs,
+	RunE: runAuthLock,
+}
+
+var authUnlockCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Decrypt stored tokens",
+	Long: `Decrypt the tokens 'frank auth lock' encrypted and store them in plain
+text again, asking for the passphrase or reading the key file.`,
+	Args: cobra.NoArgs,
+	RunE: runAuthUnlock,
+}
+
+var authLockKey bool
+
+var authClaudeRefresh bool
+
+// claudeRefreshBefore is how close to expiry auth push refreshes the Claude
+// token; ECS tasks need it to last at least until they start
+const claudeRefreshBefore = time.Hour
+
 var (
 	authGitHubToken  string
 	authGitHubClear  bool
@@ -136,17 +262,38 @@ var (
 func init() {
 	rootCmd.AddCommand(authCmd)
 	authCmd.AddCommand(authGitHubCmd)
+	authCmd.AddCommand(authGitLabCmd)
+	authCmd.AddCommand(authBitbucketCmd)
 	authCmd.AddCommand(authClaudeCmd)
 	authCmd.AddCommand(authStatusCmd)
 	authCmd.AddCommand(authAWSCmd)
 	authCmd.AddCommand(authEnkaiRelayCmd)
 	authCmd.AddCommand(authPushCmd)
+	authCmd.AddCommand(authLockCmd)
+
+	authPushCmd.Flags().StringSliceVar(&authPushOnly, "only", nil, "Only push these credentials: github, claude, gitlab, bitbucket, enkai-relay")
+	authPushCmd.Flags().BoolVar(&authPushDryRun, "dry-run", false, "Show the secrets that would be updated without pushing")
+	authPushCmd.Flags().StringVar(&authPushKMSKey, "kms-key", "", "KMS key ID, ARN or alias to encrypt the secrets with (default: aws/secretsmanager)")
+	authPushCmd.Flags().StringArrayVar(&authPushTags, "tag", nil, "Tag the secrets with KEY=VAL (repeatable)")
+	authCmd.AddCommand(authUnlockCmd)
+
+	authLockCmd.Flags().BoolVar(&authLockKey, "key", false, "Encrypt to a key file instead of a passphrase")
 
 	authGitHubCmd.Flags().StringVarP(&authGitHubToken, "token", "t", "", "GitHub Personal Access Token")
 	authGitHubCmd.Flags().BoolVar(&authGitHubClear, "clear", false, "Clear stored GitHub token")
 
+	authGitLabCmd.Flags().StringVarP(&authGitLabToken, "token", "t", "", "GitLab Personal Access Token")
+	authGitLabCmd.Flags().BoolVar(&authGitLabClear, "clear", false, "Clear stored GitLab token")
+
+	authBitbucketCmd.Flags().StringVarP(&authBitbucketToken, "token", "t", "", "Bitbucket access token")
+	authBitbucketCmd.Flags().BoolVar(&authBitbucketClear, "clear", false, "Clear stored Bitbucket token")
+
 	authClaudeCmd.Flags().StringVarP(&authClaudeToken, "token", "t", "", "Claude access token")
 	authClaudeCmd.Flags().BoolVar(&authClaudeClear, "clear", false, "Clear stored Claude token")
+	authClaudeCmd.Flags().BoolVar(&authClaudeRefresh, "refresh", false, "Refresh the OAuth token in ~/.claude/.credentials.json")
+	authClaudeCmd.MarkFlagsMutuallyExclusive("token", "clear", "refresh")
+
+	authStatusCmd.Flags().DurationVar(&authStatusWarnWithin, "warn-within", 2*time.Hour, "Warn when the Claude token expires within this long")
 
 	authAWSCmd.Flags().StringVar(&authAWSFormat, "format", "env", "Output format: env, export, json, powershell")
 	authAWSCmd.Flags().BoolVar(&authAWSLogin, "login", false, "Perform SSO login if credentials are expired")
@@ -217,7 +364,7 @@ func runAuthGitHub(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create auth directory: %w", err)
 	}
 
-	if err := os.WriteFile(tokenFile, []byte(token), 0600); err != nil {
+	if err := writeAuthToken(tokenFile, token); err != nil {
 		return fmt.Errorf("failed to store token: %w", err)
 	}
 
@@ -237,6 +384,15 @@ func runAuthClaude(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if authClaudeRefresh {
+		creds, err := refreshClaudeCredentials(cmd.Context())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Claude token refreshed (%s).\n", color.GreenString("✓"), claudeExpiryDescription(creds))
+		return nil
+	}
+
 	token := authClaudeToken
 
 	// If no token provided, try to find it or prompt
@@ -282,7 +438,7 @@ func runAuthClaude(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create auth directory: %w", err)
 	}
 
-	if err := os.WriteFile(tokenFile, []byte(token), 0600); err != nil {
+	if err := writeAuthToken(tokenFile, token); err != nil {
 		return fmt.Errorf("failed to store token: %w", err)
 	}
 
@@ -295,6 +451,16 @@ func runAuthStatus(cmd *cobra.Command, args []string) error {
 	fmt.Println("Authentication Status:")
 	fmt.Println()
 
+	// Check the token store
+	fmt.Print("Store:  ")
+	if lock, err := authstore.ReadLock(getAuthDir()); err != nil {
+		fmt.Printf("%s (%v)\n", color.RedString("unreadable"), err)
+	} else if lock != nil {
+		fmt.Printf("%s (%s)\n", color.GreenString("encrypted"), lockDescription(lock))
+	} else {
+		fmt.Printf("%s (run 'frank auth lock' to encrypt)\n", color.YellowString("plain text"))
+	}
+
 	// Check Claude
 	fmt.Print("Claude: ")
 	if token := getStoredClaudeToken(); token != "" {
@@ -307,6 +473,17 @@ func runAuthStatus(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Printf("%s\n", color.YellowString("not configured"))
 	}
+	if creds, err := claude.LoadCredentials(claude.CredentialsPath()); err == nil && !creds.ExpiresAt.IsZero() {
+		fmt.Print("        ")
+		switch {
+		case creds.Expired():
+			fmt.Printf("%s (run 'frank auth claude --refresh')\n", color.RedString(claudeExpiryDescription(creds)))
+		case creds.ExpiresWithin(authStatusWarnWithin):
+			fmt.Printf("%s (run 'frank auth claude --refresh')\n", color.YellowString(claudeExpiryDescription(creds)))
+		default:
+			fmt.Println(claudeExpiryDescription(creds))
+		}
+	}
 
 	// Check GitHub
 	fmt.Print("GitHub: ")
@@ -323,6 +500,26 @@ func runAuthStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s\n", color.YellowString("not configured"))
 	}
 
+	// Check GitLab
+	fmt.Print("GitLab: ")
+	if token := getStoredToken("gitlab"); token != "" {
+		fmt.Printf("%s (stored: %s)\n", color.GreenString("configured"), maskToken(token))
+	} else if token := os.Getenv("GITLAB_TOKEN"); token != "" {
+		fmt.Printf("%s (from GITLAB_TOKEN env)\n", color.GreenString("configured"))
+	} else {
+		fmt.Printf("%s\n", color.YellowString("not configured"))
+	}
+
+	// Check Bitbucket
+	fmt.Print("Bitbucket: ")
+	if token := getStoredToken("bitbucket"); token != "" {
+		fmt.Printf("%s (stored: %s)\n", color.GreenString("configured"), maskToken(token))
+	} else if token := os.Getenv("BITBUCKET_TOKEN"); token != "" {
+		fmt.Printf("%s (from BITBUCKET_TOKEN env)\n", color.GreenString("configured"))
+	} else {
+		fmt.Printf("%s\n", color.YellowString("not configured"))
+	}
+
 	// Check EnkaiRelay
 	fmt.Print("EnkaiRelay:   ")
 	if token := getStoredEnkaiRelayToken(); token != "" {
@@ -354,27 +551,24 @@ func runAuthStatus(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// getAuthDir returns the directory holding stored auth tokens
+func getAuthDir() string {
+	return filepath.Join(getHomeDir(), ".config", "frank", "auth")
+}
+
 // getAuthTokenFile returns the path to store auth tokens
 func getAuthTokenFile(service string) string {
-	return filepath.Join(getHomeDir(), ".config", "frank", "auth", service+".token")
+	return filepath.Join(getAuthDir(), service+".token")
 }
 
 // getStoredGitHubToken reads the stored GitHub token
 func getStoredGitHubToken() string {
-	data, err := os.ReadFile(getAuthTokenFile("github"))
-	if err != nil {
-		return ""
-	}
-	return strings.TrimSpace(string(data))
+	return readAuthToken("github")
 }
 
 // getStoredClaudeToken reads the stored Claude token
 func getStoredClaudeToken() string {
-	data, err := os.ReadFile(getAuthTokenFile("claude"))
-	if err != nil {
-		return ""
-	}
-	return strings.TrimSpace(string(data))
+	return readAuthToken("claude")
 }
 
 // getClaudeCredentialsToken reads Claude's credentials file
@@ -410,6 +604,44 @@ func getClaudeCredentialsToken() string {
 	return ""
 }
 
+// refreshClaudeCredentials renews the token in Claude's credentials file and
+// writes it back. The token stored for frank follows when it was a copy of
+// the old one.
+func refreshClaudeCredentials(ctx context.Context) (*claude.Credentials, error) {
+	path := claude.CredentialsPath()
+	creds, err := claude.LoadCredentials(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Claude credentials: %w", err)
+	}
+	oldToken := creds.AccessToken
+
+	PrintVerbose("Refreshing Claude token from %s", path)
+	if err := creds.Refresh(ctx, &http.Client{Timeout: 30 * time.Second}); err != nil {
+		return nil, err
+	}
+	if err := creds.Save(path); err != nil {
+		return nil, err
+	}
+
+	if stored := getStoredClaudeToken(); stored != "" && stored == oldToken {
+		if err := writeAuthToken(getAuthTokenFile("claude"), creds.AccessToken); err != nil {
+			return nil, fmt.Errorf("failed to update stored token: %w", err)
+		}
+		PrintVerbose("Updated the stored Claude token")
+	}
+	return creds, nil
+}
+
+// claudeExpiryDescription says when the Claude token expires or expired
+func claudeExpiryDescription(creds *claude.Credentials) string {
+	at := creds.ExpiresAt.Local().Format("2006-01-02 15:04")
+	left := time.Until(creds.ExpiresAt).Round(time.Minute)
+	if left <= 0 {
+		return fmt.Sprintf("token expired %s ago, at %s", strings.TrimSuffix((-left).String(), "0s"), at)
+	}
+	return fmt.Sprintf("token expires in %s, at %s", strings.TrimSuffix(left.String(), "0s"), at)
+}
+
 // maskToken returns a masked version of a token for display
 func maskToken(token string) string {
 	if len(token) < 8 {
@@ -542,7 +774,7 @@ func runAuthEnkaiRelay(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create auth directory: %w", err)
 	}
 
-	if err := os.WriteFile(tokenFile, []byte(token), 0600); err != nil {
+	if err := writeAuthToken(tokenFile, token); err != nil {
 		return fmt.Errorf("failed to store API key: %w", err)
 	}
 
@@ -556,11 +788,7 @@ func runAuthEnkaiRelay(cmd *cobra.Command, args []string) error {
 
 // getStoredEnkaiRelayToken reads the stored EnkaiRelay API key
 func getStoredEnkaiRelayToken() string {
-	data, err := os.ReadFile(getAuthTokenFile("enkai-relay"))
-	if err != nil {
-		return ""
-	}
-	return strings.TrimSpace(string(data))
+	return readAuthToken("enkai-relay")
 }
 
 // GetEnkaiRelayToken returns the EnkaiRelay API key from stored or environment
@@ -574,13 +802,199 @@ func GetEnkaiRelayToken() string {
 	return ""
 }
 
+func runAuthGitLab(cmd *cobra.Command, args []string) error {
+	return storeProviderToken("gitlab", "GitLab", "GITLAB_TOKEN", authGitLabToken, authGitLabClear, []string{"glpat-"})
+}
+
+func runAuthBitbucket(cmd *cobra.Command, args []string) error {
+	return storeProviderToken("bitbucket", "Bitbucket", "BITBUCKET_TOKEN", authBitbucketToken, authBitbucketClear, nil)
+}
+
+// storeProviderToken stores (or clears) a git hosting provider token, prompting
+// for it or offering the environment variable when no token flag was given
+func storeProviderToken(service, label, envVar, token string, clear bool, prefixes []string) error {
+	tokenFile := getAuthTokenFile(service)
+
+	if clear {
+		if err := os.Remove(tokenFile); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to clear token: %w", err)
+		}
+		fmt.Printf("%s token cleared.\n", label)
+		return nil
+	}
+
+	if token == "" {
+		reader := bufio.NewReader(os.Stdin)
+		if envToken := os.Getenv(envVar); envToken != "" {
+			fmt.Printf("%s environment variable is already set.\n", envVar)
+			fmt.Print("Store it for future sessions? [y/N]: ")
+			response, _ := reader.ReadString('\n')
+			if strings.TrimSpace(strings.ToLower(response)) != "y" {
+				return nil
+			}
+			token = envToken
+		} else {
+			fmt.Printf("Enter your %s access token:\n", label)
+			fmt.Print("> ")
+			token, _ = reader.ReadString('\n')
+			token = strings.TrimSpace(token)
+		}
+	}
+
+	if token == "" {
+		return fmt.Errorf("no token provided")
+	}
+
+	if len(prefixes) > 0 {
+		matched := false
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(token, prefix) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fmt.Println(color.YellowString("Warning: Token doesn't match expected %s token format.", label))
+			fmt.Printf("Expected prefixes: %s\n", strings.Join(prefixes, ", "))
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(tokenFile), 0700); err != nil {
+		return fmt.Errorf("failed to create auth directory: %w", err)
+	}
+
+	if err := writeAuthToken(tokenFile, token); err != nil {
+		return fmt.Errorf("failed to store token: %w", err)
+	}
+
+	fmt.Printf("%s %s token stored successfully.\n", color.GreenString("✓"), label)
+	fmt.Printf("This token will be passed to frank containers for %s repositories.\n", label)
+	return nil
+}
+
+// getStoredToken reads a stored auth token for a service
+func getStoredToken(service string) string {
+	return readAuthToken(service)
+}
+
+// GetGitLabToken returns the GitLab token from stored or environment
+func GetGitLabToken() string {
+	if token := getStoredToken("gitlab"); token != "" {
+		return token
+	}
+	return os.Getenv("GITLAB_TOKEN")
+}
+
+// GetBitbucketToken returns the Bitbucket token from stored or environment
+func GetBitbucketToken() string {
+	if token := getStoredToken("bitbucket"); token != "" {
+		return token
+	}
+	return os.Getenv("BITBUCKET_TOKEN")
+}
+
+// gitProviderEnv returns the token and credential helper environment for the
+// repository's hosting provider. GitHub is handled separately via GH_TOKEN
+// (and the container entrypoint), so only GitLab and Bitbucket add entries.
+func gitProviderEnv(repoURL string) []string {
+	if repoURL == "" {
+		return nil
+	}
+
+	var token string
+	provider := git.DetectProvider(repoURL)
+	switch provider {
+	case git.ProviderGitLab:
+		token = GetGitLabToken()
+	case git.ProviderBitbucket:
+		token = GetBitbucketToken()
+	default:
+		return nil
+	}
+
+	env := []string{fmt.Sprintf("GIT_PROVIDER=%s", provider)}
+	if token == "" {
+		return env
+	}
+	env = append(env, fmt.Sprintf("%s=%s", provider.TokenEnvVar(), token))
+	env = append(env, git.CredentialHelperEnv(repoURL)...)
+	return env
+}
+
+// gitProviderTaskEnv is gitProviderEnv for ECS tasks. Task overrides are
+// visible to anyone who can describe the task, so the token is returned as a
+// FRANK_SECRETS NAME=secret-id pair for the entrypoint to read from the
+// secret 'frank auth push' stores it in; env holds only non-secret values.
+func gitProviderTaskEnv(repoURL string) (env []string, secret string) {
+	if repoURL == "" {
+		return nil, ""
+	}
+
+	provider := git.DetectProvider(repoURL)
+	if provider.TokenSecret() == "" {
+		return nil, ""
+	}
+	env = append([]string{fmt.Sprintf("GIT_PROVIDER=%s", provider)}, git.CredentialHelperEnv(repoURL)...)
+	return env, provider.TokenEnvVar() + "=" + provider.TokenSecret()
+}
+
+// addSecretSpec appends a NAME=secret-id pair to a FRANK_SECRETS value,
+// unless the value already loads NAME
+func addSecretSpec(secrets, spec string) string {
+	if secrets == "" {
+		return spec
+	}
+	name := strings.SplitN(spec, "=", 2)[0]
+	for _, existing := range strings.Split(secrets, ",") {
+		if strings.SplitN(existing, "=", 2)[0] == name {
+			return secrets
+		}
+	}
+	return secrets + "," + spec
+}
+
+// parseTagFlags parses repeated KEY=VAL --tag flags
+func parseTagFlags(flags []string) (map[string]string, error) {
+	tags := make(map[string]string)
+	for _, kv := range flags {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid --tag %q: expected KEY=VAL", kv)
+		}
+		tags[parts[0]] = parts[1]
+	}
+	return tags, nil
+}
+
+// authPushNames are the credentials 'auth push --only' accepts
+var authPushNames = []string{"github", "claude", "gitlab", "bitbucket", "enkai-relay"}
+
 func runAuthPush(cmd *cobra.Command, args []string) error {
-	fmt.Printf("%s Pushing credentials to AWS Secrets Manager...\n\n", color.CyanString("~"))
+	only := make(map[string]bool)
+	for _, name := range authPushOnly {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(authPushNames, name) {
+			return fmt.Errorf("unknown credential %q for --only (use: %s)", name, strings.Join(authPushNames, ", "))
+		}
+		only[name] = true
+	}
+	tags, err := parseTagFlags(authPushTags)
+	if err != nil {
+		return err
+	}
+
+	if authPushDryRun {
+		fmt.Printf("%s Credentials that would be pushed to AWS Secrets Manager:\n\n", color.CyanString("~"))
+	} else {
+		fmt.Printf("%s Pushing credentials to AWS Secrets Manager...\n\n", color.CyanString("~"))
+	}
 
 	type secretPush struct {
+		key      string
 		name     string
 		secretID string
 		value    string
+		masked   string
 		source   string
 	}
 
@@ -589,29 +1003,61 @@ func runAuthPush(cmd *cobra.Command, args []string) error {
 	// GitHub token
 	if token := GetGitHubToken(); token != "" {
 		pushes = append(pushes, secretPush{
+			key:      "github",
 			name:     "GitHub",
 			secretID: "/frank/github-token",
 			value:    token,
+			masked:   maskToken(token),
 			source:   "frank auth",
 		})
 	}
 
-	// Claude credentials — push the full credentials JSON file, not just the token
-	claudeCredFile := filepath.Join(getHomeDir(), ".claude", ".credentials.json")
+	// Claude credentials — push the full credentials JSON file, not just the token.
+	// A token about to expire is refreshed first so tasks don't get a stale one.
+	claudeCredFile := claude.CredentialsPath()
+	if len(only) == 0 || only["claude"] {
+		refreshClaudeBeforePush()
+	}
 	if data, err := os.ReadFile(claudeCredFile); err == nil && len(data) > 0 {
 		pushes = append(pushes, secretPush{
+			key:      "claude",
 			name:     "Claude",
 			secretID: "/frank/claude-credentials",
 			value:    string(data),
+			masked:   "credentials JSON, token " + maskToken(getClaudeCredentialsToken()),
 			source:   "~/.claude/.credentials.json",
 		})
 	} else if token := GetClaudeToken(); token != "" {
 		// Fallback: wrap the token in a JSON envelope
 		envelope := fmt.Sprintf(`{"claudeAiOauth":{"accessToken":"%s"}}`, token)
 		pushes = append(pushes, secretPush{
+			key:      "claude",
 			name:     "Claude",
 			secretID: "/frank/claude-credentials",
 			value:    envelope,
+			masked:   "credentials JSON, token " + maskToken(token),
+			source:   "frank auth",
+		})
+	}
+
+	// GitLab and Bitbucket tokens
+	if token := GetGitLabToken(); token != "" {
+		pushes = append(pushes, secretPush{
+			key:      "gitlab",
+			name:     "GitLab",
+			secretID: git.ProviderGitLab.TokenSecret(),
+			value:    token,
+			masked:   maskToken(token),
+			source:   "frank auth",
+		})
+	}
+	if token := GetBitbucketToken(); token != "" {
+		pushes = append(pushes, secretPush{
+			key:      "bitbucket",
+			name:     "Bitbucket",
+			secretID: git.ProviderBitbucket.TokenSecret(),
+			value:    token,
+			masked:   maskToken(token),
 			source:   "frank auth",
 		})
 	}
@@ -619,30 +1065,74 @@ func runAuthPush(cmd *cobra.Command, args []string) error {
 	// EnkaiRelay API key
 	if token := GetEnkaiRelayToken(); token != "" {
 		pushes = append(pushes, secretPush{
+			key:      "enkai-relay",
 			name:     "EnkaiRelay",
 			secretID: "/frank/enkai-relay-api-key",
 			value:    token,
+			masked:   maskToken(token),
 			source:   "frank auth",
 		})
 	}
 
+	if len(only) > 0 {
+		var selected []secretPush
+		for _, p := range pushes {
+			if only[p.key] {
+				selected = append(selected, p)
+				delete(only, p.key)
+			}
+		}
+		for name := range only {
+			fmt.Printf("  %-10s %s\n", name, color.YellowString("not configured locally, skipped"))
+		}
+		pushes = selected
+	}
+
 	if len(pushes) == 0 {
 		fmt.Println("No credentials configured. Use 'frank auth <service>' to set up credentials.")
 		return nil
 	}
 
-	// Push each credential
-	ssoManager := aws.NewSSOManager()
+	ctx := context.Background()
+	secrets, err := aws.NewSecretsClient(ctx, withAudit())
+	if err != nil {
+		return err
+	}
+	secrets.KMSKeyID = authPushKMSKey
+	secrets.Tags = tags
+
+	if authPushDryRun {
+		for _, p := range pushes {
+			action := "update"
+			if exists, err := secrets.Exists(ctx, p.secretID); err != nil {
+				action = "update (existence unknown)"
+				PrintVerbose("Could not check %s: %v", p.secretID, err)
+			} else if !exists {
+				action = "create"
+			}
+			fmt.Printf("  %-10s → %s  %s  %s (from %s)\n", p.name, p.secretID, action, p.masked, p.source)
+		}
+		fmt.Println()
+		fmt.Println("Run without --dry-run to push.")
+		return nil
+	}
+
+	// Push each credential, creating secrets that don't exist yet
 	succeeded := 0
 	failed := 0
 
 	for _, p := range pushes {
 		fmt.Printf("  %-10s → %s ", p.name, p.secretID)
-		err := ssoManager.PutSecretValue(p.secretID, p.value)
-		if err != nil {
+		created, err := secrets.Put(ctx, p.secretID, p.value)
+
+		switch {
+		case err != nil:
 			fmt.Printf("%s (%v)\n", color.RedString("FAILED"), err)
 			failed++
-		} else {
+		case created:
+			fmt.Printf("%s\n", color.GreenString("CREATED"))
+			succeeded++
+		default:
 			fmt.Printf("%s\n", color.GreenString("OK"))
 			succeeded++
 		}
@@ -658,6 +1148,28 @@ func runAuthPush(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// refreshClaudeBeforePush refreshes the Claude token when it expires within
+// claudeRefreshBefore. A failed refresh only warns: the push goes ahead with
+// the credentials as they are.
+func refreshClaudeBeforePush() {
+	creds, err := claude.LoadCredentials(claude.CredentialsPath())
+	if err != nil || !creds.ExpiresWithin(claudeRefreshBefore) {
+		return
+	}
+
+	if authPushDryRun {
+		fmt.Printf("  %-10s %s (%s)\n", "Claude", color.YellowString("would refresh"), claudeExpiryDescription(creds))
+		return
+	}
+
+	refreshed, err := refreshClaudeCredentials(context.Background())
+	if err != nil {
+		fmt.Printf("  %-10s %s (%v)\n", "Claude", color.YellowString("refresh failed, pushing the current token"), err)
+		return
+	}
+	fmt.Printf("  %-10s %s (%s)\n", "Claude", color.GreenString("refreshed"), claudeExpiryDescription(refreshed))
+}
+
 func runAuthAWS(cmd *cobra.Command, args []string) error {
 	ssoManager := aws.NewSSOManager()
 
@@ -776,3 +1288,298 @@ func runAuthAWS(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runAuthLock(cmd *cobra.Command, args []string) error {
+	dir := getAuthDir()
+	lock, err := authstore.ReadLock(dir)
+	if err != nil {
+		return err
+	}
+	if lock != nil {
+		return fmt.Errorf("auth store is already encrypted with a %s (run 'frank auth unlock' first)", lockDescription(lock))
+	}
+
+	var cipher authstore.Cipher
+	if authLockKey {
+		keyFile := getAuthKeyFile()
+		identity, err := authstore.LoadIdentity(keyFile)
+		if os.IsNotExist(err) {
+			if identity, err = authstore.GenerateIdentity(); err != nil {
+				return err
+			}
+			if err := authstore.SaveIdentity(keyFile, identity); err != nil {
+				return err
+			}
+			fmt.Printf("%s Created key %s\n", color.GreenString("✓"), keyFile)
+			fmt.Println(color.YellowString("Back it up: stored tokens can't be decrypted without it."))
+		} else if err != nil {
+			return fmt.Errorf("failed to load key: %w", err)
+		}
+		lock = authstore.NewKeyLock(identity.PublicKey())
+		if cipher, err = lock.Key(identity); err != nil {
+			return err
+		}
+	} else {
+		passphrase, err := readNewAuthPassphrase()
+		if err != nil {
+			return err
+		}
+		if lock, cipher, err = authstore.NewPassphraseLock(passphrase); err != nil {
+			return err
+		}
+	}
+
+	files, err := authTokenFiles()
+	if err != nil {
+		return err
+	}
+
+	// The lock goes first: sealed files can't be read without it
+	if err := authstore.WriteLock(dir, lock); err != nil {
+		return err
+	}
+	sealed := 0
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if authstore.IsSealed(data) {
+			continue
+		}
+		out, err := cipher.Seal(data)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", path, err)
+		}
+		if err := authstore.WriteFile(path, out); err != nil {
+			return err
+		}
+		sealed++
+	}
+
+	fmt.Printf("%s Encrypted %d stored token(s) with a %s\n", color.GreenString("✓"), sealed, lockDescription(lock))
+	return nil
+}
+
+func runAuthUnlock(cmd *cobra.Command, args []string) error {
+	dir := getAuthDir()
+	lock, err := authstore.ReadLock(dir)
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		fmt.Println("Stored tokens aren't encrypted.")
+		return nil
+	}
+
+	cipher, err := openAuthStore(lock)
+	if err != nil {
+		return err
+	}
+	files, err := authTokenFiles()
+	if err != nil {
+		return err
+	}
+
+	opened := 0
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if !authstore.IsSealed(data) {
+			continue
+		}
+		plaintext, err := cipher.Open(data)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", path, err)
+		}
+		if err := authstore.WriteFile(path, plaintext); err != nil {
+			return err
+		}
+		opened++
+	}
+
+	// The lock goes last, so an interrupted unlock can be run again
+	if err := authstore.RemoveLock(dir); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Decrypted %d stored token(s)\n", color.GreenString("✓"), opened)
+	return nil
+}
+
+// getAuthKeyFile returns the private key of a key-locked auth store. It's
+// kept outside the auth directory and FRANK_AUTH_KEY_FILE can move it
+// elsewhere, e.g. to removable media.
+func getAuthKeyFile() string {
+	if path := os.Getenv("FRANK_AUTH_KEY_FILE"); path != "" {
+		return path
+	}
+	return filepath.Join(getHomeDir(), ".config", "frank", "auth.key")
+}
+
+// authTokenFiles returns the stored token files
+func authTokenFiles() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(getAuthDir(), "*.token"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stored tokens: %w", err)
+	}
+	return files, nil
+}
+
+// readAuthToken reads a stored token, decrypting it when the store is
+// locked. A token that can't be decrypted reads as unset.
+func readAuthToken(service string) string {
+	data, err := os.ReadFile(getAuthTokenFile(service))
+	if err != nil {
+		return ""
+	}
+	if authstore.IsSealed(data) {
+		if data, err = openAuthToken(data); err != nil {
+			warnAuthLocked(err)
+			return ""
+		}
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// writeAuthToken stores a token at path, encrypted when the store is locked
+func writeAuthToken(path, token string) error {
+	lock, err := authstore.ReadLock(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		return os.WriteFile(path, []byte(token), 0600)
+	}
+
+	var cipher authstore.Cipher
+	if lock.Mode == authstore.ModeKey {
+		// Sealing only needs the public key
+		cipher, err = lock.Key(nil)
+	} else {
+		cipher, err = openAuthStore(lock)
+	}
+	if err != nil {
+		return err
+	}
+	sealed, err := cipher.Seal([]byte(token))
+	if err != nil {
+		return err
+	}
+	return authstore.WriteFile(path, sealed)
+}
+
+// openAuthToken decrypts a sealed token with the store's lock
+func openAuthToken(data []byte) ([]byte, error) {
+	lock, err := authstore.ReadLock(getAuthDir())
+	if err != nil {
+		return nil, err
+	}
+	if lock == nil {
+		return nil, fmt.Errorf("encrypted token found but the auth store has no lock file")
+	}
+	cipher, err := openAuthStore(lock)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.Open(data)
+}
+
+// The store's cipher is unlocked at most once per run, so the passphrase is
+// asked for once
+var (
+	authCipher      authstore.Cipher
+	authCipherErr   error
+	authCipherReady bool
+	authLockWarned  bool
+)
+
+// openAuthStore returns the cipher that opens the locked store, asking for
+// the passphrase or loading the key file
+func openAuthStore(lock *authstore.Lock) (authstore.Cipher, error) {
+	if authCipherReady {
+		return authCipher, authCipherErr
+	}
+	authCipherReady = true
+
+	switch lock.Mode {
+	case authstore.ModePassphrase:
+		var passphrase string
+		passphrase, authCipherErr = readAuthPassphrase("Auth store passphrase: ")
+		if authCipherErr == nil {
+			authCipher, authCipherErr = lock.Passphrase(passphrase)
+		}
+	case authstore.ModeKey:
+		identity, err := authstore.LoadIdentity(getAuthKeyFile())
+		if err != nil {
+			authCipherErr = fmt.Errorf("failed to load key (set FRANK_AUTH_KEY_FILE if it moved): %w", err)
+			break
+		}
+		authCipher, authCipherErr = lock.Key(identity)
+	default:
+		authCipherErr = fmt.Errorf("unknown auth store lock %q", lock.Mode)
+	}
+	return authCipher, authCipherErr
+}
+
+// warnAuthLocked reports, once, that stored tokens can't be decrypted
+func warnAuthLocked(err error) {
+	if authLockWarned {
+		return
+	}
+	authLockWarned = true
+	fmt.Fprintln(os.Stderr, color.YellowString("Warning: stored tokens are encrypted and can't be read: %v", err))
+}
+
+// readAuthPassphrase returns FRANK_AUTH_PASSPHRASE, or asks for the
+// passphrase on the terminal
+func readAuthPassphrase(prompt string) (string, error) {
+	if passphrase := os.Getenv("FRANK_AUTH_PASSPHRASE"); passphrase != "" {
+		return passphrase, nil
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("the passphrase is needed; set FRANK_AUTH_PASSPHRASE or run from a terminal")
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	data, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return string(data), nil
+}
+
+// readNewAuthPassphrase asks for a new passphrase twice, unless it's given in
+// FRANK_AUTH_PASSPHRASE
+func readNewAuthPassphrase() (string, error) {
+	if passphrase := os.Getenv("FRANK_AUTH_PASSPHRASE"); passphrase != "" {
+		return passphrase, nil
+	}
+	passphrase, err := readAuthPassphrase("New passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase must not be empty")
+	}
+	confirm, err := readAuthPassphrase("Confirm passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm != passphrase {
+		return "", fmt.Errorf("passphrases don't match")
+	}
+	return passphrase, nil
+}
+
+// lockDescription names how the store is locked
+func lockDescription(lock *authstore.Lock) string {
+	if lock.Mode == authstore.ModeKey {
+		return "key"
+	}
+	return "passphrase"
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/barff/frank/internal/container"
+	frankecs "github.com/barff/frank/internal/ecs"
+	"github.com/spf13/cobra"
+)
+
+// completionTimeout bounds the runtime or AWS lookup behind a shell
+// completion, so a slow daemon or expired SSO session never hangs the shell
+const completionTimeout = 2 * time.Second
+
+func init() {
+	stopCmd.ValidArgsFunction = completeFrankContainers(false, -1)
+	restartCmd.ValidArgsFunction = completeFrankContainers(true, 1)
+	logsCmd.ValidArgsFunction = completeFrankContainers(true, 1)
+	execCmd.ValidArgsFunction = completeFrankContainers(false, 1)
+	ecsStopCmd.ValidArgsFunction = completeECSTasks
+}
+
+// completeFrankContainers completes local frank container names, with their
+// status as the description. all includes stopped containers; maxArgs limits
+// how many arguments are completed (-1 for no limit).
+func completeFrankContainers(all bool, maxArgs int) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if maxArgs >= 0 && len(args) >= maxArgs {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		containers, ok := withCompletionTimeout(func() ([]container.Container, error) {
+			runtime, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
+			if err != nil {
+				return nil, err
+			}
+			return runtime.ListContainers(container.ContainerFilter{
+				All:        all,
+				NamePrefix: "frank-",
+			})
+		})
+		if !ok {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var completions []string
+		for _, c := range containers {
+			if !strings.HasPrefix(c.Name, "frank-") || container.IsSidecar(c) || slices.Contains(args, c.Name) {
+				continue
+			}
+			completions = append(completions, c.Name+"\t"+c.Status)
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeECSTasks completes running profile names and task IDs
+func completeECSTasks(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	tasks, err := runningProfileTasks(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for name, task := range tasks {
+		taskID := frankecs.TaskID(aws.ToString(task.TaskArn))
+		completions = append(completions,
+			name+"\ttask "+taskID,
+			taskID+"\tprofile "+name)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// withCompletionTimeout runs a lookup that can't be cancelled, giving up
+// after completionTimeout. ok is false on error or timeout.
+func withCompletionTimeout[T any](lookup func() (T, error)) (result T, ok bool) {
+	type outcome struct {
+		value T
+		err   error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		value, err := lookup()
+		done <- outcome{value, err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.value, o.err == nil
+	case <-time.After(completionTimeout):
+		return result, false
+	}
+}
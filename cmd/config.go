@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/barff/frank/internal/config"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/barff/frank/internal/render"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and edit frank configuration",
+	Long: `Inspect and edit the frank configuration.
+
+Values are merged from defaults, the config file, FRANK_* environment
+variables and command-line flags (in increasing order of precedence).
+
+Examples:
+  frank config show                        # Show effective configuration
+  frank config show --effective            # Show each key with its source
+  frank config get container.basePort      # Get a single value
+  frank config set container.basePort 9000 # Write a value to the config file
+  frank config validate                    # Check the configuration for errors`,
+}
+
+// Flags for config show
+var (
+	configShowEffective bool
+	configShowFormat    string
+)
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configValidateCmd)
+
+	configShowCmd.Flags().BoolVar(&configShowEffective, "effective", false, "Show every key with its source (default, file, env, flag)")
+	configShowCmd.Flags().StringVar(&configShowFormat, "format", "yaml", "Output format: yaml, json")
+}
+
+// ============================================================================
+// config show - Show the effective configuration
+// ============================================================================
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective configuration",
+	Long:  `Show the configuration frank is using after merging defaults, file, environment and flags.`,
+	RunE:  runConfigShow,
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	if configShowEffective {
+		return showEffectiveConfig(cmd)
+	}
+
+	values := configToMap(config.Settings(cfg, nil))
+
+	switch configShowFormat {
+	case "json":
+		data, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+		fmt.Println(string(data))
+	case "yaml":
+		data, err := yaml.Marshal(values)
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+		fmt.Print(string(data))
+	default:
+		return fmt.Errorf("unknown format %q (valid: yaml, json)", configShowFormat)
+	}
+	return nil
+}
+
+func showEffectiveConfig(cmd *cobra.Command) error {
+	settings := config.Settings(cfg, changedFlagKeys(cmd))
+
+	if configShowFormat == "json" {
+		type entry struct {
+			Key    string        `json:"key"`
+			Value  interface{}   `json:"value"`
+			Source config.Source `json:"source"`
+		}
+		entries := make([]entry, 0, len(settings))
+		for _, s := range settings {
+			entries = append(entries, entry{Key: s.Key, Value: displayValue(s.Value), Source: s.Source})
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Config file: %s\n\n", config.FilePath(cfgFile))
+
+	table := render.NewTable(render.Options{},
+		render.Column{Header: "KEY"},
+		render.Column{Header: "VALUE", MaxWidth: 60},
+		render.Column{Header: "SOURCE"},
+	)
+
+	for _, s := range settings {
+		value := fmt.Sprintf("%v", displayValue(s.Value))
+		table.Append(s.Key, value, formatConfigSource(s.Source))
+	}
+
+	return table.Render(os.Stdout)
+}
+
+// ============================================================================
+// config get - Get a single value
+// ============================================================================
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Get a configuration value",
+	Long:  `Print the effective value of a configuration key, e.g. container.basePort.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	value, err := config.Get(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	switch v := displayValue(value).(type) {
+	case string:
+		fmt.Println(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal value: %w", err)
+		}
+		fmt.Println(string(data))
+	}
+	return nil
+}
+
+// ============================================================================
+// config set - Write a value to the config file
+// ============================================================================
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a configuration value",
+	Long: `Write a configuration value to the config file.
+
+List values are given comma-separated. The resulting configuration is
+validated before it is saved.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runConfigSet,
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	path := config.FilePath(cfgFile)
+
+	// Keep the original so an invalid result can be rolled back
+	original, readErr := os.ReadFile(path)
+
+	key, err := config.SetValue(path, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	updated, err := config.Load(path)
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to reload config: %w", err), restoreConfigFile(path, original, readErr))
+	}
+	if errs := config.Validate(updated); len(errs) > 0 {
+		for _, e := range errs {
+			PrintError("%s", e.Error())
+		}
+		return errors.Join(exitcode.Errorf(exitcode.Config, "refusing to save invalid configuration"), restoreConfigFile(path, original, readErr))
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("%s Set %s = %s in %s\n", green("✓"), key, args[1], path)
+
+	if _, ok := os.LookupEnv(config.EnvVarName(key)); ok {
+		fmt.Printf("Note: %s is set and overrides the file value\n", config.EnvVarName(key))
+	}
+	return nil
+}
+
+// restoreConfigFile puts back the config file contents from before a failed
+// set, removing a file the set created
+func restoreConfigFile(path string, original []byte, readErr error) error {
+	if os.IsNotExist(readErr) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		return nil
+	}
+	if readErr != nil {
+		return nil
+	}
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", path, err)
+	}
+	return nil
+}
+
+// ============================================================================
+// config validate - Check the configuration
+// ============================================================================
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the configuration",
+	Long:  `Check the effective configuration for invalid values such as port ranges and runtime names.`,
+	RunE:  runConfigValidate,
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	errs := config.Validate(cfg)
+	if len(errs) == 0 {
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Configuration is valid\n", green("✓"))
+		return nil
+	}
+
+	for _, e := range errs {
+		PrintError("%s", e.Error())
+	}
+	return fmt.Errorf("configuration has %d error(s)", len(errs))
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// changedFlagKeys maps persistent flags set on the command line to their config keys
+func changedFlagKeys(cmd *cobra.Command) map[string]bool {
+	keys := make(map[string]bool)
+	flags := map[string]string{
+		"runtime":    "runtime.preferred",
+		"verbose":    "logging.verbose",
+		"log-level":  "logging.level",
+		"log-file":   "logging.file",
+		"log-format": "logging.format",
+	}
+	for flag, key := range flags {
+		if f := cmd.Flags().Lookup(flag); f != nil && f.Changed {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// configToMap rebuilds a nested map from flattened settings for YAML output
+func configToMap(settings []config.Setting) map[string]interface{} {
+	out := make(map[string]interface{})
+	for _, s := range settings {
+		parts := strings.Split(s.Key, ".")
+		node := out
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = displayValue(s.Value)
+	}
+	return out
+}
+
+// displayValue renders durations as strings so output matches the config file syntax
+func displayValue(v interface{}) interface{} {
+	if d, ok := v.(time.Duration); ok {
+		return d.String()
+	}
+	return v
+}
+
+func formatConfigSource(source config.Source) string {
+	switch source {
+	case config.SourceFlag:
+		return color.MagentaString(string(source))
+	case config.SourceEnv:
+		return color.CyanString(string(source))
+	case config.SourceFile:
+		return color.GreenString(string(source))
+	default:
+		return string(source)
+	}
+}
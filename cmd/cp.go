@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// containerWorkDir is where relative container paths start, the image's WORKDIR
+const containerWorkDir = "/workspace"
+
+var cpCmd = &cobra.Command{
+	Use:   "cp <container>:<path> <local-path> | <local-path> <container>:<path>",
+	Short: "Copy files between a container and this machine",
+	Long: `Copy a file or directory out of or into a frank container, like docker cp.
+
+Relative container paths start at /workspace. If the destination is an
+existing directory the copy is created inside it, otherwise the copy takes
+the destination's name. A local destination of "-" writes a tar archive to
+stdout. The container doesn't need to be running.
+
+Examples:
+  frank cp frank-dev-1:myrepo/dist ./dist
+  frank cp frank-dev-1:/tmp/build.log .
+  frank cp .env frank-dev-1:myrepo/.env
+  frank cp ./fixtures frank-dev-1:/tmp`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCp,
+}
+
+func init() {
+	rootCmd.AddCommand(cpCmd)
+}
+
+func runCp(cmd *cobra.Command, args []string) error {
+	src, dest := args[0], args[1]
+
+	srcContainer, srcPath, srcRemote := splitContainerPath(src)
+	destContainer, destPath, destRemote := splitContainerPath(dest)
+	switch {
+	case srcRemote && destRemote:
+		return fmt.Errorf("copying between containers is not supported; copy to this machine first")
+	case !srcRemote && !destRemote:
+		return fmt.Errorf("one of source and destination must be <container>:<path>")
+	}
+
+	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
+	if err != nil {
+		return fmt.Errorf("failed to detect container runtime: %w", err)
+	}
+
+	PrintVerbose("Using runtime: %s", runtime.Name())
+
+	containerName := srcContainer
+	if destRemote {
+		containerName = destContainer
+	}
+	if _, err := runtime.GetContainer(containerName); err != nil {
+		return exitcode.Errorf(exitcode.NotFound, "container not found: %s", containerName)
+	}
+
+	if srcRemote {
+		srcPath = resolveContainerPath(srcPath)
+		PrintVerbose("Copying %s:%s to %s", containerName, srcPath, dest)
+		if err := runtime.CopyFromContainer(containerName, srcPath, dest); err != nil {
+			return err
+		}
+		if dest != "-" {
+			fmt.Printf("%s Copied %s:%s to %s\n", color.GreenString("✓"), containerName, srcPath, dest)
+		}
+		return nil
+	}
+
+	destPath = resolveContainerPath(destPath)
+	PrintVerbose("Copying %s to %s:%s", src, containerName, destPath)
+	if err := runtime.CopyToContainer(containerName, src, destPath); err != nil {
+		return err
+	}
+	fmt.Printf("%s Copied %s to %s:%s\n", color.GreenString("✓"), src, containerName, destPath)
+	return nil
+}
+
+// splitContainerPath splits a <container>:<path> argument. As with docker
+// cp, a colon after a path separator or a drive letter (C:\dir) is part of a
+// local path, which reports ok false.
+func splitContainerPath(arg string) (name, p string, ok bool) {
+	name, p, ok = strings.Cut(arg, ":")
+	if !ok || len(name) < 2 || p == "" || strings.ContainsAny(name, `/\`) {
+		return "", "", false
+	}
+	return name, p, true
+}
+
+// resolveContainerPath makes a container path absolute, relative to the
+// image's working directory
+func resolveContainerPath(p string) string {
+	if path.IsAbs(p) {
+		return p
+	}
+	return path.Join(containerWorkDir, p)
+}
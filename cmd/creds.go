@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/barff/frank/internal/aws"
+	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// credsDirLabel records the host credentials directory mounted into a container
+	credsDirLabel = "frank.aws-creds-dir"
+
+	// containerCredsDir is where the credentials directory is mounted in the container
+	containerCredsDir = "/root/.frank-aws"
+)
+
+var credsCmd = &cobra.Command{
+	Use:   "creds",
+	Short: "Manage AWS credentials for running containers",
+	Long: `Manage AWS credentials injected into running frank containers.
+
+Containers started with a specific --profile read AWS credentials from a
+mounted file, so they can be renewed without restarting the container.`,
+}
+
+var credsRefreshCmd = &cobra.Command{
+	Use:   "refresh [containers...]",
+	Short: "Refresh AWS credentials in running containers",
+	Long: `Re-export AWS SSO credentials on the host and write them into the
+credentials file mounted in each container.
+
+With no arguments all running frank containers with refreshable credentials
+are updated. With --watch, credentials are renewed shortly before they
+expire until interrupted.
+
+Examples:
+  frank creds refresh                      # Refresh all containers once
+  frank creds refresh frank-dev-1          # Refresh one container
+  frank creds refresh frank-dev-1 --watch  # Keep credentials fresh`,
+	RunE: runCredsRefresh,
+}
+
+var credsRefreshWatch bool
+
+func init() {
+	rootCmd.AddCommand(credsCmd)
+	credsCmd.AddCommand(credsRefreshCmd)
+
+	credsRefreshCmd.Flags().BoolVarP(&credsRefreshWatch, "watch", "w", false, "Keep refreshing credentials before they expire")
+}
+
+func runCredsRefresh(cmd *cobra.Command, args []string) error {
+	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
+	if err != nil {
+		return fmt.Errorf("failed to detect container runtime: %w", err)
+	}
+
+	targets, err := findRefreshableContainers(runtime, args)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		fmt.Println("No running containers with refreshable AWS credentials")
+		fmt.Println("(containers started with a specific --profile support refresh)")
+		return nil
+	}
+
+	// Make sure the host SSO session is usable before exporting
+	ssoManager := aws.NewSSOManager()
+	checked := make(map[string]bool)
+	for _, c := range targets {
+		profile := c.Labels["frank.profile"]
+		if checked[profile] {
+			continue
+		}
+		if err := ssoManager.EnsureLoggedIn(profile, cfg.AWS.AutoLogin); err != nil {
+			return exitcode.Errorf(exitcode.Auth, "failed to ensure AWS login: %w", err)
+		}
+		checked[profile] = true
+	}
+
+	if !credsRefreshWatch {
+		var failed int
+		for _, c := range targets {
+			refresher := aws.NewRefresher(c.Labels["frank.profile"], c.Labels[credsDirLabel], cfg.AWS.CredentialRefreshBuffer)
+			expiresAt, err := refresher.Refresh()
+			printRefreshResult(c.Name, expiresAt, err)
+			if err != nil {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("failed to refresh credentials for %d container(s)", failed)
+		}
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Watching AWS credentials for %d container(s) (Ctrl+C to stop)...\n", len(targets))
+
+	var wg sync.WaitGroup
+	for _, c := range targets {
+		name := c.Name
+		refresher := aws.NewRefresher(c.Labels["frank.profile"], c.Labels[credsDirLabel], cfg.AWS.CredentialRefreshBuffer)
+		refresher.OnRefresh = func(expiresAt time.Time, err error) {
+			printRefreshResult(name, expiresAt, err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			refresher.Run(ctx)
+		}()
+	}
+
+	wg.Wait()
+	return nil
+}
+
+// findRefreshableContainers returns running frank containers whose credentials live in a mounted file
+func findRefreshableContainers(runtime container.Runtime, names []string) ([]container.Container, error) {
+	var candidates []container.Container
+	if len(names) > 0 {
+		for _, name := range names {
+			c, err := runtime.GetContainer(name)
+			if err != nil {
+				return nil, exitcode.Errorf(exitcode.NotFound, "container not found: %s", name)
+			}
+			if c.Labels[credsDirLabel] == "" {
+				return nil, fmt.Errorf("container %s was not started with refreshable AWS credentials", name)
+			}
+			candidates = append(candidates, *c)
+		}
+		return candidates, nil
+	}
+
+	containers, err := runtime.ListContainers(container.ContainerFilter{
+		All:        false,
+		NamePrefix: "frank-",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	for _, c := range containers {
+		if c.Labels[credsDirLabel] != "" {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates, nil
+}
+
+func printRefreshResult(name string, expiresAt time.Time, err error) {
+	timestamp := time.Now().Format("15:04:05")
+	if err != nil {
+		PrintError("[%s] %s: %v", timestamp, name, err)
+		return
+	}
+	fmt.Printf("[%s] %s %s credentials refreshed (expire %s)\n",
+		timestamp, color.GreenString("✓"), color.CyanString(name), formatExpiry(expiresAt))
+}
+
+// formatExpiry renders a credential expiration as local time plus remaining duration
+func formatExpiry(expiresAt time.Time) string {
+	if expiresAt.IsZero() {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s, in %s", expiresAt.Local().Format("15:04"), time.Until(expiresAt).Round(time.Minute))
+}
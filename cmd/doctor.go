@@ -0,0 +1,481 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/barff/frank/internal/aws"
+	"github.com/barff/frank/internal/config"
+	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/git"
+	"github.com/barff/frank/internal/terminal"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose common environment problems",
+	Long: `Check the local environment for problems that break frank and print
+a fix for each one.
+
+Checks:
+  - Configuration values are valid
+  - A container runtime (Docker, Podman, OrbStack) is running
+  - The Dockerfile or container image is available
+  - Shell scripts and git hooks use LF line endings
+  - AWS CLI v2 is installed
+  - The AWS SSO session has not expired
+  - Your AWS credentials allow the commands that change AWS resources
+  - The configured port range has free ports
+  - No orphaned git worktrees are left behind
+
+Exits non-zero if any check fails.`,
+	RunE:         runDoctor,
+	SilenceUsage: true,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// checkStatus is the outcome of a single doctor check
+type checkStatus int
+
+const (
+	checkOK checkStatus = iota
+	checkWarn
+	checkFail
+	checkSkip
+)
+
+// checkResult describes what a check found and how to fix it
+type checkResult struct {
+	status checkStatus
+	detail string
+	fix    string
+}
+
+type doctorCheck struct {
+	name string
+	run  func() checkResult
+}
+
+// doctorRuntime is the runtime found by the runtime check, reused by later checks
+var doctorRuntime container.Runtime
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	checks := []doctorCheck{
+		{"Configuration", checkConfig},
+		{"Container runtime", checkRuntime},
+		{"Container image", checkImage},
+		{"Line endings", checkLineEndings},
+		{"AWS CLI", checkAWSCLI},
+		{"AWS SSO session", checkSSOSession},
+		{"AWS permissions", checkAWSPermissions},
+		{"Port range", checkPortRange},
+		{"Git worktrees", checkWorktrees},
+	}
+
+	var failed, warned int
+	for _, c := range checks {
+		switch printCheck(c.name, c.run()) {
+		case checkWarn:
+			warned++
+		case checkFail:
+			failed++
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed, %d warning(s)", failed, warned)
+	}
+	if warned > 0 {
+		fmt.Printf("All checks passed with %d warning(s)\n", warned)
+		return nil
+	}
+	fmt.Printf("%s All checks passed\n", color.GreenString("✓"))
+	return nil
+}
+
+// printCheck prints a check's result, with its fix when it didn't pass, and
+// returns its status
+func printCheck(name string, result checkResult) checkStatus {
+	var symbol string
+	switch result.status {
+	case checkOK:
+		symbol = color.GreenString("✓")
+	case checkWarn:
+		symbol = color.YellowString("!")
+	case checkFail:
+		symbol = color.RedString("✗")
+	case checkSkip:
+		symbol = color.HiBlackString("-")
+	}
+
+	fmt.Printf("%s %-24s %s\n", symbol, name, result.detail)
+	if result.fix != "" && (result.status == checkWarn || result.status == checkFail) {
+		for _, line := range strings.Split(result.fix, "\n") {
+			fmt.Printf("    %s\n", color.CyanString(line))
+		}
+	}
+	return result.status
+}
+
+func checkConfig() checkResult {
+	errs := config.Validate(cfg)
+	if len(errs) == 0 {
+		return checkResult{status: checkOK, detail: config.FilePath(cfgFile)}
+	}
+
+	var fixes []string
+	for _, e := range errs {
+		fixes = append(fixes, e.Error())
+	}
+	fixes = append(fixes, "Fix with: frank config set <key> <value>")
+	return checkResult{
+		status: checkFail,
+		detail: fmt.Sprintf("%d invalid value(s)", len(errs)),
+		fix:    strings.Join(fixes, "\n"),
+	}
+}
+
+func checkRuntime() checkResult {
+	rt, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
+	if err != nil || !rt.IsAvailable() {
+		var fixes []string
+		var detectErr *container.DetectError
+		if errors.As(err, &detectErr) {
+			for _, a := range detectErr.Attempts {
+				fixes = append(fixes, "Tried "+a.String())
+			}
+		}
+		fixes = append(fixes, "Start Docker Desktop, Podman (podman machine start) or OrbStack")
+		if cfg.Runtime.DockerHost != "" {
+			fixes = append(fixes, fmt.Sprintf("Or clear the pinned endpoint: frank config set runtime.dockerHost \"\" (currently %q)", cfg.Runtime.DockerHost))
+		}
+		if cfg.Runtime.Preferred != "auto" {
+			fixes = append(fixes, fmt.Sprintf("Or let frank auto-detect: frank config set runtime.preferred auto (currently %q)", cfg.Runtime.Preferred))
+		}
+		return checkResult{status: checkFail, detail: "no running container runtime found", fix: strings.Join(fixes, "\n")}
+	}
+
+	doctorRuntime = rt
+	detail := rt.Name()
+	if endpoint := container.RuntimeEndpoint(rt); endpoint != "" {
+		detail += " (" + endpoint + ")"
+	}
+	return checkResult{status: checkOK, detail: detail}
+}
+
+func checkImage() checkResult {
+	dockerfile, dockerfileErr := findDockerfile()
+
+	if doctorRuntime == nil {
+		if dockerfileErr != nil {
+			return checkResult{status: checkWarn, detail: "Dockerfile not found", fix: "Run frank from the repository root, or copy build/Dockerfile to ~/.config/frank/Dockerfile"}
+		}
+		return checkResult{status: checkSkip, detail: "no runtime to check image"}
+	}
+
+	exists, err := doctorRuntime.ImageExists(cfg.Container.Image)
+	if err != nil {
+		return checkResult{status: checkWarn, detail: fmt.Sprintf("failed to check image: %v", err)}
+	}
+
+	switch {
+	case exists:
+		return checkResult{status: checkOK, detail: cfg.Container.Image}
+	case dockerfileErr != nil:
+		return checkResult{
+			status: checkFail,
+			detail: fmt.Sprintf("image %s missing and no Dockerfile found", cfg.Container.Image),
+			fix:    "Run frank from the repository root, or copy build/Dockerfile to ~/.config/frank/Dockerfile, then run: frank rebuild",
+		}
+	default:
+		return checkResult{
+			status: checkWarn,
+			detail: fmt.Sprintf("image %s not built yet (Dockerfile: %s)", cfg.Container.Image, dockerfile),
+			fix:    "Run: frank rebuild",
+		}
+	}
+}
+
+// checkLineEndings finds scripts copied into the image that have CRLF endings,
+// which make bash fail with "/bin/bash^M: bad interpreter" inside the container
+func checkLineEndings() checkResult {
+	dockerfile, err := findDockerfile()
+	if err != nil {
+		return checkResult{status: checkSkip, detail: "no build directory found"}
+	}
+	buildDir := filepath.Dir(dockerfile)
+
+	var candidates []string
+	scripts, _ := filepath.Glob(filepath.Join(buildDir, "*.sh"))
+	candidates = append(candidates, scripts...)
+	hooks, _ := filepath.Glob(filepath.Join(buildDir, "git-templates", "hooks", "*"))
+	candidates = append(candidates, hooks...)
+	candidates = append(candidates, dockerfile)
+
+	var bad []string
+	for _, path := range candidates {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if bytes.Contains(data, []byte("\r\n")) {
+			rel, err := filepath.Rel(buildDir, path)
+			if err != nil {
+				rel = path
+			}
+			bad = append(bad, rel)
+		}
+	}
+
+	if len(bad) == 0 {
+		return checkResult{status: checkOK, detail: fmt.Sprintf("%d file(s) checked", len(candidates))}
+	}
+	return checkResult{
+		status: checkFail,
+		detail: fmt.Sprintf("CRLF line endings in %s", strings.Join(bad, ", ")),
+		fix: fmt.Sprintf("Convert with: dos2unix %s\nPrevent it with: git config core.autocrlf input && git checkout -- %s",
+			strings.Join(bad, " "), buildDir),
+	}
+}
+
+var awsCLIVersionRe = regexp.MustCompile(`aws-cli/(\d+)\.(\d+)\.(\d+)`)
+
+func checkAWSCLI() checkResult {
+	if _, err := exec.LookPath("aws"); err != nil {
+		return checkResult{
+			status: checkFail,
+			detail: "aws not found in PATH",
+			fix:    "Install AWS CLI v2: https://docs.aws.amazon.com/cli/latest/userguide/getting-started-install.html",
+		}
+	}
+
+	output, err := exec.Command("aws", "--version").CombinedOutput()
+	if err != nil {
+		return checkResult{status: checkWarn, detail: fmt.Sprintf("failed to run aws --version: %v", err)}
+	}
+
+	match := awsCLIVersionRe.FindStringSubmatch(string(output))
+	if match == nil {
+		return checkResult{status: checkWarn, detail: fmt.Sprintf("unrecognised version: %s", strings.TrimSpace(string(output)))}
+	}
+	version := fmt.Sprintf("%s.%s.%s", match[1], match[2], match[3])
+	if match[1] != "2" {
+		return checkResult{
+			status: checkFail,
+			detail: fmt.Sprintf("version %s (v2 required for SSO credential export)", version),
+			fix:    "Upgrade to AWS CLI v2: https://docs.aws.amazon.com/cli/latest/userguide/getting-started-install.html",
+		}
+	}
+	return checkResult{status: checkOK, detail: version}
+}
+
+func checkSSOSession() checkResult {
+	profile := cfg.AWS.DefaultProfile
+	loginCmd := "aws sso login"
+	if profile != "" {
+		loginCmd += " --profile " + profile
+	}
+
+	expiresAt, err := aws.NewSSOManager().SessionExpiry()
+	if err != nil {
+		return checkResult{status: checkSkip, detail: "no cached SSO session"}
+	}
+
+	remaining := time.Until(expiresAt)
+	switch {
+	case remaining <= 0:
+		return checkResult{
+			status: checkFail,
+			detail: fmt.Sprintf("expired %s ago", (-remaining).Round(time.Minute)),
+			fix:    "Run: " + loginCmd,
+		}
+	case remaining < cfg.AWS.CredentialRefreshBuffer:
+		return checkResult{
+			status: checkWarn,
+			detail: fmt.Sprintf("expires in %s", remaining.Round(time.Minute)),
+			fix:    "Run: " + loginCmd,
+		}
+	default:
+		return checkResult{status: checkOK, detail: fmt.Sprintf("valid for %s", remaining.Round(time.Minute))}
+	}
+}
+
+// checkAWSPermissions reports the commands the caller's IAM policies won't
+// let run, according to the IAM policy simulator
+func checkAWSPermissions() checkResult {
+	if cfg.AWS.ReadOnly {
+		return checkResult{status: checkSkip, detail: "read-only mode"}
+	}
+
+	commands := mutatingCommands(rootCmd)
+	var actions []string
+	for _, needs := range commands {
+		for _, action := range needs {
+			if !slices.Contains(actions, action) {
+				actions = append(actions, action)
+			}
+		}
+	}
+	sort.Strings(actions)
+
+	denied, err := deniedActions(actions)
+	if err != nil {
+		return checkResult{status: checkSkip, detail: fmt.Sprintf("can't simulate IAM policies (%v)", err)}
+	}
+	if len(denied) == 0 {
+		return checkResult{status: checkOK, detail: fmt.Sprintf("%d actions allowed", len(actions))}
+	}
+
+	paths := make([]string, 0, len(commands))
+	for path := range commands {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var fixes []string
+	for _, path := range paths {
+		var missing []string
+		for _, action := range commands[path] {
+			if slices.Contains(denied, action) {
+				missing = append(missing, action)
+			}
+		}
+		if len(missing) > 0 {
+			fixes = append(fixes, fmt.Sprintf("%s needs %s", path, strings.Join(missing, ", ")))
+		}
+	}
+	fixes = append(fixes, "Ask for these permissions, or set aws.readOnly to stop frank trying")
+	return checkResult{
+		status: checkWarn,
+		detail: fmt.Sprintf("%d of %d actions denied", len(denied), len(actions)),
+		fix:    strings.Join(fixes, "\n"),
+	}
+}
+
+// checkPortRange reports ports in the configured range held by non-frank processes
+func checkPortRange() checkResult {
+	basePort, maxPort := cfg.Container.BasePort, cfg.Container.MaxPort
+	if basePort < 1 || maxPort > 65535 || basePort >= maxPort {
+		return checkResult{status: checkSkip, detail: "invalid range (see Configuration)"}
+	}
+
+	frankPorts := make(map[int]bool)
+	if doctorRuntime != nil {
+		containers, _ := doctorRuntime.ListContainers(container.ContainerFilter{NamePrefix: "frank-"})
+		for _, c := range containers {
+			for _, p := range c.Ports {
+				frankPorts[p.HostPort] = true
+			}
+		}
+	}
+
+	// Containers need 4 consecutive free ports
+	var foreign []int
+	freeBlocks, run := 0, 0
+	for port := basePort; port <= maxPort; port++ {
+		switch {
+		case frankPorts[port]:
+			run = 0
+		case terminal.IsPortAvailable(port):
+			run++
+			if run == 4 {
+				freeBlocks++
+				run = 0
+			}
+		default:
+			foreign = append(foreign, port)
+			run = 0
+		}
+	}
+
+	detail := fmt.Sprintf("%d-%d, room for %d more container(s)", basePort, maxPort, freeBlocks)
+	if freeBlocks == 0 {
+		return checkResult{
+			status: checkFail,
+			detail: detail,
+			fix:    fmt.Sprintf("Stop unused containers (frank stop --all) or widen the range: frank config set container.maxPort %d", maxPort+100),
+		}
+	}
+	if len(foreign) > 0 {
+		return checkResult{
+			status: checkWarn,
+			detail: fmt.Sprintf("%s; %d port(s) used by other processes: %s", detail, len(foreign), formatPortList(foreign)),
+			fix:    "Free those ports or move the range: frank config set container.basePort <port>",
+		}
+	}
+	return checkResult{status: checkOK, detail: detail}
+}
+
+// formatPortList shows up to five ports followed by a count of the rest
+func formatPortList(ports []int) string {
+	var parts []string
+	for i, p := range ports {
+		if i == 5 {
+			parts = append(parts, fmt.Sprintf("+%d more", len(ports)-5))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%d", p))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// checkWorktrees finds worktree directories whose container no longer exists
+func checkWorktrees() checkResult {
+	if doctorRuntime == nil {
+		return checkResult{status: checkSkip, detail: "no runtime to compare against"}
+	}
+
+	names, err := git.NewWorktreeManager(cfg.Git.WorktreeBase).Dirs()
+	if err != nil {
+		return checkResult{status: checkWarn, detail: err.Error()}
+	}
+	if len(names) == 0 {
+		return checkResult{status: checkOK, detail: "none"}
+	}
+
+	containers, err := doctorRuntime.ListContainers(container.ContainerFilter{All: true, NamePrefix: "frank-"})
+	if err != nil {
+		return checkResult{status: checkWarn, detail: fmt.Sprintf("failed to list containers: %v", err)}
+	}
+	existing := make(map[string]bool)
+	for _, c := range containers {
+		existing[c.Name] = true
+	}
+
+	var orphaned []string
+	for _, name := range names {
+		if !existing[name] {
+			orphaned = append(orphaned, name)
+		}
+	}
+
+	if len(orphaned) == 0 {
+		return checkResult{status: checkOK, detail: fmt.Sprintf("%d in use", len(names))}
+	}
+
+	var fixes []string
+	for _, name := range orphaned {
+		fixes = append(fixes, "rm -rf "+filepath.Join(cfg.Git.WorktreeBase, name))
+	}
+	fixes = append(fixes, fmt.Sprintf("git -C %s worktree prune", filepath.Join(cfg.Git.WorktreeBase, ".main-repo")))
+	return checkResult{
+		status: checkWarn,
+		detail: fmt.Sprintf("%d orphaned: %s", len(orphaned), strings.Join(orphaned, ", ")),
+		fix:    strings.Join(fixes, "\n"),
+	}
+}
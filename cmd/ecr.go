@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	frankaws "github.com/barff/frank/internal/aws"
+	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/fatih/color"
+)
+
+// defaultECRRepository is the repository name used when --repo is not given
+const defaultECRRepository = "frank"
+
+// lastPushedTimeout bounds the ECR lookup for the default build cache, so a
+// machine without AWS access isn't held up
+const lastPushedTimeout = 15 * time.Second
+
+// pushToECR tags the local image into the ECR repository, pushes it and
+// optionally rolls the ECS task definition forward to the pushed digest
+func pushToECR(runtime container.Runtime, localTag string) error {
+	ctx := context.Background()
+
+	awsCfg, err := rebuildAWSConfig(ctx)
+	if err != nil {
+		return err
+	}
+	ecrClient := ecr.NewFromConfig(awsCfg)
+
+	repoURI := rebuildRepo
+	if repoURI == "" {
+		repoURI, err = lookupECRRepository(ctx, ecrClient, defaultECRRepository)
+		if err != nil {
+			return err
+		}
+	}
+
+	auth, err := getECRAuth(ctx, ecrClient)
+	if err != nil {
+		return err
+	}
+
+	remoteTag := repoURI + ":" + imageTagOf(localTag)
+	if err := runtime.TagImage(localTag, remoteTag); err != nil {
+		return fmt.Errorf("failed to tag image: %w", err)
+	}
+
+	fmt.Printf("\nPushing %s...\n", color.CyanString(remoteTag))
+	digest, err := runtime.PushImage(remoteTag, auth)
+	if err != nil {
+		return err
+	}
+	if digest == "" {
+		return fmt.Errorf("push completed but no image digest was reported")
+	}
+
+	imageRef := repoURI + "@" + digest
+	fmt.Printf("%s Image pushed: %s\n", color.GreenString("✓"), imageRef)
+
+	if !rebuildUpdateTaskDef && !rebuildDeploy {
+		return nil
+	}
+
+	ecsClient := ecs.NewFromConfig(awsCfg)
+	currentTaskDef, err := currentServiceTaskDef(ctx, ecsClient, cfg.ECS.Cluster)
+	if err != nil {
+		return err
+	}
+
+	newTaskDef, err := registerTaskDefRevision(ctx, ecsClient, currentTaskDef, func(containers []types.ContainerDefinition) error {
+		def, err := findContainerDef(containers, defaultContainer)
+		if err != nil {
+			return err
+		}
+		def.Image = aws.String(imageRef)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Registered task definition: %s\n", color.GreenString("✓"), extractTaskDefName(newTaskDef))
+
+	if !rebuildDeploy {
+		fmt.Println("Run with --deploy to roll the service, or use it for new tasks via 'frank ecs start'")
+		return nil
+	}
+
+	_, err = ecsClient.UpdateService(ctx, &ecs.UpdateServiceInput{
+		Cluster:        aws.String(cfg.ECS.Cluster),
+		Service:        aws.String(defaultService),
+		TaskDefinition: aws.String(newTaskDef),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update service: %w", err)
+	}
+	fmt.Printf("%s Service %s rolling to %s\n", color.GreenString("✓"), defaultService, extractTaskDefName(newTaskDef))
+	return nil
+}
+
+// rebuildAWSConfig loads the AWS config for rebuild's ECR and ECS calls
+func rebuildAWSConfig(ctx context.Context) (aws.Config, error) {
+	opts := []func(*config.LoadOptions) error{frankaws.WithRetries(), withAudit()}
+	if rebuildRegion != "" {
+		opts = append(opts, config.WithRegion(rebuildRegion))
+	}
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return awsCfg, nil
+}
+
+// lastPushedImage returns the image in the ECR repository with localTag's
+// tag, or "" when there is none or ECR can't be reached
+func lastPushedImage(ctx context.Context, localTag string) string {
+	ctx, cancel := context.WithTimeout(ctx, lastPushedTimeout)
+	defer cancel()
+
+	awsCfg, err := rebuildAWSConfig(ctx)
+	if err != nil {
+		PrintVerbose("Not looking for a pushed image: %v", err)
+		return ""
+	}
+	client := ecr.NewFromConfig(awsCfg)
+
+	repoURI := rebuildRepo
+	if repoURI == "" {
+		if repoURI, err = lookupECRRepository(ctx, client, defaultECRRepository); err != nil {
+			PrintVerbose("Not looking for a pushed image: %v", err)
+			return ""
+		}
+	}
+	_, name, _ := strings.Cut(repoURI, "/")
+	tag := imageTagOf(localTag)
+	_, err = client.DescribeImages(ctx, &ecr.DescribeImagesInput{
+		RepositoryName: aws.String(name),
+		ImageIds:       []ecrtypes.ImageIdentifier{{ImageTag: aws.String(tag)}},
+	})
+	if err != nil {
+		PrintVerbose("No pushed image %s:%s: %v", repoURI, tag, err)
+		return ""
+	}
+	return repoURI + ":" + tag
+}
+
+// lookupECRRepository resolves a repository name to its URI
+func lookupECRRepository(ctx context.Context, client *ecr.Client, name string) (string, error) {
+	out, err := client.DescribeRepositories(ctx, &ecr.DescribeRepositoriesInput{
+		RepositoryNames: []string{name},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to find ECR repository %q (use --repo to specify one): %w", name, err)
+	}
+	if len(out.Repositories) == 0 {
+		return "", exitcode.Errorf(exitcode.NotFound, "ECR repository %q not found (use --repo to specify one)", name)
+	}
+	return aws.ToString(out.Repositories[0].RepositoryUri), nil
+}
+
+// getECRAuth exchanges AWS credentials for registry credentials
+func getECRAuth(ctx context.Context, client *ecr.Client) (container.RegistryAuth, error) {
+	out, err := client.GetAuthorizationToken(ctx, &ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		return container.RegistryAuth{}, fmt.Errorf("failed to get ECR authorization token: %w", err)
+	}
+	if len(out.AuthorizationData) == 0 {
+		return container.RegistryAuth{}, fmt.Errorf("ECR returned no authorization data")
+	}
+
+	data := out.AuthorizationData[0]
+	decoded, err := base64.StdEncoding.DecodeString(aws.ToString(data.AuthorizationToken))
+	if err != nil {
+		return container.RegistryAuth{}, fmt.Errorf("failed to decode ECR authorization token: %w", err)
+	}
+
+	// Token is "AWS:<password>"
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 {
+		return container.RegistryAuth{}, fmt.Errorf("unexpected ECR authorization token format")
+	}
+
+	return container.RegistryAuth{
+		Username:      parts[0],
+		Password:      parts[1],
+		ServerAddress: aws.ToString(data.ProxyEndpoint),
+	}, nil
+}
+
+// imageTagOf returns the tag portion of an image reference, defaulting to latest
+func imageTagOf(image string) string {
+	slash := strings.LastIndex(image, "/")
+	if colon := strings.LastIndex(image, ":"); colon > slash {
+		return image[colon+1:]
+	}
+	return "latest"
+}
@@ -1,12 +1,25 @@
 package cmd
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
-	"os/exec"
+	"os/signal"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -14,10 +27,21 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	schedulertypes "github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/barff/frank/internal/agent"
 	"github.com/barff/frank/internal/alb"
+	"github.com/barff/frank/internal/archive"
+	frankaws "github.com/barff/frank/internal/aws"
+	frankecs "github.com/barff/frank/internal/ecs"
+	"github.com/barff/frank/internal/exitcode"
 	"github.com/barff/frank/internal/profile"
+	"github.com/barff/frank/internal/redact"
+	"github.com/barff/frank/internal/render"
+	"github.com/barff/frank/internal/ssmexec"
+	"github.com/barff/frank/internal/state"
 	"github.com/fatih/color"
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
@@ -27,8 +51,17 @@ const (
 	defaultService   = "frank"
 	defaultLogGroup  = "/ecs/frank"
 	defaultTaskFamily = "FrankStack-FrankTask"
+	defaultContainer = "frank"
 )
 
+// defaultBootTimeout is how long 'ecs start' waits for a task to turn healthy
+// when its profile doesn't set boot_timeout
+const defaultBootTimeout = 5 * time.Minute
+
+// defaultPrewarmWorkers is how many worktrees 'ecs prewarm' creates when
+// neither --workers nor the profile's prewarm_workers says
+const defaultPrewarmWorkers = 4
+
 var ecsCmd = &cobra.Command{
 	Use:   "ecs",
 	Short: "Manage Frank instances on AWS ECS",
@@ -40,6 +73,7 @@ stop tasks, and stream logs.
 Examples:
   frank ecs start enkai             # Start a profile (creates subdomain)
   frank ecs list                    # List all running Frank tasks
+  frank ecs list --mine             # List the tasks you started
   frank ecs stop enkai              # Stop a profile by name
   frank ecs stop <task-id>          # Stop a specific task by ID
   frank ecs logs <task-id>          # Stream logs from a task`,
@@ -47,11 +81,53 @@ Examples:
 
 // Subcommand flags
 var (
-	ecsCluster      string
-	ecsRegion       string
-	ecsLogsFollow   bool
-	ecsLogsTail     int
-	prewarmWorkers  int
+	ecsCluster       string
+	ecsRegion        string
+	ecsLogsFollow    bool
+	ecsLogsTail      int
+	ecsLogsExport    string
+	prewarmWorkers   int
+	prewarmAll       bool
+	prewarmParallel  int
+	prewarmCron      string
+	prewarmTimezone  string
+	prewarmRoleArn   string
+	prewarmDelete    bool
+	ecsListWatch     bool
+	ecsListInterval  time.Duration
+	taskDefImage     string
+	taskDefEnv       []string
+	taskDefUnsetEnv  []string
+	taskDefNoDeploy  bool
+	autostopIdle     time.Duration
+	autostopDryRun   bool
+	autostopWatch    bool
+	autostopInterval time.Duration
+	autostopExclude  []string
+	ecsStartDryRun   bool
+	ecsStartResume   bool
+	ecsStartNoWait   bool
+	ecsStartRecord   bool
+	ecsStopDryRun    bool
+	ecsRunRepo       string
+	ecsRunBranch     string
+	ecsRunEnv        []string
+	ecsRunName       string
+	ecsRunPrompt     string
+	ecsRunCapacity   string
+	ecsRunWait       bool
+	ecsRunMaxSpot    int
+	cleanupDryRun    bool
+	albAuditAll      bool
+	ecsTunnelPorts   []string
+	ecsListMine      bool
+	ecsListOwner     string
+	ecsListOutput    render.Options
+	ecsPoolOutput    render.Options
+	ecsStopMine      bool
+	ecsStopOwner     string
+	cleanupMine      bool
+	cleanupOwner     string
 )
 
 func init() {
@@ -70,20 +146,91 @@ func init() {
 	ecsCmd.AddCommand(ecsLogsCmd)
 	ecsCmd.AddCommand(ecsStatusCmd)
 	ecsCmd.AddCommand(ecsExecCmd)
+	ecsCmd.AddCommand(ecsTunnelCmd)
+	ecsCmd.AddCommand(ecsCpCmd)
+	ecsCmd.AddCommand(ecsLsCmd)
 	ecsCmd.AddCommand(ecsPrewarmCmd)
 	ecsCmd.AddCommand(ecsCleanupCmd)
+	ecsCmd.AddCommand(ecsTaskDefCmd)
+	ecsCmd.AddCommand(ecsAutostopCmd)
+	ecsCmd.AddCommand(ecsALBCmd)
+	ecsCmd.AddCommand(ecsRoutesCmd)
+	ecsCmd.AddCommand(ecsVerifyCmd)
+	ecsCmd.AddCommand(ecsPoolCmd)
+	ecsPoolCmd.AddCommand(ecsPoolSetSizeCmd)
+
+	// ALB subcommands
+	ecsALBCmd.AddCommand(ecsALBAuditCmd)
+	ecsALBAuditCmd.Flags().BoolVar(&albAuditAll, "all", false, "Also list every listener rule, not just problems")
+
+	// Task definition subcommands
+	ecsTaskDefCmd.AddCommand(ecsTaskDefShowCmd)
+	ecsTaskDefCmd.AddCommand(ecsTaskDefUpdateCmd)
+	ecsTaskDefUpdateCmd.Flags().StringVar(&taskDefImage, "image", "", "New container image")
+	ecsTaskDefUpdateCmd.Flags().StringArrayVar(&taskDefEnv, "env", nil, "Set an environment variable (KEY=VAL, repeatable)")
+	ecsTaskDefUpdateCmd.Flags().StringArrayVar(&taskDefUnsetEnv, "unset-env", nil, "Remove an environment variable (repeatable)")
+	ecsTaskDefUpdateCmd.Flags().BoolVar(&taskDefNoDeploy, "no-deploy", false, "Register the revision without updating the service")
+
+	// List command flags
+	ecsListCmd.Flags().BoolVarP(&ecsListWatch, "watch", "w", false, "Refresh the table until interrupted")
+	ecsListCmd.Flags().DurationVar(&ecsListInterval, "interval", defaultWatchInterval, "Refresh interval for --watch")
+	ecsListCmd.Flags().BoolVar(&ecsListMine, "mine", false, "Only list tasks you started")
+	ecsListCmd.Flags().StringVar(&ecsListOwner, "owner", "", "Only list tasks started by this owner")
+	render.AddFlags(ecsListCmd, &ecsListOutput)
+	render.AddFlags(ecsPoolCmd, &ecsPoolOutput)
+
+	// Autostop command flags
+	ecsStartCmd.Flags().BoolVar(&ecsStartDryRun, "dry-run", false, "Show the ALB changes without starting the task")
+	ecsStartCmd.Flags().BoolVar(&ecsStartRecord, "record", false, "Record the agent terminal for this run (see 'frank recordings')")
+	ecsStartCmd.Flags().BoolVar(&ecsStartNoWait, "no-wait", false, "Don't wait for the task to pass its health check")
+	ecsStartCmd.Flags().BoolVar(&ecsStartResume, "resume", false, "Reattach the workspace and agent session of the profile's last task")
+
+	// Run command flags
+	ecsRunCmd.Flags().StringVarP(&ecsRunRepo, "repo", "r", "", "Git repository URL to clone")
+	ecsRunCmd.Flags().StringVarP(&ecsRunBranch, "branch", "b", "", "Branch to check out (default: main)")
+	ecsRunCmd.Flags().StringArrayVar(&ecsRunEnv, "env", nil, "Set a container environment variable (KEY=VAL, repeatable)")
+	ecsRunCmd.Flags().StringVarP(&ecsRunName, "name", "n", "", "Container name for the task's worktree and agent session (default: the task's hostname)")
+	ecsRunCmd.Flags().StringVar(&ecsRunPrompt, "task-prompt", "", "Send a prompt to the agent as soon as it starts")
+	ecsRunCmd.Flags().StringVar(&ecsRunCapacity, "capacity", string(frankecs.CapacityOnDemand), "Fargate capacity: spot, on-demand or auto (Spot, falling back to on-demand)")
+	ecsRunCmd.Flags().BoolVar(&ecsRunWait, "wait", false, "Wait for the task to finish, running it again if Fargate Spot reclaims it")
+	ecsRunCmd.Flags().IntVar(&ecsRunMaxSpot, "max-interruptions", 3, "Spot interruptions to recover from with --wait before giving up")
+	ecsStopCmd.Flags().BoolVar(&ecsStopDryRun, "dry-run", false, "Show the ALB changes without stopping the task")
+	ecsStopCmd.Flags().BoolVar(&ecsStopMine, "mine", false, "Stop every task you started, or refuse to stop someone else's")
+	ecsStopCmd.Flags().StringVar(&ecsStopOwner, "owner", "", "Stop every task started by this owner, or refuse to stop anyone else's")
+	ecsCleanupCmd.Flags().BoolVar(&cleanupDryRun, "dry-run", false, "Show the ALB resources that would be deleted")
+	ecsCleanupCmd.Flags().BoolVar(&cleanupMine, "mine", false, "Only clean up profiles you started")
+	ecsCleanupCmd.Flags().StringVar(&cleanupOwner, "owner", "", "Only clean up profiles started by this owner")
+
+	ecsAutostopCmd.Flags().DurationVar(&autostopIdle, "idle", defaultAutostopIdle, "Stop tasks idle for at least this long")
+	ecsAutostopCmd.Flags().BoolVar(&autostopDryRun, "dry-run", false, "Show idle tasks without stopping them")
+	ecsAutostopCmd.Flags().BoolVarP(&autostopWatch, "watch", "w", false, "Keep running and check every --interval")
+	ecsAutostopCmd.Flags().DurationVar(&autostopInterval, "interval", 15*time.Minute, "Check interval for --watch")
+	ecsAutostopCmd.Flags().StringSliceVar(&autostopExclude, "exclude", nil, "Profiles that are never stopped (repeatable)")
 
 	// Prewarm command flags
-	ecsPrewarmCmd.Flags().IntVar(&prewarmWorkers, "workers", 4, "Number of worktrees to create")
+	ecsPrewarmCmd.AddCommand(ecsPrewarmScheduleCmd)
+	ecsPrewarmCmd.PersistentFlags().IntVar(&prewarmWorkers, "workers", 0, "Number of worktrees to create (default: the profile's prewarm_workers, or 4)")
+	ecsPrewarmCmd.Flags().BoolVar(&prewarmAll, "all", false, "Pre-warm every configured profile")
+	ecsPrewarmCmd.Flags().IntVar(&prewarmParallel, "parallel", 1, "Profiles to pre-warm at once with --all")
+	ecsPrewarmScheduleCmd.Flags().StringVar(&prewarmCron, "cron", "", "When to pre-warm, e.g. \"0 6 * * 1-5\" for 6:00 on weekdays")
+	ecsPrewarmScheduleCmd.Flags().StringVar(&prewarmTimezone, "timezone", "UTC", "IANA timezone for --cron, e.g. America/New_York")
+	ecsPrewarmScheduleCmd.Flags().StringVar(&prewarmRoleArn, "role-arn", "", "Role the schedule assumes (default: the stack's frank-scheduler role)")
+	ecsPrewarmScheduleCmd.Flags().BoolVar(&prewarmDelete, "delete", false, "Delete the prewarm schedule")
+
+	// Tunnel command flags
+	ecsTunnelCmd.Flags().StringArrayVarP(&ecsTunnelPorts, "port", "p", []string{"7683"}, "Port to forward as LOCAL:REMOTE or PORT (repeatable)")
 
 	// Logs command flags
 	ecsLogsCmd.Flags().BoolVarP(&ecsLogsFollow, "follow", "f", false, "Follow log output")
 	ecsLogsCmd.Flags().IntVarP(&ecsLogsTail, "tail", "t", 50, "Number of lines to show from the end")
+	ecsLogsCmd.Flags().StringVar(&ecsLogsExport, "export", "", "Write the entire log stream to a file or s3://bucket/key")
+	ecsLogsCmd.MarkFlagsMutuallyExclusive("export", "follow")
 }
 
-// getECSClient creates an ECS client with the configured region
+// getECSClient creates an ECS client with the configured region. Task
+// launches and stops are recorded in the audit log.
 func getECSClient(ctx context.Context) (*ecs.Client, error) {
-	opts := []func(*config.LoadOptions) error{}
+	opts := []func(*config.LoadOptions) error{frankaws.WithRetries(), withAudit()}
 	if ecsRegion != "" {
 		opts = append(opts, config.WithRegion(ecsRegion))
 	}
@@ -96,9 +243,34 @@ func getECSClient(ctx context.Context) (*ecs.Client, error) {
 	return ecs.NewFromConfig(cfg), nil
 }
 
+// getTaskClient creates the client for finding and starting profile tasks in
+// the configured cluster
+func getTaskClient(ctx context.Context) (*frankecs.Client, error) {
+	client, err := getECSClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return frankecs.New(client, nil, ecsCluster), nil
+}
+
+// getSSMClient creates an SSM client for Session Manager sessions
+func getSSMClient(ctx context.Context) (*ssm.Client, error) {
+	opts := []func(*config.LoadOptions) error{frankaws.WithRetries(), withAudit()}
+	if ecsRegion != "" {
+		opts = append(opts, config.WithRegion(ecsRegion))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return ssm.NewFromConfig(cfg), nil
+}
+
 // getLogsClient creates a CloudWatch Logs client
 func getLogsClient(ctx context.Context) (*cloudwatchlogs.Client, error) {
-	opts := []func(*config.LoadOptions) error{}
+	opts := []func(*config.LoadOptions) error{frankaws.WithRetries()}
 	if ecsRegion != "" {
 		opts = append(opts, config.WithRegion(ecsRegion))
 	}
@@ -111,6 +283,21 @@ func getLogsClient(ctx context.Context) (*cloudwatchlogs.Client, error) {
 	return cloudwatchlogs.NewFromConfig(cfg), nil
 }
 
+// getSchedulerClient creates an EventBridge Scheduler client
+func getSchedulerClient(ctx context.Context) (*scheduler.Client, error) {
+	opts := []func(*config.LoadOptions) error{frankaws.WithRetries(), withAudit()}
+	if ecsRegion != "" {
+		opts = append(opts, config.WithRegion(ecsRegion))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return scheduler.NewFromConfig(cfg), nil
+}
+
 // ============================================================================
 // ecs start - Start a profile-based task
 // ============================================================================
@@ -122,13 +309,23 @@ var ecsStartCmd = &cobra.Command{
 
 The profile must be configured first using 'frank profile add'.
 This command will:
-  1. Create ALB infrastructure (target group, listener rule) if needed
+  1. Create the profile's ALB endpoints (web, terminal, bash and status
+     target groups and listener rules) if needed
   2. Start an ECS task with the profile's repository configuration
-  3. Register the task in the target group for routing
+  3. Register the task in the endpoints' target groups for routing
+
+Use --dry-run to print the ALB changes as a plan without making them. With
+--read-only, or when your AWS credentials aren't allowed to create the
+task and its endpoints, start prints the plan instead of failing halfway.
+
+Use --resume to pick up where the profile's last task stopped. The task
+reattaches the same EFS worktree without cloning, keeping uncommitted
+changes, and the agent continues its most recent conversation.
 
 The task will be accessible at https://<profile>.frank.digitaldevops.io/claude/`,
-	Args: cobra.ExactArgs(1),
-	RunE: runECSStart,
+	Args:        cobra.ExactArgs(1),
+	Annotations: mutates("ecs:RunTask", "iam:PassRole", "elasticloadbalancing:CreateTargetGroup", "elasticloadbalancing:CreateRule", "elasticloadbalancing:RegisterTargets"),
+	RunE:        runECSStart,
 }
 
 func runECSStart(cmd *cobra.Command, args []string) error {
@@ -138,7 +335,7 @@ func runECSStart(cmd *cobra.Command, args []string) error {
 	// Load profile configuration
 	p, err := profile.GetProfile(profileName)
 	if err != nil {
-		return fmt.Errorf("profile %q not found. Create it with: frank profile add %s --repo <url>", profileName, profileName)
+		return exitcode.Errorf(exitcode.NotFound, "profile %q not found. Create it with: frank profile add %s --repo <url>", profileName, profileName)
 	}
 
 	// Check if task is already running for this profile
@@ -146,216 +343,262 @@ func runECSStart(cmd *cobra.Command, args []string) error {
 	if existingTask != "" {
 		fmt.Printf("Profile %q is already running\n\n", profileName)
 		fmt.Printf("  Task ID: %s\n", color.CyanString(existingTask))
-		fmt.Printf("  URL:     %s\n", color.CyanString(fmt.Sprintf("https://frank.digitaldevops.io/%s/", profileName)))
+		fmt.Printf("  URL:     %s\n", color.CyanString(fmt.Sprintf("https://%s/%s/", frankDomain(), profileName)))
 		fmt.Println()
 		fmt.Printf("Use 'frank ecs stop %s' to stop it first\n", profileName)
 		return nil
 	}
 	_ = existingIP // Will be used later
-
-	fmt.Printf("Starting profile %q...\n", profileName)
+	warnLocalProfileContainers(profileName)
 
 	// Create ALB manager
-	albMgr, err := alb.NewManager(ctx)
+	albMgr, err := alb.NewManager(ctx, withAudit())
 	if err != nil {
 		return fmt.Errorf("failed to create ALB manager: %w", err)
 	}
 
-	// Ensure ALB infrastructure exists
-	fmt.Printf("  Ensuring ALB target group...\n")
-	tgArn, err := albMgr.EnsureTargetGroup(ctx, profileName)
+	hc, err := profileHealthCheck(p)
 	if err != nil {
-		return fmt.Errorf("failed to ensure target group: %w", err)
+		return err
 	}
 
-	fmt.Printf("  Ensuring ALB listener rule...\n")
-	if err := albMgr.EnsureListenerRule(ctx, profileName, tgArn); err != nil {
-		return fmt.Errorf("failed to ensure listener rule: %w", err)
+	if ecsStartDryRun {
+		plan, err := albMgr.PlanStart(ctx, profileName, hc)
+		if err != nil {
+			return fmt.Errorf("failed to plan ALB changes: %w", err)
+		}
+		printALBPlans(plan)
+		fmt.Printf("\nAn ECS task for profile %q would be started. Run without --dry-run to apply.\n", profileName)
+		return nil
 	}
 
-	// Get ECS client
-	client, err := getECSClient(ctx)
-	if err != nil {
-		return err
+	fmt.Printf("Starting profile %q...\n", profileName)
+	if ecsStartRecord {
+		p.Record = true
 	}
 
-	// Get the service to find the task definition and network config
-	descService, err := client.DescribeServices(ctx, &ecs.DescribeServicesInput{
-		Cluster:  aws.String(ecsCluster),
-		Services: []string{defaultService},
+	started, err := startProfileTask(ctx, p, albMgr, ecsStartResume, 0, func(format string, a ...interface{}) {
+		fmt.Printf("  "+format+"\n", a...)
 	})
 	if err != nil {
-		return fmt.Errorf("failed to describe service: %w", err)
-	}
-
-	if len(descService.Services) == 0 {
-		return fmt.Errorf("service %s not found in cluster %s", defaultService, ecsCluster)
+		return err
 	}
 
-	service := descService.Services[0]
-	taskDef := aws.ToString(service.TaskDefinition)
-	networkConfig := service.NetworkConfiguration
+	fmt.Printf("\n%s Profile %q started!\n\n", color.GreenString("✓"), profileName)
+	fmt.Printf("  Task ID:    %s\n", color.CyanString(started.TaskID))
+	fmt.Printf("  Repository: %s\n", p.Repo)
+	fmt.Printf("  Branch:     %s\n", started.Branch)
+	fmt.Printf("  URL:        %s\n", color.CyanString(started.URL))
+	fmt.Println()
 
-	// Build container overrides for profile
-	branch := p.Branch
-	if branch == "" {
-		branch = "main"
+	if ecsStartNoWait || started.IP == "" {
+		fmt.Printf("Note: It may take 1-2 minutes for the task to become healthy\n")
+	} else {
+		timeout := profileBootTimeout(p)
+		fmt.Printf("Waiting up to %s for the task to become healthy...\n", timeout)
+		if err := albMgr.WaitForHealthy(ctx, started.TargetGroupArn, started.IP, alb.TargetPort, timeout); err != nil {
+			fmt.Printf("%s %v\n", color.YellowString("Warning:"), err)
+		} else {
+			fmt.Printf("%s Task is healthy\n", color.GreenString("✓"))
+		}
 	}
+	fmt.Printf("Use 'frank ecs logs %s' to view logs\n", started.TaskID)
 
-	overrides := &types.TaskOverride{
-		ContainerOverrides: []types.ContainerOverride{
-			{
-				Name: aws.String("frank"),
-				Environment: []types.KeyValuePair{
-					{Name: aws.String("CONTAINER_NAME"), Value: aws.String(profileName)},
-					{Name: aws.String("GIT_REPO"), Value: aws.String(p.Repo)},
-					{Name: aws.String("GIT_BRANCH"), Value: aws.String(branch)},
-					{Name: aws.String("URL_PREFIX"), Value: aws.String("/" + profileName)},
-				},
-			},
-		},
-	}
+	return nil
+}
 
-	// Start the task
-	fmt.Printf("  Starting ECS task...\n")
-	runResult, err := client.RunTask(ctx, &ecs.RunTaskInput{
-		Cluster:              aws.String(ecsCluster),
-		TaskDefinition:       aws.String(taskDef),
-		LaunchType:           types.LaunchTypeFargate,
-		NetworkConfiguration: networkConfig,
-		Overrides:            overrides,
-		EnableExecuteCommand: true,
-		Tags: []types.Tag{
-			{Key: aws.String("frank-profile"), Value: aws.String(profileName)},
-		},
-	})
+// startProfileTask starts a task for a profile with its agent, provider and
+// git credentials, and routes the profile's URL to it. Replica 0 is the
+// profile's own task; other replicas run as <profile>-<n> with their own
+// worktree and share the profile's URL. progress reports each step.
+func startProfileTask(ctx context.Context, p *profile.Profile, albMgr *alb.Manager, resume bool, replica int, progress func(format string, a ...interface{})) (*frankecs.StartedTask, error) {
+	// The entrypoint runs the profile's agent, provider and model in the
+	// terminal, loading provider keys from Secrets Manager
+	worker, err := agent.Resolve(p.AgentConfig())
 	if err != nil {
-		return fmt.Errorf("failed to run task: %w", err)
+		return nil, fmt.Errorf("invalid agent settings for profile %s: %w", p.Name, err)
 	}
 
-	if len(runResult.Tasks) == 0 {
-		if len(runResult.Failures) > 0 {
-			return fmt.Errorf("failed to start task: %s - %s",
-				aws.ToString(runResult.Failures[0].Reason),
-				aws.ToString(runResult.Failures[0].Detail))
-		}
-		return fmt.Errorf("failed to start task: no task created")
+	hc, err := profileHealthCheck(p)
+	if err != nil {
+		return nil, err
 	}
 
-	task := runResult.Tasks[0]
-	taskID := extractTaskID(*task.TaskArn)
+	env := map[string]string{}
+	for name, value := range worker.Env {
+		env[name] = value
+	}
+	if secrets := worker.SecretsEnv(); secrets != "" {
+		env["FRANK_SECRETS"] = secrets
+	}
+	// The entrypoint records the agent terminal and uploads it to S3
+	if p.Record {
+		env["FRANK_RECORD"] = "1"
+	}
+	// GitLab/Bitbucket repos need their credential helper, and their token
+	// loaded from Secrets Manager
+	providerEnv, providerSecret := gitProviderTaskEnv(p.Repo)
+	for _, kv := range providerEnv {
+		parts := strings.SplitN(kv, "=", 2)
+		env[parts[0]] = parts[1]
+	}
+	if providerSecret != "" {
+		env["FRANK_SECRETS"] = addSecretSpec(env["FRANK_SECRETS"], providerSecret)
+	}
 
-	// Wait for task to get an IP address
-	fmt.Printf("  Waiting for task IP...\n")
-	taskIP, err := waitForTaskIP(ctx, client, taskID)
+	ecsClient, err := getECSClient(ctx)
 	if err != nil {
-		fmt.Printf("  Warning: Could not get task IP: %v\n", err)
-		fmt.Printf("  You may need to manually register the task in the target group\n")
-	} else {
-		// Register task in target group
-		fmt.Printf("  Registering task in target group...\n")
-		if err := albMgr.RegisterTarget(ctx, tgArn, taskIP, alb.TargetPort); err != nil {
-			fmt.Printf("  Warning: Failed to register target: %v\n", err)
-		}
-	}
+		return nil, err
+	}
+	client := frankecs.New(ecsClient, nil, ecsCluster)
+	client.Owner = taskOwner(ctx)
+	albMgr.Owner = client.Owner
+	started, err := client.StartProfileTask(ctx, albMgr, frankecs.StartSpec{
+		Profile:     p.Name,
+		Repo:        p.Repo,
+		Branch:      p.Branch,
+		Domain:      frankDomain(),
+		Replica:     replica,
+		Resume:      resume,
+		Env:         env,
+		HealthCheck: hc,
+		WarmPool:    execBootstrapper{client: ecsClient},
+	}, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	name := p.Name
+	if replica > 0 {
+		name = fmt.Sprintf("%s-%d", p.Name, replica)
+	}
+	recordState(func(s *state.Store) error {
+		return s.Start(state.Run{
+			Kind:    state.KindTask,
+			Name:    name,
+			ID:      started.TaskID,
+			Profile: p.Name,
+			Repo:    p.Repo,
+			Branch:  started.Branch,
+			Runtime: ecsCluster,
+		})
+	})
+	return started, nil
+}
 
-	fmt.Printf("\n%s Profile %q started!\n\n", color.GreenString("✓"), profileName)
-	fmt.Printf("  Task ID:    %s\n", color.CyanString(taskID))
-	fmt.Printf("  Repository: %s\n", p.Repo)
-	fmt.Printf("  Branch:     %s\n", branch)
-	fmt.Printf("  URL:        %s\n", color.CyanString(fmt.Sprintf("https://frank.digitaldevops.io/%s/", profileName)))
-	fmt.Println()
-	fmt.Printf("Note: It may take 1-2 minutes for the task to become healthy\n")
-	fmt.Printf("Use 'frank ecs logs %s' to view logs\n", taskID)
+// execBootstrapper writes a claimed warm task's profile environment over
+// ECS Exec; the waiting entrypoint picks it up and carries on as a profile
+// task
+type execBootstrapper struct {
+	client *ecs.Client
+}
+
+// bootstrapDone is printed by the bootstrap script once the file is in place
+const bootstrapDone = "__FRANK_BOOTSTRAPPED__"
 
+// envNamePattern matches the variable names the bootstrap file may export
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// secretEnvSuffixes mark variables that hold credentials. The bootstrap
+// command is recorded by CloudTrail, so these belong in FRANK_SECRETS.
+var secretEnvSuffixes = []string{"_TOKEN", "_KEY", "_PASSWORD", "_SECRET", "_CREDENTIALS"}
+
+// checkBootstrapEnv rejects names the bootstrap file can't export safely and
+// values that look like credentials
+func checkBootstrapEnv(name, value string) error {
+	if !envNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid environment variable name %q", name)
+	}
+	for _, suffix := range secretEnvSuffixes {
+		if strings.HasSuffix(strings.ToUpper(name), suffix) {
+			return fmt.Errorf("refusing to send %s over ECS Exec: load it through FRANK_SECRETS", name)
+		}
+	}
+	if redact.String(value) != value {
+		return fmt.Errorf("refusing to send %s over ECS Exec: its value looks like a credential", name)
+	}
 	return nil
 }
 
-// findTaskByProfile finds a running task for a profile by checking tags
-func findTaskByProfile(ctx context.Context, profileName string) (taskID string, taskIP string) {
-	client, err := getECSClient(ctx)
-	if err != nil {
-		return "", ""
+func (b execBootstrapper) Bootstrap(ctx context.Context, taskID string, env map[string]string) error {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		if err := checkBootstrapEnv(name, env[name]); err != nil {
+			return err
+		}
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	// List all tasks
-	listResult, err := client.ListTasks(ctx, &ecs.ListTasksInput{
-		Cluster: aws.String(ecsCluster),
-	})
-	if err != nil || len(listResult.TaskArns) == 0 {
-		return "", ""
+	var file strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&file, "export %s='%s'\n", name, strings.ReplaceAll(env[name], "'", `'\''`))
 	}
 
-	// Describe tasks with tags
-	descResult, err := client.DescribeTasks(ctx, &ecs.DescribeTasksInput{
-		Cluster: aws.String(ecsCluster),
-		Tasks:   listResult.TaskArns,
-		Include: []types.TaskField{types.TaskFieldTags},
-	})
+	// The file travels base64 encoded and is renamed into place, so the
+	// entrypoint never reads half of it. Only its owner can read it, and the
+	// entrypoint removes it once loaded.
+	tmp := frankecs.BootstrapPath + ".tmp"
+	script := fmt.Sprintf(`mkdir -p %s && umask 077 && echo %s | base64 -d > %s && mv %s %s && echo %s`,
+		path.Dir(frankecs.BootstrapPath), base64.StdEncoding.EncodeToString([]byte(file.String())),
+		tmp, tmp, frankecs.BootstrapPath, bootstrapDone)
+
+	var out bytes.Buffer
+	err := runTaskScript(ctx, b.client, taskID, script, &out)
+	var remoteExit *ssmexec.ExitError
+	if errors.As(err, &remoteExit) {
+		return fmt.Errorf("bootstrap script exited with status %d: %s", remoteExit.Code, strings.TrimSpace(out.String()))
+	}
 	if err != nil {
-		return "", ""
+		return err
 	}
-
-	for _, task := range descResult.Tasks {
-		// Check tags for profile match
-		for _, tag := range task.Tags {
-			if aws.ToString(tag.Key) == "frank-profile" && aws.ToString(tag.Value) == profileName {
-				// Found matching task
-				tid := extractTaskID(*task.TaskArn)
-				ip := ""
-				// Get IP from attachments
-				for _, att := range task.Attachments {
-					if aws.ToString(att.Type) == "ElasticNetworkInterface" {
-						for _, detail := range att.Details {
-							if aws.ToString(detail.Name) == "privateIPv4Address" {
-								ip = aws.ToString(detail.Value)
-							}
-						}
-					}
-				}
-				return tid, ip
-			}
-		}
+	if !strings.Contains(out.String(), bootstrapDone) {
+		return fmt.Errorf("bootstrap script failed: %s", strings.TrimSpace(out.String()))
 	}
-
-	return "", ""
+	return nil
 }
 
-// waitForTaskIP waits for a task to get an IP address
-func waitForTaskIP(ctx context.Context, client *ecs.Client, taskID string) (string, error) {
-	for i := 0; i < 30; i++ { // Wait up to 60 seconds
-		descResult, err := client.DescribeTasks(ctx, &ecs.DescribeTasksInput{
-			Cluster: aws.String(ecsCluster),
-			Tasks:   []string{taskID},
-		})
-		if err != nil {
-			return "", err
-		}
-
-		if len(descResult.Tasks) == 0 {
-			return "", fmt.Errorf("task not found")
-		}
-
-		task := descResult.Tasks[0]
+// profileHealthCheck returns the ALB health check for a profile's tasks
+func profileHealthCheck(p *profile.Profile) (alb.HealthCheck, error) {
+	port, path, err := p.HealthCheck()
+	if err != nil {
+		return alb.HealthCheck{}, fmt.Errorf("invalid health_path for profile %s: %w", p.Name, err)
+	}
+	return alb.HealthCheck{Path: path, Port: port}, nil
+}
 
-		// Check for IP in attachments
-		for _, att := range task.Attachments {
-			if aws.ToString(att.Type) == "ElasticNetworkInterface" {
-				for _, detail := range att.Details {
-					if aws.ToString(detail.Name) == "privateIPv4Address" {
-						ip := aws.ToString(detail.Value)
-						if ip != "" {
-							return ip, nil
-						}
-					}
-				}
-			}
-		}
+// profileBootTimeout returns how long 'ecs start' waits for a profile's task
+// to turn healthy
+func profileBootTimeout(p *profile.Profile) time.Duration {
+	if p.BootTimeout > 0 {
+		return p.BootTimeout
+	}
+	return defaultBootTimeout
+}
 
-		time.Sleep(2 * time.Second)
+// prewarmWorkersFor returns how many worktrees to pre-warm for a profile:
+// --workers when given, else the profile's prewarm_workers, else the default
+func prewarmWorkersFor(p *profile.Profile) int {
+	switch {
+	case prewarmWorkers > 0:
+		return prewarmWorkers
+	case p.PrewarmWorkers > 0:
+		return p.PrewarmWorkers
 	}
+	return defaultPrewarmWorkers
+}
 
-	return "", fmt.Errorf("timeout waiting for task IP")
+// findTaskByProfile finds a running task for a profile by checking tags
+func findTaskByProfile(ctx context.Context, profileName string) (taskID string, taskIP string) {
+	client, err := getTaskClient(ctx)
+	if err != nil {
+		return "", ""
+	}
+	task, err := client.FindTaskByProfile(ctx, profileName)
+	if err != nil || task == nil {
+		return "", ""
+	}
+	return task.ID, task.IP
 }
 
 // ============================================================================
@@ -365,56 +608,78 @@ func waitForTaskIP(ctx context.Context, client *ecs.Client, taskID string) (stri
 var ecsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List running Frank tasks on ECS",
-	Long:  `List all Frank tasks running on the ECS cluster.`,
-	RunE:  runECSList,
+	Long: `List all Frank tasks running on the ECS cluster.
+
+OWNER is who started the task, from the ecs.owner setting or their AWS
+identity. --mine and --owner list only the tasks of one owner.
+
+With --watch the table refreshes until interrupted, highlighting tasks
+whose status changed recently.`,
+	RunE: runECSList,
 }
 
 func runECSList(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
+	owner, err := ownerFilter(ctx, ecsListMine, ecsListOwner)
+	if err != nil {
+		return err
+	}
+	if err := ecsListOutput.Validate(); err != nil {
+		return err
+	}
 	client, err := getECSClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	// List tasks in the cluster
-	listResult, err := client.ListTasks(ctx, &ecs.ListTasksInput{
-		Cluster: aws.String(ecsCluster),
-	})
+	if ecsListWatch {
+		if ecsListOutput.Structured() {
+			return fmt.Errorf("--watch only supports table output")
+		}
+		tracker := newStatusTracker()
+		return watchLoop(ecsListInterval, func() error {
+			defer tracker.next()
+			return renderECSTasks(ctx, client, owner, tracker)
+		})
+	}
+
+	return renderECSTasks(ctx, client, owner, nil)
+}
+
+// renderECSTasks lists the cluster's tasks as a table, only those of owner
+// when it's set, highlighting status transitions when a tracker is given
+func renderECSTasks(ctx context.Context, client *ecs.Client, owner string, tracker *statusTracker) error {
+	// List tasks in the cluster, with their tags
+	all, err := frankecs.New(client, nil, ecsCluster).ListTasks(ctx, "")
 	if err != nil {
-		return fmt.Errorf("failed to list tasks: %w", err)
+		return err
 	}
+	tasks := filterTasksByOwner(all, owner)
 
-	if len(listResult.TaskArns) == 0 {
+	if len(tasks) == 0 && !ecsListOutput.Structured() {
+		if owner != "" {
+			fmt.Printf("No Frank tasks owned by %s running\n", owner)
+			return nil
+		}
 		fmt.Println("No Frank tasks running")
 		return nil
 	}
 
-	// Describe tasks to get details including tags
-	descResult, err := client.DescribeTasks(ctx, &ecs.DescribeTasksInput{
-		Cluster: aws.String(ecsCluster),
-		Tasks:   listResult.TaskArns,
-		Include: []types.TaskField{types.TaskFieldTags},
-	})
-	if err != nil {
-		return fmt.Errorf("failed to describe tasks: %w", err)
-	}
-
 	// Display as table
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"PROFILE", "TYPE", "TASK ID", "STATUS", "HEALTH", "STARTED"})
-	table.SetBorder(false)
-	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-	table.SetAlignment(tablewriter.ALIGN_LEFT)
-	table.SetCenterSeparator("")
-	table.SetColumnSeparator("")
-	table.SetRowSeparator("")
-	table.SetHeaderLine(false)
-	table.SetTablePadding("  ")
-	table.SetNoWhiteSpace(true)
-
-	for _, task := range descResult.Tasks {
-		taskID := extractTaskID(*task.TaskArn)
-		status := formatECSStatus(aws.ToString(task.LastStatus))
+	table := render.NewTable(ecsListOutput,
+		render.Column{Header: "PROFILE"},
+		render.Column{Header: "TYPE", Hide: 3},
+		render.Column{Header: "OWNER", MaxWidth: 30, Hide: 2},
+		render.Column{Header: "TASK ID"},
+		render.Column{Header: "STATUS"},
+		render.Column{Header: "HEALTH", Hide: 1},
+		render.Column{Header: "STARTED", Hide: 4},
+	)
+
+	for _, task := range tasks {
+		taskID := frankecs.TaskID(aws.ToString(task.TaskArn))
+		lastStatus := aws.ToString(task.LastStatus)
+		status := tracker.format(taskID, lastStatus, formatECSStatus(lastStatus))
 		health := formatHealthStatus(task.HealthStatus)
 
 		// Extract profile and task type from tags
@@ -429,16 +694,20 @@ func runECSList(cmd *cobra.Command, args []string) error {
 			}
 		}
 
+		ownerName := frankecs.TagValue(task.Tags, frankecs.OwnerTagKey)
+		if ownerName == "" {
+			ownerName = "-"
+		}
+
 		started := "-"
 		if task.StartedAt != nil {
 			started = task.StartedAt.Format("2006-01-02 15:04")
 		}
 
-		table.Append([]string{profileName, taskType, taskID, status, health, started})
+		table.Append(profileName, taskType, ownerName, taskID, status, health, started)
 	}
 
-	table.Render()
-	return nil
+	return table.Render(os.Stdout)
 }
 
 // ============================================================================
@@ -451,76 +720,198 @@ var ecsRunCmd = &cobra.Command{
 	Long: `Run a new standalone Frank task on ECS.
 
 This creates a new task separate from the main service, useful for
-running parallel workers or isolated experiments.
+running parallel workers or isolated experiments without creating a profile.
+
+The task will use the same task definition as the service. It isn't routed
+through the ALB; use 'frank ecs exec' to reach it.
+
+--capacity spot runs the task on Fargate Spot, which is cheaper but can be
+reclaimed at two minutes' notice; auto tries Spot and falls back to
+on-demand when Spot has no capacity. With --wait, frank waits for the task
+to stop and runs it again with the same repo, branch and prompt when Spot
+reclaimed it. In auto mode the rerun goes to on-demand capacity.
 
-The task will use the same task definition as the service.`,
-	RunE: runECSRun,
+Examples:
+  frank ecs run --repo https://github.com/org/repo.git --branch spike
+  frank ecs run --repo https://github.com/org/repo.git --name bench \
+    --task-prompt "Profile the test suite and report the slowest tests"
+  frank ecs run --env FRANK_MODEL=claude-sonnet-4-5
+  frank ecs run --repo https://github.com/org/repo.git --capacity auto --wait \
+    --task-prompt "Fix the flaky integration tests and open a PR"`,
+	Args:        cobra.NoArgs,
+	Annotations: mutates("ecs:RunTask", "iam:PassRole"),
+	RunE:        runECSRun,
 }
 
 func runECSRun(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-	client, err := getECSClient(ctx)
+	if ecsRunBranch != "" && ecsRunRepo == "" {
+		return fmt.Errorf("--branch requires --repo")
+	}
+	capacity, err := frankecs.ParseCapacity(ecsRunCapacity)
 	if err != nil {
-		return err
+		return exitcode.Errorf(exitcode.Usage, "%v", err)
 	}
-
-	// Get the service to find the task definition
-	descService, err := client.DescribeServices(ctx, &ecs.DescribeServicesInput{
-		Cluster:  aws.String(ecsCluster),
-		Services: []string{defaultService},
-	})
+	if cmd.Flags().Changed("max-interruptions") && !ecsRunWait {
+		return exitcode.Errorf(exitcode.Usage, "--max-interruptions requires --wait")
+	}
+	env, err := parseEnvFlags(ecsRunEnv)
 	if err != nil {
-		return fmt.Errorf("failed to describe service: %w", err)
+		return err
 	}
-
-	if len(descService.Services) == 0 {
-		return fmt.Errorf("service %s not found in cluster %s", defaultService, ecsCluster)
+	// GitLab/Bitbucket repos need their credential helper, and their token
+	// loaded from Secrets Manager
+	providerEnv, providerSecret := gitProviderTaskEnv(ecsRunRepo)
+	for _, kv := range providerEnv {
+		parts := strings.SplitN(kv, "=", 2)
+		if _, set := env[parts[0]]; !set {
+			env[parts[0]] = parts[1]
+		}
+	}
+	// A token given with --env wins over the stored one
+	if name := strings.SplitN(providerSecret, "=", 2)[0]; name != "" {
+		if _, set := env[name]; !set {
+			env["FRANK_SECRETS"] = addSecretSpec(env["FRANK_SECRETS"], providerSecret)
+		}
 	}
 
-	service := descService.Services[0]
-	taskDef := aws.ToString(service.TaskDefinition)
-
-	// Get network configuration from the service
-	var networkConfig *types.NetworkConfiguration
-	if service.NetworkConfiguration != nil {
-		networkConfig = service.NetworkConfiguration
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	client, err := getTaskClient(ctx)
+	if err != nil {
+		return err
 	}
+	client.Owner = taskOwner(ctx)
 
 	// Run the task
 	fmt.Printf("Starting new Frank task...\n")
 
-	runResult, err := client.RunTask(ctx, &ecs.RunTaskInput{
-		Cluster:              aws.String(ecsCluster),
-		TaskDefinition:       aws.String(taskDef),
-		LaunchType:           types.LaunchTypeFargate,
-		NetworkConfiguration: networkConfig,
-		EnableExecuteCommand: true,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to run task: %w", err)
+	spec := frankecs.RunSpec{
+		Name:     ecsRunName,
+		Repo:     ecsRunRepo,
+		Branch:   ecsRunBranch,
+		Prompt:   ecsRunPrompt,
+		Env:      env,
+		Capacity: capacity,
 	}
-
-	if len(runResult.Tasks) == 0 {
-		if len(runResult.Failures) > 0 {
-			return fmt.Errorf("failed to start task: %s - %s",
-				aws.ToString(runResult.Failures[0].Reason),
-				aws.ToString(runResult.Failures[0].Detail))
-		}
-		return fmt.Errorf("failed to start task: no task created")
+	task, err := startECSRunTask(ctx, client, spec)
+	if err != nil {
+		return err
 	}
-
-	task := runResult.Tasks[0]
-	taskID := extractTaskID(*task.TaskArn)
+	taskID := frankecs.TaskID(aws.ToString(task.TaskArn))
 
 	fmt.Printf("\n%s Task started successfully!\n\n", color.GreenString("✓"))
 	fmt.Printf("  Task ID:    %s\n", color.CyanString(taskID))
 	fmt.Printf("  Status:     %s\n", aws.ToString(task.LastStatus))
-	fmt.Printf("  Task Def:   %s\n", extractTaskDefName(taskDef))
+	fmt.Printf("  Task Def:   %s\n", extractTaskDefName(aws.ToString(task.TaskDefinitionArn)))
+	fmt.Printf("  Capacity:   %s\n", taskCapacity(*task))
+	if ecsRunName != "" {
+		fmt.Printf("  Name:       %s\n", ecsRunName)
+	}
+	if ecsRunRepo != "" {
+		branch := ecsRunBranch
+		if branch == "" {
+			branch = "main"
+		}
+		fmt.Printf("  Repo:       %s (%s)\n", ecsRunRepo, branch)
+	}
+	if ecsRunPrompt != "" {
+		prompt := strings.Join(strings.Fields(ecsRunPrompt), " ")
+		if len(prompt) > 60 {
+			prompt = prompt[:57] + "..."
+		}
+		fmt.Printf("  Prompt:     %s\n", prompt)
+	}
 	fmt.Println()
 	fmt.Printf("Use 'frank ecs logs %s' to view logs\n", taskID)
 	fmt.Printf("Use 'frank ecs stop %s' to stop the task\n", taskID)
 
-	return nil
+	if !ecsRunWait {
+		return nil
+	}
+	return waitECSRunTask(ctx, client, spec, task)
+}
+
+// startECSRunTask runs a standalone task and records it in the local history
+func startECSRunTask(ctx context.Context, client *frankecs.Client, spec frankecs.RunSpec) (*types.Task, error) {
+	task, err := client.RunTask(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	taskID := frankecs.TaskID(aws.ToString(task.TaskArn))
+
+	name := spec.Name
+	if name == "" {
+		name = taskID
+	}
+	recordState(func(s *state.Store) error {
+		return s.Start(state.Run{
+			Kind:    state.KindTask,
+			Name:    name,
+			ID:      taskID,
+			Repo:    spec.Repo,
+			Branch:  spec.Branch,
+			Image:   extractTaskDefName(aws.ToString(task.TaskDefinitionArn)),
+			Runtime: ecsCluster,
+		})
+	})
+	return task, nil
+}
+
+// waitECSRunTask waits for a standalone task to stop and runs it again each
+// time Fargate Spot reclaims it, up to --max-interruptions times. Auto
+// capacity moves the rerun to on-demand, since Spot just ran out.
+func waitECSRunTask(ctx context.Context, client *frankecs.Client, spec frankecs.RunSpec, task *types.Task) error {
+	interruptions := 0
+	for {
+		taskID := frankecs.TaskID(aws.ToString(task.TaskArn))
+		fmt.Printf("\nWaiting for task %s to finish (Ctrl+C stops waiting, not the task)...\n", taskID)
+		stopped, err := client.WaitForStop(ctx, taskID)
+		if err != nil {
+			if ctx.Err() != nil {
+				fmt.Printf("Stopped waiting; task %s is still running\n", taskID)
+				return nil
+			}
+			return fmt.Errorf("failed to wait for task %s: %w", taskID, err)
+		}
+		reason := aws.ToString(stopped.StoppedReason)
+		recordState(func(s *state.Store) error { return s.Stop(taskID, reason) })
+
+		if !frankecs.SpotInterrupted(*stopped) {
+			switch code := frankecs.ExitCode(*stopped); {
+			case code < 0:
+				return exitcode.Errorf(exitcode.Runtime, "task %s stopped: %s", taskID, reason)
+			case code > 0:
+				return exitcode.Errorf(exitcode.Runtime, "task %s stopped with exit code %d: %s", taskID, code, reason)
+			}
+			fmt.Printf("%s Task %s finished\n", color.GreenString("✓"), taskID)
+			return nil
+		}
+
+		interruptions++
+		if interruptions > ecsRunMaxSpot {
+			return exitcode.Errorf(exitcode.Runtime, "task %s was reclaimed by Fargate Spot %d times; giving up", taskID, interruptions)
+		}
+		if spec.Capacity == frankecs.CapacityAuto {
+			spec.Capacity = frankecs.CapacityOnDemand
+		}
+		fmt.Printf("%s Task %s was reclaimed by Fargate Spot; running it again on %s capacity (%d/%d)\n",
+			color.YellowString("!"), taskID, spec.Capacity, interruptions, ecsRunMaxSpot)
+
+		task, err = startECSRunTask(ctx, client, spec)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Task %s started (%s)\n", color.GreenString("✓"),
+			color.CyanString(frankecs.TaskID(aws.ToString(task.TaskArn))), taskCapacity(*task))
+	}
+}
+
+// taskCapacity names the Fargate capacity a task was placed on
+func taskCapacity(task types.Task) string {
+	if frankecs.OnSpot(task) {
+		return string(frankecs.CapacitySpot)
+	}
+	return string(frankecs.CapacityOnDemand)
 }
 
 // ============================================================================
@@ -528,18 +919,46 @@ func runECSRun(cmd *cobra.Command, args []string) error {
 // ============================================================================
 
 var ecsStopCmd = &cobra.Command{
-	Use:   "stop <profile-or-task-id>",
+	Use:   "stop [profile-or-task-id]",
 	Short: "Stop a running Frank task",
 	Long: `Stop a Frank task by profile name or task ID.
 
 If the argument matches a profile name with a running task, stops that task.
-Otherwise, treats the argument as a task ID.`,
-	Args: cobra.ExactArgs(1),
-	RunE: runECSStop,
+Otherwise, treats the argument as a task ID.
+
+Stopping a profile also removes its ALB listener rules and target groups.
+Use --dry-run to print those changes as a plan without making them.
+
+With --mine or --owner and no argument, stops every task of that owner.
+With an argument, they refuse to stop a task someone else started.
+
+Examples:
+  frank ecs stop enkai              # Stop a profile
+  frank ecs stop enkai --mine       # Only if you started it
+  frank ecs stop --mine             # Stop everything you started`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: mutates("ecs:StopTask", "elasticloadbalancing:DeregisterTargets", "elasticloadbalancing:DeleteRule", "elasticloadbalancing:DeleteTargetGroup"),
+	RunE:        runECSStop,
 }
 
 func runECSStop(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
+	owner, err := ownerFilter(ctx, ecsStopMine, ecsStopOwner)
+	if err != nil {
+		return err
+	}
+
+	client, err := getECSClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		if owner == "" {
+			return fmt.Errorf("give a profile or task ID, or --mine/--owner to stop every task of an owner")
+		}
+		return stopOwnerTasks(ctx, client, owner)
+	}
 	arg := args[0]
 
 	// Check if arg is a profile name with a running task
@@ -551,26 +970,41 @@ func runECSStop(cmd *cobra.Command, args []string) error {
 		taskID = arg
 	}
 
-	client, err := getECSClient(ctx)
-	if err != nil {
-		return err
+	if owner != "" {
+		if err := checkTaskOwner(ctx, client, taskID, owner); err != nil {
+			return err
+		}
+	}
+
+	if ecsStopDryRun {
+		if !isProfile {
+			fmt.Printf("Task %s would be stopped (no ALB changes)\n", taskID)
+			return nil
+		}
+		albMgr, err := alb.NewManager(ctx, withAudit())
+		if err != nil {
+			return fmt.Errorf("failed to create ALB manager: %w", err)
+		}
+		plan, err := albMgr.PlanStop(ctx, arg, taskIP)
+		if err != nil {
+			return fmt.Errorf("failed to plan ALB changes: %w", err)
+		}
+		printALBPlans(plan)
+		fmt.Printf("\nTask %s would be stopped. Run without --dry-run to apply.\n", taskID)
+		return nil
 	}
 
 	if isProfile {
 		fmt.Printf("Stopping profile %q (task %s)...\n", arg, taskID)
-
-		// Deregister from target group
-		albMgr, err := alb.NewManager(ctx)
-		if err == nil && taskIP != "" {
-			tgArn, err := albMgr.GetTargetGroupArn(ctx, arg)
-			if err == nil {
-				_ = albMgr.DeregisterTarget(ctx, tgArn, taskIP, alb.TargetPort)
-			}
+		if err := stopProfileTask(ctx, client, arg, taskID, taskIP, "Stopped by frank ecs stop"); err != nil {
+			return err
 		}
-	} else {
-		fmt.Printf("Stopping task %s...\n", taskID)
+		fmt.Printf("%s Profile %q stopped\n", color.GreenString("✓"), arg)
+		fmt.Printf("Use 'frank ecs start %s --resume' to continue where it left off\n", arg)
+		return nil
 	}
 
+	fmt.Printf("Stopping task %s...\n", taskID)
 	_, err = client.StopTask(ctx, &ecs.StopTaskInput{
 		Cluster: aws.String(ecsCluster),
 		Task:    aws.String(taskID),
@@ -579,23 +1013,160 @@ func runECSStop(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to stop task: %w", err)
 	}
+	recordState(func(s *state.Store) error { return s.Stop(taskID, "Stopped by frank ecs stop") })
 
-	if isProfile {
-		// Clean up ALB resources (listener rules + target groups)
-		albMgr, albErr := alb.NewManager(ctx)
-		if albErr == nil {
-			fmt.Printf("  Cleaning up ALB resources...\n")
-			if err := albMgr.DeleteAllListenerRules(ctx, arg); err != nil {
-				fmt.Printf("  Warning: Failed to delete listener rules: %v\n", err)
-			}
-			if err := albMgr.DeleteAllTargetGroups(ctx, arg); err != nil {
-				fmt.Printf("  Warning: Failed to delete target groups: %v\n", err)
+	fmt.Printf("%s Task %s stopped\n", color.GreenString("✓"), taskID)
+	return nil
+}
+
+// stopOwnerTasks stops every running task of owner. A profile whose own task
+// belongs to owner is stopped with its replicas and ALB resources; other
+// tasks are stopped on their own.
+func stopOwnerTasks(ctx context.Context, client *ecs.Client, owner string) error {
+	all, err := frankecs.New(client, nil, ecsCluster).ListTasks(ctx, types.DesiredStatusRunning)
+	if err != nil {
+		return err
+	}
+	tasks := filterTasksByOwner(all, owner)
+	if len(tasks) == 0 {
+		fmt.Printf("No Frank tasks owned by %s running\n", owner)
+		return nil
+	}
+
+	if ecsStopDryRun {
+		for _, task := range tasks {
+			taskID := frankecs.TaskID(aws.ToString(task.TaskArn))
+			if profileName := frankecs.TagValue(task.Tags, frankecs.ProfileTagKey); profileName != "" {
+				fmt.Printf("Task %s (profile %q) would be stopped\n", taskID, profileName)
+				continue
 			}
+			fmt.Printf("Task %s would be stopped\n", taskID)
+		}
+		fmt.Printf("\nStopping a profile also removes its ALB resources. Run without --dry-run to apply.\n")
+		return nil
+	}
+
+	reason := "Stopped by frank ecs stop"
+	stopped, failed := 0, 0
+
+	// Profiles go first, taking their replicas with them
+	stoppedProfiles := make(map[string]bool)
+	var rest []types.Task
+	for _, task := range tasks {
+		profileName := frankecs.TagValue(task.Tags, frankecs.ProfileTagKey)
+		if profileName == "" || frankecs.TagValue(task.Tags, frankecs.ReplicaTagKey) != "" {
+			rest = append(rest, task)
+			continue
+		}
+		taskID := frankecs.TaskID(aws.ToString(task.TaskArn))
+		fmt.Printf("Stopping profile %q (task %s)...\n", profileName, taskID)
+		if err := stopProfileTask(ctx, client, profileName, taskID, frankecs.PrivateIP(task), reason); err != nil {
+			fmt.Printf("  Warning: %v\n", err)
+			failed++
+			continue
+		}
+		stoppedProfiles[profileName] = true
+		stopped++
+	}
+
+	albMgr, _ := alb.NewManager(ctx, withAudit())
+	for _, task := range rest {
+		profileName := frankecs.TagValue(task.Tags, frankecs.ProfileTagKey)
+		if stoppedProfiles[profileName] {
+			continue
+		}
+		taskID := frankecs.TaskID(aws.ToString(task.TaskArn))
+		fmt.Printf("Stopping task %s...\n", taskID)
+		if err := stopReplicaTask(ctx, client, albMgr, profileName, frankecs.Task{ID: taskID, IP: frankecs.PrivateIP(task)}, reason); err != nil {
+			fmt.Printf("  Warning: %v\n", err)
+			failed++
+			continue
+		}
+		stopped++
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to stop %d task(s) owned by %s", failed, owner)
+	}
+	fmt.Printf("%s Stopped %d task(s) owned by %s\n", color.GreenString("✓"), stopped, owner)
+	return nil
+}
+
+// checkTaskOwner refuses a task that owner didn't start
+func checkTaskOwner(ctx context.Context, client *ecs.Client, taskID, owner string) error {
+	out, err := client.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+		Cluster: aws.String(ecsCluster),
+		Tasks:   []string{taskID},
+		Include: []types.TaskField{types.TaskFieldTags},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe task: %w", err)
+	}
+	if len(out.Tasks) == 0 {
+		return exitcode.Errorf(exitcode.NotFound, "task %s not found", taskID)
+	}
+
+	taskOwner := frankecs.TagValue(out.Tasks[0].Tags, frankecs.OwnerTagKey)
+	if !ownerMatches(taskOwner, owner) {
+		if taskOwner == "" {
+			return fmt.Errorf("task %s has no owner tag, not %s", taskID, owner)
+		}
+		return fmt.Errorf("task %s belongs to %s, not %s", taskID, taskOwner, owner)
+	}
+	return nil
+}
+
+// stopProfileTask deregisters a profile task from its target groups, stops
+// it along with any replicas 'ecs scale' started, and removes the profile's
+// ALB endpoints
+func stopProfileTask(ctx context.Context, client *ecs.Client, profileName, taskID, taskIP, reason string) error {
+	albMgr, albErr := alb.NewManager(ctx, withAudit())
+
+	// Replicas go first so none is left behind the deleted target group
+	replicas, err := frankecs.New(client, nil, ecsCluster).FindTasksByProfile(ctx, profileName)
+	if err != nil {
+		PrintVerbose("Could not list replicas of %s: %v", profileName, err)
+	}
+	for _, replica := range replicas {
+		if replica.ID == taskID {
+			continue
+		}
+		fmt.Printf("  Stopping replica %s...\n", replica.ID)
+		if err := stopReplicaTask(ctx, client, albMgr, profileName, replica, reason); err != nil {
+			fmt.Printf("  Warning: %v\n", err)
 		}
-		fmt.Printf("%s Profile %q stopped\n", color.GreenString("✓"), arg)
-	} else {
-		fmt.Printf("%s Task %s stopped\n", color.GreenString("✓"), taskID)
 	}
+
+	if err := stopReplicaTask(ctx, client, albMgr, profileName, frankecs.Task{ID: taskID, IP: taskIP}, reason); err != nil {
+		return err
+	}
+
+	// Clean up ALB resources (listener rules + target groups)
+	if albErr == nil {
+		fmt.Printf("  Cleaning up ALB resources...\n")
+		if err := albMgr.DeleteEndpoints(ctx, profileName); err != nil {
+			fmt.Printf("  Warning: Failed to delete ALB endpoints: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// stopReplicaTask deregisters one of a profile's tasks from its target
+// groups and stops it, leaving the profile's ALB routing in place. albMgr may be nil.
+func stopReplicaTask(ctx context.Context, client *ecs.Client, albMgr *alb.Manager, profileName string, task frankecs.Task, reason string) error {
+	if albMgr != nil && task.IP != "" {
+		_ = albMgr.DeregisterTargets(ctx, profileName, task.IP)
+	}
+
+	_, err := client.StopTask(ctx, &ecs.StopTaskInput{
+		Cluster: aws.String(ecsCluster),
+		Task:    aws.String(task.ID),
+		Reason:  aws.String(reason),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stop task %s: %w", task.ID, err)
+	}
+	recordState(func(s *state.Store) error { return s.Stop(task.ID, reason) })
 	return nil
 }
 
@@ -604,16 +1175,34 @@ func runECSStop(cmd *cobra.Command, args []string) error {
 // ============================================================================
 
 var ecsScaleCmd = &cobra.Command{
-	Use:   "scale <count>",
-	Short: "Scale the Frank service to a specific number of tasks",
-	Long: `Scale the Frank ECS service to run a specific number of tasks.
+	Use:   "scale [profile] <count>",
+	Short: "Scale the Frank service or a profile to a specific number of tasks",
+	Long: `Scale the Frank ECS service, or a profile, to run a specific number of tasks.
 
-This updates the desired count of the main service, not standalone tasks.`,
-	Args: cobra.ExactArgs(1),
-	RunE: runECSScale,
+With just a count this updates the desired count of the main service, not
+standalone tasks.
+
+With a profile it starts or stops the profile's tasks until count are
+running. Extra tasks are replicas named <profile>-<n>, each in its own
+worktree (a pre-warmed one when available), and all of them are registered
+in the profile's target groups so the ALB balances traffic across them.
+Replicas are stopped highest number first; scaling to 0 stops the profile like
+'frank ecs stop'.
+
+Examples:
+  frank ecs scale 2              # Run two service tasks
+  frank ecs scale enkai 3        # Run enkai plus two replicas
+  frank ecs scale enkai 1        # Back to a single enkai task`,
+	Args:        cobra.RangeArgs(1, 2),
+	Annotations: mutates("ecs:RunTask", "ecs:StopTask", "iam:PassRole", "elasticloadbalancing:RegisterTargets", "elasticloadbalancing:DeregisterTargets"),
+	RunE:        runECSScale,
 }
 
 func runECSScale(cmd *cobra.Command, args []string) error {
+	if len(args) == 2 {
+		return runECSScaleProfile(args[0], args[1])
+	}
+
 	ctx := context.Background()
 	client, err := getECSClient(ctx)
 	if err != nil {
@@ -646,15 +1235,122 @@ func runECSScale(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runECSScaleProfile starts or stops a profile's replicas until count tasks
+// are running behind its target group
+func runECSScaleProfile(profileName, countArg string) error {
+	ctx := context.Background()
+
+	count, err := strconv.Atoi(countArg)
+	if err != nil {
+		return fmt.Errorf("invalid count: %s", countArg)
+	}
+	if count < 0 {
+		return fmt.Errorf("count must be non-negative")
+	}
+
+	p, err := profile.GetProfile(profileName)
+	if err != nil {
+		return exitcode.Errorf(exitcode.NotFound, "profile %q not found. Create it with: frank profile add %s --repo <url>", profileName, profileName)
+	}
+
+	client, err := getECSClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := frankecs.New(client, nil, ecsCluster).FindTasksByProfile(ctx, profileName)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case count == len(tasks):
+		fmt.Printf("Profile %q already has %d running tasks\n", profileName, count)
+		return nil
+
+	case count == 0:
+		fmt.Printf("Stopping profile %q (%d tasks)...\n", profileName, len(tasks))
+		if err := stopProfileTask(ctx, client, profileName, tasks[0].ID, tasks[0].IP, "Scaled to 0 by frank ecs scale"); err != nil {
+			return err
+		}
+		fmt.Printf("%s Profile %q stopped\n", color.GreenString("✓"), profileName)
+		return nil
+
+	case count < len(tasks):
+		albMgr, err := alb.NewManager(ctx, withAudit())
+		if err != nil {
+			return fmt.Errorf("failed to create ALB manager: %w", err)
+		}
+
+		fmt.Printf("Scaling profile %q from %d to %d tasks...\n", profileName, len(tasks), count)
+		for i := len(tasks) - 1; i >= count; i-- {
+			fmt.Printf("  Stopping task %s...\n", tasks[i].ID)
+			if err := stopReplicaTask(ctx, client, albMgr, profileName, tasks[i], "Scaled down by frank ecs scale"); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("%s Profile %q scaled to %d tasks\n", color.GreenString("✓"), profileName, count)
+		return nil
+	}
+
+	albMgr, err := alb.NewManager(ctx, withAudit())
+	if err != nil {
+		return fmt.Errorf("failed to create ALB manager: %w", err)
+	}
+
+	// New tasks take the lowest free replica numbers, so the profile's own
+	// task comes back first if it was stopped
+	used := map[int]bool{}
+	for _, t := range tasks {
+		used[t.Replica] = true
+	}
+
+	fmt.Printf("Scaling profile %q from %d to %d tasks...\n", profileName, len(tasks), count)
+	replica := 0
+	for n := len(tasks); n < count; n++ {
+		for used[replica] {
+			replica++
+		}
+		used[replica] = true
+
+		started, err := startProfileTask(ctx, p, albMgr, false, replica, func(format string, a ...interface{}) {
+			fmt.Printf("  "+format+"\n", a...)
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  %s Started task %s (%s)\n", color.GreenString("✓"), color.CyanString(started.TaskID), frankecs.ReplicaName(profileName, replica))
+	}
+
+	fmt.Printf("\n%s Profile %q scaled to %d tasks\n", color.GreenString("✓"), profileName, count)
+	fmt.Printf("  URL: %s\n", color.CyanString(fmt.Sprintf("https://%s/%s/", frankDomain(), profileName)))
+	fmt.Println("Note: It may take 1-2 minutes for new tasks to become healthy")
+	return nil
+}
+
+
 // ============================================================================
 // ecs logs - Stream task logs
 // ============================================================================
 
 var ecsLogsCmd = &cobra.Command{
-	Use:   "logs [task-id]",
+	Use:   "logs [profile-or-task-id]",
 	Short: "View logs from a Frank task",
-	Long: `View logs from a Frank task. If no task ID is provided, shows logs
-from the most recent task.`,
+	Long: `View logs from a Frank task. If no argument is provided, shows logs
+from the most recent task.
+
+A profile name shows logs from every task tagged with that profile. When
+there is more than one, the streams are interleaved by time with a colored
+task prefix on each line, like 'docker compose logs'.
+
+--export writes the task's entire CloudWatch log stream, not just the tail,
+to a local file or an S3 object. For a profile with several tasks, each
+task's stream is written in turn with the task ID prefixed to every line.
+
+Examples:
+  frank ecs logs dev -f
+  frank ecs logs dev --export dev.log
+  frank ecs logs 3f2a9c1e --export s3://my-bucket/logs/3f2a9c1e.log`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runECSLogs,
 }
@@ -670,11 +1366,21 @@ func runECSLogs(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	client := frankecs.New(ecsClient, logsClient, ecsCluster)
 
-	// Get task ID
-	var taskID string
+	// Resolve task IDs: profile name > task ID > most recent task
+	var taskIDs []string
 	if len(args) > 0 {
-		taskID = args[0]
+		tasks, err := client.FindTasksByProfile(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			taskIDs = append(taskIDs, task.ID)
+		}
+		if len(taskIDs) == 0 {
+			taskIDs = []string{args[0]}
+		}
 	} else {
 		// Find the most recent task
 		listResult, err := ecsClient.ListTasks(ctx, &ecs.ListTasksInput{
@@ -686,69 +1392,116 @@ func runECSLogs(cmd *cobra.Command, args []string) error {
 		if len(listResult.TaskArns) == 0 {
 			return fmt.Errorf("no tasks running")
 		}
-		taskID = extractTaskID(listResult.TaskArns[0])
+		taskIDs = []string{frankecs.TaskID(listResult.TaskArns[0])}
 	}
 
-	// The log stream name format for Fargate is: prefix/container-name/task-id
-	logStreamName := fmt.Sprintf("frank/frank/%s", taskID)
-
-	fmt.Printf("Fetching logs for task %s...\n\n", taskID)
-
-	// Get log events
-	input := &cloudwatchlogs.GetLogEventsInput{
-		LogGroupName:  aws.String(defaultLogGroup),
-		LogStreamName: aws.String(logStreamName),
-		StartFromHead: aws.Bool(false),
-		Limit:         aws.Int32(int32(ecsLogsTail)),
+	if ecsLogsExport != "" {
+		return exportECSLogs(ctx, client, taskIDs, ecsLogsExport)
 	}
 
-	result, err := logsClient.GetLogEvents(ctx, input)
-	if err != nil {
-		// Try with different stream name format (sometimes the container name is different)
-		logStreamName = fmt.Sprintf("frank/%s", taskID)
-		input.LogStreamName = aws.String(logStreamName)
-		result, err = logsClient.GetLogEvents(ctx, input)
-		if err != nil {
-			return fmt.Errorf("failed to get log events: %w", err)
-		}
+	multiplexed := len(taskIDs) > 1
+	if multiplexed {
+		fmt.Printf("Fetching logs for %d tasks of profile %q...\n\n", len(taskIDs), args[0])
+	} else {
+		fmt.Printf("Fetching logs for task %s...\n\n", taskIDs[0])
 	}
 
-	// Print existing events
-	for _, event := range result.Events {
-		timestamp := time.UnixMilli(aws.ToInt64(event.Timestamp)).Format("15:04:05")
-		fmt.Printf("%s %s\n", color.YellowString(timestamp), aws.ToString(event.Message))
+	// Each task gets its own prefix color, like 'docker compose logs'
+	prefixes := map[string]string{}
+	for i, taskID := range taskIDs {
+		short := taskID
+		if len(short) > 8 {
+			short = short[:8]
+		}
+		prefixes[taskID] = taskLogPrefixColors[i%len(taskLogPrefixColors)](short + " |")
 	}
 
-	// If following, continue to poll for new events
-	if ecsLogsFollow {
-		fmt.Println(color.CyanString("\n--- Following logs (Ctrl+C to exit) ---\n"))
-		nextToken := result.NextForwardToken
-
-		for {
-			time.Sleep(2 * time.Second)
-
-			input.NextToken = nextToken
-			input.Limit = aws.Int32(100)
-
-			result, err = logsClient.GetLogEvents(ctx, input)
-			if err != nil {
+	first := true
+	return client.StreamLogs(ctx, taskIDs, frankecs.LogOptions{
+		Tail:   ecsLogsTail,
+		Follow: ecsLogsFollow,
+		OnError: func(taskID string, err error) {
+			if first {
+				PrintError("%s: %v", taskID, err)
+			} else {
 				// Log error but continue trying
-				PrintVerbose("Error fetching logs: %v", err)
-				continue
+				PrintVerbose("Error fetching logs for %s: %v", taskID, err)
 			}
-
-			for _, event := range result.Events {
-				timestamp := time.UnixMilli(aws.ToInt64(event.Timestamp)).Format("15:04:05")
-				fmt.Printf("%s %s\n", color.YellowString(timestamp), aws.ToString(event.Message))
+		},
+	}, func(events []frankecs.LogEvent) error {
+		for _, event := range events {
+			timestamp := color.YellowString(event.Timestamp.Format("15:04:05"))
+			message := redact.String(event.Message)
+			if multiplexed {
+				fmt.Printf("%s %s %s\n", prefixes[event.TaskID], timestamp, message)
+			} else {
+				fmt.Printf("%s %s\n", timestamp, message)
 			}
+		}
+		if first && ecsLogsFollow {
+			fmt.Println(color.CyanString("\n--- Following logs (Ctrl+C to exit) ---\n"))
+		}
+		first = false
+		return nil
+	})
+}
+
+// exportECSLogs pages through the whole log stream of each task and writes
+// it to dest
+func exportECSLogs(ctx context.Context, client *frankecs.Client, taskIDs []string, dest string) error {
+	export, err := openLogExport(dest)
+	if err != nil {
+		return err
+	}
 
-			nextToken = result.NextForwardToken
+	multiplexed := len(taskIDs) > 1
+	exported := 0
+	for _, taskID := range taskIDs {
+		fmt.Printf("Exporting logs for task %s...\n", taskID)
+		err := client.ExportLogs(ctx, taskID, func(events []frankecs.LogEvent) error {
+			for _, event := range events {
+				line := event.Timestamp.UTC().Format(time.RFC3339Nano) + " " + event.Message
+				if multiplexed {
+					line = event.TaskID + " | " + line
+				}
+				if err := export.Printf("%s", line); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			if !multiplexed {
+				export.Abort()
+				return err
+			}
+			PrintError("%s: %v", taskID, err)
+			continue
 		}
+		exported++
+	}
+	if exported == 0 {
+		export.Abort()
+		return fmt.Errorf("no log streams found")
 	}
 
+	if err := export.Close(ctx); err != nil {
+		return err
+	}
+	fmt.Printf("%s Exported %d lines to %s\n", color.GreenString("✓"), export.lines, dest)
 	return nil
 }
 
+// taskLogPrefixColors cycle across tasks in multiplexed output
+var taskLogPrefixColors = []func(format string, a ...interface{}) string{
+	color.CyanString,
+	color.MagentaString,
+	color.GreenString,
+	color.BlueString,
+	color.HiRedString,
+	color.HiYellowString,
+}
+
 // ============================================================================
 // ecs status - Show service status
 // ============================================================================
@@ -825,311 +1578,2168 @@ func runECSStatus(cmd *cobra.Command, args []string) error {
 }
 
 // ============================================================================
-// ecs prewarm - Pre-warm repos and worktrees on EFS
+// ecs pool - Keep idle tasks running for instant profile starts
 // ============================================================================
 
-var ecsPrewarmCmd = &cobra.Command{
-	Use:   "prewarm <profile>",
-	Short: "Pre-warm repos and worktrees on EFS for faster boot",
-	Long: `Pre-warm a profile's repository and worktrees on EFS.
+var ecsPoolCmd = &cobra.Command{
+	Use:   "pool",
+	Short: "Manage the warm pool of idle tasks",
+	Long: `Keep generic tasks running so 'frank ecs start' doesn't wait for Fargate
+to provision a task.
 
-This command SSMs into a running task and creates:
-1. A shared base repository clone
-2. Multiple worktrees for parallel workers
+A warm task runs the service's task definition and waits. 'frank ecs start'
+claims a ready one, hands it the profile's environment over ECS Exec and
+registers it in the profile's ALB endpoints; the entrypoint then clones the
+repository and starts the agent as usual. A replacement warm task is started
+for each one claimed, and a fresh task is run when none is ready.
 
-This dramatically speeds up boot time since containers don't need to clone.
+Warm tasks are tagged frank-task-type=warm and cost as much as any other
+running task.
 
 Examples:
-  frank ecs prewarm enkai              # Pre-warm with 4 workers (default)
-  frank ecs prewarm enkai --workers 8  # Pre-warm with 8 workers`,
-	Args: cobra.ExactArgs(1),
-	RunE: runECSPrewarm,
+  frank ecs pool                 # Show the warm tasks
+  frank ecs pool set-size 2      # Keep two warm tasks
+  frank ecs pool set-size 0      # Empty the pool`,
+	Args: cobra.NoArgs,
+	RunE: runECSPoolStatus,
 }
 
-func runECSPrewarm(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-	profileName := args[0]
-
-	// Load profile configuration
-	p, err := profile.GetProfile(profileName)
-	if err != nil {
-		return fmt.Errorf("profile %q not found. Create it with: frank profile add %s --repo <url>", profileName, profileName)
-	}
+var ecsPoolSetSizeCmd = &cobra.Command{
+	Use:         "set-size <count>",
+	Short:       "Start or stop warm tasks until count are in the pool",
+	Args:        cobra.ExactArgs(1),
+	Annotations: mutates("ecs:UpdateService"),
+	RunE:        runECSPoolSetSize,
+}
 
-	// Find a running task to execute the prewarm script
-	client, err := getECSClient(ctx)
+func runECSPoolStatus(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	client, err := getTaskClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	// List all tasks
-	listResult, err := client.ListTasks(ctx, &ecs.ListTasksInput{
-		Cluster: aws.String(ecsCluster),
-	})
+	warm, err := client.WarmTasks(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to list tasks: %w", err)
+		return err
 	}
-
-	if len(listResult.TaskArns) == 0 {
-		return fmt.Errorf("no running tasks found. Start a task first with 'frank ecs run' or 'frank ecs start <profile>'")
+	if len(warm) == 0 && !ecsPoolOutput.Structured() {
+		fmt.Println("The warm pool is empty.")
+		fmt.Println("Fill it with: frank ecs pool set-size <count>")
+		return nil
 	}
 
-	// Find a running task with execute command enabled
-	descResult, err := client.DescribeTasks(ctx, &ecs.DescribeTasksInput{
-		Cluster: aws.String(ecsCluster),
-		Tasks:   listResult.TaskArns,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to describe tasks: %w", err)
-	}
+	table := render.NewTable(ecsPoolOutput,
+		render.Column{Header: "TASK ID"},
+		render.Column{Header: "STATUS"},
+		render.Column{Header: "READY"},
+	)
 
-	var targetTaskID string
-	for _, task := range descResult.Tasks {
-		if task.EnableExecuteCommand && aws.ToString(task.LastStatus) == "RUNNING" {
-			targetTaskID = extractTaskID(*task.TaskArn)
-			break
+	ready := 0
+	for _, t := range warm {
+		state := color.YellowString("starting")
+		if t.Ready {
+			state = color.GreenString("yes")
+			ready++
 		}
+		table.Append(t.ID, formatECSStatus(t.Status), state)
 	}
-
-	if targetTaskID == "" {
-		return fmt.Errorf("no running task with execute command enabled. Start a task first")
+	if err := table.Render(os.Stdout); err != nil || ecsPoolOutput.Structured() {
+		return err
 	}
+	fmt.Printf("\n%d warm task(s), %d ready\n", len(warm), ready)
+	return nil
+}
 
-	branch := p.Branch
-	if branch == "" {
-		branch = "main"
+func runECSPoolSetSize(cmd *cobra.Command, args []string) error {
+	size, err := strconv.Atoi(args[0])
+	if err != nil || size < 0 {
+		return fmt.Errorf("invalid pool size %q", args[0])
 	}
 
-	// Build the prewarm command
-	prewarmScript := fmt.Sprintf("/usr/local/bin/prewarm.sh %s %s %d %s",
-		profileName, p.Repo, prewarmWorkers, branch)
-
-	awsArgs := []string{
-		"ecs", "execute-command",
-		"--cluster", ecsCluster,
-		"--task", targetTaskID,
-		"--container", "frank",
-		"--interactive",
-		"--command", prewarmScript,
+	ctx := context.Background()
+	client, err := getTaskClient(ctx)
+	if err != nil {
+		return err
 	}
+	client.Owner = taskOwner(ctx)
 
-	if ecsRegion != "" {
-		awsArgs = append([]string{"--region", ecsRegion}, awsArgs...)
+	started, stopped, err := client.ResizePool(ctx, size)
+	for _, id := range started {
+		fmt.Printf("  Started warm task %s\n", id)
 	}
-
-	fmt.Printf("Pre-warming profile %q with %d workers...\n", profileName, prewarmWorkers)
-	fmt.Printf("Using task: %s\n", color.CyanString(targetTaskID))
-	fmt.Printf("Repository: %s\n", p.Repo)
-	fmt.Printf("Branch: %s\n\n", branch)
-
-	// Execute the prewarm script via SSM
-	awsCmd := exec.Command("aws", awsArgs...)
-	awsCmd.Stdin = os.Stdin
-	awsCmd.Stdout = os.Stdout
-	awsCmd.Stderr = os.Stderr
-
-	if err := awsCmd.Run(); err != nil {
-		return fmt.Errorf("failed to execute prewarm: %w", err)
+	for _, id := range stopped {
+		fmt.Printf("  Stopped warm task %s\n", id)
 	}
-
-	fmt.Printf("\n%s Pre-warm complete!\n", color.GreenString("✓"))
-	fmt.Printf("\nTo use pre-warmed worktrees, start containers with worker IDs:\n")
-	for i := 1; i <= prewarmWorkers; i++ {
-		fmt.Printf("  CONTAINER_NAME=%s-%d\n", profileName, i)
+	if err != nil {
+		return fmt.Errorf("failed to resize warm pool: %w", err)
 	}
 
+	fmt.Printf("%s Warm pool set to %d task(s)\n", color.GreenString("✓"), size)
+	if len(started) > 0 {
+		fmt.Println("New tasks take a minute or two to become ready; check with 'frank ecs pool'.")
+	}
 	return nil
 }
 
 // ============================================================================
-// ecs exec - Connect to a task via SSM
+// ecs prewarm - Pre-warm repos and worktrees on EFS
 // ============================================================================
 
-var ecsExecCmd = &cobra.Command{
-	Use:   "exec <profile-or-task-id>",
-	Short: "Connect to a Frank task via SSM Session Manager",
-	Long: `Connect to a running Frank task using ECS Exec (SSM Session Manager).
+var ecsPrewarmCmd = &cobra.Command{
+	Use:   "prewarm [profile]",
+	Short: "Pre-warm repos and worktrees on EFS for faster boot",
+	Long: `Pre-warm a profile's repository and worktrees on EFS.
 
-Requires the AWS CLI and Session Manager plugin to be installed.
-If the argument matches a profile name with a running task, connects to that task.
-Otherwise, treats the argument as a task ID.
+This command SSMs into a running task and creates:
+1. A shared base repository clone
+2. Multiple worktrees for parallel workers
+
+This dramatically speeds up boot time since containers don't need to clone.
+
+With --all every configured profile is pre-warmed, one after another or
+--parallel at a time. Use 'frank ecs prewarm schedule' to pre-warm on a
+schedule instead.
 
 Examples:
-  frank ecs exec enkai              # Connect to profile's task
-  frank ecs exec abc123def456       # Connect to task by ID`,
+  frank ecs prewarm enkai              # Pre-warm with 4 workers (default)
+  frank ecs prewarm enkai --workers 8  # Pre-warm with 8 workers
+  frank ecs prewarm --all              # Pre-warm every profile in turn
+  frank ecs prewarm --all --parallel 3 # Pre-warm three profiles at a time`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if prewarmAll {
+			if len(args) > 0 {
+				return fmt.Errorf("--all doesn't take a profile argument")
+			}
+			return nil
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Annotations: mutates("ecs:ExecuteCommand"),
+	RunE:        runECSPrewarm,
+}
+
+func runECSPrewarm(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if prewarmParallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1")
+	}
+
+	// Load profile configuration
+	profiles, err := prewarmProfiles(args)
+	if err != nil {
+		return err
+	}
+
+	// Find a running task to execute the prewarm script
+	client, err := getECSClient(ctx)
+	if err != nil {
+		return err
+	}
+	targetTaskID, err := findExecTask(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Using task: %s\n", color.CyanString(targetTaskID))
+
+	if !prewarmAll {
+		p := profiles[0]
+		if err := prewarmProfile(ctx, client, targetTaskID, p, os.Stdout, os.Stderr); err != nil {
+			return err
+		}
+
+		fmt.Printf("\n%s Pre-warm complete!\n", color.GreenString("✓"))
+		fmt.Printf("\nTo use pre-warmed worktrees, start containers with worker IDs:\n")
+		for i := 1; i <= prewarmWorkersFor(p); i++ {
+			fmt.Printf("  CONTAINER_NAME=%s-%d\n", p.Name, i)
+		}
+		return nil
+	}
+
+	fmt.Printf("Pre-warming %d profiles (%d at a time)...\n\n", len(profiles), prewarmParallel)
+
+	// Parallel runs buffer each profile's output so it prints as one block
+	errs := make([]error, len(profiles))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, prewarmParallel)
+	for i, p := range profiles {
+		wg.Add(1)
+		go func(i int, p *profile.Profile) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if prewarmParallel == 1 {
+				fmt.Println(color.CyanString("=== %s ===", p.Name))
+				errs[i] = prewarmProfile(ctx, client, targetTaskID, p, os.Stdout, os.Stderr)
+				fmt.Println()
+				return
+			}
+
+			var out bytes.Buffer
+			errs[i] = prewarmProfile(ctx, client, targetTaskID, p, &out, &out)
+			mu.Lock()
+			defer mu.Unlock()
+			fmt.Println(color.CyanString("=== %s ===", p.Name))
+			fmt.Print(out.String())
+			fmt.Println()
+		}(i, p)
+	}
+	wg.Wait()
+
+	failed := 0
+	for i, p := range profiles {
+		if errs[i] != nil {
+			failed++
+			fmt.Printf("%s %s: %v\n", color.RedString("✗"), p.Name, errs[i])
+		} else {
+			fmt.Printf("%s %s\n", color.GreenString("✓"), p.Name)
+		}
+	}
+	if failed > 0 {
+		err := fmt.Errorf("%d of %d profiles failed to pre-warm", failed, len(profiles))
+		for _, profileErr := range errs {
+			var remoteExit *ssmexec.ExitError
+			if errors.As(profileErr, &remoteExit) {
+				return exitcode.Wrap(exitcode.Remote, err)
+			}
+		}
+		return err
+	}
+
+	fmt.Printf("\n%s Pre-warm complete!\n", color.GreenString("✓"))
+	return nil
+}
+
+// prewarmProfiles loads the named profile, or every profile with --all,
+// sorted by name. Each profile's Name is set from its key.
+func prewarmProfiles(args []string) ([]*profile.Profile, error) {
+	if len(args) == 1 {
+		p, err := profile.GetProfile(args[0])
+		if err != nil {
+			return nil, exitcode.Errorf(exitcode.NotFound, "profile %q not found. Create it with: frank profile add %s --repo <url>", args[0], args[0])
+		}
+		named := *p
+		named.Name = args[0]
+		return []*profile.Profile{&named}, nil
+	}
+
+	config, err := profile.LoadProfiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load profiles: %w", err)
+	}
+
+	var profiles []*profile.Profile
+	for _, name := range args {
+		p, ok := config.Profiles[name]
+		if !ok {
+			return nil, exitcode.Errorf(exitcode.NotFound, "profile %q not found", name)
+		}
+		named := *p
+		named.Name = name
+		profiles = append(profiles, &named)
+	}
+	if len(args) == 0 {
+		for name, p := range config.Profiles {
+			named := *p
+			named.Name = name
+			profiles = append(profiles, &named)
+		}
+	}
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("no profiles configured. Create one with: frank profile add <name> --repo <url>")
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles, nil
+}
+
+// findExecTask returns a running task with ECS Exec enabled
+func findExecTask(ctx context.Context, client *ecs.Client) (string, error) {
+	// List all tasks
+	tasks, err := frankecs.New(client, nil, ecsCluster).ListTasks(ctx, "")
+	if err != nil {
+		return "", err
+	}
+
+	if len(tasks) == 0 {
+		return "", exitcode.Errorf(exitcode.NotFound, "no running tasks found. Start a task first with 'frank ecs run' or 'frank ecs start <profile>'")
+	}
+
+	// Find a running task with execute command enabled
+	for _, task := range tasks {
+		if task.EnableExecuteCommand && aws.ToString(task.LastStatus) == "RUNNING" {
+			return frankecs.TaskID(aws.ToString(task.TaskArn)), nil
+		}
+	}
+
+	return "", fmt.Errorf("no running task with execute command enabled. Start a task first")
+}
+
+// prewarmProfile runs prewarm.sh for a profile in the task over ECS Exec
+func prewarmProfile(ctx context.Context, client *ecs.Client, taskID string, p *profile.Profile, stdout, stderr io.Writer) error {
+	branch := p.Branch
+	if branch == "" {
+		branch = "main"
+	}
+
+	// Build the prewarm command
+	workers := prewarmWorkersFor(p)
+	prewarmScript := fmt.Sprintf("/usr/local/bin/prewarm.sh %s %s %d %s",
+		p.Name, p.Repo, workers, branch)
+
+	fmt.Fprintf(stdout, "Pre-warming profile %q with %d workers...\n", p.Name, workers)
+	fmt.Fprintf(stdout, "Repository: %s\n", p.Repo)
+	fmt.Fprintf(stdout, "Branch: %s\n\n", branch)
+
+	// Execute the prewarm script via an ECS Exec session
+	session, err := ssmexec.Start(ctx, client, ssmexec.ExecInput{
+		Cluster:   ecsCluster,
+		Task:      taskID,
+		Container: defaultContainer,
+		Command:   prewarmScript,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute prewarm: %w", err)
+	}
+	defer session.Close()
+
+	if err := session.Run(ctx, nil, stdout, stderr); err != nil {
+		return fmt.Errorf("failed to execute prewarm: %w", err)
+	}
+	return nil
+}
+
+// ============================================================================
+// ecs prewarm schedule - Pre-warm on an EventBridge Scheduler schedule
+// ============================================================================
+
+const (
+	// prewarmScheduleName is the EventBridge Scheduler schedule for prewarms
+	prewarmScheduleName = "frank-prewarm"
+	// schedulerRoleName is the role, created by the CDK stack, that the
+	// schedule assumes to run the prewarm task
+	schedulerRoleName = "frank-scheduler"
+	// prewarmStartedBy marks tasks started by the schedule
+	prewarmStartedBy = "frank-prewarm"
+	// runTaskTargetArn is the Scheduler universal target for ecs:RunTask
+	runTaskTargetArn = "arn:aws:scheduler:::aws-sdk:ecs:runTask"
+)
+
+var ecsPrewarmScheduleCmd = &cobra.Command{
+	Use:   "schedule [profile...]",
+	Short: "Pre-warm profiles on a schedule",
+	Long: `Create or update an EventBridge Scheduler schedule that runs a one-off
+prewarm task, so worktrees are warm before the workday.
+
+The task runs prewarm.sh for each profile and exits. Without profile
+arguments every configured profile is included. Repos and branches are copied
+into the schedule, so run the command again after changing profiles.
+
+--cron takes the usual five fields (minute hour day-of-month month
+day-of-week, Sunday is 0) and is evaluated in --timezone. A full cron(),
+rate() or at() Scheduler expression is also accepted.
+
+The schedule runs as the frank-scheduler IAM role from the CDK stack.
+
+Examples:
+  frank ecs prewarm schedule --cron "0 6 * * 1-5"
+  frank ecs prewarm schedule --cron "30 7 * * 1-5" --timezone Europe/London enkai
+  frank ecs prewarm schedule            # Show the current schedule
+  frank ecs prewarm schedule --delete   # Remove the schedule`,
+	Annotations: mutates("scheduler:CreateSchedule", "scheduler:UpdateSchedule", "scheduler:DeleteSchedule", "iam:PassRole"),
+	RunE:        runECSPrewarmSchedule,
+}
+
+func runECSPrewarmSchedule(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	schedClient, err := getSchedulerClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if prewarmDelete {
+		_, err := schedClient.DeleteSchedule(ctx, &scheduler.DeleteScheduleInput{
+			Name: aws.String(prewarmScheduleName),
+		})
+		var notFound *schedulertypes.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			fmt.Println("No prewarm schedule to delete")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to delete schedule: %w", err)
+		}
+		fmt.Printf("%s Prewarm schedule deleted\n", color.GreenString("✓"))
+		return nil
+	}
+
+	existing, err := schedClient.GetSchedule(ctx, &scheduler.GetScheduleInput{
+		Name: aws.String(prewarmScheduleName),
+	})
+	var notFound *schedulertypes.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		existing = nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get schedule: %w", err)
+	}
+
+	if prewarmCron == "" {
+		if existing == nil {
+			fmt.Println("No prewarm schedule. Create one with: frank ecs prewarm schedule --cron \"0 6 * * 1-5\"")
+			return nil
+		}
+		fmt.Printf("Schedule:    %s\n", aws.ToString(existing.ScheduleExpression))
+		fmt.Printf("Timezone:    %s\n", aws.ToString(existing.ScheduleExpressionTimezone))
+		fmt.Printf("State:       %s\n", existing.State)
+		fmt.Printf("Description: %s\n", aws.ToString(existing.Description))
+		return nil
+	}
+
+	expression, err := scheduleExpression(prewarmCron)
+	if err != nil {
+		return err
+	}
+	if _, err := time.LoadLocation(prewarmTimezone); err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", prewarmTimezone, err)
+	}
+
+	profiles, err := prewarmProfiles(args)
+	if err != nil {
+		return err
+	}
+
+	// The prewarm task runs the service's task definition family, so the
+	// schedule picks up new revisions
+	client, err := getECSClient(ctx)
+	if err != nil {
+		return err
+	}
+	descService, err := client.DescribeServices(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(ecsCluster),
+		Services: []string{defaultService},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe service: %w", err)
+	}
+	if len(descService.Services) == 0 {
+		return fmt.Errorf("service %s not found in cluster %s", defaultService, ecsCluster)
+	}
+	service := descService.Services[0]
+
+	input, err := prewarmRunTaskInput(service, profiles, taskOwner(ctx))
+	if err != nil {
+		return err
+	}
+
+	roleArn := prewarmRoleArn
+	if roleArn == "" {
+		roleArn, err = stackRoleArn(aws.ToString(service.ClusterArn), schedulerRoleName)
+		if err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, len(profiles))
+	for i, p := range profiles {
+		names[i] = p.Name
+	}
+	description := fmt.Sprintf("Pre-warm frank profiles: %s", strings.Join(names, ", "))
+
+	target := &schedulertypes.Target{
+		Arn:     aws.String(runTaskTargetArn),
+		RoleArn: aws.String(roleArn),
+		Input:   aws.String(input),
+	}
+	window := &schedulertypes.FlexibleTimeWindow{Mode: schedulertypes.FlexibleTimeWindowModeOff}
+
+	PrintVerbose("Schedule input: %s", input)
+
+	if existing == nil {
+		_, err = schedClient.CreateSchedule(ctx, &scheduler.CreateScheduleInput{
+			Name:                       aws.String(prewarmScheduleName),
+			ScheduleExpression:         aws.String(expression),
+			ScheduleExpressionTimezone: aws.String(prewarmTimezone),
+			Description:                aws.String(description),
+			FlexibleTimeWindow:         window,
+			State:                      schedulertypes.ScheduleStateEnabled,
+			Target:                     target,
+		})
+	} else {
+		_, err = schedClient.UpdateSchedule(ctx, &scheduler.UpdateScheduleInput{
+			Name:                       aws.String(prewarmScheduleName),
+			ScheduleExpression:         aws.String(expression),
+			ScheduleExpressionTimezone: aws.String(prewarmTimezone),
+			Description:                aws.String(description),
+			FlexibleTimeWindow:         window,
+			State:                      schedulertypes.ScheduleStateEnabled,
+			Target:                     target,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save schedule: %w", err)
+	}
+
+	verb := "created"
+	if existing != nil {
+		verb = "updated"
+	}
+	fmt.Printf("%s Prewarm schedule %s: %s (%s)\n", color.GreenString("✓"), verb, expression, prewarmTimezone)
+	fmt.Printf("  Profiles: %s\n", strings.Join(names, ", "))
+	workers := make([]string, len(profiles))
+	for i, p := range profiles {
+		workers[i] = fmt.Sprintf("%s=%d", p.Name, prewarmWorkersFor(p))
+	}
+	fmt.Printf("  Workers:  %s\n", strings.Join(workers, ", "))
+	return nil
+}
+
+// prewarmRunTaskInput builds the ecs:RunTask request the schedule sends. The
+// entrypoint sees PREWARM_SPECS, pre-warms each "name repo workers branch"
+// entry and exits instead of starting the terminals. A non-empty owner is
+// tagged on the tasks.
+func prewarmRunTaskInput(service types.Service, profiles []*profile.Profile, owner string) (string, error) {
+	specs := make([]string, len(profiles))
+	for i, p := range profiles {
+		branch := p.Branch
+		if branch == "" {
+			branch = "main"
+		}
+		specs[i] = fmt.Sprintf("%s %s %d %s", p.Name, p.Repo, prewarmWorkersFor(p), branch)
+	}
+
+	// Universal targets take the API request with its PascalCase member names
+	input := map[string]interface{}{
+		"Cluster":        ecsCluster,
+		"TaskDefinition": taskDefinitionFamily(aws.ToString(service.TaskDefinition)),
+		"LaunchType":     string(types.LaunchTypeFargate),
+		"StartedBy":      prewarmStartedBy,
+		"Overrides": map[string]interface{}{
+			"ContainerOverrides": []map[string]interface{}{{
+				"Name": defaultContainer,
+				"Environment": []map[string]string{
+					{"Name": "CONTAINER_NAME", "Value": prewarmStartedBy},
+					{"Name": "PREWARM_SPECS", "Value": strings.Join(specs, ";")},
+				},
+			}},
+		},
+	}
+	if owner != "" {
+		input["Tags"] = []map[string]string{{"Key": frankecs.OwnerTagKey, "Value": owner}}
+	}
+	if vpc := service.NetworkConfiguration; vpc != nil && vpc.AwsvpcConfiguration != nil {
+		input["NetworkConfiguration"] = map[string]interface{}{
+			"AwsvpcConfiguration": map[string]interface{}{
+				"Subnets":        vpc.AwsvpcConfiguration.Subnets,
+				"SecurityGroups": vpc.AwsvpcConfiguration.SecurityGroups,
+				"AssignPublicIp": string(vpc.AwsvpcConfiguration.AssignPublicIp),
+			},
+		}
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode schedule input: %w", err)
+	}
+	return string(data), nil
+}
+
+// taskDefinitionFamily strips the revision from a task definition ARN
+// (arn:aws:ecs:...:task-definition/frank:12 -> frank)
+func taskDefinitionFamily(arn string) string {
+	family := arn[strings.LastIndex(arn, "/")+1:]
+	if i := strings.LastIndex(family, ":"); i >= 0 {
+		family = family[:i]
+	}
+	return family
+}
+
+// stackRoleArn builds the ARN of a role the CDK stack creates, in the
+// cluster's account
+func stackRoleArn(clusterArn, roleName string) (string, error) {
+	// arn:<partition>:ecs:<region>:<account>:cluster/<name>
+	parts := strings.Split(clusterArn, ":")
+	if len(parts) < 6 || parts[4] == "" {
+		return "", fmt.Errorf("can't determine the AWS account from cluster %q (use --role-arn)", clusterArn)
+	}
+	return fmt.Sprintf("arn:%s:iam::%s:role/%s", parts[1], parts[4], roleName), nil
+}
+
+// scheduleExpression converts a five-field cron spec to a Scheduler cron()
+// expression. Scheduler numbers weekdays 1-7 from Sunday, adds a year field
+// and needs '?' in one of the day fields. Scheduler expressions pass through.
+func scheduleExpression(spec string) (string, error) {
+	spec = strings.TrimSpace(spec)
+	for _, prefix := range []string{"cron(", "rate(", "at("} {
+		if strings.HasPrefix(spec, prefix) {
+			return spec, nil
+		}
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return "", fmt.Errorf("invalid cron %q: want 5 fields (minute hour day-of-month month day-of-week)", spec)
+	}
+	minute, hour, dom, month, dow := fields[0], fields[1], fields[2], fields[3], fields[4]
+
+	switch {
+	case dow == "*" || dow == "?":
+		dow = "?"
+		if dom == "?" {
+			dom = "*"
+		}
+	case dom == "*" || dom == "?":
+		dom = "?"
+		var err error
+		if dow, err = schedulerWeekdays(dow); err != nil {
+			return "", fmt.Errorf("invalid cron %q: %w", spec, err)
+		}
+	default:
+		return "", fmt.Errorf("invalid cron %q: can't restrict both day-of-month and day-of-week", spec)
+	}
+
+	return fmt.Sprintf("cron(%s %s %s %s %s *)", minute, hour, dom, month, dow), nil
+}
+
+// schedulerWeekdays renumbers a cron day-of-week field from 0-7 (Sunday is 0
+// or 7) to Scheduler's 1-7. Day names pass through.
+func schedulerWeekdays(field string) (string, error) {
+	parts := strings.Split(field, ",")
+	var extra []string
+	for i, part := range parts {
+		base, step, hasStep := strings.Cut(part, "/")
+		base, nth, hasNth := strings.Cut(base, "#")
+
+		if lo, hi, isRange := strings.Cut(base, "-"); isRange {
+			loDay, err := shiftWeekday(lo)
+			if err != nil {
+				return "", err
+			}
+			// A range ending on Sunday (7) would wrap to 1; split it
+			if hi == "7" && !hasStep {
+				base = loDay + "-7"
+				extra = append(extra, "1")
+			} else {
+				hiDay, err := shiftWeekday(hi)
+				if err != nil {
+					return "", err
+				}
+				base = loDay + "-" + hiDay
+			}
+		} else if base != "*" {
+			day, err := shiftWeekday(base)
+			if err != nil {
+				return "", err
+			}
+			base = day
+		}
+
+		if hasNth {
+			base += "#" + nth
+		}
+		if hasStep {
+			base += "/" + step
+		}
+		parts[i] = base
+	}
+	return strings.Join(append(parts, extra...), ","), nil
+}
+
+// shiftWeekday converts one numeric cron weekday to Scheduler numbering
+func shiftWeekday(day string) (string, error) {
+	n, err := strconv.Atoi(day)
+	if err != nil {
+		return day, nil
+	}
+	if n < 0 || n > 7 {
+		return "", fmt.Errorf("day of week %d out of range 0-7", n)
+	}
+	return strconv.Itoa(n%7 + 1), nil
+}
+
+// ============================================================================
+// ecs exec - Connect to a task via SSM
+// ============================================================================
+
+var ecsExecCmd = &cobra.Command{
+	Use:   "exec <profile-or-task-id>",
+	Short: "Connect to a Frank task via SSM Session Manager",
+	Long: `Connect to a running Frank task using ECS Exec (SSM Session Manager).
+
+If the argument matches a profile name with a running task, connects to that task.
+Otherwise, treats the argument as a task ID.
+
+Examples:
+  frank ecs exec enkai              # Connect to profile's task
+  frank ecs exec abc123def456       # Connect to task by ID`,
 	Args: cobra.ExactArgs(1),
 	RunE: runECSExec,
 }
 
-func runECSExec(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-	arg := args[0]
-
-	// Check if arg is a profile name with a running task
-	taskID, _ := findTaskByProfile(ctx, arg)
-	if taskID == "" {
-		// Treat as task ID
-		taskID = arg
+func runECSExec(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getECSClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	taskID, _, err := resolveExecTask(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Connecting to task %s...\n\n", color.CyanString(taskID))
+
+	session, err := ssmexec.Start(ctx, client, ssmexec.ExecInput{
+		Cluster:   ecsCluster,
+		Task:      taskID,
+		Container: defaultContainer,
+		Command:   "/bin/bash",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start exec session: %w\n\nMake sure the task role allows ssmmessages:* and your credentials allow ecs:ExecuteCommand", err)
+	}
+	defer session.Close()
+	PrintVerbose("Session ID: %s", session.ID)
+
+	if err := session.RunInteractive(ctx); err != nil {
+		// The shell's exit status becomes frank's, as with ssh
+		var remoteExit *ssmexec.ExitError
+		if errors.As(err, &remoteExit) {
+			return remoteExit
+		}
+		return fmt.Errorf("exec session failed: %w", err)
+	}
+
+	return nil
+}
+
+// resolveExecTask returns the running task for a profile name or task ID and
+// checks that it accepts ECS Exec. profileName is empty when arg is a task ID.
+func resolveExecTask(ctx context.Context, client *ecs.Client, arg string) (taskID, profileName string, err error) {
+	// Check if arg is a profile name with a running task
+	taskID, _ = findTaskByProfile(ctx, arg)
+	if taskID != "" {
+		profileName = arg
+	} else {
+		// Treat as task ID
+		taskID = arg
+	}
+
+	// Verify task exists and has execute command enabled
+	descResult, err := client.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+		Cluster: aws.String(ecsCluster),
+		Tasks:   []string{taskID},
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to describe task: %w", err)
+	}
+
+	if len(descResult.Tasks) == 0 {
+		return "", "", exitcode.Errorf(exitcode.NotFound, "task %s not found", taskID)
+	}
+
+	task := descResult.Tasks[0]
+	if !task.EnableExecuteCommand {
+		return "", "", fmt.Errorf("task %s does not have execute command enabled. Start a new task with 'frank ecs start'", taskID)
+	}
+
+	if aws.ToString(task.LastStatus) != "RUNNING" {
+		return "", "", fmt.Errorf("task %s is not running (status: %s)", taskID, aws.ToString(task.LastStatus))
+	}
+
+	return taskID, profileName, nil
+}
+
+// ============================================================================
+// ecs tunnel - Forward local ports to a task via SSM
+// ============================================================================
+
+var ecsTunnelCmd = &cobra.Command{
+	Use:   "tunnel <profile-or-task-id>",
+	Short: "Forward local ports to a Frank task via SSM Session Manager",
+	Long: `Forward local ports to a running Frank task's container through SSM
+Session Manager, so local tools can reach ports the ALB doesn't route, such
+as the status server, without opening them to the network.
+
+Ports are given as LOCAL:REMOTE, or a single port to use the same number on
+both ends; the default forwards the status server on 7683. Local ports listen
+on 127.0.0.1 only. Each connection opens its own SSM session. The tunnel runs
+until interrupted.
+
+Requires ssm:StartSession on the task and the AWS-StartPortForwardingSession
+document; the task role needs the same ssmmessages permissions as ecs exec.
+
+Examples:
+  frank ecs tunnel enkai                      # localhost:7683 -> status server
+  frank ecs tunnel enkai -p 9000:7681         # localhost:9000 -> agent terminal
+  frank ecs tunnel enkai -p 7681 -p 7683      # Several ports at once`,
+	Args: cobra.ExactArgs(1),
+	RunE: runECSTunnel,
+}
+
+// tunnelPort maps a local port to a port in the task's container
+type tunnelPort struct {
+	Local  int
+	Remote int
+}
+
+func runECSTunnel(cmd *cobra.Command, args []string) error {
+	ports, err := parseTunnelPorts(ecsTunnelPorts)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client, err := getECSClient(ctx)
+	if err != nil {
+		return err
+	}
+	ssmClient, err := getSSMClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	taskID, _, err := resolveExecTask(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+	target, err := taskSSMTarget(ctx, client, taskID)
+	if err != nil {
+		return err
+	}
+	PrintVerbose("SSM target: %s", target)
+
+	var listeners []net.Listener
+	defer func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}()
+	for _, p := range ports {
+		l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", p.Local))
+		if err != nil {
+			return fmt.Errorf("failed to listen on port %d: %w", p.Local, err)
+		}
+		listeners = append(listeners, l)
+	}
+
+	fmt.Printf("Tunneling to task %s\n", color.CyanString(taskID))
+	for _, p := range ports {
+		fmt.Printf("  localhost:%d -> %d\n", p.Local, p.Remote)
+	}
+	fmt.Println("\nPress Ctrl+C to stop")
+
+	var wg sync.WaitGroup
+	for i, l := range listeners {
+		wg.Add(1)
+		go func(l net.Listener, p tunnelPort) {
+			defer wg.Done()
+			serveTunnel(ctx, ssmClient, l, target, p)
+		}(l, ports[i])
+	}
+
+	<-ctx.Done()
+	for _, l := range listeners {
+		l.Close()
+	}
+	wg.Wait()
+	fmt.Println("\nTunnel closed")
+	return nil
+}
+
+// serveTunnel forwards each connection accepted on l through its own SSM
+// session until l is closed
+func serveTunnel(ctx context.Context, client *ssm.Client, l net.Listener, target string, p tunnelPort) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			session, err := ssmexec.StartPortForward(ctx, client, ssmexec.PortForwardInput{Target: target, Port: p.Remote})
+			if err != nil {
+				PrintError("localhost:%d: %v", p.Local, err)
+				conn.Close()
+				return
+			}
+			PrintVerbose("localhost:%d: session %s", p.Local, session.ID)
+
+			if err := session.Forward(ctx, conn); err != nil {
+				PrintVerbose("localhost:%d: %v", p.Local, err)
+			}
+			session.Close()
+
+			// Free the session now rather than when it times out
+			client.TerminateSession(context.Background(), &ssm.TerminateSessionInput{SessionId: aws.String(session.ID)})
+		}()
+	}
+}
+
+// parseTunnelPorts parses LOCAL:REMOTE and PORT specs
+func parseTunnelPorts(specs []string) ([]tunnelPort, error) {
+	var ports []tunnelPort
+	for _, spec := range specs {
+		localStr, remoteStr, found := strings.Cut(spec, ":")
+		if !found {
+			remoteStr = localStr
+		}
+		local, err := strconv.Atoi(localStr)
+		if err != nil || local < 1 || local > 65535 {
+			return nil, fmt.Errorf("invalid --port %q: expected LOCAL:REMOTE or PORT", spec)
+		}
+		remote, err := strconv.Atoi(remoteStr)
+		if err != nil || remote < 1 || remote > 65535 {
+			return nil, fmt.Errorf("invalid --port %q: expected LOCAL:REMOTE or PORT", spec)
+		}
+		ports = append(ports, tunnelPort{Local: local, Remote: remote})
+	}
+	return ports, nil
+}
+
+// taskSSMTarget returns the SSM target for the frank container of a task
+func taskSSMTarget(ctx context.Context, client *ecs.Client, taskID string) (string, error) {
+	result, err := client.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+		Cluster: aws.String(ecsCluster),
+		Tasks:   []string{taskID},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe task: %w", err)
+	}
+	if len(result.Tasks) == 0 {
+		return "", exitcode.Errorf(exitcode.NotFound, "task %s not found", taskID)
+	}
+
+	for _, c := range result.Tasks[0].Containers {
+		if aws.ToString(c.Name) == defaultContainer && aws.ToString(c.RuntimeId) != "" {
+			return ssmexec.ECSTarget(ecsCluster, frankecs.TaskID(aws.ToString(result.Tasks[0].TaskArn)), aws.ToString(c.RuntimeId)), nil
+		}
+	}
+	return "", fmt.Errorf("task %s has no running %s container", taskID, defaultContainer)
+}
+
+// ============================================================================
+// ecs cp / ecs ls - Copy files out of and browse a task's workspace
+// ============================================================================
+
+// Markers around the payload a task command prints, so it can be told apart
+// from anything else the terminal session writes
+const (
+	execPayloadBegin = "__FRANK_BEGIN__"
+	execPayloadEnd   = "__FRANK_END__"
+	execPayloadError = "__FRANK_ERROR__"
+)
+
+var ecsCpCmd = &cobra.Command{
+	Use:   "cp <profile-or-task-id>:<path> <local-path>",
+	Short: "Copy a file or directory out of a task",
+	Long: `Copy a file or directory from a running task to this machine over ECS Exec,
+like docker cp. The copy is archived in the task and streamed back base64
+encoded, so no bucket or session-manager-plugin is needed.
+
+Relative task paths start at the profile's worktree when a profile name is
+given, and at /workspace for a task ID. If the local path is an existing
+directory the copy is created inside it; "-" writes a tar archive to stdout.
+
+Examples:
+  frank ecs cp enkai:dist ./dist              # Worktree's dist directory
+  frank ecs cp enkai:/tmp/build.log .         # Into the current directory
+  frank ecs cp abc123def456:repos/enkai/work/go.sum go.sum
+  frank ecs cp enkai:coverage - | tar -t      # Archive to stdout`,
+	Args: cobra.ExactArgs(2),
+	RunE: runECSCp,
+}
+
+var ecsLsCmd = &cobra.Command{
+	Use:   "ls <profile-or-task-id> [path]",
+	Short: "List files in a task's workspace",
+	Long: `List a directory in a running task over ECS Exec.
+
+Relative paths start at the profile's worktree when a profile name is given,
+and at /workspace for a task ID. The path defaults to that directory.
+
+Examples:
+  frank ecs ls enkai                  # Profile's worktree
+  frank ecs ls enkai src/components
+  frank ecs ls abc123def456 /workspace/repos`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runECSLs,
+}
+
+func runECSCp(cmd *cobra.Command, args []string) error {
+	target, remotePath, ok := strings.Cut(args[0], ":")
+	if !ok || target == "" || remotePath == "" {
+		if strings.Contains(args[1], ":") {
+			return fmt.Errorf("copying into a task is not supported; the source must be <profile-or-task-id>:<path>")
+		}
+		return fmt.Errorf("source must be <profile-or-task-id>:<path>")
+	}
+	localPath := args[1]
+
+	ctx := context.Background()
+	client, err := getECSClient(ctx)
+	if err != nil {
+		return err
+	}
+	taskID, profileName, err := resolveExecTask(ctx, client, target)
+	if err != nil {
+		return err
+	}
+	remotePath = taskPath(profileName, remotePath)
+	PrintVerbose("Copying %s from task %s", remotePath, taskID)
+
+	// The archive goes to a temp file first so a tar failure is reported
+	// before any payload is written
+	script := shellPathVar(remotePath) +
+		`if [ ! -e "$P" ]; then echo "` + execPayloadError + ` $P: no such file or directory"; exit 1; fi; ` +
+		`T=$(mktemp) && tar -C "$(dirname "$P")" -czf "$T" "$(basename "$P")" 2>/dev/null || ` +
+		`{ echo "` + execPayloadError + ` failed to archive $P"; rm -f "$T"; exit 1; }; ` +
+		`echo ` + execPayloadBegin + `; base64 "$T"; echo ` + execPayloadEnd + `; rm -f "$T"`
+
+	pr, pw := io.Pipe()
+	extracted := make(chan error, 1)
+	go func() {
+		err := extractTaskArchive(base64.NewDecoder(base64.StdEncoding, pr), localPath)
+		if err != nil {
+			pr.CloseWithError(err)
+		} else {
+			// Drain the archive's trailing padding so the session can finish
+			io.Copy(io.Discard, pr)
+		}
+		extracted <- err
+	}()
+
+	payload := &execPayloadWriter{out: pw}
+	runErr := runTaskScript(ctx, client, taskID, script, payload)
+	switch {
+	case runErr != nil:
+		pw.CloseWithError(runErr)
+	case payload.errMsg != "":
+		pw.CloseWithError(errors.New(payload.errMsg))
+	case !payload.done:
+		pw.CloseWithError(fmt.Errorf("copy ended before the archive was complete"))
+	default:
+		pw.Close()
+	}
+	extractErr := <-extracted
+
+	if payload.errMsg != "" {
+		return errors.New(payload.errMsg)
+	}
+	if extractErr != nil {
+		return fmt.Errorf("failed to copy %s: %w", remotePath, extractErr)
+	}
+	if runErr != nil {
+		return runErr
+	}
+
+	if localPath != "-" {
+		fmt.Printf("%s Copied %s:%s to %s\n", color.GreenString("✓"), target, remotePath, localPath)
+	}
+	return nil
+}
+
+// extractTaskArchive unpacks a gzipped tar stream to localPath, or copies the
+// uncompressed tar to stdout when localPath is "-"
+func extractTaskArchive(r io.Reader, localPath string) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	if localPath == "-" {
+		_, err := io.Copy(os.Stdout, zr)
+		return err
+	}
+	return archive.Extract(zr, localPath)
+}
+
+func runECSLs(cmd *cobra.Command, args []string) error {
+	dir := ""
+	if len(args) > 1 {
+		dir = args[1]
+	}
+
+	ctx := context.Background()
+	client, err := getECSClient(ctx)
+	if err != nil {
+		return err
+	}
+	taskID, profileName, err := resolveExecTask(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+	dir = taskPath(profileName, dir)
+
+	// One line per entry: type, size, modification time and name
+	format := `-printf "%y\t%s\t%TY-%Tm-%Td %TH:%TM\t%f\n"`
+	script := shellPathVar(dir) +
+		`if [ ! -e "$P" ]; then echo "` + execPayloadError + ` $P: no such file or directory"; exit 1; fi; ` +
+		`echo ` + execPayloadBegin + `; ` +
+		`if [ -d "$P" ]; then find "$P" -mindepth 1 -maxdepth 1 ` + format + `; ` +
+		`else find "$P" -maxdepth 0 ` + format + `; fi; ` +
+		`echo ` + execPayloadEnd
+
+	var listing bytes.Buffer
+	payload := &execPayloadWriter{out: &listing}
+	// The script reports a missing path before exiting non-zero
+	err = runTaskScript(ctx, client, taskID, script, payload)
+	if payload.errMsg != "" {
+		return errors.New(payload.errMsg)
+	}
+	if err != nil {
+		return err
+	}
+	if !payload.done {
+		return fmt.Errorf("listing of %s ended early", dir)
+	}
+
+	type entry struct {
+		name     string
+		isDir    bool
+		size     string
+		modified string
+	}
+	var entries []entry
+	for _, line := range strings.Split(strings.TrimSpace(listing.String()), "\n") {
+		fields := strings.SplitN(line, "\t", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		e := entry{name: fields[3], isDir: fields[0] == "d", size: fields[1], modified: fields[2]}
+		if fields[0] == "l" {
+			e.name += "@"
+		}
+		entries = append(entries, e)
+	}
+
+	// Directories first, then by name
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].isDir != entries[j].isDir {
+			return entries[i].isDir
+		}
+		return entries[i].name < entries[j].name
+	})
+
+	fmt.Printf("%s\n\n", color.CyanString(dir))
+	if len(entries) == 0 {
+		fmt.Println("(empty)")
+		return nil
+	}
+
+	table := render.NewTable(render.Options{},
+		render.Column{Header: "NAME"},
+		render.Column{Header: "SIZE"},
+		render.Column{Header: "MODIFIED", Hide: 1},
+	)
+
+	for _, e := range entries {
+		name, size := e.name, formatFileSize(e.size)
+		if e.isDir {
+			name = color.BlueString(e.name + "/")
+			size = "-"
+		}
+		table.Append(name, size, e.modified)
+	}
+	return table.Render(os.Stdout)
+}
+
+// taskPath resolves a path in a task: relative paths start at the profile's
+// worktree, or at /workspace when the task was given by ID
+func taskPath(profileName, p string) string {
+	base := "/workspace"
+	if profileName != "" {
+		base = frankecs.ProfileWorkspacePath(profileName)
+	}
+	if p == "" {
+		return base
+	}
+	if path.IsAbs(p) {
+		return path.Clean(p)
+	}
+	return path.Join(base, p)
+}
+
+// shellPathVar returns shell that sets $P to p. The path travels base64
+// encoded so it needs no quoting inside the sh -c argument.
+func shellPathVar(p string) string {
+	return `P="$(echo ` + base64.StdEncoding.EncodeToString([]byte(p)) + ` | base64 -d)"; `
+}
+
+// runTaskScript runs a shell script in the task's container, writing its
+// terminal output to stdout
+func runTaskScript(ctx context.Context, client *ecs.Client, taskID, script string, stdout io.Writer) error {
+	session, err := ssmexec.Start(ctx, client, ssmexec.ExecInput{
+		Cluster:   ecsCluster,
+		Task:      taskID,
+		Container: defaultContainer,
+		Command:   "/bin/sh -c '" + script + "'",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start exec session: %w", err)
+	}
+	defer session.Close()
+	PrintVerbose("Session ID: %s", session.ID)
+
+	if err := session.Run(ctx, nil, stdout, io.Discard); err != nil {
+		return fmt.Errorf("exec session failed: %w", err)
+	}
+	return nil
+}
+
+// execPayloadWriter picks the payload out of a task command's terminal
+// output: the lines between execPayloadBegin and execPayloadEnd are written
+// to out without their carriage returns, and an execPayloadError line is
+// kept as errMsg.
+type execPayloadWriter struct {
+	out    io.Writer
+	line   []byte
+	inside bool
+	done   bool
+	errMsg string
+}
+
+func (w *execPayloadWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if b != '\n' {
+			w.line = append(w.line, b)
+			continue
+		}
+		line := strings.TrimRight(string(w.line), "\r")
+		w.line = w.line[:0]
+
+		switch {
+		case w.done:
+		case line == execPayloadBegin:
+			w.inside = true
+		case line == execPayloadEnd:
+			w.inside, w.done = false, true
+		case w.inside:
+			if _, err := io.WriteString(w.out, line+"\n"); err != nil {
+				return 0, err
+			}
+		case strings.HasPrefix(line, execPayloadError+" "):
+			w.errMsg = strings.TrimPrefix(line, execPayloadError+" ")
+		}
+	}
+	return len(p), nil
+}
+
+// formatFileSize renders a byte count from find as a short human-readable size
+func formatFileSize(s string) string {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return s
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// ============================================================================
+// ecs cleanup - Remove orphaned ALB resources
+// ============================================================================
+
+var ecsCleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Remove orphaned ALB target groups and listener rules",
+	Long: `Find and remove ALB target groups and listener rules that belong to
+profiles without running tasks.
+
+These orphans accumulate when tasks are stopped without cleaning up ALB
+resources. This command identifies them and removes them. Use --dry-run
+to print the deletions as a plan without making them.
+
+With --mine or --owner, only profiles whose target group carries that owner
+tag are cleaned up; profiles started before owners were recorded have none.`,
+	Annotations: mutates("elasticloadbalancing:DeleteRule", "elasticloadbalancing:DeleteTargetGroup"),
+	RunE:        runECSCleanup,
+}
+
+func runECSCleanup(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	owner, err := ownerFilter(ctx, cleanupMine, cleanupOwner)
+	if err != nil {
+		return err
+	}
+
+	// Get running tasks to build set of active profiles
+	client, err := getECSClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := frankecs.New(client, nil, ecsCluster).ListTasks(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	runningProfiles := make(map[string]bool)
+	for _, task := range tasks {
+		if name := frankecs.TagValue(task.Tags, frankecs.ProfileTagKey); name != "" {
+			runningProfiles[name] = true
+		}
+	}
+
+	fmt.Printf("Found %d running profile(s)\n", len(runningProfiles))
+
+	// Find orphaned target groups
+	albMgr, err := alb.NewManager(ctx, withAudit())
+	if err != nil {
+		return fmt.Errorf("failed to create ALB manager: %w", err)
+	}
+
+	if cleanupDryRun {
+		all, err := albMgr.PlanCleanup(ctx, runningProfiles)
+		if err != nil {
+			return fmt.Errorf("failed to plan ALB changes: %w", err)
+		}
+		var plans []*alb.Plan
+		for _, plan := range all {
+			if profileOwnedBy(ctx, albMgr, plan.Profile, owner) {
+				plans = append(plans, plan)
+			}
+		}
+		if len(plans) == 0 {
+			fmt.Printf("%s No orphaned ALB resources found\n", color.GreenString("✓"))
+			return nil
+		}
+		printALBPlans(plans...)
+		return nil
+	}
+
+	all, err := albMgr.FindOrphanedTargetGroups(ctx, runningProfiles)
+	if err != nil {
+		return fmt.Errorf("failed to find orphaned target groups: %w", err)
+	}
+	var orphans []string
+	for _, profileName := range all {
+		if profileOwnedBy(ctx, albMgr, profileName, owner) {
+			orphans = append(orphans, profileName)
+		}
+	}
+
+	if len(orphans) == 0 {
+		fmt.Printf("%s No orphaned ALB resources found\n", color.GreenString("✓"))
+		return nil
+	}
+
+	fmt.Printf("Found %d orphaned profile(s): %s\n", len(orphans), strings.Join(orphans, ", "))
+
+	// Delete orphaned resources
+	deleted := 0
+	for _, profileName := range orphans {
+		fmt.Printf("  Cleaning up %q...\n", profileName)
+		if err := albMgr.DeleteEndpoints(ctx, profileName); err != nil {
+			fmt.Printf("    Warning: Failed to delete ALB endpoints: %v\n", err)
+		} else {
+			deleted++
+		}
+	}
+
+	fmt.Printf("%s Cleaned up %d orphaned profile(s)\n", color.GreenString("✓"), deleted)
+	return nil
+}
+
+// ============================================================================
+// ecs alb - Inspect ALB listener rules
+// ============================================================================
+
+var ecsALBCmd = &cobra.Command{
+	Use:   "alb",
+	Short: "Inspect the ALB listener rules that route profiles",
+}
+
+var ecsALBAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Report conflicting and duplicate listener rules",
+	Long: `Check the HTTPS listener's rules for problems with profile routing.
+
+Each profile has four rules (status, terminal, bash and web) at consecutive
+priorities between 100 and 999, allocated from a hash of the profile name
+and probing for the next free run on collision. Every rule is tagged with its
+profile. This command reports:
+
+  duplicate      Several rules match the same path; only the first gets traffic
+  conflict       A rule's tag, paths and target groups name different profiles
+  untagged       A profile rule has no frank-profile tag
+  out-of-range   A profile rule's priority is outside 100-999
+
+The command exits with an error when problems are found.`,
+	Args: cobra.NoArgs,
+	RunE: runECSALBAudit,
+}
+
+func runECSALBAudit(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	albMgr, err := alb.NewManager(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create ALB manager: %w", err)
+	}
+
+	rules, issues, err := albMgr.Audit(ctx)
+	if err != nil {
+		return err
+	}
+
+	if albAuditAll {
+		table := render.NewTable(render.Options{},
+			render.Column{Header: "PRIORITY"},
+			render.Column{Header: "PATHS"},
+			render.Column{Header: "PROFILE"},
+			render.Column{Header: "TARGET GROUPS", Hide: 1},
+		)
+		for _, r := range rules {
+			owner := r.Profile
+			if owner == "" {
+				owner = "-"
+			}
+			table.Append(
+				fmt.Sprintf("%d", r.Priority),
+				strings.Join(r.Paths, ", "),
+				owner,
+				strings.Join(r.TargetGroups, ", "),
+			)
+		}
+		if err := table.Render(os.Stdout); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
+	if len(issues) == 0 {
+		fmt.Printf("%s %d listener rule(s), no problems found\n", color.GreenString("✓"), len(rules))
+		return nil
+	}
+
+	table := render.NewTable(render.Options{},
+		render.Column{Header: "PROBLEM"},
+		render.Column{Header: "PRIORITY"},
+		render.Column{Header: "PATHS"},
+		render.Column{Header: "PROFILE", Hide: 1},
+		render.Column{Header: "DETAIL"},
+	)
+	for _, issue := range issues {
+		owner := issue.Profile
+		if owner == "" {
+			owner = "-"
+		}
+		kind := color.YellowString(string(issue.Kind))
+		if issue.Kind == alb.IssueDuplicatePath || issue.Kind == alb.IssueOwnerMismatch {
+			kind = color.RedString(string(issue.Kind))
+		}
+		table.Append(
+			kind,
+			fmt.Sprintf("%d", issue.Priority),
+			strings.Join(issue.Paths, ", "),
+			owner,
+			issue.Detail,
+		)
+	}
+	if err := table.Render(os.Stdout); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%d problem(s) found in %d listener rule(s)", len(issues), len(rules))
+}
+
+// ============================================================================
+// ecs routes - Show a profile's ALB endpoints
+// ============================================================================
+
+var ecsRoutesCmd = &cobra.Command{
+	Use:   "routes <profile>",
+	Short: "Show a profile's ALB rules and target health",
+	Long: `Show the ALB endpoints of a profile: the listener rule and target group
+for the web page, the Claude and bash terminals and the status page, with
+the health of each registered target.
+
+Missing, out of order or stale rules are reported; the next
+'frank ecs start' replaces them as a set.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runECSRoutes,
+}
+
+func runECSRoutes(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	profileName := args[0]
+
+	albMgr, err := alb.NewManager(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create ALB manager: %w", err)
+	}
+	endpoints, err := albMgr.Endpoints(ctx, profileName)
+	if err != nil {
+		return err
+	}
+
+	table := render.NewTable(render.Options{},
+		render.Column{Header: "ENDPOINT"},
+		render.Column{Header: "PRIORITY"},
+		render.Column{Header: "PATHS", Hide: 1},
+		render.Column{Header: "TARGET GROUP", Hide: 2},
+		render.Column{Header: "TARGETS"},
+	)
+
+	for _, ep := range endpoints.Rules() {
+		priority := color.RedString("missing")
+		if ep.Rule != nil {
+			priority = fmt.Sprintf("%d", ep.Rule.Priority)
+		}
+
+		group := ep.TargetGroup
+		targets := "-"
+		if ep.TargetGroupArn == "" {
+			group += color.RedString(" (missing)")
+		} else if states, err := albMgr.TargetHealth(ctx, ep.TargetGroupArn); err != nil {
+			targets = color.RedString("%v", err)
+		} else {
+			targets = formatTargetStates(states)
+		}
+
+		table.Append(ep.Name, priority, strings.Join(ep.Paths, ", "), group, targets)
+	}
+	if err := table.Render(os.Stdout); err != nil {
+		return err
+	}
+
+	for _, r := range endpoints.Stale {
+		fmt.Printf("\n%s Stale rule at priority %d routes %s\n", color.YellowString("!"), r.Priority, strings.Join(r.Paths, ", "))
+	}
+	if !endpoints.Complete() {
+		fmt.Printf("\nThe routes are incomplete; 'frank ecs start %s' recreates them.\n", profileName)
+	}
+	return nil
+}
+
+// formatTargetStates lists registered targets with their health
+func formatTargetStates(states []alb.TargetState) string {
+	var parts []string
+	for _, s := range states {
+		state := s.State
+		switch s.State {
+		case "healthy":
+			state = color.GreenString(state)
+		case "initial", "draining":
+			state = color.YellowString(state)
+		default:
+			state = color.RedString(state)
+		}
+		parts = append(parts, s.Address+" "+state)
 	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
 
-	// Verify task exists and has execute command enabled
+// ============================================================================
+// ecs taskdef - Inspect and update the service task definition
+// ============================================================================
+
+var ecsTaskDefCmd = &cobra.Command{
+	Use:   "taskdef",
+	Short: "Inspect and update the Frank task definition",
+	Long: `Inspect and update the task definition used by the Frank ECS service.
+
+Updates register a new revision derived from the current one and roll the
+service to it, so image tags and environment defaults can be changed
+without redeploying the CDK stack.
+
+Examples:
+  frank ecs taskdef show
+  frank ecs taskdef update --image 123456789012.dkr.ecr.us-east-1.amazonaws.com/frank:v2
+  frank ecs taskdef update --env LOG_LEVEL=debug --env FEATURE_X=1
+  frank ecs taskdef update --unset-env FEATURE_X --no-deploy`,
+}
+
+var ecsTaskDefShowCmd = &cobra.Command{
+	Use:   "show [task-definition]",
+	Short: "Show the task definition",
+	Long: `Show the image and environment of a task definition.
+
+Defaults to the revision the Frank service is currently running.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runECSTaskDefShow,
+}
+
+func runECSTaskDefShow(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
 	client, err := getECSClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	descResult, err := client.DescribeTasks(ctx, &ecs.DescribeTasksInput{
-		Cluster: aws.String(ecsCluster),
-		Tasks:   []string{taskID},
+	taskDefArn := ""
+	if len(args) > 0 {
+		taskDefArn = args[0]
+	} else {
+		taskDefArn, err = currentServiceTaskDef(ctx, client, ecsCluster)
+		if err != nil {
+			return err
+		}
+	}
+
+	desc, err := client.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(taskDefArn),
 	})
 	if err != nil {
-		return fmt.Errorf("failed to describe task: %w", err)
+		return fmt.Errorf("failed to describe task definition: %w", err)
 	}
+	td := desc.TaskDefinition
 
-	if len(descResult.Tasks) == 0 {
-		return fmt.Errorf("task %s not found", taskID)
+	fmt.Printf("Task Definition: %s\n", color.CyanString(extractTaskDefName(aws.ToString(td.TaskDefinitionArn))))
+	fmt.Printf("  Status:  %s\n", formatECSStatus(string(td.Status)))
+	fmt.Printf("  CPU:     %s\n", aws.ToString(td.Cpu))
+	fmt.Printf("  Memory:  %s\n", aws.ToString(td.Memory))
+	if td.RegisteredAt != nil {
+		fmt.Printf("  Created: %s\n", td.RegisteredAt.Local().Format("2006-01-02 15:04:05"))
 	}
 
-	task := descResult.Tasks[0]
-	if !task.EnableExecuteCommand {
-		return fmt.Errorf("task %s does not have execute command enabled. Start a new task with 'frank ecs start'", taskID)
+	for _, c := range td.ContainerDefinitions {
+		fmt.Printf("\nContainer: %s\n", color.CyanString(aws.ToString(c.Name)))
+		fmt.Printf("  Image: %s\n", aws.ToString(c.Image))
+
+		if len(c.Environment) == 0 {
+			continue
+		}
+
+		env := make([]types.KeyValuePair, len(c.Environment))
+		copy(env, c.Environment)
+		sort.Slice(env, func(i, j int) bool {
+			return aws.ToString(env[i].Name) < aws.ToString(env[j].Name)
+		})
+
+		fmt.Println("  Environment:")
+		for _, kv := range env {
+			fmt.Printf("    %s=%s\n", aws.ToString(kv.Name), aws.ToString(kv.Value))
+		}
 	}
 
-	if aws.ToString(task.LastStatus) != "RUNNING" {
-		return fmt.Errorf("task %s is not running (status: %s)", taskID, aws.ToString(task.LastStatus))
+	return nil
+}
+
+var ecsTaskDefUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Register a new task definition revision and roll the service",
+	Long: `Register a new revision of the service's task definition with a new image
+and/or environment changes, then update the service to use it.`,
+	Annotations: mutates("ecs:RegisterTaskDefinition", "ecs:UpdateService", "iam:PassRole"),
+	RunE:        runECSTaskDefUpdate,
+}
+
+func runECSTaskDefUpdate(cmd *cobra.Command, args []string) error {
+	if taskDefImage == "" && len(taskDefEnv) == 0 && len(taskDefUnsetEnv) == 0 {
+		return fmt.Errorf("nothing to update: specify --image, --env or --unset-env")
 	}
 
-	// Build the AWS CLI command
-	awsArgs := []string{
-		"ecs", "execute-command",
-		"--cluster", ecsCluster,
-		"--task", taskID,
-		"--container", "frank",
-		"--interactive",
-		"--command", "/bin/bash",
+	setEnv, err := parseEnvFlags(taskDefEnv)
+	if err != nil {
+		return err
 	}
 
-	if ecsRegion != "" {
-		awsArgs = append([]string{"--region", ecsRegion}, awsArgs...)
+	ctx := context.Background()
+	client, err := getECSClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	currentTaskDef, err := currentServiceTaskDef(ctx, client, ecsCluster)
+	if err != nil {
+		return err
 	}
+	PrintVerbose("Current task definition: %s", currentTaskDef)
 
-	fmt.Printf("Connecting to task %s...\n", color.CyanString(taskID))
-	fmt.Printf("Running: aws %s\n\n", strings.Join(awsArgs, " "))
+	newTaskDef, err := registerTaskDefRevision(ctx, client, currentTaskDef, func(containers []types.ContainerDefinition) error {
+		def, err := findContainerDef(containers, defaultContainer)
+		if err != nil {
+			return err
+		}
+		if taskDefImage != "" {
+			fmt.Printf("  Image: %s -> %s\n", aws.ToString(def.Image), taskDefImage)
+			def.Image = aws.String(taskDefImage)
+		}
+		def.Environment = applyEnvChanges(def.Environment, setEnv, taskDefUnsetEnv)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Registered task definition: %s\n", color.GreenString("✓"), extractTaskDefName(newTaskDef))
 
-	// Execute AWS CLI - this replaces the current process
-	awsCmd := exec.Command("aws", awsArgs...)
-	awsCmd.Stdin = os.Stdin
-	awsCmd.Stdout = os.Stdout
-	awsCmd.Stderr = os.Stderr
+	if taskDefNoDeploy {
+		return nil
+	}
 
-	if err := awsCmd.Run(); err != nil {
-		return fmt.Errorf("failed to execute command: %w\n\nMake sure you have:\n1. AWS CLI installed\n2. Session Manager plugin installed (https://docs.aws.amazon.com/systems-manager/latest/userguide/session-manager-working-with-install-plugin.html)", err)
+	_, err = client.UpdateService(ctx, &ecs.UpdateServiceInput{
+		Cluster:        aws.String(ecsCluster),
+		Service:        aws.String(defaultService),
+		TaskDefinition: aws.String(newTaskDef),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update service: %w", err)
 	}
 
+	fmt.Printf("%s Service %s rolling to %s\n", color.GreenString("✓"), defaultService, extractTaskDefName(newTaskDef))
+	fmt.Println("Note: Profile tasks started with 'frank ecs start' pick up the new revision on next start")
 	return nil
 }
 
+// applyEnvChanges sets and removes environment variables, preserving existing order
+func applyEnvChanges(env []types.KeyValuePair, set map[string]string, unset []string) []types.KeyValuePair {
+	remove := make(map[string]bool)
+	for _, name := range unset {
+		remove[name] = true
+	}
+
+	var result []types.KeyValuePair
+	applied := make(map[string]bool)
+	for _, kv := range env {
+		name := aws.ToString(kv.Name)
+		if remove[name] {
+			fmt.Printf("  Env: -%s\n", name)
+			continue
+		}
+		if value, ok := set[name]; ok {
+			fmt.Printf("  Env: %s=%s\n", name, value)
+			kv.Value = aws.String(value)
+			applied[name] = true
+		}
+		result = append(result, kv)
+	}
+
+	// Append new variables in a stable order
+	var added []string
+	for name := range set {
+		if !applied[name] {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+	for _, name := range added {
+		fmt.Printf("  Env: +%s=%s\n", name, set[name])
+		result = append(result, types.KeyValuePair{Name: aws.String(name), Value: aws.String(set[name])})
+	}
+
+	return result
+}
+
 // ============================================================================
-// ecs cleanup - Remove orphaned ALB resources
+// ecs autostop - Stop idle profile tasks
 // ============================================================================
 
-var ecsCleanupCmd = &cobra.Command{
-	Use:   "cleanup",
-	Short: "Remove orphaned ALB target groups and listener rules",
-	Long: `Find and remove ALB target groups and listener rules that belong to
-profiles without running tasks.
+// defaultAutostopIdle is how long a profile task may sit idle before autostop stops it
+const defaultAutostopIdle = 2 * time.Hour
 
-These orphans accumulate when tasks are stopped without cleaning up ALB
-resources. This command identifies them and removes them.`,
-	RunE: runECSCleanup,
+var ecsAutostopCmd = &cobra.Command{
+	Use:   "autostop",
+	Short: "Stop profile tasks that have been idle too long",
+	Long: `Stop profile tasks that have been idle longer than a threshold and clean up
+their ALB resources.
+
+Activity is read from the task's /status/activity endpoint, which reports the
+last prompt, typing in the web UI, or time Claude was busy. If the endpoint is
+unreachable, the last CloudWatch log event for the task is used instead, and
+failing that the task's start time.
+
+Use --watch to keep running and check again every --interval.
+
+Examples:
+  frank ecs autostop                      # Stop tasks idle for 2h or more
+  frank ecs autostop --idle 30m --dry-run # Show what would be stopped
+  frank ecs autostop --exclude enkai      # Never stop the enkai profile
+  frank ecs autostop --watch --interval 15m`,
+	Annotations: mutates("ecs:StopTask", "elasticloadbalancing:DeregisterTargets"),
+	RunE:        runECSAutostop,
 }
 
-func runECSCleanup(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+// taskActivity is the idle state of one profile task
+type taskActivity struct {
+	Profile      string
+	TaskID       string
+	TaskIP       string
+	LastActivity time.Time
+	Source       string
+}
 
-	// Get running tasks to build set of active profiles
+func runECSAutostop(cmd *cobra.Command, args []string) error {
+	if autostopIdle <= 0 {
+		return fmt.Errorf("--idle must be greater than zero")
+	}
+
+	ctx := context.Background()
 	client, err := getECSClient(ctx)
 	if err != nil {
 		return err
 	}
+	logsClient, err := getLogsClient(ctx)
+	if err != nil {
+		return err
+	}
 
-	listResult, err := client.ListTasks(ctx, &ecs.ListTasksInput{
-		Cluster: aws.String(ecsCluster),
-	})
+	if !autostopWatch {
+		return autostopOnce(ctx, client, logsClient)
+	}
+
+	if autostopInterval < time.Minute {
+		return fmt.Errorf("--interval must be at least 1m")
+	}
+
+	sigCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(autostopInterval)
+	defer ticker.Stop()
+
+	for {
+		fmt.Printf("[%s] Checking for idle profile tasks\n", time.Now().Format("15:04:05"))
+		if err := autostopOnce(sigCtx, client, logsClient); err != nil {
+			PrintError("%v", err)
+		}
+
+		select {
+		case <-sigCtx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// autostopOnce checks every running profile task and stops the idle ones
+func autostopOnce(ctx context.Context, client *ecs.Client, logsClient *cloudwatchlogs.Client) error {
+	tasks, err := frankecs.New(client, nil, ecsCluster).ListTasks(ctx, types.DesiredStatusRunning)
 	if err != nil {
-		return fmt.Errorf("failed to list tasks: %w", err)
+		return err
+	}
+	if len(tasks) == 0 {
+		fmt.Println("No Frank tasks running")
+		return nil
 	}
 
-	runningProfiles := make(map[string]bool)
+	excluded := make(map[string]bool)
+	for _, name := range autostopExclude {
+		excluded[name] = true
+	}
 
-	if len(listResult.TaskArns) > 0 {
-		descResult, err := client.DescribeTasks(ctx, &ecs.DescribeTasksInput{
-			Cluster: aws.String(ecsCluster),
-			Tasks:   listResult.TaskArns,
-			Include: []types.TaskField{types.TaskFieldTags},
-		})
-		if err != nil {
-			return fmt.Errorf("failed to describe tasks: %w", err)
+	var activities []taskActivity
+	for _, task := range tasks {
+		profileName := ""
+		for _, tag := range task.Tags {
+			if aws.ToString(tag.Key) == "frank-profile" {
+				profileName = aws.ToString(tag.Value)
+			}
 		}
+		// Only profile tasks are reaped; the service's own tasks are managed by ECS
+		if profileName == "" || excluded[profileName] {
+			continue
+		}
+		activities = append(activities, lookupTaskActivity(ctx, logsClient, profileName, task))
+	}
 
-		for _, task := range descResult.Tasks {
-			for _, tag := range task.Tags {
-				if aws.ToString(tag.Key) == "frank-profile" {
-					runningProfiles[aws.ToString(tag.Value)] = true
-				}
+	if len(activities) == 0 {
+		fmt.Println("No profile tasks to check")
+		return nil
+	}
+
+	table := render.NewTable(render.Options{},
+		render.Column{Header: "PROFILE"},
+		render.Column{Header: "TASK ID"},
+		render.Column{Header: "IDLE"},
+		render.Column{Header: "SOURCE", Hide: 1},
+		render.Column{Header: "ACTION"},
+	)
+
+	var idle []taskActivity
+	for _, a := range activities {
+		idleFor := time.Since(a.LastActivity)
+		action := "keep"
+		if idleFor >= autostopIdle {
+			idle = append(idle, a)
+			action = color.YellowString("stop")
+			if autostopDryRun {
+				action = color.YellowString("stop (dry run)")
 			}
 		}
+		table.Append(a.Profile, a.TaskID, idleFor.Truncate(time.Minute).String(), a.Source, action)
+	}
+	if err := table.Render(os.Stdout); err != nil {
+		return err
+	}
+
+	if autostopDryRun || len(idle) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	stopped := 0
+	reason := fmt.Sprintf("Stopped by frank ecs autostop (idle %s)", autostopIdle)
+	for _, a := range idle {
+		fmt.Printf("Stopping profile %q (task %s)...\n", a.Profile, a.TaskID)
+		if err := stopProfileTask(ctx, client, a.Profile, a.TaskID, a.TaskIP, reason); err != nil {
+			PrintError("Failed to stop %s: %v", a.Profile, err)
+			continue
+		}
+		stopped++
 	}
 
-	fmt.Printf("Found %d running profile(s)\n", len(runningProfiles))
+	fmt.Printf("%s Stopped %d idle profile(s)\n", color.GreenString("✓"), stopped)
+	return nil
+}
 
-	// Find orphaned target groups
-	albMgr, err := alb.NewManager(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to create ALB manager: %w", err)
+// lookupTaskActivity determines when a profile task was last active, preferring
+// the container's status endpoint over CloudWatch logs over the start time
+func lookupTaskActivity(ctx context.Context, logsClient *cloudwatchlogs.Client, profileName string, task types.Task) taskActivity {
+	a := taskActivity{
+		Profile: profileName,
+		TaskID:  frankecs.TaskID(aws.ToString(task.TaskArn)),
+		TaskIP:  frankecs.PrivateIP(task),
+	}
+
+	last, err := fetchStatusActivity(ctx, profileName)
+	if err == nil {
+		a.LastActivity, a.Source = last, "status"
+		return a
 	}
+	PrintVerbose("Status endpoint unavailable for %s: %v", profileName, err)
 
-	orphans, err := albMgr.FindOrphanedTargetGroups(ctx, runningProfiles)
+	last, err = lastLogEvent(ctx, logsClient, a.TaskID)
+	if err == nil {
+		a.LastActivity, a.Source = last, "logs"
+		return a
+	}
+	PrintVerbose("No log activity for %s: %v", profileName, err)
+
+	a.LastActivity, a.Source = aws.ToTime(task.StartedAt), "started"
+	if task.StartedAt == nil {
+		// A task that hasn't started yet is never idle
+		a.LastActivity = time.Now()
+	}
+	return a
+}
+
+// frankDomain returns the ALB's domain: ecs.domain from the config, else the
+// default
+func frankDomain() string {
+	if cfg := GetConfig(); cfg != nil && cfg.ECS.Domain != "" {
+		return cfg.ECS.Domain
+	}
+	return "frank.digitaldevops.io"
+}
+
+// fetchStatusActivity reads /status/activity from a profile through the ALB
+func fetchStatusActivity(ctx context.Context, profileName string) (time.Time, error) {
+	domain := frankDomain()
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	url := fmt.Sprintf("https://%s/%s/status/activity", domain, profileName)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to find orphaned target groups: %w", err)
+		return time.Time{}, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return time.Time{}, err
 	}
+	defer resp.Body.Close()
 
-	if len(orphans) == 0 {
-		fmt.Printf("%s No orphaned ALB resources found\n", color.GreenString("✓"))
-		return nil
+	if resp.StatusCode != http.StatusOK {
+		return time.Time{}, fmt.Errorf("unexpected status %s", resp.Status)
 	}
 
-	fmt.Printf("Found %d orphaned profile(s): %s\n", len(orphans), strings.Join(orphans, ", "))
+	var activity struct {
+		LastActivity time.Time `json:"last_activity"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&activity); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse activity: %w", err)
+	}
+	if activity.LastActivity.IsZero() {
+		return time.Time{}, fmt.Errorf("no activity reported")
+	}
+	return activity.LastActivity, nil
+}
 
-	// Delete orphaned resources
-	deleted := 0
-	for _, profileName := range orphans {
-		fmt.Printf("  Cleaning up %q...\n", profileName)
-		if err := albMgr.DeleteAllListenerRules(ctx, profileName); err != nil {
-			fmt.Printf("    Warning: Failed to delete listener rules: %v\n", err)
+// lastLogEvent returns the time of the newest CloudWatch log event for a task
+func lastLogEvent(ctx context.Context, logsClient *cloudwatchlogs.Client, taskID string) (time.Time, error) {
+	// Same stream name formats as ecs logs
+	for _, prefix := range []string{"frank/frank/", "frank/"} {
+		result, err := logsClient.DescribeLogStreams(ctx, &cloudwatchlogs.DescribeLogStreamsInput{
+			LogGroupName:        aws.String(defaultLogGroup),
+			LogStreamNamePrefix: aws.String(prefix + taskID),
+		})
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to describe log streams: %w", err)
 		}
-		if err := albMgr.DeleteAllTargetGroups(ctx, profileName); err != nil {
-			fmt.Printf("    Warning: Failed to delete target groups: %v\n", err)
-		} else {
-			deleted++
+		for _, stream := range result.LogStreams {
+			if stream.LastEventTimestamp != nil {
+				return time.UnixMilli(*stream.LastEventTimestamp), nil
+			}
 		}
 	}
-
-	fmt.Printf("%s Cleaned up %d orphaned profile(s)\n", color.GreenString("✓"), deleted)
-	return nil
+	return time.Time{}, fmt.Errorf("no log events for task %s", taskID)
 }
 
 // ============================================================================
 // Helper functions
 // ============================================================================
 
-// extractTaskID extracts the task ID from a full ARN
-func extractTaskID(arn string) string {
-	parts := strings.Split(arn, "/")
-	if len(parts) > 0 {
-		return parts[len(parts)-1]
+// parseEnvFlags parses repeated KEY=VAL --env flags
+func parseEnvFlags(flags []string) (map[string]string, error) {
+	env := make(map[string]string)
+	for _, kv := range flags {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid --env %q: expected KEY=VAL", kv)
+		}
+		env[parts[0]] = parts[1]
 	}
-	return arn
+	return env, nil
+}
+
+// currentOwner returns the owner of the tasks this user starts: ecs.owner
+// from the config, else the name behind their AWS credentials. The lookup is
+// made once per run.
+func currentOwner(ctx context.Context) (string, error) {
+	if c := GetConfig(); c != nil && c.ECS.Owner != "" {
+		return c.ECS.Owner, nil
+	}
+
+	callerOnce.Do(func() {
+		opts := []func(*config.LoadOptions) error{frankaws.WithRetries()}
+		if ecsRegion != "" {
+			opts = append(opts, config.WithRegion(ecsRegion))
+		}
+		awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+		if err != nil {
+			callerErr = fmt.Errorf("failed to load AWS config: %w", err)
+			return
+		}
+		callerName, callerErr = frankaws.CallerName(ctx, awsCfg)
+	})
+	return callerName, callerErr
+}
+
+var (
+	callerOnce sync.Once
+	callerName string
+	callerErr  error
+)
+
+// taskOwner returns the owner to tag new tasks with, or "" when it can't be
+// determined and the tasks go untagged
+func taskOwner(ctx context.Context) string {
+	owner, err := currentOwner(ctx)
+	if err != nil {
+		PrintVerbose("Not tagging an owner (set ecs.owner to fix): %v", err)
+	}
+	return owner
+}
+
+// ownerFilter returns the owner selected by --mine or --owner, or "" for
+// every owner
+func ownerFilter(ctx context.Context, mine bool, owner string) (string, error) {
+	if mine && owner != "" {
+		return "", fmt.Errorf("--mine and --owner can't be used together")
+	}
+	if !mine {
+		return owner, nil
+	}
+	current, err := currentOwner(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to determine who you are (set ecs.owner): %w", err)
+	}
+	return current, nil
+}
+
+// ownerMatches reports whether an owner tag passes the owner filter
+func ownerMatches(owner, filter string) bool {
+	return filter == "" || strings.EqualFold(owner, filter)
+}
+
+// filterTasksByOwner returns the tasks tagged with owner, or all of them when
+// owner is empty
+func filterTasksByOwner(tasks []types.Task, owner string) []types.Task {
+	if owner == "" {
+		return tasks
+	}
+	var result []types.Task
+	for _, task := range tasks {
+		if ownerMatches(frankecs.TagValue(task.Tags, frankecs.OwnerTagKey), owner) {
+			result = append(result, task)
+		}
+	}
+	return result
+}
+
+// profileOwnedBy reports whether a profile's ALB resources are tagged with
+// owner, always true when owner is empty
+func profileOwnedBy(ctx context.Context, albMgr *alb.Manager, profileName, owner string) bool {
+	if owner == "" {
+		return true
+	}
+	profileOwner, err := albMgr.ProfileOwner(ctx, profileName)
+	if err != nil {
+		PrintVerbose("Could not read the owner of %s: %v", profileName, err)
+		return false
+	}
+	return ownerMatches(profileOwner, owner)
+}
+
+// printALBPlans prints ALB plans terraform-style: one +/~/- line per change,
+// grouped by profile, followed by a summary
+func printALBPlans(plans ...*alb.Plan) {
+	creates, updates, deletes := 0, 0, 0
+	for _, plan := range plans {
+		fmt.Printf("\nALB changes for profile %q:\n", plan.Profile)
+		if len(plan.Changes) == 0 {
+			fmt.Println("  (none)")
+		}
+		for _, c := range plan.Changes {
+			line := fmt.Sprintf("%-14s %s", c.Resource, c.Name)
+			if c.Detail != "" {
+				line += " (" + c.Detail + ")"
+			}
+			switch c.Action {
+			case alb.ActionCreate:
+				fmt.Println(color.GreenString("  + " + line))
+			case alb.ActionUpdate:
+				fmt.Println(color.YellowString("  ~ " + line))
+			default:
+				fmt.Println(color.RedString("  - " + line))
+			}
+		}
+		c, u, d := plan.Counts()
+		creates += c
+		updates += u
+		deletes += d
+	}
+	fmt.Printf("\nPlan: %d to create, %d to update, %d to delete.\n", creates, updates, deletes)
 }
 
 // extractTaskDefName extracts the task definition name from a full ARN
@@ -1141,6 +3751,73 @@ func extractTaskDefName(arn string) string {
 	return arn
 }
 
+// currentServiceTaskDef returns the task definition ARN the Frank service is running
+func currentServiceTaskDef(ctx context.Context, client *ecs.Client, cluster string) (string, error) {
+	desc, err := client.DescribeServices(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(cluster),
+		Services: []string{defaultService},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe service: %w", err)
+	}
+	if len(desc.Services) == 0 {
+		return "", fmt.Errorf("service %s not found in cluster %s", defaultService, cluster)
+	}
+	return aws.ToString(desc.Services[0].TaskDefinition), nil
+}
+
+// registerTaskDefRevision registers a new revision of a task definition after
+// applying mutate to a copy of its container definitions. It returns the new ARN.
+func registerTaskDefRevision(ctx context.Context, client *ecs.Client, taskDefArn string, mutate func([]types.ContainerDefinition) error) (string, error) {
+	desc, err := client.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(taskDefArn),
+		Include:        []types.TaskDefinitionField{types.TaskDefinitionFieldTags},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe task definition: %w", err)
+	}
+	td := desc.TaskDefinition
+
+	containers := td.ContainerDefinitions
+	if err := mutate(containers); err != nil {
+		return "", err
+	}
+
+	out, err := client.RegisterTaskDefinition(ctx, &ecs.RegisterTaskDefinitionInput{
+		Family:                  td.Family,
+		ContainerDefinitions:    containers,
+		Cpu:                     td.Cpu,
+		Memory:                  td.Memory,
+		EphemeralStorage:        td.EphemeralStorage,
+		ExecutionRoleArn:        td.ExecutionRoleArn,
+		TaskRoleArn:             td.TaskRoleArn,
+		InferenceAccelerators:   td.InferenceAccelerators,
+		IpcMode:                 td.IpcMode,
+		PidMode:                 td.PidMode,
+		NetworkMode:             td.NetworkMode,
+		PlacementConstraints:    td.PlacementConstraints,
+		ProxyConfiguration:      td.ProxyConfiguration,
+		RequiresCompatibilities: td.RequiresCompatibilities,
+		RuntimePlatform:         td.RuntimePlatform,
+		Volumes:                 td.Volumes,
+		Tags:                    desc.Tags,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to register task definition: %w", err)
+	}
+	return aws.ToString(out.TaskDefinition.TaskDefinitionArn), nil
+}
+
+// findContainerDef returns the named container definition for in-place edits
+func findContainerDef(containers []types.ContainerDefinition, name string) (*types.ContainerDefinition, error) {
+	for i := range containers {
+		if aws.ToString(containers[i].Name) == name {
+			return &containers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("container %q not found in task definition", name)
+}
+
 // formatECSStatus formats an ECS status with color
 func formatECSStatus(status string) string {
 	statusLower := strings.ToLower(status)
@@ -1176,7 +3853,7 @@ func outputECSJSON(tasks []types.Task) error {
 	for i, task := range tasks {
 		output[i] = map[string]interface{}{
 			"taskArn":    aws.ToString(task.TaskArn),
-			"taskId":     extractTaskID(*task.TaskArn),
+			"taskId":     frankecs.TaskID(aws.ToString(task.TaskArn)),
 			"status":     aws.ToString(task.LastStatus),
 			"health":     string(task.HealthStatus),
 			"startedAt":  task.StartedAt,
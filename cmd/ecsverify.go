@@ -0,0 +1,414 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/barff/frank/internal/alb"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// ============================================================================
+// ecs verify - Check that a profile's URL works end to end
+// ============================================================================
+
+var ecsVerifyCmd = &cobra.Command{
+	Use:   "verify <profile>",
+	Short: "Check DNS, TLS, ALB routing and health for a profile's URL",
+	Long: `Follow a request to a profile's URL hop by hop and report where it breaks.
+
+Checks, in order:
+  - The ECS domain resolves, and to the frank ALB
+  - The profile's listener rules exist and are in order
+  - The running task is registered and healthy in each target group
+  - The certificate served for the domain is valid and not about to expire
+  - https://<domain>/<profile>/ and its status page answer
+
+Each failure comes with a diagnosis and the command that fixes it. Checks
+that depend on an earlier one are skipped when it fails. Exits non-zero if
+any check fails.
+
+Examples:
+  frank ecs verify myprofile`,
+	Args:         cobra.ExactArgs(1),
+	RunE:         runECSVerify,
+	SilenceUsage: true,
+}
+
+// verifyTimeout bounds each network check
+const verifyTimeout = 10 * time.Second
+
+// certExpiryWarning is how close to expiry a certificate is reported
+const certExpiryWarning = 14 * 24 * time.Hour
+
+func runECSVerify(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	profileName := args[0]
+
+	domain := cfg.ECS.Domain
+	if domain == "" {
+		return exitcode.Errorf(exitcode.Config, "ecs.domain is not set. Set it with: frank config set ecs.domain <domain>")
+	}
+
+	albMgr, err := alb.NewManager(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create ALB manager: %w", err)
+	}
+
+	fmt.Printf("Verifying %s\n\n", color.CyanString("https://%s/%s/", domain, profileName))
+
+	// Later checks are skipped when the ones they depend on fail
+	var (
+		resolved  bool
+		endpoints *alb.ProfileEndpoints
+		tlsOK     bool
+	)
+	checks := []doctorCheck{
+		{"DNS", func() checkResult {
+			result := verifyDNS(ctx, albMgr, domain)
+			resolved = result.status != checkFail
+			return result
+		}},
+		{"Listener rules", func() checkResult {
+			var result checkResult
+			endpoints, result = verifyListenerRules(ctx, albMgr, profileName)
+			return result
+		}},
+		{"Target health", func() checkResult {
+			if endpoints == nil {
+				return checkResult{status: checkSkip, detail: "listener rules couldn't be read"}
+			}
+			return verifyTargetHealth(ctx, albMgr, endpoints)
+		}},
+		{"TLS certificate", func() checkResult {
+			if !resolved {
+				return checkResult{status: checkSkip, detail: "domain doesn't resolve"}
+			}
+			result := verifyCertificate(domain)
+			tlsOK = result.status != checkFail
+			return result
+		}},
+		{"Web page", func() checkResult {
+			if !tlsOK {
+				return checkResult{status: checkSkip, detail: "no TLS connection"}
+			}
+			return verifyURL(ctx, fmt.Sprintf("https://%s/%s/", domain, profileName), profileName)
+		}},
+		{"Status page", func() checkResult {
+			if !tlsOK {
+				return checkResult{status: checkSkip, detail: "no TLS connection"}
+			}
+			return verifyURL(ctx, fmt.Sprintf("https://%s/%s/status", domain, profileName), profileName)
+		}},
+	}
+
+	var failed, warned int
+	for _, c := range checks {
+		switch printCheck(c.name, c.run()) {
+		case checkWarn:
+			warned++
+		case checkFail:
+			failed++
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed, %d warning(s)", failed, warned)
+	}
+	if warned > 0 {
+		fmt.Printf("%s is reachable with %d warning(s)\n", profileName, warned)
+		return nil
+	}
+	fmt.Printf("%s %s is reachable\n", color.GreenString("✓"), profileName)
+	return nil
+}
+
+// verifyDNS resolves the domain and checks it points at the frank ALB
+func verifyDNS(ctx context.Context, albMgr *alb.Manager, domain string) checkResult {
+	ctx, cancel := context.WithTimeout(ctx, verifyTimeout)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupHost(ctx, domain)
+	if err != nil || len(addrs) == 0 {
+		result := checkResult{status: checkFail, detail: fmt.Sprintf("%s doesn't resolve: %v", domain, err)}
+		if infra, err := albMgr.DiscoverInfrastructure(ctx); err == nil && infra.DNSName != "" {
+			result.fix = fmt.Sprintf("Create an alias (or CNAME) record for %s pointing at %s", domain, infra.DNSName)
+		} else {
+			result.fix = fmt.Sprintf("Create a DNS record for %s pointing at the frank ALB", domain)
+		}
+		return result
+	}
+	detail := fmt.Sprintf("%s → %s", domain, strings.Join(addrs, ", "))
+
+	// An alias record resolves to the ALB's current addresses; anything else
+	// means the record points somewhere else (or at an old load balancer)
+	infra, err := albMgr.DiscoverInfrastructure(ctx)
+	if err != nil {
+		PrintVerbose("Not comparing with the ALB: %v", err)
+		return checkResult{status: checkOK, detail: detail}
+	}
+	albAddrs, err := net.DefaultResolver.LookupHost(ctx, infra.DNSName)
+	if err != nil {
+		PrintVerbose("Failed to resolve %s: %v", infra.DNSName, err)
+		return checkResult{status: checkOK, detail: detail}
+	}
+	for _, a := range addrs {
+		if slices.Contains(albAddrs, a) {
+			return checkResult{status: checkOK, detail: detail}
+		}
+	}
+	return checkResult{
+		status: checkWarn,
+		detail: detail + ", none of which belong to the frank ALB",
+		fix:    fmt.Sprintf("Point %s at %s (a stale record, or a CDN or proxy in front of the ALB, causes this)", domain, infra.DNSName),
+	}
+}
+
+// verifyListenerRules checks the profile has every listener rule, in order
+func verifyListenerRules(ctx context.Context, albMgr *alb.Manager, profileName string) (*alb.ProfileEndpoints, checkResult) {
+	endpoints, err := albMgr.Endpoints(ctx, profileName)
+	if err != nil {
+		return nil, checkResult{status: checkFail, detail: err.Error(), fix: "Log in with: frank auth aws"}
+	}
+
+	var missing []string
+	for _, ep := range endpoints.Rules() {
+		if ep.Rule == nil {
+			missing = append(missing, ep.Name)
+		}
+	}
+	fix := fmt.Sprintf("Recreate the routes with: frank ecs start %s", profileName)
+	switch {
+	case len(missing) == len(endpoints.Rules()):
+		return endpoints, checkResult{
+			status: checkFail,
+			detail: fmt.Sprintf("no rules route /%s/; requests get the listener's default response", profileName),
+			fix:    fix,
+		}
+	case len(missing) > 0:
+		return endpoints, checkResult{status: checkFail, detail: "missing rules for " + strings.Join(missing, ", "), fix: fix}
+	case !endpoints.Complete():
+		detail := "rules are out of order"
+		if len(endpoints.Stale) > 0 {
+			detail = fmt.Sprintf("%d stale rule(s) also route the profile's paths", len(endpoints.Stale))
+		}
+		return endpoints, checkResult{
+			status: checkWarn,
+			detail: detail,
+			fix:    fix + "\nInspect them with: frank ecs routes " + profileName,
+		}
+	}
+
+	priorities := make([]string, 0, len(endpoints.Rules()))
+	for _, ep := range endpoints.Rules() {
+		priorities = append(priorities, fmt.Sprintf("%s %d", ep.Name, ep.Rule.Priority))
+	}
+	return endpoints, checkResult{status: checkOK, detail: strings.Join(priorities, ", ")}
+}
+
+// verifyTargetHealth checks the profile's running task is registered and
+// healthy in each of its target groups
+func verifyTargetHealth(ctx context.Context, albMgr *alb.Manager, endpoints *alb.ProfileEndpoints) checkResult {
+	profileName := endpoints.Profile
+	taskID, taskIP := findTaskByProfile(ctx, profileName)
+	if taskID == "" {
+		return checkResult{
+			status: checkFail,
+			detail: "no task is running for " + profileName,
+			fix:    "Start it with: frank ecs start " + profileName,
+		}
+	}
+
+	var problems []string
+	var starting bool
+	var fix string
+	for _, ep := range endpoints.TargetGroups() {
+		if ep.TargetGroupArn == "" {
+			problems = append(problems, ep.TargetGroup+" is missing")
+			fix = "Recreate the target groups with: frank ecs start " + profileName
+			continue
+		}
+		states, err := albMgr.TargetHealth(ctx, ep.TargetGroupArn)
+		if err != nil {
+			return checkResult{status: checkFail, detail: err.Error()}
+		}
+
+		address := fmt.Sprintf("%s:%d", taskIP, ep.Port)
+		i := slices.IndexFunc(states, func(s alb.TargetState) bool { return s.Address == address })
+		if i < 0 {
+			problems = append(problems, fmt.Sprintf("%s: task %s isn't registered", ep.Name, address))
+			fix = fmt.Sprintf("Stop and start the profile to re-register it: frank ecs stop %s && frank ecs start %s", profileName, profileName)
+			continue
+		}
+		switch s := states[i]; s.State {
+		case "healthy":
+		case "initial":
+			starting = true
+		default:
+			problem := fmt.Sprintf("%s: %s", ep.Name, s.State)
+			if s.Reason != "" {
+				problem += " (" + s.Reason + ")"
+			}
+			problems = append(problems, problem)
+			if fix == "" {
+				fix = fmt.Sprintf("Check the task's logs: frank ecs logs %s\nCheck the health check settings: frank profile show %s", profileName, profileName)
+			}
+		}
+	}
+
+	switch {
+	case len(problems) > 0:
+		return checkResult{status: checkFail, detail: strings.Join(problems, "; "), fix: fix}
+	case starting:
+		return checkResult{
+			status: checkWarn,
+			detail: fmt.Sprintf("task %s is still passing its first health checks", taskIP),
+			fix:    "Wait a minute and run this again",
+		}
+	}
+	return checkResult{status: checkOK, detail: fmt.Sprintf("task %s healthy in %d target groups", taskIP, len(endpoints.TargetGroups()))}
+}
+
+// verifyCertificate connects to the domain and checks the certificate it serves
+func verifyCertificate(domain string) checkResult {
+	dialer := &net.Dialer{Timeout: verifyTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(domain, "443"), &tls.Config{ServerName: domain})
+	if err != nil {
+		return diagnoseTLSError(domain, err)
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return checkResult{status: checkFail, detail: "no certificate presented"}
+	}
+	leaf := certs[0]
+	left := time.Until(leaf.NotAfter)
+	detail := fmt.Sprintf("%s, issued by %s, expires %s", leaf.Subject.CommonName, leaf.Issuer.CommonName, leaf.NotAfter.Format("2006-01-02"))
+	if left < certExpiryWarning {
+		return checkResult{
+			status: checkWarn,
+			detail: fmt.Sprintf("%s (in %d days)", detail, int(left.Hours()/24)),
+			fix:    "Check that the ACM certificate's DNS validation records still exist, so it renews",
+		}
+	}
+	return checkResult{status: checkOK, detail: detail}
+}
+
+// diagnoseTLSError explains why a TLS connection to the domain failed
+func diagnoseTLSError(domain string, err error) checkResult {
+	var (
+		hostErr    x509.HostnameError
+		invalidErr x509.CertificateInvalidError
+		authErr    x509.UnknownAuthorityError
+		netErr     net.Error
+	)
+	switch {
+	case errors.As(err, &hostErr):
+		return checkResult{
+			status: checkFail,
+			detail: fmt.Sprintf("certificate is for %s, not %s", strings.Join(certNames(hostErr.Certificate), ", "), domain),
+			fix:    fmt.Sprintf("Attach a certificate covering %s to the ALB's HTTPS listener", domain),
+		}
+	case errors.As(err, &invalidErr) && invalidErr.Reason == x509.Expired:
+		return checkResult{
+			status: checkFail,
+			detail: fmt.Sprintf("certificate expired on %s", invalidErr.Cert.NotAfter.Format("2006-01-02")),
+			fix:    "Renew the ACM certificate, or re-create its DNS validation records",
+		}
+	case errors.As(err, &authErr):
+		return checkResult{
+			status: checkFail,
+			detail: "certificate isn't signed by a trusted authority",
+			fix:    "Use an ACM certificate, or check for a proxy intercepting TLS on your network",
+		}
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return checkResult{
+			status: checkFail,
+			detail: fmt.Sprintf("connection to %s:443 timed out", domain),
+			fix:    "Check that the ALB's security group allows HTTPS from your address",
+		}
+	}
+	return checkResult{
+		status: checkFail,
+		detail: fmt.Sprintf("TLS connection failed: %v", err),
+		fix:    "Check that the ALB has an HTTPS listener on port 443",
+	}
+}
+
+// certNames returns the names a certificate is valid for
+func certNames(cert *x509.Certificate) []string {
+	if cert == nil {
+		return nil
+	}
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames
+	}
+	return []string{cert.Subject.CommonName}
+}
+
+// verifyURL requests url and explains an ALB error response
+func verifyURL(ctx context.Context, url, profileName string) checkResult {
+	ctx, cancel := context.WithTimeout(ctx, verifyTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return checkResult{status: checkFail, detail: err.Error()}
+	}
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return checkResult{status: checkFail, detail: fmt.Sprintf("request failed: %v", err)}
+	}
+	resp.Body.Close()
+	detail := fmt.Sprintf("%s in %s", resp.Status, time.Since(start).Round(time.Millisecond))
+
+	logs := "Check the task's logs: frank ecs logs " + profileName
+	switch {
+	case resp.StatusCode < 400:
+		return checkResult{status: checkOK, detail: detail}
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return checkResult{
+			status: checkWarn,
+			detail: detail + " (refused by ALB authentication or a WAF rule)",
+			fix:    "Open the URL in a browser that is signed in",
+		}
+	case resp.StatusCode == http.StatusNotFound:
+		return checkResult{
+			status: checkFail,
+			detail: detail + " (no listener rule matched, or the web wrapper doesn't serve this path)",
+			fix:    "Inspect the routes with: frank ecs routes " + profileName,
+		}
+	case resp.StatusCode == http.StatusBadGateway:
+		return checkResult{
+			status: checkFail,
+			detail: detail + " (the task closed the connection or sent an invalid response)",
+			fix:    logs,
+		}
+	case resp.StatusCode == http.StatusServiceUnavailable:
+		return checkResult{
+			status: checkFail,
+			detail: detail + " (no healthy targets)",
+			fix:    "Inspect target health with: frank ecs routes " + profileName,
+		}
+	case resp.StatusCode == http.StatusGatewayTimeout:
+		return checkResult{
+			status: checkFail,
+			detail: detail + " (the task didn't answer in time)",
+			fix:    "Check that the task's security group allows the ALB to reach it\n" + logs,
+		}
+	}
+	return checkResult{status: checkFail, detail: detail, fix: logs}
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/smithy-go"
+	"github.com/barff/frank/internal/authstore"
+	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/barff/frank/internal/profile"
+	"github.com/barff/frank/internal/redact"
+	"github.com/barff/frank/internal/ssmexec"
+	"github.com/docker/docker/errdefs"
+	"github.com/spf13/cobra"
+)
+
+// awsAuthErrorCodes are AWS error codes that mean the credentials are
+// expired, invalid or not allowed to make the call
+var awsAuthErrorCodes = map[string]bool{
+	"ExpiredToken":                true,
+	"ExpiredTokenException":       true,
+	"InvalidClientTokenId":        true,
+	"UnrecognizedClientException": true,
+	"AccessDenied":                true,
+	"AccessDeniedException":       true,
+	"UnauthorizedOperation":       true,
+	"UnauthorizedException":       true,
+}
+
+// errorKind decides the exit code of an error: the kind attached with
+// exitcode.Wrap, or one inferred from the AWS, Docker and frank errors it
+// wraps
+func errorKind(err error) exitcode.Kind {
+	if kind, ok := exitcode.KindOf(err); ok {
+		return kind
+	}
+
+	var tokenErr *ssocreds.InvalidTokenError
+	var apiErr smithy.APIError
+	var opErr *smithy.OperationError
+	var remoteExit *ssmexec.ExitError
+	switch {
+	case errors.As(err, &remoteExit):
+		// Only a bare remote status passes through as the exit code (see
+		// Execute); its number says nothing about the kind of failure
+		return exitcode.Remote
+	case errors.As(err, &tokenErr), errors.Is(err, authstore.ErrNoIdentity):
+		return exitcode.Auth
+	case errors.As(err, &apiErr) && awsAuthErrorCodes[apiErr.ErrorCode()]:
+		return exitcode.Auth
+	case errors.As(err, &apiErr), errors.As(err, &opErr):
+		return exitcode.AWS
+	case errors.Is(err, container.ErrNoRuntimeFound):
+		return exitcode.Runtime
+	case errors.Is(err, profile.ErrNotFound), errdefs.IsNotFound(err):
+		return exitcode.NotFound
+	case strings.HasPrefix(err.Error(), "unknown command "):
+		// Cobra's own error when no subcommand matches
+		return exitcode.Usage
+	}
+	return exitcode.General
+}
+
+// markUsageErrors gives every command's argument check the usage kind
+func markUsageErrors(c *cobra.Command) {
+	if check := c.Args; check != nil {
+		c.Args = func(cmd *cobra.Command, args []string) error {
+			return exitcode.Wrap(exitcode.Usage, check(cmd, args))
+		}
+	}
+	for _, sub := range c.Commands() {
+		markUsageErrors(sub)
+	}
+}
+
+// quietMode reports whether the command ran with --quiet, which reports a
+// failure as a single JSON object
+func quietMode(c *cobra.Command) bool {
+	if f := c.Flags().Lookup("quiet"); f != nil && f.Changed {
+		return f.Value.String() == "true"
+	}
+
+	// Cobra stops before parsing flags on an unknown command or flag
+	for _, arg := range os.Args[1:] {
+		if arg == "--" {
+			break
+		}
+		if arg == "--quiet" || arg == "--quiet=true" {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonError is the object --quiet prints for a failure
+type jsonError struct {
+	Error    string `json:"error"`
+	Kind     string `json:"kind"`
+	ExitCode int    `json:"exit_code"`
+	Command  string `json:"command,omitempty"`
+}
+
+// printCommandError reports a failed command on w: cobra's "Error: ..." line
+// followed, for usage errors, by the command's usage, or the JSON object in
+// quiet mode
+func printCommandError(w io.Writer, c *cobra.Command, err error, kind exitcode.Kind) {
+	message := redact.String(err.Error())
+	if c == nil || !quietMode(c) {
+		fmt.Fprintln(w, "Error:", message)
+		if kind != exitcode.Usage || c == nil {
+			return
+		}
+		if c == rootCmd && strings.HasPrefix(message, "unknown command ") {
+			fmt.Fprintf(w, "Run '%s --help' for usage.\n", c.CommandPath())
+			return
+		}
+		fmt.Fprintln(w, c.UsageString())
+		return
+	}
+
+	out := jsonError{Error: message, Kind: kind.String(), ExitCode: kind.Code()}
+	if c != rootCmd {
+		out.Command = c.CommandPath()
+	}
+	data, _ := json.Marshal(out)
+	fmt.Fprintln(w, string(data))
+}
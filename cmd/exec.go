@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/exitcode"
 	"github.com/spf13/cobra"
 )
 
@@ -42,7 +43,7 @@ func runExec(cmd *cobra.Command, args []string) error {
 	containerName := args[0]
 	command := args[1:]
 
-	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred)
+	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
 	if err != nil {
 		return fmt.Errorf("failed to detect container runtime: %w", err)
 	}
@@ -52,7 +53,7 @@ func runExec(cmd *cobra.Command, args []string) error {
 	// Verify container exists and is running
 	c, err := runtime.GetContainer(containerName)
 	if err != nil {
-		return fmt.Errorf("container not found: %s", containerName)
+		return exitcode.Errorf(exitcode.NotFound, "container not found: %s", containerName)
 	}
 
 	if c.Status != "running" {
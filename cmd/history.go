@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/barff/frank/internal/render"
+	"github.com/barff/frank/internal/state"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [name]",
+	Short: "Show the containers and ECS tasks frank has started",
+	Long: `Show every container and ECS task frank has started, newest first,
+including ones that have since been removed.
+
+The history lives in ~/.frank/state.db. Each run records its repo, branch,
+image, ports, the snapshot it resumed from, the snapshots saved when it
+stopped and why it stopped. Given a container name, history lists that
+container's runs and the lineage of its workspace: the runs whose snapshots
+it resumed from.
+
+Examples:
+  frank history                       # Recent runs
+  frank history frank-dev-3           # One container and its lineage
+  frank history --repo my-app -n 50   # Runs of a repo
+  frank history --kind task --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHistory,
+}
+
+var (
+	historyProfile string
+	historyRepo    string
+	historyKind    string
+	historyLimit   int
+	historyOutput  render.Options
+)
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().StringVar(&historyProfile, "profile", "", "Only show runs of this profile")
+	historyCmd.Flags().StringVar(&historyRepo, "repo", "", "Only show runs whose repo contains this")
+	historyCmd.Flags().StringVar(&historyKind, "kind", "", "Only show containers or tasks (container, task)")
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 20, "Maximum number of runs to show (0 for all)")
+	render.AddFlags(historyCmd, &historyOutput)
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	if err := historyOutput.Validate(); err != nil {
+		return err
+	}
+	switch historyKind {
+	case "", state.KindContainer, state.KindTask:
+	default:
+		return fmt.Errorf("unknown kind %q (use container or task)", historyKind)
+	}
+
+	store, err := state.Open(getStateDBPath())
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	filter := state.Filter{
+		Profile: historyProfile,
+		Repo:    historyRepo,
+		Kind:    historyKind,
+		Limit:   historyLimit,
+	}
+	if len(args) > 0 {
+		filter.Name = args[0]
+	}
+	runs, err := store.List(filter)
+	if err != nil {
+		return err
+	}
+
+	if len(runs) == 0 && !historyOutput.Structured() {
+		fmt.Println("No runs recorded.")
+		return nil
+	}
+
+	table := render.NewTable(historyOutput,
+		render.Column{Header: "NAME"},
+		render.Column{Header: "KIND", Hide: 4},
+		render.Column{Header: "PROFILE", Hide: 3},
+		render.Column{Header: "REPO", MaxWidth: 40, TruncateLeft: true, Hide: 2},
+		render.Column{Header: "STARTED"},
+		render.Column{Header: "DURATION"},
+		render.Column{Header: "PORT", Hide: 5},
+		render.Column{Header: "FROM SNAPSHOT", Key: "from_snapshot", MaxWidth: 40, Hide: 1},
+		render.Column{Header: "STATUS", MaxWidth: 40},
+	)
+	for _, r := range runs {
+		port := ""
+		if len(r.Ports) > 0 {
+			port = strconv.Itoa(r.Ports[0])
+		}
+		started := r.StartedAt.Local().Format("2006-01-02 15:04")
+		if historyOutput.Structured() {
+			started = r.StartedAt.Format(time.RFC3339)
+		}
+		table.Append(
+			r.Name,
+			r.Kind,
+			r.Profile,
+			r.Repo,
+			started,
+			r.Duration().Round(time.Second).String(),
+			port,
+			r.FromSnapshot,
+			formatRunStatus(r),
+		)
+	}
+	if err := table.Render(os.Stdout); err != nil {
+		return err
+	}
+
+	// The lineage of a single container's latest run
+	if len(args) == 0 || historyOutput.Structured() || runs[0].FromSnapshot == "" {
+		return nil
+	}
+	lineage, err := store.Lineage(runs[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nLineage of %s:\n", color.CyanString(runs[0].Name))
+	fmt.Printf("  %s resumed from %s\n", runs[0].Name, runs[0].FromSnapshot)
+	for _, parent := range lineage {
+		line := fmt.Sprintf("  %s saved it on %s", parent.Name, parent.StoppedAt.Local().Format("2006-01-02 15:04"))
+		if parent.FromSnapshot != "" {
+			line += ", resumed from " + parent.FromSnapshot
+		}
+		fmt.Println(line)
+	}
+	if len(lineage) == 0 {
+		fmt.Println("  (the run that saved it isn't in the history)")
+	}
+	return nil
+}
+
+// formatRunStatus says whether a run is still going or why it stopped
+func formatRunStatus(r state.Run) string {
+	if !r.Stopped() {
+		return color.GreenString("running")
+	}
+	if r.StopReason == "" {
+		return "stopped"
+	}
+	return "stopped: " + r.StopReason
+}
+
+// getStateDBPath returns the local history database
+func getStateDBPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".frank", "state.db")
+}
+
+// recordState applies update to the local history. Like analytics,
+// failures never affect the command being recorded.
+func recordState(update func(*state.Store) error) {
+	store, err := state.Open(getStateDBPath())
+	if err != nil {
+		PrintVerbose("Warning: failed to record history: %v", err)
+		return
+	}
+	defer store.Close()
+	if err := update(store); err != nil {
+		PrintVerbose("Warning: failed to record history: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/barff/frank/internal/config"
+	"github.com/barff/frank/internal/container"
+	"github.com/fatih/color"
+)
+
+// containerHooks returns the start hooks for profile:
+// container.hooks followed by container.profileHooks, whose timeout wins
+// when set
+func containerHooks(cc config.ContainerConfig, profile string) config.HooksConfig {
+	hooks := config.HooksConfig{
+		PreStart:  append([]string{}, cc.Hooks.PreStart...),
+		PostStart: append([]string{}, cc.Hooks.PostStart...),
+		Timeout:   cc.Hooks.Timeout,
+	}
+	if p, ok := cc.ProfileHooks[strings.ToLower(profile)]; ok {
+		hooks.PreStart = append(hooks.PreStart, p.PreStart...)
+		hooks.PostStart = append(hooks.PostStart, p.PostStart...)
+		if p.Timeout > 0 {
+			hooks.Timeout = p.Timeout
+		}
+	}
+	return hooks
+}
+
+// runPreStartHooks runs the pre-start commands on the host, in dir when set,
+// with env added to frank's own environment. The first failure is returned.
+func runPreStartHooks(hooks config.HooksConfig, dir string, env []string) error {
+	for _, command := range hooks.PreStart {
+		fmt.Printf("Running pre-start hook: %s\n", color.CyanString(command))
+
+		ctx, cancel := context.WithTimeout(context.Background(), hooks.Timeout)
+		c := exec.CommandContext(ctx, "sh", "-c", command)
+		c.Dir = dir
+		c.Env = append(os.Environ(), env...)
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		err := c.Run()
+		timedOut := errors.Is(ctx.Err(), context.DeadlineExceeded)
+		cancel()
+
+		if timedOut {
+			return fmt.Errorf("pre-start hook %q timed out after %s", command, hooks.Timeout)
+		}
+		if err != nil {
+			return fmt.Errorf("pre-start hook %q failed: %w", command, err)
+		}
+	}
+	return nil
+}
+
+// runPostStartHooks runs the post-start commands in a started container,
+// stopping at the first one that fails or runs past the timeout. Failures are
+// reported but leave the container running.
+func runPostStartHooks(runtime container.Runtime, containerID string, hooks config.HooksConfig) {
+	for _, command := range hooks.PostStart {
+		fmt.Printf("Running post-start hook: %s\n", color.CyanString(command))
+
+		// TTY output isn't multiplexed, but the Docker API doesn't report the
+		// exit code, so an outer shell prints it after the hook
+		var output bytes.Buffer
+		done := make(chan error, 1)
+		go func() {
+			done <- runtime.ExecInContainer(containerID, []string{"/bin/sh", "-c", `/bin/sh -c "$1"; echo "` + hookExitMarker + `$?"`, "sh", command}, container.ExecOptions{
+				TTY:     true,
+				WorkDir: cfg.Container.WorkspaceMount,
+				Stdout:  &output,
+			})
+		}()
+
+		// A timed out exec is left running in the container, still writing
+		// to output, so its output is only read once it has returned
+		select {
+		case err := <-done:
+			lines, code := splitHookOutput(output.String())
+			if err == nil && code != "0" {
+				err = fmt.Errorf("exit status %s", code)
+			}
+			if err == nil {
+				for _, line := range lines {
+					PrintVerbose("    %s", line)
+				}
+				continue
+			}
+			for _, line := range lines {
+				fmt.Printf("    %s\n", line)
+			}
+			fmt.Printf("%s post-start hook failed: %v\n", color.YellowString("Warning:"), err)
+		case <-time.After(hooks.Timeout):
+			fmt.Printf("%s post-start hook still running after %s\n", color.YellowString("Warning:"), hooks.Timeout)
+		}
+		fmt.Println("The container is running; the remaining post-start hooks were skipped.")
+		return
+	}
+}
+
+// hookExitMarker precedes a post-start hook's exit code in its output
+const hookExitMarker = "frank-hook-exit="
+
+// splitHookOutput returns a post-start hook's output lines and its exit code,
+// which is empty when the marker is missing
+func splitHookOutput(output string) ([]string, string) {
+	var lines []string
+	var code string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if rest, ok := strings.CutPrefix(line, hookExitMarker); ok {
+			code = strings.TrimSpace(rest)
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if code == "" {
+		code = "unknown"
+	}
+	return lines, code
+}
+
+// hookEnv describes the container being started to pre-start hooks
+func hookEnv(containerName, profile, workspaceDir string, port int) []string {
+	env := []string{
+		"FRANK_CONTAINER=" + containerName,
+		"FRANK_PROFILE=" + profile,
+		fmt.Sprintf("FRANK_PORT=%d", port),
+	}
+	if workspaceDir != "" {
+		env = append(env, "FRANK_WORKSPACE="+workspaceDir)
+	}
+	if startRepo != "" {
+		env = append(env, "FRANK_REPO="+startRepo)
+	}
+	if startBranch != "" {
+		env = append(env, "FRANK_BRANCH="+startBranch)
+	}
+	return env
+}
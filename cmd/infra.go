@@ -0,0 +1,492 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/smithy-go"
+	"github.com/barff/frank/internal/agent"
+	"github.com/barff/frank/internal/alb"
+	frankaws "github.com/barff/frank/internal/aws"
+	frankecs "github.com/barff/frank/internal/ecs"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/barff/frank/internal/redact"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var infraCmd = &cobra.Command{
+	Use:   "infra",
+	Short: "Inspect the AWS infrastructure frank runs on",
+	Long: `Inspect the AWS infrastructure the CDK stack in cdk/ creates for
+'frank ecs' and the dashboard.
+
+Examples:
+  frank infra check                 # Verify every resource frank expects`,
+}
+
+var (
+	infraRegion  string
+	infraCluster string
+)
+
+func init() {
+	rootCmd.AddCommand(infraCmd)
+	infraCmd.AddCommand(infraCheckCmd)
+
+	infraCmd.PersistentFlags().StringVar(&infraRegion, "region", "", "AWS region (default: from AWS config)")
+	infraCheckCmd.Flags().StringVar(&infraCluster, "cluster", defaultCluster, "ECS cluster name")
+}
+
+// ============================================================================
+// infra check - Verify the resources frank assumes exist
+// ============================================================================
+
+var infraCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Verify the AWS resources frank depends on",
+	Long: `Check that every AWS resource frank assumes exists and looks the way
+the CDK stack in cdk/ defines it, and report exactly what is missing.
+
+Checks:
+  - The FrankStack CloudFormation stack is deployed and has all its outputs
+  - The frank-alb load balancer is active with an HTTPS listener
+  - The /ecs/frank log group exists
+  - The ECS cluster and its frank service are active
+  - The task definition has the frank container, its ports, log group and
+    the Claude, GitHub and Codex (OpenAI) secrets
+  - Each /frank/* secret exists and has a value
+
+Exits non-zero if any check fails.
+
+Examples:
+  frank infra check
+  frank infra check --region us-west-2 --cluster frank-dev`,
+	Args:         cobra.NoArgs,
+	RunE:         runInfraCheck,
+	SilenceUsage: true,
+}
+
+// stackFix is how operators recreate anything the stack owns
+const stackFix = "Deploy the stack: cd cdk && npx cdk deploy"
+
+// stackOutputs are the outputs the CDK stack defines
+var stackOutputs = []string{
+	"ServiceUrl",
+	"AlbDnsName",
+	"DashboardUrl",
+	"AnalyticsBucketName",
+	"SchedulerRoleArn",
+	"NotifyRoleArn",
+	"GitHubTokenSecretArn",
+	"GitHubAppIdSecretArn",
+	"GitHubAppPrivateKeySecretArn",
+	"GitHubAppInstallationIdSecretArn",
+	"ClaudeCredentialsSecretArn",
+	"OpenAIApiKeySecretArn",
+	"AzureOpenAIApiKeySecretArn",
+}
+
+// frankSecret is a Secrets Manager secret the stack creates
+type frankSecret struct {
+	name string
+	env  string // Variable the task definition maps it to; empty when loaded through FRANK_SECRETS
+}
+
+var frankSecrets = []frankSecret{
+	{"/frank/claude-credentials", "CLAUDE_CREDENTIALS"},
+	{"/frank/github-token", "GITHUB_TOKEN"},
+	{"/frank/github-app-id", "GITHUB_APP_ID"},
+	{"/frank/github-app-private-key", "GITHUB_APP_PRIVATE_KEY"},
+	{"/frank/github-app-installation-id", "GITHUB_APP_INSTALLATION_ID"},
+	{"/frank/openai-api-key", "OPENAI_API_KEY"}, // Codex
+	{agent.AzureAPIKeySecret, ""},
+}
+
+// taskPorts are the container ports the ALB and status checks reach
+var taskPorts = []int32{alb.WebPort, alb.ClaudePort, alb.BashPort, alb.StatusPort}
+
+// infraClients are the AWS clients the checks use
+type infraClients struct {
+	cfn     *cloudformation.Client
+	elb     *elasticloadbalancingv2.Client
+	logs    *cloudwatchlogs.Client
+	ecs     *ecs.Client
+	secrets *secretsmanager.Client
+}
+
+func runInfraCheck(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	opts := []func(*config.LoadOptions) error{frankaws.WithRetries()}
+	if infraRegion != "" {
+		opts = append(opts, config.WithRegion(infraRegion))
+	}
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return exitcode.Errorf(exitcode.Auth, "failed to load AWS config: %w", err)
+	}
+	// Without credentials every check would fail, each after its own retries
+	if _, err := awsCfg.Credentials.Retrieve(ctx); err != nil {
+		return exitcode.Errorf(exitcode.Auth, "no usable AWS credentials (run 'frank doctor' for details): %w", err)
+	}
+	c := infraClients{
+		cfn:     cloudformation.NewFromConfig(awsCfg),
+		elb:     elasticloadbalancingv2.NewFromConfig(awsCfg),
+		logs:    cloudwatchlogs.NewFromConfig(awsCfg),
+		ecs:     ecs.NewFromConfig(awsCfg),
+		secrets: secretsmanager.NewFromConfig(awsCfg),
+	}
+
+	fmt.Printf("Checking frank infrastructure in %s\n\n", color.CyanString(awsCfg.Region))
+
+	// The ALB's DNS name is compared with the stack's AlbDnsName output
+	var albDNS string
+	checks := []doctorCheck{
+		{"Load balancer", func() checkResult {
+			var result checkResult
+			albDNS, result = checkInfraALB(ctx, c)
+			return result
+		}},
+		{"Stack " + alb.StackName, func() checkResult { return checkInfraStack(ctx, c, albDNS) }},
+		{"Log group", func() checkResult { return checkInfraLogGroup(ctx, c) }},
+		{"Cluster", func() checkResult { return checkInfraCluster(ctx, c) }},
+		{"Service", func() checkResult { return checkInfraService(ctx, c) }},
+		{"Task definition", func() checkResult { return checkInfraTaskDefinition(ctx, c) }},
+	}
+	for _, s := range frankSecrets {
+		s := s
+		checks = append(checks, doctorCheck{"Secret " + strings.TrimPrefix(s.name, "/frank/"), func() checkResult {
+			return checkInfraSecret(ctx, c, s)
+		}})
+	}
+
+	var failed, warned int
+	for _, check := range checks {
+		result := check.run()
+		// Every other check would fail the same way
+		if result.fix == awsAuthFix {
+			printCheck(check.name, result)
+			fmt.Println()
+			return exitcode.Errorf(exitcode.Auth, "AWS credentials were rejected")
+		}
+		switch printCheck(check.name, result) {
+		case checkWarn:
+			warned++
+		case checkFail:
+			failed++
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed, %d warning(s)", failed, warned)
+	}
+	if warned > 0 {
+		fmt.Printf("Infrastructure is in place with %d warning(s)\n", warned)
+		return nil
+	}
+	fmt.Printf("%s Infrastructure is in place\n", color.GreenString("✓"))
+	return nil
+}
+
+// checkInfraALB checks the frank-alb load balancer and its HTTPS listener,
+// returning its DNS name
+func checkInfraALB(ctx context.Context, c infraClients) (string, checkResult) {
+	out, err := c.elb.DescribeLoadBalancers(ctx, &elasticloadbalancingv2.DescribeLoadBalancersInput{
+		Names: []string{"frank-alb"},
+	})
+	if awsErrorCode(err) == "LoadBalancerNotFound" || (err == nil && len(out.LoadBalancers) == 0) {
+		return "", checkResult{status: checkFail, detail: "load balancer frank-alb not found", fix: stackFix}
+	}
+	if err != nil {
+		return "", infraErrorResult(err)
+	}
+
+	lb := out.LoadBalancers[0]
+	dnsName := aws.ToString(lb.DNSName)
+	if lb.State != nil && lb.State.Code != "active" {
+		return dnsName, checkResult{status: checkWarn, detail: fmt.Sprintf("frank-alb is %s", lb.State.Code)}
+	}
+
+	listeners, err := c.elb.DescribeListeners(ctx, &elasticloadbalancingv2.DescribeListenersInput{
+		LoadBalancerArn: lb.LoadBalancerArn,
+	})
+	if err != nil {
+		return dnsName, infraErrorResult(err)
+	}
+	for _, l := range listeners.Listeners {
+		if aws.ToInt32(l.Port) == 443 {
+			return dnsName, checkResult{status: checkOK, detail: "frank-alb, HTTPS listener on 443"}
+		}
+	}
+	return dnsName, checkResult{status: checkFail, detail: "frank-alb has no HTTPS listener on port 443", fix: stackFix}
+}
+
+// checkInfraStack checks the stack's status and outputs
+func checkInfraStack(ctx context.Context, c infraClients, albDNS string) checkResult {
+	out, err := c.cfn.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(alb.StackName),
+	})
+	if awsErrorCode(err) == "ValidationError" || (err == nil && len(out.Stacks) == 0) {
+		return checkResult{status: checkFail, detail: "stack not deployed", fix: stackFix}
+	}
+	if err != nil {
+		return infraErrorResult(err)
+	}
+
+	stack := out.Stacks[0]
+	status := string(stack.StackStatus)
+	if strings.HasSuffix(status, "_FAILED") || strings.Contains(status, "ROLLBACK") {
+		return checkResult{status: checkFail, detail: "stack is " + status, fix: "Check the stack events in the CloudFormation console, then: cd cdk && npx cdk deploy"}
+	}
+
+	outputs := make(map[string]string)
+	for _, o := range stack.Outputs {
+		outputs[aws.ToString(o.OutputKey)] = aws.ToString(o.OutputValue)
+	}
+	var missing []string
+	for _, key := range stackOutputs {
+		if outputs[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return checkResult{status: checkFail, detail: "missing outputs: " + strings.Join(missing, ", "), fix: stackFix}
+	}
+	if albDNS != "" && !strings.EqualFold(outputs["AlbDnsName"], albDNS) {
+		return checkResult{
+			status: checkWarn,
+			detail: fmt.Sprintf("output AlbDnsName is %s, but frank-alb is %s", outputs["AlbDnsName"], albDNS),
+			fix:    stackFix,
+		}
+	}
+	if strings.HasSuffix(status, "_IN_PROGRESS") {
+		return checkResult{status: checkWarn, detail: "stack is " + status}
+	}
+	return checkResult{status: checkOK, detail: fmt.Sprintf("%s, %d outputs", status, len(stack.Outputs))}
+}
+
+// checkInfraLogGroup checks the log group the task definition writes to
+func checkInfraLogGroup(ctx context.Context, c infraClients) checkResult {
+	out, err := c.logs.DescribeLogGroups(ctx, &cloudwatchlogs.DescribeLogGroupsInput{
+		LogGroupNamePrefix: aws.String(defaultLogGroup),
+	})
+	if err != nil {
+		return infraErrorResult(err)
+	}
+	for _, g := range out.LogGroups {
+		if aws.ToString(g.LogGroupName) == defaultLogGroup {
+			detail := defaultLogGroup
+			if days := aws.ToInt32(g.RetentionInDays); days > 0 {
+				detail += fmt.Sprintf(", %d day retention", days)
+			}
+			return checkResult{status: checkOK, detail: detail}
+		}
+	}
+	return checkResult{status: checkFail, detail: fmt.Sprintf("log group %s not found", defaultLogGroup), fix: stackFix}
+}
+
+// checkInfraCluster checks the ECS cluster is active
+func checkInfraCluster(ctx context.Context, c infraClients) checkResult {
+	out, err := c.ecs.DescribeClusters(ctx, &ecs.DescribeClustersInput{
+		Clusters: []string{infraCluster},
+	})
+	if err != nil {
+		return infraErrorResult(err)
+	}
+	if len(out.Clusters) == 0 {
+		return checkResult{status: checkFail, detail: fmt.Sprintf("cluster %s not found", infraCluster), fix: stackFix}
+	}
+	cluster := out.Clusters[0]
+	if status := aws.ToString(cluster.Status); status != "ACTIVE" {
+		return checkResult{status: checkFail, detail: fmt.Sprintf("cluster %s is %s", infraCluster, status), fix: stackFix}
+	}
+	if !slices.Contains(cluster.CapacityProviders, frankecs.SpotCapacityProvider) {
+		return checkResult{
+			status: checkWarn,
+			detail: fmt.Sprintf("%s has no %s capacity provider; 'ecs run --capacity spot' will fail", infraCluster, frankecs.SpotCapacityProvider),
+			fix:    stackFix,
+		}
+	}
+	return checkResult{
+		status: checkOK,
+		detail: fmt.Sprintf("%s, %d running task(s)", infraCluster, cluster.RunningTasksCount),
+	}
+}
+
+// checkInfraService checks the service profile tasks copy their task
+// definition and network configuration from
+func checkInfraService(ctx context.Context, c infraClients) checkResult {
+	out, err := c.ecs.DescribeServices(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(infraCluster),
+		Services: []string{frankecs.DefaultService},
+	})
+	if awsErrorCode(err) == "ClusterNotFoundException" {
+		return checkResult{status: checkSkip, detail: "no cluster"}
+	}
+	if err != nil {
+		return infraErrorResult(err)
+	}
+	if len(out.Services) == 0 || aws.ToString(out.Services[0].Status) == "INACTIVE" {
+		return checkResult{status: checkFail, detail: fmt.Sprintf("service %s not found in cluster %s", frankecs.DefaultService, infraCluster), fix: stackFix}
+	}
+
+	service := out.Services[0]
+	var problems []string
+	if status := aws.ToString(service.Status); status != "ACTIVE" {
+		problems = append(problems, "service is "+status)
+	}
+	if family := taskDefinitionFamily(aws.ToString(service.TaskDefinition)); family != defaultTaskFamily {
+		problems = append(problems, fmt.Sprintf("runs task definition %s, expected %s", family, defaultTaskFamily))
+	}
+	if nc := service.NetworkConfiguration; nc == nil || nc.AwsvpcConfiguration == nil || len(nc.AwsvpcConfiguration.Subnets) == 0 {
+		problems = append(problems, "no awsvpc subnets for profile tasks to reuse")
+	}
+	if len(problems) > 0 {
+		return checkResult{status: checkFail, detail: strings.Join(problems, "; "), fix: stackFix}
+	}
+	return checkResult{
+		status: checkOK,
+		detail: fmt.Sprintf("%s, %d/%d tasks running", frankecs.DefaultService, service.RunningCount, service.DesiredCount),
+	}
+}
+
+// taskContainer is what the checks need from the frank container definition
+type taskContainer struct {
+	secrets  map[string]string // Variable name to secret ARN
+	logGroup string
+	ports    []int32
+}
+
+// checkInfraTaskDefinition checks the latest active revision of the task
+// definition has what profile tasks and both agents need
+func checkInfraTaskDefinition(ctx context.Context, c infraClients) checkResult {
+	out, err := c.ecs.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(defaultTaskFamily),
+	})
+	if awsErrorCode(err) == "ClientException" {
+		return checkResult{status: checkFail, detail: fmt.Sprintf("no active revision of %s", defaultTaskFamily), fix: stackFix}
+	}
+	if err != nil {
+		return infraErrorResult(err)
+	}
+
+	td := out.TaskDefinition
+	revision := fmt.Sprintf("%s:%d", defaultTaskFamily, td.Revision)
+	var container *taskContainer
+	for _, def := range td.ContainerDefinitions {
+		if aws.ToString(def.Name) != frankecs.ContainerName {
+			continue
+		}
+		container = &taskContainer{secrets: make(map[string]string)}
+		for _, s := range def.Secrets {
+			container.secrets[aws.ToString(s.Name)] = aws.ToString(s.ValueFrom)
+		}
+		if def.LogConfiguration != nil {
+			container.logGroup = def.LogConfiguration.Options["awslogs-group"]
+		}
+		for _, pm := range def.PortMappings {
+			container.ports = append(container.ports, aws.ToInt32(pm.ContainerPort))
+		}
+	}
+	if container == nil {
+		return checkResult{status: checkFail, detail: fmt.Sprintf("%s has no %q container", revision, frankecs.ContainerName), fix: stackFix}
+	}
+
+	var problems []string
+	var missingSecrets []string
+	for _, s := range frankSecrets {
+		if s.env == "" {
+			continue
+		}
+		valueFrom, ok := container.secrets[s.env]
+		if !ok {
+			missingSecrets = append(missingSecrets, s.env)
+			continue
+		}
+		// Secrets Manager ARNs end in the name plus a random suffix
+		if !strings.Contains(valueFrom, ":secret:"+s.name) {
+			problems = append(problems, fmt.Sprintf("%s reads %s, expected %s", s.env, valueFrom, s.name))
+		}
+	}
+	if len(missingSecrets) > 0 {
+		problems = append(problems, "missing secrets: "+strings.Join(missingSecrets, ", "))
+	}
+	var missingPorts []string
+	for _, port := range taskPorts {
+		if !slices.Contains(container.ports, port) {
+			missingPorts = append(missingPorts, fmt.Sprint(port))
+		}
+	}
+	if len(missingPorts) > 0 {
+		problems = append(problems, "missing container ports: "+strings.Join(missingPorts, ", "))
+	}
+	if container.logGroup != defaultLogGroup {
+		problems = append(problems, fmt.Sprintf("logs to %q, expected %s", container.logGroup, defaultLogGroup))
+	}
+
+	if len(problems) > 0 {
+		return checkResult{status: checkFail, detail: revision + ": " + strings.Join(problems, "; "), fix: stackFix}
+	}
+	return checkResult{status: checkOK, detail: revision}
+}
+
+// checkInfraSecret checks a secret exists and has a current value
+func checkInfraSecret(ctx context.Context, c infraClients, s frankSecret) checkResult {
+	putFix := fmt.Sprintf("Set it with: aws secretsmanager put-secret-value --secret-id %s --secret-string <value>", s.name)
+	out, err := c.secrets.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+		SecretId: aws.String(s.name),
+	})
+	if awsErrorCode(err) == "ResourceNotFoundException" {
+		return checkResult{status: checkFail, detail: s.name + " not found", fix: stackFix}
+	}
+	if err != nil {
+		return infraErrorResult(err)
+	}
+	if out.DeletedDate != nil {
+		return checkResult{
+			status: checkFail,
+			detail: fmt.Sprintf("%s is scheduled for deletion on %s", s.name, out.DeletedDate.Local().Format("2006-01-02")),
+			fix:    "Restore it with: aws secretsmanager restore-secret --secret-id " + s.name,
+		}
+	}
+	for _, stages := range out.VersionIdsToStages {
+		if slices.Contains(stages, "AWSCURRENT") {
+			return checkResult{status: checkOK, detail: s.name}
+		}
+	}
+	return checkResult{status: checkWarn, detail: s.name + " has no value", fix: putFix}
+}
+
+// awsErrorCode returns the error code of an AWS API error, or "" for other errors
+func awsErrorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
+
+// awsAuthFix is the fix for a check whose AWS call was refused
+const awsAuthFix = "Refresh your AWS credentials (frank doctor shows what's wrong), then run again"
+
+// infraErrorResult reports an AWS call that failed for a reason other than
+// the resource missing
+func infraErrorResult(err error) checkResult {
+	result := checkResult{status: checkFail, detail: redact.String(err.Error())}
+	if errorKind(err) == exitcode.Auth {
+		result.fix = awsAuthFix
+	}
+	return result
+}
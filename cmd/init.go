@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	frankaws "github.com/barff/frank/internal/aws"
+	"github.com/barff/frank/internal/config"
+	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Set up frank for the first time",
+	Long: `Walk through first-time setup.
+
+init asks for the settings a new install needs and writes them to the config
+file: the container runtime (after detecting which ones are running), the
+image and port range, the AWS profile, and the ECS cluster and domain. It then
+offers to build the image if it's missing, to store credentials for each
+service 'frank auth' handles, and to check that the ECS stack is reachable.
+
+An existing config file is left alone unless --force is given; its other
+settings are kept. --yes accepts every default without asking, which also
+skips the steps that need input.
+
+Examples:
+  frank init
+  frank init --force         # Re-run setup over an existing config
+  frank init --yes           # Write the defaults without prompting`,
+	Args: cobra.NoArgs,
+	RunE: runInit,
+}
+
+var (
+	initForce bool
+	initYes   bool
+)
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().BoolVar(&initForce, "force", false, "Update an existing config file")
+	initCmd.Flags().BoolVarP(&initYes, "yes", "y", false, "Accept the defaults without prompting")
+}
+
+// initPrompter asks setup questions, or takes the defaults with --yes
+type initPrompter struct {
+	reader *bufio.Reader
+	yes    bool
+}
+
+// ask returns the answer to question, or def for an empty answer
+func (p *initPrompter) ask(question, def string) string {
+	if p.yes {
+		return def
+	}
+	if def != "" {
+		fmt.Printf("%s [%s]: ", question, def)
+	} else {
+		fmt.Printf("%s: ", question)
+	}
+	answer, _ := p.reader.ReadString('\n')
+	if answer = strings.TrimSpace(answer); answer != "" {
+		return answer
+	}
+	return def
+}
+
+// confirm asks a yes/no question
+func (p *initPrompter) confirm(question string, def bool) bool {
+	if p.yes {
+		return def
+	}
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	fmt.Printf("%s [%s]: ", question, hint)
+	answer, _ := p.reader.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	}
+	return def
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	path := config.FilePath(cfgFile)
+	if _, err := os.Stat(path); err == nil && !initForce {
+		return fmt.Errorf("%s already exists (use --force to update it, or 'frank config set')", path)
+	}
+	if !initYes && !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("frank init asks questions on a terminal; use --yes to accept the defaults")
+	}
+	p := &initPrompter{reader: bufio.NewReader(os.Stdin), yes: initYes}
+
+	fmt.Printf("Setting up frank in %s\n\n", color.CyanString(path))
+
+	// Container runtime
+	fmt.Println(color.New(color.Bold).Sprint("Container runtime"))
+	rt, err := container.DetectRuntime("auto", cfg.Runtime.DockerHost)
+	if err != nil {
+		var detectErr *container.DetectError
+		if errors.As(err, &detectErr) {
+			for _, a := range detectErr.Attempts {
+				fmt.Printf("  Tried %s\n", a.String())
+			}
+		}
+		fmt.Printf("  %s no running runtime found; start Docker Desktop, Podman or OrbStack before 'frank start'\n", color.YellowString("!"))
+	} else {
+		detail := rt.Name()
+		if endpoint := container.RuntimeEndpoint(rt); endpoint != "" {
+			detail += " (" + endpoint + ")"
+		}
+		fmt.Printf("  %s Found %s\n", color.GreenString("✓"), detail)
+	}
+	values := []struct{ key, value string }{
+		{"runtime.preferred", p.ask("  Runtime (auto, docker, podman, orbstack)", cfg.Runtime.Preferred)},
+	}
+
+	// Container image and ports
+	fmt.Println(color.New(color.Bold).Sprint("\nContainers"))
+	image := p.ask("  Image", cfg.Container.Image)
+	values = append(values,
+		struct{ key, value string }{"container.image", image},
+		struct{ key, value string }{"container.basePort", p.ask("  First host port", strconv.Itoa(cfg.Container.BasePort))},
+		struct{ key, value string }{"container.workspaceMode", p.ask("  Workspace mode (bind, volume)", cfg.Container.WorkspaceMode)},
+	)
+
+	// AWS and ECS
+	fmt.Println(color.New(color.Bold).Sprint("\nAWS"))
+	defaultProfile := cfg.AWS.DefaultProfile
+	if profiles, err := frankaws.NewSSOManager().ListProfiles(); err == nil && len(profiles) > 0 {
+		names := make([]string, 0, len(profiles))
+		for _, prof := range profiles {
+			names = append(names, prof.Name)
+		}
+		fmt.Printf("  Profiles in ~/.aws/config: %s\n", strings.Join(names, ", "))
+		if defaultProfile == "" {
+			defaultProfile = names[0]
+		}
+	}
+	awsProfile := p.ask("  Default AWS profile (empty for none)", defaultProfile)
+	cluster := p.ask("  ECS cluster", cfg.ECS.Cluster)
+	values = append(values,
+		struct{ key, value string }{"aws.defaultProfile", awsProfile},
+		struct{ key, value string }{"ecs.cluster", cluster},
+		struct{ key, value string }{"ecs.domain", p.ask("  Domain for profile URLs", cfg.ECS.Domain)},
+	)
+
+	// Write the answers, rolling back if the result doesn't validate
+	original, readErr := os.ReadFile(path)
+	for _, v := range values {
+		if _, err := config.SetValue(path, v.key, v.value); err != nil {
+			restoreConfigFile(path, original, readErr)
+			return err
+		}
+	}
+	updated, err := config.Load(path)
+	if err != nil {
+		restoreConfigFile(path, original, readErr)
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+	if errs := config.Validate(updated); len(errs) > 0 {
+		restoreConfigFile(path, original, readErr)
+		for _, e := range errs {
+			PrintError("%s", e.Error())
+		}
+		return exitcode.Errorf(exitcode.Config, "refusing to save invalid configuration")
+	}
+	cfg = updated
+	fmt.Printf("\n%s Wrote %s\n", color.GreenString("✓"), path)
+
+	// Image
+	if rt != nil {
+		if exists, err := rt.ImageExists(image); err == nil && !exists {
+			if _, err := findDockerfile(); err != nil {
+				fmt.Printf("\nImage %s isn't built yet and no Dockerfile was found; build it later with 'frank rebuild'.\n", image)
+			} else if p.confirm(fmt.Sprintf("\nImage %s isn't built yet. Build it now?", image), false) {
+				rebuildTag = image
+				if err := runRebuild(rebuildCmd, nil); err != nil {
+					fmt.Printf("%s %v\n", color.YellowString("Warning:"), err)
+				}
+			}
+		}
+	}
+
+	// Credentials for each service
+	if !initYes {
+		fmt.Println(color.New(color.Bold).Sprint("\nCredentials"))
+	}
+	for _, s := range initAuthServices() {
+		if !initYes && s.configured() {
+			fmt.Printf("  %s %s already configured\n", color.GreenString("✓"), s.name)
+			continue
+		}
+		if !p.confirm(fmt.Sprintf("  Set up %s now?", s.name), false) {
+			continue
+		}
+		if err := s.cmd.RunE(s.cmd, nil); err != nil {
+			fmt.Printf("%s %s: %v\n", color.YellowString("Warning:"), s.name, err)
+		}
+	}
+
+	// ECS stack
+	if p.confirm("\nCheck that the ECS stack is reachable?", false) {
+		fmt.Println()
+		checks := []doctorCheck{
+			{"AWS CLI", checkAWSCLI},
+			{"AWS SSO session", checkSSOSession},
+			{"ECS cluster", func() checkResult { return checkECSCluster(awsProfile, cluster) }},
+			{"AWS permissions", checkAWSPermissions},
+		}
+		for _, c := range checks {
+			printCheck(c.name, c.run())
+		}
+	}
+
+	fmt.Printf("\n%s frank is set up. Next:\n", color.GreenString("✓"))
+	fmt.Println("  frank doctor                       # Check the whole environment")
+	fmt.Println("  frank start --repo <url>           # Start a local container")
+	fmt.Println("  frank profile add <name> --repo <url> && frank ecs start <name>")
+	return nil
+}
+
+// initAuthService is a credential frank init offers to set up
+type initAuthService struct {
+	name       string
+	configured func() bool
+	cmd        *cobra.Command
+}
+
+func initAuthServices() []initAuthService {
+	has := func(get func() string) func() bool {
+		return func() bool { return get() != "" }
+	}
+	return []initAuthService{
+		{"Claude", has(GetClaudeToken), authClaudeCmd},
+		{"GitHub", has(GetGitHubToken), authGitHubCmd},
+		{"GitLab", has(GetGitLabToken), authGitLabCmd},
+		{"Bitbucket", has(GetBitbucketToken), authBitbucketCmd},
+		{"EnkaiRelay", has(GetEnkaiRelayToken), authEnkaiRelayCmd},
+	}
+}
+
+// checkECSCluster checks that the frank cluster exists and is active, using
+// awsProfile when set
+func checkECSCluster(awsProfile, cluster string) checkResult {
+	ctx, cancel := context.WithTimeout(context.Background(), permissionCheckTimeout)
+	defer cancel()
+
+	opts := []func(*awsconfig.LoadOptions) error{frankaws.WithRetries()}
+	if awsProfile != "" {
+		opts = append(opts, awsconfig.WithSharedConfigProfile(awsProfile))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return checkResult{status: checkFail, detail: fmt.Sprintf("failed to load AWS config: %v", err)}
+	}
+
+	deploy := "Deploy the stack from the frank repository: cd cdk && npx cdk deploy (see cdk/README.md)"
+	out, err := ecs.NewFromConfig(awsCfg).DescribeClusters(ctx, &ecs.DescribeClustersInput{
+		Clusters: []string{cluster},
+	})
+	if err != nil {
+		return checkResult{status: checkFail, detail: fmt.Sprintf("failed to describe cluster %s: %v", cluster, err), fix: "Log in with: frank auth aws"}
+	}
+	if len(out.Clusters) == 0 || aws.ToString(out.Clusters[0].Status) != "ACTIVE" {
+		return checkResult{status: checkFail, detail: fmt.Sprintf("cluster %s not found in %s", cluster, awsCfg.Region), fix: deploy}
+	}
+	c := out.Clusters[0]
+	return checkResult{
+		status: checkOK,
+		detail: fmt.Sprintf("%s in %s (%d running tasks)", cluster, awsCfg.Region, c.RunningTasksCount),
+	}
+}
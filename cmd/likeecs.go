@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	frankaws "github.com/barff/frank/internal/aws"
+	"github.com/fatih/color"
+)
+
+// ecsOnlyEnv are task definition variables a local container doesn't take:
+// the repo comes from --repo or the local path instead
+var ecsOnlyEnv = map[string]bool{
+	"GIT_REPO":   true,
+	"GIT_BRANCH": true,
+}
+
+// ecsTaskEnv is the environment of the Frank service's task definition
+type ecsTaskEnv struct {
+	TaskDef string
+	Env     []string // NAME=VALUE, variables then secrets
+	Secrets int
+}
+
+// loadECSTaskEnv reads the environment variables and secrets of the task
+// definition the Frank service runs. Secrets are resolved from Secrets
+// Manager or SSM Parameter Store with the caller's credentials; ones that
+// can't be read are skipped with a warning.
+func loadECSTaskEnv(ctx context.Context) (*ecsTaskEnv, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, frankaws.WithRetries())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	client := ecs.NewFromConfig(awsCfg)
+
+	taskDefArn, err := currentServiceTaskDef(ctx, client, cfg.ECS.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	desc, err := client.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(taskDefArn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe task definition: %w", err)
+	}
+	def, err := findContainerDef(desc.TaskDefinition.ContainerDefinitions, defaultContainer)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ecsTaskEnv{TaskDef: extractTaskDefName(taskDefArn)}
+	for _, kv := range def.Environment {
+		name := aws.ToString(kv.Name)
+		if ecsOnlyEnv[name] {
+			continue
+		}
+		result.Env = append(result.Env, name+"="+aws.ToString(kv.Value))
+	}
+	sort.Strings(result.Env)
+
+	secrets := secretsmanager.NewFromConfig(awsCfg)
+	params := ssm.NewFromConfig(awsCfg)
+	var resolved []string
+	for _, s := range def.Secrets {
+		name := aws.ToString(s.Name)
+		value, err := resolveECSSecret(ctx, secrets, params, aws.ToString(s.ValueFrom))
+		if err != nil {
+			fmt.Printf("%s skipping secret %s: %v\n", color.YellowString("Warning:"), name, err)
+			continue
+		}
+		resolved = append(resolved, name+"="+value)
+		PrintVerbose("Resolved secret %s", name)
+	}
+	sort.Strings(resolved)
+	result.Env = append(result.Env, resolved...)
+	result.Secrets = len(resolved)
+	return result, nil
+}
+
+// resolveECSSecret reads the value a task definition secret points at. A
+// Secrets Manager reference may pick a JSON key, version stage and version:
+// arn:aws:secretsmanager:<region>:<account>:secret:<name>:<json-key>:<stage>:<version>.
+// Anything else is an SSM parameter name or ARN.
+func resolveECSSecret(ctx context.Context, secrets *secretsmanager.Client, params *ssm.Client, valueFrom string) (string, error) {
+	parts := strings.Split(valueFrom, ":")
+	if len(parts) < 7 || parts[0] != "arn" || parts[2] != "secretsmanager" {
+		out, err := params.GetParameter(ctx, &ssm.GetParameterInput{
+			Name:           aws.String(valueFrom),
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to read parameter: %w", err)
+		}
+		return aws.ToString(out.Parameter.Value), nil
+	}
+
+	in := &secretsmanager.GetSecretValueInput{SecretId: aws.String(strings.Join(parts[:7], ":"))}
+	jsonKey := ""
+	if len(parts) > 7 {
+		jsonKey = parts[7]
+	}
+	if len(parts) > 8 && parts[8] != "" {
+		in.VersionStage = aws.String(parts[8])
+	}
+	if len(parts) > 9 && parts[9] != "" {
+		in.VersionId = aws.String(parts[9])
+	}
+	out, err := secrets.GetSecretValue(ctx, in)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret: %w", err)
+	}
+	value := aws.ToString(out.SecretString)
+	if jsonKey == "" {
+		return value, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", fmt.Errorf("secret isn't JSON, so it has no key %q", jsonKey)
+	}
+	field, ok := fields[jsonKey]
+	if !ok {
+		return "", fmt.Errorf("secret has no key %q", jsonKey)
+	}
+	if s, ok := field.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(field)
+	return string(data), err
+}
+
+// mergeECSEnv adds the task definition's variables to env, keeping the
+// values frank set itself for the local container
+func mergeECSEnv(env, imported []string) []string {
+	set := make(map[string]bool, len(env))
+	for _, kv := range env {
+		set[strings.SplitN(kv, "=", 2)[0]] = true
+	}
+	for _, kv := range imported {
+		name := strings.SplitN(kv, "=", 2)[0]
+		if set[name] {
+			PrintVerbose("Keeping the local value of %s", name)
+			continue
+		}
+		env = append(env, kv)
+	}
+	return env
+}
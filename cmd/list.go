@@ -1,14 +1,17 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/render"
+	"github.com/barff/frank/internal/usage"
 	"github.com/fatih/color"
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -16,14 +19,26 @@ import (
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List running frank containers",
-	Long:  `List all frank containers. By default shows only running containers.`,
-	RunE:  runList,
+	Long: `List all frank containers. By default shows only running containers.
+
+With --watch the table refreshes until interrupted, highlighting containers
+whose status changed recently.
+
+With --usage the table adds each running container's token use, how full the
+agent's context window is, and its cost. Usage is read from the agents'
+session transcripts and headless output in the container logs; COST is
+marked ~ when it's estimated from list prices rather than reported by the
+agent.`,
+	RunE: runList,
 }
 
 var (
-	listAll    bool
-	listQuiet  bool
-	listFormat string
+	listAll      bool
+	listQuiet    bool
+	listOutput   render.Options
+	listWatch    bool
+	listInterval time.Duration
+	listUsage    bool
 )
 
 func init() {
@@ -31,31 +46,42 @@ func init() {
 
 	listCmd.Flags().BoolVarP(&listAll, "all", "a", false, "Show all containers including stopped")
 	listCmd.Flags().BoolVarP(&listQuiet, "quiet", "q", false, "Only display container IDs")
-	listCmd.Flags().StringVar(&listFormat, "format", "table", "Output format: table, json, yaml")
+	render.AddFlags(listCmd, &listOutput)
+	listCmd.Flags().BoolVarP(&listWatch, "watch", "w", false, "Refresh the table until interrupted")
+	listCmd.Flags().DurationVar(&listInterval, "interval", defaultWatchInterval, "Refresh interval for --watch")
+	listCmd.Flags().BoolVar(&listUsage, "usage", false, "Show token use, context and cost of running containers")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred)
+	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
 	if err != nil {
 		return fmt.Errorf("failed to detect container runtime: %w", err)
 	}
 
 	PrintVerbose("Using runtime: %s", runtime.Name())
 
-	containers, err := runtime.ListContainers(container.ContainerFilter{
-		All:        listAll,
-		NamePrefix: "frank-",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to list containers: %w", err)
+	if err := listOutput.Validate(); err != nil {
+		return err
 	}
 
-	// Filter to only frank containers
-	var frankContainers []container.Container
-	for _, c := range containers {
-		if strings.HasPrefix(c.Name, "frank-") {
-			frankContainers = append(frankContainers, c)
+	if listWatch {
+		if listQuiet || listOutput.Structured() {
+			return fmt.Errorf("--watch only supports table output")
 		}
+		tracker := newStatusTracker()
+		return watchLoop(listInterval, func() error {
+			defer tracker.next()
+			containers, err := listFrankContainers(runtime)
+			if err != nil {
+				return err
+			}
+			return outputTable(containers, tracker, collectUsage(runtime, containers))
+		})
+	}
+
+	frankContainers, err := listFrankContainers(runtime)
+	if err != nil {
+		return err
 	}
 
 	if listQuiet {
@@ -65,33 +91,63 @@ func runList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	switch listFormat {
-	case "json":
-		return outputJSON(frankContainers)
-	case "yaml":
-		return outputYAML(frankContainers)
+	usages := collectUsage(runtime, frankContainers)
+
+	switch listOutput.Format {
+	case render.FormatJSON:
+		return outputJSON(frankContainers, usages)
+	case render.FormatYAML:
+		return outputYAML(frankContainers, usages)
 	default:
-		return outputTable(frankContainers)
+		return outputTable(frankContainers, nil, usages)
+	}
+}
+
+// listFrankContainers returns frank containers, including stopped ones with --all
+func listFrankContainers(runtime container.Runtime) ([]container.Container, error) {
+	containers, err := runtime.ListContainers(container.ContainerFilter{
+		All:        listAll,
+		NamePrefix: "frank-",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	// Filter to only frank containers
+	var frankContainers []container.Container
+	for _, c := range containers {
+		if strings.HasPrefix(c.Name, "frank-") && !container.IsSidecar(c) {
+			frankContainers = append(frankContainers, c)
+		}
 	}
+	return frankContainers, nil
 }
 
-func outputTable(containers []container.Container) error {
+// outputTable prints the container table, with usage columns when usages
+// isn't nil
+func outputTable(containers []container.Container, tracker *statusTracker, usages map[string]usage.Usage) error {
 	if len(containers) == 0 {
 		fmt.Println("No frank containers found")
 		return nil
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"NAME", "STATUS", "PORT", "PROFILE", "CREATED", "IMAGE"})
-	table.SetBorder(false)
-	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-	table.SetAlignment(tablewriter.ALIGN_LEFT)
-	table.SetCenterSeparator("")
-	table.SetColumnSeparator("")
-	table.SetRowSeparator("")
-	table.SetHeaderLine(false)
-	table.SetTablePadding("  ")
-	table.SetNoWhiteSpace(true)
+	columns := []render.Column{
+		{Header: "NAME"},
+		{Header: "STATUS"},
+		{Header: "HEALTH", Hide: 3},
+		{Header: "PORT"},
+		{Header: "PROFILE", Hide: 4},
+		{Header: "CREATED", Hide: 5},
+		{Header: "IMAGE", MaxWidth: 40, TruncateLeft: true, Hide: 6},
+	}
+	if usages != nil {
+		columns = append(columns,
+			render.Column{Header: "TOKENS", Hide: 2},
+			render.Column{Header: "CONTEXT", Hide: 1},
+			render.Column{Header: "COST"},
+		)
+	}
+	table := render.NewTable(listOutput, columns...)
 
 	for _, c := range containers {
 		// Extract profile from container name (frank-<profile>-<index>)
@@ -107,37 +163,45 @@ func outputTable(containers []container.Container) error {
 		}
 
 		// Format status with color
-		status := formatStatus(c.Status)
+		status := tracker.format(c.Name, containerState(c.Status), formatStatus(c.Status))
 
 		// Format created time
 		created := c.Created.Format("2006-01-02 15:04")
 
-		table.Append([]string{
+		row := []string{
 			c.Name,
 			status,
+			formatHealth(c.Health),
 			port,
 			profile,
 			created,
 			c.Image,
-		})
+		}
+		if usages != nil {
+			row = append(row, formatUsage(usages[c.Name])...)
+		}
+		table.Append(row...)
 	}
 
-	table.Render()
-	return nil
+	return table.Render(os.Stdout)
 }
 
-func outputJSON(containers []container.Container) error {
+func outputJSON(containers []container.Container, usages map[string]usage.Usage) error {
 	output := make([]map[string]interface{}, len(containers))
 	for i, c := range containers {
 		output[i] = map[string]interface{}{
 			"id":      c.ID,
 			"name":    c.Name,
 			"status":  c.Status,
+			"health":  c.Health,
 			"image":   c.Image,
 			"created": c.Created.Format("2006-01-02T15:04:05Z"),
 			"ports":   c.Ports,
 			"profile": extractProfile(c.Name),
 		}
+		if u, ok := usages[c.Name]; ok {
+			output[i]["usage"] = usageMap(u)
+		}
 	}
 
 	enc := json.NewEncoder(os.Stdout)
@@ -145,18 +209,22 @@ func outputJSON(containers []container.Container) error {
 	return enc.Encode(output)
 }
 
-func outputYAML(containers []container.Container) error {
+func outputYAML(containers []container.Container, usages map[string]usage.Usage) error {
 	output := make([]map[string]interface{}, len(containers))
 	for i, c := range containers {
 		output[i] = map[string]interface{}{
 			"id":      c.ID,
 			"name":    c.Name,
 			"status":  c.Status,
+			"health":  c.Health,
 			"image":   c.Image,
 			"created": c.Created.Format("2006-01-02T15:04:05Z"),
 			"ports":   c.Ports,
 			"profile": extractProfile(c.Name),
 		}
+		if u, ok := usages[c.Name]; ok {
+			output[i]["usage"] = usageMap(u)
+		}
 	}
 
 	enc := yaml.NewEncoder(os.Stdout)
@@ -173,14 +241,151 @@ func extractProfile(name string) string {
 	return "-"
 }
 
+// containerState reduces a runtime status such as "Up 5 minutes (healthy)" to
+// its stable part ("up (healthy)") so uptime counters aren't seen as changes
+func containerState(status string) string {
+	fields := strings.Fields(strings.ToLower(status))
+	if len(fields) == 0 {
+		return ""
+	}
+	state := fields[0]
+	if open := strings.LastIndex(status, "("); open >= 0 && strings.HasSuffix(status, ")") {
+		if detail := strings.ToLower(status[open:]); !strings.ContainsAny(detail, "0123456789") {
+			state += " " + detail
+		}
+	}
+	return state
+}
+
 func formatStatus(status string) string {
 	statusLower := strings.ToLower(status)
 	if strings.Contains(statusLower, "up") || strings.Contains(statusLower, "running") {
 		return color.GreenString(status)
 	} else if strings.Contains(statusLower, "exited") || strings.Contains(statusLower, "stopped") {
 		return color.RedString(status)
-	} else if strings.Contains(statusLower, "created") {
+	} else if strings.Contains(statusLower, "created") || strings.Contains(statusLower, "restarting") {
 		return color.YellowString(status)
 	}
 	return status
 }
+
+// formatHealth colors a health state, showing "-" for containers without a
+// health check
+func formatHealth(health string) string {
+	switch health {
+	case container.HealthHealthy:
+		return color.GreenString(health)
+	case container.HealthUnhealthy:
+		return color.RedString(health)
+	case container.HealthStarting:
+		return color.YellowString(health)
+	case "":
+		return "-"
+	}
+	return health
+}
+
+// usageSources prints the agents' session transcripts, with a header before
+// each file so the tracker can tell sessions apart
+const usageSources = `tail -n +1 "$HOME"/.claude/projects/*/*.jsonl "$HOME"/.codex/sessions/*/*/*/*.jsonl 2>/dev/null; true`
+
+// collectUsage reads the usage of each running container with --usage,
+// returning nil without it
+func collectUsage(runtime container.Runtime, containers []container.Container) map[string]usage.Usage {
+	if !listUsage {
+		return nil
+	}
+
+	usages := make(map[string]usage.Usage)
+	for _, c := range containers {
+		if !strings.HasPrefix(containerState(c.Status), "up") {
+			continue
+		}
+		u, err := containerUsage(runtime, c.Name)
+		if err != nil {
+			PrintVerbose("Warning: failed to read usage of %s: %v", c.Name, err)
+			continue
+		}
+		usages[c.Name] = u
+	}
+	return usages
+}
+
+// containerUsage totals the usage in a container's agent transcripts and logs
+func containerUsage(runtime container.Runtime, name string) (usage.Usage, error) {
+	tracker := usage.NewTracker()
+
+	// TTY output isn't multiplexed, so lines arrive intact
+	var transcripts bytes.Buffer
+	err := runtime.ExecInContainer(name, []string{"sh", "-c", usageSources}, container.ExecOptions{
+		TTY:    true,
+		Stdout: &transcripts,
+	})
+	if err != nil {
+		return usage.Usage{}, err
+	}
+	if err := tracker.Read(&transcripts); err != nil {
+		return usage.Usage{}, err
+	}
+
+	// Headless runs print stream-json usage to the container log
+	logs, err := runtime.ContainerLogs(name, container.LogOptions{Tail: "all", Stdout: true, Stderr: true})
+	if err != nil {
+		return usage.Usage{}, err
+	}
+	defer logs.Close()
+	if err := tracker.Read(logs); err != nil {
+		return usage.Usage{}, err
+	}
+
+	return tracker.Usage(), nil
+}
+
+// formatUsage returns the TOKENS, CONTEXT and COST cells for a container
+func formatUsage(u usage.Usage) []string {
+	if u.Tokens() == 0 {
+		return []string{"-", "-", "-"}
+	}
+
+	tokens := fmt.Sprintf("%s in / %s out", formatTokenCount(u.InputTokens+u.CacheReadTokens+u.CacheCreationTokens), formatTokenCount(u.OutputTokens))
+
+	window := fmt.Sprintf("%s (%.0f%%)", formatTokenCount(u.ContextTokens), u.ContextPercent())
+	switch pct := u.ContextPercent(); {
+	case pct >= 90:
+		window = color.RedString(window)
+	case pct >= 75:
+		window = color.YellowString(window)
+	}
+
+	cost := fmt.Sprintf("$%.2f", u.CostUSD())
+	if u.Estimated() {
+		cost = "~" + cost
+	}
+	return []string{tokens, window, cost}
+}
+
+// formatTokenCount abbreviates a token count, e.g. 1.2M or 35k
+func formatTokenCount(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.0fk", float64(n)/1_000)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// usageMap returns a container's usage for JSON and YAML output
+func usageMap(u usage.Usage) map[string]interface{} {
+	return map[string]interface{}{
+		"input_tokens":          u.InputTokens,
+		"output_tokens":         u.OutputTokens,
+		"cache_read_tokens":     u.CacheReadTokens,
+		"cache_creation_tokens": u.CacheCreationTokens,
+		"context_tokens":        u.ContextTokens,
+		"context_percent":       u.ContextPercent(),
+		"model":                 u.Model,
+		"cost_usd":              u.CostUSD(),
+		"cost_estimated":        u.Estimated(),
+	}
+}
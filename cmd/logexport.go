@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	frankaws "github.com/barff/frank/internal/aws"
+	"github.com/barff/frank/internal/redact"
+)
+
+// logExport writes exported logs to a local file or, for s3://bucket/key
+// destinations, to a temporary file uploaded when it is closed. Everything
+// written is redacted.
+type logExport struct {
+	dest   string
+	bucket string
+	key    string
+	file   *os.File
+	buf    *bufio.Writer
+	out    *redact.Writer
+	lines  int
+}
+
+// openLogExport creates the export file for dest
+func openLogExport(dest string) (*logExport, error) {
+	e := &logExport{dest: dest}
+	var err error
+	if rest, ok := strings.CutPrefix(dest, "s3://"); ok {
+		e.bucket, e.key, _ = strings.Cut(rest, "/")
+		if e.bucket == "" || e.key == "" || strings.HasSuffix(e.key, "/") {
+			return nil, fmt.Errorf("invalid export destination %q (use s3://bucket/key)", dest)
+		}
+		e.file, err = os.CreateTemp("", "frank-logs-*.log")
+	} else {
+		e.file, err = os.Create(dest)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export file: %w", err)
+	}
+	e.buf = bufio.NewWriter(e.file)
+	e.out = redact.NewWriter(e.buf)
+	return e, nil
+}
+
+// Write adds raw log output to the export
+func (e *logExport) Write(p []byte) (int, error) {
+	e.lines += strings.Count(string(p), "\n")
+	return e.out.Write(p)
+}
+
+// Printf adds a line to the export
+func (e *logExport) Printf(format string, a ...interface{}) error {
+	_, err := fmt.Fprintf(e, format+"\n", a...)
+	return err
+}
+
+// Close finishes the export, uploading it for S3 destinations
+func (e *logExport) Close(ctx context.Context) error {
+	err := e.out.Flush()
+	if err == nil {
+		err = e.buf.Flush()
+	}
+	if e.bucket == "" {
+		if cerr := e.file.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", e.dest, err)
+		}
+		return nil
+	}
+
+	defer os.Remove(e.file.Name())
+	defer e.file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	if _, err := e.file.Seek(0, 0); err != nil {
+		return fmt.Errorf("failed to read export: %w", err)
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, frankaws.WithRetries(), awsconfig.WithRegion(getAnalyticsRegion()))
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	PrintVerbose("Uploading %s", e.dest)
+	_, err = s3.NewFromConfig(awsCfg).PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(e.bucket),
+		Key:         aws.String(e.key),
+		Body:        e.file,
+		ContentType: aws.String("text/plain; charset=utf-8"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", e.dest, err)
+	}
+	return nil
+}
+
+// Abort discards an unfinished export
+func (e *logExport) Abort() {
+	e.file.Close()
+	os.Remove(e.file.Name())
+}
@@ -1,12 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"time"
 
 	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/barff/frank/internal/redact"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
@@ -19,7 +23,12 @@ Examples:
   frank logs frank-dev-1
   frank logs frank-dev-1 -f
   frank logs frank-dev-1 --tail 50
-  frank logs frank-dev-1 -f -t`,
+  frank logs frank-dev-1 -f -t
+  frank logs frank-dev-1 --export dev.log
+  frank logs frank-dev-1 --export s3://my-bucket/logs/dev.log
+
+--export writes the container's entire log, not just the last --tail lines,
+to a local file or an S3 object.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runLogs,
 }
@@ -29,6 +38,7 @@ var (
 	logsTail       int
 	logsTimestamps bool
 	logsSince      string
+	logsExport     string
 )
 
 func init() {
@@ -38,12 +48,14 @@ func init() {
 	logsCmd.Flags().IntVar(&logsTail, "tail", 100, "Number of lines from end")
 	logsCmd.Flags().BoolVarP(&logsTimestamps, "timestamps", "t", false, "Show timestamps")
 	logsCmd.Flags().StringVar(&logsSince, "since", "", "Show logs since timestamp (e.g., 2024-01-15T10:00:00)")
+	logsCmd.Flags().StringVar(&logsExport, "export", "", "Write the entire log to a file or s3://bucket/key")
+	logsCmd.MarkFlagsMutuallyExclusive("export", "follow")
 }
 
 func runLogs(cmd *cobra.Command, args []string) error {
 	containerName := args[0]
 
-	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred)
+	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
 	if err != nil {
 		return fmt.Errorf("failed to detect container runtime: %w", err)
 	}
@@ -53,7 +65,7 @@ func runLogs(cmd *cobra.Command, args []string) error {
 	// Verify container exists
 	_, err = runtime.GetContainer(containerName)
 	if err != nil {
-		return fmt.Errorf("container not found: %s", containerName)
+		return exitcode.Errorf(exitcode.NotFound, "container not found: %s", containerName)
 	}
 
 	// Parse since time if provided
@@ -82,17 +94,43 @@ func runLogs(cmd *cobra.Command, args []string) error {
 		Stderr:     true,
 	}
 
+	if logsExport != "" && !cmd.Flags().Changed("tail") {
+		logOpts.Tail = "all"
+	}
+
 	logs, err := runtime.ContainerLogs(containerName, logOpts)
 	if err != nil {
 		return fmt.Errorf("failed to get logs: %w", err)
 	}
 	defer logs.Close()
 
-	// Copy logs to stdout
-	_, err = io.Copy(os.Stdout, logs)
+	if logsExport != "" {
+		return exportContainerLogs(logs, logsExport)
+	}
+
+	// Copy logs to stdout, masking any tokens the container echoed
+	out := redact.NewWriter(os.Stdout)
+	_, err = io.Copy(out, logs)
 	if err != nil && err != io.EOF {
 		return fmt.Errorf("error reading logs: %w", err)
 	}
 
+	return out.Flush()
+}
+
+// exportContainerLogs writes a container's log output to dest
+func exportContainerLogs(logs io.Reader, dest string) error {
+	export, err := openLogExport(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(export, logs); err != nil && err != io.EOF {
+		export.Abort()
+		return fmt.Errorf("error reading logs: %w", err)
+	}
+	if err := export.Close(context.Background()); err != nil {
+		return err
+	}
+	fmt.Printf("%s Exported %d lines to %s\n", color.GreenString("✓"), export.lines, dest)
 	return nil
 }
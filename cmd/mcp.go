@@ -0,0 +1,365 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/barff/frank/internal/claude"
+	"github.com/barff/frank/internal/config"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/barff/frank/internal/render"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Manage MCP servers",
+	Long: `Manage the MCP servers configured for new containers.
+
+Built-in servers (context7, sequential-thinking, aws-documentation,
+aws-knowledge, aws-core) only need to be enabled. Custom servers are defined
+with a command, arguments and environment, and a custom definition with a
+built-in name replaces the built-in one. A server limited to profiles is only
+enabled for containers started with one of them.
+
+Each container gets its own MCP config, generated when it starts, so changes
+apply to containers started afterwards.
+
+Examples:
+  frank mcp list                                   # Show configured servers
+  frank mcp add github --command npx \
+    --arg -y --arg @modelcontextprotocol/server-github \
+    --env GITHUB_TOKEN=ghp_xxx                     # Add a custom server
+  frank mcp add postgres --command uvx --arg mcp-server-postgres \
+    --profile dev                                  # Only for --profile dev
+  frank mcp disable aws-core                       # Stop using a server
+  frank mcp remove github                          # Remove a server`,
+}
+
+// Flags for mcp commands
+var (
+	mcpListProfile   string
+	mcpAddCommand    string
+	mcpAddArgs       []string
+	mcpAddEnv        []string
+	mcpAddProfiles   []string
+	mcpAddDisabled   bool
+	mcpEnableProfile []string
+)
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+	mcpCmd.AddCommand(mcpListCmd)
+	mcpCmd.AddCommand(mcpAddCmd)
+	mcpCmd.AddCommand(mcpRemoveCmd)
+	mcpCmd.AddCommand(mcpEnableCmd)
+	mcpCmd.AddCommand(mcpDisableCmd)
+
+	mcpListCmd.Flags().StringVarP(&mcpListProfile, "profile", "p", "", "Show which servers are enabled for a profile")
+
+	mcpAddCmd.Flags().StringVar(&mcpAddCommand, "command", "", "Command that runs the server (required for custom servers)")
+	mcpAddCmd.Flags().StringArrayVar(&mcpAddArgs, "arg", nil, "Argument for the command (repeatable)")
+	mcpAddCmd.Flags().StringArrayVar(&mcpAddEnv, "env", nil, "Environment variable KEY=VAL for the server (repeatable)")
+	mcpAddCmd.Flags().StringSliceVarP(&mcpAddProfiles, "profile", "p", nil, "Only enable for these profiles (default: all)")
+	mcpAddCmd.Flags().BoolVar(&mcpAddDisabled, "disabled", false, "Add the server disabled")
+
+	mcpEnableCmd.Flags().StringSliceVarP(&mcpEnableProfile, "profile", "p", nil, "Only enable for these profiles (replaces the current list)")
+}
+
+// ============================================================================
+// mcp list - Show configured servers
+// ============================================================================
+
+var mcpListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List MCP servers",
+	Long: `List the configured MCP servers and the built-in servers that aren't
+configured. With --profile, ENABLED shows whether a container started with
+that profile would get the server.`,
+	RunE: runMCPList,
+}
+
+func runMCPList(cmd *cobra.Command, args []string) error {
+	servers := append([]config.MCPServer(nil), cfg.MCP.Servers...)
+	configured := make(map[string]bool)
+	for _, s := range servers {
+		configured[s.Name] = true
+	}
+	var builtins []string
+	for name := range claude.GetDefaultServers() {
+		if !configured[name] {
+			builtins = append(builtins, name)
+		}
+	}
+	sort.Strings(builtins)
+	for _, name := range builtins {
+		servers = append(servers, config.MCPServer{Name: name})
+	}
+
+	table := render.NewTable(render.Options{},
+		render.Column{Header: "NAME"},
+		render.Column{Header: "ENABLED"},
+		render.Column{Header: "SOURCE"},
+		render.Column{Header: "COMMAND"},
+		render.Column{Header: "PROFILES"},
+	)
+
+	for _, s := range servers {
+		enabled := s.Enabled
+		if mcpListProfile != "" {
+			enabled = enabled && mcpServerForProfile(s, mcpListProfile)
+		}
+		enabledStr := "no"
+		if enabled {
+			enabledStr = color.GreenString("yes")
+		}
+
+		source := "built-in"
+		switch {
+		case s.Command != "" && claude.IsBuiltinServer(s.Name):
+			source = "custom (overrides built-in)"
+		case s.Command != "":
+			source = "custom"
+		case !claude.IsBuiltinServer(s.Name):
+			source = color.RedString("unknown")
+		}
+
+		command := "-"
+		if resolved, err := claude.ResolveServer(claude.MCPServer{Name: s.Name, Command: s.Command, Args: s.Args}); err == nil {
+			command = strings.Join(append([]string{resolved.Command}, resolved.Args...), " ")
+		}
+
+		profiles := "all"
+		if len(s.Profiles) > 0 {
+			profiles = strings.Join(s.Profiles, ",")
+		}
+
+		table.Append(s.Name, enabledStr, source, command, profiles)
+	}
+
+	return table.Render(os.Stdout)
+}
+
+// ============================================================================
+// mcp add - Add a server
+// ============================================================================
+
+var mcpAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add an MCP server",
+	Long: `Add an MCP server to the config file. Give --command to define a
+custom server; a built-in server can be added by name alone. Environment
+values are stored in the config file as given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMCPAdd,
+}
+
+func runMCPAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	servers := append([]config.MCPServer(nil), cfg.MCP.Servers...)
+	if findMCPServer(servers, name) >= 0 {
+		return fmt.Errorf("MCP server %s is already configured (remove it first to redefine it)", name)
+	}
+	if mcpAddCommand == "" && !claude.IsBuiltinServer(name) {
+		return fmt.Errorf("%s is not a built-in server; give its --command", name)
+	}
+	if mcpAddCommand == "" && (len(mcpAddArgs) > 0 || len(mcpAddEnv) > 0) {
+		return fmt.Errorf("--arg and --env need --command")
+	}
+
+	if _, err := parseEnvFlags(mcpAddEnv); err != nil {
+		return err
+	}
+
+	servers = append(servers, config.MCPServer{
+		Name:     name,
+		Enabled:  !mcpAddDisabled,
+		Command:  mcpAddCommand,
+		Args:     mcpAddArgs,
+		Env:      mcpAddEnv,
+		Profiles: mcpAddProfiles,
+	})
+	path, err := saveMCPServers(servers)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Added MCP server %s to %s\n", color.GreenString("✓"), name, path)
+	return nil
+}
+
+// ============================================================================
+// mcp remove - Remove a server
+// ============================================================================
+
+var mcpRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove an MCP server",
+	Long: `Remove an MCP server from the config file. A removed built-in server
+is no longer enabled; add it again to bring it back.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMCPRemove,
+}
+
+func runMCPRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	servers := append([]config.MCPServer(nil), cfg.MCP.Servers...)
+	i := findMCPServer(servers, name)
+	if i < 0 {
+		return fmt.Errorf("MCP server %s is not configured", name)
+	}
+
+	servers = append(servers[:i], servers[i+1:]...)
+	path, err := saveMCPServers(servers)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Removed MCP server %s from %s\n", color.GreenString("✓"), name, path)
+	return nil
+}
+
+// ============================================================================
+// mcp enable / disable - Toggle a server
+// ============================================================================
+
+var mcpEnableCmd = &cobra.Command{
+	Use:   "enable <name>",
+	Short: "Enable an MCP server",
+	Long: `Enable an MCP server for new containers. A built-in server that isn't
+configured is added. With --profile, the server is only enabled for
+containers started with one of those profiles.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setMCPServerEnabled(cmd, args[0], true)
+	},
+}
+
+var mcpDisableCmd = &cobra.Command{
+	Use:   "disable <name>",
+	Short: "Disable an MCP server",
+	Long:  `Disable an MCP server for new containers, keeping its definition.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setMCPServerEnabled(cmd, args[0], false)
+	},
+}
+
+func setMCPServerEnabled(cmd *cobra.Command, name string, enabled bool) error {
+	servers := append([]config.MCPServer(nil), cfg.MCP.Servers...)
+	i := findMCPServer(servers, name)
+	if i < 0 {
+		if !claude.IsBuiltinServer(name) {
+			return fmt.Errorf("MCP server %s is not configured (use 'frank mcp add' for custom servers)", name)
+		}
+		servers = append(servers, config.MCPServer{Name: name})
+		i = len(servers) - 1
+	}
+
+	servers[i].Enabled = enabled
+	if enabled && cmd.Flags().Changed("profile") {
+		servers[i].Profiles = mcpEnableProfile
+	}
+	path, err := saveMCPServers(servers)
+	if err != nil {
+		return err
+	}
+
+	state := "Disabled"
+	if enabled {
+		state = "Enabled"
+	}
+	fmt.Printf("%s %s MCP server %s in %s\n", color.GreenString("✓"), state, name, path)
+	return nil
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// findMCPServer returns the index of the named server, or -1
+func findMCPServer(servers []config.MCPServer, name string) int {
+	for i, s := range servers {
+		if s.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// saveMCPServers writes the server list to the config file, rolling back if
+// the result doesn't validate, and returns the file written
+func saveMCPServers(servers []config.MCPServer) (string, error) {
+	path := config.FilePath(cfgFile)
+
+	// Keep the original so an invalid result can be rolled back
+	original, readErr := os.ReadFile(path)
+
+	if err := config.SetMCPServers(path, servers); err != nil {
+		return "", err
+	}
+
+	updated, err := config.Load(path)
+	if err != nil {
+		restoreConfigFile(path, original, readErr)
+		return "", fmt.Errorf("failed to reload config: %w", err)
+	}
+	if errs := config.Validate(updated); len(errs) > 0 {
+		restoreConfigFile(path, original, readErr)
+		for _, e := range errs {
+			PrintError("%s", e.Error())
+		}
+		return "", exitcode.Errorf(exitcode.Config, "refusing to save invalid configuration")
+	}
+	return path, nil
+}
+
+// mcpServerForProfile reports whether a server applies to containers
+// started with profile
+func mcpServerForProfile(s config.MCPServer, profile string) bool {
+	if len(s.Profiles) == 0 {
+		return true
+	}
+	for _, p := range s.Profiles {
+		if strings.EqualFold(p, profile) {
+			return true
+		}
+	}
+	return false
+}
+
+// containerMCPServers returns the servers for a container started with
+// profile, disabling the ones limited to other profiles
+func containerMCPServers(servers []config.MCPServer, profile string) []claude.MCPServer {
+	var result []claude.MCPServer
+	for _, s := range servers {
+		server := claude.MCPServer{
+			Name:    s.Name,
+			Enabled: s.Enabled && mcpServerForProfile(s, profile),
+			Command: s.Command,
+			Args:    s.Args,
+		}
+		if len(s.Env) > 0 {
+			// Validated as KEY=VAL when the config is loaded
+			server.Env, _ = parseEnvFlags(s.Env)
+		}
+		result = append(result, server)
+	}
+	return result
+}
+
+// enabledMCPServerNames returns the names of the enabled servers
+func enabledMCPServerNames(servers []claude.MCPServer) []string {
+	var names []string
+	for _, s := range servers {
+		if s.Enabled {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
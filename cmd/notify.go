@@ -0,0 +1,637 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	ebtypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	frankaws "github.com/barff/frank/internal/aws"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Names of the resources 'frank notify setup' creates
+const (
+	notifyRuleName        = "frank-task-state"
+	notifyTopicName       = "frank-task-notifications"
+	notifyConnectionName  = "frank-task-notify"
+	notifyDestinationName = "frank-task-notify-slack"
+
+	// notifyRoleName is the role, created by the CDK stack, that lets the
+	// rule call the Slack API destination
+	notifyRoleName = "frank-notify"
+)
+
+// Target IDs on the rule, one per kind of destination
+const (
+	notifyTargetTopic = "frank-topic"
+	notifyTargetSNS   = "frank-sns"
+	notifyTargetSlack = "frank-slack"
+)
+
+// notifyInputPaths pull the interesting fields out of an ECS task state
+// change event for the message templates
+var notifyInputPaths = map[string]string{
+	"task":   "$.detail.taskArn",
+	"status": "$.detail.lastStatus",
+	"group":  "$.detail.group",
+	"reason": "$.detail.stoppedReason",
+}
+
+const notifyMessage = "Frank task <task> (<group>) is <status>. <reason>"
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Get notified when ECS tasks change state",
+	Long: `Get notified when Frank tasks on ECS stop (or start), even with your
+terminal closed.
+
+'frank notify setup' creates an EventBridge rule for ECS task state changes
+in the Frank cluster and delivers them by email (through an SNS topic it
+creates), to an SNS topic you already have, or to a Slack incoming webhook
+(through an EventBridge API destination).
+
+Task state events don't carry tags, so the rule matches every standalone
+task in the cluster: profile tasks, 'frank ecs run' tasks and workers. The
+service's own tasks are left out.
+
+Examples:
+  frank notify setup --email me@example.com
+  frank notify setup --slack-webhook https://hooks.slack.com/services/...
+  frank notify setup --sns-topic arn:aws:sns:us-east-1:123456789012:alerts --states STOPPED,RUNNING
+  frank notify status
+  frank notify remove`,
+}
+
+var (
+	notifyEmails       []string
+	notifySlackWebhook string
+	notifySNSTopic     string
+	notifyStates       []string
+	notifyRoleArn      string
+)
+
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+	notifyCmd.AddCommand(notifySetupCmd)
+	notifyCmd.AddCommand(notifyStatusCmd)
+	notifyCmd.AddCommand(notifyRemoveCmd)
+
+	notifyCmd.PersistentFlags().StringVar(&ecsCluster, "cluster", defaultCluster, "ECS cluster name")
+	notifyCmd.PersistentFlags().StringVar(&ecsRegion, "region", "", "AWS region (default: from AWS config)")
+
+	notifySetupCmd.Flags().StringArrayVar(&notifyEmails, "email", nil, "Email address to notify through an SNS topic (repeatable)")
+	notifySetupCmd.Flags().StringVar(&notifySlackWebhook, "slack-webhook", "", "Slack incoming webhook URL to post to")
+	notifySetupCmd.Flags().StringVar(&notifySNSTopic, "sns-topic", "", "ARN of an existing SNS topic to publish to")
+	notifySetupCmd.Flags().StringSliceVar(&notifyStates, "states", []string{"STOPPED"}, "Task states to notify about")
+	notifySetupCmd.Flags().StringVar(&notifyRoleArn, "role-arn", "", "Role the rule uses to call Slack (default: the stack's frank-notify role)")
+}
+
+// notifyClients are the AWS clients the notify commands use
+type notifyClients struct {
+	events *eventbridge.Client
+	sns    *sns.Client
+	ecs    *ecs.Client
+}
+
+func getNotifyClients(ctx context.Context) (*notifyClients, error) {
+	opts := []func(*config.LoadOptions) error{frankaws.WithRetries(), withAudit()}
+	if ecsRegion != "" {
+		opts = append(opts, config.WithRegion(ecsRegion))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return &notifyClients{
+		events: eventbridge.NewFromConfig(cfg),
+		sns:    sns.NewFromConfig(cfg),
+		ecs:    ecs.NewFromConfig(cfg),
+	}, nil
+}
+
+// ============================================================================
+// notify setup - Create or update the rule and its destinations
+// ============================================================================
+
+var notifySetupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Create or update the task state notification rule",
+	Long: `Create or update the EventBridge rule that sends ECS task state changes
+to email, an SNS topic or Slack. Running setup again replaces the rule's
+destinations with the ones given.
+
+Email addresses get a confirmation message from AWS; nothing is delivered
+to them until it's confirmed.`,
+	Args:        cobra.NoArgs,
+	Annotations: mutates("events:PutRule", "events:PutTargets"),
+	RunE:        runNotifySetup,
+}
+
+func runNotifySetup(cmd *cobra.Command, args []string) error {
+	if len(notifyEmails) == 0 && notifySlackWebhook == "" && notifySNSTopic == "" {
+		return fmt.Errorf("nothing to notify: use --email, --slack-webhook or --sns-topic")
+	}
+	if notifySlackWebhook != "" && !strings.HasPrefix(notifySlackWebhook, "https://") {
+		return fmt.Errorf("--slack-webhook must be an https:// URL")
+	}
+	states := make([]string, len(notifyStates))
+	for i, s := range notifyStates {
+		states[i] = strings.ToUpper(strings.TrimSpace(s))
+	}
+
+	ctx := context.Background()
+	clients, err := getNotifyClients(ctx)
+	if err != nil {
+		return err
+	}
+
+	clusterArn, err := notifyClusterArn(ctx, clients.ecs)
+	if err != nil {
+		return err
+	}
+	pattern, err := notifyEventPattern(clusterArn, states)
+	if err != nil {
+		return err
+	}
+
+	rule, err := clients.events.PutRule(ctx, &eventbridge.PutRuleInput{
+		Name:         aws.String(notifyRuleName),
+		EventPattern: aws.String(pattern),
+		State:        ebtypes.RuleStateEnabled,
+		Description:  aws.String(fmt.Sprintf("Frank task state notifications for cluster %s", ecsCluster)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save rule: %w", err)
+	}
+	fmt.Printf("%s Rule %s matches %s tasks in %s\n", color.GreenString("✓"), notifyRuleName, strings.Join(states, ", "), ecsCluster)
+
+	var targets []ebtypes.Target
+	snsTemplate := aws.String(`"` + notifyMessage + `"`)
+
+	if len(notifyEmails) > 0 {
+		topicArn, err := ensureNotifyTopic(ctx, clients.sns, aws.ToString(rule.RuleArn))
+		if err != nil {
+			return err
+		}
+		for _, email := range notifyEmails {
+			if _, err := clients.sns.Subscribe(ctx, &sns.SubscribeInput{
+				TopicArn: aws.String(topicArn),
+				Protocol: aws.String("email"),
+				Endpoint: aws.String(email),
+			}); err != nil {
+				return fmt.Errorf("failed to subscribe %s: %w", email, err)
+			}
+			fmt.Printf("%s Subscribed %s (confirm the email from AWS to start receiving notifications)\n", color.GreenString("✓"), email)
+		}
+		targets = append(targets, ebtypes.Target{
+			Id:               aws.String(notifyTargetTopic),
+			Arn:              aws.String(topicArn),
+			InputTransformer: &ebtypes.InputTransformer{InputPathsMap: notifyInputPaths, InputTemplate: snsTemplate},
+		})
+	}
+
+	if notifySNSTopic != "" {
+		targets = append(targets, ebtypes.Target{
+			Id:               aws.String(notifyTargetSNS),
+			Arn:              aws.String(notifySNSTopic),
+			InputTransformer: &ebtypes.InputTransformer{InputPathsMap: notifyInputPaths, InputTemplate: snsTemplate},
+		})
+		fmt.Printf("%s Publishing to %s (its access policy must allow events.amazonaws.com)\n", color.GreenString("✓"), notifySNSTopic)
+	}
+
+	if notifySlackWebhook != "" {
+		roleArn := notifyRoleArn
+		if roleArn == "" {
+			roleArn, err = stackRoleArn(clusterArn, notifyRoleName)
+			if err != nil {
+				return err
+			}
+		}
+		destinationArn, err := ensureSlackDestination(ctx, clients.events, notifySlackWebhook)
+		if err != nil {
+			return err
+		}
+		// Written out rather than marshalled, which would escape the <>
+		// around the placeholders
+		slackTemplate := `{"text": "` + notifyMessage + `"}`
+		targets = append(targets, ebtypes.Target{
+			Id:               aws.String(notifyTargetSlack),
+			Arn:              aws.String(destinationArn),
+			RoleArn:          aws.String(roleArn),
+			InputTransformer: &ebtypes.InputTransformer{InputPathsMap: notifyInputPaths, InputTemplate: aws.String(slackTemplate)},
+		})
+		fmt.Printf("%s Posting to Slack through API destination %s\n", color.GreenString("✓"), notifyDestinationName)
+	}
+
+	out, err := clients.events.PutTargets(ctx, &eventbridge.PutTargetsInput{
+		Rule:    aws.String(notifyRuleName),
+		Targets: targets,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add rule targets: %w", err)
+	}
+	if out.FailedEntryCount > 0 {
+		f := out.FailedEntries[0]
+		return fmt.Errorf("failed to add target %s: %s", aws.ToString(f.TargetId), aws.ToString(f.ErrorMessage))
+	}
+
+	// Destinations left out of this setup stop receiving notifications
+	keep := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		keep[aws.ToString(t.Id)] = true
+	}
+	existing, err := listNotifyTargets(ctx, clients.events)
+	if err != nil {
+		return err
+	}
+	var stale []string
+	for _, t := range existing {
+		if !keep[aws.ToString(t.Id)] {
+			stale = append(stale, aws.ToString(t.Id))
+		}
+	}
+	if len(stale) > 0 {
+		if _, err := clients.events.RemoveTargets(ctx, &eventbridge.RemoveTargetsInput{
+			Rule: aws.String(notifyRuleName),
+			Ids:  stale,
+		}); err != nil {
+			return fmt.Errorf("failed to remove old rule targets: %w", err)
+		}
+		PrintVerbose("Removed targets: %s", strings.Join(stale, ", "))
+	}
+
+	fmt.Println("\nCheck it with: frank notify status")
+	return nil
+}
+
+// notifyClusterArn returns the ARN of the --cluster cluster
+func notifyClusterArn(ctx context.Context, client *ecs.Client) (string, error) {
+	out, err := client.DescribeClusters(ctx, &ecs.DescribeClustersInput{
+		Clusters: []string{ecsCluster},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe cluster: %w", err)
+	}
+	if len(out.Clusters) == 0 {
+		return "", fmt.Errorf("cluster %s not found", ecsCluster)
+	}
+	return aws.ToString(out.Clusters[0].ClusterArn), nil
+}
+
+// notifyEventPattern matches state changes of the cluster's standalone
+// tasks. Service tasks are in group service:<name>, RunTask ones in
+// family:<name>.
+func notifyEventPattern(clusterArn string, states []string) (string, error) {
+	pattern := map[string]interface{}{
+		"source":      []string{"aws.ecs"},
+		"detail-type": []string{"ECS Task State Change"},
+		"detail": map[string]interface{}{
+			"clusterArn": []string{clusterArn},
+			"lastStatus": states,
+			"group":      []map[string]string{{"prefix": "family:"}},
+		},
+	}
+	data, err := json.Marshal(pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode event pattern: %w", err)
+	}
+	return string(data), nil
+}
+
+// ensureNotifyTopic creates frank's SNS topic, or returns the existing one,
+// and lets the rule publish to it
+func ensureNotifyTopic(ctx context.Context, client *sns.Client, ruleArn string) (string, error) {
+	topic, err := client.CreateTopic(ctx, &sns.CreateTopicInput{
+		Name: aws.String(notifyTopicName),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create SNS topic: %w", err)
+	}
+	topicArn := aws.ToString(topic.TopicArn)
+
+	policy, err := json.Marshal(map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{{
+			"Sid":       "FrankTaskState",
+			"Effect":    "Allow",
+			"Principal": map[string]string{"Service": "events.amazonaws.com"},
+			"Action":    "sns:Publish",
+			"Resource":  topicArn,
+			"Condition": map[string]interface{}{
+				"ArnEquals": map[string]string{"aws:SourceArn": ruleArn},
+			},
+		}},
+	})
+	if err != nil {
+		return "", err
+	}
+	if _, err := client.SetTopicAttributes(ctx, &sns.SetTopicAttributesInput{
+		TopicArn:       aws.String(topicArn),
+		AttributeName:  aws.String("Policy"),
+		AttributeValue: aws.String(string(policy)),
+	}); err != nil {
+		return "", fmt.Errorf("failed to set SNS topic policy: %w", err)
+	}
+	return topicArn, nil
+}
+
+// ensureSlackDestination creates or updates the API destination that posts
+// to webhook, with the connection it needs. Slack webhooks don't take
+// credentials, so the connection carries a placeholder API key.
+func ensureSlackDestination(ctx context.Context, client *eventbridge.Client, webhook string) (string, error) {
+	var connectionArn string
+	conn, err := client.DescribeConnection(ctx, &eventbridge.DescribeConnectionInput{
+		Name: aws.String(notifyConnectionName),
+	})
+	switch {
+	case err == nil:
+		connectionArn = aws.ToString(conn.ConnectionArn)
+	case isEventBridgeNotFound(err):
+		created, err := client.CreateConnection(ctx, &eventbridge.CreateConnectionInput{
+			Name:              aws.String(notifyConnectionName),
+			Description:       aws.String("Frank task notifications to Slack"),
+			AuthorizationType: ebtypes.ConnectionAuthorizationTypeApiKey,
+			AuthParameters: &ebtypes.CreateConnectionAuthRequestParameters{
+				ApiKeyAuthParameters: &ebtypes.CreateConnectionApiKeyAuthRequestParameters{
+					ApiKeyName:  aws.String("X-Frank-Notify"),
+					ApiKeyValue: aws.String("frank"),
+				},
+			},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to create connection: %w", err)
+		}
+		connectionArn = aws.ToString(created.ConnectionArn)
+	default:
+		return "", fmt.Errorf("failed to describe connection: %w", err)
+	}
+
+	_, err = client.DescribeApiDestination(ctx, &eventbridge.DescribeApiDestinationInput{
+		Name: aws.String(notifyDestinationName),
+	})
+	if isEventBridgeNotFound(err) {
+		created, err := client.CreateApiDestination(ctx, &eventbridge.CreateApiDestinationInput{
+			Name:                         aws.String(notifyDestinationName),
+			Description:                  aws.String("Slack webhook for Frank task notifications"),
+			ConnectionArn:                aws.String(connectionArn),
+			InvocationEndpoint:           aws.String(webhook),
+			HttpMethod:                   ebtypes.ApiDestinationHttpMethodPost,
+			InvocationRateLimitPerSecond: aws.Int32(1),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to create API destination: %w", err)
+		}
+		return aws.ToString(created.ApiDestinationArn), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to describe API destination: %w", err)
+	}
+
+	updated, err := client.UpdateApiDestination(ctx, &eventbridge.UpdateApiDestinationInput{
+		Name:                         aws.String(notifyDestinationName),
+		ConnectionArn:                aws.String(connectionArn),
+		InvocationEndpoint:           aws.String(webhook),
+		HttpMethod:                   ebtypes.ApiDestinationHttpMethodPost,
+		InvocationRateLimitPerSecond: aws.Int32(1),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to update API destination: %w", err)
+	}
+	return aws.ToString(updated.ApiDestinationArn), nil
+}
+
+// listNotifyTargets returns the rule's targets
+func listNotifyTargets(ctx context.Context, client *eventbridge.Client) ([]ebtypes.Target, error) {
+	var targets []ebtypes.Target
+	var next *string
+	for {
+		out, err := client.ListTargetsByRule(ctx, &eventbridge.ListTargetsByRuleInput{
+			Rule:      aws.String(notifyRuleName),
+			NextToken: next,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list rule targets: %w", err)
+		}
+		targets = append(targets, out.Targets...)
+		if out.NextToken == nil {
+			return targets, nil
+		}
+		next = out.NextToken
+	}
+}
+
+func isEventBridgeNotFound(err error) bool {
+	var notFound *ebtypes.ResourceNotFoundException
+	return errors.As(err, &notFound)
+}
+
+// ============================================================================
+// notify status - Show the rule and where it delivers
+// ============================================================================
+
+var notifyStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the task state notification rule and its destinations",
+	Args:  cobra.NoArgs,
+	RunE:  runNotifyStatus,
+}
+
+func runNotifyStatus(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	clients, err := getNotifyClients(ctx)
+	if err != nil {
+		return err
+	}
+
+	rule, err := clients.events.DescribeRule(ctx, &eventbridge.DescribeRuleInput{
+		Name: aws.String(notifyRuleName),
+	})
+	if isEventBridgeNotFound(err) {
+		fmt.Println("Task notifications aren't set up.")
+		fmt.Println("Set them up with: frank notify setup --email <address>")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to describe rule: %w", err)
+	}
+
+	state := color.GreenString(string(rule.State))
+	if rule.State != ebtypes.RuleStateEnabled {
+		state = color.YellowString(string(rule.State))
+	}
+	fmt.Printf("Rule:    %s (%s)\n", notifyRuleName, state)
+	fmt.Printf("States:  %s\n", strings.Join(notifyPatternStates(aws.ToString(rule.EventPattern)), ", "))
+
+	targets, err := listNotifyTargets(ctx, clients.events)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		fmt.Printf("Targets: %s\n", color.YellowString("none"))
+		return nil
+	}
+
+	fmt.Println("Targets:")
+	for _, t := range targets {
+		arn := aws.ToString(t.Arn)
+		switch aws.ToString(t.Id) {
+		case notifyTargetTopic:
+			fmt.Printf("  Email via %s\n", shortARN(arn))
+			subs, err := clients.sns.ListSubscriptionsByTopic(ctx, &sns.ListSubscriptionsByTopicInput{
+				TopicArn: aws.String(arn),
+			})
+			if err != nil {
+				PrintError("Failed to list subscriptions: %v", err)
+				continue
+			}
+			for _, s := range subs.Subscriptions {
+				fmt.Printf("    %s %s\n", aws.ToString(s.Endpoint), formatSubscription(s))
+			}
+		case notifyTargetSlack:
+			dest, err := clients.events.DescribeApiDestination(ctx, &eventbridge.DescribeApiDestinationInput{
+				Name: aws.String(notifyDestinationName),
+			})
+			if err != nil {
+				fmt.Printf("  Slack %s\n", color.RedString("(API destination missing)"))
+				continue
+			}
+			fmt.Printf("  Slack %s (%s)\n", redactWebhook(aws.ToString(dest.InvocationEndpoint)), dest.ApiDestinationState)
+		default:
+			fmt.Printf("  SNS %s\n", arn)
+		}
+	}
+	return nil
+}
+
+// notifyPatternStates returns the task states a rule's event pattern matches
+func notifyPatternStates(pattern string) []string {
+	var p struct {
+		Detail struct {
+			LastStatus []string `json:"lastStatus"`
+		} `json:"detail"`
+	}
+	if err := json.Unmarshal([]byte(pattern), &p); err != nil || len(p.Detail.LastStatus) == 0 {
+		return []string{"any"}
+	}
+	return p.Detail.LastStatus
+}
+
+// formatSubscription shows whether an SNS subscription is confirmed
+func formatSubscription(s snstypes.Subscription) string {
+	if aws.ToString(s.SubscriptionArn) == "PendingConfirmation" {
+		return color.YellowString("(pending confirmation)")
+	}
+	return color.GreenString("(confirmed)")
+}
+
+// redactWebhook hides the secret part of a webhook URL
+func redactWebhook(url string) string {
+	if i := strings.Index(url, "/services/"); i >= 0 {
+		return url[:i] + "/services/..."
+	}
+	return url
+}
+
+// ============================================================================
+// notify remove - Delete the rule and what setup created
+// ============================================================================
+
+var notifyRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Delete the task state notification rule",
+	Long: `Delete the notification rule, frank's SNS topic with its subscriptions,
+and the Slack API destination and connection. Topics given with --sns-topic
+are left alone.`,
+	Args:        cobra.NoArgs,
+	Annotations: mutates("events:RemoveTargets", "events:DeleteRule"),
+	RunE:        runNotifyRemove,
+}
+
+func runNotifyRemove(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	clients, err := getNotifyClients(ctx)
+	if err != nil {
+		return err
+	}
+
+	removed := false
+	targets, err := listNotifyTargets(ctx, clients.events)
+	if err != nil && !isEventBridgeNotFound(err) {
+		return err
+	}
+
+	var topicArn string
+	if len(targets) > 0 {
+		ids := make([]string, len(targets))
+		for i, t := range targets {
+			ids[i] = aws.ToString(t.Id)
+			if ids[i] == notifyTargetTopic {
+				topicArn = aws.ToString(t.Arn)
+			}
+		}
+		if _, err := clients.events.RemoveTargets(ctx, &eventbridge.RemoveTargetsInput{
+			Rule: aws.String(notifyRuleName),
+			Ids:  ids,
+		}); err != nil {
+			return fmt.Errorf("failed to remove rule targets: %w", err)
+		}
+	}
+
+	_, err = clients.events.DeleteRule(ctx, &eventbridge.DeleteRuleInput{
+		Name: aws.String(notifyRuleName),
+	})
+	if err != nil && !isEventBridgeNotFound(err) {
+		return fmt.Errorf("failed to delete rule: %w", err)
+	}
+	if err == nil {
+		fmt.Printf("%s Deleted rule %s\n", color.GreenString("✓"), notifyRuleName)
+		removed = true
+	}
+
+	if topicArn != "" {
+		if _, err := clients.sns.DeleteTopic(ctx, &sns.DeleteTopicInput{TopicArn: aws.String(topicArn)}); err != nil {
+			return fmt.Errorf("failed to delete SNS topic: %w", err)
+		}
+		fmt.Printf("%s Deleted SNS topic %s\n", color.GreenString("✓"), notifyTopicName)
+		removed = true
+	}
+
+	_, err = clients.events.DeleteApiDestination(ctx, &eventbridge.DeleteApiDestinationInput{
+		Name: aws.String(notifyDestinationName),
+	})
+	if err != nil && !isEventBridgeNotFound(err) {
+		return fmt.Errorf("failed to delete API destination: %w", err)
+	}
+	if err == nil {
+		fmt.Printf("%s Deleted API destination %s\n", color.GreenString("✓"), notifyDestinationName)
+		removed = true
+	}
+
+	_, err = clients.events.DeleteConnection(ctx, &eventbridge.DeleteConnectionInput{
+		Name: aws.String(notifyConnectionName),
+	})
+	if err != nil && !isEventBridgeNotFound(err) {
+		return fmt.Errorf("failed to delete connection: %w", err)
+	}
+	if err == nil {
+		fmt.Printf("%s Deleted connection %s\n", color.GreenString("✓"), notifyConnectionName)
+		removed = true
+	}
+
+	if !removed {
+		fmt.Println("Task notifications aren't set up.")
+	}
+	return nil
+}
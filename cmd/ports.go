@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/barff/frank/internal/config"
+	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/render"
+	"github.com/barff/frank/internal/terminal"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var portsCmd = &cobra.Command{
+	Use:   "ports",
+	Short: "Inspect and manage host port leases",
+	Long: `Inspect and manage the host port ranges reserved for frank containers.
+
+Each container gets four consecutive host ports (web, claude, bash, status).
+Allocations are stored in ports.json in the frank config directory so that
+stopped containers keep their ports and can be resumed without collisions.
+A lease is renewed whenever 'frank start' sees its container and expires
+after 7 days otherwise.
+
+Examples:
+  frank ports list                 # Show all leases
+  frank ports release frank-dev-1  # Free a container's ports
+  frank ports release 8084         # Free the range containing a port
+  frank ports release --stale      # Free leases whose container no longer exists`,
+}
+
+var (
+	portsReleaseStale bool
+	portsReleaseAll   bool
+)
+
+func init() {
+	rootCmd.AddCommand(portsCmd)
+	portsCmd.AddCommand(portsListCmd)
+	portsCmd.AddCommand(portsReleaseCmd)
+
+	portsReleaseCmd.Flags().BoolVar(&portsReleaseStale, "stale", false, "Release expired leases and leases whose container no longer exists")
+	portsReleaseCmd.Flags().BoolVar(&portsReleaseAll, "all", false, "Release every lease")
+}
+
+// ============================================================================
+// ports list - Show port leases
+// ============================================================================
+
+var portsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List port leases",
+	RunE:  runPortsList,
+}
+
+func runPortsList(cmd *cobra.Command, args []string) error {
+	registry, err := terminal.LoadPortRegistry(terminal.RegistryPath(config.GetConfigDir()))
+	if err != nil {
+		return err
+	}
+
+	if len(registry.Leases) == 0 {
+		fmt.Println("No port leases")
+		return nil
+	}
+
+	states := containerStates()
+
+	table := render.NewTable(render.Options{},
+		render.Column{Header: "PORTS"},
+		render.Column{Header: "CONTAINER"},
+		render.Column{Header: "STATE"},
+		render.Column{Header: "ALLOCATED"},
+		render.Column{Header: "EXPIRES"},
+	)
+
+	now := time.Now()
+	for _, l := range registry.Leases {
+		expires := l.ExpiresAt.Format("2006-01-02 15:04")
+		if l.Expired(now) {
+			expires = color.RedString("expired")
+		}
+		table.Append(
+			fmt.Sprintf("%d-%d", l.Port, l.Port+terminal.PortsPerContainer-1),
+			l.Container,
+			formatLeaseState(states, l.Container),
+			l.AllocatedAt.Format("2006-01-02 15:04"),
+			expires,
+		)
+	}
+
+	return table.Render(os.Stdout)
+}
+
+// ============================================================================
+// ports release - Free port leases
+// ============================================================================
+
+var portsReleaseCmd = &cobra.Command{
+	Use:   "release [container-or-port...]",
+	Short: "Release port leases",
+	Long: `Release the port leases of containers, given by name or by any port in
+their range. Releasing a lease does not stop the container; if it is still
+running its ports are in use and will be skipped regardless.`,
+	RunE: runPortsRelease,
+}
+
+func runPortsRelease(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !portsReleaseStale && !portsReleaseAll {
+		return fmt.Errorf("specify containers or ports to release, or use --stale or --all")
+	}
+
+	registry, err := terminal.LoadPortRegistry(terminal.RegistryPath(config.GetConfigDir()))
+	if err != nil {
+		return err
+	}
+
+	var released []terminal.PortLease
+	switch {
+	case portsReleaseAll:
+		released = registry.Leases
+		registry.Leases = nil
+	case portsReleaseStale:
+		released = registry.Prune(time.Now())
+		states := containerStates()
+		if states == nil {
+			return fmt.Errorf("cannot check for removed containers without a container runtime")
+		}
+		for _, l := range append([]terminal.PortLease{}, registry.Leases...) {
+			if _, exists := states[l.Container]; !exists {
+				registry.Release(l.Container)
+				released = append(released, l)
+			}
+		}
+	}
+
+	for _, arg := range args {
+		l, ok := registry.Release(arg)
+		if !ok {
+			PrintError("No lease found for %s", arg)
+			continue
+		}
+		released = append(released, l)
+	}
+
+	if len(released) == 0 {
+		fmt.Println("No leases released")
+		return nil
+	}
+
+	if err := registry.Save(); err != nil {
+		return err
+	}
+
+	for _, l := range released {
+		fmt.Printf("%s Released %d-%d (%s)\n", color.GreenString("✓"),
+			l.Port, l.Port+terminal.PortsPerContainer-1, l.Container)
+	}
+	return nil
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// containerStates maps frank container names to their runtime status. It
+// returns nil when no runtime is available.
+func containerStates() map[string]string {
+	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
+	if err != nil {
+		PrintVerbose("Warning: failed to detect container runtime: %v", err)
+		return nil
+	}
+
+	containers, err := runtime.ListContainers(container.ContainerFilter{
+		All:        true,
+		NamePrefix: "frank-",
+	})
+	if err != nil {
+		PrintVerbose("Warning: failed to list containers: %v", err)
+		return nil
+	}
+
+	states := make(map[string]string)
+	for _, c := range containers {
+		states[c.Name] = c.Status
+	}
+	return states
+}
+
+func formatLeaseState(states map[string]string, name string) string {
+	if states == nil {
+		return "-"
+	}
+	status, ok := states[name]
+	if !ok {
+		return color.YellowString("removed")
+	}
+	if state := strings.Fields(containerState(status)); len(state) > 0 {
+		return formatStatus(state[0])
+	}
+	return status
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/barff/frank/internal/analytics"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/barff/frank/internal/git"
+	"github.com/barff/frank/internal/github"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var prCmd = &cobra.Command{
+	Use:   "pr",
+	Short: "Work with GitHub pull requests",
+	Long:  `Create GitHub pull requests from frank worktrees using the stored GitHub token.`,
+}
+
+var prCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a pull request from the current branch",
+	Long: `Push the current branch and open a GitHub pull request for it.
+
+The repository is the current directory, or a container's worktree with
+--container. Inside a frank container, run it from /workspace; the token is
+read from GH_TOKEN.
+
+The title defaults to the last commit subject. The body comes from --body,
+--body-file, the github.prTemplate config file, or the repository's pull
+request template, in that order, and otherwise lists the branch's commits.
+Templates can use {{commits}} and {{branch}} placeholders.
+
+Reviewers from github.reviewers in the config are requested automatically;
+--reviewer adds more. Teams are written as org/team-slug.
+
+Examples:
+  frank pr create
+  frank pr create --container frank-dev-1 --draft
+  frank pr create --title "Fix login redirect" --reviewer alice`,
+	RunE: runPRCreate,
+}
+
+var (
+	prContainer string
+	prTitle     string
+	prBody      string
+	prBodyFile  string
+	prBase      string
+	prDraft     bool
+	prReviewers []string
+	prNoPush    bool
+)
+
+func init() {
+	rootCmd.AddCommand(prCmd)
+	prCmd.AddCommand(prCreateCmd)
+
+	prCreateCmd.Flags().StringVarP(&prContainer, "container", "c", "", "Use this container's worktree instead of the current directory")
+	prCreateCmd.Flags().StringVarP(&prTitle, "title", "t", "", "Pull request title (default: last commit subject)")
+	prCreateCmd.Flags().StringVarP(&prBody, "body", "b", "", "Pull request body")
+	prCreateCmd.Flags().StringVar(&prBodyFile, "body-file", "", "Read the pull request body from a file")
+	prCreateCmd.Flags().StringVar(&prBase, "base", "", "Base branch (default: repository default branch)")
+	prCreateCmd.Flags().BoolVar(&prDraft, "draft", false, "Open as a draft pull request")
+	prCreateCmd.Flags().StringSliceVarP(&prReviewers, "reviewer", "r", nil, "Request a review from a user or org/team (repeatable)")
+	prCreateCmd.Flags().BoolVar(&prNoPush, "no-push", false, "Don't push the branch first")
+}
+
+func runPRCreate(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	token := GetGitHubToken()
+	if token == "" {
+		return exitcode.Errorf(exitcode.Auth, "no GitHub token configured (run 'frank auth github')")
+	}
+
+	dir := "."
+	if prContainer != "" {
+		dir = git.NewWorktreeManager(cfg.Git.WorktreeBase).GetPath(prContainer)
+		if _, err := os.Stat(dir); err != nil {
+			return fmt.Errorf("no worktree found for container %s", prContainer)
+		}
+	}
+
+	remoteURL, err := gitOutput(dir, "remote", "get-url", "origin")
+	if err != nil {
+		return fmt.Errorf("failed to get origin remote: %w", err)
+	}
+	if git.DetectProvider(remoteURL) != git.ProviderGitHub {
+		return fmt.Errorf("origin is not a GitHub repository: %s", remoteURL)
+	}
+	owner, repo, err := github.ParseRepo(remoteURL)
+	if err != nil {
+		return err
+	}
+
+	branch, err := gitOutput(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to determine current branch: %w", err)
+	}
+	if branch == "HEAD" {
+		return fmt.Errorf("HEAD is detached; check out a branch first")
+	}
+
+	client := github.NewClient(token)
+
+	base := prBase
+	if base == "" {
+		base, err = client.DefaultBranch(ctx, owner, repo)
+		if err != nil {
+			return err
+		}
+	}
+	if branch == base {
+		return fmt.Errorf("current branch is the base branch %q; create a feature branch first", base)
+	}
+
+	if !prNoPush {
+		fmt.Printf("Pushing %s to origin...\n", branch)
+		push := exec.Command("git", "-C", dir, "push", "-u", "origin", branch)
+		push.Stdout = os.Stdout
+		push.Stderr = os.Stderr
+		if err := push.Run(); err != nil {
+			return fmt.Errorf("failed to push branch: %w", err)
+		}
+	}
+
+	title := prTitle
+	if title == "" {
+		title, err = gitOutput(dir, "log", "-1", "--format=%s")
+		if err != nil {
+			return fmt.Errorf("failed to read last commit: %w", err)
+		}
+	}
+
+	body, err := prBodyText(dir, branch, base)
+	if err != nil {
+		return err
+	}
+
+	pr, err := client.CreatePullRequest(ctx, owner, repo, github.PullRequestInput{
+		Title: title,
+		Head:  branch,
+		Base:  base,
+		Body:  body,
+		Draft: prDraft,
+	})
+	if err != nil {
+		return err
+	}
+
+	reviewers := append(append([]string{}, cfg.GitHub.Reviewers...), prReviewers...)
+	if len(reviewers) > 0 {
+		if err := client.RequestReviewers(ctx, owner, repo, pr.Number, reviewers); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		} else {
+			PrintVerbose("Requested reviews from: %s", strings.Join(reviewers, ", "))
+		}
+	}
+
+	recordAnalytics(analytics.Record{
+		Type:    analytics.TypeOutcome,
+		Session: prContainer,
+		Repo:    owner + "/" + repo,
+		Branch:  branch,
+		Outcome: analytics.OutcomePRCreated,
+		Detail:  pr.HTMLURL,
+	})
+
+	fmt.Printf("%s Created pull request #%d\n", color.GreenString("✓"), pr.Number)
+	fmt.Println(color.CyanString(pr.HTMLURL))
+	return nil
+}
+
+// prTemplatePaths are where GitHub looks for a repository's pull request template
+var prTemplatePaths = []string{
+	".github/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	"PULL_REQUEST_TEMPLATE.md",
+	"docs/pull_request_template.md",
+}
+
+// prBodyText picks the pull request body from flags, templates, or the commit list
+func prBodyText(dir, branch, base string) (string, error) {
+	if prBody != "" {
+		return prBody, nil
+	}
+	if prBodyFile != "" {
+		data, err := os.ReadFile(prBodyFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read body file: %w", err)
+		}
+		return string(data), nil
+	}
+
+	commits, _ := gitOutput(dir, "log", "--format=- %s", "--reverse", "origin/"+base+"..HEAD")
+
+	template := ""
+	if cfg.GitHub.PRTemplate != "" {
+		data, err := os.ReadFile(cfg.GitHub.PRTemplate)
+		if err != nil {
+			return "", fmt.Errorf("failed to read github.prTemplate: %w", err)
+		}
+		template = string(data)
+	} else {
+		for _, p := range prTemplatePaths {
+			if data, err := os.ReadFile(filepath.Join(dir, p)); err == nil {
+				template = string(data)
+				break
+			}
+		}
+	}
+
+	if template == "" {
+		if commits == "" {
+			return "", nil
+		}
+		return "## Commits\n\n" + commits + "\n", nil
+	}
+
+	return strings.NewReplacer("{{commits}}", commits, "{{branch}}", branch).Replace(template), nil
+}
+
+// gitOutput runs git in dir and returns its trimmed stdout
+func gitOutput(dir string, args ...string) (string, error) {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
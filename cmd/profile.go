@@ -1,19 +1,31 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"regexp"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/barff/frank/internal/agent"
+	frankaws "github.com/barff/frank/internal/aws"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/barff/frank/internal/git"
+	"github.com/barff/frank/internal/github"
 	"github.com/barff/frank/internal/profile"
+	"github.com/barff/frank/internal/render"
 	"github.com/fatih/color"
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var profileCmd = &cobra.Command{
@@ -27,8 +39,12 @@ as ECS tasks with their own URL paths.
 Examples:
   frank profile list                           # List all profiles
   frank profile add enkai --repo https://github.com/org/enkai.git
+  frank profile create                         # Create a profile interactively
   frank profile show enkai                     # Show profile details
-  frank profile remove enkai                   # Remove a profile`,
+  frank profile remove enkai                   # Remove a profile
+  frank profile export > profiles.yaml         # Share profiles with a team
+  frank profile import profiles.yaml           # Add profiles from a file
+  frank profile discover --org myorg           # Create profiles for an org's repos`,
 }
 
 // Flags for profile add
@@ -37,6 +53,28 @@ var (
 	profileAddBranch      string
 	profileAddDescription string
 	profileAddURL         string
+	profileAddAgent       string
+	profileAddModel       string
+	profileAddProvider    string
+	profileAddEndpoint    string
+	profileAddPrewarm     int
+	profileAddBootTimeout time.Duration
+	profileAddHealthPath  string
+	profileAddRecord      bool
+	profileAddInteractive bool
+)
+
+// Flags for profile list
+var profileListOutput render.Options
+
+// Flags for profile export, import and discover
+var (
+	profileExportOutput     string
+	profileImportOverwrite  bool
+	profileDiscoverOrg      string
+	profileDiscoverFilter   string
+	profileDiscoverArchived bool
+	profileDiscoverForks    bool
 )
 
 // SSM parameter name for profiles
@@ -48,16 +86,38 @@ func init() {
 	// Add subcommands
 	profileCmd.AddCommand(profileListCmd)
 	profileCmd.AddCommand(profileAddCmd)
+	profileCmd.AddCommand(profileCreateCmd)
 	profileCmd.AddCommand(profileShowCmd)
 	profileCmd.AddCommand(profileRemoveCmd)
 	profileCmd.AddCommand(profileSyncCmd)
+	profileCmd.AddCommand(profileExportCmd)
+	profileCmd.AddCommand(profileImportCmd)
+	profileCmd.AddCommand(profileDiscoverCmd)
 
 	// Add command flags
+	render.AddFlags(profileListCmd, &profileListOutput)
+
 	profileAddCmd.Flags().StringVarP(&profileAddRepo, "repo", "r", "", "Git repository URL (required)")
 	profileAddCmd.Flags().StringVarP(&profileAddBranch, "branch", "b", "main", "Git branch")
 	profileAddCmd.Flags().StringVarP(&profileAddDescription, "description", "d", "", "Profile description")
 	profileAddCmd.Flags().StringVarP(&profileAddURL, "url", "u", "", "Deployed site URL")
-	profileAddCmd.MarkFlagRequired("repo")
+	profileAddCmd.Flags().StringVar(&profileAddAgent, "agent", "", "Coding agent: claude or codex (default: claude)")
+	profileAddCmd.Flags().StringVar(&profileAddModel, "model", "", "Model for the agent (default: the agent's own default)")
+	profileAddCmd.Flags().StringVar(&profileAddProvider, "provider", "", "Model provider: "+strings.Join(agent.Providers(), ", ")+" (default: the agent's own)")
+	profileAddCmd.Flags().StringVar(&profileAddEndpoint, "endpoint", "", "Provider endpoint, e.g. an Ollama or Azure OpenAI URL")
+	profileAddCmd.Flags().IntVar(&profileAddPrewarm, "prewarm-workers", 0, "Worktrees 'frank ecs prewarm' creates (default: 4)")
+	profileAddCmd.Flags().DurationVar(&profileAddBootTimeout, "boot-timeout", 0, "How long 'frank ecs start' waits for the task to turn healthy (default: 5m)")
+	profileAddCmd.Flags().StringVar(&profileAddHealthPath, "health-path", "", "ALB health check, /path or :port/path (default: /health on port 7683)")
+	profileAddCmd.Flags().BoolVar(&profileAddRecord, "record", false, "Record the agent terminal and upload it to S3 (see 'frank recordings')")
+	profileAddCmd.Flags().BoolVarP(&profileAddInteractive, "interactive", "i", false, "Walk through the profile settings interactively")
+
+	profileExportCmd.Flags().StringVarP(&profileExportOutput, "output", "o", "", "Write to a file instead of stdout")
+	profileImportCmd.Flags().BoolVar(&profileImportOverwrite, "overwrite", false, "Replace profiles that already exist")
+	profileDiscoverCmd.Flags().StringVar(&profileDiscoverOrg, "org", "", "GitHub organization to list repositories from (required)")
+	profileDiscoverCmd.Flags().StringVar(&profileDiscoverFilter, "filter", "", "Only list repositories whose name contains this text")
+	profileDiscoverCmd.Flags().BoolVar(&profileDiscoverArchived, "archived", false, "Include archived repositories")
+	profileDiscoverCmd.Flags().BoolVar(&profileDiscoverForks, "forks", false, "Include forks")
+	profileDiscoverCmd.MarkFlagRequired("org")
 }
 
 // ============================================================================
@@ -77,7 +137,7 @@ func runProfileList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load profiles: %w", err)
 	}
 
-	if len(config.Profiles) == 0 {
+	if len(config.Profiles) == 0 && !profileListOutput.Structured() {
 		fmt.Println("No profiles configured.")
 		fmt.Printf("\nAdd a profile with: frank profile add <name> --repo <url>\n")
 		fmt.Printf("Profiles are stored in: %s\n", profile.GetProfilesPath())
@@ -92,34 +152,21 @@ func runProfileList(cmd *cobra.Command, args []string) error {
 	sort.Strings(names)
 
 	// Display as table
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"PROFILE", "REPO", "BRANCH", "DESCRIPTION"})
-	table.SetBorder(false)
-	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-	table.SetAlignment(tablewriter.ALIGN_LEFT)
-	table.SetCenterSeparator("")
-	table.SetColumnSeparator("")
-	table.SetRowSeparator("")
-	table.SetHeaderLine(false)
-	table.SetTablePadding("  ")
-	table.SetNoWhiteSpace(true)
-
+	table := render.NewTable(profileListOutput,
+		render.Column{Header: "PROFILE", Key: "name"},
+		render.Column{Header: "REPO", MaxWidth: 50, TruncateLeft: true},
+		render.Column{Header: "BRANCH", Hide: 2},
+		render.Column{Header: "DESCRIPTION", MaxWidth: 60, Hide: 1},
+	)
 	for _, name := range names {
 		p := config.Profiles[name]
 		branch := p.Branch
 		if branch == "" {
 			branch = "main"
 		}
-		// Truncate repo URL for display
-		repo := p.Repo
-		if len(repo) > 50 {
-			repo = "..." + repo[len(repo)-47:]
-		}
-		table.Append([]string{name, repo, branch, p.Description})
+		table.Append(name, p.Repo, branch, p.Description)
 	}
-
-	table.Render()
-	return nil
+	return table.Render(os.Stdout)
 }
 
 // ============================================================================
@@ -131,12 +178,31 @@ var profileAddCmd = &cobra.Command{
 	Short: "Add a new profile",
 	Long: `Add a new Frank profile with the specified repository configuration.
 
-The profile name will be used in the URL path: frank.digitaldevops.io/<name>/`,
-	Args: cobra.ExactArgs(1),
+The profile name will be used in the URL path: frank.digitaldevops.io/<name>/
+
+Use --interactive (or 'frank profile create') to be prompted for each setting.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runProfileAdd,
 }
 
 func runProfileAdd(cmd *cobra.Command, args []string) error {
+	if profileAddInteractive {
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+		return runProfileWizard(name)
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("profile name required (or use --interactive)")
+	}
+	if profileAddRepo == "" {
+		return fmt.Errorf("--repo is required (or use --interactive)")
+	}
+	if err := profile.ValidateAgent(profileAddAgent); err != nil {
+		return err
+	}
 	name := args[0]
 
 	// Check if profile already exists
@@ -151,13 +217,33 @@ func runProfileAdd(cmd *cobra.Command, args []string) error {
 		Branch:      profileAddBranch,
 		Description: profileAddDescription,
 		SiteURL:     profileAddURL,
+		Agent:       profileAddAgent,
+		Model:       profileAddModel,
+		Provider:    profileAddProvider,
+		Endpoint:    profileAddEndpoint,
+
+		PrewarmWorkers: profileAddPrewarm,
+		BootTimeout:    profileAddBootTimeout,
+		HealthPath:     profileAddHealthPath,
+		Record:         profileAddRecord,
+	}
+	if err := agent.Validate(p.AgentConfig()); err != nil {
+		return err
+	}
+	if err := p.ValidateBoot(); err != nil {
+		return err
 	}
 
 	if err := profile.AddProfile(p); err != nil {
 		return fmt.Errorf("failed to add profile: %w", err)
 	}
 
-	fmt.Printf("%s Profile %q saved\n\n", color.GreenString("✓"), name)
+	printProfileSaved(p)
+	return nil
+}
+
+func printProfileSaved(p *profile.Profile) {
+	fmt.Printf("%s Profile %q saved\n\n", color.GreenString("✓"), p.Name)
 	fmt.Printf("  Repo:        %s\n", p.Repo)
 	fmt.Printf("  Branch:      %s\n", p.Branch)
 	if p.Description != "" {
@@ -166,13 +252,343 @@ func runProfileAdd(cmd *cobra.Command, args []string) error {
 	if p.SiteURL != "" {
 		fmt.Printf("  Site URL:    %s\n", p.SiteURL)
 	}
+	if p.Agent != "" {
+		fmt.Printf("  Agent:       %s\n", p.Agent)
+	}
+	if p.Model != "" {
+		fmt.Printf("  Model:       %s\n", p.Model)
+	}
+	if p.Provider != "" {
+		fmt.Printf("  Provider:    %s\n", p.Provider)
+	}
+	if p.Endpoint != "" {
+		fmt.Printf("  Endpoint:    %s\n", p.Endpoint)
+	}
+	if p.PrewarmWorkers > 0 {
+		fmt.Printf("  Prewarm:     %d workers\n", p.PrewarmWorkers)
+	}
+	if p.BootTimeout > 0 {
+		fmt.Printf("  Boot:        %s timeout\n", p.BootTimeout)
+	}
+	if p.HealthPath != "" {
+		fmt.Printf("  Health:      %s\n", p.HealthPath)
+	}
+	if p.Record {
+		fmt.Printf("  Recording:   on\n")
+	}
 	fmt.Println()
-	fmt.Printf("Start with: frank ecs start %s\n", name)
-	fmt.Printf("URL will be: https://frank.digitaldevops.io/%s/\n", name)
+	fmt.Printf("Start with: frank ecs start %s\n", p.Name)
+	fmt.Printf("URL will be: https://%s/%s/\n", frankDomain(), p.Name)
+}
 
+// ============================================================================
+// profile create - Create a profile interactively
+// ============================================================================
+
+var profileCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create a profile interactively",
+	Long: `Walk through creating a profile: pick the repository (from your GitHub
+repositories when a token is configured), the branch (listed from the
+remote), the coding agent and model, and a description. The resulting YAML
+is shown before it is saved.
+
+Same as 'frank profile add --interactive'.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+		return runProfileWizard(name)
+	},
+}
+
+// profileNamePattern keeps profile names usable as URL path segments
+var profileNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+func runProfileWizard(name string) error {
+	w := &wizard{reader: bufio.NewReader(os.Stdin)}
+
+	for !profileNamePattern.MatchString(name) {
+		if name != "" {
+			PrintError("Profile names may contain letters, digits, '-' and '_'")
+		}
+		name = w.ask("Profile name", "")
+	}
+
+	// Editing an existing profile starts from its values
+	p := &profile.Profile{Name: name}
+	if existing, _ := profile.GetProfile(name); existing != nil {
+		fmt.Printf("Profile %q exists; press Enter to keep current values.\n", name)
+		p = existing
+		p.Name = name
+	}
+	fmt.Println()
+
+	p.Repo = w.pickRepo(p.Repo)
+	p.Branch = w.pickBranch(p.Repo, p.Branch)
+
+	selected := p.Agent
+	if selected == "" {
+		selected = profile.DefaultAgent
+	}
+	selected = w.choose("Agent", profile.Agents, selected)
+	p.Agent = selected
+	if p.Agent == profile.DefaultAgent {
+		p.Agent = "" // Don't store the default
+	}
+	p.Model = w.chooseOrEnter("Model (Enter for the agent's default)", profile.Models[w.lastChoice], p.Model)
+
+	// Other providers serve the agent's models from Bedrock, Ollama or Azure
+	provider := p.Provider
+	if provider == "" {
+		provider = agent.DefaultProvider(selected)
+	}
+	p.Provider = w.choose("Provider", agent.ProvidersFor(selected), provider)
+	if p.Provider == agent.DefaultProvider(selected) {
+		p.Provider = "" // Don't store the default
+		p.Endpoint = ""
+	} else {
+		for {
+			p.Endpoint = w.ask("Endpoint (Enter for the provider's default)", p.Endpoint)
+			err := agent.Validate(p.AgentConfig())
+			if err == nil {
+				break
+			}
+			PrintError("%v", err)
+		}
+	}
+
+	p.Description = w.ask("Description", p.Description)
+
+	data, err := yaml.Marshal(map[string]map[string]*profile.Profile{"profiles": {name: p}})
+	if err != nil {
+		return fmt.Errorf("failed to render profile: %w", err)
+	}
+	fmt.Println()
+	fmt.Println(string(data))
+
+	if !w.confirm(fmt.Sprintf("Save to %s?", profile.GetProfilesPath()), true) {
+		fmt.Println("Profile not saved.")
+		return nil
+	}
+
+	if err := profile.AddProfile(p); err != nil {
+		return fmt.Errorf("failed to add profile: %w", err)
+	}
+	fmt.Println()
+	printProfileSaved(p)
 	return nil
 }
 
+// wizard reads answers to interactive prompts from stdin
+type wizard struct {
+	reader     *bufio.Reader
+	lastChoice string
+}
+
+// ask prompts for a line of input, returning def when it is left empty
+func (w *wizard) ask(label, def string) string {
+	if def != "" {
+		fmt.Printf("%s [%s]: ", label, def)
+	} else {
+		fmt.Printf("%s: ", label)
+	}
+	line, _ := w.reader.ReadString('\n')
+	if line = strings.TrimSpace(line); line == "" {
+		return def
+	}
+	return line
+}
+
+func (w *wizard) confirm(label string, def bool) bool {
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	fmt.Printf("%s %s: ", label, hint)
+	line, _ := w.reader.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "":
+		return def
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// choose asks for one of options by number or name
+func (w *wizard) choose(label string, options []string, def string) string {
+	for i, o := range options {
+		fmt.Printf("  %d) %s\n", i+1, o)
+	}
+	for {
+		answer := w.ask(label, def)
+		if match, ok := matchOption(options, answer); ok {
+			w.lastChoice = match
+			return match
+		}
+		PrintError("Choose one of: %s", strings.Join(options, ", "))
+	}
+}
+
+// chooseOrEnter offers suggestions but accepts any value
+func (w *wizard) chooseOrEnter(label string, suggestions []string, def string) string {
+	for i, o := range suggestions {
+		fmt.Printf("  %d) %s\n", i+1, o)
+	}
+	answer := w.ask(label, def)
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(suggestions) {
+		return suggestions[n-1]
+	}
+	return answer
+}
+
+// pickRepo offers the user's GitHub repositories when a token is available.
+// The answer can be a number, text that narrows the list, or a clone URL.
+func (w *wizard) pickRepo(current string) string {
+	var repos []github.Repository
+	if token := GetGitHubToken(); token != "" {
+		fmt.Println("Fetching your GitHub repositories...")
+		var err error
+		repos, err = github.NewClient(token).ListRepositories(context.Background())
+		if err != nil {
+			PrintError("%v", err)
+		}
+	}
+
+	if len(repos) == 0 {
+		for {
+			if url := w.ask("Repository URL", current); url != "" {
+				return url
+			}
+		}
+	}
+
+	shown := repos
+	for {
+		printRepoChoices(shown)
+		answer := w.ask("Repository (number, search text or URL)", current)
+		switch {
+		case answer == "":
+			continue
+		case answer == current, strings.Contains(answer, "://"), strings.HasPrefix(answer, "git@"):
+			return answer
+		}
+
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= min(len(shown), maxWizardChoices) {
+			return shown[n-1].CloneURL
+		}
+
+		var matches []github.Repository
+		for _, r := range repos {
+			if strings.Contains(strings.ToLower(r.FullName), strings.ToLower(answer)) {
+				matches = append(matches, r)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			PrintError("No repository matches %q", answer)
+		case 1:
+			fmt.Printf("Using %s\n", color.CyanString(matches[0].FullName))
+			return matches[0].CloneURL
+		default:
+			shown = matches
+		}
+	}
+}
+
+// maxWizardChoices caps how many numbered choices are listed at once
+const maxWizardChoices = 20
+
+func printRepoChoices(repos []github.Repository) {
+	for i, r := range repos[:min(len(repos), maxWizardChoices)] {
+		line := fmt.Sprintf("  %2d) %s", i+1, r.FullName)
+		if r.Private {
+			line += color.YellowString(" (private)")
+		}
+		if r.Description != "" {
+			desc := r.Description
+			if len(desc) > 50 {
+				desc = desc[:47] + "..."
+			}
+			line += "  " + color.HiBlackString(desc)
+		}
+		fmt.Println(line)
+	}
+	if len(repos) > maxWizardChoices {
+		fmt.Printf("  ... and %d more; type part of a name to narrow the list\n", len(repos)-maxWizardChoices)
+	}
+}
+
+// pickBranch lists the remote's branches and completes a unique prefix
+func (w *wizard) pickBranch(repoURL, current string) string {
+	branches, defaultBranch, err := git.RemoteBranches(repoURL)
+	if err != nil {
+		PrintError("%v", err)
+	}
+	if current == "" {
+		current = defaultBranch
+	}
+	if len(branches) == 0 {
+		if current == "" {
+			current = "main"
+		}
+		return w.ask("Branch", current)
+	}
+
+	for {
+		for i, b := range branches[:min(len(branches), maxWizardChoices)] {
+			if b == defaultBranch {
+				b += color.HiBlackString(" (default)")
+			}
+			fmt.Printf("  %2d) %s\n", i+1, b)
+		}
+		if len(branches) > maxWizardChoices {
+			fmt.Printf("  ... and %d more; type the start of a name to complete it\n", len(branches)-maxWizardChoices)
+		}
+
+		answer := w.ask("Branch", current)
+		if match, ok := matchOption(branches[:min(len(branches), maxWizardChoices)], answer); ok {
+			return match
+		}
+
+		var matches []string
+		for _, b := range branches {
+			if b == answer {
+				return b
+			}
+			if strings.HasPrefix(b, answer) {
+				matches = append(matches, b)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			PrintError("Branch %q not found on the remote", answer)
+		case 1:
+			fmt.Printf("Using %s\n", color.CyanString(matches[0]))
+			return matches[0]
+		default:
+			branches = matches
+		}
+	}
+}
+
+// matchOption resolves a 1-based number or an exact name against options
+func matchOption(options []string, answer string) (string, bool) {
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+		return options[n-1], true
+	}
+	for _, o := range options {
+		if o == answer {
+			return o, true
+		}
+	}
+	return "", false
+}
+
 // ============================================================================
 // profile show - Show profile details
 // ============================================================================
@@ -203,7 +619,7 @@ func runProfileShow(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Site URL:    %s\n", p.SiteURL)
 	}
 	fmt.Println()
-	fmt.Printf("  URL:         https://frank.digitaldevops.io/%s/\n", name)
+	fmt.Printf("  URL:         https://%s/%s/\n", frankDomain(), name)
 	fmt.Println()
 
 	return nil
@@ -267,16 +683,41 @@ func runProfileSync(cmd *cobra.Command, args []string) error {
 		Branch      string `json:"branch,omitempty"`
 		Description string `json:"description,omitempty"`
 		SiteURL     string `json:"site_url,omitempty"`
+		Agent       string `json:"agent,omitempty"`
+		Model       string `json:"model,omitempty"`
+		Provider    string `json:"provider,omitempty"`
+		Endpoint    string `json:"endpoint,omitempty"`
+		// Env and Secrets are the resolved worker settings, so the API
+		// Lambda starts tasks the same way 'frank ecs start' does
+		Env     map[string]string `json:"env,omitempty"`
+		Secrets string            `json:"secrets,omitempty"`
 	}
 
 	profiles := make([]ssmProfile, 0, len(cfg.Profiles))
 	for name, p := range cfg.Profiles {
+		worker, err := agent.Resolve(p.AgentConfig())
+		if err != nil {
+			return fmt.Errorf("invalid agent settings for profile %s: %w", name, err)
+		}
+		env := worker.Env
+		if p.Record {
+			env = map[string]string{"FRANK_RECORD": "1"}
+			for k, v := range worker.Env {
+				env[k] = v
+			}
+		}
 		profiles = append(profiles, ssmProfile{
 			Name:        name,
 			Repo:        p.Repo,
 			Branch:      p.Branch,
 			Description: p.Description,
 			SiteURL:     p.SiteURL,
+			Agent:       p.Agent,
+			Model:       p.Model,
+			Provider:    p.Provider,
+			Endpoint:    p.Endpoint,
+			Env:         env,
+			Secrets:     worker.SecretsEnv(),
 		})
 	}
 
@@ -286,7 +727,7 @@ func runProfileSync(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load AWS config
-	awsCfg, err := config.LoadDefaultConfig(ctx)
+	awsCfg, err := config.LoadDefaultConfig(ctx, frankaws.WithRetries(), withAudit())
 	if err != nil {
 		return fmt.Errorf("failed to load AWS config: %w", err)
 	}
@@ -310,3 +751,362 @@ func runProfileSync(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// ============================================================================
+// profile export - Write profiles as YAML
+// ============================================================================
+
+var profileExportCmd = &cobra.Command{
+	Use:   "export [name...]",
+	Short: "Export profiles as YAML",
+	Long: `Write profiles in the profiles.yaml format, all of them or the named ones.
+The output can be checked into a repository or sent to a teammate and loaded
+with 'frank profile import'.`,
+	RunE: runProfileExport,
+}
+
+func runProfileExport(cmd *cobra.Command, args []string) error {
+	config, err := profile.LoadProfiles()
+	if err != nil {
+		return fmt.Errorf("failed to load profiles: %w", err)
+	}
+
+	if len(args) > 0 {
+		selected := profile.NewProfileConfig()
+		for _, name := range args {
+			p, ok := config.Profiles[name]
+			if !ok {
+				return exitcode.Errorf(exitcode.NotFound, "profile %q not found", name)
+			}
+			selected.Profiles[name] = p
+		}
+		config = selected
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal profiles: %w", err)
+	}
+
+	if profileExportOutput == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(profileExportOutput, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", profileExportOutput, err)
+	}
+	fmt.Fprintf(os.Stderr, "%s Exported %d profile(s) to %s\n", color.GreenString("✓"), len(config.Profiles), profileExportOutput)
+	return nil
+}
+
+// ============================================================================
+// profile import - Add profiles from a YAML file
+// ============================================================================
+
+var profileImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import profiles from a YAML file",
+	Long: `Add the profiles in a file written by 'frank profile export' (or any file
+in the profiles.yaml format). Use - to read from stdin.
+
+Profiles that already exist are kept unless --overwrite is given. Every
+profile is checked before any is saved, so a bad entry imports nothing.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProfileImport,
+}
+
+func runProfileImport(cmd *cobra.Command, args []string) error {
+	var data []byte
+	var err error
+	if args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", args[0], err)
+	}
+
+	imported, err := profile.ParseProfiles(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", args[0], err)
+	}
+	if len(imported.Profiles) == 0 {
+		return fmt.Errorf("no profiles found in %s", args[0])
+	}
+
+	names := make([]string, 0, len(imported.Profiles))
+	for name, p := range imported.Profiles {
+		if p == nil {
+			return fmt.Errorf("profile %q is empty", name)
+		}
+		if err := validateProfile(p); err != nil {
+			return fmt.Errorf("profile %q: %w", name, err)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	config, err := profile.LoadProfiles()
+	if err != nil {
+		return fmt.Errorf("failed to load profiles: %w", err)
+	}
+
+	added, updated, skipped := 0, 0, 0
+	for _, name := range names {
+		_, exists := config.Profiles[name]
+		switch {
+		case !exists:
+			fmt.Printf("  %s %s\n", color.GreenString("+"), name)
+			added++
+		case profileImportOverwrite:
+			fmt.Printf("  %s %s\n", color.YellowString("~"), name)
+			updated++
+		default:
+			fmt.Printf("  %s %s (exists; use --overwrite to replace it)\n", color.HiBlackString("-"), name)
+			skipped++
+			continue
+		}
+		config.Profiles[name] = imported.Profiles[name]
+	}
+
+	if added+updated > 0 {
+		if err := profile.SaveProfiles(config); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("\n%s Imported %d profile(s): %d added, %d updated, %d skipped\n",
+		color.GreenString("✓"), added+updated, added, updated, skipped)
+	return nil
+}
+
+// validateProfile applies the checks 'profile add' makes to a profile read
+// from a file
+func validateProfile(p *profile.Profile) error {
+	if !profileNamePattern.MatchString(p.Name) {
+		return fmt.Errorf("profile names may contain letters, digits, '-' and '_'")
+	}
+	if p.Repo == "" {
+		return fmt.Errorf("repo is required")
+	}
+	if err := profile.ValidateAgent(p.Agent); err != nil {
+		return err
+	}
+	if err := agent.Validate(p.AgentConfig()); err != nil {
+		return err
+	}
+	return p.ValidateBoot()
+}
+
+// ============================================================================
+// profile discover - Create profiles for a GitHub organization's repositories
+// ============================================================================
+
+var profileDiscoverCmd = &cobra.Command{
+	Use:   "discover --org <org>",
+	Short: "Create profiles for repositories in a GitHub organization",
+	Long: `List an organization's repositories through the GitHub API and create
+profiles for the ones you pick. Each profile is named after its repository
+and uses the repository's default branch and description.
+
+Repositories that already have a profile are left out, as are archived
+repositories and forks unless --archived or --forks is given.
+
+Requires a GitHub token (see 'frank auth github').`,
+	Args: cobra.NoArgs,
+	RunE: runProfileDiscover,
+}
+
+func runProfileDiscover(cmd *cobra.Command, args []string) error {
+	token := GetGitHubToken()
+	if token == "" {
+		return exitcode.Errorf(exitcode.Auth, "no GitHub token configured; run 'frank auth github' or set GH_TOKEN")
+	}
+
+	config, err := profile.LoadProfiles()
+	if err != nil {
+		return fmt.Errorf("failed to load profiles: %w", err)
+	}
+
+	fmt.Printf("Listing repositories in %s...\n", color.CyanString(profileDiscoverOrg))
+	repos, err := github.NewClient(token).ListOrgRepositories(context.Background(), profileDiscoverOrg)
+	if err != nil {
+		return err
+	}
+
+	// Repositories with a profile are matched by owner/name so that HTTPS
+	// and SSH remotes of the same repository count
+	profiled := make(map[string]bool)
+	for _, p := range config.Profiles {
+		if p == nil {
+			continue
+		}
+		if owner, name, err := github.ParseRepo(p.Repo); err == nil {
+			profiled[strings.ToLower(owner+"/"+name)] = true
+		}
+	}
+
+	var candidates []github.Repository
+	existing := 0
+	for _, r := range repos {
+		switch {
+		case r.Archived && !profileDiscoverArchived, r.Fork && !profileDiscoverForks:
+			continue
+		case profileDiscoverFilter != "" && !strings.Contains(strings.ToLower(r.FullName), strings.ToLower(profileDiscoverFilter)):
+			continue
+		case profiled[strings.ToLower(r.FullName)]:
+			existing++
+			continue
+		}
+		candidates = append(candidates, r)
+	}
+
+	if existing > 0 {
+		fmt.Printf("Skipping %d repositories that already have a profile.\n", existing)
+	}
+	if len(candidates) == 0 {
+		fmt.Println("No repositories to add.")
+		return nil
+	}
+
+	fmt.Println()
+	for i, r := range candidates {
+		line := fmt.Sprintf("  %3d) %s", i+1, r.FullName)
+		if r.Private {
+			line += color.YellowString(" (private)")
+		}
+		if r.Archived {
+			line += color.HiBlackString(" (archived)")
+		}
+		if r.Fork {
+			line += color.HiBlackString(" (fork)")
+		}
+		if r.Description != "" {
+			desc := r.Description
+			if len(desc) > 50 {
+				desc = desc[:47] + "..."
+			}
+			line += "  " + color.HiBlackString(desc)
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+
+	w := &wizard{reader: bufio.NewReader(os.Stdin)}
+	var selected []int
+	for {
+		answer := w.ask("Repositories to add (e.g. 1,3,5-7 or all; Enter to cancel)", "")
+		if answer == "" {
+			fmt.Println("No profiles created.")
+			return nil
+		}
+		if selected, err = parseSelection(answer, len(candidates)); err == nil {
+			break
+		}
+		PrintError("%v", err)
+	}
+
+	var created []*profile.Profile
+	for _, i := range selected {
+		r := candidates[i]
+		name := discoveredProfileName(r.FullName)
+		for !profileNamePattern.MatchString(name) || config.Profiles[name] != nil {
+			if name != "" && config.Profiles[name] != nil {
+				PrintError("Profile %q already exists", name)
+			}
+			name = w.ask(fmt.Sprintf("Profile name for %s", r.FullName), "")
+		}
+
+		branch := r.DefaultBranch
+		if branch == "" {
+			branch = "main"
+		}
+		p := &profile.Profile{
+			Name:        name,
+			Repo:        r.CloneURL,
+			Branch:      branch,
+			Description: r.Description,
+		}
+		config.Profiles[name] = p
+		created = append(created, p)
+	}
+
+	fmt.Println()
+	table := render.NewTable(render.Options{},
+		render.Column{Header: "PROFILE"},
+		render.Column{Header: "REPO"},
+		render.Column{Header: "BRANCH"},
+	)
+	for _, p := range created {
+		table.Append(p.Name, p.Repo, p.Branch)
+	}
+	if err := table.Render(os.Stdout); err != nil {
+		return err
+	}
+	fmt.Println()
+
+	if !w.confirm(fmt.Sprintf("Create %d profile(s) in %s?", len(created), profile.GetProfilesPath()), true) {
+		fmt.Println("No profiles created.")
+		return nil
+	}
+	if err := profile.SaveProfiles(config); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Created %d profile(s)\n", color.GreenString("✓"), len(created))
+	fmt.Println("Run 'frank profile sync' to show them on the launch page.")
+	return nil
+}
+
+// discoveredProfileName turns owner/repo into a profile name, replacing
+// characters that aren't allowed in one
+func discoveredProfileName(fullName string) string {
+	name := fullName[strings.LastIndex(fullName, "/")+1:]
+	name = profileNameInvalidChars.ReplaceAllString(name, "-")
+	return strings.TrimLeft(name, "-_")
+}
+
+var profileNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// parseSelection parses a list of 1-based numbers and ranges such as
+// "1,3,5-7", or "all", into sorted 0-based indexes below n
+func parseSelection(answer string, n int) ([]int, error) {
+	if strings.EqualFold(strings.TrimSpace(answer), "all") {
+		indexes := make([]int, n)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes, nil
+	}
+
+	seen := make(map[int]bool)
+	for _, part := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection %q", part)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil {
+				return nil, fmt.Errorf("invalid selection %q", part)
+			}
+		}
+		if first < 1 || last > n || first > last {
+			return nil, fmt.Errorf("selection %q is out of range (1-%d)", part, n)
+		}
+		for i := first; i <= last; i++ {
+			seen[i-1] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("nothing selected")
+	}
+
+	indexes := make([]int, 0, len(seen))
+	for i := range seen {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes, nil
+}
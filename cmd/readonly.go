@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	frankaws "github.com/barff/frank/internal/aws"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// mutatesAnnotation marks commands that change AWS resources. Its value is
+// the comma-separated IAM actions the command needs.
+const mutatesAnnotation = "frank.mutates"
+
+// permissionCheckTimeout bounds the IAM policy simulation before a command
+const permissionCheckTimeout = 10 * time.Second
+
+// mutates returns command annotations for a command that changes AWS
+// resources with the given IAM actions
+func mutates(actions ...string) map[string]string {
+	return map[string]string{mutatesAnnotation: strings.Join(actions, ",")}
+}
+
+// guardMutation stops a command that changes AWS resources from running
+// when frank is read-only, or when the caller's IAM policies don't allow
+// every action it needs. Commands with --dry-run fall back to it, so they
+// print what they would change; the rest fail before making any call.
+func guardMutation(cmd *cobra.Command) error {
+	actions, ok := cmd.Annotations[mutatesAnnotation]
+	if !ok {
+		return nil
+	}
+	return guardActions(cmd, strings.Split(actions, ",")...)
+}
+
+// guardActions is guardMutation for a command that only changes AWS
+// resources with some flags, such as rebuild --push. It's called once those
+// flags are known to be set, with the IAM actions they need.
+func guardActions(cmd *cobra.Command, actions ...string) error {
+	if cfg == nil {
+		return nil
+	}
+
+	var reason string
+	if cfg.AWS.ReadOnly {
+		reason = "frank is in read-only mode"
+	} else if cfg.AWS.PermissionCheck {
+		denied, err := deniedActions(actions)
+		if err != nil {
+			// Without iam:SimulatePrincipalPolicy there's no telling, so run as usual
+			PrintVerbose("Skipping the permission check: %v", err)
+			return nil
+		}
+		if len(denied) == 0 {
+			return nil
+		}
+		reason = "your AWS credentials aren't allowed " + strings.Join(denied, ", ")
+	} else {
+		return nil
+	}
+
+	if f := cmd.Flags().Lookup("dry-run"); f != nil {
+		if f.Value.String() != "true" {
+			if err := cmd.Flags().Set("dry-run", "true"); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "%s %s; showing what '%s' would change instead.\n\n", color.YellowString("Read-only:"), reason, cmd.CommandPath())
+		}
+		return nil
+	}
+	return exitcode.Errorf(exitcode.Config, "'%s' changes AWS resources and is disabled: %s", cmd.CommandPath(), reason)
+}
+
+// deniedActions asks the IAM policy simulator which actions the caller
+// can't perform
+func deniedActions(actions []string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), permissionCheckTimeout)
+	defer cancel()
+
+	opts := []func(*awsconfig.LoadOptions) error{frankaws.WithRetries()}
+	if ecsRegion != "" {
+		opts = append(opts, awsconfig.WithRegion(ecsRegion))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return frankaws.DeniedActions(ctx, awsCfg, actions)
+}
+
+// mutatingCommands returns the IAM actions each command that changes AWS
+// resources needs, by command path
+func mutatingCommands(cmd *cobra.Command) map[string][]string {
+	commands := map[string][]string{}
+	if actions, ok := cmd.Annotations[mutatesAnnotation]; ok {
+		commands[cmd.CommandPath()] = strings.Split(actions, ",")
+	}
+	for _, sub := range cmd.Commands() {
+		for path, actions := range mutatingCommands(sub) {
+			commands[path] = actions
+		}
+	}
+	return commands
+}
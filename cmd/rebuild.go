@@ -1,11 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/barff/frank/internal/render"
+	"github.com/barff/frank/internal/scan"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -28,27 +35,99 @@ tools/configs into the base image.
 Examples:
   frank rebuild                                    # Build from Dockerfile
   frank rebuild --no-cache                         # Build without cache
+  frank rebuild --cache-from ghcr.io/org/frank:main  # Reuse layers from another image
   frank rebuild --tag my-frank:v1                  # Custom tag
-  frank rebuild --from-snapshot frank-snapshot-abc123:latest  # Use snapshot as base`,
+  frank rebuild --from-snapshot frank-snapshot-abc123:latest  # Use snapshot as base
+  frank rebuild --push                             # Build the ECS image and push it to the frank ECR repository
+  frank rebuild --push --update-taskdef --deploy   # Push, register a task definition and roll the service
+  frank rebuild --scan                             # Scan the new image for vulnerabilities
+  frank rebuild --fail-on critical --push          # Only push if there are no critical CVEs
+
+--push builds the ECS image instead of the local one: build/Dockerfile.ecs,
+with the repository root as the build context, tagged frank-ecs:latest
+unless --tag is given. A snapshot can be pushed with --from-snapshot, but
+not registered with --update-taskdef or --deploy, since ECS tasks need the
+ECS image's entrypoint.
+
+Unless --no-cache is given, the build reuses layers from the image it
+replaces. An ECS build also reuses the image last pushed to ECR under the
+same tag, so CI and teammates share one layer cache; it is pulled first,
+using the --repo and --region flags. --cache-from names other images to
+pull and reuse instead of the ECR one; --cache-from none only uses the
+local cache.
+
+Private base images are pulled with the credentials from 'docker login', an
+ECR token for ECR registries, or --registry-user/--registry-password.
+
+--scan runs Trivy (https://trivy.dev) against the new image and prints a
+summary by severity. The full report is saved to ~/.frank/scans/<image-id>.json.
+--fail-on scans too, and fails before pushing when there are vulnerabilities
+at that severity or above.`,
 	RunE: runRebuild,
 }
 
 var (
-	rebuildNoCache     bool
-	rebuildTag         string
-	rebuildFromSnapshot string
+	rebuildNoCache       bool
+	rebuildTag           string
+	rebuildFromSnapshot  string
+	rebuildPush          bool
+	rebuildRepo          string
+	rebuildRegion        string
+	rebuildUpdateTaskDef bool
+	rebuildDeploy        bool
+	rebuildScan          bool
+	rebuildFailOn        string
+	rebuildCacheFrom     []string
 )
 
 func init() {
 	rootCmd.AddCommand(rebuildCmd)
 
 	rebuildCmd.Flags().BoolVar(&rebuildNoCache, "no-cache", false, "Build without using cache")
-	rebuildCmd.Flags().StringVar(&rebuildTag, "tag", "frank-dev:latest", "Image tag")
+	rebuildCmd.Flags().StringArrayVar(&rebuildCacheFrom, "cache-from", nil, "Image to reuse build cache from, repeatable, or none (default: the image last pushed to ECR, with --push)")
+	rebuildCmd.Flags().StringVar(&rebuildTag, "tag", "frank-dev:latest", "Image tag (default with --push: frank-ecs:latest)")
 	rebuildCmd.Flags().StringVar(&rebuildFromSnapshot, "from-snapshot", "", "Build from existing snapshot image instead of Dockerfile")
+	rebuildCmd.Flags().BoolVar(&rebuildPush, "push", false, "Push the image to ECR after building")
+	rebuildCmd.Flags().StringVar(&rebuildRepo, "repo", "", "ECR repository URI (default: the 'frank' repository in the current account)")
+	rebuildCmd.Flags().StringVar(&rebuildRegion, "region", "", "AWS region (default: from AWS config)")
+	rebuildCmd.Flags().BoolVar(&rebuildUpdateTaskDef, "update-taskdef", false, "Register a new ECS task definition revision using the pushed digest")
+	rebuildCmd.Flags().BoolVar(&rebuildDeploy, "deploy", false, "Roll the ECS service to the new task definition (implies --update-taskdef)")
+	rebuildCmd.Flags().BoolVar(&rebuildScan, "scan", false, "Scan the image for vulnerabilities with Trivy")
+	rebuildCmd.Flags().StringVar(&rebuildFailOn, "fail-on", "", "Fail on vulnerabilities of this severity or above: critical, high, medium, low (implies --scan)")
+	addRegistryFlags(rebuildCmd)
 }
 
 func runRebuild(cmd *cobra.Command, args []string) error {
-	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred)
+	if rebuildNoCache && len(rebuildCacheFrom) > 0 {
+		return exitcode.Errorf(exitcode.Usage, "--cache-from can't be used with --no-cache")
+	}
+	if slices.Contains(rebuildCacheFrom, "none") && len(rebuildCacheFrom) > 1 {
+		return exitcode.Errorf(exitcode.Usage, "--cache-from none can't be combined with other images")
+	}
+
+	if (rebuildUpdateTaskDef || rebuildDeploy) && !rebuildPush {
+		return fmt.Errorf("--update-taskdef and --deploy require --push")
+	}
+	if (rebuildUpdateTaskDef || rebuildDeploy) && rebuildFromSnapshot != "" {
+		return exitcode.Errorf(exitcode.Usage, "--update-taskdef and --deploy can't be used with --from-snapshot: snapshots are local images, and ECS tasks need the image built from Dockerfile.ecs")
+	}
+	if rebuildPush {
+		actions := []string{"ecr:InitiateLayerUpload", "ecr:UploadLayerPart", "ecr:CompleteLayerUpload", "ecr:PutImage"}
+		if rebuildUpdateTaskDef || rebuildDeploy {
+			actions = append(actions, "ecs:RegisterTaskDefinition", "iam:PassRole")
+		}
+		if rebuildDeploy {
+			actions = append(actions, "ecs:UpdateService")
+		}
+		if err := guardActions(cmd, actions...); err != nil {
+			return err
+		}
+	}
+	if rebuildFailOn != "" && !scan.ValidSeverity(rebuildFailOn) {
+		return fmt.Errorf("invalid --fail-on severity %q (use critical, high, medium or low)", rebuildFailOn)
+	}
+
+	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
 	if err != nil {
 		return fmt.Errorf("failed to detect container runtime: %w", err)
 	}
@@ -57,22 +136,55 @@ func runRebuild(cmd *cobra.Command, args []string) error {
 
 	// If building from snapshot, just tag the existing image
 	if rebuildFromSnapshot != "" {
-		return rebuildFromExistingSnapshot(runtime)
+		if err := rebuildFromExistingSnapshot(runtime); err != nil {
+			return err
+		}
+		if err := scanRebuiltImage(runtime, rebuildTag); err != nil {
+			return err
+		}
+		if rebuildPush {
+			return pushToECR(runtime, rebuildTag)
+		}
+		return nil
 	}
 
-	// Find Dockerfile
-	dockerfilePath, err := findDockerfile()
-	if err != nil {
-		return err
+	// Pushed images run on ECS, so they're built from Dockerfile.ecs, which
+	// copies from build/ and needs the repository root as its context
+	var dockerfilePath, contextDir string
+	if rebuildPush {
+		if !cmd.Flags().Changed("tag") {
+			rebuildTag = ecsImageTag
+		}
+		dockerfilePath, err = findECSDockerfile()
+		if err != nil {
+			return err
+		}
+		contextDir = filepath.Dir(filepath.Dir(dockerfilePath))
+	} else {
+		dockerfilePath, err = findDockerfile()
+		if err != nil {
+			return err
+		}
+		contextDir = filepath.Dir(dockerfilePath)
 	}
 
 	fmt.Printf("Building image %s...\n", color.CyanString(rebuildTag))
 	PrintVerbose("Using Dockerfile: %s", dockerfilePath)
 
+	registryAuths, err := buildRegistryAuths(context.Background(), dockerfilePath)
+	if err != nil {
+		return err
+	}
+
 	buildOpts := container.BuildOptions{
-		NoCache:    rebuildNoCache,
-		Dockerfile: dockerfilePath,
-		Context:    filepath.Dir(dockerfilePath),
+		NoCache:       rebuildNoCache,
+		Dockerfile:    dockerfilePath,
+		Context:       contextDir,
+		RegistryAuths: registryAuths,
+		CacheFrom:     rebuildCacheSources(context.Background(), runtime),
+	}
+	if len(buildOpts.CacheFrom) > 0 {
+		PrintVerbose("Reusing build cache from: %s", strings.Join(buildOpts.CacheFrom, ", "))
 	}
 
 	if err := runtime.BuildImage(rebuildTag, buildOpts); err != nil {
@@ -80,9 +192,139 @@ func runRebuild(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("\n%s Image built successfully: %s\n", color.GreenString("✓"), rebuildTag)
+
+	if err := scanRebuiltImage(runtime, rebuildTag); err != nil {
+		return err
+	}
+	if rebuildPush {
+		return pushToECR(runtime, rebuildTag)
+	}
+	return nil
+}
+
+// rebuildCacheSources returns the images the build may reuse layers from:
+// the --cache-from images or, for an ECS build, the image last pushed to ECR,
+// plus the local image being replaced. Registry images are pulled first, since Docker only
+// reads cache from local images; one that can't be pulled is left out. nil
+// leaves the runtime's own cache in charge.
+func rebuildCacheSources(ctx context.Context, runtime container.Runtime) []string {
+	if rebuildNoCache || slices.Contains(rebuildCacheFrom, "none") {
+		return nil
+	}
+
+	var sources []string
+	images := rebuildCacheFrom
+	if len(images) == 0 && rebuildPush {
+		if pushed := lastPushedImage(ctx, rebuildTag); pushed != "" {
+			images = []string{pushed}
+		}
+	}
+	for _, image := range images {
+		if !container.HasRegistry(image) {
+			sources = append(sources, image)
+			continue
+		}
+		fmt.Printf("Pulling %s for build cache...\n", color.CyanString(image))
+		auth, err := registryAuthFor(ctx, image)
+		if err == nil {
+			err = runtime.PullImage(image, auth)
+		}
+		if err != nil {
+			fmt.Printf("%s Not using %s as build cache: %v\n", color.YellowString("!"), image, err)
+			continue
+		}
+		sources = append(sources, image)
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+
+	// Naming any cache source stops Docker's classic builder from using its
+	// own, so the image being replaced is listed too
+	if exists, _ := runtime.ImageExists(rebuildTag); exists && !slices.Contains(sources, rebuildTag) {
+		sources = append([]string{rebuildTag}, sources...)
+	}
+	return sources
+}
+
+// scanRebuiltImage runs the --scan/--fail-on vulnerability scan of image
+func scanRebuiltImage(runtime container.Runtime, image string) error {
+	if !rebuildScan && rebuildFailOn == "" {
+		return nil
+	}
+
+	fmt.Printf("\nScanning %s for vulnerabilities...\n", color.CyanString(image))
+	var progress io.Writer
+	if GetVerbose() {
+		progress = os.Stderr
+	}
+	report, err := scan.Scan(context.Background(), image, scan.Options{Runtime: runtime.Name(), Stderr: progress})
+	if err != nil {
+		return fmt.Errorf("failed to scan image: %w", err)
+	}
+
+	if len(report.Vulnerabilities) > 0 {
+		table := render.NewTable(render.Options{},
+			render.Column{Header: "SEVERITY"},
+			render.Column{Header: "ID"},
+			render.Column{Header: "PACKAGE", Hide: 2},
+			render.Column{Header: "INSTALLED", Hide: 3},
+			render.Column{Header: "FIXED", Hide: 1},
+			render.Column{Header: "TITLE", MaxWidth: 50, Hide: 4},
+		)
+		shown := report.Vulnerabilities[:min(rebuildScanShown, len(report.Vulnerabilities))]
+		for _, v := range shown {
+			table.Append(formatSeverity(v.Severity), v.ID, v.Package, v.InstalledVersion, v.FixedVersion, v.Title)
+		}
+		fmt.Println()
+		if err := table.Render(os.Stdout); err != nil {
+			return err
+		}
+		if more := len(report.Vulnerabilities) - len(shown); more > 0 {
+			fmt.Printf("... and %d more\n", more)
+		}
+	}
+	fmt.Printf("\nVulnerabilities: %s\n", report.Summary())
+
+	path, err := report.Save(getScanReportsDir())
+	if err != nil {
+		fmt.Printf("Warning: failed to save scan report: %v\n", err)
+	} else {
+		fmt.Printf("Report: %s\n", path)
+	}
+
+	if rebuildFailOn != "" {
+		if n := report.AtLeast(rebuildFailOn); n > 0 {
+			return fmt.Errorf("%s has %d vulnerabilities at %s or above", image, n, strings.ToUpper(rebuildFailOn))
+		}
+		fmt.Printf("%s No vulnerabilities at %s or above\n", color.GreenString("✓"), strings.ToUpper(rebuildFailOn))
+	}
 	return nil
 }
 
+// rebuildScanShown is how many of the most severe vulnerabilities a scan lists
+const rebuildScanShown = 15
+
+// formatSeverity colors a vulnerability severity
+func formatSeverity(severity string) string {
+	switch severity {
+	case "CRITICAL":
+		return color.RedString(severity)
+	case "HIGH":
+		return color.HiRedString(severity)
+	case "MEDIUM":
+		return color.YellowString(severity)
+	default:
+		return severity
+	}
+}
+
+// getScanReportsDir returns where vulnerability scan reports are saved
+func getScanReportsDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".frank", "scans")
+}
+
 func rebuildFromExistingSnapshot(runtime container.Runtime) error {
 	// Check if snapshot exists
 	exists, err := runtime.ImageExists(rebuildFromSnapshot)
@@ -94,7 +336,7 @@ func rebuildFromExistingSnapshot(runtime container.Runtime) error {
 		fmt.Printf("Snapshot %s not found.\n\n", color.RedString(rebuildFromSnapshot))
 		fmt.Println("Available snapshots:")
 		listSnapshots(runtime)
-		return fmt.Errorf("snapshot not found: %s", rebuildFromSnapshot)
+		return exitcode.Errorf(exitcode.NotFound, "snapshot not found: %s", rebuildFromSnapshot)
 	}
 
 	fmt.Printf("Creating new base image from snapshot...\n")
@@ -159,3 +401,28 @@ func findDockerfile() (string, error) {
 	return "", fmt.Errorf("Dockerfile not found. Searched in:\n  %s\n\nPlease ensure the Dockerfile exists or create one at build/Dockerfile",
 		filepath.Join("build", "Dockerfile"))
 }
+
+// ecsImageTag is the local tag of the ECS image --push builds
+const ecsImageTag = "frank-ecs:latest"
+
+// findECSDockerfile finds build/Dockerfile.ecs in the working directory or
+// next to the frank executable
+func findECSDockerfile() (string, error) {
+	searchPaths := []string{filepath.Join("build", "Dockerfile.ecs")}
+	if execPath, err := os.Executable(); err == nil {
+		searchPaths = append(searchPaths, filepath.Join(filepath.Dir(execPath), "build", "Dockerfile.ecs"))
+	}
+
+	for _, path := range searchPaths {
+		if _, err := os.Stat(path); err == nil {
+			absPath, err := filepath.Abs(path)
+			if err != nil {
+				return path, nil
+			}
+			return absPath, nil
+		}
+	}
+
+	return "", exitcode.Errorf(exitcode.NotFound, "Dockerfile.ecs not found in %s; run rebuild --push from the frank repository root",
+		filepath.Join("build", "Dockerfile.ecs"))
+}
@@ -0,0 +1,377 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	frankaws "github.com/barff/frank/internal/aws"
+	"github.com/barff/frank/internal/recording"
+	"github.com/barff/frank/internal/render"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// recordingsPrefix is where ECS tasks upload recordings in the bucket,
+// recordings/<profile>/<name>.cast
+const recordingsPrefix = "recordings/"
+
+var recordingsCmd = &cobra.Command{
+	Use:   "recordings",
+	Short: "List, replay and export terminal recordings",
+	Long: `List, replay and export recordings of the agent terminal.
+
+Recording is opt-in: 'frank start --record' records a local container to
+~/.frank/recordings, and profiles with record enabled ('frank profile add
+--record', or 'frank ecs start --record') upload their recordings to
+s3://<bucket>/recordings/<profile>/. Recordings are asciinema v2 cast files,
+so they also play in asciinema and its web player.
+
+Examples:
+  frank recordings list                         # Local recordings
+  frank recordings list --s3                    # Local and ECS recordings
+  frank recordings play frank-dev-1-20261018-091500
+  frank recordings play <name> --speed 2 --idle-limit 1s
+  frank recordings export <name> --format txt -o session.txt`,
+}
+
+// Flags
+var (
+	recordingsBucket    string
+	recordingsS3        bool
+	recordingsSpeed     float64
+	recordingsIdleLimit time.Duration
+	recordingsFormat    string
+	recordingsOutput    string
+)
+
+func init() {
+	rootCmd.AddCommand(recordingsCmd)
+
+	recordingsCmd.AddCommand(recordingsListCmd)
+	recordingsCmd.AddCommand(recordingsPlayCmd)
+	recordingsCmd.AddCommand(recordingsExportCmd)
+
+	recordingsCmd.PersistentFlags().StringVar(&recordingsBucket, "bucket", "", "S3 bucket of ECS recordings (default: RECORDINGS_BUCKET, then the analytics bucket)")
+	recordingsListCmd.Flags().BoolVar(&recordingsS3, "s3", false, "Also list recordings uploaded by ECS tasks")
+	recordingsPlayCmd.Flags().Float64Var(&recordingsSpeed, "speed", 1, "Playback speed multiplier")
+	recordingsPlayCmd.Flags().DurationVar(&recordingsIdleLimit, "idle-limit", 2*time.Second, "Longest pause during playback (0 keeps every pause)")
+	recordingsExportCmd.Flags().StringVar(&recordingsFormat, "format", "cast", "Output format (cast, txt)")
+	recordingsExportCmd.Flags().StringVarP(&recordingsOutput, "output", "o", "", "Output file (default: <name>.<format>)")
+}
+
+// recordingEntry is a recording found locally or in S3
+type recordingEntry struct {
+	Name     string
+	Source   string // "local" or the S3 profile folder
+	Path     string // local file or S3 key
+	Size     int64
+	Modified time.Time
+}
+
+// ============================================================================
+// recordings list - List recordings
+// ============================================================================
+
+var recordingsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List terminal recordings",
+	Long: `List local terminal recordings, newest first. With --s3, recordings
+uploaded by ECS tasks are listed too.`,
+	RunE: runRecordingsList,
+}
+
+func runRecordingsList(cmd *cobra.Command, args []string) error {
+	entries, err := localRecordings()
+	if err != nil {
+		return err
+	}
+
+	if recordingsS3 {
+		bucket := getRecordingsBucket()
+		if bucket == "" {
+			return fmt.Errorf("S3 bucket not configured. Set RECORDINGS_BUCKET or ANALYTICS_BUCKET, or use --bucket flag")
+		}
+		ctx := context.Background()
+		client, err := recordingsS3Client(ctx)
+		if err != nil {
+			return err
+		}
+		remote, err := s3Recordings(ctx, client, bucket)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, remote...)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No recordings found.")
+		fmt.Println("Record with 'frank start --record' or a profile with record enabled.")
+		return nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Modified.After(entries[j].Modified) })
+
+	table := render.NewTable(render.Options{},
+		render.Column{Header: "NAME"},
+		render.Column{Header: "SOURCE"},
+		render.Column{Header: "DURATION"},
+		render.Column{Header: "SIZE"},
+		render.Column{Header: "RECORDED"},
+	)
+
+	for _, e := range entries {
+		// Only local recordings are cheap to measure
+		duration := "-"
+		if e.Source == "local" {
+			if d, err := recording.Duration(e.Path); err == nil {
+				duration = d.Round(time.Second).String()
+			}
+		}
+		table.Append(
+			e.Name,
+			e.Source,
+			duration,
+			formatFileSize(strconv.FormatInt(e.Size, 10)),
+			e.Modified.Local().Format("2006-01-02 15:04"),
+		)
+	}
+	return table.Render(os.Stdout)
+}
+
+// ============================================================================
+// recordings play - Replay a recording in the terminal
+// ============================================================================
+
+var recordingsPlayCmd = &cobra.Command{
+	Use:   "play <name>",
+	Short: "Replay a recording in this terminal",
+	Long: `Replay a recording in this terminal with its original timing.
+
+Long pauses are shortened to --idle-limit. The recording was made at the
+terminal size in its header, so playback looks best in a terminal at least
+that large. Press Ctrl+C to stop.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRecordingsPlay,
+}
+
+func runRecordingsPlay(cmd *cobra.Command, args []string) error {
+	rc, err := openRecording(context.Background(), args[0])
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	cr, err := recording.NewReader(rc)
+	if err != nil {
+		return err
+	}
+	PrintVerbose("Recorded at %dx%d: %s", cr.Header.Width, cr.Header.Height, cr.Header.Title)
+
+	if err := recording.Play(os.Stdout, cr, recordingsSpeed, recordingsIdleLimit); err != nil {
+		return err
+	}
+	// Leave the user's terminal as it was before the agent's TUI drew on it
+	fmt.Print("\x1b[0m\x1b[?25h\n")
+	return nil
+}
+
+// ============================================================================
+// recordings export - Save a recording as a cast or text file
+// ============================================================================
+
+var recordingsExportCmd = &cobra.Command{
+	Use:   "export <name>",
+	Short: "Save a recording as a cast or text file",
+	Long: `Save a recording to a file.
+
+The cast format is the asciinema v2 file itself, for asciinema play or the
+asciinema web player. The txt format is the terminal output without escape
+sequences, for reading or searching a session. An output of "-" writes to
+stdout.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRecordingsExport,
+}
+
+func runRecordingsExport(cmd *cobra.Command, args []string) error {
+	name := strings.TrimSuffix(args[0], recording.Ext)
+	if recordingsFormat != "cast" && recordingsFormat != "txt" {
+		return fmt.Errorf("invalid format %q (valid: cast, txt)", recordingsFormat)
+	}
+
+	rc, err := openRecording(context.Background(), name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	output := recordingsOutput
+	if output == "" {
+		output = name + "." + recordingsFormat
+	}
+	var w io.Writer = os.Stdout
+	if output != "-" {
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", output, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if recordingsFormat == "cast" {
+		if _, err := io.Copy(w, rc); err != nil {
+			return fmt.Errorf("failed to export recording: %w", err)
+		}
+	} else {
+		cr, err := recording.NewReader(rc)
+		if err != nil {
+			return err
+		}
+		if err := recording.WriteText(w, cr); err != nil {
+			return fmt.Errorf("failed to export recording: %w", err)
+		}
+	}
+
+	if output != "-" {
+		fmt.Printf("%s Exported %s to %s\n", color.GreenString("✓"), name, output)
+	}
+	return nil
+}
+
+// Helper functions
+
+// getLocalRecordingsDir returns where 'frank start --record' keeps recordings
+func getLocalRecordingsDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".frank", "recordings")
+}
+
+func getRecordingsBucket() string {
+	if recordingsBucket != "" {
+		return recordingsBucket
+	}
+	if bucket := os.Getenv("RECORDINGS_BUCKET"); bucket != "" {
+		return bucket
+	}
+	return getBucket()
+}
+
+func recordingsS3Client(ctx context.Context) (*s3.Client, error) {
+	awsCfg, err := config.LoadDefaultConfig(ctx, frankaws.WithRetries(), config.WithRegion(getAnalyticsRegion()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return s3.NewFromConfig(awsCfg), nil
+}
+
+// localRecordings lists the cast files in the local recordings directory
+func localRecordings() ([]recordingEntry, error) {
+	dir := getLocalRecordingsDir()
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var entries []recordingEntry
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), recording.Ext) {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, recordingEntry{
+			Name:     strings.TrimSuffix(f.Name(), recording.Ext),
+			Source:   "local",
+			Path:     filepath.Join(dir, f.Name()),
+			Size:     info.Size(),
+			Modified: info.ModTime(),
+		})
+	}
+	return entries, nil
+}
+
+// s3Recordings lists the recordings ECS tasks uploaded to the bucket
+func s3Recordings(ctx context.Context, client *s3.Client, bucket string) ([]recordingEntry, error) {
+	var entries []recordingEntry
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(recordingsPrefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list recordings in s3://%s/%s: %w", bucket, recordingsPrefix, err)
+		}
+		for _, obj := range page.Contents {
+			key := aws.ToString(obj.Key)
+			if !strings.HasSuffix(key, recording.Ext) {
+				continue
+			}
+			entries = append(entries, recordingEntry{
+				Name:     strings.TrimSuffix(path.Base(key), recording.Ext),
+				Source:   "s3:" + path.Base(path.Dir(key)),
+				Path:     key,
+				Size:     aws.ToInt64(obj.Size),
+				Modified: aws.ToTime(obj.LastModified),
+			})
+		}
+	}
+	return entries, nil
+}
+
+// openRecording opens a recording by name, looking in the local recordings
+// directory first and then in the bucket when one is configured
+func openRecording(ctx context.Context, name string) (io.ReadCloser, error) {
+	name = strings.TrimSuffix(name, recording.Ext)
+
+	localPath := filepath.Join(getLocalRecordingsDir(), name+recording.Ext)
+	if f, err := os.Open(localPath); err == nil {
+		PrintVerbose("Reading %s", localPath)
+		return f, nil
+	}
+
+	bucket := getRecordingsBucket()
+	if bucket == "" {
+		return nil, fmt.Errorf("recording %q not found in %s", name, getLocalRecordingsDir())
+	}
+
+	client, err := recordingsS3Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := s3Recordings(ctx, client, bucket)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range remote {
+		if e.Name != name {
+			continue
+		}
+		PrintVerbose("Reading s3://%s/%s", bucket, e.Path)
+		out, err := client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(e.Path),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to download recording: %w", err)
+		}
+		return out.Body, nil
+	}
+	return nil, fmt.Errorf("recording %q not found locally or in s3://%s/%s", name, bucket, recordingsPrefix)
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	frankaws "github.com/barff/frank/internal/aws"
+	"github.com/barff/frank/internal/container"
+	"github.com/spf13/cobra"
+)
+
+// Registry credentials given on the command line, shared by start, restart
+// and rebuild
+var (
+	registryUser     string
+	registryPassword string
+)
+
+// ecrHostPattern matches private ECR registries and captures the region
+var ecrHostPattern = regexp.MustCompile(`^\d{12}\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?$`)
+
+// addRegistryFlags adds the registry credential flags to a command
+func addRegistryFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&registryUser, "registry-user", "", "Username for pulling private images (default: docker login or ECR credentials)")
+	cmd.Flags().StringVar(&registryPassword, "registry-password", "", "Password or token for --registry-user (default: $FRANK_REGISTRY_PASSWORD)")
+}
+
+// registryAuthFor returns credentials for the registry hosting image
+func registryAuthFor(ctx context.Context, image string) (container.RegistryAuth, error) {
+	return registryAuthForHost(ctx, container.RegistryHost(image))
+}
+
+// registryAuthForHost returns credentials for a registry host. In order of
+// preference they come from --registry-user/--registry-password, an ECR
+// authorization token for ECR registries, or docker login's config.json.
+func registryAuthForHost(ctx context.Context, host string) (container.RegistryAuth, error) {
+	if registryUser != "" {
+		password := registryPassword
+		if password == "" {
+			password = os.Getenv("FRANK_REGISTRY_PASSWORD")
+		}
+		if password == "" {
+			return container.RegistryAuth{}, fmt.Errorf("--registry-user needs --registry-password or FRANK_REGISTRY_PASSWORD")
+		}
+		return container.RegistryAuth{Username: registryUser, Password: password, ServerAddress: host}, nil
+	}
+
+	if m := ecrHostPattern.FindStringSubmatch(host); m != nil {
+		PrintVerbose("Getting an ECR token for %s", host)
+		awsCfg, err := config.LoadDefaultConfig(ctx, frankaws.WithRetries(), withAudit(), config.WithRegion(m[1]))
+		if err != nil {
+			return container.RegistryAuth{}, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		auth, err := getECRAuth(ctx, ecr.NewFromConfig(awsCfg))
+		if err != nil {
+			return container.RegistryAuth{}, err
+		}
+		auth.ServerAddress = host
+		return auth, nil
+	}
+
+	return container.DockerConfigAuth(host)
+}
+
+// buildRegistryAuths returns credentials for every registry a Dockerfile's
+// base images come from. Registries without credentials are left out.
+func buildRegistryAuths(ctx context.Context, dockerfilePath string) ([]container.RegistryAuth, error) {
+	images, err := dockerfileBaseImages(dockerfilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := map[string]bool{}
+	for _, image := range images {
+		hosts[container.RegistryHost(image)] = true
+	}
+	var names []string
+	for host := range hosts {
+		names = append(names, host)
+	}
+	sort.Strings(names)
+
+	var auths []container.RegistryAuth
+	for _, host := range names {
+		auth, err := registryAuthForHost(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get credentials for %s: %w", host, err)
+		}
+		if auth.Username != "" {
+			PrintVerbose("Using registry credentials for %s", host)
+			auths = append(auths, auth)
+		}
+	}
+	return auths, nil
+}
+
+// dockerfileBaseImages returns the images a Dockerfile's FROM lines pull.
+// Earlier build stages, scratch and images built from ARGs are skipped.
+func dockerfileBaseImages(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Dockerfile: %w", err)
+	}
+	defer f.Close()
+
+	stages := map[string]bool{}
+	var images []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+
+		// Skip flags such as --platform
+		args := fields[1:]
+		for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		image := args[0]
+		if image != "scratch" && !stages[strings.ToLower(image)] && !strings.Contains(image, "$") {
+			images = append(images, image)
+		}
+		if len(args) >= 3 && strings.EqualFold(args[1], "AS") {
+			stages[strings.ToLower(args[2])] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Dockerfile: %w", err)
+	}
+	return images, nil
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/barff/frank/internal/analytics"
+	"github.com/barff/frank/internal/aws"
+	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/barff/frank/internal/state"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var restartCmd = &cobra.Command{
+	Use:   "restart <container>",
+	Short: "Recreate a frank container with the same ports, mounts, and environment",
+	Long: `Recreate a frank container with identical options.
+
+The existing container is inspected and a new one is created with the same
+ports, mounts, environment, and labels. Use this after a config or credential
+change, or with --pull/--image to move the container onto a fresh image.
+
+The old container is renamed aside while the new one starts and removed once
+it is running. If the new container fails to start, the old one is renamed
+back and restarted.
+
+AWS credentials mounted from a file (containers started with a specific
+--profile) are refreshed before the new container starts.
+
+Examples:
+  frank restart frank-dev-1
+  frank restart frank-dev-1 --pull
+  frank restart frank-dev-1 --image frank-dev:latest`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRestart,
+}
+
+var (
+	restartPull    bool
+	restartImage   string
+	restartTimeout time.Duration
+)
+
+func init() {
+	rootCmd.AddCommand(restartCmd)
+
+	restartCmd.Flags().BoolVar(&restartPull, "pull", false, "Pull the image before recreating the container")
+	restartCmd.Flags().StringVar(&restartImage, "image", "", "Recreate the container from this image instead")
+	restartCmd.Flags().DurationVar(&restartTimeout, "timeout", 10*time.Second, "Timeout before force stopping the old container")
+	addRegistryFlags(restartCmd)
+}
+
+func runRestart(cmd *cobra.Command, args []string) error {
+	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
+	if err != nil {
+		return fmt.Errorf("failed to detect container runtime: %w", err)
+	}
+
+	PrintVerbose("Using runtime: %s", runtime.Name())
+
+	name := args[0]
+	old, err := runtime.GetContainer(name)
+	if err != nil {
+		return exitcode.Errorf(exitcode.NotFound, "container not found: %s", name)
+	}
+
+	opts, err := runtime.InspectContainer(old.ID)
+	if err != nil {
+		return err
+	}
+	if restartImage != "" {
+		opts.Image = restartImage
+	}
+	// AutoRemove would delete the old container as soon as it stops, leaving
+	// nothing to roll back to
+	if opts.AutoRemove {
+		return fmt.Errorf("container %s was created with auto-remove and cannot be restarted safely", name)
+	}
+
+	if restartPull {
+		fmt.Printf("Pulling %s...\n", opts.Image)
+		auth, err := registryAuthFor(context.Background(), opts.Image)
+		if err != nil {
+			return err
+		}
+		if err := runtime.PullImage(opts.Image, auth); err != nil {
+			return fmt.Errorf("failed to pull image: %w", err)
+		}
+	} else if exists, err := runtime.ImageExists(opts.Image); err == nil && !exists {
+		return fmt.Errorf("image %s not found locally (use --pull)", opts.Image)
+	}
+
+	if credsDir := opts.Labels[credsDirLabel]; credsDir != "" {
+		refresher := aws.NewRefresher(opts.Labels["frank.profile"], credsDir, cfg.AWS.CredentialRefreshBuffer)
+		if expiresAt, err := refresher.Refresh(); err != nil {
+			fmt.Printf("Warning: failed to refresh AWS credentials: %v\n", err)
+		} else {
+			PrintVerbose("AWS credentials refreshed (expire %s)", formatExpiry(expiresAt))
+		}
+	}
+
+	// The old container holds the host ports, so it has to stop first
+	fmt.Printf("Stopping %s...\n", name)
+	if err := runtime.StopContainer(old.ID, restartTimeout); err != nil {
+		return fmt.Errorf("failed to stop container: %w", err)
+	}
+
+	backupName := fmt.Sprintf("%s-restart-%s", name, time.Now().Format("20060102-150405"))
+	if err := runtime.RenameContainer(old.ID, backupName); err != nil {
+		runtime.StartContainer(old.ID)
+		return fmt.Errorf("failed to rename container: %w", err)
+	}
+
+	// frank stop removes the sidecar network, so bring it back if needed,
+	// and the shared network in case it was pruned
+	owner := name
+	if o := opts.Labels[container.SidecarLabel]; o != "" {
+		owner = o
+	}
+	if opts.Network == container.NetworkName(owner) {
+		if err := runtime.CreateNetwork(opts.Network, map[string]string{container.SidecarLabel: owner}); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
+	if opts.Network == container.SharedNetwork || slices.Contains(opts.Networks, container.SharedNetwork) {
+		if err := runtime.CreateNetwork(container.SharedNetwork, nil); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
+
+	fmt.Printf("Creating container %s from %s...\n", color.CyanString(name), opts.Image)
+	newID, err := runtime.CreateContainer(*opts)
+	if err == nil {
+		if err = runtime.StartContainer(newID); err != nil {
+			runtime.RemoveContainer(newID, true)
+		}
+	}
+	if err != nil {
+		if rbErr := rollbackRestart(runtime, old.ID, name); rbErr != nil {
+			return fmt.Errorf("failed to start new container: %w (rollback failed: %v; old container is %s)", err, rbErr, backupName)
+		}
+		return fmt.Errorf("failed to start new container, restored the original: %w", err)
+	}
+
+	if err := runtime.RemoveContainer(old.ID, false); err != nil {
+		fmt.Printf("Warning: failed to remove old container %s: %v\n", backupName, err)
+	}
+
+	recordAnalytics(analytics.Record{
+		Type:    analytics.TypeSessionRestart,
+		Session: name,
+		Profile: opts.Labels["frank.profile"],
+		Repo:    opts.Labels["frank.repo"],
+		Image:   opts.Image,
+		Runtime: runtime.Name(),
+	})
+	recordState(func(s *state.Store) error {
+		if err := s.Stop(name, "Restarted by frank restart"); err != nil {
+			return err
+		}
+		run := state.Run{
+			Kind:    state.KindContainer,
+			Name:    name,
+			ID:      newID,
+			Profile: opts.Labels["frank.profile"],
+			Repo:    opts.Labels["frank.repo"],
+			Image:   opts.Image,
+			Runtime: runtime.Name(),
+		}
+		for _, p := range opts.Ports {
+			run.Ports = append(run.Ports, p.HostPort)
+		}
+		return s.Start(run)
+	})
+
+	fmt.Printf("%s Restarted %s\n", color.GreenString("✓"), color.CyanString(name))
+	for _, p := range opts.Ports {
+		if p.ContainerPort == 7680 {
+			fmt.Printf("  URL: %s\n", color.CyanString(fmt.Sprintf("http://localhost:%d", p.HostPort)))
+		}
+	}
+	return nil
+}
+
+// rollbackRestart gives the original container its name back and starts it
+func rollbackRestart(runtime container.Runtime, oldID, name string) error {
+	if err := runtime.RenameContainer(oldID, name); err != nil {
+		return err
+	}
+	return runtime.StartContainer(oldID)
+}
@@ -1,10 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/barff/frank/internal/config"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/barff/frank/internal/log"
+	"github.com/barff/frank/internal/redact"
+	"github.com/barff/frank/internal/ssmexec"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -27,22 +33,57 @@ It provides:
   - Git worktree management for parallel development
   - Support for Docker, Podman, and OrbStack`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		return initConfig()
+		commandPath = cmd.CommandPath()
+		if err := initConfig(); err != nil {
+			return exitcode.Wrap(exitcode.Config, err)
+		}
+		return guardMutation(cmd)
 	},
+	// Execute prints errors itself, with --quiet as JSON, and the usage
+	// only for usage errors
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
-// Execute runs the root command
-func Execute() error {
-	return rootCmd.Execute()
+// Execute runs the root command and returns the process exit code, which
+// depends on the kind of error (see internal/exitcode)
+func Execute() int {
+	defer log.Close()
+	markUsageErrors(rootCmd)
+
+	c, err := rootCmd.ExecuteC()
+	if err == nil {
+		return 0
+	}
+	// An interactive remote shell's status passes through without a message
+	if remoteExit, ok := err.(*ssmexec.ExitError); ok {
+		return remoteExit.Code
+	}
+	kind := errorKind(err)
+	printCommandError(os.Stderr, c, err, kind)
+	return kind.Code()
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.config/frank/config.yaml)")
 	rootCmd.PersistentFlags().String("runtime", "", "container runtime: docker, podman, orbstack (default: auto-detect)")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().String("log-level", "", "log level: debug, info, warn, error (default: info)")
+	rootCmd.PersistentFlags().String("log-file", "", "also write logs to this file")
+	rootCmd.PersistentFlags().String("log-format", "", "log format: text, json (default: text)")
+	rootCmd.PersistentFlags().Bool("quiet", false, "on failure, print only a JSON error object with the exit code's kind (for scripts)")
+	rootCmd.PersistentFlags().Bool("read-only", false, "refuse commands that change AWS resources; ecs start and commands with --dry-run print their plan instead")
+
+	rootCmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return exitcode.Wrap(exitcode.Usage, err)
+	})
 
 	viper.BindPFlag("runtime.preferred", rootCmd.PersistentFlags().Lookup("runtime"))
 	viper.BindPFlag("logging.verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	viper.BindPFlag("logging.level", rootCmd.PersistentFlags().Lookup("log-level"))
+	viper.BindPFlag("logging.file", rootCmd.PersistentFlags().Lookup("log-file"))
+	viper.BindPFlag("logging.format", rootCmd.PersistentFlags().Lookup("log-format"))
+	viper.BindPFlag("aws.readOnly", rootCmd.PersistentFlags().Lookup("read-only"))
 }
 
 func initConfig() error {
@@ -51,6 +92,16 @@ func initConfig() error {
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+
+	// A bad logging setting shouldn't lock the user out of 'frank config set'
+	if err := log.Setup(log.Options{
+		Level:   cfg.Logging.Level,
+		Verbose: cfg.Logging.Verbose,
+		File:    cfg.Logging.File,
+		Format:  cfg.Logging.Format,
+	}); err != nil {
+		slog.Warn(fmt.Sprintf("logging disabled: %v", err))
+	}
 	return nil
 }
 
@@ -59,19 +110,18 @@ func GetConfig() *config.Config {
 	return cfg
 }
 
-// GetVerbose returns whether verbose mode is enabled
+// GetVerbose returns whether verbose (debug) output is enabled
 func GetVerbose() bool {
-	return viper.GetBool("logging.verbose")
+	return slog.Default().Enabled(context.Background(), slog.LevelDebug)
 }
 
-// PrintVerbose prints a message if verbose mode is enabled
+// PrintVerbose logs a debug message, shown with --verbose or --log-level debug.
+// Tokens and keys in the message are masked.
 func PrintVerbose(format string, args ...interface{}) {
-	if GetVerbose() {
-		fmt.Printf(format+"\n", args...)
-	}
+	slog.Debug(redact.String(fmt.Sprintf(format, args...)))
 }
 
-// PrintError prints an error message to stderr
+// PrintError logs an error message to stderr
 func PrintError(format string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
+	slog.Error(redact.String(fmt.Sprintf(format, args...)))
 }
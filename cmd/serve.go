@@ -0,0 +1,495 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/barff/frank/internal/alb"
+	frankecs "github.com/barff/frank/internal/ecs"
+	"github.com/barff/frank/internal/profile"
+	"github.com/barff/frank/internal/redact"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run an HTTP API for managing profile tasks",
+	Long: `Run an authenticated HTTP API for driving frank from dashboards and bots.
+
+Every /api request must send the token as "Authorization: Bearer <token>".
+The token comes from --token, serve.token or FRANK_SERVE_TOKEN. Without one, a
+random token is generated and printed at startup.
+
+Endpoints:
+  GET  /healthz                          Liveness check (no token needed)
+  GET  /api/profiles                     Profiles with their running task
+  GET  /api/profiles/{name}              One profile
+  POST /api/profiles/{name}/start        Start a task (?resume=true to resume)
+  POST /api/profiles/{name}/stop         Stop the profile's task
+  GET  /api/profiles/{name}/logs         Task logs as server-sent events
+                                         (?tail=50, ?follow=false for a snapshot)
+
+Examples:
+  frank serve                                  # Listen on 127.0.0.1:7070
+  FRANK_SERVE_TOKEN=secret frank serve --addr 0.0.0.0:7070
+  curl -H "Authorization: Bearer secret" localhost:7070/api/profiles`,
+	RunE: runServe,
+}
+
+var (
+	serveAddr  string
+	serveToken string
+)
+
+// serveLogPollInterval is how often followed log streams check for new events
+const serveLogPollInterval = 2 * time.Second
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "", "Listen address (default: serve.addr, 127.0.0.1:7070)")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Bearer token clients must send (default: serve.token or FRANK_SERVE_TOKEN)")
+}
+
+// ============================================================================
+// serve - HTTP control API
+// ============================================================================
+
+func runServe(cmd *cobra.Command, args []string) error {
+	addr := serveAddr
+	if addr == "" {
+		addr = cfg.Serve.Addr
+	}
+
+	token := serveToken
+	if token == "" {
+		token = cfg.Serve.Token
+	}
+	generated := token == ""
+	if generated {
+		var err error
+		if token, err = generateServeToken(); err != nil {
+			return err
+		}
+	}
+
+	// Create the audit logger before handlers can race to do it
+	getAuditLogger()
+
+	s := &apiServer{token: token, busy: make(map[string]bool)}
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+
+	fmt.Printf("%s Serving the frank API on http://%s\n", color.GreenString("✓"), addr)
+	if generated {
+		fmt.Printf("  Token: %s\n", color.CyanString(token))
+		fmt.Println("  Set serve.token or FRANK_SERVE_TOKEN to keep the same token across restarts")
+	}
+	fmt.Println("Press Ctrl+C to stop")
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to serve: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	fmt.Println("\nShutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// apiServer handles the control API requests
+type apiServer struct {
+	token string
+
+	// busy marks profiles with a start or stop in progress
+	mu   sync.Mutex
+	busy map[string]bool
+}
+
+// apiProfile is a profile as returned by the API
+type apiProfile struct {
+	*profile.Profile
+	Running   bool       `json:"running"`
+	TaskID    string     `json:"task_id,omitempty"`
+	Status    string     `json:"status,omitempty"`
+	Owner     string     `json:"owner,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	URL       string     `json:"url"`
+}
+
+// apiLogEvent is one log line sent to a logs stream
+type apiLogEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	TaskID    string    `json:"task_id"`
+	Message   string    `json:"message"`
+}
+
+func (s *apiServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.Handle("GET /api/profiles", s.auth(s.handleListProfiles))
+	mux.Handle("GET /api/profiles/{name}", s.auth(s.handleGetProfile))
+	mux.Handle("POST /api/profiles/{name}/start", s.auth(s.handleStartProfile))
+	mux.Handle("POST /api/profiles/{name}/stop", s.auth(s.handleStopProfile))
+	mux.Handle("GET /api/profiles/{name}/logs", s.auth(s.handleProfileLogs))
+	return mux
+}
+
+// auth rejects requests without the bearer token and logs the rest
+func (s *apiServer) auth(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		slog.Info(fmt.Sprintf("%s %s from %s", r.Method, r.URL.Path, r.RemoteAddr))
+		next(w, r)
+	})
+}
+
+func (s *apiServer) handleListProfiles(w http.ResponseWriter, r *http.Request) {
+	config, err := profile.LoadProfiles()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	tasks, err := runningProfileTasks(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	profiles := make([]apiProfile, 0, len(config.Profiles))
+	for _, p := range config.Profiles {
+		profiles = append(profiles, newAPIProfile(p, tasks))
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+	writeJSON(w, http.StatusOK, profiles)
+}
+
+func (s *apiServer) handleGetProfile(w http.ResponseWriter, r *http.Request) {
+	p, ok := lookupAPIProfile(w, r)
+	if !ok {
+		return
+	}
+
+	tasks, err := runningProfileTasks(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newAPIProfile(p, tasks))
+}
+
+func (s *apiServer) handleStartProfile(w http.ResponseWriter, r *http.Request) {
+	p, ok := lookupAPIProfile(w, r)
+	if !ok {
+		return
+	}
+	if !s.claim(w, p.Name) {
+		return
+	}
+	defer s.release(p.Name)
+
+	// Finish the start even if the client goes away, so ALB and ECS state
+	// aren't left half done
+	ctx := context.WithoutCancel(r.Context())
+
+	if taskID, _ := findTaskByProfile(ctx, p.Name); taskID != "" {
+		writeError(w, http.StatusConflict, fmt.Errorf("profile %q is already running as task %s", p.Name, taskID))
+		return
+	}
+
+	resume, _ := strconv.ParseBool(r.URL.Query().Get("resume"))
+
+	albMgr, err := alb.NewManager(ctx, withAudit())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to create ALB manager: %w", err))
+		return
+	}
+
+	started, err := startProfileTask(ctx, p, albMgr, resume, 0, func(format string, a ...interface{}) {
+		slog.Info(fmt.Sprintf("%s: %s", p.Name, fmt.Sprintf(format, a...)))
+	})
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, started)
+}
+
+func (s *apiServer) handleStopProfile(w http.ResponseWriter, r *http.Request) {
+	p, ok := lookupAPIProfile(w, r)
+	if !ok {
+		return
+	}
+	if !s.claim(w, p.Name) {
+		return
+	}
+	defer s.release(p.Name)
+
+	ctx := context.WithoutCancel(r.Context())
+
+	taskID, taskIP := findTaskByProfile(ctx, p.Name)
+	if taskID == "" {
+		writeError(w, http.StatusConflict, fmt.Errorf("profile %q is not running", p.Name))
+		return
+	}
+
+	client, err := getECSClient(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := stopProfileTask(ctx, client, p.Name, taskID, taskIP, "Stopped by frank serve"); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"profile": p.Name, "task_id": taskID, "status": "stopped"})
+}
+
+// handleProfileLogs streams the profile's task logs as server-sent events.
+// Each event's data is an apiLogEvent.
+func (s *apiServer) handleProfileLogs(w http.ResponseWriter, r *http.Request) {
+	p, ok := lookupAPIProfile(w, r)
+	if !ok {
+		return
+	}
+
+	tail := ecsLogsTail
+	if v := r.URL.Query().Get("tail"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 10000 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid tail %q: must be between 1 and 10000", v))
+			return
+		}
+		tail = n
+	}
+	follow := true
+	if v := r.URL.Query().Get("follow"); v != "" {
+		var err error
+		if follow, err = strconv.ParseBool(v); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid follow %q", v))
+			return
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming not supported"))
+		return
+	}
+
+	ctx := r.Context()
+	ecsClient, err := getECSClient(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	logsClient, err := getLogsClient(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	client := frankecs.New(ecsClient, logsClient, ecsCluster)
+	tasks, err := client.FindTasksByProfile(ctx, p.Name)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	if len(tasks) == 0 {
+		writeError(w, http.StatusConflict, fmt.Errorf("profile %q is not running", p.Name))
+		return
+	}
+	taskIDs := make([]string, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
+
+	// Headers go out with the first batch, so a profile whose streams can't
+	// be opened still gets an error response
+	started := false
+	err = client.StreamLogs(ctx, taskIDs, frankecs.LogOptions{
+		Tail:         tail,
+		Follow:       follow,
+		PollInterval: serveLogPollInterval,
+		OnError: func(taskID string, err error) {
+			PrintVerbose("Error fetching logs for %s: %v", taskID, err)
+		},
+	}, func(events []frankecs.LogEvent) error {
+		if !started {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("Connection", "keep-alive")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		} else if len(events) == 0 {
+			// Comment lines keep proxies from closing an idle stream
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return err
+			}
+			flusher.Flush()
+			return nil
+		}
+		if err := writeLogEvents(w, events); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+	if err != nil && !started {
+		writeError(w, http.StatusNotFound, err)
+	}
+}
+
+// claim marks a profile busy, or responds with a conflict if it already is
+func (s *apiServer) claim(w http.ResponseWriter, name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.busy[name] {
+		writeError(w, http.StatusConflict, fmt.Errorf("profile %q has a start or stop in progress", name))
+		return false
+	}
+	s.busy[name] = true
+	return true
+}
+
+func (s *apiServer) release(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.busy, name)
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// lookupAPIProfile loads the profile named in the request path, responding
+// with 404 if it doesn't exist
+func lookupAPIProfile(w http.ResponseWriter, r *http.Request) (*profile.Profile, bool) {
+	name := r.PathValue("name")
+	p, err := profile.GetProfile(name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("profile %q not found", name))
+		return nil, false
+	}
+	return p, true
+}
+
+// runningProfileTasks returns the cluster's tasks keyed by their profile tag
+func runningProfileTasks(ctx context.Context) (map[string]types.Task, error) {
+	client, err := getECSClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := frankecs.New(client, nil, ecsCluster).ListTasks(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make(map[string]types.Task)
+	for _, task := range all {
+		for _, tag := range task.Tags {
+			if aws.ToString(tag.Key) == "frank-profile" {
+				tasks[aws.ToString(tag.Value)] = task
+			}
+		}
+	}
+	return tasks, nil
+}
+
+func newAPIProfile(p *profile.Profile, tasks map[string]types.Task) apiProfile {
+	result := apiProfile{
+		Profile: p,
+		URL:     fmt.Sprintf("https://%s/%s/", frankDomain(), p.Name),
+	}
+	if task, ok := tasks[p.Name]; ok {
+		result.Running = true
+		result.TaskID = frankecs.TaskID(aws.ToString(task.TaskArn))
+		result.Status = aws.ToString(task.LastStatus)
+		result.Owner = frankecs.TagValue(task.Tags, frankecs.OwnerTagKey)
+		result.StartedAt = task.StartedAt
+	}
+	return result
+}
+
+// writeLogEvents sends events in time order, one SSE message each
+func writeLogEvents(w http.ResponseWriter, events []frankecs.LogEvent) error {
+	for _, event := range events {
+		data, err := json.Marshal(apiLogEvent{
+			Timestamp: event.Timestamp.UTC(),
+			TaskID:    event.TaskID,
+			Message:   redact.String(event.Message),
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: log\ndata: %s\n\n", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		PrintVerbose("Failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// generateServeToken returns a random 32-character hex token
+func generateServeToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
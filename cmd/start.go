@@ -1,20 +1,31 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/barff/frank/internal/analytics"
 	"github.com/barff/frank/internal/aws"
 	"github.com/barff/frank/internal/claude"
+	"github.com/barff/frank/internal/config"
 	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/exitcode"
 	"github.com/barff/frank/internal/git"
 	"github.com/barff/frank/internal/notification"
+	"github.com/barff/frank/internal/profile"
 	"github.com/barff/frank/internal/snapshot"
+	"github.com/barff/frank/internal/state"
 	"github.com/barff/frank/internal/terminal"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var startCmd = &cobra.Command{
@@ -25,11 +36,46 @@ var startCmd = &cobra.Command{
 You can provide a local directory path as an argument to mount it into the container,
 or use --repo to clone a git repository.
 
+If a container is already running the same --repo, its URLs are printed and
+you're asked whether to attach to it instead of starting another one.
+--attach reuses it without asking and --new always creates a new container;
+without a terminal to ask on, one of them is required.
+
+--like-ecs copies the environment of the Frank ECS service's task
+definition into the container, so a bug seen on ECS reproduces locally. Its
+secrets are read from Secrets Manager or Parameter Store with your AWS
+credentials; secrets you aren't allowed to read are skipped with a warning.
+Variables frank sets for the local container, such as the ports and AWS
+settings, keep their local values.
+
+--profile-name starts an ECS profile locally: its repo and branch (unless
+--repo, --branch or a local path are given), its agent, provider and model,
+and its recording setting. The container is named after the profile
+(frank-<aws-profile>-<profile>) and labeled frank.ecs-profile, and
+'frank ecs start' points out local containers of the profile it starts.
+
+If the repository has a .frank/services.yaml, its services (e.g. postgres,
+redis) are started as sidecar containers on a shared network, reachable from
+the Claude container by service name. 'frank stop' tears them down.
+
+--network (or container.network) picks the container's network. --network
+frank joins the frank network, created on first use and shared by every
+container started with it, so they reach each other by container name. The
+name of an existing network, such as a compose project's myapp_default, joins
+that network so its services resolve by name. none cuts the container off
+from every network and publishes no ports; reach it with 'frank exec'.
+Sidecars keep their own network, and the container joins both.
+
 Examples:
   frank start /path/to/project -p dev          # Mount local directory
   frank start --repo https://github.com/user/project -p dev  # Clone git repo
+  frank start --repo https://github.com/user/project --attach  # Reuse a running container
   frank start --profile all                    # Just start with AWS credentials
-  frank start --name custom-session --port 9000`,
+  frank start --name custom-session --port 9000
+  frank start --gpus all                       # Pass every NVIDIA GPU through
+  frank start . --network myapp_default        # Join a docker compose project's network
+  frank start --repo https://github.com/user/project --like-ecs
+  frank start --profile-name enkai             # Run the 'enkai' ECS profile locally`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runStart,
 }
@@ -45,6 +91,15 @@ var (
 	startFresh           bool
 	startMountSSH        bool
 	startMountGH         bool
+	startNoServices      bool
+	startRecord          bool
+	startGPUs            string
+	startAttach          bool
+	startNew             bool
+	startLikeECS         bool
+	startProfileName     string
+	startNoHooks         bool
+	startNetwork         string
 )
 
 func init() {
@@ -60,6 +115,17 @@ func init() {
 	startCmd.Flags().BoolVar(&startFresh, "fresh", false, "Force fresh clone, ignore existing snapshot")
 	startCmd.Flags().BoolVar(&startMountSSH, "ssh", false, "Mount ~/.ssh for git SSH authentication")
 	startCmd.Flags().BoolVar(&startMountGH, "gh", false, "Mount ~/.config/gh for GitHub CLI authentication")
+	startCmd.Flags().BoolVar(&startNoServices, "no-services", false, "Don't start sidecars from .frank/services.yaml")
+	startCmd.Flags().BoolVar(&startRecord, "record", false, "Record the Claude terminal to ~/.frank/recordings (see 'frank recordings')")
+	startCmd.Flags().StringVar(&startGPUs, "gpus", "", "GPUs to pass through: all, a count, device=0,1 or none (default: container.gpus)")
+	startCmd.Flags().StringVar(&startNetwork, "network", "", "Network to join: frank (shared by frank containers), an existing network, or none (default: container.network)")
+	startCmd.Flags().BoolVar(&startAttach, "attach", false, "Reuse a running container for the same --repo instead of starting another")
+	startCmd.Flags().BoolVar(&startNew, "new", false, "Start a new container even if one is running for the same --repo")
+	startCmd.Flags().BoolVar(&startLikeECS, "like-ecs", false, "Use the environment and secrets of the ECS service's task definition")
+	startCmd.Flags().StringVar(&startProfileName, "profile-name", "", "Start the repo, branch and agent of an ECS profile locally")
+	startCmd.Flags().BoolVar(&startNoHooks, "no-hooks", false, "Skip the pre-start and post-start hooks (container.hooks)")
+	startCmd.MarkFlagsMutuallyExclusive("attach", "new")
+	addRegistryFlags(startCmd)
 }
 
 func runStart(cmd *cobra.Command, args []string) error {
@@ -81,13 +147,66 @@ func runStart(cmd *cobra.Command, args []string) error {
 		PrintVerbose("Using local path: %s", localPath)
 	}
 
+	// An ECS profile supplies the repo, branch and agent 'ecs start' would use
+	var ecsProfile *profile.Profile
+	var profileEnv []string
+	if startProfileName != "" {
+		var err error
+		ecsProfile, profileEnv, err = loadStartProfile(cmd, localPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := container.ValidateRestartPolicy(cfg.Container.RestartPolicy); err != nil {
+		return exitcode.Errorf(exitcode.Config, "invalid container.restartPolicy: %w", err)
+	}
+	switch cfg.Container.WorkspaceMode {
+	case "", workspaceModeBind, workspaceModeVolume:
+	default:
+		return exitcode.Errorf(exitcode.Config, "invalid container.workspaceMode %q (must be %s or %s)",
+			cfg.Container.WorkspaceMode, workspaceModeBind, workspaceModeVolume)
+	}
+
 	// Detect container runtime
-	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred)
+	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
 	if err != nil {
 		return fmt.Errorf("failed to detect container runtime: %w", err)
 	}
 	PrintVerbose("Using runtime: %s", runtime.Name())
 
+	// A repo that's already running is reused rather than cloned again,
+	// unless --new asks for another container
+	if startRepo != "" && !startNew {
+		existing, err := runningContainersForRepo(runtime, startRepo)
+		if err != nil {
+			PrintVerbose("Warning: failed to check for running containers: %v", err)
+		}
+		if len(existing) > 0 {
+			attach, err := confirmAttach(startRepo, existing)
+			if err != nil {
+				return err
+			}
+			if attach {
+				fmt.Printf("\n%s Attached to %s\n", color.GreenString("✓"), color.CyanString(existing[0].Name))
+				fmt.Printf("Open %s in your browser to access Claude Code.\n", color.CyanString(containerURL(existing[0], 7680)))
+				return nil
+			}
+			fmt.Println()
+		}
+	}
+
+	// Read the ECS environment before anything is set up, so a failure
+	// leaves nothing behind
+	var ecsEnv *ecsTaskEnv
+	if startLikeECS {
+		fmt.Println("Reading the ECS task definition...")
+		ecsEnv, err = loadECSTaskEnv(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to read the ECS environment: %w", err)
+		}
+	}
+
 	// Determine which image to use
 	imageName := cfg.Container.Image
 	usingSnapshot := false
@@ -122,9 +241,21 @@ func runStart(cmd *cobra.Command, args []string) error {
 				PrintVerbose("Warning: failed to check image: %v", err)
 			}
 		}
+		// Images from a registry are pulled, with credentials for private ones
+		if !imageExists && container.HasRegistry(imageName) {
+			fmt.Printf("Pulling %s...\n", color.CyanString(imageName))
+			auth, err := registryAuthFor(context.Background(), imageName)
+			if err != nil {
+				return err
+			}
+			if err := runtime.PullImage(imageName, auth); err != nil {
+				return fmt.Errorf("failed to pull image: %w", err)
+			}
+			imageExists = true
+		}
 		if !imageExists {
 			fmt.Printf("Image %s not found. Run 'frank rebuild' first.\n", cfg.Container.Image)
-			return fmt.Errorf("image not found: %s", cfg.Container.Image)
+			return exitcode.Errorf(exitcode.NotFound, "image not found: %s", cfg.Container.Image)
 		}
 	}
 
@@ -137,6 +268,19 @@ func runStart(cmd *cobra.Command, args []string) error {
 		profile = "default"
 	}
 
+	gpus, err := container.ParseGPUs(containerGPUs(cfg.Container, profile))
+	if err != nil {
+		return err
+	}
+	extraArgs, err := container.ParseExtraArgs(containerExtraArgs(cfg.Container, profile))
+	if err != nil {
+		return err
+	}
+	network, err := resolveNetwork(runtime, containerNetwork(cfg.Container))
+	if err != nil {
+		return err
+	}
+
 	// Generate container name
 	containerName, err := generateContainerName(runtime, profile)
 	if err != nil {
@@ -147,16 +291,31 @@ func runStart(cmd *cobra.Command, args []string) error {
 	// Allocate port
 	portAllocator := terminal.NewPortAllocator(cfg.Container.BasePort, cfg.Container.MaxPort)
 
-	// Mark ports from existing frank containers as used
+	// Leases keep ports reserved for stopped containers that can be resumed
+	registryPath := terminal.RegistryPath(config.GetConfigDir())
+	portRegistry, err := terminal.LoadPortRegistry(registryPath)
+	if err != nil {
+		PrintVerbose("Warning: %v (starting a new registry)", err)
+		portRegistry = terminal.NewPortRegistry(registryPath)
+	}
+	for _, l := range portRegistry.Prune(time.Now()) {
+		PrintVerbose("Port lease for %s (%d) expired", l.Container, l.Port)
+	}
+
+	// Mark ports from existing frank containers as used and renew their leases
 	existingContainers, _ := runtime.ListContainers(container.ContainerFilter{
-		All:        false, // Only running containers
+		All:        true, // Stopped containers still own their port bindings
 		NamePrefix: "frank-",
 	})
 	for _, c := range existingContainers {
 		for _, p := range c.Ports {
 			portAllocator.MarkUsed(p.HostPort, c.Name)
 		}
+		if p, err := strconv.Atoi(c.Labels["frank.port"]); err == nil {
+			portRegistry.Lease(c.Name, p, terminal.DefaultLeaseTTL)
+		}
 	}
+	portRegistry.Reserve(portAllocator)
 
 	port := startPort
 	if port == 0 {
@@ -167,9 +326,15 @@ func runStart(cmd *cobra.Command, args []string) error {
 	}
 	PrintVerbose("Allocated port: %d", port)
 
+	portRegistry.Lease(containerName, port, terminal.DefaultLeaseTTL)
+	if err := portRegistry.Save(); err != nil {
+		PrintVerbose("Warning: %v", err)
+	}
+
 	// Setup AWS credentials
 	var awsEnv []string
 	var awsVolumes []container.VolumeMount
+	var credsDir string
 
 	if profile == "all" {
 		// Mount entire ~/.aws directory
@@ -195,19 +360,34 @@ func runStart(cmd *cobra.Command, args []string) error {
 
 		// Ensure we're logged in
 		if err := ssoManager.EnsureLoggedIn(profile, cfg.AWS.AutoLogin); err != nil {
-			return fmt.Errorf("failed to ensure AWS login: %w", err)
+			return exitcode.Errorf(exitcode.Auth, "failed to ensure AWS login: %w", err)
 		}
 
 		// Get credentials
 		creds, err := ssoManager.GetCredentials(profile)
 		if err != nil {
-			return fmt.Errorf("failed to get AWS credentials: %w", err)
+			return exitcode.Errorf(exitcode.Auth, "failed to get AWS credentials: %w", err)
 		}
 
-		awsEnv = aws.CredentialsToEnv(creds)
+		// Write credentials to a mounted file rather than env vars so
+		// 'frank creds refresh' can renew them while the container runs
+		credsDir = aws.CredentialsDir(containerName)
+		if err := aws.WriteCredentialsFile(credsDir, profile, creds); err != nil {
+			return fmt.Errorf("failed to write AWS credentials: %w", err)
+		}
+		awsVolumes = append(awsVolumes, container.VolumeMount{
+			HostPath:      credsDir,
+			ContainerPath: containerCredsDir,
+			ReadOnly:      true,
+		})
+		awsEnv = append(awsEnv, fmt.Sprintf("AWS_SHARED_CREDENTIALS_FILE=%s/%s", containerCredsDir, aws.CredentialsFileName))
+		if creds.Region != "" {
+			awsEnv = append(awsEnv, fmt.Sprintf("AWS_DEFAULT_REGION=%s", creds.Region))
+			awsEnv = append(awsEnv, fmt.Sprintf("AWS_REGION=%s", creds.Region))
+		}
 		// Also set AWS_PROFILE for MCP servers that need it
 		awsEnv = append(awsEnv, fmt.Sprintf("AWS_PROFILE=%s", profile))
-		PrintVerbose("Injecting AWS credentials for profile: %s", profile)
+		PrintVerbose("Injecting AWS credentials for profile: %s (expires %s)", profile, formatExpiry(creds.Expiration))
 	} else {
 		// Default profile - mount ~/.aws if it exists and set env vars for MCP
 		awsDir := aws.GetAWSDir()
@@ -231,17 +411,12 @@ func runStart(cmd *cobra.Command, args []string) error {
 
 	// Setup MCP configuration
 	mcpManager := claude.NewMCPManager(cfg.MCP.ConfigDir)
-	var mcpServers []claude.MCPServer
-	for _, s := range cfg.MCP.Servers {
-		mcpServers = append(mcpServers, claude.MCPServer{
-			Name:    s.Name,
-			Enabled: s.Enabled,
-		})
-	}
+	mcpServers := containerMCPServers(cfg.MCP.Servers, profile)
+	PrintVerbose("MCP servers: %s", strings.Join(enabledMCPServerNames(mcpServers), ", "))
 
-	mcpConfigPath, err := mcpManager.CreateContainerMCPConfig(mcpServers)
+	mcpConfigPath, err := mcpManager.CreateContainerMCPConfig(containerName, mcpServers)
 	if err != nil {
-		PrintVerbose("Warning: failed to create MCP config: %v", err)
+		fmt.Printf("Warning: failed to create MCP config: %v\n", err)
 	}
 
 	// Setup volumes
@@ -258,7 +433,9 @@ func runStart(cmd *cobra.Command, args []string) error {
 	}
 
 	// Setup workspace: local path > git repo > snapshot
+	var workspaceDir string
 	if localPath != "" {
+		workspaceDir = localPath
 		// Mount local directory directly
 		volumes = append(volumes, container.VolumeMount{
 			HostPath:      localPath,
@@ -266,6 +443,21 @@ func runStart(cmd *cobra.Command, args []string) error {
 			ReadOnly:      false,
 		})
 		PrintVerbose("Mounting local directory: %s", localPath)
+	} else if startRepo != "" && !usingSnapshot && cfg.Container.WorkspaceMode == workspaceModeVolume {
+		// The entrypoint clones into a named volume, which avoids slow bind
+		// mounts on macOS and outlives the container
+		volumeName := workspaceVolumeName(containerName)
+		if err := runtime.CreateVolume(volumeName, map[string]string{
+			workspaceVolumeLabel: containerName,
+			"frank.repo":         startRepo,
+		}); err != nil {
+			return err
+		}
+		volumes = append(volumes, container.VolumeMount{
+			Volume:        volumeName,
+			ContainerPath: cfg.Container.WorkspaceMount,
+		})
+		PrintVerbose("Using workspace volume: %s", volumeName)
 	} else if startRepo != "" && !usingSnapshot {
 		// Clone git repo into worktree
 		worktreeManager := git.NewWorktreeManager(cfg.Git.WorktreeBase)
@@ -280,6 +472,7 @@ func runStart(cmd *cobra.Command, args []string) error {
 			ReadOnly:      false,
 		})
 		PrintVerbose("Created worktree at: %s", worktreePath)
+		workspaceDir = worktreePath
 	} else if usingSnapshot {
 		PrintVerbose("Using snapshot - workspace is already in the container image")
 	}
@@ -314,12 +507,32 @@ func runStart(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Record the Claude terminal to the host's recordings directory
+	if startRecord {
+		recordingsDir := getLocalRecordingsDir()
+		if err := os.MkdirAll(recordingsDir, 0755); err != nil {
+			return fmt.Errorf("failed to create recordings directory: %w", err)
+		}
+		volumes = append(volumes, container.VolumeMount{
+			HostPath:      recordingsDir,
+			ContainerPath: "/recordings",
+		})
+		env = append(env, "FRANK_RECORD=1")
+		PrintVerbose("Recording terminal sessions to %s", recordingsDir)
+	}
+
 	// Setup GitHub authentication
 	if ghToken := GetGitHubToken(); ghToken != "" {
 		env = append(env, fmt.Sprintf("GH_TOKEN=%s", ghToken))
 		PrintVerbose("GitHub token configured")
 	}
 
+	// Setup GitLab/Bitbucket authentication based on the repo host
+	if providerEnv := gitProviderEnv(startRepo); len(providerEnv) > 0 {
+		env = append(env, providerEnv...)
+		PrintVerbose("Git provider credentials configured for %s", startRepo)
+	}
+
 	// Setup Claude authentication
 	// Mount ~/.claude directory for OAuth credentials
 	claudeDir := filepath.Join(getHomeDir(), ".claude")
@@ -338,8 +551,8 @@ func runStart(cmd *cobra.Command, args []string) error {
 	}
 
 	// Setup EnkaiRelay API key
-	if enkai-relayKey := GetEnkaiRelayToken(); enkai-relayKey != "" {
-		env = append(env, fmt.Sprintf("ENKAI_RELAY_API_KEY=%s", enkai-relayKey))
+	if relayKey := GetEnkaiRelayToken(); relayKey != "" {
+		env = append(env, fmt.Sprintf("ENKAI_RELAY_API_KEY=%s", relayKey))
 		PrintVerbose("EnkaiRelay API key configured")
 	}
 
@@ -372,14 +585,30 @@ func runStart(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if ecsProfile != nil {
+		env = mergeECSEnv(env, profileEnv)
+	}
+	if ecsEnv != nil {
+		env = mergeECSEnv(env, ecsEnv.Env)
+	}
+
 	// Create container labels
 	labels := map[string]string{
 		"frank.profile": profile,
 		"frank.port":    fmt.Sprintf("%d", port),
 	}
+	if ecsEnv != nil {
+		labels["frank.like-ecs"] = ecsEnv.TaskDef
+	}
+	if ecsProfile != nil {
+		labels[ecsProfileLabel] = ecsProfile.Name
+	}
 	if startRepo != "" {
 		labels["frank.repo"] = startRepo
 	}
+	if credsDir != "" {
+		labels[credsDirLabel] = credsDir
+	}
 
 	// Create container
 	containerOpts := container.ContainerOptions{
@@ -397,13 +626,68 @@ func runStart(cmd *cobra.Command, args []string) error {
 		TTY:       true,
 		OpenStdin: true,
 		Labels:    labels,
+
+		// Restart the container if ttyd exits, and let the health check
+		// catch the background servers dying
+		HealthCheck:   containerHealthCheck(),
+		RestartPolicy: cfg.Container.RestartPolicy,
+
+		DeviceRequests: gpus,
+		ExtraArgs:      extraArgs,
+		Network:        network,
+	}
+	if network == container.NoNetwork {
+		// Nothing could reach published ports
+		containerOpts.Ports = nil
+	}
+
+	// Pre-start hooks run on the host once the workspace exists
+	hooks := containerHooks(cfg.Container, profile)
+	if startNoHooks {
+		hooks = config.HooksConfig{}
+	}
+	if err := runPreStartHooks(hooks, workspaceDir, hookEnv(containerName, profile, workspaceDir, port)); err != nil {
+		if credsDir != "" {
+			os.RemoveAll(credsDir)
+		}
+		portRegistry.Release(containerName)
+		portRegistry.Save()
+		return err
+	}
+
+	// Sidecars start first so their names resolve once the workspace comes up
+	var services *container.Services
+	if workspaceDir != "" && !startNoServices {
+		services, err = container.LoadServices(workspaceDir)
+		if err != nil {
+			return err
+		}
+	}
+	if services != nil {
+		if network == container.NoNetwork {
+			return exitcode.Errorf(exitcode.Usage, "--network none can't be used with services from %s (use --no-services)", container.ServicesFile)
+		}
+		fmt.Printf("Starting services: %s\n", strings.Join(services.Names(), ", "))
+		if err := container.StartServices(runtime, containerName, services); err != nil {
+			return fmt.Errorf("failed to start services: %w", err)
+		}
+		// Sidecars resolve on their own network; the requested one is joined too
+		if network != "" {
+			containerOpts.Networks = []string{network}
+		}
+		containerOpts.Network = container.NetworkName(containerName)
 	}
 
 	fmt.Printf("Creating container %s...\n", color.CyanString(containerName))
 
 	containerID, err := runtime.CreateContainer(containerOpts)
 	if err != nil {
-		return fmt.Errorf("failed to create container: %w", err)
+		portRegistry.Release(containerName)
+		portRegistry.Save()
+		if services != nil {
+			container.StopServices(runtime, containerName, 0)
+		}
+		return exitcode.Errorf(exitcode.Runtime, "failed to create container: %w", err)
 	}
 	PrintVerbose("Container ID: %s", containerID)
 
@@ -411,15 +695,37 @@ func runStart(cmd *cobra.Command, args []string) error {
 	if err := runtime.StartContainer(containerID); err != nil {
 		// Cleanup on failure
 		runtime.RemoveContainer(containerID, true)
-		return fmt.Errorf("failed to start container: %w", err)
+		if credsDir != "" {
+			os.RemoveAll(credsDir)
+		}
+		portRegistry.Release(containerName)
+		portRegistry.Save()
+		if services != nil {
+			container.StopServices(runtime, containerName, 0)
+		}
+		return exitcode.Errorf(exitcode.Runtime, "failed to start container: %w", err)
 	}
+	runPostStartHooks(runtime, containerID, hooks)
 
 	fmt.Printf("\n%s Container started successfully!\n\n", color.GreenString("✓"))
 	fmt.Printf("  Name:     %s\n", color.CyanString(containerName))
-	fmt.Printf("  Terminal: %s (split view)\n", color.CyanString(fmt.Sprintf("http://localhost:%d", webPort)))
-	fmt.Printf("  Claude:   %s\n", color.YellowString(fmt.Sprintf("http://localhost:%d", claudePort)))
-	fmt.Printf("  Bash:     %s\n", color.YellowString(fmt.Sprintf("http://localhost:%d", bashPort)))
+	if network == container.NoNetwork {
+		fmt.Printf("  Network:  none (no ports published; use 'frank exec %s bash')\n", containerName)
+	} else {
+		fmt.Printf("  Terminal: %s (split view)\n", color.CyanString(fmt.Sprintf("http://localhost:%d", webPort)))
+		fmt.Printf("  Claude:   %s\n", color.YellowString(fmt.Sprintf("http://localhost:%d", claudePort)))
+		fmt.Printf("  Bash:     %s\n", color.YellowString(fmt.Sprintf("http://localhost:%d", bashPort)))
+		if network != "" {
+			fmt.Printf("  Network:  %s\n", network)
+		}
+	}
 	fmt.Printf("  Profile:  %s\n", profile)
+	if len(gpus) > 0 {
+		fmt.Printf("  GPUs:     %s\n", container.FormatGPUs(gpus))
+	}
+	if len(extraArgs) > 0 {
+		fmt.Printf("  Extra:    %s\n", strings.Join(container.FormatExtraArgs(extraArgs), " "))
+	}
 
 	if localPath != "" {
 		fmt.Printf("  Path:     %s\n", localPath)
@@ -428,27 +734,79 @@ func runStart(cmd *cobra.Command, args []string) error {
 		if startBranch != "" {
 			fmt.Printf("  Branch:   %s\n", startBranch)
 		}
+		if cfg.Container.WorkspaceMode == workspaceModeVolume && !usingSnapshot {
+			fmt.Printf("  Volume:   %s\n", workspaceVolumeName(containerName))
+		}
 		if usingSnapshot {
 			fmt.Printf("  Image:    %s (snapshot)\n", color.GreenString(imageName))
 		}
 	}
+	if services != nil {
+		fmt.Printf("  Services: %s\n", strings.Join(services.Names(), ", "))
+	}
+	if ecsProfile != nil {
+		fmt.Printf("  ECS:      profile %s (hand off with 'frank stop %s' then 'frank ecs start %s')\n", ecsProfile.Name, containerName, ecsProfile.Name)
+	}
+	if ecsEnv != nil {
+		fmt.Printf("  ECS env:  %s (%d variables, %d secrets)\n", ecsEnv.TaskDef, len(ecsEnv.Env)-ecsEnv.Secrets, ecsEnv.Secrets)
+	}
 
 	fmt.Println()
 
-	// Start notification monitor if enabled
-	if !startNoNotifications && cfg.Notifications.Enabled {
+	repo := startRepo
+	if localPath != "" {
+		repo = localPath
+	}
+	recordAnalytics(analytics.Record{
+		Type:    analytics.TypeSessionStart,
+		Session: containerName,
+		Profile: profile,
+		Repo:    repo,
+		Branch:  startBranch,
+		Image:   imageName,
+		Runtime: runtime.Name(),
+	})
+	run := state.Run{
+		Kind:    state.KindContainer,
+		Name:    containerName,
+		ID:      containerID,
+		Profile: profile,
+		Repo:    repo,
+		Branch:  startBranch,
+		Image:   imageName,
+		Runtime: runtime.Name(),
+		Ports:   []int{webPort, claudePort, bashPort, statusPort},
+	}
+	if usingSnapshot {
+		run.FromSnapshot = imageName
+	}
+	recordState(func(s *state.Store) error { return s.Start(run) })
+
+	// Start notification monitor if enabled. It polls the status port, which
+	// an isolated container doesn't publish.
+	if !startNoNotifications && cfg.Notifications.Enabled && network != container.NoNetwork {
 		fmt.Println("Starting notification monitor...")
-		monitor := notification.NewMonitor(
+		monitor, err := notification.NewMonitor(
 			containerID,
 			containerName,
+			profile,
+			statusPort,
 			runtime,
 			cfg.Notifications,
 		)
-		go monitor.Start()
+		if err != nil {
+			PrintError("Failed to start notification monitor: %v", err)
+		} else {
+			go monitor.Start()
+		}
+	}
+
+	if credsDir != "" {
+		fmt.Printf("AWS credentials expire periodically; run 'frank creds refresh %s --watch' to keep them fresh.\n", containerName)
 	}
 
 	// If not detached, show instructions
-	if !startDetach {
+	if !startDetach && network != container.NoNetwork {
 		fmt.Printf("Open %s in your browser to access Claude Code.\n", color.CyanString(fmt.Sprintf("http://localhost:%d", port)))
 		fmt.Printf("Use 'frank stop %s' to stop the container.\n", containerName)
 	}
@@ -456,7 +814,118 @@ func runStart(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runningContainersForRepo returns the running frank containers started
+// with repo, newest first
+func runningContainersForRepo(rt container.Runtime, repo string) ([]container.Container, error) {
+	containers, err := rt.ListContainers(container.ContainerFilter{NamePrefix: "frank-"})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []container.Container
+	for _, c := range containers {
+		if existing := c.Labels["frank.repo"]; existing != "" && !container.IsSidecar(c) && snapshot.SameRepo(existing, repo) {
+			matches = append(matches, c)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Created.After(matches[j].Created)
+	})
+	return matches, nil
+}
+
+// confirmAttach lists the containers already running repo and decides
+// whether to reuse the newest: yes with --attach, otherwise by asking
+func confirmAttach(repo string, existing []container.Container) (bool, error) {
+	fmt.Printf("%s is already running in:\n\n", color.CyanString(repo))
+	for _, c := range existing {
+		fmt.Printf("  %s\n", color.CyanString(c.Name))
+		fmt.Printf("    Terminal: %s (split view)\n", containerURL(c, 7680))
+		fmt.Printf("    Claude:   %s\n", containerURL(c, 7681))
+		fmt.Printf("    Bash:     %s\n", containerURL(c, 7682))
+	}
+	fmt.Println()
+
+	if startAttach {
+		return true, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, fmt.Errorf("%s is already running; use --attach to reuse %s or --new to start another container", repo, existing[0].Name)
+	}
+	w := &wizard{reader: bufio.NewReader(os.Stdin)}
+	return w.confirm(fmt.Sprintf("Attach to %s instead of starting a new container?", existing[0].Name), true), nil
+}
+
+// containerURL returns the localhost URL of a container port, or "-" when
+// it isn't published
+func containerURL(c container.Container, containerPort int) string {
+	for _, p := range c.Ports {
+		if p.ContainerPort == containerPort && p.HostPort != 0 {
+			return fmt.Sprintf("http://localhost:%d", p.HostPort)
+		}
+	}
+	return "-"
+}
+
 // generateContainerName generates a unique container name
+// containerGPUs returns the GPU spec for a container: --gpus, then the
+// profile's entry in container.profileGPUs (whose keys viper lowercases),
+// then container.gpus
+func containerGPUs(cc config.ContainerConfig, profile string) string {
+	if startGPUs != "" {
+		return startGPUs
+	}
+	if spec, ok := cc.ProfileGPUs[strings.ToLower(profile)]; ok {
+		return spec
+	}
+	return cc.GPUs
+}
+
+// containerNetwork returns the network mode for a container: --network, then
+// container.network
+func containerNetwork(cc config.ContainerConfig) string {
+	if startNetwork != "" {
+		return startNetwork
+	}
+	return cc.Network
+}
+
+// resolveNetwork returns the network a --network mode attaches a container
+// to: "" for the runtime's default, none, the shared frank network (created
+// on first use) or an existing network such as a compose project's
+func resolveNetwork(rt container.Runtime, mode string) (string, error) {
+	switch mode {
+	case "", "bridge", "default":
+		return "", nil
+	case "host":
+		return "", exitcode.Errorf(exitcode.Usage, "--network host isn't supported: frank containers publish the same container ports")
+	case container.NoNetwork:
+		return container.NoNetwork, nil
+	case container.SharedNetwork:
+		if err := rt.CreateNetwork(container.SharedNetwork, nil); err != nil {
+			return "", err
+		}
+		return container.SharedNetwork, nil
+	}
+
+	exists, err := rt.NetworkExists(mode)
+	if err != nil {
+		return "", fmt.Errorf("failed to check network %s: %w", mode, err)
+	}
+	if !exists {
+		return "", exitcode.Errorf(exitcode.NotFound, "network %s not found (start its compose project first, or use --network frank)", mode)
+	}
+	return mode, nil
+}
+
+// containerExtraArgs returns the extra create flags for a container:
+// container.extraCreateArgs followed by the profile's entry in
+// container.profileExtraCreateArgs
+func containerExtraArgs(cc config.ContainerConfig, profile string) []string {
+	args := append([]string{}, cc.ExtraCreateArgs...)
+	return append(args, cc.ProfileExtraCreateArgs[strings.ToLower(profile)]...)
+}
+
 func generateContainerName(rt container.Runtime, profile string) (string, error) {
 	if startName != "" {
 		return fmt.Sprintf("frank-%s-%s", profile, startName), nil
@@ -487,10 +956,37 @@ func generateContainerName(rt container.Runtime, profile string) (string, error)
 		}
 	}
 
-	return fmt.Sprintf("frank-%s-%d", profile, maxIndex+1), nil
+	// The history remembers indexes of removed containers too, so a name is
+	// never given to a second container
+	index := maxIndex + 1
+	recordState(func(s *state.Store) error {
+		next, err := s.NextIndex(profile, maxIndex)
+		if err == nil {
+			index = next
+		}
+		return err
+	})
+
+	return fmt.Sprintf("frank-%s-%d", profile, index), nil
 }
 
 // getHomeDir returns the user's home directory
+// containerHealthCheck returns the configured health check, or nil to keep
+// the image's HEALTHCHECK
+func containerHealthCheck() *container.HealthCheck {
+	hc := cfg.Container.HealthCheck
+	if hc.Command == "" {
+		return nil
+	}
+	return &container.HealthCheck{
+		Command:     hc.Command,
+		Interval:    hc.Interval,
+		Timeout:     hc.Timeout,
+		StartPeriod: hc.StartPeriod,
+		Retries:     hc.Retries,
+	}
+}
+
 func getHomeDir() string {
 	if home := os.Getenv("HOME"); home != "" {
 		return home
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/barff/frank/internal/agent"
+	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/barff/frank/internal/profile"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// ecsProfileLabel names the ECS profile a local container was started from
+const ecsProfileLabel = "frank.ecs-profile"
+
+// loadStartProfile reads the ECS profile named by --profile-name and fills
+// in the start flags the command line didn't set: repo and branch (unless a
+// local path is given), the name suffix and recording. It returns the
+// profile's agent environment, the same variables 'ecs start' passes the task.
+func loadStartProfile(cmd *cobra.Command, localPath string) (*profile.Profile, []string, error) {
+	p, err := profile.GetProfile(startProfileName)
+	if err != nil {
+		return nil, nil, exitcode.Errorf(exitcode.NotFound, "profile %q not found. Create it with: frank profile add %s --repo <url>", startProfileName, startProfileName)
+	}
+	worker, err := agent.Resolve(p.AgentConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid agent settings for profile %s: %w", p.Name, err)
+	}
+
+	flags := cmd.Flags()
+	if localPath == "" && !flags.Changed("repo") {
+		startRepo = p.Repo
+		if !flags.Changed("branch") {
+			startBranch = p.Branch
+		}
+	}
+	if !flags.Changed("name") {
+		startName = p.Name
+	}
+	if !flags.Changed("record") && p.Record {
+		startRecord = true
+	}
+
+	env := make([]string, 0, len(worker.Env)+1)
+	for _, name := range worker.EnvNames() {
+		env = append(env, name+"="+worker.Env[name])
+	}
+	if secrets := worker.SecretsEnv(); secrets != "" {
+		env = append(env, "FRANK_SECRETS="+secrets)
+	}
+	PrintVerbose("Profile %s: agent %s, provider %s", p.Name, worker.Agent, worker.Provider)
+	return p, env, nil
+}
+
+// warnLocalProfileContainers points out local containers started with
+// --profile-name for the profile 'ecs start' is about to run, so their
+// work can be stopped and snapshotted before the task takes over
+func warnLocalProfileContainers(profileName string) {
+	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
+	if err != nil {
+		PrintVerbose("Not checking for local containers: %v", err)
+		return
+	}
+	containers, err := runtime.ListContainers(container.ContainerFilter{
+		Labels: map[string]string{ecsProfileLabel: profileName},
+	})
+	if err != nil || len(containers) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(containers))
+	for _, c := range containers {
+		names = append(names, c.Name)
+	}
+	sort.Strings(names)
+	fmt.Printf("%s profile %q is also running locally in:\n", color.YellowString("Note:"), profileName)
+	for _, name := range names {
+		fmt.Printf("  %s (stop it with 'frank stop %s' to keep its work)\n", color.CyanString(name), name)
+	}
+	fmt.Println()
+}
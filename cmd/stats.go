@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/barff/frank/internal/render"
+	"github.com/docker/go-units"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [container...]",
+	Short: "Show CPU, memory, network and disk use of running containers",
+	Long: `Show a snapshot of each running frank container's resource use: CPU,
+memory against its limit, network and block IO since it started, process
+count, and the size of its workspace on this machine.
+
+Without arguments every running frank container is shown. CPU is a
+percentage of one CPU, so a container keeping two CPUs busy shows 200%.
+WORKSPACE is the size of the bind-mounted worktree or local directory; it is
+"-" for workspaces in a named volume or baked into a snapshot.
+
+Examples:
+  frank stats                       # All running frank containers
+  frank stats frank-dev-1           # One container
+  frank stats --watch               # Refresh until interrupted
+  frank stats --format json         # Raw numbers for scripts`,
+	RunE: runStats,
+}
+
+var (
+	statsWatch    bool
+	statsInterval time.Duration
+	statsOutput   render.Options
+)
+
+// workspaceSizeTTL is how long a workspace size is reused in watch mode,
+// since walking a large worktree takes longer than sampling stats
+const workspaceSizeTTL = 30 * time.Second
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().BoolVarP(&statsWatch, "watch", "w", false, "Refresh the table until interrupted")
+	statsCmd.Flags().DurationVar(&statsInterval, "interval", defaultWatchInterval, "Refresh interval for --watch")
+	render.AddFlags(statsCmd, &statsOutput)
+}
+
+// containerStats is one container's row
+type containerStats struct {
+	Name      string
+	Stats     *container.Stats
+	Workspace string // Host directory mounted as the workspace; empty if none
+	Size      int64  // Workspace size in bytes, -1 when unknown
+	Err       error
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	if err := statsOutput.Validate(); err != nil {
+		return err
+	}
+
+	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
+	if err != nil {
+		return fmt.Errorf("failed to detect container runtime: %w", err)
+	}
+	PrintVerbose("Using runtime: %s", runtime.Name())
+
+	sizes := newWorkspaceSizes()
+	if statsWatch {
+		if statsOutput.Structured() {
+			return fmt.Errorf("--watch only supports table output")
+		}
+		return watchLoop(statsInterval, func() error {
+			rows, err := collectStats(runtime, args, sizes)
+			if err != nil {
+				return err
+			}
+			return outputStatsTable(rows)
+		})
+	}
+
+	rows, err := collectStats(runtime, args, sizes)
+	if err != nil {
+		return err
+	}
+	switch statsOutput.Format {
+	case render.FormatJSON:
+		data, err := json.MarshalIndent(statsRecords(rows), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	case render.FormatYAML:
+		return yaml.NewEncoder(os.Stdout).Encode(statsRecords(rows))
+	}
+	return outputStatsTable(rows)
+}
+
+// collectStats samples the named containers, or every running frank
+// container, in parallel
+func collectStats(runtime container.Runtime, names []string, sizes *workspaceSizes) ([]containerStats, error) {
+	if len(names) == 0 {
+		containers, err := listFrankContainers(runtime)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range containers {
+			names = append(names, c.Name)
+		}
+	} else {
+		for _, name := range names {
+			c, err := runtime.GetContainer(name)
+			if err != nil {
+				return nil, exitcode.Errorf(exitcode.NotFound, "container not found: %s", name)
+			}
+			// Inspect reports "running"; listings report "Up 5 minutes"
+			if state := containerState(c.Status); state != "running" && state != "up" {
+				return nil, fmt.Errorf("container %s is not running", name)
+			}
+		}
+	}
+
+	rows := make([]containerStats, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			row := containerStats{Name: name, Size: -1}
+			row.Stats, row.Err = runtime.ContainerStats(name)
+			if row.Workspace = workspaceDir(runtime, name); row.Workspace != "" {
+				row.Size = sizes.get(row.Workspace)
+			}
+			rows[i] = row
+		}(i, name)
+	}
+	wg.Wait()
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+	return rows, nil
+}
+
+// workspaceDir returns the host directory mounted at the workspace mount
+// point, or "" for a volume or snapshot workspace
+func workspaceDir(runtime container.Runtime, name string) string {
+	opts, err := runtime.InspectContainer(name)
+	if err != nil {
+		return ""
+	}
+	for _, v := range opts.Volumes {
+		if v.ContainerPath == cfg.Container.WorkspaceMount && v.Volume == "" {
+			return v.HostPath
+		}
+	}
+	return ""
+}
+
+func outputStatsTable(rows []containerStats) error {
+	if len(rows) == 0 {
+		fmt.Println("No running frank containers found")
+		return nil
+	}
+
+	table := render.NewTable(statsOutput,
+		render.Column{Header: "NAME"},
+		render.Column{Header: "CPU %"},
+		render.Column{Header: "MEM USAGE / LIMIT", Hide: 4},
+		render.Column{Header: "MEM %"},
+		render.Column{Header: "NET I/O", Hide: 2},
+		render.Column{Header: "BLOCK I/O", Hide: 3},
+		render.Column{Header: "PIDS", Hide: 5},
+		render.Column{Header: "WORKSPACE", Hide: 1},
+	)
+	for _, row := range rows {
+		workspace := "-"
+		if row.Size >= 0 {
+			workspace = units.HumanSize(float64(row.Size))
+		}
+		if row.Err != nil {
+			PrintVerbose("Warning: failed to read stats of %s: %v", row.Name, row.Err)
+			table.Append(row.Name, color.RedString("error"), "-", "-", "-", "-", "-", workspace)
+			continue
+		}
+		s := row.Stats
+		table.Append(
+			row.Name,
+			formatPercent(s.CPUPercent, 200, 90),
+			units.BytesSize(float64(s.MemoryUsage))+" / "+units.BytesSize(float64(s.MemoryLimit)),
+			formatPercent(s.MemoryPercent(), 90, 75),
+			units.HumanSize(float64(s.NetRx))+" / "+units.HumanSize(float64(s.NetTx)),
+			units.HumanSize(float64(s.BlockRead))+" / "+units.HumanSize(float64(s.BlockWrite)),
+			fmt.Sprintf("%d", s.PIDs),
+			workspace,
+		)
+	}
+	return table.Render(os.Stdout)
+}
+
+// formatPercent colors a percentage red from high and yellow from warn
+func formatPercent(pct, high, warn float64) string {
+	s := fmt.Sprintf("%.1f%%", pct)
+	switch {
+	case pct >= high:
+		return color.RedString(s)
+	case pct >= warn:
+		return color.YellowString(s)
+	}
+	return s
+}
+
+// statsRecords returns the rows with raw numbers for JSON and YAML output
+func statsRecords(rows []containerStats) []map[string]interface{} {
+	records := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		r := map[string]interface{}{"name": row.Name}
+		if row.Workspace != "" {
+			r["workspace"] = row.Workspace
+		}
+		if row.Size >= 0 {
+			r["workspace_bytes"] = row.Size
+		}
+		if row.Err != nil {
+			r["error"] = row.Err.Error()
+			records = append(records, r)
+			continue
+		}
+		s := row.Stats
+		r["cpu_percent"] = s.CPUPercent
+		r["memory_bytes"] = s.MemoryUsage
+		r["memory_limit_bytes"] = s.MemoryLimit
+		r["memory_percent"] = s.MemoryPercent()
+		r["net_rx_bytes"] = s.NetRx
+		r["net_tx_bytes"] = s.NetTx
+		r["block_read_bytes"] = s.BlockRead
+		r["block_write_bytes"] = s.BlockWrite
+		r["pids"] = s.PIDs
+		records = append(records, r)
+	}
+	return records
+}
+
+// workspaceSizes caches workspace sizes between watch refreshes
+type workspaceSizes struct {
+	mu      sync.Mutex
+	entries map[string]workspaceSize
+}
+
+type workspaceSize struct {
+	bytes int64
+	at    time.Time
+}
+
+func newWorkspaceSizes() *workspaceSizes {
+	return &workspaceSizes{entries: make(map[string]workspaceSize)}
+}
+
+// get returns the size of dir, measuring it again once workspaceSizeTTL has
+// passed, or -1 if it can't be read
+func (w *workspaceSizes) get(dir string) int64 {
+	w.mu.Lock()
+	entry, ok := w.entries[dir]
+	w.mu.Unlock()
+	if ok && time.Since(entry.at) < workspaceSizeTTL {
+		return entry.bytes
+	}
+
+	size, err := dirSize(dir)
+	if err != nil {
+		PrintVerbose("Warning: failed to measure %s: %v", dir, err)
+		return -1
+	}
+	w.mu.Lock()
+	w.entries[dir] = workspaceSize{bytes: size, at: time.Now()}
+	w.mu.Unlock()
+	return size
+}
+
+// dirSize totals the sizes of the regular files under dir. Unreadable
+// entries are skipped.
+func dirSize(dir string) (int64, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return 0, err
+	}
+	var total int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() && path != dir {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total, err
+}
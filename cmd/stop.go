@@ -1,13 +1,18 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/barff/frank/internal/analytics"
+	"github.com/barff/frank/internal/config"
 	"github.com/barff/frank/internal/container"
 	"github.com/barff/frank/internal/git"
 	"github.com/barff/frank/internal/snapshot"
+	"github.com/barff/frank/internal/state"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -18,15 +23,22 @@ var stopCmd = &cobra.Command{
 	Long: `Stop one or more frank containers.
 
 When stopping a container:
-1. Git worktrees are cleaned up (can be disabled with --no-cleanup)
-2. Container state is persisted to a timestamped image (can be disabled with --no-snapshot)
+1. The pre-stop hook (container.preStop.command) runs inside it, by default
+   asking Claude to write a checkpoint to .frank/checkpoint.md; it is skipped
+   with --no-pre-stop or --force and given container.preStop.timeout to finish
+2. Uncommitted worktree changes are committed (can be disabled with --no-commit)
+   and, with --push or git.autoPush, pushed to a frank/<container> branch
+3. Git worktrees are cleaned up (can be disabled with --no-cleanup)
+4. Container state is persisted to a timestamped image (can be disabled with --no-snapshot)
+5. Sidecar services from .frank/services.yaml are removed
 
 Examples:
   frank stop frank-dev-1
   frank stop frank-dev-1 frank-prod-2
   frank stop --profile dev
   frank stop --all
-  frank stop --all --force --no-snapshot`,
+  frank stop --all --force --no-snapshot
+  frank stop frank-dev-1 --push`,
 	RunE: runStop,
 }
 
@@ -37,6 +49,9 @@ var (
 	stopTimeout    time.Duration
 	stopNoSnapshot bool
 	stopNoCleanup  bool
+	stopNoCommit   bool
+	stopPush       bool
+	stopNoPreStop  bool
 )
 
 func init() {
@@ -48,10 +63,13 @@ func init() {
 	stopCmd.Flags().DurationVar(&stopTimeout, "timeout", 10*time.Second, "Timeout before force stop")
 	stopCmd.Flags().BoolVar(&stopNoSnapshot, "no-snapshot", false, "Skip persisting container state to image")
 	stopCmd.Flags().BoolVar(&stopNoCleanup, "no-cleanup", false, "Skip git worktree cleanup")
+	stopCmd.Flags().BoolVar(&stopNoCommit, "no-commit", false, "Skip committing uncommitted worktree changes")
+	stopCmd.Flags().BoolVar(&stopPush, "push", false, "Push the auto-commit to a frank/<container> branch (default: git.autoPush)")
+	stopCmd.Flags().BoolVar(&stopNoPreStop, "no-pre-stop", false, "Skip the pre-stop hook (container.preStop.command)")
 }
 
 func runStop(cmd *cobra.Command, args []string) error {
-	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred)
+	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
 	if err != nil {
 		return fmt.Errorf("failed to detect container runtime: %w", err)
 	}
@@ -70,7 +88,7 @@ func runStop(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to list containers: %w", err)
 		}
 		for _, c := range containers {
-			if strings.HasPrefix(c.Name, "frank-") {
+			if strings.HasPrefix(c.Name, "frank-") && !container.IsSidecar(c) {
 				containersToStop = append(containersToStop, c)
 			}
 		}
@@ -83,7 +101,7 @@ func runStop(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to list containers: %w", err)
 		}
 		for _, c := range containers {
-			if strings.HasPrefix(c.Name, fmt.Sprintf("frank-%s-", stopProfile)) {
+			if strings.HasPrefix(c.Name, fmt.Sprintf("frank-%s-", stopProfile)) && !container.IsSidecar(c) {
 				containersToStop = append(containersToStop, c)
 			}
 		}
@@ -121,7 +139,17 @@ func runStop(cmd *cobra.Command, args []string) error {
 func stopContainer(runtime container.Runtime, worktreeManager *git.WorktreeManager, c container.Container) error {
 	fmt.Printf("  Stopping %s...\n", c.Name)
 
-	// Step 1: Clean up git worktree
+	// Step 0: Let the session checkpoint before anything is torn down
+	if !stopNoPreStop && !stopForce && cfg.Container.PreStop.Command != "" {
+		runPreStopHook(runtime, c, cfg.Container.PreStop)
+	}
+
+	// Step 1: Save uncommitted work before the worktree can be removed
+	if !stopNoCommit && cfg.Git.AutoCommit {
+		autoCommitWorktree(worktreeManager.GetPath(c.Name), c.Name)
+	}
+
+	// Step 2: Clean up git worktree
 	if !stopNoCleanup && cfg.Git.CleanupOnStop {
 		PrintVerbose("  Cleaning up git worktree for %s", c.Name)
 		if err := worktreeManager.Remove(c.Name); err != nil {
@@ -129,7 +157,8 @@ func stopContainer(runtime container.Runtime, worktreeManager *git.WorktreeManag
 		}
 	}
 
-	// Step 2: Persist container state to image
+	// Step 3: Persist container state to image
+	var snapshots []string
 	if !stopNoSnapshot {
 		// Create timestamped snapshot
 		timestampedName := fmt.Sprintf("%s-snapshot:%s", c.Name, time.Now().Format("20060102-150405"))
@@ -138,6 +167,7 @@ func stopContainer(runtime container.Runtime, worktreeManager *git.WorktreeManag
 			PrintVerbose("  Warning: failed to create snapshot: %v", err)
 		} else {
 			fmt.Printf("    Snapshot saved: %s\n", color.CyanString(timestampedName))
+			snapshots = append(snapshots, timestampedName)
 		}
 
 		// Also create repo-based snapshot with :latest tag for auto-resume
@@ -147,13 +177,14 @@ func stopContainer(runtime container.Runtime, worktreeManager *git.WorktreeManag
 			if err := runtime.CommitContainer(c.ID, repoSnapshotName); err != nil {
 				PrintVerbose("  Warning: failed to create repo snapshot: %v", err)
 			} else {
+				snapshots = append(snapshots, repoSnapshotName)
 				fmt.Printf("    Repo snapshot saved: %s\n", color.CyanString(repoSnapshotName))
 				fmt.Println("    (Next 'frank start' with this repo will resume from this snapshot)")
 			}
 		}
 	}
 
-	// Step 3: Stop the container
+	// Step 4: Stop the container
 	timeout := stopTimeout
 	if stopForce {
 		timeout = 0
@@ -163,6 +194,111 @@ func stopContainer(runtime container.Runtime, worktreeManager *git.WorktreeManag
 		return fmt.Errorf("failed to stop container: %w", err)
 	}
 
+	// Step 5: Tear down sidecar services and their network
+	if err := container.StopServices(runtime, c.Name, timeout); err != nil {
+		PrintVerbose("  Warning: failed to remove services: %v", err)
+	}
+
+	// Step 6: Remove refreshable AWS credentials written at start
+	if credsDir, ok := c.Labels[credsDirLabel]; ok && credsDir != "" {
+		if err := os.RemoveAll(credsDir); err != nil {
+			PrintVerbose("  Warning: failed to remove AWS credentials: %v", err)
+		}
+	}
+
+	record := analytics.Record{
+		Type:    analytics.TypeSessionStop,
+		Session: c.Name,
+		Profile: c.Labels["frank.profile"],
+		Repo:    c.Labels["frank.repo"],
+		Image:   c.Image,
+		Runtime: runtime.Name(),
+	}
+	if !c.Created.IsZero() {
+		record.Duration = time.Since(c.Created).Seconds()
+	}
+	recordAnalytics(record)
+
+	reason := "Stopped by frank stop"
+	if stopForce {
+		reason = "Force stopped by frank stop"
+	}
+	recordState(func(s *state.Store) error { return s.Stop(c.Name, reason, snapshots...) })
+
 	fmt.Printf("    %s stopped\n", color.GreenString(c.Name))
 	return nil
 }
+
+// autoCommitWorktree commits uncommitted changes in a container's worktree
+// and optionally pushes them to frank/<container>. Failures are reported but
+// never stop the container.
+func autoCommitWorktree(dir, containerName string) {
+	if _, err := os.Stat(dir); err != nil {
+		PrintVerbose("  No worktree for %s, skipping auto-commit", containerName)
+		return
+	}
+
+	changed, err := git.HasChanges(dir)
+	if err != nil {
+		PrintVerbose("  Warning: %v", err)
+		return
+	}
+	if changed {
+		message := git.ExpandCommitMessage(cfg.Git.AutoCommitMessage, containerName, time.Now())
+		sha, err := git.CommitAll(dir, message)
+		if err != nil {
+			fmt.Printf("    %s auto-commit failed: %v\n", color.YellowString("Warning:"), err)
+			return
+		}
+		fmt.Printf("    Committed uncommitted changes: %s\n", color.CyanString(sha))
+	} else {
+		PrintVerbose("  No uncommitted changes in %s", dir)
+	}
+
+	// Pushing also covers commits made in the container but never pushed
+	if !stopPush && !cfg.Git.AutoPush {
+		return
+	}
+	branch := "frank/" + containerName
+	if err := git.PushHead(dir, branch); err != nil {
+		fmt.Printf("    %s %v\n", color.YellowString("Warning:"), err)
+		return
+	}
+	fmt.Printf("    Pushed to %s\n", color.CyanString(branch))
+}
+
+// runPreStopHook runs the pre-stop command in a running container, giving up
+// on it after the configured timeout. Failures are reported but never stop
+// the container.
+func runPreStopHook(runtime container.Runtime, c container.Container, hook config.PreStopConfig) {
+	if !strings.HasPrefix(containerState(c.Status), "up") {
+		return
+	}
+	PrintVerbose("  Running pre-stop hook: %s", hook.Command)
+
+	var output bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		done <- runtime.ExecInContainer(c.ID, []string{"/bin/sh", "-c", hook.Command}, container.ExecOptions{
+			TTY:    true,
+			Stdout: &output,
+		})
+	}()
+
+	// The exec keeps running after a timeout until the stop ends it
+	select {
+	case err := <-done:
+		if err != nil {
+			fmt.Printf("    %s pre-stop hook failed: %v\n", color.YellowString("Warning:"), err)
+			return
+		}
+		for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				PrintVerbose("    %s", line)
+			}
+		}
+		fmt.Println("    Pre-stop hook finished")
+	case <-time.After(hook.Timeout):
+		fmt.Printf("    %s pre-stop hook still running after %s, stopping anyway\n", color.YellowString("Warning:"), hook.Timeout)
+	}
+}
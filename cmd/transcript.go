@@ -0,0 +1,343 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/barff/frank/internal/analytics"
+	frankaws "github.com/barff/frank/internal/aws"
+	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/exitcode"
+	"github.com/barff/frank/internal/transcript"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var transcriptCmd = &cobra.Command{
+	Use:   "transcript <container-or-task>",
+	Short: "Export a Claude session transcript as Markdown",
+	Long: `Read a Claude Code conversation from a container or an ECS task and render
+it as Markdown.
+
+Transcripts are the JSONL files under ~/.claude/projects in the container.
+The argument is a local container name, or an ECS profile name or task ID
+when no such container exists. The most recent session is exported unless
+--session picks another; --list shows them all.
+
+With --upload the Markdown is also stored in the analytics bucket under
+transcripts/YYYY/MM/DD/<profile>/<session>.md.
+
+Examples:
+  frank transcript frank-dev-1                      # Latest session to stdout
+  frank transcript frank-dev-1 --list               # Sessions, newest first
+  frank transcript frank-dev-1 --session 3f2a -o session.md
+  frank transcript enkai --upload                   # ECS profile, to S3
+  frank transcript frank-dev-1 --raw > session.jsonl`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTranscript,
+}
+
+var (
+	transcriptSession  string
+	transcriptList     bool
+	transcriptOutput   string
+	transcriptRaw      bool
+	transcriptThinking bool
+	transcriptMaxLines int
+	transcriptUpload   bool
+)
+
+func init() {
+	rootCmd.AddCommand(transcriptCmd)
+
+	transcriptCmd.Flags().StringVar(&transcriptSession, "session", "", "Session ID or ID prefix (default: most recent)")
+	transcriptCmd.Flags().BoolVar(&transcriptList, "list", false, "List the sessions instead of exporting one")
+	transcriptCmd.Flags().StringVarP(&transcriptOutput, "output", "o", "", "Write to this file instead of stdout")
+	transcriptCmd.Flags().BoolVar(&transcriptRaw, "raw", false, "Write the JSONL transcript unchanged")
+	transcriptCmd.Flags().BoolVar(&transcriptThinking, "thinking", false, "Include Claude's thinking")
+	transcriptCmd.Flags().IntVar(&transcriptMaxLines, "max-output-lines", 40, "Lines of each tool's output to keep (0 keeps all)")
+	transcriptCmd.Flags().BoolVar(&transcriptUpload, "upload", false, "Also upload the Markdown to the analytics bucket")
+	transcriptCmd.Flags().StringVar(&analyticsBucket, "bucket", "", "S3 bucket for --upload (default: analytics.bucket or ANALYTICS_BUCKET)")
+	transcriptCmd.MarkFlagsMutuallyExclusive("list", "raw")
+	transcriptCmd.MarkFlagsMutuallyExclusive("list", "upload")
+}
+
+// transcriptFiles lists Claude's session transcripts, newest first
+const transcriptFiles = `ls -1t "$HOME"/.claude/projects/*/*.jsonl 2>/dev/null; true`
+
+// transcriptSource reads transcripts from a container or a task
+type transcriptSource interface {
+	// list returns the transcript paths, newest first
+	list() ([]string, error)
+	read(p string) ([]byte, error)
+	// profile is the analytics profile uploads are filed under
+	profile() string
+}
+
+func runTranscript(cmd *cobra.Command, args []string) error {
+	if transcriptUpload {
+		if getBucket() == "" {
+			return exitcode.Errorf(exitcode.Config, "S3 bucket not configured. Set ANALYTICS_BUCKET or use --bucket flag")
+		}
+		if err := guardActions(cmd, "s3:PutObject"); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+	src, err := openTranscriptSource(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	paths, err := src.list()
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	if len(paths) == 0 {
+		return exitcode.Errorf(exitcode.NotFound, "no Claude sessions found in %s", args[0])
+	}
+
+	if transcriptList {
+		for _, p := range paths {
+			fmt.Printf("%s  %s\n", sessionID(p), color.HiBlackString(path.Base(path.Dir(p))))
+		}
+		return nil
+	}
+
+	p, err := pickSession(paths, transcriptSession)
+	if err != nil {
+		return err
+	}
+	PrintVerbose("Reading %s", p)
+	data, err := src.read(p)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", p, err)
+	}
+
+	out := data
+	var session *transcript.Session
+	if !transcriptRaw || transcriptUpload {
+		session, err = transcript.Parse(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		if session.ID == "" {
+			session.ID = sessionID(p)
+		}
+		var md bytes.Buffer
+		opts := transcript.Options{Thinking: transcriptThinking, MaxOutputLines: transcriptMaxLines}
+		if err := session.Markdown(&md, opts); err != nil {
+			return err
+		}
+		if !transcriptRaw {
+			out = md.Bytes()
+		}
+		if transcriptUpload {
+			if err := uploadTranscript(ctx, session, src.profile(), md.Bytes()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if transcriptOutput == "" {
+		if transcriptUpload {
+			// The upload message is the output; don't mix the document into it
+			return nil
+		}
+		_, err := os.Stdout.Write(out)
+		return err
+	}
+	if err := os.WriteFile(transcriptOutput, out, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", transcriptOutput, err)
+	}
+	fmt.Printf("%s Wrote %s to %s\n", color.GreenString("✓"), sessionID(p), transcriptOutput)
+	return nil
+}
+
+// openTranscriptSource finds the local container named target, or the ECS
+// task of the profile or task ID target when there is none
+func openTranscriptSource(ctx context.Context, target string) (transcriptSource, error) {
+	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
+	if err == nil {
+		if c, err := runtime.GetContainer(target); err == nil {
+			PrintVerbose("Using container %s (%s)", c.Name, runtime.Name())
+			return &containerTranscripts{runtime: runtime, c: c}, nil
+		}
+	}
+
+	client, err := getECSClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taskID, profileName, err := resolveExecTask(ctx, client, target)
+	if err != nil {
+		if kind, _ := exitcode.KindOf(err); kind == exitcode.NotFound {
+			return nil, exitcode.Errorf(exitcode.NotFound, "no container or ECS task named %s", target)
+		}
+		return nil, err
+	}
+	PrintVerbose("Using task %s", taskID)
+	return &taskTranscripts{client: client, taskID: taskID, profileName: profileName}, nil
+}
+
+// pickSession returns the transcript whose session ID starts with id, or
+// the newest one when id is empty
+func pickSession(paths []string, id string) (string, error) {
+	if id == "" {
+		return paths[0], nil
+	}
+	var matches []string
+	for _, p := range paths {
+		if strings.HasPrefix(sessionID(p), id) {
+			matches = append(matches, p)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", exitcode.Errorf(exitcode.NotFound, "no session matching %q (see --list)", id)
+	case 1:
+		return matches[0], nil
+	}
+	return "", exitcode.Errorf(exitcode.Usage, "%q matches %d sessions; give more of the ID", id, len(matches))
+}
+
+// sessionID is a transcript's file name without .jsonl
+func sessionID(p string) string {
+	return strings.TrimSuffix(path.Base(p), ".jsonl")
+}
+
+// splitLines returns the non-empty lines of a command's terminal output
+func splitLines(output string) []string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// uploadTranscript stores the Markdown in the analytics bucket
+func uploadTranscript(ctx context.Context, session *transcript.Session, profileName string, md []byte) error {
+	awsCfg, err := config.LoadDefaultConfig(ctx, frankaws.WithRetries(), config.WithRegion(getAnalyticsRegion()))
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	started := session.Start
+	if started.IsZero() {
+		started = time.Now()
+	}
+	bucket := getBucket()
+	key := analytics.TranscriptKey(started, profileName, session.ID+".md")
+	_, err = s3.NewFromConfig(awsCfg).PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(md),
+		ContentType: aws.String("text/markdown; charset=utf-8"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload transcript: %w", err)
+	}
+	fmt.Printf("%s Uploaded %s to s3://%s/%s\n", color.GreenString("✓"), session.ID, bucket, key)
+	return nil
+}
+
+// containerTranscripts reads transcripts from a local container
+type containerTranscripts struct {
+	runtime container.Runtime
+	c       *container.Container
+}
+
+func (t *containerTranscripts) list() ([]string, error) {
+	// TTY output isn't multiplexed, so lines arrive intact
+	var out bytes.Buffer
+	err := t.runtime.ExecInContainer(t.c.Name, []string{"sh", "-c", transcriptFiles}, container.ExecOptions{
+		TTY:    true,
+		Stdout: &out,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(out.String()), nil
+}
+
+func (t *containerTranscripts) read(p string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "frank-transcript-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := t.runtime.CopyFromContainer(t.c.Name, p, dir); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(dir, path.Base(p)))
+}
+
+func (t *containerTranscripts) profile() string {
+	if p := t.c.Labels["frank.profile"]; p != "" {
+		return p
+	}
+	return analytics.LocalProfile
+}
+
+// taskTranscripts reads transcripts from an ECS task over ECS Exec
+type taskTranscripts struct {
+	client      *ecs.Client
+	taskID      string
+	profileName string
+}
+
+func (t *taskTranscripts) list() ([]string, error) {
+	var out bytes.Buffer
+	script := `echo ` + execPayloadBegin + `; ` + transcriptFiles + `; echo ` + execPayloadEnd
+	if err := runTaskScript(context.Background(), t.client, t.taskID, script, &execPayloadWriter{out: &out}); err != nil {
+		return nil, err
+	}
+	return splitLines(out.String()), nil
+}
+
+func (t *taskTranscripts) read(p string) ([]byte, error) {
+	// The terminal would mangle control characters, so the file travels
+	// base64 encoded like 'ecs cp'
+	script := shellPathVar(p) +
+		`if [ ! -f "$P" ]; then echo "` + execPayloadError + ` $P: no such file"; exit 1; fi; ` +
+		`echo ` + execPayloadBegin + `; base64 "$P"; echo ` + execPayloadEnd
+
+	var encoded bytes.Buffer
+	payload := &execPayloadWriter{out: &encoded}
+	// The script reports a missing file before exiting non-zero
+	err := runTaskScript(context.Background(), t.client, t.taskID, script, payload)
+	if payload.errMsg != "" {
+		return nil, errors.New(payload.errMsg)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !payload.done {
+		return nil, fmt.Errorf("session ended before the transcript was complete")
+	}
+	return io.ReadAll(base64.NewDecoder(base64.StdEncoding, &encoded))
+}
+
+func (t *taskTranscripts) profile() string {
+	if t.profileName != "" {
+		return t.profileName
+	}
+	return "task-" + t.taskID
+}
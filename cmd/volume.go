@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/barff/frank/internal/container"
+	"github.com/barff/frank/internal/render"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Workspace modes for container.workspaceMode
+const (
+	workspaceModeBind   = "bind"
+	workspaceModeVolume = "volume"
+)
+
+// workspaceVolumeLabel marks a workspace volume with the container it was
+// created for
+const workspaceVolumeLabel = "frank.workspace"
+
+var volumeCmd = &cobra.Command{
+	Use:   "volume",
+	Short: "Manage workspace volumes",
+	Long: `Manage the named volumes that hold container workspaces.
+
+With container.workspaceMode set to volume, 'frank start --repo' creates a
+volume named <container>-workspace and the container clones the repository
+into it instead of a host worktree. Volumes are much faster than bind mounts
+on macOS and are kept when the container stops, restarts or is removed, so
+they have to be removed explicitly.
+
+Examples:
+  frank volume list                 # Show workspace volumes
+  frank volume rm frank-dev-1       # Remove a container's workspace volume
+  frank volume rm --unused          # Remove volumes whose container is gone`,
+}
+
+var (
+	volumeRmUnused bool
+	volumeRmAll    bool
+)
+
+func init() {
+	rootCmd.AddCommand(volumeCmd)
+	volumeCmd.AddCommand(volumeListCmd)
+	volumeCmd.AddCommand(volumeRmCmd)
+
+	volumeRmCmd.Flags().BoolVar(&volumeRmUnused, "unused", false, "Remove volumes whose container no longer exists")
+	volumeRmCmd.Flags().BoolVar(&volumeRmAll, "all", false, "Remove every workspace volume not in use")
+}
+
+// ============================================================================
+// volume list - Show workspace volumes
+// ============================================================================
+
+var volumeListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List workspace volumes",
+	RunE:    runVolumeList,
+}
+
+func runVolumeList(cmd *cobra.Command, args []string) error {
+	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
+	if err != nil {
+		return fmt.Errorf("failed to detect container runtime: %w", err)
+	}
+
+	volumes, err := listWorkspaceVolumes(runtime)
+	if err != nil {
+		return err
+	}
+	if len(volumes) == 0 {
+		fmt.Println("No workspace volumes")
+		return nil
+	}
+
+	states := containerStates()
+
+	table := render.NewTable(render.Options{},
+		render.Column{Header: "VOLUME"},
+		render.Column{Header: "CONTAINER"},
+		render.Column{Header: "STATE"},
+		render.Column{Header: "REPO"},
+		render.Column{Header: "CREATED"},
+	)
+
+	for _, v := range volumes {
+		name := v.Labels[workspaceVolumeLabel]
+		created := "-"
+		if !v.Created.IsZero() {
+			created = v.Created.Local().Format("2006-01-02 15:04")
+		}
+		repo := v.Labels["frank.repo"]
+		if repo == "" {
+			repo = "-"
+		}
+		table.Append(v.Name, name, formatLeaseState(states, name), repo, created)
+	}
+
+	return table.Render(os.Stdout)
+}
+
+// ============================================================================
+// volume rm - Remove workspace volumes
+// ============================================================================
+
+var volumeRmCmd = &cobra.Command{
+	Use:   "rm [volume-or-container...]",
+	Short: "Remove workspace volumes",
+	Long: `Remove workspace volumes, given by volume or container name. The
+repository and any uncommitted or unpushed work in them is lost. A volume
+still used by a container, running or stopped, can't be removed; stop and
+remove the container first.`,
+	RunE: runVolumeRm,
+}
+
+func runVolumeRm(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !volumeRmUnused && !volumeRmAll {
+		return fmt.Errorf("specify volumes or containers to remove, or use --unused or --all")
+	}
+
+	runtime, err := container.DetectRuntime(cfg.Runtime.Preferred, cfg.Runtime.DockerHost)
+	if err != nil {
+		return fmt.Errorf("failed to detect container runtime: %w", err)
+	}
+
+	volumes, err := listWorkspaceVolumes(runtime)
+	if err != nil {
+		return err
+	}
+
+	var targets []string
+	switch {
+	case volumeRmAll:
+		for _, v := range volumes {
+			targets = append(targets, v.Name)
+		}
+	case volumeRmUnused:
+		states := containerStates()
+		if states == nil {
+			return fmt.Errorf("cannot check for removed containers without a container runtime")
+		}
+		for _, v := range volumes {
+			if _, exists := states[v.Labels[workspaceVolumeLabel]]; !exists {
+				targets = append(targets, v.Name)
+			}
+		}
+	}
+
+	for _, arg := range args {
+		found := false
+		for _, v := range volumes {
+			if v.Name == arg || v.Labels[workspaceVolumeLabel] == arg {
+				targets = append(targets, v.Name)
+				found = true
+			}
+		}
+		if !found {
+			PrintError("No workspace volume found for %s", arg)
+		}
+	}
+
+	if len(targets) == 0 {
+		fmt.Println("No volumes removed")
+		return nil
+	}
+
+	var failed int
+	for _, name := range targets {
+		if err := runtime.RemoveVolume(name); err != nil {
+			PrintError("%s: %v", name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s Removed %s\n", color.GreenString("✓"), name)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to remove %d volume(s)", failed)
+	}
+	return nil
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// workspaceVolumeName returns the workspace volume of a container
+func workspaceVolumeName(containerName string) string {
+	return containerName + "-workspace"
+}
+
+// listWorkspaceVolumes returns frank's workspace volumes sorted by name
+func listWorkspaceVolumes(runtime container.Runtime) ([]container.Volume, error) {
+	all, err := runtime.ListVolumes(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var volumes []container.Volume
+	for _, v := range all {
+		if v.Labels[workspaceVolumeLabel] != "" {
+			volumes = append(volumes, v)
+		}
+	}
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
+	return volumes, nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// defaultWatchInterval is the refresh interval for --watch listings
+const defaultWatchInterval = 2 * time.Second
+
+// watchLoop clears the screen and calls render every interval until interrupted.
+// Render errors are shown on screen rather than ending the watch, so a transient
+// API or daemon failure doesn't drop the user back to the shell.
+func watchLoop(interval time.Duration, render func() error) error {
+	if interval < time.Second {
+		return fmt.Errorf("watch interval must be at least 1s")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Clear screen and move cursor home
+		fmt.Print("\033[H\033[2J")
+		fmt.Printf("Every %s: refreshed %s (Ctrl+C to stop)\n\n", interval, time.Now().Format("15:04:05"))
+
+		if err := render(); err != nil {
+			PrintError("%v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// transitionHighlight is how long a status change stays highlighted in watch mode
+const transitionHighlight = 15 * time.Second
+
+// statusTracker remembers the last status seen for each row so watch mode can
+// highlight transitions between refreshes
+type statusTracker struct {
+	last    map[string]string
+	changes map[string]statusChange
+	primed  bool
+}
+
+type statusChange struct {
+	from string
+	at   time.Time
+}
+
+func newStatusTracker() *statusTracker {
+	return &statusTracker{
+		last:    make(map[string]string),
+		changes: make(map[string]statusChange),
+	}
+}
+
+// format records status for key and returns formatted, prefixed with the
+// previous status while a recent transition is highlighted. A nil tracker
+// returns formatted unchanged.
+func (t *statusTracker) format(key, status, formatted string) string {
+	if t == nil {
+		return formatted
+	}
+
+	prev, seen := t.last[key]
+	if t.primed && (!seen || prev != status) {
+		t.changes[key] = statusChange{from: prev, at: time.Now()}
+	}
+	t.last[key] = status
+
+	change, ok := t.changes[key]
+	if !ok || time.Since(change.at) > transitionHighlight {
+		return formatted
+	}
+	if change.from == "" {
+		return color.New(color.Bold).Sprint("new ") + formatted
+	}
+	return color.New(color.Bold, color.FgYellow).Sprintf("%s → ", change.from) + formatted
+}
+
+// next completes a refresh; rows seen before this call are not reported as new
+func (t *statusTracker) next() {
+	if t == nil {
+		return
+	}
+	t.primed = true
+}
@@ -0,0 +1,177 @@
+// Package agent maps a profile's coding agent, model provider and model to
+// the settings a worker task needs.
+//
+// Every agent ships in the frank image and the entrypoint picks one at
+// startup, so a worker is described by its environment alone: FRANK_AGENT,
+// FRANK_PROVIDER and FRANK_MODEL, any provider-specific variables, and the
+// Secrets Manager secrets the entrypoint loads before starting the agent.
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Agents
+const (
+	Claude = "claude"
+	Codex  = "codex"
+)
+
+// Config selects the agent and model backend for a worker
+type Config struct {
+	Agent    string // claude or codex (default claude)
+	Provider string // Model provider (default: the agent's own)
+	Model    string // Model name, Bedrock model ID or Azure deployment
+	Endpoint string // Provider endpoint, e.g. an Ollama or Azure OpenAI URL
+}
+
+// Worker is what a task needs to run an agent against a provider
+type Worker struct {
+	Agent    string
+	Provider string
+	Env      map[string]string
+	// Secrets maps environment variables to the Secrets Manager secrets the
+	// entrypoint reads into them, so keys never appear in task overrides
+	Secrets map[string]string
+}
+
+// Provider supplies models to one or more agents
+type Provider interface {
+	// Name is the provider's name in profiles, e.g. "bedrock"
+	Name() string
+
+	// Supports reports whether the agent can use this provider
+	Supports(agent string) bool
+
+	// Configure adds the provider's settings for cfg to w
+	Configure(cfg Config, w *Worker) error
+}
+
+var providers = map[string]Provider{}
+
+// defaultProviders is each agent's native provider
+var defaultProviders = map[string]string{
+	Claude: "anthropic",
+	Codex:  "openai",
+}
+
+// Register makes a provider available by name. Registering a name twice
+// replaces the earlier provider.
+func Register(p Provider) {
+	providers[p.Name()] = p
+}
+
+// Lookup returns the provider registered as name
+func Lookup(name string) (Provider, error) {
+	p, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q (valid: %s)", name, strings.Join(Providers(), ", "))
+	}
+	return p, nil
+}
+
+// Providers returns the registered provider names, sorted
+func Providers() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProvidersFor returns the providers the agent can use, sorted
+func ProvidersFor(agent string) []string {
+	var names []string
+	for _, name := range Providers() {
+		if providers[name].Supports(agent) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// DefaultProvider returns the agent's native provider
+func DefaultProvider(agent string) string {
+	return defaultProviders[agentOrDefault(agent)]
+}
+
+// Validate checks that cfg names a known provider the agent supports, with
+// the settings the provider requires
+func Validate(cfg Config) error {
+	_, err := Resolve(cfg)
+	return err
+}
+
+// Resolve returns the worker settings for cfg
+func Resolve(cfg Config) (*Worker, error) {
+	cfg.Agent = agentOrDefault(cfg.Agent)
+	if _, ok := defaultProviders[cfg.Agent]; !ok {
+		return nil, fmt.Errorf("unknown agent %q (valid: %s, %s)", cfg.Agent, Claude, Codex)
+	}
+	if cfg.Provider == "" {
+		cfg.Provider = DefaultProvider(cfg.Agent)
+	}
+
+	p, err := Lookup(cfg.Provider)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Supports(cfg.Agent) {
+		return nil, fmt.Errorf("provider %s doesn't support agent %s (%s can use: %s)",
+			cfg.Provider, cfg.Agent, cfg.Agent, strings.Join(ProvidersFor(cfg.Agent), ", "))
+	}
+
+	w := &Worker{
+		Agent:    cfg.Agent,
+		Provider: cfg.Provider,
+		Env: map[string]string{
+			"FRANK_AGENT":    cfg.Agent,
+			"FRANK_PROVIDER": cfg.Provider,
+		},
+		Secrets: map[string]string{},
+	}
+	if cfg.Model != "" {
+		w.Env["FRANK_MODEL"] = cfg.Model
+	}
+	if err := p.Configure(cfg, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// SecretsEnv encodes w's secrets as the FRANK_SECRETS value the entrypoint
+// reads: comma-separated NAME=secret-id pairs, sorted by name. It returns
+// "" when there are none.
+func (w *Worker) SecretsEnv() string {
+	names := make([]string, 0, len(w.Secrets))
+	for name := range w.Secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + w.Secrets[name]
+	}
+	return strings.Join(pairs, ",")
+}
+
+// EnvNames returns the names of w's environment variables, sorted
+func (w *Worker) EnvNames() []string {
+	names := make([]string, 0, len(w.Env))
+	for name := range w.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func agentOrDefault(agent string) string {
+	if agent == "" {
+		return Claude
+	}
+	return agent
+}
@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Defaults for the built-in providers
+const (
+	DefaultOllamaEndpoint  = "http://localhost:11434"
+	DefaultAzureAPIVersion = "2025-04-01-preview"
+	AzureAPIKeySecret      = "/frank/azure-openai-api-key"
+)
+
+func init() {
+	Register(nativeProvider{name: "anthropic", agent: Claude})
+	Register(nativeProvider{name: "openai", agent: Codex})
+	Register(bedrockProvider{})
+	Register(ollamaProvider{})
+	Register(azureProvider{})
+}
+
+// nativeProvider is an agent's own API, authenticated by the credentials the
+// entrypoint already syncs
+type nativeProvider struct {
+	name  string
+	agent string
+}
+
+func (p nativeProvider) Name() string               { return p.name }
+func (p nativeProvider) Supports(agent string) bool { return agent == p.agent }
+
+func (p nativeProvider) Configure(cfg Config, w *Worker) error {
+	if cfg.Endpoint != "" {
+		return fmt.Errorf("provider %s doesn't take an endpoint", p.name)
+	}
+	return nil
+}
+
+// bedrockProvider runs Claude through Amazon Bedrock with the task role's
+// credentials. Model is a Bedrock model or inference profile ID.
+type bedrockProvider struct{}
+
+func (bedrockProvider) Name() string               { return "bedrock" }
+func (bedrockProvider) Supports(agent string) bool { return agent == Claude }
+
+func (bedrockProvider) Configure(cfg Config, w *Worker) error {
+	w.Env["CLAUDE_CODE_USE_BEDROCK"] = "1"
+	if cfg.Model != "" {
+		w.Env["ANTHROPIC_MODEL"] = cfg.Model
+	}
+	// A VPC endpoint or gateway in front of Bedrock
+	if cfg.Endpoint != "" {
+		w.Env["ANTHROPIC_BEDROCK_BASE_URL"] = cfg.Endpoint
+	}
+	return nil
+}
+
+// ollamaProvider runs Codex against an Ollama server, by default the one the
+// entrypoint starts in the container
+type ollamaProvider struct{}
+
+func (ollamaProvider) Name() string               { return "ollama" }
+func (ollamaProvider) Supports(agent string) bool { return agent == Codex }
+
+func (ollamaProvider) Configure(cfg Config, w *Worker) error {
+	endpoint := strings.TrimSuffix(cfg.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = DefaultOllamaEndpoint
+	}
+	w.Env["OLLAMA_HOST"] = endpoint
+	w.Env["CODEX_OSS_BASE_URL"] = endpoint + "/v1"
+	w.Env["FRANK_AGENT_ARGS"] = "--oss"
+	return nil
+}
+
+// azureProvider runs Codex against an Azure OpenAI resource. Model is the
+// deployment name; the API key is read from Secrets Manager.
+type azureProvider struct{}
+
+func (azureProvider) Name() string               { return "azure" }
+func (azureProvider) Supports(agent string) bool { return agent == Codex }
+
+func (azureProvider) Configure(cfg Config, w *Worker) error {
+	if cfg.Endpoint == "" {
+		return fmt.Errorf("provider azure needs an endpoint (https://<resource>.openai.azure.com)")
+	}
+	if cfg.Model == "" {
+		return fmt.Errorf("provider azure needs a model (the deployment name)")
+	}
+	w.Env["AZURE_OPENAI_ENDPOINT"] = strings.TrimSuffix(cfg.Endpoint, "/")
+	w.Env["AZURE_OPENAI_API_VERSION"] = DefaultAzureAPIVersion
+	w.Env["FRANK_AGENT_ARGS"] = "-c model_provider=azure"
+	w.Secrets["AZURE_OPENAI_API_KEY"] = AzureAPIKeySecret
+	return nil
+}
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"hash/fnv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	frankaws "github.com/barff/frank/internal/aws"
 )
 
 const (
@@ -23,6 +25,9 @@ const (
 	// ProfileTagKey is the tag key for identifying profile resources
 	ProfileTagKey = "frank-profile"
 
+	// OwnerTagKey is the tag key recording who started a profile
+	OwnerTagKey = "frank-owner"
+
 	// Health check settings
 	HealthCheckPath     = "/health"
 	HealthCheckPort     = "7683"
@@ -37,8 +42,33 @@ const (
 
 	// TargetPort is the port for ALB target groups (use web port for HTML wrapper)
 	TargetPort = WebPort
+
+	// maxPriorityAttempts bounds rule creation retries when another process
+	// takes the allocated priority first
+	maxPriorityAttempts = 3
+
+	// healthPollInterval is how often WaitForHealthy checks a target
+	healthPollInterval = 5 * time.Second
 )
 
+// HealthCheck is the path and port the ALB checks a profile's tasks on.
+// Empty fields use HealthCheckPath and HealthCheckPort.
+type HealthCheck struct {
+	Path string
+	Port string
+}
+
+// withDefaults fills in the default path and port
+func (hc HealthCheck) withDefaults() HealthCheck {
+	if hc.Path == "" {
+		hc.Path = HealthCheckPath
+	}
+	if hc.Port == "" {
+		hc.Port = HealthCheckPort
+	}
+	return hc
+}
+
 // Infrastructure holds discovered AWS infrastructure details
 type Infrastructure struct {
 	VPCID           string
@@ -46,6 +76,7 @@ type Infrastructure struct {
 	ListenerArn     string
 	SubnetIDs       []string
 	SecurityGroupID string
+	DNSName         string // The ALB's own hostname, which the domain should alias
 }
 
 // Manager handles ALB operations for profile routing
@@ -53,11 +84,17 @@ type Manager struct {
 	elbClient *elasticloadbalancingv2.Client
 	cfnClient *cloudformation.Client
 	infra     *Infrastructure
+
+	// Owner is recorded in OwnerTagKey on the target groups and listener
+	// rules the manager creates; empty leaves them untagged
+	Owner string
 }
 
-// NewManager creates a new ALB manager
-func NewManager(ctx context.Context) (*Manager, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+// NewManager creates a new ALB manager. optFns are applied when loading the
+// AWS config after the shared retry settings, e.g. to add API middleware.
+func NewManager(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*Manager, error) {
+	optFns = append([]func(*config.LoadOptions) error{frankaws.WithRetries()}, optFns...)
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
@@ -120,6 +157,7 @@ func (m *Manager) DiscoverInfrastructure(ctx context.Context) (*Infrastructure,
 	alb := albOutput.LoadBalancers[0]
 	infra.ALBArn = aws.ToString(alb.LoadBalancerArn)
 	infra.VPCID = aws.ToString(alb.VpcId)
+	infra.DNSName = aws.ToString(alb.DNSName)
 	infra.SecurityGroupID = alb.SecurityGroups[0]
 
 	// Get the HTTPS listener
@@ -145,33 +183,43 @@ func (m *Manager) DiscoverInfrastructure(ctx context.Context) (*Infrastructure,
 	return infra, nil
 }
 
-// EnsureTargetGroup creates a target group for the profile if it doesn't exist
-func (m *Manager) EnsureTargetGroup(ctx context.Context, profileName string) (string, error) {
-	infra, err := m.DiscoverInfrastructure(ctx)
-	if err != nil {
-		return "", err
+// updateTargetGroup points an existing target group's health check at hc
+// and records the manager's owner on it
+func (m *Manager) updateTargetGroup(ctx context.Context, profileName string, tg *elbv2types.TargetGroup, hc HealthCheck) error {
+	if !sameHealthCheck(*tg, hc) {
+		_, err := m.elbClient.ModifyTargetGroup(ctx, &elasticloadbalancingv2.ModifyTargetGroupInput{
+			TargetGroupArn:  tg.TargetGroupArn,
+			HealthCheckPath: aws.String(hc.Path),
+			HealthCheckPort: aws.String(hc.Port),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update target group health check: %w", err)
+		}
 	}
-
-	tgName := targetGroupName(profileName, "")
-
-	// Check if target group already exists
-	existing, err := m.elbClient.DescribeTargetGroups(ctx, &elasticloadbalancingv2.DescribeTargetGroupsInput{
-		Names: []string{tgName},
-	})
-	if err == nil && len(existing.TargetGroups) > 0 {
-		return aws.ToString(existing.TargetGroups[0].TargetGroupArn), nil
+	// The profile now belongs to whoever started it last
+	if m.Owner != "" {
+		_, err := m.elbClient.AddTags(ctx, &elasticloadbalancingv2.AddTagsInput{
+			ResourceArns: []string{aws.ToString(tg.TargetGroupArn)},
+			Tags:         m.tags(profileName),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to tag target group: %w", err)
+		}
 	}
+	return nil
+}
 
-	// Create new target group
+// createTargetGroup creates one of a profile's target groups
+func (m *Manager) createTargetGroup(ctx context.Context, profileName, tgName, vpcID string, hc HealthCheck) (string, error) {
 	createOutput, err := m.elbClient.CreateTargetGroup(ctx, &elasticloadbalancingv2.CreateTargetGroupInput{
 		Name:       aws.String(tgName),
 		Protocol:   elbv2types.ProtocolEnumHttp,
 		Port:       aws.Int32(TargetPort),
-		VpcId:      aws.String(infra.VPCID),
+		VpcId:      aws.String(vpcID),
 		TargetType: elbv2types.TargetTypeEnumIp,
 		HealthCheckEnabled:         aws.Bool(true),
-		HealthCheckPath:            aws.String(HealthCheckPath),
-		HealthCheckPort:            aws.String(HealthCheckPort),
+		HealthCheckPath:            aws.String(hc.Path),
+		HealthCheckPort:            aws.String(hc.Port),
 		HealthCheckProtocol:        elbv2types.ProtocolEnumHttp,
 		HealthCheckIntervalSeconds: aws.Int32(HealthCheckInterval),
 		HealthCheckTimeoutSeconds:  aws.Int32(HealthCheckTimeout),
@@ -180,15 +228,10 @@ func (m *Manager) EnsureTargetGroup(ctx context.Context, profileName string) (st
 		Matcher: &elbv2types.Matcher{
 			HttpCode: aws.String("200"),
 		},
-		Tags: []elbv2types.Tag{
-			{
-				Key:   aws.String(ProfileTagKey),
-				Value: aws.String(profileName),
-			},
-		},
+		Tags: m.tags(profileName),
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to create target group: %w", err)
+		return "", fmt.Errorf("failed to create target group %s: %w", tgName, err)
 	}
 
 	if len(createOutput.TargetGroups) == 0 {
@@ -198,100 +241,6 @@ func (m *Manager) EnsureTargetGroup(ctx context.Context, profileName string) (st
 	return aws.ToString(createOutput.TargetGroups[0].TargetGroupArn), nil
 }
 
-// EnsureListenerRule creates a listener rule for the profile if it doesn't exist
-// Uses path-based routing: /<profile>/* -> target group
-func (m *Manager) EnsureListenerRule(ctx context.Context, profileName, targetGroupArn string) error {
-	infra, err := m.DiscoverInfrastructure(ctx)
-	if err != nil {
-		return err
-	}
-
-	// Check if rule already exists by listing rules and checking conditions
-	rules, err := m.elbClient.DescribeRules(ctx, &elasticloadbalancingv2.DescribeRulesInput{
-		ListenerArn: aws.String(infra.ListenerArn),
-	})
-	if err != nil {
-		return fmt.Errorf("failed to describe listener rules: %w", err)
-	}
-
-	pathPattern := fmt.Sprintf("/%s/*", profileName)
-
-	for _, rule := range rules.Rules {
-		for _, cond := range rule.Conditions {
-			if cond.PathPatternConfig != nil {
-				for _, val := range cond.PathPatternConfig.Values {
-					if val == pathPattern {
-						// Rule already exists
-						return nil
-					}
-				}
-			}
-		}
-	}
-
-	// Calculate priority based on profile name hash (100-999 range)
-	priority := hashToPriority(profileName)
-
-	// Create listener rule with path-based routing
-	_, err = m.elbClient.CreateRule(ctx, &elasticloadbalancingv2.CreateRuleInput{
-		ListenerArn: aws.String(infra.ListenerArn),
-		Priority:    aws.Int32(priority),
-		Conditions: []elbv2types.RuleCondition{
-			{
-				Field: aws.String("path-pattern"),
-				PathPatternConfig: &elbv2types.PathPatternConditionConfig{
-					Values: []string{pathPattern},
-				},
-			},
-		},
-		Actions: []elbv2types.Action{
-			{
-				Type:           elbv2types.ActionTypeEnumForward,
-				TargetGroupArn: aws.String(targetGroupArn),
-			},
-		},
-		Tags: []elbv2types.Tag{
-			{
-				Key:   aws.String(ProfileTagKey),
-				Value: aws.String(profileName),
-			},
-		},
-	})
-	if err != nil {
-		// Check if it's a priority conflict
-		if strings.Contains(err.Error(), "PriorityInUse") {
-			// Try with a different priority
-			for i := 1; i <= 10; i++ {
-				priority = priority + int32(i)
-				_, err = m.elbClient.CreateRule(ctx, &elasticloadbalancingv2.CreateRuleInput{
-					ListenerArn: aws.String(infra.ListenerArn),
-					Priority:    aws.Int32(priority),
-					Conditions: []elbv2types.RuleCondition{
-						{
-							Field: aws.String("path-pattern"),
-							PathPatternConfig: &elbv2types.PathPatternConditionConfig{
-								Values: []string{pathPattern},
-							},
-						},
-					},
-					Actions: []elbv2types.Action{
-						{
-							Type:           elbv2types.ActionTypeEnumForward,
-							TargetGroupArn: aws.String(targetGroupArn),
-						},
-					},
-				})
-				if err == nil {
-					return nil
-				}
-			}
-		}
-		return fmt.Errorf("failed to create listener rule: %w", err)
-	}
-
-	return nil
-}
-
 // RegisterTarget registers a task IP in the target group
 func (m *Manager) RegisterTarget(ctx context.Context, targetGroupArn, ip string, port int) error {
 	_, err := m.elbClient.RegisterTargets(ctx, &elasticloadbalancingv2.RegisterTargetsInput{
@@ -309,6 +258,51 @@ func (m *Manager) RegisterTarget(ctx context.Context, targetGroupArn, ip string,
 	return nil
 }
 
+// WaitForHealthy polls the target's health in the target group until it is
+// healthy, ctx is cancelled or timeout passes
+func (m *Manager) WaitForHealthy(ctx context.Context, targetGroupArn, ip string, port int, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	state := "unknown"
+	for {
+		out, err := m.elbClient.DescribeTargetHealth(ctx, &elasticloadbalancingv2.DescribeTargetHealthInput{
+			TargetGroupArn: aws.String(targetGroupArn),
+			Targets: []elbv2types.TargetDescription{
+				{
+					Id:   aws.String(ip),
+					Port: aws.Int32(int32(port)),
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe target health: %w", err)
+		}
+		if len(out.TargetHealthDescriptions) > 0 && out.TargetHealthDescriptions[0].TargetHealth != nil {
+			health := out.TargetHealthDescriptions[0].TargetHealth
+			if health.State == elbv2types.TargetHealthStateEnumHealthy {
+				return nil
+			}
+			state = string(health.State)
+			if desc := aws.ToString(health.Description); desc != "" {
+				state += ": " + desc
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("target %s:%d not healthy after %s (%s)", ip, port, timeout, state)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(healthPollInterval):
+		}
+	}
+}
+
+// sameHealthCheck reports whether a target group already checks hc
+func sameHealthCheck(tg elbv2types.TargetGroup, hc HealthCheck) bool {
+	return aws.ToString(tg.HealthCheckPath) == hc.Path && aws.ToString(tg.HealthCheckPort) == hc.Port
+}
+
 // DeregisterTarget removes a task IP from the target group
 func (m *Manager) DeregisterTarget(ctx context.Context, targetGroupArn, ip string, port int) error {
 	_, err := m.elbClient.DeregisterTargets(ctx, &elasticloadbalancingv2.DeregisterTargetsInput{
@@ -326,82 +320,68 @@ func (m *Manager) DeregisterTarget(ctx context.Context, targetGroupArn, ip strin
 	return nil
 }
 
-// GetTargetGroupArn finds the target group ARN for a profile
+// GetTargetGroupArn finds the web target group ARN for a profile
 func (m *Manager) GetTargetGroupArn(ctx context.Context, profileName string) (string, error) {
-	tgName := targetGroupName(profileName, "")
+	arn, err := m.targetGroupArn(ctx, targetGroupName(profileName, ""))
+	if err != nil {
+		return "", fmt.Errorf("target group not found for profile %s", profileName)
+	}
+	return arn, nil
+}
 
+// targetGroupArn looks up a target group's ARN by name
+func (m *Manager) targetGroupArn(ctx context.Context, tgName string) (string, error) {
 	existing, err := m.elbClient.DescribeTargetGroups(ctx, &elasticloadbalancingv2.DescribeTargetGroupsInput{
 		Names: []string{tgName},
 	})
 	if err != nil {
-		return "", fmt.Errorf("target group not found for profile %s", profileName)
+		return "", err
 	}
-
 	if len(existing.TargetGroups) == 0 {
-		return "", fmt.Errorf("target group not found for profile %s", profileName)
+		return "", fmt.Errorf("target group %s not found", tgName)
 	}
-
 	return aws.ToString(existing.TargetGroups[0].TargetGroupArn), nil
 }
 
-// DeleteTargetGroup removes a target group for a profile
-func (m *Manager) DeleteTargetGroup(ctx context.Context, profileName string) error {
+// ProfileOwner returns the owner tag of a profile's target group, or "" when
+// it has none
+func (m *Manager) ProfileOwner(ctx context.Context, profileName string) (string, error) {
 	tgArn, err := m.GetTargetGroupArn(ctx, profileName)
 	if err != nil {
-		return nil // Target group doesn't exist, nothing to delete
+		return "", err
 	}
-
-	_, err = m.elbClient.DeleteTargetGroup(ctx, &elasticloadbalancingv2.DeleteTargetGroupInput{
-		TargetGroupArn: aws.String(tgArn),
+	out, err := m.elbClient.DescribeTags(ctx, &elasticloadbalancingv2.DescribeTagsInput{
+		ResourceArns: []string{tgArn},
 	})
 	if err != nil {
-		return fmt.Errorf("failed to delete target group: %w", err)
-	}
-
-	return nil
-}
-
-// DeleteListenerRule removes the listener rule for a profile
-func (m *Manager) DeleteListenerRule(ctx context.Context, profileName string) error {
-	infra, err := m.DiscoverInfrastructure(ctx)
-	if err != nil {
-		return err
+		return "", fmt.Errorf("failed to describe target group tags: %w", err)
 	}
-
-	// Find the rule by path pattern condition
-	rules, err := m.elbClient.DescribeRules(ctx, &elasticloadbalancingv2.DescribeRulesInput{
-		ListenerArn: aws.String(infra.ListenerArn),
-	})
-	if err != nil {
-		return fmt.Errorf("failed to describe listener rules: %w", err)
-	}
-
-	pathPattern := fmt.Sprintf("/%s/*", profileName)
-
-	for _, rule := range rules.Rules {
-		for _, cond := range rule.Conditions {
-			if cond.PathPatternConfig != nil {
-				for _, val := range cond.PathPatternConfig.Values {
-					if val == pathPattern {
-						// Delete this rule
-						_, err = m.elbClient.DeleteRule(ctx, &elasticloadbalancingv2.DeleteRuleInput{
-							RuleArn: rule.RuleArn,
-						})
-						if err != nil {
-							return fmt.Errorf("failed to delete listener rule: %w", err)
-						}
-						return nil
-					}
-				}
+	for _, desc := range out.TagDescriptions {
+		for _, tag := range desc.Tags {
+			if aws.ToString(tag.Key) == OwnerTagKey {
+				return aws.ToString(tag.Value), nil
 			}
 		}
 	}
-
-	return nil // Rule not found, nothing to delete
+	return "", nil
 }
 
-// targetGroupSuffixes are the suffixes used for profile target groups
-var targetGroupSuffixes = []string{"", "-t", "-b"}
+// tags returns the tags for a profile's ALB resources
+func (m *Manager) tags(profileName string) []elbv2types.Tag {
+	tags := []elbv2types.Tag{
+		{
+			Key:   aws.String(ProfileTagKey),
+			Value: aws.String(profileName),
+		},
+	}
+	if m.Owner != "" {
+		tags = append(tags, elbv2types.Tag{
+			Key:   aws.String(OwnerTagKey),
+			Value: aws.String(m.Owner),
+		})
+	}
+	return tags
+}
 
 // FindOrphanedTargetGroups lists all frank-profile-* target groups and returns
 // profile names that are not in the runningProfiles set.
@@ -423,12 +403,7 @@ func (m *Manager) FindOrphanedTargetGroups(ctx context.Context, runningProfiles
 			if !strings.HasPrefix(name, TargetGroupPrefix) {
 				continue
 			}
-			// Strip prefix and known suffixes to get profile name
-			profileName := strings.TrimPrefix(name, TargetGroupPrefix)
-			for _, suffix := range targetGroupSuffixes[1:] { // skip empty suffix
-				profileName = strings.TrimSuffix(profileName, suffix)
-			}
-			seenProfiles[profileName] = true
+			seenProfiles[profileFromTargetGroup(name)] = true
 		}
 
 		marker = result.NextMarker
@@ -447,87 +422,6 @@ func (m *Manager) FindOrphanedTargetGroups(ctx context.Context, runningProfiles
 	return orphans, nil
 }
 
-// DeleteAllTargetGroups removes all target groups (main, -t, -b) for a profile
-func (m *Manager) DeleteAllTargetGroups(ctx context.Context, profileName string) error {
-	for _, suffix := range targetGroupSuffixes {
-		tgName := targetGroupName(profileName, suffix)
-
-		existing, err := m.elbClient.DescribeTargetGroups(ctx, &elasticloadbalancingv2.DescribeTargetGroupsInput{
-			Names: []string{tgName},
-		})
-		if err != nil {
-			// Target group not found, skip
-			continue
-		}
-
-		if len(existing.TargetGroups) > 0 {
-			tgArn := aws.ToString(existing.TargetGroups[0].TargetGroupArn)
-			_, err = m.elbClient.DeleteTargetGroup(ctx, &elasticloadbalancingv2.DeleteTargetGroupInput{
-				TargetGroupArn: aws.String(tgArn),
-			})
-			if err != nil {
-				return fmt.Errorf("failed to delete target group %s: %w", tgName, err)
-			}
-		}
-	}
-	return nil
-}
-
-// DeleteAllListenerRules removes all listener rules for a profile (main, _t, _b, status)
-func (m *Manager) DeleteAllListenerRules(ctx context.Context, profileName string) error {
-	infra, err := m.DiscoverInfrastructure(ctx)
-	if err != nil {
-		return err
-	}
-
-	rules, err := m.elbClient.DescribeRules(ctx, &elasticloadbalancingv2.DescribeRulesInput{
-		ListenerArn: aws.String(infra.ListenerArn),
-	})
-	if err != nil {
-		return fmt.Errorf("failed to describe listener rules: %w", err)
-	}
-
-	// Path patterns that belong to this profile
-	profilePaths := []string{
-		fmt.Sprintf("/%s/*", profileName),
-		fmt.Sprintf("/%s", profileName),
-		fmt.Sprintf("/%s/_t", profileName),
-		fmt.Sprintf("/%s/_t/*", profileName),
-		fmt.Sprintf("/%s/_b", profileName),
-		fmt.Sprintf("/%s/_b/*", profileName),
-		fmt.Sprintf("/%s/status", profileName),
-		fmt.Sprintf("/%s/status/*", profileName),
-	}
-	pathSet := make(map[string]bool)
-	for _, p := range profilePaths {
-		pathSet[p] = true
-	}
-
-	for _, rule := range rules.Rules {
-		if rule.IsDefault != nil && *rule.IsDefault {
-			continue
-		}
-		for _, cond := range rule.Conditions {
-			if cond.PathPatternConfig != nil {
-				for _, val := range cond.PathPatternConfig.Values {
-					if pathSet[val] {
-						_, err = m.elbClient.DeleteRule(ctx, &elasticloadbalancingv2.DeleteRuleInput{
-							RuleArn: rule.RuleArn,
-						})
-						if err != nil {
-							return fmt.Errorf("failed to delete listener rule: %w", err)
-						}
-						goto nextRule // Rule deleted, move to next
-					}
-				}
-			}
-		}
-	nextRule:
-	}
-
-	return nil
-}
-
 // targetGroupName builds a target group name that fits the 32-char AWS limit.
 // It truncates the profile name portion while preserving the prefix and suffix.
 func targetGroupName(profileName, suffix string) string {
@@ -541,10 +435,11 @@ func targetGroupName(profileName, suffix string) string {
 	return TargetGroupPrefix + profileName + suffix
 }
 
-// hashToPriority converts a profile name to a listener rule priority (100-999)
+// hashToPriority converts a profile name to its preferred listener rule
+// priority (100-999). Names can collide; AllocatePriorities resolves that.
 func hashToPriority(name string) int32 {
 	h := fnv.New32a()
 	h.Write([]byte(name))
 	// Map to 100-999 range (leaving 1-99 for static rules)
-	return int32(100 + (h.Sum32() % 900))
+	return int32(MinProfilePriority + (h.Sum32() % (MaxProfilePriority - MinProfilePriority + 1)))
 }
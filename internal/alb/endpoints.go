@@ -0,0 +1,447 @@
+package alb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+)
+
+// Endpoint names
+const (
+	EndpointStatus   = "status"
+	EndpointTerminal = "terminal"
+	EndpointBash     = "bash"
+	EndpointWeb      = "web"
+)
+
+// endpointSpecs lists a profile's endpoints in listener rule order. The
+// rules for /_t, /_b and /status must take precedence over the web rule's
+// /<profile>/* catch-all, so they get the lower priorities.
+var endpointSpecs = []struct {
+	name       string
+	pathSuffix string // Appended to /<profile>; empty for the web root
+	tgSuffix   string // Appended to the target group name
+	port       int
+}{
+	{EndpointStatus, "/status", "", WebPort}, // Served by the web wrapper, without a target group of its own
+	{EndpointTerminal, "/_t", "-t", ClaudePort},
+	{EndpointBash, "/_b", "-b", BashPort},
+	{EndpointWeb, "", "", WebPort},
+}
+
+// Endpoint is one route into a profile's task: the listener rule for its
+// paths and the target group the rule forwards to
+type Endpoint struct {
+	Name        string
+	Paths       []string // Path patterns the listener rule matches
+	TargetGroup string   // Target group name; status shares the web target group
+	Port        int      // Port the task is registered on
+
+	// TargetGroupArn and Rule are the existing resources; empty when missing
+	TargetGroupArn string
+	Rule           *Rule
+
+	group *elbv2types.TargetGroup
+}
+
+// ProfileEndpoints are the ALB routes of one profile. They are created,
+// checked and deleted as a unit.
+type ProfileEndpoints struct {
+	Profile  string
+	Status   Endpoint
+	Terminal Endpoint
+	Bash     Endpoint
+	Web      Endpoint
+
+	// Stale are other rules that route the profile's paths, such as the
+	// single /<profile>/* rule older versions created. EnsureEndpoints
+	// replaces them.
+	Stale []Rule
+
+	// others are every other rule on the listener, for allocating priorities
+	others []Rule
+}
+
+// NewProfileEndpoints returns a profile's endpoints without any existing
+// resources filled in
+func NewProfileEndpoints(profileName string) *ProfileEndpoints {
+	e := &ProfileEndpoints{Profile: profileName}
+	for i, spec := range endpointSpecs {
+		base := "/" + profileName + spec.pathSuffix
+		*e.Rules()[i] = Endpoint{
+			Name:        spec.name,
+			Paths:       []string{base, base + "/*"},
+			TargetGroup: targetGroupName(profileName, spec.tgSuffix),
+			Port:        spec.port,
+		}
+	}
+	return e
+}
+
+// Rules returns the endpoints in listener rule order
+func (e *ProfileEndpoints) Rules() []*Endpoint {
+	return []*Endpoint{&e.Status, &e.Terminal, &e.Bash, &e.Web}
+}
+
+// TargetGroups returns the endpoints that have a target group of their own
+func (e *ProfileEndpoints) TargetGroups() []*Endpoint {
+	return []*Endpoint{&e.Web, &e.Terminal, &e.Bash}
+}
+
+// Complete reports whether every target group and rule exists, in order and
+// with no stale rules left
+func (e *ProfileEndpoints) Complete() bool {
+	for _, ep := range e.TargetGroups() {
+		if ep.TargetGroupArn == "" {
+			return false
+		}
+	}
+	return e.rulesComplete()
+}
+
+// rulesComplete reports whether every endpoint has its rule at a higher
+// precedence than the ones after it, and no stale rules remain
+func (e *ProfileEndpoints) rulesComplete() bool {
+	if len(e.Stale) > 0 {
+		return false
+	}
+	var last int32
+	for _, ep := range e.Rules() {
+		if ep.Rule == nil || ep.Rule.Priority <= last {
+			return false
+		}
+		last = ep.Rule.Priority
+	}
+	return true
+}
+
+// existingRules returns the profile's endpoint and stale rules
+func (e *ProfileEndpoints) existingRules() []Rule {
+	var rules []Rule
+	for _, ep := range e.Rules() {
+		if ep.Rule != nil {
+			rules = append(rules, *ep.Rule)
+		}
+	}
+	return append(rules, e.Stale...)
+}
+
+// classify sorts the listener's rules into the profile's endpoint rules,
+// its stale rules and everyone else's. Untagged rules on the profile's
+// paths count as the profile's: rules created before tagging have no owner.
+func (e *ProfileEndpoints) classify(rules []Rule) {
+	paths := make(map[string]bool)
+	for _, ep := range e.Rules() {
+		ep.Rule = nil
+		for _, p := range ep.Paths {
+			paths[p] = true
+		}
+	}
+	e.Stale, e.others = nil, nil
+
+	for _, r := range rules {
+		owned := r.Profile == e.Profile || r.Profile == ""
+		if !owned || !slices.ContainsFunc(r.Paths, func(p string) bool { return paths[p] }) {
+			e.others = append(e.others, r)
+			continue
+		}
+
+		matched := false
+		for _, ep := range e.Rules() {
+			if ep.Rule == nil && samePaths(ep.Paths, r.Paths) {
+				rule := r
+				ep.Rule = &rule
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			e.Stale = append(e.Stale, r)
+		}
+	}
+}
+
+// samePaths reports whether two rules match the same path patterns
+func samePaths(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, p := range a {
+		if !slices.Contains(b, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// profileFromTargetGroup strips the endpoint suffix from a profile target
+// group name, returning the (possibly truncated) profile name
+func profileFromTargetGroup(name string) string {
+	profileName := strings.TrimPrefix(name, TargetGroupPrefix)
+	for _, spec := range endpointSpecs {
+		if spec.tgSuffix != "" && strings.HasSuffix(profileName, spec.tgSuffix) {
+			return strings.TrimSuffix(profileName, spec.tgSuffix)
+		}
+	}
+	return profileName
+}
+
+// Endpoints looks up the existing target groups and listener rules of a
+// profile's endpoints
+func (m *Manager) Endpoints(ctx context.Context, profileName string) (*ProfileEndpoints, error) {
+	e := NewProfileEndpoints(profileName)
+	for _, ep := range e.TargetGroups() {
+		existing, err := m.elbClient.DescribeTargetGroups(ctx, &elasticloadbalancingv2.DescribeTargetGroupsInput{
+			Names: []string{ep.TargetGroup},
+		})
+		if err != nil || len(existing.TargetGroups) == 0 {
+			// Target group not found
+			continue
+		}
+		ep.group = &existing.TargetGroups[0]
+		ep.TargetGroupArn = aws.ToString(ep.group.TargetGroupArn)
+	}
+	e.Status.TargetGroupArn, e.Status.group = e.Web.TargetGroupArn, e.Web.group
+
+	rules, err := m.ListRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e.classify(rules)
+	return e, nil
+}
+
+// EnsureEndpoints creates whatever is missing of the profile's target
+// groups and listener rules, pointing their health checks at hc. Rules that
+// are missing, out of order or stale are replaced as a set with consecutive
+// priorities. If anything fails, the resources created so far are deleted.
+func (m *Manager) EnsureEndpoints(ctx context.Context, profileName string, hc HealthCheck) (*ProfileEndpoints, error) {
+	infra, err := m.DiscoverInfrastructure(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e, err := m.Endpoints(ctx, profileName)
+	if err != nil {
+		return nil, err
+	}
+	hc = hc.withDefaults()
+
+	var createdGroups []string
+	rollback := func(cause error) error {
+		var errs []error
+		for _, arn := range createdGroups {
+			if _, err := m.elbClient.DeleteTargetGroup(ctx, &elasticloadbalancingv2.DeleteTargetGroupInput{
+				TargetGroupArn: aws.String(arn),
+			}); err != nil {
+				errs = append(errs, fmt.Errorf("failed to roll back target group: %w", err))
+			}
+		}
+		return errors.Join(append([]error{cause}, errs...)...)
+	}
+
+	for _, ep := range e.TargetGroups() {
+		if ep.TargetGroupArn != "" {
+			if err := m.updateTargetGroup(ctx, profileName, ep.group, hc); err != nil {
+				return nil, rollback(err)
+			}
+			continue
+		}
+		arn, err := m.createTargetGroup(ctx, profileName, ep.TargetGroup, infra.VPCID, hc)
+		if err != nil {
+			return nil, rollback(err)
+		}
+		ep.TargetGroupArn = arn
+		createdGroups = append(createdGroups, arn)
+	}
+	e.Status.TargetGroupArn = e.Web.TargetGroupArn
+
+	if !e.rulesComplete() {
+		if err := m.replaceRules(ctx, infra.ListenerArn, e); err != nil {
+			return nil, rollback(err)
+		}
+	}
+	return e, nil
+}
+
+// replaceRules deletes the profile's existing rules and creates one per
+// endpoint at consecutive priorities, retrying when another process takes
+// the allocated priorities first
+func (m *Manager) replaceRules(ctx context.Context, listenerArn string, e *ProfileEndpoints) error {
+	for _, ep := range e.Rules() {
+		for _, path := range ep.Paths {
+			if err := checkPathConflict(e.Profile, path, e.others); err != nil {
+				return err
+			}
+		}
+	}
+	if err := m.deleteRules(ctx, e.existingRules()); err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		first, err := AllocatePriorities(e.Profile, e.others, len(endpointSpecs))
+		if err != nil {
+			return err
+		}
+
+		var created []Rule
+		for i, ep := range e.Rules() {
+			priority := first + int32(i)
+			var out *elasticloadbalancingv2.CreateRuleOutput
+			out, err = m.elbClient.CreateRule(ctx, &elasticloadbalancingv2.CreateRuleInput{
+				ListenerArn: aws.String(listenerArn),
+				Priority:    aws.Int32(priority),
+				Conditions: []elbv2types.RuleCondition{
+					{
+						Field: aws.String("path-pattern"),
+						PathPatternConfig: &elbv2types.PathPatternConditionConfig{
+							Values: ep.Paths,
+						},
+					},
+				},
+				Actions: []elbv2types.Action{
+					{
+						Type:           elbv2types.ActionTypeEnumForward,
+						TargetGroupArn: aws.String(ep.TargetGroupArn),
+					},
+				},
+				Tags: m.tags(e.Profile),
+			})
+			if err != nil {
+				err = fmt.Errorf("failed to create %s listener rule at priority %d: %w", ep.Name, priority, err)
+				break
+			}
+			rule := Rule{
+				Priority:     priority,
+				Paths:        ep.Paths,
+				Profile:      e.Profile,
+				TargetGroups: []string{ep.TargetGroup},
+			}
+			if len(out.Rules) > 0 {
+				rule.Arn = aws.ToString(out.Rules[0].RuleArn)
+			}
+			ep.Rule = &rule
+			created = append(created, rule)
+		}
+		if err == nil {
+			e.Stale = nil
+			return nil
+		}
+
+		if delErr := m.deleteRules(ctx, created); delErr != nil {
+			return errors.Join(err, delErr)
+		}
+		var inUse *elbv2types.PriorityInUseException
+		if !errors.As(err, &inUse) || attempt == maxPriorityAttempts {
+			return err
+		}
+
+		rules, listErr := m.ListRules(ctx)
+		if listErr != nil {
+			return listErr
+		}
+		e.classify(rules)
+	}
+}
+
+// deleteRules deletes listener rules, carrying on past failures
+func (m *Manager) deleteRules(ctx context.Context, rules []Rule) error {
+	var errs []error
+	for _, r := range rules {
+		if r.Arn == "" {
+			continue
+		}
+		if _, err := m.elbClient.DeleteRule(ctx, &elasticloadbalancingv2.DeleteRuleInput{
+			RuleArn: aws.String(r.Arn),
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete listener rule at priority %d: %w", r.Priority, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DeleteEndpoints deletes the profile's listener rules, then its target
+// groups. It carries on past failures and returns them all.
+func (m *Manager) DeleteEndpoints(ctx context.Context, profileName string) error {
+	e, err := m.Endpoints(ctx, profileName)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{m.deleteRules(ctx, e.existingRules())}
+	for _, ep := range e.TargetGroups() {
+		if ep.TargetGroupArn == "" {
+			continue
+		}
+		if _, err := m.elbClient.DeleteTargetGroup(ctx, &elasticloadbalancingv2.DeleteTargetGroupInput{
+			TargetGroupArn: aws.String(ep.TargetGroupArn),
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete target group %s: %w", ep.TargetGroup, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RegisterTargets registers a task IP in each of the endpoints' target
+// groups on the endpoint's port
+func (m *Manager) RegisterTargets(ctx context.Context, e *ProfileEndpoints, ip string) error {
+	for _, ep := range e.TargetGroups() {
+		if err := m.RegisterTarget(ctx, ep.TargetGroupArn, ip, ep.Port); err != nil {
+			return fmt.Errorf("%s: %w", ep.TargetGroup, err)
+		}
+	}
+	return nil
+}
+
+// DeregisterTargets removes a task IP from each of the profile's existing
+// target groups
+func (m *Manager) DeregisterTargets(ctx context.Context, profileName, ip string) error {
+	var errs []error
+	for _, ep := range NewProfileEndpoints(profileName).TargetGroups() {
+		arn, err := m.targetGroupArn(ctx, ep.TargetGroup)
+		if err != nil {
+			continue // Target group doesn't exist
+		}
+		if err := m.DeregisterTarget(ctx, arn, ip, ep.Port); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ep.TargetGroup, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// TargetState is the ALB's view of one registered target
+type TargetState struct {
+	Address string // ip:port
+	State   string
+	Reason  string
+}
+
+// TargetHealth returns the health of every target in a target group
+func (m *Manager) TargetHealth(ctx context.Context, targetGroupArn string) ([]TargetState, error) {
+	out, err := m.elbClient.DescribeTargetHealth(ctx, &elasticloadbalancingv2.DescribeTargetHealthInput{
+		TargetGroupArn: aws.String(targetGroupArn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe target health: %w", err)
+	}
+
+	var states []TargetState
+	for _, d := range out.TargetHealthDescriptions {
+		s := TargetState{State: "unknown"}
+		if d.Target != nil {
+			s.Address = fmt.Sprintf("%s:%d", aws.ToString(d.Target.Id), aws.ToInt32(d.Target.Port))
+		}
+		if d.TargetHealth != nil {
+			s.State = string(d.TargetHealth.State)
+			s.Reason = aws.ToString(d.TargetHealth.Description)
+		}
+		states = append(states, s)
+	}
+	return states, nil
+}
@@ -0,0 +1,123 @@
+package alb
+
+import (
+	"slices"
+	"testing"
+)
+
+// rule returns a listener rule on paths, tagged with profile
+func rule(arn string, priority int32, profile string, paths ...string) Rule {
+	return Rule{Arn: arn, Priority: priority, Paths: paths, Profile: profile}
+}
+
+func TestNewProfileEndpoints(t *testing.T) {
+	e := NewProfileEndpoints("demo")
+	for _, tt := range []struct {
+		ep          *Endpoint
+		paths       []string
+		targetGroup string
+		port        int
+	}{
+		{&e.Status, []string{"/demo/status", "/demo/status/*"}, "frank-profile-demo", WebPort},
+		{&e.Terminal, []string{"/demo/_t", "/demo/_t/*"}, "frank-profile-demo-t", ClaudePort},
+		{&e.Bash, []string{"/demo/_b", "/demo/_b/*"}, "frank-profile-demo-b", BashPort},
+		{&e.Web, []string{"/demo", "/demo/*"}, "frank-profile-demo", WebPort},
+	} {
+		if !slices.Equal(tt.ep.Paths, tt.paths) || tt.ep.TargetGroup != tt.targetGroup || tt.ep.Port != tt.port {
+			t.Errorf("%s endpoint = %v %s:%d, want %v %s:%d", tt.ep.Name, tt.ep.Paths, tt.ep.TargetGroup, tt.ep.Port, tt.paths, tt.targetGroup, tt.port)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	e := NewProfileEndpoints("demo")
+	e.classify([]Rule{
+		rule("static", 10, "", "/api/*"),
+		rule("status", 200, "demo", "/demo/status/*", "/demo/status"), // Order doesn't matter
+		rule("terminal", 201, "", "/demo/_t", "/demo/_t/*"),           // Untagged, from before tagging
+		rule("legacy", 202, "demo", "/demo/*"),                        // The old single rule
+		rule("web", 204, "demo", "/demo", "/demo/*"),
+		rule("web-copy", 205, "demo", "/demo", "/demo/*"),
+		rule("hijack", 300, "other", "/demo/_b", "/demo/_b/*"),
+		rule("other", 301, "other", "/other", "/other/*"),
+	})
+
+	for _, tt := range []struct {
+		ep   *Endpoint
+		want string
+	}{
+		{&e.Status, "status"},
+		{&e.Terminal, "terminal"},
+		{&e.Bash, ""},
+		{&e.Web, "web"},
+	} {
+		got := ""
+		if tt.ep.Rule != nil {
+			got = tt.ep.Rule.Arn
+		}
+		if got != tt.want {
+			t.Errorf("%s rule = %q, want %q", tt.ep.Name, got, tt.want)
+		}
+	}
+	if got := arns(e.Stale); !slices.Equal(got, []string{"legacy", "web-copy"}) {
+		t.Errorf("stale = %v, want [legacy web-copy]", got)
+	}
+	if got := arns(e.others); !slices.Equal(got, []string{"static", "hijack", "other"}) {
+		t.Errorf("others = %v, want [static hijack other]", got)
+	}
+	if e.rulesComplete() {
+		t.Error("rules complete with the bash rule missing and stale rules left")
+	}
+
+	// Classifying again starts over
+	e.classify([]Rule{
+		rule("status", 200, "demo", "/demo/status", "/demo/status/*"),
+		rule("terminal", 201, "demo", "/demo/_t", "/demo/_t/*"),
+		rule("bash", 202, "demo", "/demo/_b", "/demo/_b/*"),
+		rule("web", 203, "demo", "/demo", "/demo/*"),
+	})
+	if len(e.Stale) != 0 || len(e.others) != 0 {
+		t.Errorf("stale %v and others %v left from the previous rules", arns(e.Stale), arns(e.others))
+	}
+	if !e.rulesComplete() {
+		t.Error("rules incomplete with every endpoint's rule in order")
+	}
+	if got := arns(e.existingRules()); !slices.Equal(got, []string{"status", "terminal", "bash", "web"}) {
+		t.Errorf("existing rules = %v", got)
+	}
+}
+
+func TestRulesCompleteOrder(t *testing.T) {
+	// The web catch-all must come after the other endpoints' rules
+	e := NewProfileEndpoints("demo")
+	e.classify([]Rule{
+		rule("web", 200, "demo", "/demo", "/demo/*"),
+		rule("status", 201, "demo", "/demo/status", "/demo/status/*"),
+		rule("terminal", 202, "demo", "/demo/_t", "/demo/_t/*"),
+		rule("bash", 203, "demo", "/demo/_b", "/demo/_b/*"),
+	})
+	if e.rulesComplete() {
+		t.Error("rules complete with the web rule shadowing the others")
+	}
+}
+
+func TestProfileFromTargetGroup(t *testing.T) {
+	for name, want := range map[string]string{
+		"frank-profile-demo":   "demo",
+		"frank-profile-demo-t": "demo",
+		"frank-profile-demo-b": "demo",
+		targetGroupName("a-profile-name-past-the-limit", "-t"): "a-profile-name-p",
+	} {
+		if got := profileFromTargetGroup(name); got != want {
+			t.Errorf("profileFromTargetGroup(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func arns(rules []Rule) []string {
+	var out []string
+	for _, r := range rules {
+		out = append(out, r.Arn)
+	}
+	return out
+}
@@ -0,0 +1,179 @@
+package alb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+)
+
+// Action is what a planned change does to an ALB resource
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Resource kinds that appear in a plan
+const (
+	ResourceTargetGroup  = "target group"
+	ResourceListenerRule = "listener rule"
+	ResourceTarget       = "target"
+)
+
+// Change is a single planned create, update or delete
+type Change struct {
+	Action   Action
+	Resource string // One of the Resource* kinds
+	Name     string // Target group name, rule path patterns, or target address
+	Detail   string // Extra context such as priority or owning target group
+}
+
+// Plan lists the ALB changes an operation would make, in execution order
+type Plan struct {
+	Profile string
+	Changes []Change
+}
+
+func (p *Plan) add(action Action, resource, name, detail string) {
+	p.Changes = append(p.Changes, Change{Action: action, Resource: resource, Name: name, Detail: detail})
+}
+
+// Counts returns the number of planned creates, updates and deletes
+func (p *Plan) Counts() (create, update, delete int) {
+	for _, c := range p.Changes {
+		switch c.Action {
+		case ActionCreate:
+			create++
+		case ActionUpdate:
+			update++
+		case ActionDelete:
+			delete++
+		}
+	}
+	return create, update, delete
+}
+
+// PlanStart returns the changes EnsureEndpoints and RegisterTargets would
+// make when starting a task for the profile
+func (m *Manager) PlanStart(ctx context.Context, profileName string, hc HealthCheck) (*Plan, error) {
+	plan := &Plan{Profile: profileName}
+	hc = hc.withDefaults()
+
+	e, err := m.Endpoints(ctx, profileName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ep := range e.TargetGroups() {
+		switch {
+		case ep.TargetGroupArn == "":
+			// Creation needs the VPC, so fail early if it can't be found
+			if _, err := m.DiscoverInfrastructure(ctx); err != nil {
+				return nil, err
+			}
+			plan.add(ActionCreate, ResourceTargetGroup, ep.TargetGroup,
+				fmt.Sprintf("%s endpoint, health check %s on port %s", ep.Name, hc.Path, hc.Port))
+		case !sameHealthCheck(*ep.group, hc):
+			plan.add(ActionUpdate, ResourceTargetGroup, ep.TargetGroup,
+				fmt.Sprintf("health check %s on port %s", hc.Path, hc.Port))
+		}
+	}
+
+	if !e.rulesComplete() {
+		for _, ep := range e.Rules() {
+			for _, path := range ep.Paths {
+				if err := checkPathConflict(profileName, path, e.others); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, r := range e.existingRules() {
+			plan.add(ActionDelete, ResourceListenerRule, strings.Join(r.Paths, ", "),
+				fmt.Sprintf("priority %d", r.Priority))
+		}
+		first, err := AllocatePriorities(profileName, e.others, len(endpointSpecs))
+		if err != nil {
+			return nil, err
+		}
+		for i, ep := range e.Rules() {
+			plan.add(ActionCreate, ResourceListenerRule, strings.Join(ep.Paths, ", "),
+				fmt.Sprintf("priority %d, forward to %s", first+int32(i), ep.TargetGroup))
+		}
+	}
+
+	for _, ep := range e.TargetGroups() {
+		plan.add(ActionCreate, ResourceTarget, fmt.Sprintf("<task IP>:%d", ep.Port), "in "+ep.TargetGroup)
+	}
+	return plan, nil
+}
+
+// PlanStop returns the changes made when stopping a profile task: the task
+// is deregistered (if taskIP is known) and the profile's endpoints are
+// deleted
+func (m *Manager) PlanStop(ctx context.Context, profileName, taskIP string) (*Plan, error) {
+	plan := &Plan{Profile: profileName}
+
+	e, err := m.Endpoints(ctx, profileName)
+	if err != nil {
+		return nil, err
+	}
+	if taskIP != "" {
+		for _, ep := range e.TargetGroups() {
+			if ep.TargetGroupArn != "" {
+				plan.add(ActionDelete, ResourceTarget, fmt.Sprintf("%s:%d", taskIP, ep.Port), "from "+ep.TargetGroup)
+			}
+		}
+	}
+
+	planEndpointDeletes(plan, e)
+	return plan, nil
+}
+
+// PlanCleanup returns one plan per profile that has target groups but no
+// running task, listing the rules and target groups cleanup would delete
+func (m *Manager) PlanCleanup(ctx context.Context, runningProfiles map[string]bool) ([]*Plan, error) {
+	orphans, err := m.FindOrphanedTargetGroups(ctx, runningProfiles)
+	if err != nil {
+		return nil, err
+	}
+
+	var plans []*Plan
+	for _, profileName := range orphans {
+		e, err := m.Endpoints(ctx, profileName)
+		if err != nil {
+			return nil, err
+		}
+		plan := &Plan{Profile: profileName}
+		planEndpointDeletes(plan, e)
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// planEndpointDeletes adds the deletes made by DeleteEndpoints, in order
+func planEndpointDeletes(plan *Plan, e *ProfileEndpoints) {
+	for _, r := range e.existingRules() {
+		plan.add(ActionDelete, ResourceListenerRule, strings.Join(r.Paths, ", "),
+			fmt.Sprintf("priority %d", r.Priority))
+	}
+	for _, ep := range e.TargetGroups() {
+		if ep.TargetGroupArn != "" {
+			plan.add(ActionDelete, ResourceTargetGroup, ep.TargetGroup, "")
+		}
+	}
+}
+
+// rulePaths returns the path patterns a rule matches on
+func rulePaths(rule elbv2types.Rule) []string {
+	var paths []string
+	for _, cond := range rule.Conditions {
+		if cond.PathPatternConfig != nil {
+			paths = append(paths, cond.PathPatternConfig.Values...)
+		}
+	}
+	return paths
+}
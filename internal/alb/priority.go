@@ -0,0 +1,320 @@
+package alb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+)
+
+// Listener rule priorities reserved for profile rules. Static rules from the
+// CDK stack sit below this range and the catch-all above it.
+const (
+	MinProfilePriority = 100
+	MaxProfilePriority = 999
+)
+
+// ErrNoFreePriority is returned when no run of free priorities is left for
+// a profile's rules
+var ErrNoFreePriority = errors.New("no free listener rule priority in the profile range")
+
+// Rule is a listener rule with the ownership recorded in its tags. The
+// listener's rules and their frank-profile tags are the priority registry:
+// a priority is free only if no rule holds it, whoever created that rule.
+type Rule struct {
+	Arn          string
+	Priority     int32    // Zero for the default rule
+	Paths        []string // Path patterns the rule matches
+	Profile      string   // frank-profile tag; empty when untagged
+	TargetGroups []string // Names of the target groups the rule forwards to
+}
+
+// PathProfile returns the profile a path pattern routes, from its first
+// segment, e.g. "myproject" for /myproject/_t/*
+func PathProfile(path string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return strings.TrimSuffix(segment, "*")
+}
+
+// ListRules returns every non-default rule on the HTTPS listener, in
+// priority order, with its tags
+func (m *Manager) ListRules(ctx context.Context) ([]Rule, error) {
+	infra, err := m.DiscoverInfrastructure(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := m.describeRules(ctx, infra.ListenerArn)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []Rule
+	var arns []string
+	for _, r := range raw {
+		if aws.ToBool(r.IsDefault) {
+			continue
+		}
+		priority, _ := strconv.Atoi(aws.ToString(r.Priority))
+		rule := Rule{
+			Arn:      aws.ToString(r.RuleArn),
+			Priority: int32(priority),
+			Paths:    rulePaths(r),
+		}
+		for _, action := range r.Actions {
+			if action.TargetGroupArn != nil {
+				rule.TargetGroups = append(rule.TargetGroups, targetGroupNameFromArn(*action.TargetGroupArn))
+			}
+			if action.ForwardConfig != nil {
+				for _, tg := range action.ForwardConfig.TargetGroups {
+					if name := targetGroupNameFromArn(aws.ToString(tg.TargetGroupArn)); !slices.Contains(rule.TargetGroups, name) {
+						rule.TargetGroups = append(rule.TargetGroups, name)
+					}
+				}
+			}
+		}
+		rules = append(rules, rule)
+		arns = append(arns, rule.Arn)
+	}
+
+	owners, err := m.ruleOwners(ctx, arns)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rules {
+		rules[i].Profile = owners[rules[i].Arn]
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Priority < rules[j].Priority
+	})
+	return rules, nil
+}
+
+// describeRules returns every rule on a listener, following all pages
+func (m *Manager) describeRules(ctx context.Context, listenerArn string) ([]elbv2types.Rule, error) {
+	var rules []elbv2types.Rule
+	var marker *string
+	for {
+		out, err := m.elbClient.DescribeRules(ctx, &elasticloadbalancingv2.DescribeRulesInput{
+			ListenerArn: aws.String(listenerArn),
+			Marker:      marker,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe listener rules: %w", err)
+		}
+		rules = append(rules, out.Rules...)
+		marker = out.NextMarker
+		if marker == nil {
+			return rules, nil
+		}
+	}
+}
+
+// ruleOwners returns the frank-profile tag of each tagged rule
+func (m *Manager) ruleOwners(ctx context.Context, arns []string) (map[string]string, error) {
+	owners := make(map[string]string)
+	// DescribeTags accepts at most 20 resources per call
+	for start := 0; start < len(arns); start += 20 {
+		end := start + 20
+		if end > len(arns) {
+			end = len(arns)
+		}
+		out, err := m.elbClient.DescribeTags(ctx, &elasticloadbalancingv2.DescribeTagsInput{
+			ResourceArns: arns[start:end],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe rule tags: %w", err)
+		}
+		for _, desc := range out.TagDescriptions {
+			for _, tag := range desc.Tags {
+				if aws.ToString(tag.Key) == ProfileTagKey {
+					owners[aws.ToString(desc.ResourceArn)] = aws.ToString(tag.Value)
+				}
+			}
+		}
+	}
+	return owners, nil
+}
+
+// AllocatePriorities picks the first of n consecutive priorities for a
+// profile's rules. It starts at the profile's hash and probes upward,
+// wrapping within the profile range, to the first run of priorities no rule
+// holds. The same rules and profile always give the same priorities.
+func AllocatePriorities(profileName string, rules []Rule, n int) (int32, error) {
+	used := make(map[int32]bool, len(rules))
+	for _, r := range rules {
+		used[r.Priority] = true
+	}
+
+	span := int32(MaxProfilePriority - MinProfilePriority + 1)
+	start := hashToPriority(profileName) - MinProfilePriority
+	for i := int32(0); i < span; i++ {
+		first := MinProfilePriority + (start+i)%span
+		if first+int32(n)-1 > MaxProfilePriority {
+			continue
+		}
+		free := true
+		for p := first; p < first+int32(n); p++ {
+			if used[p] {
+				free = false
+				break
+			}
+		}
+		if free {
+			return first, nil
+		}
+	}
+	return 0, ErrNoFreePriority
+}
+
+// checkPathConflict returns an error if another profile's rule already
+// routes path
+func checkPathConflict(profileName, path string, rules []Rule) error {
+	for _, r := range rules {
+		if r.Profile != "" && r.Profile != profileName && slices.Contains(r.Paths, path) {
+			return fmt.Errorf("path %s is already routed by profile %q at priority %d (run 'frank ecs alb audit')", path, r.Profile, r.Priority)
+		}
+	}
+	return nil
+}
+
+// IssueKind classifies a problem found by Audit
+type IssueKind string
+
+const (
+	// IssueDuplicatePath means several rules match the same path; only the
+	// lowest priority one receives traffic
+	IssueDuplicatePath IssueKind = "duplicate"
+	// IssueOwnerMismatch means a rule's tag, paths or target groups name
+	// different profiles
+	IssueOwnerMismatch IssueKind = "conflict"
+	// IssueUntagged means a profile rule has no frank-profile tag, so
+	// ownership can't be checked
+	IssueUntagged IssueKind = "untagged"
+	// IssueOutOfRange means a profile rule's priority is outside the range
+	// reserved for profiles and may be shadowed by, or shadow, static rules
+	IssueOutOfRange IssueKind = "out-of-range"
+)
+
+// Issue is a problem with one listener rule
+type Issue struct {
+	Kind     IssueKind
+	Priority int32
+	Paths    []string
+	Profile  string // Owning profile from the tag, if any
+	RuleArn  string
+	Detail   string
+}
+
+// Audit lists conflicting, duplicate and unowned profile rules on the
+// HTTPS listener
+func (m *Manager) Audit(ctx context.Context) ([]Rule, []Issue, error) {
+	rules, err := m.ListRules(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rules, FindIssues(rules), nil
+}
+
+// FindIssues checks rules for duplicate paths and inconsistent ownership.
+// Rules that forward to no frank-profile target group and carry no profile
+// tag are static rules and are skipped.
+func FindIssues(rules []Rule) []Issue {
+	var issues []Issue
+	add := func(kind IssueKind, r Rule, format string, a ...interface{}) {
+		issues = append(issues, Issue{
+			Kind:     kind,
+			Priority: r.Priority,
+			Paths:    r.Paths,
+			Profile:  r.Profile,
+			RuleArn:  r.Arn,
+			Detail:   fmt.Sprintf(format, a...),
+		})
+	}
+
+	// Rules are in priority order, so the first rule for a path is the one
+	// that wins
+	winner := make(map[string]Rule)
+	for _, r := range rules {
+		if !isProfileRule(r) {
+			continue
+		}
+
+		for _, path := range r.Paths {
+			if first, ok := winner[path]; ok {
+				add(IssueDuplicatePath, r, "%s is also matched by priority %d, which takes precedence", path, first.Priority)
+				continue
+			}
+			winner[path] = r
+		}
+
+		if r.Profile == "" {
+			add(IssueUntagged, r, "no %s tag", ProfileTagKey)
+		}
+		if r.Priority < MinProfilePriority || r.Priority > MaxProfilePriority {
+			add(IssueOutOfRange, r, "priority outside %d-%d", MinProfilePriority, MaxProfilePriority)
+		}
+
+		// Untagged rules are owned by the profile of their first path
+		owner := r.Profile
+		for _, path := range r.Paths {
+			p := PathProfile(path)
+			if owner == "" {
+				owner = p
+			} else if p != owner {
+				add(IssueOwnerMismatch, r, "belongs to %q but routes %s", owner, path)
+			}
+		}
+		for _, tg := range r.TargetGroups {
+			if !strings.HasPrefix(tg, TargetGroupPrefix) {
+				continue
+			}
+			if owner != "" && !isProfileTargetGroup(owner, tg) {
+				add(IssueOwnerMismatch, r, "belongs to %q but forwards to %s", owner, tg)
+			}
+		}
+	}
+	return issues
+}
+
+// isProfileRule reports whether a rule was created for a profile
+func isProfileRule(r Rule) bool {
+	if r.Profile != "" {
+		return true
+	}
+	for _, tg := range r.TargetGroups {
+		if strings.HasPrefix(tg, TargetGroupPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isProfileTargetGroup reports whether name is one of the profile's target
+// groups, allowing for truncated names
+func isProfileTargetGroup(profileName, name string) bool {
+	for _, ep := range NewProfileEndpoints(profileName).TargetGroups() {
+		if ep.TargetGroup == name {
+			return true
+		}
+	}
+	return false
+}
+
+// targetGroupNameFromArn extracts the name from a target group ARN
+// (arn:aws:elasticloadbalancing:...:targetgroup/<name>/<id>)
+func targetGroupNameFromArn(arn string) string {
+	parts := strings.Split(arn, "/")
+	if len(parts) < 3 {
+		return arn
+	}
+	return parts[len(parts)-2]
+}
@@ -0,0 +1,192 @@
+package alb
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// occupied returns rules holding every profile priority except free
+func occupied(free ...int32) []Rule {
+	var rules []Rule
+	for p := int32(MinProfilePriority); p <= MaxProfilePriority; p++ {
+		if !slices.Contains(free, p) {
+			rules = append(rules, Rule{Priority: p, Profile: "other"})
+		}
+	}
+	return rules
+}
+
+func TestAllocatePriorities(t *testing.T) {
+	const n = 4
+
+	t.Run("hash", func(t *testing.T) {
+		// An empty listener gives a profile its hash, unless the run would
+		// pass the end of the range and wrap to its start
+		for _, name := range []string{"demo", "api", "a-much-longer-profile-name"} {
+			want := hashToPriority(name)
+			if want+n-1 > MaxProfilePriority {
+				want = MinProfilePriority
+			}
+			got, err := AllocatePriorities(name, nil, n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("%s: first priority %d, want %d", name, got, want)
+			}
+		}
+	})
+
+	t.Run("taken", func(t *testing.T) {
+		// Any rule in the way, tagged or not, moves the run up
+		start := hashToPriority("demo")
+		if start+2*n > MaxProfilePriority {
+			t.Skip("demo hashes too near the end of the range")
+		}
+		rules := []Rule{{Priority: start + 1}, {Priority: start + n + 1, Profile: "other"}}
+		got, err := AllocatePriorities("demo", rules, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != start+n+2 {
+			t.Errorf("first priority %d, want %d", got, start+n+2)
+		}
+		again, _ := AllocatePriorities("demo", rules, n)
+		if again != got {
+			t.Errorf("allocation isn't stable: %d then %d", got, again)
+		}
+	})
+
+	t.Run("wrap", func(t *testing.T) {
+		got, err := AllocatePriorities("demo", occupied(100, 101, 102, 103, 998, 999), n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != 100 {
+			t.Errorf("first priority %d, want 100 after wrapping", got)
+		}
+	})
+
+	for _, tt := range []struct {
+		name string
+		free []int32
+	}{
+		{"full", nil},
+		{"gap too small", []int32{500, 501, 502}},
+		{"gap split", []int32{500, 501, 503, 504}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AllocatePriorities("demo", occupied(tt.free...), n)
+			if !errors.Is(err, ErrNoFreePriority) {
+				t.Errorf("got %d, %v; want ErrNoFreePriority", got, err)
+			}
+		})
+	}
+}
+
+func TestFindIssues(t *testing.T) {
+	demo := Rule{
+		Arn:          "demo",
+		Priority:     200,
+		Paths:        []string{"/demo", "/demo/*"},
+		Profile:      "demo",
+		TargetGroups: []string{"frank-profile-demo"},
+	}
+
+	for _, tt := range []struct {
+		name  string
+		rules []Rule
+		want  []IssueKind
+	}{
+		{
+			name: "clean",
+			rules: []Rule{
+				{Arn: "static", Priority: 10, Paths: []string{"/api/*"}, TargetGroups: []string{"frank-api"}},
+				demo,
+				{Arn: "demo-t", Priority: 201, Paths: []string{"/demo/_t", "/demo/_t/*"}, Profile: "demo", TargetGroups: []string{"frank-profile-demo-t"}},
+			},
+		},
+		{
+			name: "duplicate path",
+			rules: []Rule{
+				demo,
+				{Arn: "copy", Priority: 300, Paths: []string{"/demo/*"}, Profile: "demo", TargetGroups: []string{"frank-profile-demo"}},
+			},
+			want: []IssueKind{IssueDuplicatePath},
+		},
+		{
+			name: "another profile's path",
+			rules: []Rule{
+				{Arn: "other", Priority: 300, Paths: []string{"/other/*", "/demo/x"}, Profile: "other", TargetGroups: []string{"frank-profile-other"}},
+			},
+			want: []IssueKind{IssueOwnerMismatch},
+		},
+		{
+			name: "another profile's target group",
+			rules: []Rule{
+				{Arn: "other", Priority: 300, Paths: []string{"/other/*"}, Profile: "other", TargetGroups: []string{"frank-profile-demo"}},
+			},
+			want: []IssueKind{IssueOwnerMismatch},
+		},
+		{
+			name: "untagged",
+			rules: []Rule{
+				{Arn: "old", Priority: 300, Paths: []string{"/old/*"}, TargetGroups: []string{"frank-profile-old"}},
+			},
+			want: []IssueKind{IssueUntagged},
+		},
+		{
+			name: "out of range",
+			rules: []Rule{
+				{Arn: "low", Priority: 50, Paths: []string{"/low/*"}, Profile: "low", TargetGroups: []string{"frank-profile-low"}},
+			},
+			want: []IssueKind{IssueOutOfRange},
+		},
+		{
+			name: "truncated target group",
+			rules: []Rule{
+				{
+					Arn:          "long",
+					Priority:     300,
+					Paths:        []string{"/a-profile-name-past-the-limit/*"},
+					Profile:      "a-profile-name-past-the-limit",
+					TargetGroups: []string{targetGroupName("a-profile-name-past-the-limit", "-t")},
+				},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []IssueKind
+			for _, issue := range FindIssues(tt.rules) {
+				got = append(got, issue.Kind)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathProfile(t *testing.T) {
+	for path, want := range map[string]string{
+		"/demo":      "demo",
+		"/demo/*":    "demo",
+		"/demo/_t/*": "demo",
+		"/demo*":     "demo",
+	} {
+		if got := PathProfile(path); got != want {
+			t.Errorf("PathProfile(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestTargetGroupNameFromArn(t *testing.T) {
+	arn := "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/frank-profile-demo/0123456789abcdef"
+	if got := targetGroupNameFromArn(arn); got != "frank-profile-demo" {
+		t.Errorf("name = %q, want frank-profile-demo", got)
+	}
+	if got := targetGroupNameFromArn("frank-profile-demo"); got != "frank-profile-demo" {
+		t.Errorf("name of a bare name = %q", got)
+	}
+}
@@ -0,0 +1,132 @@
+// Package analytics records frank session metadata locally as JSONL and
+// syncs it to S3. Recording never needs the network: records are appended to
+// daily files under the analytics directory and uploaded later.
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Record types
+const (
+	TypeSessionStart   = "session_start"
+	TypeSessionStop    = "session_stop"
+	TypeSessionRestart = "session_restart"
+	TypeOutcome        = "outcome"
+)
+
+// Outcomes recorded with TypeOutcome
+const (
+	OutcomePRCreated = "pr_created"
+)
+
+const (
+	// DefaultBatchSize is how many records are buffered before writing
+	DefaultBatchSize = 20
+
+	// MaxFileSize rotates a day's file once it grows past this many bytes
+	MaxFileSize = 5 << 20
+)
+
+// Record is one line of the local analytics log
+type Record struct {
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"`
+	Session   string    `json:"session,omitempty"` // Container name or ECS profile
+	Profile   string    `json:"profile,omitempty"`
+	Repo      string    `json:"repo,omitempty"`
+	Branch    string    `json:"branch,omitempty"`
+	Image     string    `json:"image,omitempty"`
+	Runtime   string    `json:"runtime,omitempty"`
+	Duration  float64   `json:"duration_seconds,omitempty"`
+	Outcome   string    `json:"outcome,omitempty"`
+	Detail    string    `json:"detail,omitempty"`
+}
+
+// Collector buffers records and appends them to rotating JSONL files
+type Collector struct {
+	dir       string
+	batchSize int
+	mu        sync.Mutex
+	pending   []Record
+}
+
+// NewCollector creates a collector writing to dir
+func NewCollector(dir string) *Collector {
+	return &Collector{dir: dir, batchSize: DefaultBatchSize}
+}
+
+// Dir returns the directory records are written to
+func (c *Collector) Dir() string {
+	return c.dir
+}
+
+// Record buffers a record, writing the batch once it is full. The timestamp
+// defaults to now.
+func (c *Collector) Record(r Record) error {
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now().UTC()
+	}
+
+	c.mu.Lock()
+	c.pending = append(c.pending, r)
+	full := len(c.pending) >= c.batchSize
+	c.mu.Unlock()
+
+	if full {
+		return c.Flush()
+	}
+	return nil
+}
+
+// Flush writes buffered records to the current file
+func (c *Collector) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.pending) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create analytics directory: %w", err)
+	}
+
+	path := c.currentFile(time.Now().UTC())
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open analytics file: %w", err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, r := range c.pending {
+		if err := enc.Encode(r); err != nil {
+			return fmt.Errorf("failed to write analytics record: %w", err)
+		}
+	}
+	c.pending = nil
+	return nil
+}
+
+// currentFile returns today's file, moving to a numbered file once the
+// previous one reaches MaxFileSize: events-2026-10-18.jsonl, events-2026-10-18.1.jsonl, ...
+func (c *Collector) currentFile(now time.Time) string {
+	day := now.Format("2006-01-02")
+	for n := 0; ; n++ {
+		name := fmt.Sprintf("events-%s.jsonl", day)
+		if n > 0 {
+			name = fmt.Sprintf("events-%s.%d.jsonl", day, n)
+		}
+		path := filepath.Join(c.dir, name)
+		info, err := os.Stat(path)
+		if err != nil || info.Size() < MaxFileSize {
+			return path
+		}
+	}
+}
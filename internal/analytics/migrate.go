@@ -0,0 +1,140 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Move is an object to copy from an older prompt layout to its
+// date-partitioned key
+type Move struct {
+	From string
+	To   string
+}
+
+// LegacyPromptKey maps a key in an older layout to the key PromptKey gives
+// it. Containers used to write prompts/<profile>/YYYY/MM/DD/<file>, and
+// earlier syncs prompts/<profile>/<file>; the latter are dated by the day in
+// a collector file's name, else by modified. ok is false for keys already in
+// the date-partitioned layout.
+func LegacyPromptKey(key string, modified time.Time) (newKey string, ok bool) {
+	if !strings.HasPrefix(key, PromptsPrefix) {
+		return "", false
+	}
+	if _, _, dated := ParsePromptKey(key); dated {
+		return "", false
+	}
+	profile, name, found := strings.Cut(strings.TrimPrefix(key, PromptsPrefix), "/")
+	if !found || profile == "" || name == "" {
+		return "", false
+	}
+
+	day := modified
+	if parts := strings.SplitN(name, "/", 4); len(parts) == 4 {
+		if t, err := time.Parse("2006/01/02", strings.Join(parts[:3], "/")); err == nil {
+			return PromptKey(t, profile, parts[3]), true
+		}
+	}
+	if m := eventsFilePattern.FindStringSubmatch(path.Base(name)); m != nil {
+		day, _ = time.Parse("2006-01-02", m[1])
+	}
+	return PromptKey(day, profile, name), true
+}
+
+// PlanMigration lists the objects under PromptsPrefix that are still in an
+// older layout, with the keys they move to
+func PlanMigration(ctx context.Context, client *s3.Client, bucket string) ([]Move, error) {
+	var moves []Move
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(PromptsPrefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", PromptsPrefix, err)
+		}
+		for _, obj := range page.Contents {
+			key := aws.ToString(obj.Key)
+			if to, ok := LegacyPromptKey(key, aws.ToTime(obj.LastModified)); ok {
+				moves = append(moves, Move{From: key, To: to})
+			}
+		}
+	}
+	return moves, nil
+}
+
+// MigrateResult summarises a migration
+type MigrateResult struct {
+	Copied  int
+	Deleted int     // Old objects removed after their copy succeeded
+	Failed  []error // Per-object copy and delete errors
+}
+
+// Migrate copies each object to its new key, concurrency at a time. Unless
+// keep is set, an old object is deleted once its copy succeeds.
+func Migrate(ctx context.Context, client *s3.Client, bucket string, moves []Move, concurrency int, keep bool) *MigrateResult {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+
+	var (
+		mu     sync.Mutex
+		result MigrateResult
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if ctx.Err() == nil {
+			result.Failed = append(result.Failed, err)
+		}
+	}
+
+	forEach(len(moves), concurrency, func(i int) {
+		if ctx.Err() != nil {
+			return
+		}
+		m := moves[i]
+		_, err := client.CopyObject(ctx, &s3.CopyObjectInput{
+			Bucket:     aws.String(bucket),
+			Key:        aws.String(m.To),
+			CopySource: aws.String(copySource(bucket, m.From)),
+		})
+		if err != nil {
+			fail(fmt.Errorf("failed to copy %s: %w", m.From, err))
+			return
+		}
+		mu.Lock()
+		result.Copied++
+		mu.Unlock()
+
+		if keep {
+			return
+		}
+		if err := deleteObject(ctx, client, bucket, m.From); err != nil {
+			fail(fmt.Errorf("failed to delete %s: %w", m.From, err))
+			return
+		}
+		mu.Lock()
+		result.Deleted++
+		mu.Unlock()
+	})
+	return &result
+}
+
+// copySource returns the URL-encoded bucket/key CopyObject reads from
+func copySource(bucket, key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return bucket + "/" + strings.Join(segments, "/")
+}
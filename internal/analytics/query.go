@@ -0,0 +1,235 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// PromptsPrefix is the root of the date-partitioned prompt keys:
+// prompts/YYYY/MM/DD/<profile>/<file>
+const PromptsPrefix = "prompts/"
+
+// DefaultConcurrency is how many S3 requests a query runs in parallel
+const DefaultConcurrency = 8
+
+// PromptKey returns the key a file recorded at t for profile is stored under
+func PromptKey(t time.Time, profile, name string) string {
+	return DayPrefix(t) + profile + "/" + name
+}
+
+// DayPrefix returns the key prefix holding every profile's prompts for t's day
+func DayPrefix(t time.Time) string {
+	return PromptsPrefix + t.UTC().Format("2006/01/02") + "/"
+}
+
+// TranscriptsPrefix is the root of exported session transcripts:
+// transcripts/YYYY/MM/DD/<profile>/<session>.md
+const TranscriptsPrefix = "transcripts/"
+
+// TranscriptKey returns the key a session transcript that started at t is
+// stored under
+func TranscriptKey(t time.Time, profile, name string) string {
+	return TranscriptsPrefix + t.UTC().Format("2006/01/02") + "/" + profile + "/" + name
+}
+
+// ParsePromptKey extracts the day and profile from a prompt key
+func ParsePromptKey(key string) (day time.Time, profile string, ok bool) {
+	parts := strings.SplitN(strings.TrimPrefix(key, PromptsPrefix), "/", 5)
+	if len(parts) < 5 {
+		return time.Time{}, "", false
+	}
+	day, err := time.Parse("2006/01/02", strings.Join(parts[:3], "/"))
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	return day, parts[3], true
+}
+
+// Prompt is a prompt captured by a container's status server
+type Prompt struct {
+	Time    time.Time
+	Profile string
+	Text    string
+	Turns   int
+	Key     string // Object the prompt was read from
+}
+
+// Query selects prompts from the bucket
+type Query struct {
+	Profile     string    // Only this profile; empty for all
+	Since       time.Time // Earliest prompt time
+	Until       time.Time // Latest prompt time; zero means now
+	Search      string    // Case-insensitive substring of the prompt text
+	Concurrency int       // Parallel S3 requests; zero means DefaultConcurrency
+}
+
+// ListPrompts returns the prompts matching q, newest first. Only the day
+// partitions in the query's range are listed, and objects are fetched in
+// parallel.
+func ListPrompts(ctx context.Context, client *s3.Client, bucket string, q Query) ([]Prompt, error) {
+	if q.Until.IsZero() {
+		q.Until = time.Now()
+	}
+	if q.Concurrency <= 0 {
+		q.Concurrency = DefaultConcurrency
+	}
+
+	var prefixes []string
+	for day := q.Since.UTC().Truncate(24 * time.Hour); !day.After(q.Until.UTC()); day = day.AddDate(0, 0, 1) {
+		prefix := DayPrefix(day)
+		if q.Profile != "" {
+			prefix += q.Profile + "/"
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	keys, err := listKeys(ctx, client, bucket, prefixes, q.Concurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu      sync.Mutex
+		prompts []Prompt
+	)
+	forEach(len(keys), q.Concurrency, func(i int) {
+		found, err := fetchPrompts(ctx, client, bucket, keys[i])
+		if err != nil {
+			return // Unreadable objects are skipped, as before partitioning
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, p := range found {
+			if q.matches(p) {
+				prompts = append(prompts, p)
+			}
+		}
+	})
+
+	sort.Slice(prompts, func(i, j int) bool {
+		return prompts[i].Time.After(prompts[j].Time)
+	})
+	return prompts, nil
+}
+
+func (q Query) matches(p Prompt) bool {
+	if !p.Time.IsZero() && (p.Time.Before(q.Since) || p.Time.After(q.Until)) {
+		return false
+	}
+	if q.Search != "" && !strings.Contains(strings.ToLower(p.Text), strings.ToLower(q.Search)) {
+		return false
+	}
+	return true
+}
+
+// listKeys lists the prompt objects under each prefix, in parallel
+func listKeys(ctx context.Context, client *s3.Client, bucket string, prefixes []string, concurrency int) ([]string, error) {
+	var (
+		mu       sync.Mutex
+		keys     []string
+		firstErr error
+	)
+	forEach(len(prefixes), concurrency, func(i int) {
+		var found []string
+		paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+			Bucket: aws.String(bucket),
+			Prefix: aws.String(prefixes[i]),
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to list %s: %w", prefixes[i], err)
+				}
+				mu.Unlock()
+				return
+			}
+			for _, obj := range page.Contents {
+				// Session records (.jsonl) share the partitions but aren't prompts
+				if obj.Key != nil && path.Ext(*obj.Key) == ".json" {
+					found = append(found, *obj.Key)
+				}
+			}
+		}
+
+		mu.Lock()
+		keys = append(keys, found...)
+		mu.Unlock()
+	})
+	return keys, firstErr
+}
+
+// fetchPrompts reads one uploaded batch of prompts
+func fetchPrompts(ctx context.Context, client *s3.Client, bucket, key string) ([]Prompt, error) {
+	result, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer result.Body.Close()
+
+	var records []struct {
+		Timestamp string `json:"timestamp"`
+		Prompt    struct {
+			Text string `json:"text"`
+		} `json:"prompt"`
+		Outcome struct {
+			NextTurnCount int `json:"next_turn_count"`
+		} `json:"outcome"`
+	}
+	if err := json.NewDecoder(result.Body).Decode(&records); err != nil {
+		return nil, err
+	}
+
+	_, profile, _ := ParsePromptKey(key)
+	prompts := make([]Prompt, 0, len(records))
+	for _, r := range records {
+		prompts = append(prompts, Prompt{
+			Time:    parseTimestamp(r.Timestamp),
+			Profile: profile,
+			Text:    r.Prompt.Text,
+			Turns:   r.Outcome.NextTurnCount,
+			Key:     key,
+		})
+	}
+	return prompts, nil
+}
+
+// parseTimestamp accepts RFC 3339 and the zoneless ISO format Python's
+// datetime.isoformat() produces, which is taken as UTC
+func parseTimestamp(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t
+	}
+	t, _ := time.Parse("2006-01-02T15:04:05.999999", s)
+	return t
+}
+
+// forEach calls fn for 0..n-1 using at most limit goroutines
+func forEach(n, limit int, fn func(i int)) {
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
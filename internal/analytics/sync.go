@@ -0,0 +1,376 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// LocalProfile is the profile partition local analytics files are uploaded to
+const LocalProfile = "local"
+
+// stateFile is the manifest of uploaded files; dot files are never synced
+const stateFile = ".sync-state.json"
+
+// Upload is the manifest entry for an uploaded file
+type Upload struct {
+	Key     string    `json:"key"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	SHA256  string    `json:"sha256"`
+}
+
+// SyncState remembers the last sync and where each file was uploaded, with
+// its checksum, so only new or changed files are uploaded again
+type SyncState struct {
+	LastSync time.Time         `json:"lastSync"`
+	Uploads  map[string]Upload `json:"uploads"`
+
+	// Files is the size-only manifest written by older versions
+	Files map[string]int64 `json:"files,omitempty"`
+}
+
+// LoadSyncState reads the sync state from dir. A missing file yields an empty state.
+func LoadSyncState(dir string) (*SyncState, error) {
+	state := &SyncState{Uploads: make(map[string]Upload)}
+
+	data, err := os.ReadFile(filepath.Join(dir, stateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("failed to read sync state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse sync state: %w", err)
+	}
+	if state.Uploads == nil {
+		state.Uploads = make(map[string]Upload)
+	}
+
+	// Older manifests only have sizes; the next sync uploads those files
+	// again to record their key and checksum
+	for rel, size := range state.Files {
+		if _, ok := state.Uploads[rel]; !ok {
+			state.Uploads[rel] = Upload{Size: size}
+		}
+	}
+	state.Files = nil
+	return state, nil
+}
+
+// Save writes the sync state to dir
+func (s *SyncState) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create analytics directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, stateFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write sync state: %w", err)
+	}
+	return os.Rename(tmp, filepath.Join(dir, stateFile))
+}
+
+// Due reports whether interval has passed since the last sync
+func (s *SyncState) Due(interval time.Duration, now time.Time) bool {
+	return now.Sub(s.LastSync) >= interval
+}
+
+// LocalFile is an analytics file in the local directory
+type LocalFile struct {
+	Path    string // Absolute path
+	Rel     string // Path relative to the analytics directory, with forward slashes
+	Size    int64
+	ModTime time.Time
+}
+
+// eventsFilePattern matches the collector's daily files, including rotated ones
+var eventsFilePattern = regexp.MustCompile(`^events-(\d{4}-\d{2}-\d{2})(\.\d+)?\.jsonl$`)
+
+// Key returns the object key for the file. Collector files are partitioned by
+// the day in their name, anything else by when it was last written.
+func (f LocalFile) Key() string {
+	day := f.ModTime
+	if m := eventsFilePattern.FindStringSubmatch(path.Base(f.Rel)); m != nil {
+		day, _ = time.Parse("2006-01-02", m[1])
+	}
+	return PromptKey(day, LocalProfile, f.Rel)
+}
+
+// ListFiles returns the .json and .jsonl files under dir
+func ListFiles(dir string) ([]LocalFile, error) {
+	var files []LocalFile
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+		if !strings.HasSuffix(path, ".json") && !strings.HasSuffix(path, ".jsonl") {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files = append(files, LocalFile{Path: path, Rel: filepath.ToSlash(rel), Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	return files, err
+}
+
+// Pending returns the files that are new or changed since they were last uploaded
+func (s *SyncState) Pending(files []LocalFile) []LocalFile {
+	var pending []LocalFile
+	for _, f := range files {
+		if s.changed(f) {
+			pending = append(pending, f)
+		}
+	}
+	return pending
+}
+
+// changed reports whether f differs from what was uploaded, or belongs
+// under another key. A file with the same size and modification time is
+// taken as unchanged; otherwise its checksum decides.
+func (s *SyncState) changed(f LocalFile) bool {
+	u, ok := s.Uploads[f.Rel]
+	if !ok || u.Size != f.Size {
+		return true
+	}
+	if u.SHA256 == "" {
+		// Entries from older manifests don't record their key, and the
+		// object may sit under the old layout; upload it again
+		return true
+	}
+	if u.Key != "" && u.Key != f.Key() {
+		return true
+	}
+	if u.ModTime.Equal(f.ModTime) {
+		return false
+	}
+	sum, err := checksum(f)
+	return err != nil || sum != u.SHA256
+}
+
+// Stale returns the manifest entries whose local file no longer exists
+func (s *SyncState) Stale(files []LocalFile) []string {
+	present := make(map[string]bool, len(files))
+	for _, f := range files {
+		present[f.Rel] = true
+	}
+	var stale []string
+	for rel := range s.Uploads {
+		if !present[rel] {
+			stale = append(stale, rel)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// SyncOptions controls a sync
+type SyncOptions struct {
+	Concurrency int  // Uploads in flight at once
+	Delete      bool // Remove uploaded objects whose local file is gone
+}
+
+// DefaultSyncConcurrency is the number of parallel uploads when none is set
+const DefaultSyncConcurrency = 4
+
+// SyncResult summarises a sync
+type SyncResult struct {
+	Uploaded int
+	Skipped  int     // Files unchanged since the last sync
+	Deleted  int     // Objects removed because their local file is gone
+	Stale    int     // Uploaded files gone locally, left in place without Delete
+	Failed   []error // Per-file upload and delete errors
+}
+
+// Sync uploads new and changed files from dir to bucket, partitioned by day
+// under the local profile, and records each one in the manifest as it
+// completes, so an interrupted sync picks up where it stopped
+func Sync(ctx context.Context, client *s3.Client, bucket, dir string, opts SyncOptions) (*SyncResult, error) {
+	state, err := LoadSyncState(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := ListFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list analytics files: %w", err)
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = DefaultSyncConcurrency
+	}
+
+	var pending []LocalFile
+	for _, f := range files {
+		switch u := state.Uploads[f.Rel]; {
+		case state.changed(f):
+			pending = append(pending, f)
+		case !u.ModTime.Equal(f.ModTime):
+			// Touched but identical; remember the new time to skip the checksum
+			u.ModTime = f.ModTime
+			state.Uploads[f.Rel] = u
+		}
+	}
+	result := &SyncResult{Skipped: len(files) - len(pending)}
+
+	var mu sync.Mutex
+	record := func(rel string, u *Upload, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			if ctx.Err() == nil {
+				result.Failed = append(result.Failed, fmt.Errorf("%s: %w", rel, err))
+			}
+			return
+		}
+		if u != nil {
+			state.Uploads[rel] = *u
+			result.Uploaded++
+		} else {
+			delete(state.Uploads, rel)
+			result.Deleted++
+		}
+		// Saved after every change so an interrupted sync isn't repeated
+		if err := state.Save(dir); err != nil {
+			result.Failed = append(result.Failed, err)
+		}
+	}
+
+	forEach(len(pending), opts.Concurrency, func(i int) {
+		if ctx.Err() != nil {
+			return
+		}
+		f := pending[i]
+		sum, err := upload(ctx, client, bucket, f)
+		if err != nil {
+			record(f.Rel, nil, err)
+			return
+		}
+
+		// A file partitioned by modification time moves to a new key when
+		// it is written on another day
+		mu.Lock()
+		previous := state.Uploads[f.Rel].Key
+		mu.Unlock()
+		if opts.Delete && previous != "" && previous != f.Key() {
+			if err := deleteObject(ctx, client, bucket, previous); err != nil {
+				mu.Lock()
+				result.Failed = append(result.Failed, fmt.Errorf("%s: %w", previous, err))
+				mu.Unlock()
+			}
+		}
+		record(f.Rel, &Upload{Key: f.Key(), Size: f.Size, ModTime: f.ModTime, SHA256: sum}, nil)
+	})
+
+	stale := state.Stale(files)
+	if opts.Delete {
+		forEach(len(stale), opts.Concurrency, func(i int) {
+			if ctx.Err() != nil {
+				return
+			}
+			rel := stale[i]
+			mu.Lock()
+			key := state.Uploads[rel].Key
+			mu.Unlock()
+			if key != "" {
+				// Entries from older manifests don't know their key; they
+				// are only dropped
+				if err := deleteObject(ctx, client, bucket, key); err != nil {
+					record(rel, nil, err)
+					return
+				}
+			}
+			record(rel, nil, nil)
+		})
+	} else {
+		result.Stale = len(stale)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+	state.LastSync = time.Now().UTC()
+	if err := state.Save(dir); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// checksum returns the hex SHA-256 of the first f.Size bytes of the file
+func checksum(f LocalFile) (string, error) {
+	data, err := readFile(f)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// readFile reads exactly the bytes counted in Size; the collector may be appending
+func readFile(f LocalFile) ([]byte, error) {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data := make([]byte, f.Size)
+	if _, err := io.ReadFull(file, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func deleteObject(ctx context.Context, client *s3.Client, bucket, key string) error {
+	_, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// upload puts f at its key and returns the checksum of what was uploaded
+func upload(ctx context.Context, client *s3.Client, bucket string, f LocalFile) (string, error) {
+	data, err := readFile(f)
+	if err != nil {
+		return "", err
+	}
+
+	contentType := "application/json"
+	if strings.HasSuffix(f.Rel, ".jsonl") {
+		contentType = "application/x-ndjson"
+	}
+
+	_, err = client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(f.Key()),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,161 @@
+// Package archive packs and unpacks the tar streams frank copies into and out
+// of containers and tasks, following docker cp's rules for where a copy lands.
+package archive
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Extract unpacks a tar stream holding a single file or directory tree, such
+// as 'tar -C dir -c name' or the Docker copy API produce, to dest:
+//
+//   - if dest is an existing directory, the copy is created inside it
+//   - otherwise the copy is created as dest, whose parent must exist
+//
+// Entries that would land outside the copy are rejected.
+func Extract(r io.Reader, dest string) error {
+	info, err := os.Stat(dest)
+	destIsDir := err == nil && info.IsDir()
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to stat %s: %w", dest, err)
+	}
+	if !destIsDir {
+		if _, err := os.Stat(filepath.Dir(dest)); err != nil {
+			return fmt.Errorf("destination directory %s does not exist", filepath.Dir(dest))
+		}
+	}
+
+	tr := tar.NewReader(r)
+	root := ""
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		name := filepath.FromSlash(strings.TrimPrefix(hdr.Name, "./"))
+		name = filepath.Clean(name)
+		if name == "." || filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %q is outside the copy", hdr.Name)
+		}
+		if root == "" {
+			root = strings.SplitN(name, string(filepath.Separator), 2)[0]
+		}
+		rel, ok := strings.CutPrefix(name, root)
+		if !ok || (rel != "" && rel[0] != filepath.Separator) {
+			return fmt.Errorf("archive holds more than one file or directory (%s and %s)", root, name)
+		}
+
+		target := dest + rel
+		if destIsDir {
+			target = filepath.Join(dest, name)
+		}
+		if err := extractEntry(tr, hdr, target); err != nil {
+			return err
+		}
+	}
+	if root == "" {
+		return fmt.Errorf("archive is empty")
+	}
+	return nil
+}
+
+// Write writes src, a file or directory tree, to w as a tar stream whose
+// single top-level entry is called name. Symlinks are stored, not followed.
+func Write(w io.Writer, src, name string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(filepath.Join(name, rel))
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		// Ownership is the container user's, not the local one's
+		hdr.Uid, hdr.Gid, hdr.Uname, hdr.Gname = 0, 0, "", ""
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", src, err)
+	}
+	return tw.Close()
+}
+
+// extractEntry writes one archive entry to target
+func extractEntry(tr *tar.Reader, hdr *tar.Header, target string) error {
+	mode := os.FileMode(hdr.Mode).Perm()
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		if info, err := os.Stat(target); err == nil && !info.IsDir() {
+			return fmt.Errorf("cannot overwrite file %s with a directory", target)
+		}
+		if err := os.MkdirAll(target, mode|0o700); err != nil {
+			return fmt.Errorf("failed to create %s: %w", target, err)
+		}
+	case tar.TypeReg:
+		if info, err := os.Stat(target); err == nil && info.IsDir() {
+			return fmt.Errorf("cannot overwrite directory %s with a file", target)
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", target, err)
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write %s: %w", target, err)
+		}
+	case tar.TypeSymlink:
+		os.Remove(target)
+		if err := os.Symlink(hdr.Linkname, target); err != nil {
+			return fmt.Errorf("failed to create symlink %s: %w", target, err)
+		}
+		return nil
+	default:
+		// Hard links, devices and FIFOs aren't useful outside the container
+		return nil
+	}
+
+	os.Chtimes(target, hdr.ModTime, hdr.ModTime)
+	return nil
+}
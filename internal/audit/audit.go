@@ -0,0 +1,236 @@
+// Package audit keeps an append-only record of the AWS changes frank makes,
+// with the caller identity that made them. Entries go to a local JSONL file
+// and optionally to a CloudWatch Logs stream.
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	logstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	frankaws "github.com/barff/frank/internal/aws"
+)
+
+// FileName is the audit log's name in the frank config directory
+const FileName = "audit.jsonl"
+
+// Entry is one audited AWS call
+type Entry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Service    string    `json:"service"`
+	Operation  string    `json:"operation"`
+	Resource   string    `json:"resource,omitempty"`
+	Region     string    `json:"region,omitempty"`
+	Caller     string    `json:"caller,omitempty"`  // IAM ARN of the credentials used
+	Account    string    `json:"account,omitempty"` // AWS account ID
+	Command    string    `json:"command,omitempty"` // frank command that made the call, e.g. "frank ecs start"
+	DurationMs int64     `json:"duration_ms"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Failed reports whether the audited call returned an error
+func (e Entry) Failed() bool {
+	return e.Error != ""
+}
+
+// Options configures a Logger
+type Options struct {
+	// LogGroup also sends entries to this CloudWatch Logs group when set.
+	// The group must exist; a stream per host is created on first use.
+	LogGroup string
+
+	// Command is recorded with each entry. Only the command path is used, not
+	// its arguments, which may hold tokens.
+	Command string
+}
+
+// Logger appends audit entries to a file
+type Logger struct {
+	path     string
+	logGroup string
+	command  string
+
+	mu sync.Mutex
+
+	identityOnce sync.Once
+	caller       string
+	account      string
+
+	streamOnce sync.Once
+	cw         *cloudwatchlogs.Client
+	stream     string
+}
+
+// Path returns the audit log location in dir
+func Path(dir string) string {
+	return filepath.Join(dir, FileName)
+}
+
+// New creates a logger writing to the audit log in dir
+func New(dir string, opts Options) *Logger {
+	return &Logger{
+		path:     Path(dir),
+		logGroup: opts.LogGroup,
+		command:  opts.Command,
+	}
+}
+
+// Path returns the file the logger appends to
+func (l *Logger) Path() string {
+	return l.path
+}
+
+// Record appends an entry, filling in the time, caller identity and
+// command. Auditing is best effort: failures are logged, never returned, so they
+// can't turn a successful change into a reported failure.
+func (l *Logger) Record(ctx context.Context, e Entry) {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+	if e.Command == "" {
+		e.Command = l.command
+	}
+	if e.Caller == "" {
+		e.Caller, e.Account = l.identity(ctx)
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		slog.Warn(fmt.Sprintf("audit: failed to encode entry: %v", err))
+		return
+	}
+
+	if err := l.append(data); err != nil {
+		slog.Warn(fmt.Sprintf("audit: %v", err))
+	}
+	if l.logGroup != "" {
+		if err := l.sendToCloudWatch(ctx, e.Timestamp, data); err != nil {
+			slog.Warn(fmt.Sprintf("audit: failed to write to CloudWatch Logs group %s: %v", l.logGroup, err))
+		}
+	}
+}
+
+func (l *Logger) append(line []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// identity looks up the caller once per process
+func (l *Logger) identity(ctx context.Context) (caller, account string) {
+	l.identityOnce.Do(func() {
+		cfg, err := config.LoadDefaultConfig(ctx, frankaws.WithRetries())
+		if err != nil {
+			return
+		}
+		out, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		if err != nil {
+			slog.Debug(fmt.Sprintf("audit: failed to get caller identity: %v", err))
+			return
+		}
+		l.caller = aws.ToString(out.Arn)
+		l.account = aws.ToString(out.Account)
+	})
+	return l.caller, l.account
+}
+
+func (l *Logger) sendToCloudWatch(ctx context.Context, t time.Time, message []byte) error {
+	var setupErr error
+	l.streamOnce.Do(func() {
+		cfg, err := config.LoadDefaultConfig(ctx, frankaws.WithRetries())
+		if err != nil {
+			setupErr = err
+			return
+		}
+		l.cw = cloudwatchlogs.NewFromConfig(cfg)
+		l.stream = streamName()
+
+		_, err = l.cw.CreateLogStream(ctx, &cloudwatchlogs.CreateLogStreamInput{
+			LogGroupName:  aws.String(l.logGroup),
+			LogStreamName: aws.String(l.stream),
+		})
+		var exists *logstypes.ResourceAlreadyExistsException
+		if err != nil && !errors.As(err, &exists) {
+			setupErr = err
+		}
+	})
+	if setupErr != nil {
+		return setupErr
+	}
+	if l.cw == nil {
+		return errors.New("client unavailable")
+	}
+
+	_, err := l.cw.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
+		LogGroupName:  aws.String(l.logGroup),
+		LogStreamName: aws.String(l.stream),
+		LogEvents: []logstypes.InputLogEvent{{
+			Message:   aws.String(string(message)),
+			Timestamp: aws.Int64(t.UnixMilli()),
+		}},
+	})
+	return err
+}
+
+// Read returns the entries in the audit log at path, oldest first. A missing
+// file has no entries.
+func Read(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return entries, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// streamName identifies the machine entries come from
+func streamName() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return "frank/" + host
+}
@@ -0,0 +1,127 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// Operations lists the AWS calls that are audited. Reads are never recorded.
+var Operations = map[string]bool{
+	"RunTask":           true,
+	"StopTask":          true,
+	"UpdateService":     true,
+	"CreateTargetGroup": true,
+	"DeleteTargetGroup": true,
+	"RegisterTargets":   true,
+	"DeregisterTargets": true,
+	"CreateRule":        true,
+	"DeleteRule":        true,
+	"PutParameter":      true,
+	"PutSecretValue":    true,
+	"CreateSecret":      true,
+	"UpdateSecret":      true,
+}
+
+// APIOptions returns SDK client options that record every audited operation.
+// Pass them with config.WithAPIOptions when loading an AWS config.
+func (l *Logger) APIOptions() []func(*middleware.Stack) error {
+	return []func(*middleware.Stack) error{
+		func(stack *middleware.Stack) error {
+			// After the service metadata middleware, so the operation name is known
+			return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("FrankAudit", l.handleInitialize), middleware.After)
+		},
+	}
+}
+
+func (l *Logger) handleInitialize(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+	operation := awsmiddleware.GetOperationName(ctx)
+	if !Operations[operation] {
+		return next.HandleInitialize(ctx, in)
+	}
+
+	start := time.Now()
+	out, metadata, err := next.HandleInitialize(ctx, in)
+
+	e := Entry{
+		Timestamp:  start.UTC(),
+		Service:    awsmiddleware.GetServiceID(ctx),
+		Operation:  operation,
+		Resource:   resource(in.Parameters, out.Result),
+		Region:     awsmiddleware.GetRegion(ctx),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	l.Record(ctx, e)
+
+	return out, metadata, err
+}
+
+// resource describes what an audited call acted on, preferring identifiers
+// from the response when the call created something
+func resource(input, output interface{}) string {
+	switch in := input.(type) {
+	case *ecs.RunTaskInput:
+		if out, ok := output.(*ecs.RunTaskOutput); ok && len(out.Tasks) > 0 {
+			arns := make([]string, len(out.Tasks))
+			for i, t := range out.Tasks {
+				arns[i] = aws.ToString(t.TaskArn)
+			}
+			return strings.Join(arns, ",")
+		}
+		return aws.ToString(in.TaskDefinition)
+	case *ecs.StopTaskInput:
+		return aws.ToString(in.Task)
+	case *ecs.UpdateServiceInput:
+		return aws.ToString(in.Service)
+	case *elbv2.CreateTargetGroupInput:
+		if out, ok := output.(*elbv2.CreateTargetGroupOutput); ok && len(out.TargetGroups) > 0 {
+			return aws.ToString(out.TargetGroups[0].TargetGroupArn)
+		}
+		return aws.ToString(in.Name)
+	case *elbv2.DeleteTargetGroupInput:
+		return aws.ToString(in.TargetGroupArn)
+	case *elbv2.RegisterTargetsInput:
+		return targets(aws.ToString(in.TargetGroupArn), len(in.Targets), func(i int) string { return aws.ToString(in.Targets[i].Id) })
+	case *elbv2.DeregisterTargetsInput:
+		return targets(aws.ToString(in.TargetGroupArn), len(in.Targets), func(i int) string { return aws.ToString(in.Targets[i].Id) })
+	case *elbv2.CreateRuleInput:
+		if out, ok := output.(*elbv2.CreateRuleOutput); ok && len(out.Rules) > 0 {
+			return aws.ToString(out.Rules[0].RuleArn)
+		}
+		return fmt.Sprintf("%s priority %d", aws.ToString(in.ListenerArn), aws.ToInt32(in.Priority))
+	case *elbv2.DeleteRuleInput:
+		return aws.ToString(in.RuleArn)
+	case *ssm.PutParameterInput:
+		return aws.ToString(in.Name)
+	case *secretsmanager.PutSecretValueInput:
+		return aws.ToString(in.SecretId)
+	case *secretsmanager.CreateSecretInput:
+		if out, ok := output.(*secretsmanager.CreateSecretOutput); ok && out.ARN != nil {
+			return aws.ToString(out.ARN)
+		}
+		return aws.ToString(in.Name)
+	case *secretsmanager.UpdateSecretInput:
+		return aws.ToString(in.SecretId)
+	}
+	return ""
+}
+
+func targets(group string, n int, id func(int) string) string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = id(i)
+	}
+	return fmt.Sprintf("%s [%s]", group, strings.Join(ids, ","))
+}
@@ -0,0 +1,409 @@
+// Package authstore encrypts the token files frank keeps under
+// ~/.config/frank/auth.
+//
+// A store is locked with either a passphrase or an X25519 key. The lock file
+// records which, along with the passphrase's salt or the key's public half,
+// so tokens stored later are sealed the same way. Files are sealed with
+// AES-256-GCM: under a key derived from the passphrase with PBKDF2, or under
+// a key agreed between a fresh X25519 key and the store's public key, so a
+// key-locked store can take new tokens without its private key.
+package authstore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// Header starts every sealed file
+	Header = "frank-sealed-v1\n"
+
+	// LockFileName is the lock file inside a locked store
+	LockFileName = ".lock"
+
+	// DefaultIterations is the PBKDF2-SHA256 work factor for new passphrase
+	// locks
+	DefaultIterations = 600000
+
+	// checkValue is sealed into a passphrase lock to recognise a wrong
+	// passphrase
+	checkValue = "frank"
+)
+
+// Mode is how a store is locked
+type Mode string
+
+const (
+	// ModePassphrase derives the file key from a passphrase
+	ModePassphrase Mode = "passphrase"
+
+	// ModeKey seals files to an X25519 public key
+	ModeKey Mode = "x25519"
+)
+
+// ErrNoIdentity is returned when opening a key-locked file without the
+// private key
+var ErrNoIdentity = errors.New("the private key is needed to decrypt")
+
+// Lock describes how a store is encrypted. It holds nothing secret.
+type Lock struct {
+	Mode       Mode   `json:"mode"`
+	Salt       string `json:"salt,omitempty"`
+	Iterations int    `json:"iterations,omitempty"`
+	Check      string `json:"check,omitempty"`
+
+	// Recipient is the base64 X25519 public key of a key lock
+	Recipient string `json:"recipient,omitempty"`
+}
+
+// Cipher seals and opens a store's files
+type Cipher interface {
+	Seal(plaintext []byte) ([]byte, error)
+	Open(sealed []byte) ([]byte, error)
+}
+
+// IsSealed reports whether data is a sealed file
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(Header))
+}
+
+// ReadLock returns the lock of the store in dir, or nil when it isn't locked
+func ReadLock(dir string) (*Lock, error) {
+	data, err := os.ReadFile(filepath.Join(dir, LockFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file: %w", err)
+	}
+	return &lock, nil
+}
+
+// WriteLock saves the lock of the store in dir
+func WriteLock(dir string, lock *Lock) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode lock file: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create auth directory: %w", err)
+	}
+	return WriteFile(filepath.Join(dir, LockFileName), append(data, '\n'))
+}
+
+// RemoveLock unlocks the store in dir once its files are decrypted
+func RemoveLock(dir string) error {
+	err := os.Remove(filepath.Join(dir, LockFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove lock file: %w", err)
+	}
+	return nil
+}
+
+// WriteFile replaces path with data, readable only by the user, so a reader
+// never sees a partial write
+func WriteFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set permissions on %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// ============================================================================
+// Passphrase locks
+// ============================================================================
+
+// NewPassphraseLock creates a lock for passphrase with a fresh salt, and the
+// cipher it unlocks
+func NewPassphraseLock(passphrase string) (*Lock, Cipher, error) {
+	if passphrase == "" {
+		return nil, nil, fmt.Errorf("passphrase must not be empty")
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	c := &gcmCipher{key: pbkdf2.Key([]byte(passphrase), salt, DefaultIterations, 32, sha256.New)}
+	check, err := c.Seal([]byte(checkValue))
+	if err != nil {
+		return nil, nil, err
+	}
+	lock := &Lock{
+		Mode:       ModePassphrase,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Iterations: DefaultIterations,
+		Check:      string(check),
+	}
+	return lock, c, nil
+}
+
+// Passphrase returns the cipher of a passphrase lock, failing when the
+// passphrase is wrong
+func (l *Lock) Passphrase(passphrase string) (Cipher, error) {
+	if l.Mode != ModePassphrase {
+		return nil, fmt.Errorf("the auth store is locked with a key, not a passphrase")
+	}
+	salt, err := base64.StdEncoding.DecodeString(l.Salt)
+	if err != nil || l.Iterations <= 0 {
+		return nil, fmt.Errorf("invalid passphrase lock")
+	}
+
+	c := &gcmCipher{key: pbkdf2.Key([]byte(passphrase), salt, l.Iterations, 32, sha256.New)}
+	if check, err := c.Open([]byte(l.Check)); err != nil || string(check) != checkValue {
+		return nil, fmt.Errorf("wrong passphrase")
+	}
+	return c, nil
+}
+
+// gcmCipher seals with AES-256-GCM under a fixed key. A sealed file is the
+// header and base64 of nonce || ciphertext.
+type gcmCipher struct {
+	key []byte
+}
+
+func (c *gcmCipher) Seal(plaintext []byte) ([]byte, error) {
+	sealed, err := sealGCM(c.key, plaintext, nil)
+	if err != nil {
+		return nil, err
+	}
+	return encode(sealed), nil
+}
+
+func (c *gcmCipher) Open(data []byte) ([]byte, error) {
+	sealed, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return openGCM(c.key, sealed, nil)
+}
+
+// ============================================================================
+// Key locks
+// ============================================================================
+
+// NewKeyLock creates a lock that seals files to recipient
+func NewKeyLock(recipient *ecdh.PublicKey) *Lock {
+	return &Lock{
+		Mode:      ModeKey,
+		Recipient: base64.StdEncoding.EncodeToString(recipient.Bytes()),
+	}
+}
+
+// Key returns the cipher of a key lock. With a nil identity the cipher can
+// seal but not open.
+func (l *Lock) Key(identity *ecdh.PrivateKey) (Cipher, error) {
+	if l.Mode != ModeKey {
+		return nil, fmt.Errorf("the auth store is locked with a passphrase, not a key")
+	}
+	raw, err := base64.StdEncoding.DecodeString(l.Recipient)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key lock: %w", err)
+	}
+	recipient, err := ecdh.X25519().NewPublicKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key lock: %w", err)
+	}
+	if identity != nil && !identity.PublicKey().Equal(recipient) {
+		return nil, fmt.Errorf("the private key doesn't match the auth store's lock")
+	}
+	return &keyCipher{recipient: recipient, identity: identity}, nil
+}
+
+// keyCipher seals each file under a key agreed between a fresh X25519 key
+// and the recipient. A sealed file is the header and base64 of the fresh
+// public key || nonce || ciphertext.
+type keyCipher struct {
+	recipient *ecdh.PublicKey
+	identity  *ecdh.PrivateKey
+}
+
+func (c *keyCipher) Seal(plaintext []byte) ([]byte, error) {
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	shared, err := ephemeral.ECDH(c.recipient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to agree key: %w", err)
+	}
+
+	public := ephemeral.PublicKey().Bytes()
+	sealed, err := sealGCM(c.fileKey(shared, public), plaintext, public)
+	if err != nil {
+		return nil, err
+	}
+	return encode(append(public, sealed...)), nil
+}
+
+func (c *keyCipher) Open(data []byte) ([]byte, error) {
+	if c.identity == nil {
+		return nil, ErrNoIdentity
+	}
+	sealed, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < 32 {
+		return nil, fmt.Errorf("sealed file is truncated")
+	}
+
+	public, sealed := sealed[:32], sealed[32:]
+	ephemeral, err := ecdh.X25519().NewPublicKey(public)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sealed file: %w", err)
+	}
+	shared, err := c.identity.ECDH(ephemeral)
+	if err != nil {
+		return nil, fmt.Errorf("failed to agree key: %w", err)
+	}
+	return openGCM(c.fileKey(shared, public), sealed, public)
+}
+
+// fileKey binds the agreed secret to both public keys
+func (c *keyCipher) fileKey(shared, ephemeral []byte) []byte {
+	mac := hmac.New(sha256.New, shared)
+	mac.Write([]byte(Header))
+	mac.Write(ephemeral)
+	mac.Write(c.recipient.Bytes())
+	return mac.Sum(nil)
+}
+
+// GenerateIdentity creates a new X25519 private key
+func GenerateIdentity() (*ecdh.PrivateKey, error) {
+	identity, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	return identity, nil
+}
+
+// LoadIdentity reads a private key written by SaveIdentity
+func LoadIdentity(path string) (*ecdh.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key file %s: %w", path, err)
+		}
+		identity, err := ecdh.X25519().NewPrivateKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key file %s: %w", path, err)
+		}
+		return identity, nil
+	}
+	return nil, fmt.Errorf("invalid key file %s: no key found", path)
+}
+
+// SaveIdentity writes a private key to path, readable only by the user,
+// with its public key as a comment
+func SaveIdentity(path string, identity *ecdh.PrivateKey) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create key directory: %w", err)
+	}
+	content := fmt.Sprintf("# created: %s\n# public key: %s\n%s\n",
+		time.Now().UTC().Format(time.RFC3339),
+		base64.StdEncoding.EncodeToString(identity.PublicKey().Bytes()),
+		base64.StdEncoding.EncodeToString(identity.Bytes()))
+	return WriteFile(path, []byte(content))
+}
+
+// ============================================================================
+// Helper functions
+// ============================================================================
+
+// sealGCM encrypts plaintext under key, returning nonce || ciphertext
+func sealGCM(key, plaintext, additional []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+// openGCM decrypts nonce || ciphertext under key
+func openGCM(key, sealed, additional []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("sealed file is truncated")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additional)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: wrong key or corrupted file")
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func encode(sealed []byte) []byte {
+	return []byte(Header + base64.StdEncoding.EncodeToString(sealed) + "\n")
+}
+
+func decode(data []byte) ([]byte, error) {
+	if !IsSealed(data) {
+		return nil, fmt.Errorf("not a sealed file")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data[len(Header):])))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sealed file: %w", err)
+	}
+	return sealed, nil
+}
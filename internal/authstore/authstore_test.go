@@ -0,0 +1,253 @@
+package authstore
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// legacyLock and legacySealed were written by the hand-rolled PBKDF2 this
+// package used before golang.org/x/crypto/pbkdf2, with 1000 iterations
+const (
+	legacyLock   = `{"mode":"passphrase","salt":"iWlAi6XbIyOwdSwgryewLg==","iterations":1000,"check":"frank-sealed-v1\nQ3K5yV6ophnxmyIzSzW0ve8kPWGcOo64S4J7sg9luCWK\n"}`
+	legacySealed = "frank-sealed-v1\naJLLUrYCCrgfM0qLAo4pPPxLuSNhrK6ZSFCK1ou0pLoNUxfV3OT0WPS3bA==\n"
+)
+
+func TestPassphraseRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	lock, sealer, err := NewPassphraseLock("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteLock(dir, lock); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "github")
+	writeSealed(t, sealer, path, "ghp_token")
+
+	read, err := ReadLock(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opener, err := read.Passphrase("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readSealed(t, opener, path); got != "ghp_token" {
+		t.Errorf("opened %q, want %q", got, "ghp_token")
+	}
+}
+
+func TestPassphraseWrong(t *testing.T) {
+	lock, _, err := NewPassphraseLock("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lock.Passphrase("battery staple"); err == nil {
+		t.Error("wrong passphrase unlocked the store")
+	}
+	if _, err := lock.Key(nil); err == nil {
+		t.Error("passphrase lock opened as a key lock")
+	}
+}
+
+func TestPassphraseEmpty(t *testing.T) {
+	if _, _, err := NewPassphraseLock(""); err == nil {
+		t.Error("empty passphrase was accepted")
+	}
+}
+
+func TestPassphraseLegacyLock(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, LockFileName), []byte(legacyLock), 0600); err != nil {
+		t.Fatal(err)
+	}
+	lock, err := ReadLock(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := lock.Passphrase("correct horse")
+	if err != nil {
+		t.Fatalf("legacy lock didn't unlock: %v", err)
+	}
+	got, err := c.Open([]byte(legacySealed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "ghp_legacytoken" {
+		t.Errorf("opened %q, want %q", got, "ghp_legacytoken")
+	}
+}
+
+func TestKeyRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	identity, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(dir, "keys", "auth.key")
+	if err := SaveIdentity(keyPath, identity); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteLock(dir, NewKeyLock(identity.PublicKey())); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tokens can be stored without the private key
+	lock, err := ReadLock(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealer, err := lock.Key(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "gitlab")
+	writeSealed(t, sealer, path, "glpat-token")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sealer.Open(data); !errors.Is(err, ErrNoIdentity) {
+		t.Errorf("open without the private key: got %v, want ErrNoIdentity", err)
+	}
+
+	loaded, err := LoadIdentity(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opener, err := lock.Key(loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readSealed(t, opener, path); got != "glpat-token" {
+		t.Errorf("opened %q, want %q", got, "glpat-token")
+	}
+}
+
+func TestKeyWrongIdentity(t *testing.T) {
+	identity, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock := NewKeyLock(identity.PublicKey())
+	if _, err := lock.Key(other); err == nil {
+		t.Error("a different private key unlocked the store")
+	}
+	if _, err := lock.Passphrase("anything"); err == nil {
+		t.Error("key lock opened as a passphrase lock")
+	}
+}
+
+func TestTamper(t *testing.T) {
+	_, passphrase, err := NewPassphraseLock("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	identity, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := NewKeyLock(identity.PublicKey()).Key(identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, c := range map[string]Cipher{"passphrase": passphrase, "key": key} {
+		t.Run(name, func(t *testing.T) {
+			data, err := c.Seal([]byte("secret"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sealed, err := decode(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Every byte is covered: nonce, ciphertext, tag and, for key
+			// locks, the ephemeral public key
+			for i := range sealed {
+				tampered := bytes.Clone(sealed)
+				tampered[i] ^= 0x01
+				if _, err := c.Open(encode(tampered)); err == nil {
+					t.Fatalf("flipping byte %d went unnoticed", i)
+				}
+			}
+			if _, err := c.Open(encode(sealed[:len(sealed)/2])); err == nil {
+				t.Error("truncated file opened")
+			}
+			if _, err := c.Open([]byte("ghp_plaintext\n")); err == nil {
+				t.Error("unsealed file opened")
+			}
+		})
+	}
+}
+
+func TestReadLockMissing(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := ReadLock(dir)
+	if err != nil || lock != nil {
+		t.Fatalf("ReadLock of an unlocked store = %v, %v; want nil, nil", lock, err)
+	}
+
+	identity, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteLock(dir, NewKeyLock(identity.PublicKey())); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveLock(dir); err != nil {
+		t.Fatal(err)
+	}
+	if lock, err := ReadLock(dir); err != nil || lock != nil {
+		t.Errorf("ReadLock after RemoveLock = %v, %v; want nil, nil", lock, err)
+	}
+	if err := RemoveLock(dir); err != nil {
+		t.Errorf("RemoveLock of an unlocked store: %v", err)
+	}
+}
+
+// writeSealed seals value to path and checks only the user can read it
+func writeSealed(t *testing.T, c Cipher, path, value string) {
+	t.Helper()
+	sealed, err := c.Seal([]byte(value))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealed(sealed) || bytes.Contains(sealed, []byte(value)) {
+		t.Fatalf("sealed file exposes its contents: %q", sealed)
+	}
+	if err := WriteFile(path, sealed); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("%s has mode %o, want 600", path, perm)
+	}
+}
+
+// readSealed opens the sealed file at path
+func readSealed(t *testing.T, c Cipher, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := c.Open(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(plaintext)
+}
@@ -0,0 +1,36 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// CallerName returns a short name for whoever the credentials in cfg belong
+// to, as NameFromARN
+func CallerName(ctx context.Context, cfg sdkaws.Config) (string, error) {
+	out, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get caller identity: %w", err)
+	}
+	return NameFromARN(sdkaws.ToString(out.Arn)), nil
+}
+
+// NameFromARN shortens a caller identity ARN to the person behind it: the
+// session name of an assumed role (the user name for SSO), the user name of
+// an IAM user, or the last part of anything else
+func NameFromARN(arn string) string {
+	// arn:<partition>:<service>:<region>:<account>:<resource>
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 {
+		return arn
+	}
+	resource := parts[5]
+	if resource == "root" {
+		return "root"
+	}
+	return resource[strings.LastIndex(resource, "/")+1:]
+}
@@ -0,0 +1,80 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// DeniedActions returns the IAM actions the caller's policies don't allow,
+// asking the IAM policy simulator. Actions are simulated against every
+// resource, so a policy scoped to particular resources can still show up as
+// a denial. The root user is never denied anything.
+//
+// The simulator itself needs iam:SimulatePrincipalPolicy (and iam:GetRole for
+// assumed roles); without them an error is returned and the caller can't
+// tell what's allowed.
+func DeniedActions(ctx context.Context, cfg sdkaws.Config, actions []string) ([]string, error) {
+	identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get caller identity: %w", err)
+	}
+
+	client := iam.NewFromConfig(cfg)
+	principal, err := principalARN(ctx, client, sdkaws.ToString(identity.Arn))
+	if err != nil {
+		return nil, err
+	}
+	if principal == "" {
+		return nil, nil
+	}
+
+	var denied []string
+	paginator := iam.NewSimulatePrincipalPolicyPaginator(client, &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: sdkaws.String(principal),
+		ActionNames:     actions,
+	})
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to simulate policies for %s: %w", principal, err)
+		}
+		for _, result := range out.EvaluationResults {
+			if result.EvalDecision != iamtypes.PolicyEvaluationDecisionTypeAllowed {
+				denied = append(denied, sdkaws.ToString(result.EvalActionName))
+			}
+		}
+	}
+	return denied, nil
+}
+
+// principalARN returns the IAM user or role behind a caller identity ARN, or
+// "" for the root user. Assumed-role sessions are looked up as their role,
+// whose ARN includes a path the session ARN leaves out.
+func principalARN(ctx context.Context, client *iam.Client, arn string) (string, error) {
+	// arn:<partition>:<service>:<region>:<account>:<resource>
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 {
+		return "", fmt.Errorf("unexpected caller ARN %q", arn)
+	}
+	resource := parts[5]
+	switch {
+	case resource == "root":
+		return "", nil
+	case strings.HasPrefix(resource, "assumed-role/"):
+		// assumed-role/<role>/<session>
+		role := strings.SplitN(strings.TrimPrefix(resource, "assumed-role/"), "/", 2)[0]
+		out, err := client.GetRole(ctx, &iam.GetRoleInput{RoleName: sdkaws.String(role)})
+		if err != nil {
+			return "", fmt.Errorf("failed to look up role %s: %w", role, err)
+		}
+		return sdkaws.ToString(out.Role.Arn), nil
+	default:
+		return arn, nil
+	}
+}
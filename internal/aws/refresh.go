@@ -0,0 +1,134 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CredentialsFileName is the file written inside a container credentials directory
+const CredentialsFileName = "credentials"
+
+// DefaultCredentialLifetime is assumed when the SSO session does not report an expiration
+const DefaultCredentialLifetime = time.Hour
+
+// CredentialsDir returns the host directory holding refreshable credentials for a container
+func CredentialsDir(containerName string) string {
+	return filepath.Join(getHomeDir(), ".config", "frank", "aws", containerName)
+}
+
+// WriteCredentialsFile writes creds as a shared credentials file in dir under
+// both the named profile and [default]. The file is replaced atomically so a
+// process reading it inside the container never sees a partial write.
+func WriteCredentialsFile(dir, profile string, creds *Credentials) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+
+	var content string
+	sections := []string{"default"}
+	if profile != "" && profile != "default" {
+		sections = append(sections, profile)
+	}
+	for _, section := range sections {
+		content += fmt.Sprintf("[%s]\n", section)
+		content += fmt.Sprintf("aws_access_key_id = %s\n", creds.AccessKeyID)
+		content += fmt.Sprintf("aws_secret_access_key = %s\n", creds.SecretAccessKey)
+		if creds.SessionToken != "" {
+			content += fmt.Sprintf("aws_session_token = %s\n", creds.SessionToken)
+		}
+		if creds.Region != "" {
+			content += fmt.Sprintf("region = %s\n", creds.Region)
+		}
+		content += "\n"
+	}
+
+	tmp, err := os.CreateTemp(dir, ".credentials-*")
+	if err != nil {
+		return fmt.Errorf("failed to create credentials file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set credentials file permissions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, CredentialsFileName)); err != nil {
+		return fmt.Errorf("failed to replace credentials file: %w", err)
+	}
+	return nil
+}
+
+// Refresher periodically re-exports SSO credentials for a profile into a
+// credentials directory mounted into a container
+type Refresher struct {
+	manager *SSOManager
+	profile string
+	dir     string
+	buffer  time.Duration
+
+	// OnRefresh is called after each refresh attempt with the new expiration or an error
+	OnRefresh func(expiresAt time.Time, err error)
+}
+
+// NewRefresher creates a refresher that renews credentials buffer before they expire
+func NewRefresher(profile, dir string, buffer time.Duration) *Refresher {
+	return &Refresher{
+		manager: NewSSOManager(),
+		profile: profile,
+		dir:     dir,
+		buffer:  buffer,
+	}
+}
+
+// Refresh exports fresh credentials once and writes them to the credentials file
+func (r *Refresher) Refresh() (time.Time, error) {
+	creds, err := r.manager.GetCredentials(r.profile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := WriteCredentialsFile(r.dir, r.profile, creds); err != nil {
+		return time.Time{}, err
+	}
+
+	expiresAt := creds.Expiration
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(DefaultCredentialLifetime)
+	}
+	return expiresAt, nil
+}
+
+// Run refreshes credentials until ctx is cancelled. Failed refreshes are
+// retried every minute so a later `aws sso login` on the host is picked up.
+func (r *Refresher) Run(ctx context.Context) error {
+	for {
+		expiresAt, err := r.Refresh()
+		if r.OnRefresh != nil {
+			r.OnRefresh(expiresAt, err)
+		}
+
+		wait := time.Minute
+		if err == nil {
+			wait = time.Until(expiresAt) - r.buffer
+			if wait < time.Minute {
+				wait = time.Minute
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package aws
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+const (
+	// MaxAttempts is how many times a throttled or failed call is tried
+	MaxAttempts = 8
+
+	// MaxBackoff caps the jittered delay between attempts
+	MaxBackoff = 20 * time.Second
+
+	// ConnectTimeout bounds dialing and the TLS handshake of an attempt
+	ConnectTimeout = 10 * time.Second
+
+	// ResponseTimeout bounds the wait for response headers, so a stalled
+	// connection fails the attempt and is retried instead of hanging. Bodies
+	// such as S3 downloads may take longer.
+	ResponseTimeout = 30 * time.Second
+)
+
+var (
+	retryerOnce sync.Once
+	retryer     sdkaws.Retryer
+)
+
+// sharedRetryer returns the process-wide adaptive retryer. Sharing it lets
+// every client back off together when one of them is throttled.
+func sharedRetryer() sdkaws.Retryer {
+	retryerOnce.Do(func() {
+		retryer = retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+			o.StandardOptions = append(o.StandardOptions, func(so *retry.StandardOptions) {
+				so.MaxAttempts = MaxAttempts
+				so.MaxBackoff = MaxBackoff
+			})
+		})
+	})
+	return retryer
+}
+
+// WithRetries configures SDK clients to retry throttled and transient
+// failures in adaptive mode and to time out attempts that stall. Pass it to
+// config.LoadDefaultConfig next to the other load options.
+func WithRetries() func(*config.LoadOptions) error {
+	return func(o *config.LoadOptions) error {
+		o.Retryer = sharedRetryer
+		o.HTTPClient = awshttp.NewBuildableClient().
+			WithDialerOptions(func(d *net.Dialer) {
+				d.Timeout = ConnectTimeout
+			}).
+			WithTransportOptions(func(t *http.Transport) {
+				t.TLSHandshakeTimeout = ConnectTimeout
+				t.ResponseHeaderTimeout = ResponseTimeout
+			})
+		return nil
+	}
+}
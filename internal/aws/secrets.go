@@ -0,0 +1,147 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smtypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+// SecretsAPI is the part of the Secrets Manager client SecretsClient uses
+type SecretsAPI interface {
+	DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error)
+	CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error)
+	UpdateSecret(ctx context.Context, params *secretsmanager.UpdateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretOutput, error)
+	PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error)
+	TagResource(ctx context.Context, params *secretsmanager.TagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.TagResourceOutput, error)
+}
+
+// SecretsClient writes secrets to AWS Secrets Manager, creating the ones
+// that don't exist yet
+type SecretsClient struct {
+	api SecretsAPI
+
+	// KMSKeyID encrypts the secrets the client writes; empty keeps a
+	// secret's key, or uses aws/secretsmanager for new secrets
+	KMSKeyID string
+
+	// Tags are set on every secret the client writes
+	Tags map[string]string
+}
+
+// NewSecretsClient creates a client with the shared retry settings. optFns
+// are applied after them, e.g. to add API middleware.
+func NewSecretsClient(ctx context.Context, optFns ...func(*config.LoadOptions) error) (*SecretsClient, error) {
+	optFns = append([]func(*config.LoadOptions) error{WithRetries()}, optFns...)
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return &SecretsClient{api: secretsmanager.NewFromConfig(cfg)}, nil
+}
+
+// Exists reports whether a secret exists
+func (c *SecretsClient) Exists(ctx context.Context, secretID string) (bool, error) {
+	_, err := c.describe(ctx, secretID)
+	if errors.Is(err, errSecretNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Put stores value in a secret, creating the secret when it doesn't exist,
+// and reports whether it was created
+func (c *SecretsClient) Put(ctx context.Context, secretID, value string) (created bool, err error) {
+	secret, err := c.describe(ctx, secretID)
+	if errors.Is(err, errSecretNotFound) {
+		_, err := c.api.CreateSecret(ctx, &secretsmanager.CreateSecretInput{
+			Name:         sdkaws.String(secretID),
+			SecretString: sdkaws.String(value),
+			KmsKeyId:     optionalString(c.KMSKeyID),
+			Tags:         c.tags(),
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to create secret: %w", err)
+		}
+		return true, nil
+	}
+	// Without permission to describe the secret, the put still goes ahead
+	// and reports any real problem
+
+	// A new key applies to the value written next
+	if err == nil && c.KMSKeyID != "" && c.KMSKeyID != sdkaws.ToString(secret.KmsKeyId) {
+		_, err := c.api.UpdateSecret(ctx, &secretsmanager.UpdateSecretInput{
+			SecretId: sdkaws.String(secretID),
+			KmsKeyId: sdkaws.String(c.KMSKeyID),
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to change the secret's KMS key: %w", err)
+		}
+	}
+
+	_, err = c.api.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:     sdkaws.String(secretID),
+		SecretString: sdkaws.String(value),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to put secret value: %w", err)
+	}
+
+	if len(c.Tags) > 0 {
+		_, err := c.api.TagResource(ctx, &secretsmanager.TagResourceInput{
+			SecretId: sdkaws.String(secretID),
+			Tags:     c.tags(),
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to tag secret: %w", err)
+		}
+	}
+	return false, nil
+}
+
+// errSecretNotFound is returned by describe for a missing secret
+var errSecretNotFound = errors.New("secret not found")
+
+func (c *SecretsClient) describe(ctx context.Context, secretID string) (*secretsmanager.DescribeSecretOutput, error) {
+	out, err := c.api.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+		SecretId: sdkaws.String(secretID),
+	})
+	var notFound *smtypes.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return nil, errSecretNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe secret: %w", err)
+	}
+	return out, nil
+}
+
+// tags returns the client's tags sorted by key
+func (c *SecretsClient) tags() []smtypes.Tag {
+	keys := make([]string, 0, len(c.Tags))
+	for key := range c.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tags := make([]smtypes.Tag, len(keys))
+	for i, key := range keys {
+		tags[i] = smtypes.Tag{Key: sdkaws.String(key), Value: sdkaws.String(c.Tags[key])}
+	}
+	return tags
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return sdkaws.String(s)
+}
@@ -63,6 +63,11 @@ func (m *SSOManager) CheckCredentials(profile string) (bool, time.Time, error) {
 	return true, expiresAt, nil
 }
 
+// SessionExpiry returns when the most recent cached SSO session expires
+func (m *SSOManager) SessionExpiry() (time.Time, error) {
+	return m.getCredentialExpiration("")
+}
+
 // getCredentialExpiration tries to determine credential expiration from cache
 func (m *SSOManager) getCredentialExpiration(profile string) (time.Time, error) {
 	// Read SSO cache files
@@ -145,6 +150,10 @@ func (m *SSOManager) GetCredentials(profile string) (*Credentials, error) {
 			creds.SecretAccessKey = value
 		case "AWS_SESSION_TOKEN":
 			creds.SessionToken = value
+		case "AWS_CREDENTIAL_EXPIRATION":
+			if expiresAt, err := time.Parse(time.RFC3339, value); err == nil {
+				creds.Expiration = expiresAt
+			}
 		}
 	}
 
@@ -228,19 +237,6 @@ func getHomeDir() string {
 	return home
 }
 
-// PutSecretValue updates a secret in AWS Secrets Manager using the AWS CLI
-func (m *SSOManager) PutSecretValue(secretID, value string) error {
-	cmd := exec.Command("aws", "secretsmanager", "put-secret-value",
-		"--secret-id", secretID,
-		"--secret-string", value,
-	)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("%s: %s", err, strings.TrimSpace(string(output)))
-	}
-	return nil
-}
-
 // CredentialsToEnv converts credentials to environment variable format
 func CredentialsToEnv(creds *Credentials) []string {
 	env := []string{
@@ -0,0 +1,182 @@
+package claude
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// OAuth endpoint and public client ID Claude Code refreshes its tokens with
+const (
+	OAuthTokenURL = "https://console.anthropic.com/v1/oauth/token"
+	OAuthClientID = "9d1c250a-e61b-44d9-88ed-5944d1962f5e"
+)
+
+// CredentialsPath returns where Claude Code keeps its OAuth credentials
+func CredentialsPath() string {
+	return filepath.Join(getHomeDir(), ".claude", ".credentials.json")
+}
+
+// Credentials are the OAuth tokens in Claude Code's credentials file. Fields
+// frank doesn't use are kept as they were when the file is saved.
+type Credentials struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time // zero when the file doesn't say
+
+	raw   map[string]json.RawMessage // the whole file
+	oauth map[string]json.RawMessage // its claudeAiOauth object
+}
+
+// LoadCredentials reads a credentials file in the current claudeAiOauth
+// format
+func LoadCredentials(path string) (*Credentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCredentials(data)
+}
+
+// ParseCredentials parses the contents of a credentials file
+func ParseCredentials(data []byte) (*Credentials, error) {
+	c := &Credentials{}
+	if err := json.Unmarshal(data, &c.raw); err != nil {
+		return nil, fmt.Errorf("invalid credentials file: %w", err)
+	}
+	if err := json.Unmarshal(c.raw["claudeAiOauth"], &c.oauth); err != nil || c.oauth == nil {
+		return nil, fmt.Errorf("credentials file has no claudeAiOauth tokens")
+	}
+
+	var fields struct {
+		AccessToken  string `json:"accessToken"`
+		RefreshToken string `json:"refreshToken"`
+		ExpiresAt    int64  `json:"expiresAt"` // Unix milliseconds
+	}
+	if err := json.Unmarshal(c.raw["claudeAiOauth"], &fields); err != nil {
+		return nil, fmt.Errorf("invalid claudeAiOauth tokens: %w", err)
+	}
+	c.AccessToken = fields.AccessToken
+	c.RefreshToken = fields.RefreshToken
+	if fields.ExpiresAt > 0 {
+		c.ExpiresAt = time.UnixMilli(fields.ExpiresAt)
+	}
+	return c, nil
+}
+
+// ExpiresWithin reports whether the access token expires within d of now.
+// Tokens without an expiry never do.
+func (c *Credentials) ExpiresWithin(d time.Duration) bool {
+	return !c.ExpiresAt.IsZero() && time.Until(c.ExpiresAt) < d
+}
+
+// Expired reports whether the access token has expired
+func (c *Credentials) Expired() bool {
+	return c.ExpiresWithin(0)
+}
+
+// Refresh exchanges the refresh token for a new access token, as Claude
+// Code does when its token expires. The server may rotate the refresh token
+// too.
+func (c *Credentials) Refresh(ctx context.Context, client *http.Client) error {
+	if c.RefreshToken == "" {
+		return fmt.Errorf("credentials have no refresh token; run 'claude' to log in again")
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": c.RefreshToken,
+		"client_id":     OAuthClientID,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, OAuthTokenURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to refresh token: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token refresh failed: %s: %s", resp.Status, bytes.TrimSpace(data))
+	}
+
+	var token struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"` // seconds
+	}
+	if err := json.Unmarshal(data, &token); err != nil {
+		return fmt.Errorf("invalid token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return fmt.Errorf("token response has no access token")
+	}
+
+	c.AccessToken = token.AccessToken
+	if token.RefreshToken != "" {
+		c.RefreshToken = token.RefreshToken
+	}
+	c.ExpiresAt = time.Time{}
+	if token.ExpiresIn > 0 {
+		c.ExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return nil
+}
+
+// Marshal returns the credentials file contents with the current tokens
+func (c *Credentials) Marshal() ([]byte, error) {
+	set := func(key string, value interface{}) error {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		c.oauth[key] = data
+		return nil
+	}
+	if err := set("accessToken", c.AccessToken); err != nil {
+		return nil, err
+	}
+	if err := set("refreshToken", c.RefreshToken); err != nil {
+		return nil, err
+	}
+	if !c.ExpiresAt.IsZero() {
+		if err := set("expiresAt", c.ExpiresAt.UnixMilli()); err != nil {
+			return nil, err
+		}
+	}
+
+	oauth, err := json.Marshal(c.oauth)
+	if err != nil {
+		return nil, err
+	}
+	c.raw["claudeAiOauth"] = oauth
+	return json.Marshal(c.raw)
+}
+
+// Save writes the credentials to path, readable only by the user
+func (c *Credentials) Save(path string) error {
+	data, err := c.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
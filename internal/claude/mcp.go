@@ -7,10 +7,14 @@ import (
 	"path/filepath"
 )
 
-// MCPServer represents an MCP server configuration
+// MCPServer represents an MCP server configuration. A server with a Command
+// is custom; one without uses the built-in definition of its name.
 type MCPServer struct {
-	Name    string `json:"name"`
-	Enabled bool   `json:"enabled"`
+	Name    string            `json:"name"`
+	Enabled bool              `json:"enabled"`
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
 }
 
 // MCPConfig represents Claude Code MCP configuration
@@ -63,9 +67,30 @@ func GetDefaultServers() map[string]MCPServerConfig {
 	}
 }
 
+// IsBuiltinServer reports whether name has a built-in definition
+func IsBuiltinServer(name string) bool {
+	_, ok := GetDefaultServers()[name]
+	return ok
+}
+
+// ResolveServer returns the configuration a server runs with: its own
+// command when set, otherwise the built-in definition of its name
+func ResolveServer(server MCPServer) (MCPServerConfig, error) {
+	if server.Command != "" {
+		return MCPServerConfig{
+			Command: server.Command,
+			Args:    server.Args,
+			Env:     server.Env,
+		}, nil
+	}
+	if serverConfig, ok := GetDefaultServers()[server.Name]; ok {
+		return serverConfig, nil
+	}
+	return MCPServerConfig{}, fmt.Errorf("unknown MCP server %q (custom servers need a command)", server.Name)
+}
+
 // GenerateConfig generates the MCP configuration for enabled servers
 func (m *MCPManager) GenerateConfig(enabledServers []MCPServer) (*MCPConfig, error) {
-	defaultServers := GetDefaultServers()
 	config := &MCPConfig{
 		MCPServers: make(map[string]MCPServerConfig),
 	}
@@ -75,9 +100,11 @@ func (m *MCPManager) GenerateConfig(enabledServers []MCPServer) (*MCPConfig, err
 			continue
 		}
 
-		if serverConfig, ok := defaultServers[server.Name]; ok {
-			config.MCPServers[server.Name] = serverConfig
+		serverConfig, err := ResolveServer(server)
+		if err != nil {
+			return nil, err
 		}
+		config.MCPServers[server.Name] = serverConfig
 	}
 
 	return config, nil
@@ -108,14 +135,20 @@ func (m *MCPManager) GetConfigPath() string {
 	return filepath.Join(m.configDir, "mcp-config.json")
 }
 
-// CreateContainerMCPConfig creates an MCP config file for container use
-func (m *MCPManager) CreateContainerMCPConfig(enabledServers []MCPServer) (string, error) {
+// GetContainerConfigPath returns the path for a container's MCP configuration
+func (m *MCPManager) GetContainerConfigPath(containerName string) string {
+	return filepath.Join(m.configDir, "containers", containerName+".json")
+}
+
+// CreateContainerMCPConfig creates the MCP config file for a container, so
+// containers with different servers don't share one file
+func (m *MCPManager) CreateContainerMCPConfig(containerName string, enabledServers []MCPServer) (string, error) {
 	config, err := m.GenerateConfig(enabledServers)
 	if err != nil {
 		return "", err
 	}
 
-	configPath := m.GetConfigPath()
+	configPath := m.GetContainerConfigPath(containerName)
 	if err := m.WriteConfig(config, configPath); err != nil {
 		return "", err
 	}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -22,20 +23,60 @@ type Config struct {
 	MCP           MCPConfig           `mapstructure:"mcp"`
 	Git           GitConfig           `mapstructure:"git"`
 	Logging       LoggingConfig       `mapstructure:"logging"`
+	Analytics     AnalyticsConfig     `mapstructure:"analytics"`
+	Audit         AuditConfig         `mapstructure:"audit"`
+	Serve         ServeConfig         `mapstructure:"serve"`
 }
 
 // RuntimeConfig holds container runtime settings
 type RuntimeConfig struct {
-	Preferred string        `mapstructure:"preferred"` // auto, docker, podman, orbstack
-	Timeout   time.Duration `mapstructure:"timeout"`
+	Preferred  string        `mapstructure:"preferred"` // auto, docker, podman, orbstack
+	Timeout    time.Duration `mapstructure:"timeout"`
+	DockerHost string        `mapstructure:"dockerHost"` // Pin the Docker endpoint, e.g. npipe:////./pipe/docker_engine
 }
 
 // ContainerConfig holds container settings
 type ContainerConfig struct {
-	Image          string `mapstructure:"image"`
-	BasePort       int    `mapstructure:"basePort"`
-	MaxPort        int    `mapstructure:"maxPort"`
-	WorkspaceMount string `mapstructure:"workspaceMount"`
+	Image                  string                 `mapstructure:"image"`
+	BasePort               int                    `mapstructure:"basePort"`
+	MaxPort                int                    `mapstructure:"maxPort"`
+	WorkspaceMount         string                 `mapstructure:"workspaceMount"`
+	WorkspaceMode          string                 `mapstructure:"workspaceMode"` // bind (host worktree) or volume
+	RestartPolicy          string                 `mapstructure:"restartPolicy"` // no, always, on-failure, unless-stopped
+	HealthCheck            HealthCheckConfig      `mapstructure:"healthCheck"`
+	PreStop                PreStopConfig          `mapstructure:"preStop"`
+	GPUs                   string                 `mapstructure:"gpus"`                   // all, a count, device=0,1 or none
+	ProfileGPUs            map[string]string      `mapstructure:"profileGPUs"`            // GPUs by profile, overriding gpus
+	ExtraCreateArgs        []string               `mapstructure:"extraCreateArgs"`        // docker run flags frank doesn't model, e.g. --cap-add=SYS_PTRACE
+	ProfileExtraCreateArgs map[string][]string    `mapstructure:"profileExtraCreateArgs"` // Added to extraCreateArgs by profile
+	Network                string                 `mapstructure:"network"`                // frank, an existing network, none, or empty for the default bridge
+	Hooks                  HooksConfig            `mapstructure:"hooks"`
+	ProfileHooks           map[string]HooksConfig `mapstructure:"profileHooks"` // Run after hooks, by profile
+}
+
+// HealthCheckConfig holds the health check run inside local containers.
+// An empty command keeps the image's own HEALTHCHECK.
+type HealthCheckConfig struct {
+	Command     string        `mapstructure:"command"` // Run with /bin/sh -c; exit 0 means healthy
+	Interval    time.Duration `mapstructure:"interval"`
+	Timeout     time.Duration `mapstructure:"timeout"`
+	StartPeriod time.Duration `mapstructure:"startPeriod"`
+	Retries     int           `mapstructure:"retries"`
+}
+
+// PreStopConfig holds the hook 'frank stop' runs inside a container before
+// stopping it. An empty command disables the hook.
+type PreStopConfig struct {
+	Command string        `mapstructure:"command"` // Run with /bin/sh -c
+	Timeout time.Duration `mapstructure:"timeout"` // The stop goes ahead once it passes
+}
+
+// HooksConfig holds the commands 'frank start' runs around creating a
+// container: pre-start ones on the host, post-start ones inside the container
+type HooksConfig struct {
+	PreStart  []string      `mapstructure:"preStart"`  // Run on the host with sh -c, in the workspace; a failure aborts the start
+	PostStart []string      `mapstructure:"postStart"` // Run in the container with /bin/sh -c once it has started
+	Timeout   time.Duration `mapstructure:"timeout"`   // Per command
 }
 
 // AWSConfig holds AWS settings
@@ -43,12 +84,15 @@ type AWSConfig struct {
 	DefaultProfile          string        `mapstructure:"defaultProfile"`
 	AutoLogin               bool          `mapstructure:"autoLogin"`
 	CredentialRefreshBuffer time.Duration `mapstructure:"credentialRefreshBuffer"`
+	ReadOnly                bool          `mapstructure:"readOnly"`        // Refuse commands that change AWS resources
+	PermissionCheck         bool          `mapstructure:"permissionCheck"` // Simulate IAM policies before changing AWS resources
 }
 
 // ECSConfig holds ECS deployment settings
 type ECSConfig struct {
 	Domain  string `mapstructure:"domain"`  // Domain name for ALB (e.g., frank.digitaldevops.io)
 	Cluster string `mapstructure:"cluster"` // ECS cluster name
+	Owner   string `mapstructure:"owner"`   // Owner tag for started tasks (default: from the AWS caller identity)
 }
 
 // ClaudeConfig holds Claude Code settings
@@ -58,8 +102,10 @@ type ClaudeConfig struct {
 
 // GitHubConfig holds GitHub authentication settings
 type GitHubConfig struct {
-	MountSSH      bool `mapstructure:"mountSSH"`      // Always mount ~/.ssh
-	MountGHConfig bool `mapstructure:"mountGHConfig"` // Always mount ~/.config/gh
+	MountSSH      bool     `mapstructure:"mountSSH"`      // Always mount ~/.ssh
+	MountGHConfig bool     `mapstructure:"mountGHConfig"` // Always mount ~/.config/gh
+	Reviewers     []string `mapstructure:"reviewers"`     // Reviewers requested by frank pr create
+	PRTemplate    string   `mapstructure:"prTemplate"`    // Pull request body template file
 }
 
 // NotificationConfig holds notification settings
@@ -69,6 +115,15 @@ type NotificationConfig struct {
 	Sound             bool                   `mapstructure:"sound"`
 	InactivityTimeout time.Duration          `mapstructure:"inactivityTimeout"`
 	Patterns          NotificationPatterns   `mapstructure:"patterns"`
+	Backends          []NotificationBackend  `mapstructure:"backends"`
+}
+
+// NotificationBackend is a remote notification target such as a team channel
+type NotificationBackend struct {
+	Type     string            `mapstructure:"type"`     // slack, discord, webhook
+	URL      string            `mapstructure:"url"`      // Incoming webhook URL
+	Headers  map[string]string `mapstructure:"headers"`  // Extra HTTP headers (webhook only)
+	Profiles []string          `mapstructure:"profiles"` // Profiles to notify for (empty = all)
 }
 
 // NotificationPatterns holds the patterns for detecting notifications
@@ -84,17 +139,25 @@ type MCPConfig struct {
 	Servers   []MCPServer `mapstructure:"servers"`
 }
 
-// MCPServer represents an MCP server configuration
+// MCPServer represents an MCP server configuration. Built-in servers only
+// need a name; setting Command defines a custom server or replaces the
+// built-in definition of the same name.
 type MCPServer struct {
-	Name    string `mapstructure:"name"`
-	Enabled bool   `mapstructure:"enabled"`
+	Name     string   `mapstructure:"name"`
+	Enabled  bool     `mapstructure:"enabled"`
+	Command  string   `mapstructure:"command"`
+	Args     []string `mapstructure:"args"`
+	Env      []string `mapstructure:"env"`      // KEY=VAL; a list keeps the case viper folds in map keys
+	Profiles []string `mapstructure:"profiles"` // Profiles to enable for (empty = all)
 }
 
 // GitConfig holds git settings
 type GitConfig struct {
 	WorktreeBase      string `mapstructure:"worktreeBase"`
 	CleanupOnStop     bool   `mapstructure:"cleanupOnStop"`
-	AutoCommitMessage string `mapstructure:"autoCommitMessage"`
+	AutoCommit        bool   `mapstructure:"autoCommit"`        // Commit uncommitted worktree changes on stop
+	AutoCommitMessage string `mapstructure:"autoCommitMessage"` // Supports {{container}} and {{timestamp}}
+	AutoPush          bool   `mapstructure:"autoPush"`          // Push auto-commits to frank/<container>
 }
 
 // LoggingConfig holds logging settings
@@ -102,8 +165,40 @@ type LoggingConfig struct {
 	Level   string `mapstructure:"level"`
 	Verbose bool   `mapstructure:"verbose"`
 	File    string `mapstructure:"file"`
+	Format  string `mapstructure:"format"` // text or json
+}
+
+// AnalyticsConfig holds local session analytics settings
+type AnalyticsConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`      // Record sessions to ~/.frank/analytics
+	Bucket       string        `mapstructure:"bucket"`       // S3 bucket for sync (falls back to ANALYTICS_BUCKET)
+	Region       string        `mapstructure:"region"`       // AWS region of the bucket
+	AutoSync     bool          `mapstructure:"autoSync"`     // Sync in the background after recording
+	SyncInterval time.Duration `mapstructure:"syncInterval"` // Minimum time between background syncs
+}
+
+// AuditConfig holds settings for the log of AWS changes made by frank
+type AuditConfig struct {
+	Enabled            bool   `mapstructure:"enabled"`            // Record AWS mutations to audit.jsonl
+	CloudWatchLogGroup string `mapstructure:"cloudWatchLogGroup"` // Also send entries to this log group
+}
+
+// ServeConfig holds settings for the 'frank serve' control API
+type ServeConfig struct {
+	Addr  string `mapstructure:"addr"`  // Listen address
+	Token string `mapstructure:"token"` // Bearer token clients must send (FRANK_SERVE_TOKEN)
 }
 
+// DefaultHealthCheckCommand checks the web view, the Claude terminal and the
+// status server, so a crash of any of them marks the container unhealthy
+const DefaultHealthCheckCommand = "curl -fsS -o /dev/null http://localhost:7680/ && " +
+	"curl -fsS -o /dev/null http://localhost:7681/ && " +
+	"curl -fsS -o /dev/null http://localhost:7683/health"
+
+// DefaultPreStopCommand asks Claude to write a checkpoint of its session and
+// flushes the container's filesystems
+const DefaultPreStopCommand = "/usr/local/bin/pre-stop.sh"
+
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
 	home, _ := os.UserHomeDir()
@@ -119,11 +214,28 @@ func DefaultConfig() *Config {
 			BasePort:       8080,
 			MaxPort:        8180,
 			WorkspaceMount: "/workspace",
+			WorkspaceMode:  "bind",
+			RestartPolicy:  "unless-stopped",
+			HealthCheck: HealthCheckConfig{
+				Command:     DefaultHealthCheckCommand,
+				Interval:    30 * time.Second,
+				Timeout:     10 * time.Second,
+				StartPeriod: 15 * time.Second,
+				Retries:     3,
+			},
+			PreStop: PreStopConfig{
+				Command: DefaultPreStopCommand,
+				Timeout: 60 * time.Second,
+			},
+			Hooks: HooksConfig{
+				Timeout: 5 * time.Minute,
+			},
 		},
 		AWS: AWSConfig{
 			DefaultProfile:          "",
 			AutoLogin:               true,
 			CredentialRefreshBuffer: 5 * time.Minute,
+			PermissionCheck:         true,
 		},
 		ECS: ECSConfig{
 			Domain:  "frank.digitaldevops.io",
@@ -178,12 +290,26 @@ func DefaultConfig() *Config {
 		Git: GitConfig{
 			WorktreeBase:      filepath.Join(home, ".frank", "worktrees"),
 			CleanupOnStop:     true,
-			AutoCommitMessage: "WIP: Auto-save before container stop",
+			AutoCommit:        true,
+			AutoCommitMessage: "WIP: Auto-save {{container}} before stop ({{timestamp}})",
 		},
 		Logging: LoggingConfig{
 			Level:   "info",
 			Verbose: false,
 			File:    "",
+			Format:  "text",
+		},
+		Analytics: AnalyticsConfig{
+			Enabled:      true,
+			Region:       "us-east-1",
+			AutoSync:     true,
+			SyncInterval: time.Hour,
+		},
+		Audit: AuditConfig{
+			Enabled: true,
+		},
+		Serve: ServeConfig{
+			Addr: "127.0.0.1:7070",
 		},
 	}
 }
@@ -205,6 +331,7 @@ func Load(cfgFile string) (*Config, error) {
 
 	// Environment variables
 	viper.SetEnvPrefix("FRANK")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
 
 	// Read config file (ignore if not found)
@@ -249,18 +376,36 @@ func setDefaults(cfg *Config) {
 	viper.SetDefault("version", cfg.Version)
 	viper.SetDefault("runtime.preferred", cfg.Runtime.Preferred)
 	viper.SetDefault("runtime.timeout", cfg.Runtime.Timeout)
+	viper.SetDefault("runtime.dockerHost", cfg.Runtime.DockerHost)
 	viper.SetDefault("container.image", cfg.Container.Image)
 	viper.SetDefault("container.basePort", cfg.Container.BasePort)
 	viper.SetDefault("container.maxPort", cfg.Container.MaxPort)
 	viper.SetDefault("container.workspaceMount", cfg.Container.WorkspaceMount)
+	viper.SetDefault("container.workspaceMode", cfg.Container.WorkspaceMode)
+	viper.SetDefault("container.restartPolicy", cfg.Container.RestartPolicy)
+	viper.SetDefault("container.gpus", cfg.Container.GPUs)
+	viper.SetDefault("container.network", cfg.Container.Network)
+	viper.SetDefault("container.healthCheck.command", cfg.Container.HealthCheck.Command)
+	viper.SetDefault("container.healthCheck.interval", cfg.Container.HealthCheck.Interval)
+	viper.SetDefault("container.healthCheck.timeout", cfg.Container.HealthCheck.Timeout)
+	viper.SetDefault("container.healthCheck.startPeriod", cfg.Container.HealthCheck.StartPeriod)
+	viper.SetDefault("container.healthCheck.retries", cfg.Container.HealthCheck.Retries)
+	viper.SetDefault("container.preStop.command", cfg.Container.PreStop.Command)
+	viper.SetDefault("container.preStop.timeout", cfg.Container.PreStop.Timeout)
+	viper.SetDefault("container.hooks.timeout", cfg.Container.Hooks.Timeout)
 	viper.SetDefault("aws.defaultProfile", cfg.AWS.DefaultProfile)
 	viper.SetDefault("aws.autoLogin", cfg.AWS.AutoLogin)
 	viper.SetDefault("aws.credentialRefreshBuffer", cfg.AWS.CredentialRefreshBuffer)
+	viper.SetDefault("aws.readOnly", cfg.AWS.ReadOnly)
+	viper.SetDefault("aws.permissionCheck", cfg.AWS.PermissionCheck)
 	viper.SetDefault("ecs.domain", cfg.ECS.Domain)
 	viper.SetDefault("ecs.cluster", cfg.ECS.Cluster)
+	viper.SetDefault("ecs.owner", cfg.ECS.Owner)
 	viper.SetDefault("claude.tokenEnvVar", cfg.Claude.TokenEnvVar)
 	viper.SetDefault("github.mountSSH", cfg.GitHub.MountSSH)
 	viper.SetDefault("github.mountGHConfig", cfg.GitHub.MountGHConfig)
+	viper.SetDefault("github.reviewers", cfg.GitHub.Reviewers)
+	viper.SetDefault("github.prTemplate", cfg.GitHub.PRTemplate)
 	viper.SetDefault("notifications.enabled", cfg.Notifications.Enabled)
 	viper.SetDefault("notifications.cooldown", cfg.Notifications.Cooldown)
 	viper.SetDefault("notifications.sound", cfg.Notifications.Sound)
@@ -272,8 +417,20 @@ func setDefaults(cfg *Config) {
 	viper.SetDefault("mcp.servers", cfg.MCP.Servers)
 	viper.SetDefault("git.worktreeBase", cfg.Git.WorktreeBase)
 	viper.SetDefault("git.cleanupOnStop", cfg.Git.CleanupOnStop)
+	viper.SetDefault("git.autoCommit", cfg.Git.AutoCommit)
 	viper.SetDefault("git.autoCommitMessage", cfg.Git.AutoCommitMessage)
+	viper.SetDefault("git.autoPush", cfg.Git.AutoPush)
 	viper.SetDefault("logging.level", cfg.Logging.Level)
 	viper.SetDefault("logging.verbose", cfg.Logging.Verbose)
 	viper.SetDefault("logging.file", cfg.Logging.File)
+	viper.SetDefault("logging.format", cfg.Logging.Format)
+	viper.SetDefault("analytics.enabled", cfg.Analytics.Enabled)
+	viper.SetDefault("analytics.bucket", cfg.Analytics.Bucket)
+	viper.SetDefault("analytics.region", cfg.Analytics.Region)
+	viper.SetDefault("analytics.autoSync", cfg.Analytics.AutoSync)
+	viper.SetDefault("analytics.syncInterval", cfg.Analytics.SyncInterval)
+	viper.SetDefault("audit.enabled", cfg.Audit.Enabled)
+	viper.SetDefault("audit.cloudWatchLogGroup", cfg.Audit.CloudWatchLogGroup)
+	viper.SetDefault("serve.addr", cfg.Serve.Addr)
+	viper.SetDefault("serve.token", cfg.Serve.Token)
 }
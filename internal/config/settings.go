@@ -0,0 +1,261 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// Source identifies where an effective configuration value came from
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// Setting is a single flattened configuration value with its provenance
+type Setting struct {
+	Key    string
+	Value  interface{}
+	Source Source
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Settings flattens the configuration into dotted keys (e.g. container.basePort)
+// in declaration order. flagKeys marks keys that were set by command-line flags.
+func Settings(cfg *Config, flagKeys map[string]bool) []Setting {
+	var settings []Setting
+	walkFields(reflect.ValueOf(cfg).Elem(), "", func(key string, v reflect.Value) {
+		settings = append(settings, Setting{
+			Key:    key,
+			Value:  v.Interface(),
+			Source: sourceOf(key, flagKeys),
+		})
+	})
+	return settings
+}
+
+// Get returns the effective value for a dotted key (case-insensitive)
+func Get(cfg *Config, key string) (interface{}, error) {
+	for _, s := range Settings(cfg, nil) {
+		if strings.EqualFold(s.Key, key) {
+			return s.Value, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown config key %q", key)
+}
+
+// EnvVarName returns the environment variable that overrides a dotted key
+func EnvVarName(key string) string {
+	return "FRANK_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// FilePath returns the config file in use, or the default location if none was loaded
+func FilePath(cfgFile string) string {
+	if cfgFile != "" {
+		return cfgFile
+	}
+	if used := viper.ConfigFileUsed(); used != "" {
+		return used
+	}
+	return filepath.Join(getConfigDir(), "config.yaml")
+}
+
+// SetValue parses raw according to the key's type and writes it to the config
+// file, preserving the other values already in the file. It returns the
+// canonical key that was written.
+func SetValue(path, key, raw string) (string, error) {
+	canonical, typ, err := lookupField(key)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := parseValue(typ, raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid value for %s: %w", canonical, err)
+	}
+
+	if err := writeValue(path, canonical, value); err != nil {
+		return "", err
+	}
+	return canonical, nil
+}
+
+// SetMCPServers replaces mcp.servers in the config file, preserving the other
+// values already in the file
+func SetMCPServers(path string, servers []MCPServer) error {
+	list := make([]map[string]interface{}, 0, len(servers))
+	for _, s := range servers {
+		entry := map[string]interface{}{
+			"name":    s.Name,
+			"enabled": s.Enabled,
+		}
+		if s.Command != "" {
+			entry["command"] = s.Command
+		}
+		if len(s.Args) > 0 {
+			entry["args"] = s.Args
+		}
+		if len(s.Env) > 0 {
+			entry["env"] = s.Env
+		}
+		if len(s.Profiles) > 0 {
+			entry["profiles"] = s.Profiles
+		}
+		list = append(list, entry)
+	}
+	return writeValue(path, "mcp.servers", list)
+}
+
+// writeValue sets a dotted key in the config file to value
+func writeValue(path, key string, value interface{}) error {
+	doc := make(map[string]interface{})
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if len(data) > 0 {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+
+	setNested(doc, strings.Split(key, "."), value)
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, out.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// sourceOf determines the provenance of a key using viper's loaded state
+func sourceOf(key string, flagKeys map[string]bool) Source {
+	if flagKeys[key] {
+		return SourceFlag
+	}
+	if _, ok := os.LookupEnv(EnvVarName(key)); ok {
+		return SourceEnv
+	}
+	if viper.InConfig(strings.ToLower(key)) {
+		return SourceFile
+	}
+	return SourceDefault
+}
+
+// walkFields visits every leaf field of a config struct using mapstructure tags
+func walkFields(v reflect.Value, prefix string, visit func(key string, v reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("mapstructure")
+		if tag == "" {
+			continue
+		}
+		key := tag
+		if prefix != "" {
+			key = prefix + "." + tag
+		}
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct && field.Type != durationType {
+			walkFields(fv, key, visit)
+			continue
+		}
+		visit(key, fv)
+	}
+}
+
+// lookupField resolves a case-insensitive dotted key to its canonical form and type
+func lookupField(key string) (string, reflect.Type, error) {
+	var (
+		canonical string
+		typ       reflect.Type
+	)
+	walkFields(reflect.ValueOf(&Config{}).Elem(), "", func(k string, v reflect.Value) {
+		if strings.EqualFold(k, key) {
+			canonical = k
+			typ = v.Type()
+		}
+	})
+	if canonical == "" {
+		return "", nil, fmt.Errorf("unknown config key %q", key)
+	}
+	return canonical, typ, nil
+}
+
+// parseValue converts a command-line string into a value suitable for the YAML file
+func parseValue(typ reflect.Type, raw string) (interface{}, error) {
+	if typ == durationType {
+		if _, err := time.ParseDuration(raw); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return raw, nil
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return strconv.Atoi(raw)
+	case reflect.Bool:
+		return strconv.ParseBool(raw)
+	case reflect.Slice:
+		if typ.Elem().Kind() != reflect.String {
+			return nil, fmt.Errorf("list of %s cannot be set from the command line; edit the config file", typ.Elem().Name())
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", typ)
+	}
+}
+
+// setNested sets a value in a nested map, matching existing keys case-insensitively
+// so hand-written files keep their original key spelling
+func setNested(doc map[string]interface{}, path []string, value interface{}) {
+	key := path[0]
+	for existing := range doc {
+		if strings.EqualFold(existing, key) {
+			key = existing
+			break
+		}
+	}
+
+	if len(path) == 1 {
+		doc[key] = value
+		return
+	}
+
+	child, ok := doc[key].(map[string]interface{})
+	if !ok {
+		child = make(map[string]interface{})
+		doc[key] = child
+	}
+	setNested(child, path[1:], value)
+}
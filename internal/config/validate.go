@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidRuntimes lists the accepted values for runtime.preferred
+var ValidRuntimes = []string{"auto", "docker", "podman", "orbstack"}
+
+// ValidDockerHostSchemes lists the accepted URL schemes for runtime.dockerHost
+var ValidDockerHostSchemes = []string{"unix", "npipe", "tcp", "ssh"}
+
+// ValidLogLevels lists the accepted values for logging.level
+var ValidLogLevels = []string{"debug", "info", "warn", "error"}
+
+// ValidNotificationBackends lists the accepted values for notifications.backends[].type
+var ValidNotificationBackends = []string{"slack", "discord", "webhook"}
+
+// ValidLogFormats lists the accepted values for logging.format
+var ValidLogFormats = []string{"text", "json"}
+
+// ValidationError describes a single invalid configuration value
+type ValidationError struct {
+	Key     string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Key, e.Message)
+}
+
+// Validate checks the configuration for values that would break frank at runtime.
+// It returns every problem found rather than stopping at the first.
+func Validate(cfg *Config) []ValidationError {
+	var errs []ValidationError
+	add := func(key, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if !contains(ValidRuntimes, cfg.Runtime.Preferred) {
+		add("runtime.preferred", "invalid runtime %q (valid: %s)", cfg.Runtime.Preferred, strings.Join(ValidRuntimes, ", "))
+	}
+	if cfg.Runtime.Timeout < 0 {
+		add("runtime.timeout", "must not be negative")
+	}
+	if host := cfg.Runtime.DockerHost; host != "" {
+		scheme, _, found := strings.Cut(host, "://")
+		if !found || !contains(ValidDockerHostSchemes, scheme) {
+			add("runtime.dockerHost", "invalid docker host %q (use unix://, npipe://, tcp:// or ssh://)", host)
+		}
+	}
+
+	if cfg.Container.Image == "" {
+		add("container.image", "must not be empty")
+	}
+	if cfg.Container.BasePort < 1 || cfg.Container.BasePort > 65535 {
+		add("container.basePort", "%d is outside the valid port range 1-65535", cfg.Container.BasePort)
+	}
+	if cfg.Container.MaxPort < 1 || cfg.Container.MaxPort > 65535 {
+		add("container.maxPort", "%d is outside the valid port range 1-65535", cfg.Container.MaxPort)
+	}
+	if cfg.Container.BasePort >= cfg.Container.MaxPort {
+		add("container.maxPort", "must be greater than basePort (%d >= %d)", cfg.Container.BasePort, cfg.Container.MaxPort)
+	} else if cfg.Container.MaxPort-cfg.Container.BasePort < 3 {
+		// Each container needs 4 consecutive ports (web, claude, bash, status)
+		add("container.maxPort", "port range %d-%d is too small for a single container (needs 4 ports)", cfg.Container.BasePort, cfg.Container.MaxPort)
+	}
+	if cfg.Container.WorkspaceMount == "" || !strings.HasPrefix(cfg.Container.WorkspaceMount, "/") {
+		add("container.workspaceMount", "must be an absolute container path, got %q", cfg.Container.WorkspaceMount)
+	}
+	if cfg.Container.Network == "host" {
+		add("container.network", "host networking isn't supported: frank containers publish the same container ports")
+	}
+	if cfg.Container.PreStop.Timeout < 0 {
+		add("container.preStop.timeout", "must not be negative")
+	}
+	if cfg.Container.Hooks.Timeout < 0 {
+		add("container.hooks.timeout", "must not be negative")
+	}
+	for name, hooks := range cfg.Container.ProfileHooks {
+		if hooks.Timeout < 0 {
+			add("container.profileHooks."+name+".timeout", "must not be negative")
+		}
+	}
+
+	if cfg.AWS.CredentialRefreshBuffer < 0 {
+		add("aws.credentialRefreshBuffer", "must not be negative")
+	}
+
+	if cfg.ECS.Cluster == "" {
+		add("ecs.cluster", "must not be empty")
+	}
+
+	if cfg.Notifications.Cooldown < 0 {
+		add("notifications.cooldown", "must not be negative")
+	}
+	if cfg.Notifications.InactivityTimeout < 0 {
+		add("notifications.inactivityTimeout", "must not be negative")
+	}
+	for i, b := range cfg.Notifications.Backends {
+		if !contains(ValidNotificationBackends, b.Type) {
+			add(fmt.Sprintf("notifications.backends[%d].type", i), "invalid backend %q (valid: %s)", b.Type, strings.Join(ValidNotificationBackends, ", "))
+		}
+		if !strings.HasPrefix(b.URL, "https://") && !strings.HasPrefix(b.URL, "http://") {
+			add(fmt.Sprintf("notifications.backends[%d].url", i), "must be an http(s) URL, got %q", b.URL)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, s := range cfg.MCP.Servers {
+		if s.Name == "" {
+			add(fmt.Sprintf("mcp.servers[%d].name", i), "must not be empty")
+			continue
+		}
+		if seen[s.Name] {
+			add(fmt.Sprintf("mcp.servers[%d].name", i), "duplicate server %q", s.Name)
+		}
+		seen[s.Name] = true
+		if s.Command == "" && (len(s.Args) > 0 || len(s.Env) > 0) {
+			add(fmt.Sprintf("mcp.servers[%d].command", i), "must be set when args or env are given")
+		}
+		for _, kv := range s.Env {
+			if name, _, found := strings.Cut(kv, "="); !found || name == "" {
+				add(fmt.Sprintf("mcp.servers[%d].env", i), "invalid entry %q (expected KEY=VAL)", kv)
+			}
+		}
+	}
+
+	if !contains(ValidLogLevels, strings.ToLower(cfg.Logging.Level)) {
+		add("logging.level", "invalid level %q (valid: %s)", cfg.Logging.Level, strings.Join(ValidLogLevels, ", "))
+	}
+	if !contains(ValidLogFormats, cfg.Logging.Format) {
+		add("logging.format", "invalid format %q (valid: %s)", cfg.Logging.Format, strings.Join(ValidLogFormats, ", "))
+	}
+
+	if cfg.Analytics.SyncInterval < 0 {
+		add("analytics.syncInterval", "must not be negative")
+	}
+
+	return errs
+}
+
+// contains reports whether value is in list
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
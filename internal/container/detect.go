@@ -1,70 +1,269 @@
 package container
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"strings"
 )
 
-// DetectRuntime detects and returns the best available container runtime
-func DetectRuntime(preferred string) (Runtime, error) {
-	// If a specific runtime is preferred, try that first
+// DetectAttempt records one runtime candidate tried during detection
+type DetectAttempt struct {
+	Runtime  string
+	Endpoint string // Docker or podman API address; empty for CLI-based runtimes
+	Err      error  // Why the candidate was rejected, nil if it was used
+}
+
+func (a DetectAttempt) String() string {
+	name := a.Runtime
+	if a.Endpoint != "" {
+		name += " at " + a.Endpoint
+	}
+	if a.Err != nil {
+		return fmt.Sprintf("%s: %v", name, a.Err)
+	}
+	return name + ": ok"
+}
+
+// DetectError is returned when no runtime answered. It lists every
+// candidate tried and unwraps to ErrNoRuntimeFound.
+type DetectError struct {
+	Attempts []DetectAttempt
+}
+
+func (e *DetectError) Error() string {
+	tried := make([]string, len(e.Attempts))
+	for i, a := range e.Attempts {
+		tried[i] = a.String()
+	}
+	return "no container runtime found (tried " + strings.Join(tried, "; ") + ")"
+}
+
+func (e *DetectError) Unwrap() error {
+	return ErrNoRuntimeFound
+}
+
+// DetectRuntime detects and returns the best available container runtime.
+// dockerHost pins the Docker endpoint; when empty, DOCKER_HOST and the
+// platform's usual sockets and named pipes are probed.
+func DetectRuntime(preferred, dockerHost string) (Runtime, error) {
+	d := &detector{dockerHost: dockerHost}
+
+	// If a specific runtime is preferred, only try that
+	var candidates []func() Runtime
 	switch preferred {
 	case "docker":
-		return tryDocker()
+		candidates = []func() Runtime{d.docker}
 	case "podman":
-		return tryPodman()
+		candidates = []func() Runtime{d.podman}
 	case "orbstack":
-		return tryOrbStack()
+		candidates = []func() Runtime{d.orbstack}
+	default:
+		// Auto-detect: on macOS, prefer OrbStack if available
+		if runtime.GOOS == "darwin" {
+			candidates = []func() Runtime{d.orbstack, d.docker, d.podman}
+		} else {
+			candidates = []func() Runtime{d.docker, d.podman, d.orbstack}
+		}
 	}
 
-	// Auto-detect: try runtimes in order of preference
-	// On macOS, prefer OrbStack if available
-	if runtime.GOOS == "darwin" {
-		if r, err := tryOrbStack(); err == nil && r.IsAvailable() {
+	for _, try := range candidates {
+		if r := try(); r != nil {
 			return r, nil
 		}
 	}
+	return nil, &DetectError{Attempts: d.attempts}
+}
 
-	// Try Docker
-	if r, err := tryDocker(); err == nil && r.IsAvailable() {
-		return r, nil
+// RuntimeEndpoint returns the daemon address of a Docker-based runtime, or
+// an empty string for CLI-based ones
+func RuntimeEndpoint(r Runtime) string {
+	if e, ok := r.(interface{ Endpoint() string }); ok {
+		return e.Endpoint()
 	}
+	return ""
+}
+
+// detector tries runtime candidates and records the outcome of each
+type detector struct {
+	dockerHost string
+	attempts   []DetectAttempt
+}
 
-	// Try Podman
-	if r, err := tryPodman(); err == nil && r.IsAvailable() {
-		return r, nil
+func (d *detector) record(name, endpoint string, err error) {
+	d.attempts = append(d.attempts, DetectAttempt{Runtime: name, Endpoint: endpoint, Err: err})
+}
+
+func (d *detector) docker() Runtime {
+	for _, endpoint := range d.dockerEndpoints() {
+		if err := socketExists(endpoint); err != nil {
+			d.record("docker", endpoint, err)
+			continue
+		}
+		r, err := NewDockerRuntimeWithHost(endpoint)
+		if err != nil {
+			d.record("docker", endpoint, err)
+			continue
+		}
+		if !r.IsAvailable() {
+			d.record("docker", r.Endpoint(), errors.New("daemon not responding"))
+			continue
+		}
+		d.record("docker", r.Endpoint(), nil)
+		return r
 	}
+	return nil
+}
 
-	// Try OrbStack as fallback on any platform
-	if r, err := tryOrbStack(); err == nil && r.IsAvailable() {
-		return r, nil
+// dockerEndpoints lists the Docker daemon addresses to probe, most specific first
+func (d *detector) dockerEndpoints() []string {
+	if d.dockerHost != "" {
+		return []string{d.dockerHost}
+	}
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		return []string{host}
 	}
 
-	return nil, ErrNoRuntimeFound
-}
+	if runtime.GOOS == "windows" {
+		return []string{
+			"npipe:////./pipe/docker_engine",
+			// Docker Desktop 4.x also serves its Linux engine here
+			"npipe:////./pipe/dockerDesktopLinuxEngine",
+		}
+	}
 
-func tryDocker() (Runtime, error) {
-	return NewDockerRuntime()
+	endpoints := []string{"unix:///var/run/docker.sock"}
+	if home, err := os.UserHomeDir(); err == nil {
+		endpoints = append(endpoints,
+			"unix://"+filepath.Join(home, ".docker", "run", "docker.sock"),     // Docker Desktop on macOS
+			"unix://"+filepath.Join(home, ".docker", "desktop", "docker.sock"), // Docker Desktop on Linux
+		)
+	}
+	if xdg := os.Getenv("XDG_RUNTIME_DIR"); xdg != "" {
+		endpoints = append(endpoints, "unix://"+filepath.Join(xdg, "docker.sock")) // Rootless Docker
+	}
+	if isWSL() {
+		// Docker Desktop's WSL integration mounts its socket at /var/run;
+		// a shared daemon in another distro commonly lives here
+		endpoints = append(endpoints, "unix:///mnt/wsl/shared-docker/docker.sock")
+	}
+	return endpoints
 }
 
-func tryPodman() (Runtime, error) {
-	// Check if podman command exists
+func (d *detector) podman() Runtime {
 	if _, err := exec.LookPath("podman"); err != nil {
-		return nil, err
+		d.record("podman", "", errors.New("podman not installed"))
+		return nil
+	}
+
+	r, err := NewPodmanRuntime()
+	if err != nil {
+		d.record("podman", "", err)
+		return nil
+	}
+	if r.IsAvailable() {
+		// The API socket is faster than the CLI but optional; without it
+		// every call goes through the podman binary
+		r.UseAPI()
+		d.record("podman", r.Endpoint(), nil)
+		return r
+	}
+
+	// On macOS and Windows podman runs in a VM that has to be started
+	err = errors.New("podman not responding")
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		if machineErr := podmanMachineStatus(); machineErr != nil {
+			err = machineErr
+		}
 	}
-	return NewPodmanRuntime()
+	d.record("podman", "", err)
+	return nil
 }
 
-func tryOrbStack() (Runtime, error) {
-	// Check if orbctl command exists (OrbStack CLI)
-	if _, err := exec.LookPath("orbctl"); err != nil {
-		// Also check for docker symlink from OrbStack
+func (d *detector) orbstack() Runtime {
+	_, orbctlErr := exec.LookPath("orbctl")
+	if orbctlErr != nil {
+		// Could be OrbStack's docker symlink, detected by IsAvailable()
 		if _, err := exec.LookPath("docker"); err != nil {
-			return nil, err
+			d.record("orbstack", "", errors.New("orbctl and docker not installed"))
+			return nil
+		}
+	}
+
+	// Prefer OrbStack's own socket so a different DOCKER_HOST doesn't hide it
+	endpoint := d.dockerHost
+	if endpoint == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			socket := "unix://" + filepath.Join(home, ".orbstack", "run", "docker.sock")
+			if socketExists(socket) == nil {
+				endpoint = socket
+			}
+		}
+	}
+
+	r, err := NewOrbStackRuntimeWithHost(endpoint)
+	if err != nil {
+		d.record("orbstack", endpoint, err)
+		return nil
+	}
+	if !r.IsAvailable() {
+		d.record("orbstack", endpoint, errors.New("OrbStack not running"))
+		return nil
+	}
+	d.record("orbstack", r.Endpoint(), nil)
+	return r
+}
+
+// socketExists rejects unix socket endpoints whose file is missing, so
+// detection doesn't wait on a connection that can't succeed
+func socketExists(endpoint string) error {
+	path, ok := strings.CutPrefix(endpoint, "unix://")
+	if !ok {
+		return nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return errors.New("socket not found")
+	}
+	return nil
+}
+
+// podmanMachineStatus explains why podman's VM isn't usable, or returns nil
+// if a machine is running
+func podmanMachineStatus() error {
+	output, err := exec.Command("podman", "machine", "list", "--format", "json").Output()
+	if err != nil {
+		return nil
+	}
+
+	var machines []struct {
+		Name    string `json:"Name"`
+		Running bool   `json:"Running"`
+	}
+	if json.Unmarshal(output, &machines) != nil {
+		return nil
+	}
+	if len(machines) == 0 {
+		return errors.New("no podman machine (run: podman machine init)")
+	}
+	for _, m := range machines {
+		if m.Running {
+			return nil
 		}
-		// Could be OrbStack's docker, will be detected by IsAvailable()
 	}
-	return NewOrbStackRuntime()
+	return fmt.Errorf("podman machine %s is stopped (run: podman machine start)", strings.TrimSuffix(machines[0].Name, "*"))
+}
+
+// isWSL reports whether frank runs inside Windows Subsystem for Linux
+func isWSL() bool {
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	data, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	return err == nil && strings.Contains(strings.ToLower(string(data)), "microsoft")
 }
 
 // RuntimeError represents a container runtime error
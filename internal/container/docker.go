@@ -4,18 +4,25 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/barff/frank/internal/archive"
 	"github.com/docker/docker/api/types"
 	containerTypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/go-connections/nat"
 )
 
@@ -26,13 +33,29 @@ type DockerRuntime struct {
 
 // NewDockerRuntime creates a new Docker runtime
 func NewDockerRuntime() (*DockerRuntime, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	return NewDockerRuntimeWithHost("")
+}
+
+// NewDockerRuntimeWithHost creates a Docker runtime for a specific daemon
+// endpoint (unix://, npipe://, tcp:// or ssh://). An empty host uses
+// DOCKER_HOST or the platform default.
+func NewDockerRuntimeWithHost(host string) (*DockerRuntime, error) {
+	opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
+	if host != "" {
+		opts = append(opts, client.WithHost(host))
+	}
+	cli, err := client.NewClientWithOpts(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create docker client: %w", err)
 	}
 	return &DockerRuntime{client: cli}, nil
 }
 
+// Endpoint returns the daemon address the runtime talks to
+func (d *DockerRuntime) Endpoint() string {
+	return d.client.DaemonHost()
+}
+
 // Name returns the runtime name
 func (d *DockerRuntime) Name() string {
 	return "docker"
@@ -50,6 +73,11 @@ func (d *DockerRuntime) IsAvailable() bool {
 func (d *DockerRuntime) CreateContainer(opts ContainerOptions) (string, error) {
 	ctx := context.Background()
 
+	networkName, err := extraNetwork(opts)
+	if err != nil {
+		return "", err
+	}
+
 	// Build port bindings
 	exposedPorts := nat.PortSet{}
 	portBindings := nat.PortMap{}
@@ -72,12 +100,16 @@ func (d *DockerRuntime) CreateContainer(opts ContainerOptions) (string, error) {
 	// Build mounts
 	var mounts []mount.Mount
 	for _, v := range opts.Volumes {
-		mounts = append(mounts, mount.Mount{
+		m := mount.Mount{
 			Type:     mount.TypeBind,
 			Source:   v.HostPath,
 			Target:   v.ContainerPath,
 			ReadOnly: v.ReadOnly,
-		})
+		}
+		if v.Volume != "" {
+			m.Type, m.Source = mount.TypeVolume, v.Volume
+		}
+		mounts = append(mounts, m)
 	}
 
 	// Container config
@@ -87,7 +119,7 @@ func (d *DockerRuntime) CreateContainer(opts ContainerOptions) (string, error) {
 		WorkingDir:   opts.WorkDir,
 		Cmd:          opts.Cmd,
 		Entrypoint:   opts.Entrypoint,
-		Labels:       opts.Labels,
+		Labels:       extraArgsLabels(opts),
 		ExposedPorts: exposedPorts,
 		Tty:          opts.TTY,
 		OpenStdin:    opts.OpenStdin,
@@ -100,11 +132,64 @@ func (d *DockerRuntime) CreateContainer(opts ContainerOptions) (string, error) {
 		AutoRemove:   opts.AutoRemove,
 	}
 
-	resp, err := d.client.ContainerCreate(ctx, containerConfig, hostConfig, nil, nil, opts.Name)
+	if hc := opts.HealthCheck; hc != nil {
+		containerConfig.Healthcheck = &containerTypes.HealthConfig{
+			Test:        healthTest(hc),
+			Interval:    hc.Interval,
+			Timeout:     hc.Timeout,
+			StartPeriod: hc.StartPeriod,
+			Retries:     hc.Retries,
+		}
+	}
+	if opts.RestartPolicy != "" && !opts.AutoRemove {
+		hostConfig.RestartPolicy = containerTypes.RestartPolicy{
+			Name: containerTypes.RestartPolicyMode(opts.RestartPolicy),
+		}
+	}
+	for _, req := range opts.DeviceRequests {
+		dr := containerTypes.DeviceRequest{
+			Driver:    req.Driver,
+			Count:     req.Count,
+			DeviceIDs: req.DeviceIDs,
+		}
+		if len(req.Capabilities) > 0 {
+			dr.Capabilities = [][]string{req.Capabilities}
+		}
+		hostConfig.DeviceRequests = append(hostConfig.DeviceRequests, dr)
+	}
+	for _, a := range opts.ExtraArgs {
+		if err := applyExtraArg(hostConfig, a); err != nil {
+			return "", err
+		}
+	}
+
+	// Attach to a user-defined network so sidecars resolve by name
+	var networkingConfig *network.NetworkingConfig
+	if networkName != "" {
+		hostConfig.NetworkMode = containerTypes.NetworkMode(networkName)
+		if hostConfig.NetworkMode.IsUserDefined() {
+			networkingConfig = &network.NetworkingConfig{
+				EndpointsConfig: map[string]*network.EndpointSettings{
+					networkName: {Aliases: opts.Aliases},
+				},
+			}
+		}
+	}
+
+	resp, err := d.client.ContainerCreate(ctx, containerConfig, hostConfig, networkingConfig, nil, opts.Name)
 	if err != nil {
 		return "", fmt.Errorf("failed to create container: %w", err)
 	}
 
+	// Older daemons take a single network at create, so the rest are joined
+	// afterwards
+	for _, name := range opts.Networks {
+		if err := d.client.NetworkConnect(ctx, name, resp.ID, nil); err != nil {
+			d.client.ContainerRemove(ctx, resp.ID, containerTypes.RemoveOptions{Force: true})
+			return "", fmt.Errorf("failed to join network %s: %w", name, err)
+		}
+	}
+
 	return resp.ID, nil
 }
 
@@ -171,6 +256,7 @@ func (d *DockerRuntime) ListContainers(filter ContainerFilter) ([]Container, err
 			Created: time.Unix(c.Created, 0),
 			Ports:   ports,
 			Labels:  c.Labels,
+			Health:  healthFromStatus(c.Status),
 		})
 	}
 
@@ -203,6 +289,11 @@ func (d *DockerRuntime) GetContainer(idOrName string) (*Container, error) {
 
 	created, _ := time.Parse(time.RFC3339, json.Created)
 
+	var health string
+	if json.State.Health != nil {
+		health = json.State.Health.Status
+	}
+
 	return &Container{
 		ID:      json.ID[:12],
 		Name:    name,
@@ -211,9 +302,266 @@ func (d *DockerRuntime) GetContainer(idOrName string) (*Container, error) {
 		Created: created,
 		Ports:   ports,
 		Labels:  json.Config.Labels,
+		Health:  health,
 	}, nil
 }
 
+// InspectContainer returns the options a container was created with
+func (d *DockerRuntime) InspectContainer(idOrName string) (*ContainerOptions, error) {
+	ctx := context.Background()
+
+	info, err := d.client.ContainerInspect(ctx, idOrName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	opts := &ContainerOptions{
+		Name:       strings.TrimPrefix(info.Name, "/"),
+		Image:      info.Config.Image,
+		Env:        info.Config.Env,
+		WorkDir:    info.Config.WorkingDir,
+		Cmd:        info.Config.Cmd,
+		Entrypoint: info.Config.Entrypoint,
+		Labels:     info.Config.Labels,
+		AutoRemove: info.HostConfig.AutoRemove,
+		TTY:        info.Config.Tty,
+		OpenStdin:  info.Config.OpenStdin,
+
+		HealthCheck:   dockerHealthCheck(info.Config.Healthcheck),
+		RestartPolicy: restartPolicyName(string(info.HostConfig.RestartPolicy.Name)),
+	}
+
+	for _, dr := range info.HostConfig.DeviceRequests {
+		req := DeviceRequest{Driver: dr.Driver, Count: dr.Count, DeviceIDs: dr.DeviceIDs}
+		if len(dr.Capabilities) > 0 {
+			req.Capabilities = dr.Capabilities[0]
+		}
+		opts.DeviceRequests = append(opts.DeviceRequests, req)
+	}
+
+	if mode := info.HostConfig.NetworkMode; mode.IsUserDefined() || mode.IsNone() {
+		opts.Network = string(mode)
+		if endpoint, ok := info.NetworkSettings.Networks[opts.Network]; ok {
+			opts.Aliases = userAliases(endpoint.Aliases, opts.Name, info.ID)
+		}
+	}
+	if info.NetworkSettings != nil {
+		var names []string
+		for name := range info.NetworkSettings.Networks {
+			names = append(names, name)
+		}
+		opts.Networks = extraNetworks(names, opts.Network)
+	}
+
+	// Use the configured bindings rather than NetworkSettings, which is empty
+	// once the container has stopped
+	for containerPort, bindings := range info.HostConfig.PortBindings {
+		for _, binding := range bindings {
+			var hostPort int
+			fmt.Sscanf(binding.HostPort, "%d", &hostPort)
+			opts.Ports = append(opts.Ports, PortMapping{
+				HostPort:      hostPort,
+				ContainerPort: containerPort.Int(),
+				Protocol:      containerPort.Proto(),
+			})
+		}
+	}
+	sortPorts(opts.Ports)
+
+	for _, m := range info.Mounts {
+		switch m.Type {
+		case mount.TypeBind:
+			opts.Volumes = append(opts.Volumes, VolumeMount{
+				HostPath:      m.Source,
+				ContainerPath: m.Destination,
+				ReadOnly:      !m.RW,
+			})
+		case mount.TypeVolume:
+			opts.Volumes = append(opts.Volumes, VolumeMount{
+				Volume:        m.Name,
+				ContainerPath: m.Destination,
+				ReadOnly:      !m.RW,
+			})
+		}
+	}
+
+	image, _, err := d.client.ImageInspectWithRaw(ctx, info.Image)
+	if err == nil && image.Config != nil {
+		stripImageDefaults(opts, image.Config.Env, image.Config.Cmd, image.Config.Entrypoint)
+		if sameHealthCheck(opts.HealthCheck, dockerHealthCheck(image.Config.Healthcheck)) {
+			opts.HealthCheck = nil
+		}
+	}
+	restoreExtraArgs(opts)
+
+	return opts, nil
+}
+
+// dockerHealthCheck converts a Docker health config to a HealthCheck
+func dockerHealthCheck(hc *containerTypes.HealthConfig) *HealthCheck {
+	if hc == nil {
+		return nil
+	}
+	return healthCheckFromTest(hc.Test, hc.Interval, hc.Timeout, hc.StartPeriod, hc.Retries)
+}
+
+// RenameContainer renames a container
+func (d *DockerRuntime) RenameContainer(id string, newName string) error {
+	return d.client.ContainerRename(context.Background(), id, newName)
+}
+
+// CreateNetwork creates a bridge network, doing nothing if it already exists
+func (d *DockerRuntime) CreateNetwork(name string, labels map[string]string) error {
+	ctx := context.Background()
+
+	if _, err := d.client.NetworkInspect(ctx, name, types.NetworkInspectOptions{}); err == nil {
+		return nil
+	}
+
+	_, err := d.client.NetworkCreate(ctx, name, types.NetworkCreate{
+		Driver: "bridge",
+		Labels: labels,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create network: %w", err)
+	}
+	return nil
+}
+
+// NetworkExists checks if a network exists
+func (d *DockerRuntime) NetworkExists(name string) (bool, error) {
+	_, err := d.client.NetworkInspect(context.Background(), name, types.NetworkInspectOptions{})
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to inspect network: %w", err)
+	}
+	return true, nil
+}
+
+// RemoveNetwork removes a network
+func (d *DockerRuntime) RemoveNetwork(name string) error {
+	ctx := context.Background()
+	if err := d.client.NetworkRemove(ctx, name); err != nil {
+		return fmt.Errorf("failed to remove network: %w", err)
+	}
+	return nil
+}
+
+// CreateVolume creates a named volume, doing nothing if it already exists
+func (d *DockerRuntime) CreateVolume(name string, labels map[string]string) error {
+	ctx := context.Background()
+
+	if _, err := d.client.VolumeInspect(ctx, name); err == nil {
+		return nil
+	}
+
+	_, err := d.client.VolumeCreate(ctx, volume.CreateOptions{
+		Name:   name,
+		Labels: labels,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create volume: %w", err)
+	}
+	return nil
+}
+
+// ListVolumes lists the volumes carrying all of labels
+func (d *DockerRuntime) ListVolumes(labels map[string]string) ([]Volume, error) {
+	ctx := context.Background()
+
+	args := filters.NewArgs()
+	for k, v := range labels {
+		args.Add("label", fmt.Sprintf("%s=%s", k, v))
+	}
+
+	resp, err := d.client.VolumeList(ctx, volume.ListOptions{Filters: args})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %w", err)
+	}
+
+	var result []Volume
+	for _, v := range resp.Volumes {
+		created, _ := time.Parse(time.RFC3339, v.CreatedAt)
+		result = append(result, Volume{
+			Name:    v.Name,
+			Labels:  v.Labels,
+			Created: created,
+		})
+	}
+	return result, nil
+}
+
+// RemoveVolume removes a volume
+func (d *DockerRuntime) RemoveVolume(name string) error {
+	ctx := context.Background()
+	if err := d.client.VolumeRemove(ctx, name, false); err != nil {
+		return fmt.Errorf("failed to remove volume: %w", err)
+	}
+	return nil
+}
+
+// ContainerStats samples a running container's resource use. The daemon
+// takes two readings about a second apart to work out CPU use.
+func (d *DockerRuntime) ContainerStats(id string) (*Stats, error) {
+	ctx := context.Background()
+
+	resp, err := d.client.ContainerStats(ctx, id, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container stats: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var s types.StatsJSON
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to parse container stats: %w", err)
+	}
+	return statsFromJSON(s), nil
+}
+
+// statsFromJSON computes Stats the way docker stats does
+func statsFromJSON(s types.StatsJSON) *Stats {
+	stats := &Stats{
+		MemoryUsage: s.MemoryStats.Usage,
+		MemoryLimit: s.MemoryStats.Limit,
+		PIDs:        s.PidsStats.Current,
+	}
+
+	cpuDelta := float64(s.CPUStats.CPUUsage.TotalUsage) - float64(s.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(s.CPUStats.SystemUsage) - float64(s.PreCPUStats.SystemUsage)
+	cpus := float64(s.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(s.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta > 0 && systemDelta > 0 {
+		stats.CPUPercent = cpuDelta / systemDelta * cpus * 100
+	}
+
+	// Page cache can be reclaimed, so it isn't counted: cgroup v1 reports
+	// total_inactive_file, v2 inactive_file
+	for _, key := range []string{"total_inactive_file", "inactive_file"} {
+		if cache, ok := s.MemoryStats.Stats[key]; ok && cache < stats.MemoryUsage {
+			stats.MemoryUsage -= cache
+			break
+		}
+	}
+
+	for _, n := range s.Networks {
+		stats.NetRx += n.RxBytes
+		stats.NetTx += n.TxBytes
+	}
+	for _, entry := range s.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			stats.BlockRead += entry.Value
+		case "write":
+			stats.BlockWrite += entry.Value
+		}
+	}
+	return stats
+}
+
 // ContainerLogs returns container logs
 func (d *DockerRuntime) ContainerLogs(id string, opts LogOptions) (io.ReadCloser, error) {
 	ctx := context.Background()
@@ -282,6 +630,57 @@ func (d *DockerRuntime) ExecInContainer(id string, cmd []string, opts ExecOption
 	return nil
 }
 
+// CopyFromContainer copies a file or directory out of a container
+func (d *DockerRuntime) CopyFromContainer(id, srcPath, destPath string) error {
+	ctx := context.Background()
+
+	content, _, err := d.client.CopyFromContainer(ctx, id, srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to copy from container: %w", err)
+	}
+	defer content.Close()
+
+	if destPath == "-" {
+		if _, err := io.Copy(os.Stdout, content); err != nil {
+			return fmt.Errorf("failed to copy from container: %w", err)
+		}
+		return nil
+	}
+	if err := archive.Extract(content, destPath); err != nil {
+		return fmt.Errorf("failed to copy from container: %w", err)
+	}
+	return nil
+}
+
+// CopyToContainer copies a local file or directory into a container. The
+// copy goes inside destPath if that's a directory, otherwise it becomes
+// destPath.
+func (d *DockerRuntime) CopyToContainer(id, srcPath, destPath string) error {
+	ctx := context.Background()
+
+	if _, err := os.Lstat(srcPath); err != nil {
+		return fmt.Errorf("failed to copy to container: %w", err)
+	}
+
+	dir, name := destPath, filepath.Base(srcPath)
+	if stat, err := d.client.ContainerStatPath(ctx, id, destPath); err != nil || !stat.Mode.IsDir() {
+		dir, name = path.Dir(destPath), path.Base(destPath)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(archive.Write(pw, srcPath, name))
+	}()
+	defer pr.Close()
+
+	// CopyUIDGID makes the files the container user's rather than root's
+	err := d.client.CopyToContainer(ctx, id, dir, pr, types.CopyToContainerOptions{CopyUIDGID: true})
+	if err != nil {
+		return fmt.Errorf("failed to copy to container: %w", err)
+	}
+	return nil
+}
+
 // CommitContainer commits container state to an image
 func (d *DockerRuntime) CommitContainer(id string, imageName string) error {
 	ctx := context.Background()
@@ -307,17 +706,22 @@ func (d *DockerRuntime) BuildImage(tag string, opts BuildOptions) error {
 	}
 
 	buildOptions := types.ImageBuildOptions{
-		Tags:       []string{tag},
-		Dockerfile: filepath.Base(opts.Dockerfile),
-		NoCache:    opts.NoCache,
-		Remove:     true,
-		BuildArgs:  make(map[string]*string),
+		Tags:        []string{tag},
+		Dockerfile:  filepath.Base(opts.Dockerfile),
+		NoCache:     opts.NoCache,
+		CacheFrom:   opts.CacheFrom,
+		Remove:      true,
+		BuildArgs:   make(map[string]*string),
+		AuthConfigs: make(map[string]registry.AuthConfig),
 	}
 
 	for k, v := range opts.BuildArgs {
 		val := v
 		buildOptions.BuildArgs[k] = &val
 	}
+	for _, auth := range opts.RegistryAuths {
+		buildOptions.AuthConfigs[authKey(auth.ServerAddress)] = dockerAuthConfig(auth)
+	}
 
 	resp, err := d.client.ImageBuild(ctx, buildContext, buildOptions)
 	if err != nil {
@@ -334,11 +738,27 @@ func (d *DockerRuntime) BuildImage(tag string, opts BuildOptions) error {
 	return nil
 }
 
-// PullImage pulls an image from a registry
-func (d *DockerRuntime) PullImage(imageName string) error {
+// PullImage pulls an image from a registry. The daemon doesn't read
+// config.json itself, so empty credentials are looked up there.
+func (d *DockerRuntime) PullImage(imageName string, auth RegistryAuth) error {
 	ctx := context.Background()
 
-	resp, err := d.client.ImagePull(ctx, imageName, types.ImagePullOptions{})
+	if auth.Username == "" {
+		var err error
+		if auth, err = DockerConfigAuth(RegistryHost(imageName)); err != nil {
+			return err
+		}
+	}
+	var pullOptions types.ImagePullOptions
+	if auth.Username != "" {
+		encodedAuth, err := registry.EncodeAuthConfig(dockerAuthConfig(auth))
+		if err != nil {
+			return fmt.Errorf("failed to encode registry auth: %w", err)
+		}
+		pullOptions.RegistryAuth = encodedAuth
+	}
+
+	resp, err := d.client.ImagePull(ctx, imageName, pullOptions)
 	if err != nil {
 		return fmt.Errorf("failed to pull image: %w", err)
 	}
@@ -353,6 +773,41 @@ func (d *DockerRuntime) PullImage(imageName string) error {
 	return nil
 }
 
+// PushImage pushes an image to a registry and returns the pushed digest
+func (d *DockerRuntime) PushImage(imageName string, auth RegistryAuth) (string, error) {
+	ctx := context.Background()
+
+	encodedAuth, err := registry.EncodeAuthConfig(dockerAuthConfig(auth))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode registry auth: %w", err)
+	}
+
+	resp, err := d.client.ImagePush(ctx, imageName, types.ImagePushOptions{RegistryAuth: encodedAuth})
+	if err != nil {
+		return "", fmt.Errorf("failed to push image: %w", err)
+	}
+	defer resp.Close()
+
+	// The final aux message of a push carries the manifest digest
+	var digest string
+	err = jsonmessage.DisplayJSONMessagesStream(resp, os.Stdout, 0, false, func(msg jsonmessage.JSONMessage) {
+		if msg.Aux == nil {
+			return
+		}
+		var result struct {
+			Digest string `json:"Digest"`
+		}
+		if json.Unmarshal(*msg.Aux, &result) == nil && result.Digest != "" {
+			digest = result.Digest
+		}
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to push image: %w", err)
+	}
+
+	return digest, nil
+}
+
 // ImageExists checks if an image exists locally
 func (d *DockerRuntime) ImageExists(imageName string) (bool, error) {
 	ctx := context.Background()
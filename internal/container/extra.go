@@ -0,0 +1,250 @@
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	containerTypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/go-units"
+)
+
+// ExtraArgsLabel records a container's extra create flags so restarts
+// recreate it with them
+const ExtraArgsLabel = "frank.extra-args"
+
+// extraFlags are the create flags frank passes through, and whether each
+// takes a value
+var extraFlags = map[string]bool{
+	"add-host":     true,
+	"cap-add":      true,
+	"cap-drop":     true,
+	"cpus":         true,
+	"device":       true,
+	"dns":          true,
+	"init":         false,
+	"memory":       true,
+	"network":      true,
+	"pids-limit":   true,
+	"privileged":   false,
+	"security-opt": true,
+	"shm-size":     true,
+	"sysctl":       true,
+	"ulimit":       true,
+}
+
+// ExtraArg is one create flag, in the form 'docker run' and 'podman create'
+// take it
+type ExtraArg struct {
+	Flag  string // Without the leading dashes, e.g. cap-add
+	Value string // Empty for boolean flags
+}
+
+// String returns the flag as a single command line argument
+func (a ExtraArg) String() string {
+	if !extraFlags[a.Flag] {
+		return "--" + a.Flag
+	}
+	return "--" + a.Flag + "=" + a.Value
+}
+
+// ParseExtraArgs parses create flags frank doesn't model itself, such as
+// "--cap-add=SYS_PTRACE", "--ulimit nofile=65536" or "--privileged". A flag
+// and its value may be one argument or two.
+func ParseExtraArgs(args []string) ([]ExtraArg, error) {
+	var parsed []ExtraArg
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		name, ok := strings.CutPrefix(arg, "--")
+		if !ok {
+			return nil, fmt.Errorf("invalid extra create arg %q (must start with --)", arg)
+		}
+		name, value, hasValue := strings.Cut(name, "=")
+		takesValue, known := extraFlags[name]
+		if !known {
+			return nil, fmt.Errorf("unsupported extra create arg --%s (supported: %s)", name, strings.Join(extraFlagNames(), ", "))
+		}
+
+		switch {
+		case !takesValue && hasValue:
+			return nil, fmt.Errorf("--%s takes no value", name)
+		case takesValue && !hasValue:
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("--%s needs a value", name)
+			}
+			i++
+			value = strings.TrimSpace(args[i])
+		}
+
+		a := ExtraArg{Flag: name, Value: value}
+		if err := applyExtraArg(&containerTypes.HostConfig{}, a); err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, a)
+	}
+	return parsed, nil
+}
+
+// FormatExtraArgs returns the flags as command line arguments
+func FormatExtraArgs(args []ExtraArg) []string {
+	formatted := make([]string, 0, len(args))
+	for _, a := range args {
+		formatted = append(formatted, a.String())
+	}
+	return formatted
+}
+
+func extraFlagNames() []string {
+	names := make([]string, 0, len(extraFlags))
+	for name := range extraFlags {
+		names = append(names, "--"+name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyExtraArg sets the Docker host config field for a flag. --network is
+// handled by extraNetwork instead.
+func applyExtraArg(hc *containerTypes.HostConfig, a ExtraArg) error {
+	invalid := func(err error) error {
+		return fmt.Errorf("invalid --%s %q: %w", a.Flag, a.Value, err)
+	}
+	if extraFlags[a.Flag] && a.Value == "" {
+		return fmt.Errorf("--%s needs a value", a.Flag)
+	}
+
+	switch a.Flag {
+	case "add-host":
+		hc.ExtraHosts = append(hc.ExtraHosts, a.Value)
+	case "cap-add":
+		hc.CapAdd = append(hc.CapAdd, a.Value)
+	case "cap-drop":
+		hc.CapDrop = append(hc.CapDrop, a.Value)
+	case "cpus":
+		cpus, err := strconv.ParseFloat(a.Value, 64)
+		if err != nil || cpus <= 0 {
+			return invalid(fmt.Errorf("must be a positive number"))
+		}
+		hc.NanoCPUs = int64(cpus * 1e9)
+	case "device":
+		parts := strings.Split(a.Value, ":")
+		if len(parts) > 3 {
+			return invalid(fmt.Errorf("use HOST[:CONTAINER[:PERMISSIONS]]"))
+		}
+		device := containerTypes.DeviceMapping{PathOnHost: parts[0], PathInContainer: parts[0], CgroupPermissions: "rwm"}
+		if len(parts) > 1 {
+			device.PathInContainer = parts[1]
+		}
+		if len(parts) > 2 {
+			device.CgroupPermissions = parts[2]
+		}
+		hc.Devices = append(hc.Devices, device)
+	case "dns":
+		hc.DNS = append(hc.DNS, a.Value)
+	case "init":
+		enabled := true
+		hc.Init = &enabled
+	case "memory":
+		bytes, err := units.RAMInBytes(a.Value)
+		if err != nil {
+			return invalid(err)
+		}
+		hc.Memory = bytes
+	case "network":
+	case "pids-limit":
+		limit, err := strconv.ParseInt(a.Value, 10, 64)
+		if err != nil {
+			return invalid(err)
+		}
+		hc.PidsLimit = &limit
+	case "privileged":
+		hc.Privileged = true
+	case "security-opt":
+		hc.SecurityOpt = append(hc.SecurityOpt, a.Value)
+	case "shm-size":
+		bytes, err := units.RAMInBytes(a.Value)
+		if err != nil {
+			return invalid(err)
+		}
+		hc.ShmSize = bytes
+	case "sysctl":
+		key, value, ok := strings.Cut(a.Value, "=")
+		if !ok || key == "" {
+			return invalid(fmt.Errorf("use KEY=VALUE"))
+		}
+		if hc.Sysctls == nil {
+			hc.Sysctls = map[string]string{}
+		}
+		hc.Sysctls[key] = value
+	case "ulimit":
+		ulimit, err := units.ParseUlimit(a.Value)
+		if err != nil {
+			return invalid(err)
+		}
+		hc.Ulimits = append(hc.Ulimits, ulimit)
+	default:
+		return fmt.Errorf("unsupported extra create arg --%s", a.Flag)
+	}
+	return nil
+}
+
+// extraNetwork returns the network an --network extra arg puts the
+// container on, or opts.Network. The two can't differ: frank attaches
+// containers with sidecars to their own network, and --network containers
+// to the one named.
+func extraNetwork(opts ContainerOptions) (string, error) {
+	network := opts.Network
+	for _, a := range opts.ExtraArgs {
+		if a.Flag != "network" {
+			continue
+		}
+		if opts.Network != "" && a.Value != opts.Network {
+			return "", fmt.Errorf("extra create arg --network=%s conflicts with network %s", a.Value, opts.Network)
+		}
+		network = a.Value
+	}
+	return network, nil
+}
+
+// extraArgsLabels returns opts.Labels with ExtraArgsLabel recording the
+// extra args, copying the map rather than changing the caller's
+func extraArgsLabels(opts ContainerOptions) map[string]string {
+	if len(opts.ExtraArgs) == 0 {
+		return opts.Labels
+	}
+	data, err := json.Marshal(FormatExtraArgs(opts.ExtraArgs))
+	if err != nil {
+		return opts.Labels
+	}
+	labels := make(map[string]string, len(opts.Labels)+1)
+	for k, v := range opts.Labels {
+		labels[k] = v
+	}
+	labels[ExtraArgsLabel] = string(data)
+	return labels
+}
+
+// restoreExtraArgs sets opts.ExtraArgs from the label of an inspected
+// container. A network that came from --network is left to the extra arg.
+func restoreExtraArgs(opts *ContainerOptions) {
+	data, ok := opts.Labels[ExtraArgsLabel]
+	if !ok {
+		return
+	}
+	var args []string
+	if err := json.Unmarshal([]byte(data), &args); err != nil {
+		return
+	}
+	parsed, err := ParseExtraArgs(args)
+	if err != nil {
+		return
+	}
+	opts.ExtraArgs = parsed
+	for _, a := range parsed {
+		if a.Flag == "network" && a.Value == opts.Network {
+			opts.Network, opts.Aliases = "", nil
+		}
+	}
+}
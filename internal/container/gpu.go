@@ -0,0 +1,107 @@
+package container
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cdiGPUPrefix names NVIDIA GPUs in the Container Device Interface, which
+// podman uses for devices
+const cdiGPUPrefix = "nvidia.com/gpu="
+
+// DeviceRequest asks the runtime for host devices, such as GPUs
+type DeviceRequest struct {
+	Driver       string   // e.g. nvidia; empty lets the runtime pick
+	Count        int      // Number of devices, -1 for all; ignored with DeviceIDs
+	DeviceIDs    []string // Specific device indexes or UUIDs
+	Capabilities []string // e.g. gpu
+}
+
+// ParseGPUs parses a GPU spec in the form of docker's --gpus: "all", a
+// count such as "2", or "device=0,1" for specific devices. Empty and "none"
+// request no GPUs.
+func ParseGPUs(spec string) ([]DeviceRequest, error) {
+	spec = strings.TrimSpace(spec)
+	req := DeviceRequest{Driver: "nvidia", Capabilities: []string{"gpu"}}
+	switch {
+	case spec == "" || spec == "none":
+		return nil, nil
+	case spec == "all":
+		req.Count = -1
+	case strings.HasPrefix(spec, "device="):
+		for _, id := range strings.Split(strings.TrimPrefix(spec, "device="), ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				req.DeviceIDs = append(req.DeviceIDs, id)
+			}
+		}
+		if len(req.DeviceIDs) == 0 {
+			return nil, fmt.Errorf("invalid GPU spec %q: no devices listed", spec)
+		}
+	default:
+		count, err := strconv.Atoi(spec)
+		if err != nil || count < 1 {
+			return nil, fmt.Errorf("invalid GPU spec %q (must be all, a count, device=<ids> or none)", spec)
+		}
+		req.Count = count
+	}
+	return []DeviceRequest{req}, nil
+}
+
+// FormatGPUs returns the GPU spec for device requests, the inverse of
+// ParseGPUs, or "" when none ask for GPUs
+func FormatGPUs(reqs []DeviceRequest) string {
+	for _, req := range reqs {
+		switch {
+		case len(req.DeviceIDs) > 0:
+			return "device=" + strings.Join(req.DeviceIDs, ",")
+		case req.Count < 0:
+			return "all"
+		case req.Count > 0:
+			return strconv.Itoa(req.Count)
+		}
+	}
+	return ""
+}
+
+// cdiDevices returns the CDI device names for GPU requests. CDI has no
+// count, so a count of n takes GPUs 0 to n-1.
+func cdiDevices(reqs []DeviceRequest) []string {
+	var devices []string
+	for _, req := range reqs {
+		switch {
+		case len(req.DeviceIDs) > 0:
+			for _, id := range req.DeviceIDs {
+				devices = append(devices, cdiGPUPrefix+id)
+			}
+		case req.Count < 0:
+			devices = append(devices, cdiGPUPrefix+"all")
+		default:
+			for i := 0; i < req.Count; i++ {
+				devices = append(devices, cdiGPUPrefix+strconv.Itoa(i))
+			}
+		}
+	}
+	return devices
+}
+
+// cdiDeviceRequests turns the CDI GPU names of a container's devices back
+// into a device request
+func cdiDeviceRequests(devices []string) []DeviceRequest {
+	req := DeviceRequest{Driver: "nvidia", Capabilities: []string{"gpu"}}
+	for _, device := range devices {
+		id, ok := strings.CutPrefix(device, cdiGPUPrefix)
+		if !ok {
+			continue
+		}
+		if id == "all" {
+			req.Count, req.DeviceIDs = -1, nil
+			break
+		}
+		req.DeviceIDs = append(req.DeviceIDs, id)
+	}
+	if req.Count == 0 && len(req.DeviceIDs) == 0 {
+		return nil
+	}
+	return []DeviceRequest{req}
+}
@@ -0,0 +1,85 @@
+package container
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Health states reported by the runtimes
+const (
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+	HealthStarting  = "starting"
+)
+
+// ValidateRestartPolicy checks that policy is one the runtimes accept. Empty
+// is allowed and means no restart.
+func ValidateRestartPolicy(policy string) error {
+	switch policy {
+	case "", RestartNo, RestartAlways, RestartOnFailure, RestartUnlessStopped:
+		return nil
+	}
+	return fmt.Errorf("invalid restart policy %q (must be %s, %s, %s or %s)",
+		policy, RestartNo, RestartAlways, RestartOnFailure, RestartUnlessStopped)
+}
+
+// healthFromStatus extracts the health state from a status line such as
+// "Up 5 minutes (healthy)" or "Up 3 seconds (health: starting)"
+func healthFromStatus(status string) string {
+	switch {
+	case strings.Contains(status, "(unhealthy)"):
+		return HealthUnhealthy
+	case strings.Contains(status, "(healthy)"):
+		return HealthHealthy
+	case strings.Contains(status, "starting)"):
+		return HealthStarting
+	}
+	return ""
+}
+
+// healthTest returns the runtime test command for a health check
+func healthTest(hc *HealthCheck) []string {
+	return []string{"CMD-SHELL", hc.Command}
+}
+
+// healthCheckFromTest converts a runtime health config back to a HealthCheck.
+// It returns nil when the test is empty or disabled.
+func healthCheckFromTest(test []string, interval, timeout, startPeriod time.Duration, retries int) *HealthCheck {
+	if len(test) < 2 {
+		return nil
+	}
+	var command string
+	switch test[0] {
+	case "CMD-SHELL":
+		command = test[1]
+	case "CMD":
+		command = strings.Join(test[1:], " ")
+	default:
+		return nil
+	}
+	return &HealthCheck{
+		Command:     command,
+		Interval:    interval,
+		Timeout:     timeout,
+		StartPeriod: startPeriod,
+		Retries:     retries,
+	}
+}
+
+// sameHealthCheck reports whether two health checks are equivalent
+func sameHealthCheck(a, b *HealthCheck) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// restartPolicyName normalizes a runtime's restart policy, reporting "no"
+// as empty
+func restartPolicyName(name string) string {
+	if name == RestartNo {
+		return ""
+	}
+	return name
+}
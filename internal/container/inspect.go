@@ -0,0 +1,75 @@
+package container
+
+import (
+	"sort"
+	"strings"
+)
+
+// stripImageDefaults removes settings a container inherited unchanged from its
+// image, so recreating it against a newer image picks up the new defaults
+func stripImageDefaults(opts *ContainerOptions, imageEnv, imageCmd, imageEntrypoint []string) {
+	inherited := make(map[string]bool, len(imageEnv))
+	for _, e := range imageEnv {
+		inherited[e] = true
+	}
+
+	var env []string
+	for _, e := range opts.Env {
+		if !inherited[e] {
+			env = append(env, e)
+		}
+	}
+	opts.Env = env
+
+	if equalStrings(opts.Cmd, imageCmd) {
+		opts.Cmd = nil
+	}
+	if equalStrings(opts.Entrypoint, imageEntrypoint) {
+		opts.Entrypoint = nil
+	}
+}
+
+// userAliases drops the aliases a runtime adds on its own (the container
+// name and short ID) from a network endpoint's alias list
+func userAliases(aliases []string, name, id string) []string {
+	var out []string
+	for _, a := range aliases {
+		if a == name || (len(a) >= 12 && strings.HasPrefix(id, a)) {
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// extraNetworks returns the networks in names other than primary and the
+// runtimes' default ones, sorted
+func extraNetworks(names []string, primary string) []string {
+	var out []string
+	for _, name := range names {
+		if name != primary && name != "bridge" && name != "podman" {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// sortPorts orders port mappings by container port for stable output
+func sortPorts(ports []PortMapping) {
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i].ContainerPort < ports[j].ContainerPort
+	})
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -15,13 +15,24 @@ type OrbStackRuntime struct {
 
 // NewOrbStackRuntime creates a new OrbStack runtime
 func NewOrbStackRuntime() (*OrbStackRuntime, error) {
-	docker, err := NewDockerRuntime()
+	return NewOrbStackRuntimeWithHost("")
+}
+
+// NewOrbStackRuntimeWithHost creates an OrbStack runtime using a specific
+// Docker endpoint, such as OrbStack's own socket
+func NewOrbStackRuntimeWithHost(host string) (*OrbStackRuntime, error) {
+	docker, err := NewDockerRuntimeWithHost(host)
 	if err != nil {
 		return nil, err
 	}
 	return &OrbStackRuntime{docker: docker}, nil
 }
 
+// Endpoint returns the daemon address the runtime talks to
+func (o *OrbStackRuntime) Endpoint() string {
+	return o.docker.Endpoint()
+}
+
 // Name returns the runtime name
 func (o *OrbStackRuntime) Name() string {
 	return "orbstack"
@@ -77,6 +88,51 @@ func (o *OrbStackRuntime) GetContainer(idOrName string) (*Container, error) {
 	return o.docker.GetContainer(idOrName)
 }
 
+// InspectContainer returns the options a container was created with
+func (o *OrbStackRuntime) InspectContainer(idOrName string) (*ContainerOptions, error) {
+	return o.docker.InspectContainer(idOrName)
+}
+
+// RenameContainer renames a container
+func (o *OrbStackRuntime) RenameContainer(id string, newName string) error {
+	return o.docker.RenameContainer(id, newName)
+}
+
+// CreateNetwork creates a bridge network, doing nothing if it already exists
+func (o *OrbStackRuntime) CreateNetwork(name string, labels map[string]string) error {
+	return o.docker.CreateNetwork(name, labels)
+}
+
+// NetworkExists checks if a network exists
+func (o *OrbStackRuntime) NetworkExists(name string) (bool, error) {
+	return o.docker.NetworkExists(name)
+}
+
+// RemoveNetwork removes a network
+func (o *OrbStackRuntime) RemoveNetwork(name string) error {
+	return o.docker.RemoveNetwork(name)
+}
+
+// CreateVolume creates a named volume, doing nothing if it already exists
+func (o *OrbStackRuntime) CreateVolume(name string, labels map[string]string) error {
+	return o.docker.CreateVolume(name, labels)
+}
+
+// ListVolumes lists the volumes carrying all of labels
+func (o *OrbStackRuntime) ListVolumes(labels map[string]string) ([]Volume, error) {
+	return o.docker.ListVolumes(labels)
+}
+
+// RemoveVolume removes a volume
+func (o *OrbStackRuntime) RemoveVolume(name string) error {
+	return o.docker.RemoveVolume(name)
+}
+
+// ContainerStats samples a running container's resource use
+func (o *OrbStackRuntime) ContainerStats(id string) (*Stats, error) {
+	return o.docker.ContainerStats(id)
+}
+
 // ContainerLogs returns container logs
 func (o *OrbStackRuntime) ContainerLogs(id string, opts LogOptions) (io.ReadCloser, error) {
 	return o.docker.ContainerLogs(id, opts)
@@ -87,6 +143,16 @@ func (o *OrbStackRuntime) ExecInContainer(id string, cmd []string, opts ExecOpti
 	return o.docker.ExecInContainer(id, cmd, opts)
 }
 
+// CopyFromContainer copies a file or directory out of a container
+func (o *OrbStackRuntime) CopyFromContainer(id, srcPath, destPath string) error {
+	return o.docker.CopyFromContainer(id, srcPath, destPath)
+}
+
+// CopyToContainer copies a local file or directory into a container
+func (o *OrbStackRuntime) CopyToContainer(id, srcPath, destPath string) error {
+	return o.docker.CopyToContainer(id, srcPath, destPath)
+}
+
 // CommitContainer commits container state to an image
 func (o *OrbStackRuntime) CommitContainer(id string, imageName string) error {
 	return o.docker.CommitContainer(id, imageName)
@@ -98,8 +164,13 @@ func (o *OrbStackRuntime) BuildImage(tag string, opts BuildOptions) error {
 }
 
 // PullImage pulls an image from a registry
-func (o *OrbStackRuntime) PullImage(imageName string) error {
-	return o.docker.PullImage(imageName)
+func (o *OrbStackRuntime) PullImage(imageName string, auth RegistryAuth) error {
+	return o.docker.PullImage(imageName, auth)
+}
+
+// PushImage pushes an image to a registry
+func (o *OrbStackRuntime) PushImage(imageName string, auth RegistryAuth) (string, error) {
+	return o.docker.PushImage(imageName, auth)
 }
 
 // ImageExists checks if an image exists locally
@@ -2,6 +2,7 @@ package container
 
 import (
 	"bufio"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,14 +12,37 @@ import (
 	"time"
 )
 
-// PodmanRuntime implements Runtime using Podman CLI
-type PodmanRuntime struct{}
+// PodmanRuntime implements Runtime using Podman CLI, and podman's API socket
+// for the calls it handles when the socket is available
+type PodmanRuntime struct {
+	api *DockerRuntime // nil when every call uses the CLI
+}
 
 // NewPodmanRuntime creates a new Podman runtime
 func NewPodmanRuntime() (*PodmanRuntime, error) {
 	return &PodmanRuntime{}, nil
 }
 
+// UseAPI connects the runtime to podman's API socket. On error the runtime
+// keeps using the CLI for everything.
+func (p *PodmanRuntime) UseAPI() error {
+	api, err := newPodmanAPI()
+	if err != nil {
+		return err
+	}
+	p.api = api
+	return nil
+}
+
+// Endpoint returns the API socket the runtime talks to, or an empty string
+// when it only uses the CLI
+func (p *PodmanRuntime) Endpoint() string {
+	if p.api == nil {
+		return ""
+	}
+	return p.api.Endpoint()
+}
+
 // Name returns the runtime name
 func (p *PodmanRuntime) Name() string {
 	return "podman"
@@ -32,8 +56,21 @@ func (p *PodmanRuntime) IsAvailable() bool {
 
 // CreateContainer creates a new container
 func (p *PodmanRuntime) CreateContainer(opts ContainerOptions) (string, error) {
+	// GPUs need CDI device names, which only the CLI takes
+	if p.api != nil && len(opts.DeviceRequests) == 0 {
+		id, err := p.api.CreateContainer(opts)
+		if !apiUnreachable(err) {
+			return id, err
+		}
+	}
+
 	args := []string{"create", "--name", opts.Name}
 
+	networkName, err := extraNetwork(opts)
+	if err != nil {
+		return "", err
+	}
+
 	// Add port mappings
 	for _, port := range opts.Ports {
 		protocol := port.Protocol
@@ -50,7 +87,11 @@ func (p *PodmanRuntime) CreateContainer(opts ContainerOptions) (string, error) {
 
 	// Add volume mounts
 	for _, vol := range opts.Volumes {
-		mountOpt := fmt.Sprintf("%s:%s", vol.HostPath, vol.ContainerPath)
+		source := vol.HostPath
+		if vol.Volume != "" {
+			source = vol.Volume
+		}
+		mountOpt := fmt.Sprintf("%s:%s", source, vol.ContainerPath)
 		if vol.ReadOnly {
 			mountOpt += ":ro"
 		}
@@ -63,10 +104,53 @@ func (p *PodmanRuntime) CreateContainer(opts ContainerOptions) (string, error) {
 	}
 
 	// Add labels
-	for k, v := range opts.Labels {
+	for k, v := range extraArgsLabels(opts) {
 		args = append(args, "--label", fmt.Sprintf("%s=%s", k, v))
 	}
 
+	// Attach to a user-defined network so sidecars resolve by name
+	if networkName != "" {
+		args = append(args, "--network", networkName)
+		for _, alias := range opts.Aliases {
+			args = append(args, "--network-alias", alias)
+		}
+	}
+	for _, name := range opts.Networks {
+		args = append(args, "--network", name)
+	}
+
+	// Add health check and restart policy
+	if hc := opts.HealthCheck; hc != nil {
+		args = append(args, "--health-cmd", hc.Command)
+		if hc.Interval > 0 {
+			args = append(args, "--health-interval", hc.Interval.String())
+		}
+		if hc.Timeout > 0 {
+			args = append(args, "--health-timeout", hc.Timeout.String())
+		}
+		if hc.StartPeriod > 0 {
+			args = append(args, "--health-start-period", hc.StartPeriod.String())
+		}
+		if hc.Retries > 0 {
+			args = append(args, "--health-retries", fmt.Sprintf("%d", hc.Retries))
+		}
+	}
+	if opts.RestartPolicy != "" && !opts.AutoRemove {
+		args = append(args, "--restart", opts.RestartPolicy)
+	}
+
+	// GPUs are CDI devices; nvidia-ctk must have generated the CDI spec
+	for _, device := range cdiDevices(opts.DeviceRequests) {
+		args = append(args, "--device", device)
+	}
+
+	// Create flags from the config; --network was handled above
+	for _, a := range opts.ExtraArgs {
+		if a.Flag != "network" {
+			args = append(args, a.String())
+		}
+	}
+
 	// Add TTY and stdin options
 	if opts.TTY {
 		args = append(args, "-t")
@@ -97,18 +181,33 @@ func (p *PodmanRuntime) CreateContainer(opts ContainerOptions) (string, error) {
 
 // StartContainer starts a container
 func (p *PodmanRuntime) StartContainer(id string) error {
+	if p.api != nil {
+		if err := p.api.StartContainer(id); !apiUnreachable(err) {
+			return err
+		}
+	}
 	cmd := exec.Command("podman", "start", id)
 	return cmd.Run()
 }
 
 // StopContainer stops a container
 func (p *PodmanRuntime) StopContainer(id string, timeout time.Duration) error {
+	if p.api != nil {
+		if err := p.api.StopContainer(id, timeout); !apiUnreachable(err) {
+			return err
+		}
+	}
 	cmd := exec.Command("podman", "stop", "-t", fmt.Sprintf("%d", int(timeout.Seconds())), id)
 	return cmd.Run()
 }
 
 // RemoveContainer removes a container
 func (p *PodmanRuntime) RemoveContainer(id string, force bool) error {
+	if p.api != nil {
+		if err := p.api.RemoveContainer(id, force); !apiUnreachable(err) {
+			return err
+		}
+	}
 	args := []string{"rm"}
 	if force {
 		args = append(args, "-f")
@@ -120,6 +219,12 @@ func (p *PodmanRuntime) RemoveContainer(id string, force bool) error {
 
 // ListContainers lists containers matching the filter
 func (p *PodmanRuntime) ListContainers(filter ContainerFilter) ([]Container, error) {
+	if p.api != nil {
+		containers, err := p.api.ListContainers(filter)
+		if !apiUnreachable(err) {
+			return containers, err
+		}
+	}
 	args := []string{"ps", "--format", "json"}
 	if filter.All {
 		args = append(args, "-a")
@@ -205,6 +310,7 @@ func (p *PodmanRuntime) ListContainers(filter ContainerFilter) ([]Container, err
 			Created: created,
 			Ports:   ports,
 			Labels:  c.Labels,
+			Health:  healthFromStatus(c.Status),
 		})
 	}
 
@@ -213,6 +319,12 @@ func (p *PodmanRuntime) ListContainers(filter ContainerFilter) ([]Container, err
 
 // GetContainer gets a specific container by ID or name
 func (p *PodmanRuntime) GetContainer(idOrName string) (*Container, error) {
+	if p.api != nil {
+		c, err := p.api.GetContainer(idOrName)
+		if !apiUnreachable(err) {
+			return c, err
+		}
+	}
 	cmd := exec.Command("podman", "inspect", "--format", "json", idOrName)
 	output, err := cmd.Output()
 	if err != nil {
@@ -228,6 +340,9 @@ func (p *PodmanRuntime) GetContainer(idOrName string) (*Container, error) {
 		} `json:"Config"`
 		State struct {
 			Status string `json:"Status"`
+			Health *struct {
+				Status string `json:"Status"`
+			} `json:"Health"`
 		} `json:"State"`
 		Created interface{} `json:"Created"` // Can be string or number
 	}
@@ -251,6 +366,11 @@ func (p *PodmanRuntime) GetContainer(idOrName string) (*Container, error) {
 		created = time.Unix(int64(v), 0)
 	}
 
+	var health string
+	if c.State.Health != nil {
+		health = c.State.Health.Status
+	}
+
 	return &Container{
 		ID:      c.ID[:12],
 		Name:    strings.TrimPrefix(c.Name, "/"),
@@ -258,11 +378,355 @@ func (p *PodmanRuntime) GetContainer(idOrName string) (*Container, error) {
 		Status:  c.State.Status,
 		Created: created,
 		Labels:  c.Config.Labels,
+		Health:  health,
+	}, nil
+}
+
+// InspectContainer returns the options a container was created with
+func (p *PodmanRuntime) InspectContainer(idOrName string) (*ContainerOptions, error) {
+	cmd := exec.Command("podman", "inspect", "--format", "json", idOrName)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	var containers []struct {
+		Name   string `json:"Name"`
+		Image  string `json:"Image"`
+		Config struct {
+			Image       string              `json:"Image"`
+			Env         []string            `json:"Env"`
+			WorkingDir  string              `json:"WorkingDir"`
+			Cmd         []string            `json:"Cmd"`
+			Entrypoint  json.RawMessage     `json:"Entrypoint"` // String or array depending on version
+			Labels      map[string]string   `json:"Labels"`
+			Tty         bool                `json:"Tty"`
+			OpenStdin   bool                `json:"OpenStdin"`
+			Healthcheck *podmanHealthConfig `json:"Healthcheck"`
+		} `json:"Config"`
+		HostConfig struct {
+			AutoRemove   bool `json:"AutoRemove"`
+			PortBindings map[string][]struct {
+				HostPort string `json:"HostPort"`
+			} `json:"PortBindings"`
+			RestartPolicy struct {
+				Name string `json:"Name"`
+			} `json:"RestartPolicy"`
+			Devices []struct {
+				PathOnHost string `json:"PathOnHost"`
+			} `json:"Devices"`
+			NetworkMode string `json:"NetworkMode"`
+		} `json:"HostConfig"`
+		Mounts []struct {
+			Type        string `json:"Type"`
+			Name        string `json:"Name"`
+			Source      string `json:"Source"`
+			Destination string `json:"Destination"`
+			RW          bool   `json:"RW"`
+		} `json:"Mounts"`
+		ID              string `json:"Id"`
+		NetworkSettings struct {
+			Networks map[string]struct {
+				Aliases []string `json:"Aliases"`
+			} `json:"Networks"`
+		} `json:"NetworkSettings"`
+	}
+
+	if err := json.Unmarshal(output, &containers); err != nil {
+		return nil, fmt.Errorf("failed to parse container info: %w", err)
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("container not found: %s", idOrName)
+	}
+	c := containers[0]
+
+	opts := &ContainerOptions{
+		Name:       strings.TrimPrefix(c.Name, "/"),
+		Image:      c.Config.Image,
+		Env:        c.Config.Env,
+		WorkDir:    c.Config.WorkingDir,
+		Cmd:        c.Config.Cmd,
+		Entrypoint: parseEntrypoint(c.Config.Entrypoint),
+		Labels:     c.Config.Labels,
+		AutoRemove: c.HostConfig.AutoRemove,
+		TTY:        c.Config.Tty,
+		OpenStdin:  c.Config.OpenStdin,
+
+		HealthCheck:   c.Config.Healthcheck.toHealthCheck(),
+		RestartPolicy: restartPolicyName(c.HostConfig.RestartPolicy.Name),
+	}
+
+	var devices []string
+	for _, device := range c.HostConfig.Devices {
+		devices = append(devices, device.PathOnHost)
+	}
+	opts.DeviceRequests = cdiDeviceRequests(devices)
+
+	for spec, bindings := range c.HostConfig.PortBindings {
+		// Keys look like "7680/tcp"
+		var containerPort int
+		protocol := "tcp"
+		if parts := strings.SplitN(spec, "/", 2); len(parts) == 2 {
+			protocol = parts[1]
+		}
+		fmt.Sscanf(spec, "%d", &containerPort)
+		for _, binding := range bindings {
+			var hostPort int
+			fmt.Sscanf(binding.HostPort, "%d", &hostPort)
+			opts.Ports = append(opts.Ports, PortMapping{
+				HostPort:      hostPort,
+				ContainerPort: containerPort,
+				Protocol:      protocol,
+			})
+		}
+	}
+	sortPorts(opts.Ports)
+
+	// podman reports its default network as "podman". It doesn't say which
+	// network came first, so the first by name is the one with aliases.
+	var names []string
+	for name := range c.NetworkSettings.Networks {
+		names = append(names, name)
+	}
+	if networks := extraNetworks(names, ""); len(networks) > 0 {
+		opts.Network, opts.Networks = networks[0], networks[1:]
+		opts.Aliases = userAliases(c.NetworkSettings.Networks[opts.Network].Aliases, opts.Name, c.ID)
+	} else if c.HostConfig.NetworkMode == NoNetwork {
+		opts.Network = NoNetwork
+	}
+
+	for _, m := range c.Mounts {
+		switch m.Type {
+		case "bind":
+			opts.Volumes = append(opts.Volumes, VolumeMount{
+				HostPath:      m.Source,
+				ContainerPath: m.Destination,
+				ReadOnly:      !m.RW,
+			})
+		case "volume":
+			opts.Volumes = append(opts.Volumes, VolumeMount{
+				Volume:        m.Name,
+				ContainerPath: m.Destination,
+				ReadOnly:      !m.RW,
+			})
+		}
+	}
+
+	imageOutput, err := exec.Command("podman", "image", "inspect", "--format", "json", c.Image).Output()
+	if err == nil {
+		var images []struct {
+			Config struct {
+				Env        []string        `json:"Env"`
+				Cmd        []string        `json:"Cmd"`
+				Entrypoint json.RawMessage `json:"Entrypoint"`
+			} `json:"Config"`
+			HealthCheck *podmanHealthConfig `json:"HealthCheck"`
+		}
+		if json.Unmarshal(imageOutput, &images) == nil && len(images) > 0 {
+			img := images[0].Config
+			stripImageDefaults(opts, img.Env, img.Cmd, parseEntrypoint(img.Entrypoint))
+			if sameHealthCheck(opts.HealthCheck, images[0].HealthCheck.toHealthCheck()) {
+				opts.HealthCheck = nil
+			}
+		}
+	}
+	restoreExtraArgs(opts)
+
+	return opts, nil
+}
+
+// podmanHealthConfig is a health check as podman inspect reports it, with
+// durations in nanoseconds
+type podmanHealthConfig struct {
+	Test        []string      `json:"Test"`
+	Interval    time.Duration `json:"Interval"`
+	Timeout     time.Duration `json:"Timeout"`
+	StartPeriod time.Duration `json:"StartPeriod"`
+	Retries     int           `json:"Retries"`
+}
+
+func (h *podmanHealthConfig) toHealthCheck() *HealthCheck {
+	if h == nil {
+		return nil
+	}
+	return healthCheckFromTest(h.Test, h.Interval, h.Timeout, h.StartPeriod, h.Retries)
+}
+
+// parseEntrypoint decodes an entrypoint that podman reports as either a
+// string or an array
+func parseEntrypoint(raw json.RawMessage) []string {
+	var list []string
+	if json.Unmarshal(raw, &list) == nil {
+		return list
+	}
+	var single string
+	if json.Unmarshal(raw, &single) == nil && single != "" {
+		return strings.Fields(single)
+	}
+	return nil
+}
+
+// RenameContainer renames a container
+func (p *PodmanRuntime) RenameContainer(id string, newName string) error {
+	cmd := exec.Command("podman", "rename", id, newName)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// CreateNetwork creates a bridge network, doing nothing if it already exists
+func (p *PodmanRuntime) CreateNetwork(name string, labels map[string]string) error {
+	if exec.Command("podman", "network", "exists", name).Run() == nil {
+		return nil
+	}
+
+	args := []string{"network", "create"}
+	for k, v := range labels {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", k, v))
+	}
+	args = append(args, name)
+
+	cmd := exec.Command("podman", args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create network: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// NetworkExists checks if a network exists
+func (p *PodmanRuntime) NetworkExists(name string) (bool, error) {
+	err := exec.Command("podman", "network", "exists", name).Run()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveNetwork removes a network
+func (p *PodmanRuntime) RemoveNetwork(name string) error {
+	cmd := exec.Command("podman", "network", "rm", name)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to remove network: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// CreateVolume creates a named volume, doing nothing if it already exists
+func (p *PodmanRuntime) CreateVolume(name string, labels map[string]string) error {
+	if exec.Command("podman", "volume", "exists", name).Run() == nil {
+		return nil
+	}
+
+	args := []string{"volume", "create"}
+	for k, v := range labels {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", k, v))
+	}
+	args = append(args, name)
+
+	cmd := exec.Command("podman", args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create volume: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// ListVolumes lists the volumes carrying all of labels
+func (p *PodmanRuntime) ListVolumes(labels map[string]string) ([]Volume, error) {
+	args := []string{"volume", "ls", "--format", "json"}
+	for k, v := range labels {
+		args = append(args, "--filter", fmt.Sprintf("label=%s=%s", k, v))
+	}
+
+	cmd := exec.Command("podman", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %w", err)
+	}
+
+	var volumes []struct {
+		Name      string            `json:"Name"`
+		Labels    map[string]string `json:"Labels"`
+		CreatedAt string            `json:"CreatedAt"`
+	}
+	if err := json.Unmarshal(output, &volumes); err != nil {
+		return nil, fmt.Errorf("failed to parse volume list: %w", err)
+	}
+
+	var result []Volume
+	for _, v := range volumes {
+		created, _ := time.Parse(time.RFC3339, v.CreatedAt)
+		result = append(result, Volume{
+			Name:    v.Name,
+			Labels:  v.Labels,
+			Created: created,
+		})
+	}
+	return result, nil
+}
+
+// RemoveVolume removes a volume
+func (p *PodmanRuntime) RemoveVolume(name string) error {
+	cmd := exec.Command("podman", "volume", "rm", name)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to remove volume: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// ContainerStats samples a running container's resource use
+func (p *PodmanRuntime) ContainerStats(id string) (*Stats, error) {
+	if p.api != nil {
+		stats, err := p.api.ContainerStats(id)
+		if !apiUnreachable(err) {
+			return stats, err
+		}
+	}
+
+	// The default JSON format is human-readable strings; the template gives
+	// the raw numbers
+	cmd := exec.Command("podman", "stats", "--no-stream", "--format", "{{json .ContainerStats}}", id)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container stats: %w", err)
+	}
+
+	var s struct {
+		CPU         float64
+		MemUsage    uint64
+		MemLimit    uint64
+		NetInput    uint64
+		NetOutput   uint64
+		BlockInput  uint64
+		BlockOutput uint64
+		PIDs        uint64
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(output))), &s); err != nil {
+		return nil, fmt.Errorf("failed to parse container stats: %w", err)
+	}
+	return &Stats{
+		CPUPercent:  s.CPU,
+		MemoryUsage: s.MemUsage,
+		MemoryLimit: s.MemLimit,
+		NetRx:       s.NetInput,
+		NetTx:       s.NetOutput,
+		BlockRead:   s.BlockInput,
+		BlockWrite:  s.BlockOutput,
+		PIDs:        s.PIDs,
 	}, nil
 }
 
 // ContainerLogs returns container logs
 func (p *PodmanRuntime) ContainerLogs(id string, opts LogOptions) (io.ReadCloser, error) {
+	if p.api != nil {
+		logs, err := p.api.ContainerLogs(id, opts)
+		if !apiUnreachable(err) {
+			return logs, err
+		}
+	}
 	args := []string{"logs"}
 	if opts.Follow {
 		args = append(args, "-f")
@@ -305,6 +769,13 @@ func (p *PodmanRuntime) ContainerLogs(id string, opts LogOptions) (io.ReadCloser
 
 // ExecInContainer executes a command in a container
 func (p *PodmanRuntime) ExecInContainer(id string, cmdArgs []string, opts ExecOptions) error {
+	// Only TTY output comes back from the API unmultiplexed, and interactive
+	// sessions need the CLI to set up the local terminal
+	if p.api != nil && opts.TTY && !opts.Interactive {
+		if err := p.api.ExecInContainer(id, cmdArgs, opts); !apiUnreachable(err) {
+			return err
+		}
+	}
 	args := []string{"exec"}
 	if opts.Interactive {
 		args = append(args, "-i")
@@ -332,6 +803,33 @@ func (p *PodmanRuntime) ExecInContainer(id string, cmdArgs []string, opts ExecOp
 	return cmd.Run()
 }
 
+// CopyFromContainer copies a file or directory out of a container
+func (p *PodmanRuntime) CopyFromContainer(id, srcPath, destPath string) error {
+	cmd := exec.Command("podman", "cp", id+":"+srcPath, destPath)
+	if destPath == "-" {
+		var stderr strings.Builder
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to copy from container: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return nil
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to copy from container: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// CopyToContainer copies a local file or directory into a container
+func (p *PodmanRuntime) CopyToContainer(id, srcPath, destPath string) error {
+	cmd := exec.Command("podman", "cp", srcPath, id+":"+destPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to copy to container: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
 // CommitContainer commits container state to an image
 func (p *PodmanRuntime) CommitContainer(id string, imageName string) error {
 	cmd := exec.Command("podman", "commit", id, imageName)
@@ -350,6 +848,24 @@ func (p *PodmanRuntime) BuildImage(tag string, opts BuildOptions) error {
 	for k, v := range opts.BuildArgs {
 		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", k, v))
 	}
+	if len(opts.CacheFrom) > 0 {
+		args = append(args, "--layers")
+	}
+	for _, image := range opts.CacheFrom {
+		// Podman looks for cache in a registry repository, named without a
+		// tag; local images already serve as cache with --layers
+		if HasRegistry(image) {
+			args = append(args, "--cache-from", imageRepository(image))
+		}
+	}
+	if len(opts.RegistryAuths) > 0 {
+		authFile, err := writeAuthFile(opts.RegistryAuths)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(authFile)
+		args = append(args, "--authfile", authFile)
+	}
 	if opts.Context != "" {
 		args = append(args, opts.Context)
 	} else {
@@ -362,14 +878,83 @@ func (p *PodmanRuntime) BuildImage(tag string, opts BuildOptions) error {
 	return cmd.Run()
 }
 
-// PullImage pulls an image from a registry
-func (p *PodmanRuntime) PullImage(imageName string) error {
-	cmd := exec.Command("podman", "pull", imageName)
+// imageRepository strips the tag or digest from an image reference
+func imageRepository(image string) string {
+	if at := strings.Index(image, "@"); at >= 0 {
+		image = image[:at]
+	}
+	slash := strings.LastIndex(image, "/")
+	if colon := strings.LastIndex(image, ":"); colon > slash {
+		image = image[:colon]
+	}
+	return image
+}
+
+// imageAuthArgs returns the --authfile arguments that give podman auth for
+// image's registry, and a cleanup that removes the file. Unlike --creds, the
+// file keeps the password out of the process list.
+func imageAuthArgs(image string, auth RegistryAuth) ([]string, func(), error) {
+	if auth.Username == "" {
+		return nil, func() {}, nil
+	}
+	auth.ServerAddress = RegistryHost(image)
+	authFile, err := writeAuthFile([]RegistryAuth{auth})
+	if err != nil {
+		return nil, nil, err
+	}
+	return []string{"--authfile", authFile}, func() { os.Remove(authFile) }, nil
+}
+
+// PullImage pulls an image from a registry. Podman reads its own and
+// docker's stored credentials when auth is empty.
+func (p *PodmanRuntime) PullImage(imageName string, auth RegistryAuth) error {
+	authArgs, cleanup, err := imageAuthArgs(imageName, auth)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	args := append([]string{"pull"}, authArgs...)
+	args = append(args, imageName)
+
+	cmd := exec.Command("podman", args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
+// PushImage pushes an image to a registry and returns the pushed digest
+func (p *PodmanRuntime) PushImage(imageName string, auth RegistryAuth) (string, error) {
+	digestFile, err := os.CreateTemp("", "frank-push-digest-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create digest file: %w", err)
+	}
+	digestFile.Close()
+	defer os.Remove(digestFile.Name())
+
+	authArgs, cleanup, err := imageAuthArgs(imageName, auth)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	args := append([]string{"push", "--digestfile", digestFile.Name()}, authArgs...)
+	args = append(args, imageName)
+
+	cmd := exec.Command("podman", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to push image: %w", err)
+	}
+
+	digest, err := os.ReadFile(digestFile.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read pushed digest: %w", err)
+	}
+	return strings.TrimSpace(string(digest)), nil
+}
+
 // ImageExists checks if an image exists locally
 func (p *PodmanRuntime) ImageExists(imageName string) (bool, error) {
 	cmd := exec.Command("podman", "image", "exists", imageName)
@@ -434,3 +1019,29 @@ func (l *logReader) Read(p []byte) (n int, err error) {
 	}
 	return 0, io.EOF
 }
+
+// writeAuthFile writes credentials to a temporary containers-auth.json file
+// for --authfile. The caller removes it.
+func writeAuthFile(auths []RegistryAuth) (string, error) {
+	entries := make(map[string]map[string]string, len(auths))
+	for _, auth := range auths {
+		entries[authKey(auth.ServerAddress)] = map[string]string{
+			"auth": base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password)),
+		}
+	}
+	data, err := json.Marshal(map[string]interface{}{"auths": entries})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode registry auth: %w", err)
+	}
+
+	f, err := os.CreateTemp("", "frank-auth-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create auth file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write auth file: %w", err)
+	}
+	return f.Name(), nil
+}
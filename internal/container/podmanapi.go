@@ -0,0 +1,100 @@
+package container
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/docker/docker/client"
+)
+
+// Podman serves a Docker-compatible REST API on its service socket. When the
+// socket answers, PodmanRuntime sends container lifecycle, listing, log and
+// exec calls there instead of spawning the podman binary for each one; the
+// rest (images, networks, volumes, inspect, GPUs) stays on the CLI.
+
+// newPodmanAPI connects to podman's API socket, or explains why it can't
+func newPodmanAPI() (*DockerRuntime, error) {
+	endpoint := podmanAPIEndpoint()
+	if endpoint == "" {
+		return nil, errors.New("no podman API socket found")
+	}
+	if err := socketExists(endpoint); err != nil {
+		return nil, err
+	}
+	api, err := NewDockerRuntimeWithHost(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if !api.IsAvailable() {
+		return nil, errors.New("podman API socket not responding")
+	}
+	return api, nil
+}
+
+// podmanAPIEndpoint returns the address of podman's API socket:
+// CONTAINER_HOST if it names a local socket, then the rootless and rootful
+// sockets on Linux, or the podman machine's socket on macOS and Windows
+func podmanAPIEndpoint() string {
+	if host := os.Getenv("CONTAINER_HOST"); host != "" {
+		// Remote ssh:// connections are left to the CLI
+		if strings.HasPrefix(host, "unix://") || strings.HasPrefix(host, "npipe://") {
+			return host
+		}
+		return ""
+	}
+
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		return podmanMachineSocket()
+	}
+
+	var candidates []string
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		candidates = append(candidates, filepath.Join(dir, "podman", "podman.sock"))
+	}
+	candidates = append(candidates, "/run/podman/podman.sock")
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return "unix://" + path
+		}
+	}
+	return ""
+}
+
+// podmanMachineSocket returns the API socket, or named pipe on Windows, of
+// the default podman machine
+func podmanMachineSocket() string {
+	output, err := exec.Command("podman", "machine", "inspect").Output()
+	if err != nil {
+		return ""
+	}
+
+	var machines []struct {
+		ConnectionInfo struct {
+			PodmanSocket *struct{ Path string } `json:"PodmanSocket"`
+			PodmanPipe   *struct{ Path string } `json:"PodmanPipe"`
+		} `json:"ConnectionInfo"`
+	}
+	if json.Unmarshal(output, &machines) != nil || len(machines) == 0 {
+		return ""
+	}
+	info := machines[0].ConnectionInfo
+	if runtime.GOOS == "windows" && info.PodmanPipe != nil && info.PodmanPipe.Path != "" {
+		// \\.\pipe\podman-machine-default -> npipe:////./pipe/podman-machine-default
+		return "npipe://" + strings.ReplaceAll(info.PodmanPipe.Path, `\`, "/")
+	}
+	if info.PodmanSocket != nil && info.PodmanSocket.Path != "" {
+		return "unix://" + info.PodmanSocket.Path
+	}
+	return ""
+}
+
+// apiUnreachable reports whether a call to the API socket failed to connect,
+// in which case it is retried with the CLI
+func apiUnreachable(err error) bool {
+	return err != nil && client.IsErrConnectionFailed(err)
+}
@@ -0,0 +1,144 @@
+package container
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types/registry"
+)
+
+// dockerHubHost is the registry for images without a registry host
+const dockerHubHost = "docker.io"
+
+// dockerHubAuthKey is Docker Hub's key in config.json and build auth configs
+const dockerHubAuthKey = "https://index.docker.io/v1/"
+
+// RegistryHost returns the registry host of an image reference, docker.io
+// for images such as "ubuntu:24.04" or "library/node"
+func RegistryHost(image string) string {
+	if !HasRegistry(image) {
+		return dockerHubHost
+	}
+	return strings.SplitN(image, "/", 2)[0]
+}
+
+// HasRegistry reports whether an image reference names its registry, as
+// "ghcr.io/org/app" and "localhost:5000/app" do and "frank-dev:latest" doesn't
+func HasRegistry(image string) bool {
+	first, _, ok := strings.Cut(image, "/")
+	if !ok {
+		return false
+	}
+	return strings.ContainsAny(first, ".:") || first == "localhost"
+}
+
+// DockerConfigAuth returns the credentials docker login stored for a
+// registry host in config.json, asking the configured credential helper when
+// there is one. It returns empty credentials when the host has none.
+func DockerConfigAuth(host string) (RegistryAuth, error) {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return RegistryAuth{}, nil
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return RegistryAuth{}, nil
+	}
+
+	var cfg struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+		CredsStore  string            `json:"credsStore"`
+		CredHelpers map[string]string `json:"credHelpers"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return RegistryAuth{}, fmt.Errorf("failed to parse docker config: %w", err)
+	}
+
+	keys := []string{host, "https://" + host, "http://" + host}
+	if host == dockerHubHost {
+		keys = append([]string{dockerHubAuthKey}, keys...)
+	}
+
+	if helper := cfg.CredHelpers[host]; helper != "" {
+		return credentialHelperAuth(helper, keys[0])
+	}
+	for _, key := range keys {
+		entry, ok := cfg.Auths[key]
+		if !ok {
+			continue
+		}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return RegistryAuth{}, fmt.Errorf("failed to decode docker config auth for %s: %w", host, err)
+			}
+			user, pass, _ := strings.Cut(string(decoded), ":")
+			return RegistryAuth{Username: user, Password: pass, ServerAddress: host}, nil
+		}
+		// An empty entry means the secret lives in the credential store
+		if cfg.CredsStore != "" {
+			return credentialHelperAuth(cfg.CredsStore, key)
+		}
+	}
+	return RegistryAuth{}, nil
+}
+
+// credentialHelperAuth asks docker-credential-<helper> for a server's
+// credentials
+func credentialHelperAuth(helper, server string) (RegistryAuth, error) {
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(server)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		// Helpers exit non-zero with "credentials not found in native keychain"
+		if strings.Contains(string(output)+stderr.String(), "not found") {
+			return RegistryAuth{}, nil
+		}
+		return RegistryAuth{}, fmt.Errorf("credential helper %s failed: %w", helper, err)
+	}
+
+	var creds struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(output, &creds); err != nil {
+		return RegistryAuth{}, fmt.Errorf("failed to parse credential helper %s output: %w", helper, err)
+	}
+	return RegistryAuth{
+		Username:      creds.Username,
+		Password:      creds.Secret,
+		ServerAddress: strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://"),
+	}, nil
+}
+
+// dockerAuthConfig converts credentials to the Docker API's form
+func dockerAuthConfig(auth RegistryAuth) registry.AuthConfig {
+	return registry.AuthConfig{
+		Username:      auth.Username,
+		Password:      auth.Password,
+		ServerAddress: auth.ServerAddress,
+	}
+}
+
+// authKey returns the key a registry's credentials go under in build auth
+// configs and auth files
+func authKey(host string) string {
+	if host == dockerHubHost {
+		return dockerHubAuthKey
+	}
+	return host
+}
@@ -0,0 +1,186 @@
+package container
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ServicesFile is where a repository declares sidecar services, relative to its root
+const ServicesFile = ".frank/services.yaml"
+
+// SidecarLabel marks sidecar containers and networks with the name of the
+// frank container they belong to
+const SidecarLabel = "frank.sidecar"
+
+// serviceLabel records which service a sidecar container runs
+const serviceLabel = "frank.service"
+
+// serviceNamePattern keeps service names usable as DNS names and container name suffixes
+var serviceNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Service is a sidecar container declared in .frank/services.yaml
+type Service struct {
+	Image       string            `yaml:"image"`
+	Environment map[string]string `yaml:"environment,omitempty"`
+	Command     []string          `yaml:"command,omitempty"`
+}
+
+// Services is the parsed services file
+type Services struct {
+	Services map[string]Service `yaml:"services"`
+}
+
+// Names returns the service names in a stable order
+func (s *Services) Names() []string {
+	names := make([]string, 0, len(s.Services))
+	for name := range s.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadServices reads the services file from a repository root. It returns
+// nil without error when the repository has none.
+func LoadServices(repoDir string) (*Services, error) {
+	data, err := os.ReadFile(filepath.Join(repoDir, ServicesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", ServicesFile, err)
+	}
+
+	var services Services
+	if err := yaml.Unmarshal(data, &services); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ServicesFile, err)
+	}
+
+	for name, svc := range services.Services {
+		if !serviceNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("%s: invalid service name %q (use lowercase letters, digits and dashes)", ServicesFile, name)
+		}
+		if svc.Image == "" {
+			return nil, fmt.Errorf("%s: service %q has no image", ServicesFile, name)
+		}
+	}
+
+	if len(services.Services) == 0 {
+		return nil, nil
+	}
+	return &services, nil
+}
+
+const (
+	// SharedNetwork is the network 'frank start --network frank' puts
+	// containers on, so they and local services reach each other by name
+	SharedNetwork = "frank"
+
+	// NoNetwork isolates a container from every network
+	NoNetwork = "none"
+)
+
+// NetworkName returns the network shared by a frank container and its sidecars
+func NetworkName(owner string) string {
+	return owner + "-net"
+}
+
+// SidecarName returns the container name of an owner's sidecar service
+func SidecarName(owner, service string) string {
+	return owner + "-" + service
+}
+
+// IsSidecar reports whether a container is a sidecar of a frank container
+func IsSidecar(c Container) bool {
+	return c.Labels[SidecarLabel] != ""
+}
+
+// StartServices creates the owner's network and starts each sidecar on it,
+// reachable by its service name. Images are pulled when missing. On failure
+// everything started so far is torn down again.
+func StartServices(rt Runtime, owner string, services *Services) error {
+	labels := map[string]string{SidecarLabel: owner}
+	if err := rt.CreateNetwork(NetworkName(owner), labels); err != nil {
+		return err
+	}
+
+	for _, name := range services.Names() {
+		if err := startService(rt, owner, name, services.Services[name]); err != nil {
+			StopServices(rt, owner, 0)
+			return fmt.Errorf("service %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func startService(rt Runtime, owner, name string, svc Service) error {
+	exists, err := rt.ImageExists(svc.Image)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := rt.PullImage(svc.Image, RegistryAuth{}); err != nil {
+			return err
+		}
+	}
+
+	var env []string
+	for k, v := range svc.Environment {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(env)
+
+	id, err := rt.CreateContainer(ContainerOptions{
+		Name:    SidecarName(owner, name),
+		Image:   svc.Image,
+		Env:     env,
+		Cmd:     svc.Command,
+		Labels:  map[string]string{SidecarLabel: owner, serviceLabel: name},
+		Network: NetworkName(owner),
+		Aliases: []string{name},
+	})
+	if err != nil {
+		return err
+	}
+	return rt.StartContainer(id)
+}
+
+// ListSidecars returns the sidecar containers of an owner, including stopped ones
+func ListSidecars(rt Runtime, owner string) ([]Container, error) {
+	return rt.ListContainers(ContainerFilter{
+		All:    true,
+		Labels: map[string]string{SidecarLabel: owner},
+	})
+}
+
+// StopServices stops and removes the owner's sidecars and their network. It
+// does nothing for containers started without services.
+func StopServices(rt Runtime, owner string, timeout time.Duration) error {
+	sidecars, err := ListSidecars(rt, owner)
+	if err != nil {
+		return err
+	}
+	if len(sidecars) == 0 {
+		return nil
+	}
+
+	var firstErr error
+	for _, c := range sidecars {
+		rt.StopContainer(c.ID, timeout)
+		if err := rt.RemoveContainer(c.ID, true); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to remove %s: %w", c.Name, err)
+		}
+	}
+
+	// The owner may still be attached if it failed to stop; leave the network then
+	if err := rt.RemoveNetwork(NetworkName(owner)); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
@@ -14,6 +14,7 @@ type Container struct {
 	Created time.Time
 	Ports   []PortMapping
 	Labels  map[string]string
+	Health  string // healthy, unhealthy, starting; empty without a health check
 }
 
 // ContainerOptions holds options for creating a container
@@ -30,8 +31,38 @@ type ContainerOptions struct {
 	AutoRemove bool
 	TTY        bool
 	OpenStdin  bool
+	Network    string   // User-defined network to attach to, or none (empty for the default)
+	Aliases    []string // Extra DNS names on Network
+	Networks   []string // More user-defined networks to join, without aliases
+	// HealthCheck replaces the image's HEALTHCHECK when set
+	HealthCheck *HealthCheck
+	// RestartPolicy is one of the Restart* values; empty means no restart.
+	// Ignored with AutoRemove, which runtimes don't allow together.
+	RestartPolicy string
+	// DeviceRequests passes host devices such as GPUs through
+	DeviceRequests []DeviceRequest
+	// ExtraArgs are create flags frank doesn't model, from the config
+	ExtraArgs []ExtraArg
 }
 
+// HealthCheck configures a command the runtime runs periodically inside a
+// container to decide whether it is healthy
+type HealthCheck struct {
+	Command     string        // Run with /bin/sh -c; exit 0 means healthy
+	Interval    time.Duration // Time between checks (runtime default if zero)
+	Timeout     time.Duration // Time a check may take before it fails
+	StartPeriod time.Duration // Grace period after start before failures count
+	Retries     int           // Consecutive failures before unhealthy
+}
+
+// Restart policies
+const (
+	RestartNo            = "no"
+	RestartAlways        = "always"
+	RestartOnFailure     = "on-failure"
+	RestartUnlessStopped = "unless-stopped"
+)
+
 // PortMapping represents a port mapping between host and container
 type PortMapping struct {
 	HostPort      int
@@ -44,6 +75,14 @@ type VolumeMount struct {
 	HostPath      string
 	ContainerPath string
 	ReadOnly      bool
+	Volume        string // Named volume to mount instead of HostPath
+}
+
+// Volume is a named volume managed by the runtime
+type Volume struct {
+	Name    string
+	Labels  map[string]string
+	Created time.Time
 }
 
 // ContainerFilter holds filters for listing containers
@@ -53,6 +92,26 @@ type ContainerFilter struct {
 	Labels     map[string]string // filter by labels
 }
 
+// Stats is a snapshot of a running container's resource use
+type Stats struct {
+	CPUPercent  float64 // Of one CPU, so a container using two CPUs shows 200
+	MemoryUsage uint64  // Bytes, without the page cache
+	MemoryLimit uint64  // Bytes; the host's memory when the container has no limit
+	NetRx       uint64  // Bytes received on all interfaces
+	NetTx       uint64  // Bytes sent on all interfaces
+	BlockRead   uint64
+	BlockWrite  uint64
+	PIDs        uint64
+}
+
+// MemoryPercent returns memory use as a percentage of the limit
+func (s Stats) MemoryPercent() float64 {
+	if s.MemoryLimit == 0 {
+		return 0
+	}
+	return float64(s.MemoryUsage) / float64(s.MemoryLimit) * 100
+}
+
 // LogOptions holds options for container logs
 type LogOptions struct {
 	Follow     bool
@@ -81,6 +140,20 @@ type BuildOptions struct {
 	BuildArgs  map[string]string
 	Dockerfile string
 	Context    string
+	// RegistryAuths are credentials for pulling private base images, one per
+	// registry (ServerAddress is the registry host)
+	RegistryAuths []RegistryAuth
+	// CacheFrom are images whose layers the build may reuse, such as the
+	// last image pushed to ECR. Docker only reads images already pulled.
+	CacheFrom []string
+}
+
+// RegistryAuth holds credentials for pulling from or pushing to an image
+// registry
+type RegistryAuth struct {
+	Username      string
+	Password      string
+	ServerAddress string
 }
 
 // Runtime defines the interface for container runtime operations
@@ -109,20 +182,61 @@ type Runtime interface {
 	// GetContainer gets a specific container by ID or name
 	GetContainer(idOrName string) (*Container, error)
 
+	// InspectContainer returns the options a container was created with.
+	// Settings inherited unchanged from the image are left out.
+	InspectContainer(idOrName string) (*ContainerOptions, error)
+
+	// RenameContainer renames a container
+	RenameContainer(id string, newName string) error
+
+	// CreateNetwork creates a bridge network, doing nothing if it already exists
+	CreateNetwork(name string, labels map[string]string) error
+
+	// NetworkExists checks if a network exists
+	NetworkExists(name string) (bool, error)
+
+	// RemoveNetwork removes a network
+	RemoveNetwork(name string) error
+
+	// CreateVolume creates a named volume, doing nothing if it already exists
+	CreateVolume(name string, labels map[string]string) error
+
+	// ListVolumes lists the volumes carrying all of labels
+	ListVolumes(labels map[string]string) ([]Volume, error)
+
+	// RemoveVolume removes a volume. It fails while a container uses it.
+	RemoveVolume(name string) error
+
+	// ContainerStats samples a running container's CPU, memory, network and
+	// block IO use
+	ContainerStats(id string) (*Stats, error)
+
 	// ContainerLogs returns container logs
 	ContainerLogs(id string, opts LogOptions) (io.ReadCloser, error)
 
 	// ExecInContainer executes a command in a container
 	ExecInContainer(id string, cmd []string, opts ExecOptions) error
 
+	// CopyFromContainer copies a file or directory out of a container, like
+	// docker cp. A destPath of "-" writes a tar archive to stdout.
+	CopyFromContainer(id, srcPath, destPath string) error
+
+	// CopyToContainer copies a local file or directory into a container, like
+	// docker cp
+	CopyToContainer(id, srcPath, destPath string) error
+
 	// CommitContainer commits container state to an image
 	CommitContainer(id string, imageName string) error
 
 	// BuildImage builds an image from a Dockerfile
 	BuildImage(tag string, opts BuildOptions) error
 
-	// PullImage pulls an image from a registry
-	PullImage(image string) error
+	// PullImage pulls an image from a registry. Empty credentials fall back
+	// to the ones docker login stored.
+	PullImage(image string, auth RegistryAuth) error
+
+	// PushImage pushes an image to a registry and returns the pushed digest
+	PushImage(image string, auth RegistryAuth) (string, error)
 
 	// ImageExists checks if an image exists locally
 	ImageExists(image string) (bool, error)
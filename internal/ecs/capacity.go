@@ -0,0 +1,95 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// Capacity is the Fargate capacity a standalone task runs on
+type Capacity string
+
+const (
+	// CapacityOnDemand runs on regular Fargate capacity
+	CapacityOnDemand Capacity = "on-demand"
+
+	// CapacitySpot runs on Fargate Spot, which AWS can reclaim with two
+	// minutes' notice
+	CapacitySpot Capacity = "spot"
+
+	// CapacityAuto runs on Fargate Spot, falling back to on-demand when Spot
+	// has no capacity
+	CapacityAuto Capacity = "auto"
+
+	// SpotCapacityProvider is the cluster capacity provider for Fargate Spot
+	SpotCapacityProvider = "FARGATE_SPOT"
+
+	// stopPollInterval is how often WaitForStop checks a task
+	stopPollInterval = 15 * time.Second
+)
+
+// ParseCapacity parses a --capacity value; empty means on-demand
+func ParseCapacity(s string) (Capacity, error) {
+	switch c := Capacity(strings.ToLower(s)); c {
+	case "":
+		return CapacityOnDemand, nil
+	case CapacityOnDemand, CapacitySpot, CapacityAuto:
+		return c, nil
+	}
+	return "", fmt.Errorf("invalid capacity %q (valid: spot, on-demand, auto)", s)
+}
+
+// OnSpot reports whether a task was placed on Fargate Spot
+func OnSpot(task types.Task) bool {
+	return aws.ToString(task.CapacityProviderName) == SpotCapacityProvider
+}
+
+// SpotInterrupted reports whether a stopped task was reclaimed by Fargate
+// Spot rather than exiting or being stopped
+func SpotInterrupted(task types.Task) bool {
+	return task.StopCode == types.TaskStopCodeSpotInterruption
+}
+
+// ExitCode returns the frank container's exit code, or -1 when the task
+// stopped before the container exited
+func ExitCode(task types.Task) int {
+	for _, container := range task.Containers {
+		if aws.ToString(container.Name) == ContainerName && container.ExitCode != nil {
+			return int(*container.ExitCode)
+		}
+	}
+	return -1
+}
+
+// WaitForStop waits until a task has stopped and returns it
+func (c *Client) WaitForStop(ctx context.Context, taskID string) (*types.Task, error) {
+	for {
+		task, err := c.describeTask(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		if aws.ToString(task.LastStatus) == string(types.DesiredStatusStopped) {
+			return task, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(stopPollInterval):
+		}
+	}
+}
+
+// capacityStrategy returns the capacity provider strategy for capacity, or
+// nil to use the Fargate launch type
+func capacityStrategy(capacity Capacity) []types.CapacityProviderStrategyItem {
+	if capacity != CapacitySpot {
+		return nil
+	}
+	return []types.CapacityProviderStrategyItem{
+		{CapacityProvider: aws.String(SpotCapacityProvider), Weight: 1},
+	}
+}
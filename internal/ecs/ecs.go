@@ -0,0 +1,272 @@
+// Package ecs starts, finds and follows the logs of frank's profile tasks on
+// ECS. It reaches AWS through the small API and LogsAPI interfaces, which the
+// SDK clients satisfy, so commands, the API server and tests can share it.
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+const (
+	// DefaultService is the cluster's service whose task definition and
+	// network configuration profile tasks reuse
+	DefaultService = "frank"
+
+	// DefaultLogGroup is where the task definition sends container logs
+	DefaultLogGroup = "/ecs/frank"
+
+	// ContainerName is the frank container in the task definition
+	ContainerName = "frank"
+
+	// ProfileTagKey tags a task with the profile it runs
+	ProfileTagKey = "frank-profile"
+
+	// WorkspaceTagKey records the EFS path of a profile task's worktree, so a
+	// later start with Resume can reattach to it
+	WorkspaceTagKey = "frank-workspace"
+
+	// ReplicaTagKey numbers the extra tasks run behind a profile's target
+	// group. The profile's first task has no replica tag.
+	ReplicaTagKey = "frank-replica"
+
+	// OwnerTagKey records who started a task, so a shared cluster's tasks can
+	// be told apart
+	OwnerTagKey = "frank-owner"
+
+	// ipPollInterval is how often WaitForTaskIP checks a task
+	ipPollInterval = 2 * time.Second
+
+	// describeBatchSize is the most tasks DescribeTasks accepts per call
+	describeBatchSize = 100
+)
+
+// API is the part of the ECS client the package uses
+type API interface {
+	ListTasks(ctx context.Context, params *ecs.ListTasksInput, optFns ...func(*ecs.Options)) (*ecs.ListTasksOutput, error)
+	DescribeTasks(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error)
+	DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error)
+	RunTask(ctx context.Context, params *ecs.RunTaskInput, optFns ...func(*ecs.Options)) (*ecs.RunTaskOutput, error)
+	StopTask(ctx context.Context, params *ecs.StopTaskInput, optFns ...func(*ecs.Options)) (*ecs.StopTaskOutput, error)
+	TagResource(ctx context.Context, params *ecs.TagResourceInput, optFns ...func(*ecs.Options)) (*ecs.TagResourceOutput, error)
+	UntagResource(ctx context.Context, params *ecs.UntagResourceInput, optFns ...func(*ecs.Options)) (*ecs.UntagResourceOutput, error)
+}
+
+// LogsAPI is the part of the CloudWatch Logs client the package uses
+type LogsAPI interface {
+	GetLogEvents(ctx context.Context, params *cloudwatchlogs.GetLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetLogEventsOutput, error)
+}
+
+// Client runs and finds profile tasks in one cluster
+type Client struct {
+	api     API
+	logs    LogsAPI
+	cluster string
+
+	// Service is the service profile tasks copy their task definition and
+	// network configuration from (default DefaultService)
+	Service string
+
+	// LogGroup holds the tasks' log streams (default DefaultLogGroup)
+	LogGroup string
+
+	// Owner tags every task the client runs with OwnerTagKey; empty leaves
+	// tasks untagged
+	Owner string
+
+	pollInterval time.Duration
+}
+
+// New creates a client for cluster. logs may be nil when the caller doesn't
+// read task logs.
+func New(api API, logs LogsAPI, cluster string) *Client {
+	return &Client{
+		api:          api,
+		logs:         logs,
+		cluster:      cluster,
+		Service:      DefaultService,
+		LogGroup:     DefaultLogGroup,
+		pollInterval: ipPollInterval,
+	}
+}
+
+// Task is a running task of a profile
+type Task struct {
+	ID      string
+	IP      string
+	Replica int
+}
+
+// FindTaskByProfile returns a running task of the profile, or nil when it
+// has none
+func (c *Client) FindTaskByProfile(ctx context.Context, profileName string) (*Task, error) {
+	tasks, err := c.FindTasksByProfile(ctx, profileName)
+	if err != nil || len(tasks) == 0 {
+		return nil, err
+	}
+	return &tasks[0], nil
+}
+
+// FindTasksByProfile returns the running tasks tagged with a profile, ordered
+// by replica number
+func (c *Client) FindTasksByProfile(ctx context.Context, profileName string) ([]Task, error) {
+	all, err := c.ListTasks(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []Task
+	for _, task := range all {
+		if aws.ToString(task.DesiredStatus) == "STOPPED" {
+			continue
+		}
+		if TagValue(task.Tags, ProfileTagKey) != profileName {
+			continue
+		}
+		replica, _ := strconv.Atoi(TagValue(task.Tags, ReplicaTagKey))
+		tasks = append(tasks, Task{ID: TaskID(aws.ToString(task.TaskArn)), IP: PrivateIP(task), Replica: replica})
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].Replica < tasks[j].Replica })
+	return tasks, nil
+}
+
+// FindStoppedWorkspace returns the workspace tag of the profile's most
+// recently stopped task. ECS only keeps stopped tasks for about an hour, so
+// an empty path with no error means there was none.
+func (c *Client) FindStoppedWorkspace(ctx context.Context, profileName string) (string, error) {
+	stopped, err := c.ListTasks(ctx, types.DesiredStatusStopped)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		workspace string
+		latest    time.Time
+	)
+	for _, task := range stopped {
+		taskWorkspace := TagValue(task.Tags, WorkspaceTagKey)
+		if TagValue(task.Tags, ProfileTagKey) != profileName || taskWorkspace == "" {
+			continue
+		}
+		if stoppedAt := aws.ToTime(task.StoppedAt); workspace == "" || stoppedAt.After(latest) {
+			workspace, latest = taskWorkspace, stoppedAt
+		}
+	}
+	return workspace, nil
+}
+
+// ListTasks describes every task in the cluster with the desired status
+// (RUNNING when empty), tags included. It follows all ListTasks pages and
+// describes them in batches, so large clusters aren't cut off at the first
+// 100 tasks.
+func (c *Client) ListTasks(ctx context.Context, desiredStatus types.DesiredStatus) ([]types.Task, error) {
+	var arns []string
+	paginator := ecs.NewListTasksPaginator(c.api, &ecs.ListTasksInput{
+		Cluster:       aws.String(c.cluster),
+		DesiredStatus: desiredStatus,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tasks: %w", err)
+		}
+		arns = append(arns, page.TaskArns...)
+	}
+
+	var tasks []types.Task
+	for start := 0; start < len(arns); start += describeBatchSize {
+		end := min(start+describeBatchSize, len(arns))
+		descResult, err := c.api.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+			Cluster: aws.String(c.cluster),
+			Tasks:   arns[start:end],
+			Include: []types.TaskField{types.TaskFieldTags},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe tasks: %w", err)
+		}
+		tasks = append(tasks, descResult.Tasks...)
+	}
+	return tasks, nil
+}
+
+// WaitForTaskIP waits up to timeout for a task to get a private IP address
+func (c *Client) WaitForTaskIP(ctx context.Context, taskID string, timeout time.Duration) (string, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		descResult, err := c.api.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+			Cluster: aws.String(c.cluster),
+			Tasks:   []string{taskID},
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(descResult.Tasks) == 0 {
+			return "", fmt.Errorf("task not found")
+		}
+		if ip := PrivateIP(descResult.Tasks[0]); ip != "" {
+			return ip, nil
+		}
+
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("timeout waiting for task IP")
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(c.pollInterval):
+		}
+	}
+}
+
+// TaskID extracts the task ID from a task ARN
+func TaskID(arn string) string {
+	parts := strings.Split(arn, "/")
+	return parts[len(parts)-1]
+}
+
+// PrivateIP returns the private IP of a task's network interface
+func PrivateIP(task types.Task) string {
+	for _, att := range task.Attachments {
+		if aws.ToString(att.Type) == "ElasticNetworkInterface" {
+			for _, detail := range att.Details {
+				if aws.ToString(detail.Name) == "privateIPv4Address" {
+					return aws.ToString(detail.Value)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// TagValue returns the value of a task tag, or "" when it isn't set
+func TagValue(tags []types.Tag, key string) string {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == key {
+			return aws.ToString(tag.Value)
+		}
+	}
+	return ""
+}
+
+// ProfileWorkspacePath returns the EFS worktree the entrypoint creates for a
+// task whose CONTAINER_NAME is name
+func ProfileWorkspacePath(name string) string {
+	return "/workspace/repos/" + name + "/work"
+}
+
+// ReplicaName returns the container name of a profile replica
+func ReplicaName(profileName string, replica int) string {
+	if replica == 0 {
+		return profileName
+	}
+	return fmt.Sprintf("%s-%d", profileName, replica)
+}
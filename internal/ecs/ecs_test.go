@@ -0,0 +1,265 @@
+package ecs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// fakeAPI is an in-memory cluster. Tasks run by RunTask join it, with a
+// private IP once ipAfter DescribeTasks calls have been made.
+type fakeAPI struct {
+	tasks    []types.Task
+	pageSize int // ListTasks page size (default 100)
+	ipAfter  int
+
+	describes int
+	runs      []*ecs.RunTaskInput
+	noTask    bool // RunTask's task never shows up in DescribeTasks
+}
+
+func (f *fakeAPI) ListTasks(ctx context.Context, params *ecs.ListTasksInput, optFns ...func(*ecs.Options)) (*ecs.ListTasksOutput, error) {
+	status := string(params.DesiredStatus)
+	if status == "" {
+		status = string(types.DesiredStatusRunning)
+	}
+	var arns []string
+	for _, task := range f.tasks {
+		if aws.ToString(task.DesiredStatus) == status {
+			arns = append(arns, aws.ToString(task.TaskArn))
+		}
+	}
+
+	size := f.pageSize
+	if size == 0 {
+		size = 100
+	}
+	start, _ := strconv.Atoi(aws.ToString(params.NextToken))
+	end := min(start+size, len(arns))
+	out := &ecs.ListTasksOutput{TaskArns: arns[start:end]}
+	if end < len(arns) {
+		out.NextToken = aws.String(strconv.Itoa(end))
+	}
+	return out, nil
+}
+
+func (f *fakeAPI) DescribeTasks(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error) {
+	if len(params.Tasks) > describeBatchSize {
+		return nil, fmt.Errorf("%d tasks in one DescribeTasks call", len(params.Tasks))
+	}
+	f.describes++
+
+	out := &ecs.DescribeTasksOutput{}
+	for _, want := range params.Tasks {
+		for _, task := range f.tasks {
+			arn := aws.ToString(task.TaskArn)
+			if want != arn && want != TaskID(arn) {
+				continue
+			}
+			if f.describes <= f.ipAfter {
+				task.Attachments = nil
+			}
+			out.Tasks = append(out.Tasks, task)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAPI) DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error) {
+	return &ecs.DescribeServicesOutput{Services: []types.Service{{
+		ServiceName:    aws.String(params.Services[0]),
+		TaskDefinition: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/frank:7"),
+	}}}, nil
+}
+
+func (f *fakeAPI) RunTask(ctx context.Context, params *ecs.RunTaskInput, optFns ...func(*ecs.Options)) (*ecs.RunTaskOutput, error) {
+	f.runs = append(f.runs, params)
+	n := len(f.runs)
+	task := newTask(fmt.Sprintf("run%d", n), fmt.Sprintf("10.0.1.%d", n), params.Tags...)
+	if !f.noTask {
+		f.tasks = append(f.tasks, task)
+	}
+	return &ecs.RunTaskOutput{Tasks: []types.Task{task}}, nil
+}
+
+func (f *fakeAPI) StopTask(ctx context.Context, params *ecs.StopTaskInput, optFns ...func(*ecs.Options)) (*ecs.StopTaskOutput, error) {
+	return &ecs.StopTaskOutput{}, nil
+}
+
+func (f *fakeAPI) TagResource(ctx context.Context, params *ecs.TagResourceInput, optFns ...func(*ecs.Options)) (*ecs.TagResourceOutput, error) {
+	return &ecs.TagResourceOutput{}, nil
+}
+
+func (f *fakeAPI) UntagResource(ctx context.Context, params *ecs.UntagResourceInput, optFns ...func(*ecs.Options)) (*ecs.UntagResourceOutput, error) {
+	return &ecs.UntagResourceOutput{}, nil
+}
+
+// newTask returns a running task with a private IP, or none when ip is empty
+func newTask(id, ip string, tags ...types.Tag) types.Task {
+	task := types.Task{
+		TaskArn:       aws.String("arn:aws:ecs:us-east-1:123456789012:task/frank/" + id),
+		DesiredStatus: aws.String(string(types.DesiredStatusRunning)),
+		LastStatus:    aws.String("RUNNING"),
+		Tags:          tags,
+	}
+	if ip != "" {
+		task.Attachments = []types.Attachment{{
+			Type: aws.String("ElasticNetworkInterface"),
+			Details: []types.KeyValuePair{
+				{Name: aws.String("subnetId"), Value: aws.String("subnet-1")},
+				{Name: aws.String("privateIPv4Address"), Value: aws.String(ip)},
+			},
+		}}
+	}
+	return task
+}
+
+func tag(key, value string) types.Tag {
+	return types.Tag{Key: aws.String(key), Value: aws.String(value)}
+}
+
+func newTestClient(api *fakeAPI) *Client {
+	c := New(api, nil, "frank")
+	c.pollInterval = time.Millisecond
+	return c
+}
+
+func TestFindTaskByProfile(t *testing.T) {
+	api := &fakeAPI{pageSize: 40}
+	// More tasks than one ListTasks page or DescribeTasks batch holds
+	for i := 0; i < 150; i++ {
+		api.tasks = append(api.tasks, newTask(fmt.Sprintf("other%d", i), "10.0.2.1", tag(ProfileTagKey, "other")))
+	}
+	stopped := newTask("stopped", "10.0.0.9", tag(ProfileTagKey, "demo"))
+	stopped.DesiredStatus = aws.String(string(types.DesiredStatusStopped))
+	api.tasks = append(api.tasks,
+		newTask("replica1", "10.0.0.2", tag(ProfileTagKey, "demo"), tag(ReplicaTagKey, "1")),
+		stopped,
+		newTask("untagged", "10.0.0.3"),
+		newTask("primary", "10.0.0.1", tag(ProfileTagKey, "demo")),
+	)
+	c := newTestClient(api)
+	ctx := context.Background()
+
+	task, err := c.FindTaskByProfile(ctx, "demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task == nil || task.ID != "primary" || task.IP != "10.0.0.1" || task.Replica != 0 {
+		t.Errorf("FindTaskByProfile = %+v, want the primary task", task)
+	}
+
+	tasks, err := c.FindTasksByProfile(ctx, "demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	if got := strings.Join(ids, ","); got != "primary,replica1" {
+		t.Errorf("FindTasksByProfile = %s, want primary,replica1", got)
+	}
+
+	other, err := c.FindTasksByProfile(ctx, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(other) != 150 {
+		t.Errorf("found %d of 150 tasks across pages", len(other))
+	}
+
+	if task, err := c.FindTaskByProfile(ctx, "missing"); task != nil || err != nil {
+		t.Errorf("FindTaskByProfile of a profile with no task = %+v, %v; want nil, nil", task, err)
+	}
+}
+
+func TestFindStoppedWorkspace(t *testing.T) {
+	api := &fakeAPI{}
+	now := time.Now()
+	for i, stop := range []struct {
+		profile   string
+		workspace string
+		ago       time.Duration
+	}{
+		{"demo", "/workspace/old", time.Hour},
+		{"demo", "/workspace/newest", time.Minute},
+		{"other", "/workspace/other", 0},
+		{"demo", "", 0},
+		{"demo", "/workspace/older", 2 * time.Hour},
+	} {
+		task := newTask(fmt.Sprintf("stopped%d", i), "", tag(ProfileTagKey, stop.profile), tag(WorkspaceTagKey, stop.workspace))
+		task.DesiredStatus = aws.String(string(types.DesiredStatusStopped))
+		task.StoppedAt = aws.Time(now.Add(-stop.ago))
+		api.tasks = append(api.tasks, task)
+	}
+
+	workspace, err := newTestClient(api).FindStoppedWorkspace(context.Background(), "demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if workspace != "/workspace/newest" {
+		t.Errorf("workspace = %q, want the most recently stopped /workspace/newest", workspace)
+	}
+}
+
+func TestWaitForTaskIP(t *testing.T) {
+	t.Run("assigned", func(t *testing.T) {
+		api := &fakeAPI{tasks: []types.Task{newTask("abc", "10.0.0.5")}, ipAfter: 3}
+		ip, err := newTestClient(api).WaitForTaskIP(context.Background(), "abc", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ip != "10.0.0.5" {
+			t.Errorf("ip = %q, want 10.0.0.5", ip)
+		}
+		if api.describes != 4 {
+			t.Errorf("described the task %d times, want 4", api.describes)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := newTestClient(&fakeAPI{}).WaitForTaskIP(context.Background(), "abc", time.Minute)
+		if err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("err = %v, want task not found", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		api := &fakeAPI{tasks: []types.Task{newTask("abc", "")}}
+		_, err := newTestClient(api).WaitForTaskIP(context.Background(), "abc", 10*time.Millisecond)
+		if err == nil || !strings.Contains(err.Error(), "timeout") {
+			t.Errorf("err = %v, want a timeout", err)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		api := &fakeAPI{tasks: []types.Task{newTask("abc", "")}}
+		_, err := newTestClient(api).WaitForTaskIP(ctx, "abc", time.Minute)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	})
+}
+
+func TestTaskID(t *testing.T) {
+	for arn, want := range map[string]string{
+		"arn:aws:ecs:us-east-1:123456789012:task/frank/abc123": "abc123",
+		"arn:aws:ecs:us-east-1:123456789012:task/abc123":       "abc123",
+		"abc123": "abc123",
+	} {
+		if got := TaskID(arn); got != want {
+			t.Errorf("TaskID(%q) = %q, want %q", arn, got, want)
+		}
+	}
+}
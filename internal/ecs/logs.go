@@ -0,0 +1,210 @@
+package ecs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+)
+
+// logPollInterval is how often followed log streams check for new events
+const logPollInterval = 2 * time.Second
+
+// LogEvent is a log line from one task
+type LogEvent struct {
+	Timestamp time.Time
+	TaskID    string
+	Message   string
+}
+
+// LogOptions controls StreamLogs
+type LogOptions struct {
+	// Tail is how many events to send from the end of each stream
+	Tail int
+
+	// Follow keeps polling for new events until the context is done
+	Follow bool
+
+	// PollInterval is the time between polls when following (default 2s)
+	PollInterval time.Duration
+
+	// OnError is told about streams that fail to open or poll; nil ignores them
+	OnError func(taskID string, err error)
+}
+
+// logStream follows the CloudWatch log stream of one task
+type logStream struct {
+	taskID    string
+	input     *cloudwatchlogs.GetLogEventsInput
+	nextToken *string
+}
+
+// StreamLogs sends the last opts.Tail events of each task's log stream to
+// emit in time order and, when following, every poll's new events, which may
+// be none. It stops when ctx is done or emit returns an error. A stream that
+// can't be opened is passed to opts.OnError and skipped, unless it is the
+// only one, whose error is returned.
+func (c *Client) StreamLogs(ctx context.Context, taskIDs []string, opts LogOptions, emit func([]LogEvent) error) error {
+	if c.logs == nil {
+		return errors.New("no CloudWatch Logs client")
+	}
+
+	var streams []*logStream
+	var events []LogEvent
+	for _, taskID := range taskIDs {
+		stream, initial, err := c.openLogStream(ctx, taskID, opts.Tail)
+		if err != nil {
+			if len(taskIDs) == 1 {
+				return err
+			}
+			if opts.OnError != nil {
+				opts.OnError(taskID, err)
+			}
+			continue
+		}
+		streams = append(streams, stream)
+		events = append(events, initial...)
+	}
+	if len(streams) == 0 {
+		return errors.New("no log streams found")
+	}
+	if err := emit(sortLogEvents(events)); err != nil {
+		return err
+	}
+	if !opts.Follow {
+		return nil
+	}
+
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = logPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		events = events[:0]
+		for _, stream := range streams {
+			newEvents, err := c.pollLogStream(ctx, stream)
+			if err != nil {
+				if opts.OnError != nil {
+					opts.OnError(stream.taskID, err)
+				}
+				continue
+			}
+			events = append(events, newEvents...)
+		}
+		if err := emit(sortLogEvents(events)); err != nil {
+			return err
+		}
+	}
+}
+
+// openLogStream fetches the last tail events of a task's log stream
+func (c *Client) openLogStream(ctx context.Context, taskID string, tail int) (*logStream, []LogEvent, error) {
+	stream := &logStream{
+		taskID: taskID,
+		// The log stream name format for Fargate is: prefix/container-name/task-id
+		input: &cloudwatchlogs.GetLogEventsInput{
+			LogGroupName:  aws.String(c.LogGroup),
+			LogStreamName: aws.String(fmt.Sprintf("frank/%s/%s", ContainerName, taskID)),
+			StartFromHead: aws.Bool(false),
+			Limit:         aws.Int32(int32(tail)),
+		},
+	}
+
+	result, err := c.logs.GetLogEvents(ctx, stream.input)
+	if err != nil {
+		// Try with different stream name format (sometimes the container name is different)
+		stream.input.LogStreamName = aws.String(fmt.Sprintf("frank/%s", taskID))
+		result, err = c.logs.GetLogEvents(ctx, stream.input)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get log events: %w", err)
+		}
+	}
+
+	stream.nextToken = result.NextForwardToken
+	stream.input.Limit = aws.Int32(100)
+	return stream, stream.wrap(result), nil
+}
+
+// pollLogStream returns events logged since the previous call
+func (c *Client) pollLogStream(ctx context.Context, s *logStream) ([]LogEvent, error) {
+	s.input.NextToken = s.nextToken
+	result, err := c.logs.GetLogEvents(ctx, s.input)
+	if err != nil {
+		return nil, err
+	}
+	s.nextToken = result.NextForwardToken
+	return s.wrap(result), nil
+}
+
+func (s *logStream) wrap(result *cloudwatchlogs.GetLogEventsOutput) []LogEvent {
+	events := make([]LogEvent, 0, len(result.Events))
+	for _, event := range result.Events {
+		events = append(events, LogEvent{
+			Timestamp: time.UnixMilli(aws.ToInt64(event.Timestamp)),
+			TaskID:    s.taskID,
+			Message:   aws.ToString(event.Message),
+		})
+	}
+	return events
+}
+
+func sortLogEvents(events []LogEvent) []LogEvent {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	return events
+}
+
+// ExportLogs sends every event in a task's log stream to emit, oldest first,
+// a page at a time. Unlike StreamLogs it starts from the head of the stream
+// and stops once the stream has no more events.
+func (c *Client) ExportLogs(ctx context.Context, taskID string, emit func([]LogEvent) error) error {
+	if c.logs == nil {
+		return errors.New("no CloudWatch Logs client")
+	}
+
+	stream := &logStream{
+		taskID: taskID,
+		input: &cloudwatchlogs.GetLogEventsInput{
+			LogGroupName:  aws.String(c.LogGroup),
+			LogStreamName: aws.String(fmt.Sprintf("frank/%s/%s", ContainerName, taskID)),
+			StartFromHead: aws.Bool(true),
+		},
+	}
+	result, err := c.logs.GetLogEvents(ctx, stream.input)
+	if err != nil {
+		stream.input.LogStreamName = aws.String(fmt.Sprintf("frank/%s", taskID))
+		result, err = c.logs.GetLogEvents(ctx, stream.input)
+		if err != nil {
+			return fmt.Errorf("failed to get log events: %w", err)
+		}
+	}
+
+	for {
+		if err := emit(stream.wrap(result)); err != nil {
+			return err
+		}
+		// The last page returns the token it was given
+		next := aws.ToString(result.NextForwardToken)
+		if next == "" || next == aws.ToString(stream.input.NextToken) {
+			return nil
+		}
+		stream.input.NextToken = result.NextForwardToken
+		result, err = c.logs.GetLogEvents(ctx, stream.input)
+		if err != nil {
+			return fmt.Errorf("failed to get log events: %w", err)
+		}
+	}
+}
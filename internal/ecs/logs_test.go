@@ -0,0 +1,206 @@
+package ecs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	logstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// fakeLogs holds log streams by name. Forward tokens are "f/<index>" of the
+// next event; like CloudWatch, the last page returns the token it was given.
+type fakeLogs struct {
+	streams map[string][]logstypes.OutputLogEvent
+}
+
+func (f *fakeLogs) GetLogEvents(ctx context.Context, params *cloudwatchlogs.GetLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetLogEventsOutput, error) {
+	events, ok := f.streams[aws.ToString(params.LogStreamName)]
+	if !ok {
+		return nil, &logstypes.ResourceNotFoundException{Message: aws.String("The specified log stream does not exist.")}
+	}
+
+	limit := int(aws.ToInt32(params.Limit))
+	if limit <= 0 {
+		limit = 100
+	}
+	var start int
+	switch {
+	case params.NextToken != nil:
+		start, _ = strconv.Atoi(strings.TrimPrefix(aws.ToString(params.NextToken), "f/"))
+	case !aws.ToBool(params.StartFromHead):
+		start = max(len(events)-limit, 0)
+	}
+	end := min(start+limit, len(events))
+	return &cloudwatchlogs.GetLogEventsOutput{
+		Events:           events[start:end],
+		NextForwardToken: aws.String(fmt.Sprintf("f/%d", end)),
+	}, nil
+}
+
+// addEvents appends events at the given seconds to a stream
+func (f *fakeLogs) addEvents(stream string, seconds ...int) {
+	if f.streams == nil {
+		f.streams = make(map[string][]logstypes.OutputLogEvent)
+	}
+	for _, s := range seconds {
+		f.streams[stream] = append(f.streams[stream], logstypes.OutputLogEvent{
+			Timestamp: aws.Int64(int64(s) * 1000),
+			Message:   aws.String(fmt.Sprintf("%s %d", stream, s)),
+		})
+	}
+}
+
+// messages returns events as "<task> <second>"
+func messages(events []LogEvent) []string {
+	var out []string
+	for _, e := range events {
+		out = append(out, fmt.Sprintf("%s %d", e.TaskID, e.Timestamp.Unix()))
+	}
+	return out
+}
+
+func TestStreamLogsTail(t *testing.T) {
+	logs := &fakeLogs{}
+	logs.addEvents("frank/frank/a", 1, 3, 5)
+	logs.addEvents("frank/frank/b", 2, 4)
+	logs.addEvents("frank/c", 6) // Older stream name format
+	c := New(&fakeAPI{}, logs, "frank")
+
+	var got []string
+	err := c.StreamLogs(context.Background(), []string{"a", "b", "c"}, LogOptions{Tail: 2}, func(events []LogEvent) error {
+		got = append(got, messages(events)...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "b 2,a 3,b 4,a 5,c 6"; strings.Join(got, ",") != want {
+		t.Errorf("events = %s, want %s", strings.Join(got, ","), want)
+	}
+}
+
+func TestStreamLogsMissingStream(t *testing.T) {
+	logs := &fakeLogs{}
+	logs.addEvents("frank/frank/a", 1)
+	c := New(&fakeAPI{}, logs, "frank")
+	emit := func([]LogEvent) error { return nil }
+
+	// One missing stream of several is reported and skipped
+	var failed []string
+	err := c.StreamLogs(context.Background(), []string{"a", "gone"}, LogOptions{
+		Tail:    10,
+		OnError: func(taskID string, err error) { failed = append(failed, taskID) },
+	}, emit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(failed) != "[gone]" {
+		t.Errorf("OnError got %v, want [gone]", failed)
+	}
+
+	// The only stream missing is an error
+	err = c.StreamLogs(context.Background(), []string{"gone"}, LogOptions{Tail: 10}, emit)
+	var notFound *logstypes.ResourceNotFoundException
+	if !errors.As(err, &notFound) {
+		t.Errorf("err = %v, want ResourceNotFoundException", err)
+	}
+
+	if err := New(&fakeAPI{}, nil, "frank").StreamLogs(context.Background(), []string{"a"}, LogOptions{}, emit); err == nil {
+		t.Error("streamed without a CloudWatch Logs client")
+	}
+}
+
+func TestStreamLogsFollow(t *testing.T) {
+	logs := &fakeLogs{}
+	logs.addEvents("frank/frank/a", 1, 2)
+	c := New(&fakeAPI{}, logs, "frank")
+
+	// 250 new events arrive after the tail: the polls page through them
+	// 100 at a time without repeating any
+	var newEvents []int
+	for s := 10; s < 260; s++ {
+		newEvents = append(newEvents, s)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var got []string
+	emits := 0
+	err := c.StreamLogs(ctx, []string{"a"}, LogOptions{Tail: 10, Follow: true, PollInterval: time.Millisecond}, func(events []LogEvent) error {
+		emits++
+		got = append(got, messages(events)...)
+		if emits == 1 {
+			logs.addEvents("frank/frank/a", newEvents...)
+		}
+		if len(got) == 2+len(newEvents) {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2+len(newEvents) {
+		t.Fatalf("got %d events, want %d", len(got), 2+len(newEvents))
+	}
+	for i, s := range append([]int{1, 2}, newEvents...) {
+		if want := fmt.Sprintf("a %d", s); got[i] != want {
+			t.Fatalf("event %d = %s, want %s", i, got[i], want)
+		}
+	}
+	if emits < 4 {
+		t.Errorf("emitted %d times, want the tail and at least 3 polls", emits)
+	}
+}
+
+func TestStreamLogsEmitError(t *testing.T) {
+	logs := &fakeLogs{}
+	logs.addEvents("frank/frank/a", 1)
+	c := New(&fakeAPI{}, logs, "frank")
+
+	stop := errors.New("stop")
+	err := c.StreamLogs(context.Background(), []string{"a"}, LogOptions{Tail: 10, Follow: true, PollInterval: time.Millisecond}, func([]LogEvent) error {
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("err = %v, want emit's error", err)
+	}
+}
+
+func TestExportLogs(t *testing.T) {
+	logs := &fakeLogs{}
+	var seconds []int
+	for s := 0; s < 230; s++ {
+		seconds = append(seconds, s)
+	}
+	logs.addEvents("frank/frank/a", seconds...)
+	c := New(&fakeAPI{}, logs, "frank")
+
+	var got []string
+	pages := 0
+	err := c.ExportLogs(context.Background(), "a", func(events []LogEvent) error {
+		pages++
+		got = append(got, messages(events)...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(seconds) {
+		t.Fatalf("exported %d events, want %d", len(got), len(seconds))
+	}
+	if got[0] != "a 0" || got[len(got)-1] != "a 229" {
+		t.Errorf("exported %s to %s, want a 0 to a 229", got[0], got[len(got)-1])
+	}
+	// Three full or partial pages, then the empty one that returns its token
+	if pages != 4 {
+		t.Errorf("emitted %d pages, want 4", pages)
+	}
+}
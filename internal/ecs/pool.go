@@ -0,0 +1,236 @@
+package ecs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+const (
+	// TaskTypeWarm marks an idle task in the warm pool, waiting for a
+	// profile to claim it
+	TaskTypeWarm = "warm"
+
+	// ClaimTagKey holds the token of the start that claimed a warm task, so
+	// two starts racing for the same task can tell who won
+	ClaimTagKey = "frank-claim"
+
+	// WarmPoolEnv makes the entrypoint wait for a bootstrap file with the
+	// profile's environment before it sets anything up
+	WarmPoolEnv = "FRANK_WARM_POOL"
+
+	// BootstrapPath is where a claimed warm task's profile environment is
+	// written, as shell export lines
+	BootstrapPath = "/tmp/frank/bootstrap.env"
+)
+
+// Bootstrapper hands a claimed warm task its profile environment, usually
+// by writing BootstrapPath over ECS Exec
+type Bootstrapper interface {
+	Bootstrap(ctx context.Context, taskID string, env map[string]string) error
+}
+
+// WarmTask is a task in the warm pool
+type WarmTask struct {
+	ID     string
+	Arn    string
+	Status string // ECS last status
+	Ready  bool   // Running with the ECS Exec agent up, so it can be claimed
+}
+
+// WarmTasks returns the pool's unclaimed tasks, oldest first
+func (c *Client) WarmTasks(ctx context.Context) ([]WarmTask, error) {
+	all, err := c.ListTasks(ctx, types.DesiredStatusRunning)
+	if err != nil {
+		return nil, err
+	}
+
+	var warm []types.Task
+	for _, task := range all {
+		if TagValue(task.Tags, TaskTypeTagKey) == TaskTypeWarm && TagValue(task.Tags, ClaimTagKey) == "" {
+			warm = append(warm, task)
+		}
+	}
+	sort.Slice(warm, func(i, j int) bool {
+		return aws.ToTime(warm[i].CreatedAt).Before(aws.ToTime(warm[j].CreatedAt))
+	})
+
+	tasks := make([]WarmTask, 0, len(warm))
+	for _, task := range warm {
+		tasks = append(tasks, WarmTask{
+			ID:     TaskID(aws.ToString(task.TaskArn)),
+			Arn:    aws.ToString(task.TaskArn),
+			Status: aws.ToString(task.LastStatus),
+			Ready:  execReady(task),
+		})
+	}
+	return tasks, nil
+}
+
+// execReady reports whether a task is running with its ECS Exec agent up
+func execReady(task types.Task) bool {
+	if aws.ToString(task.LastStatus) != "RUNNING" {
+		return false
+	}
+	for _, container := range task.Containers {
+		if aws.ToString(container.Name) != ContainerName {
+			continue
+		}
+		for _, agent := range container.ManagedAgents {
+			if agent.Name == types.ManagedAgentNameExecuteCommandAgent && aws.ToString(agent.LastStatus) == "RUNNING" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RunWarmTask starts an idle task for the warm pool
+func (c *Client) RunWarmTask(ctx context.Context) (*types.Task, error) {
+	service, err := c.describeService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tags := []types.Tag{
+		{Key: aws.String(TaskTypeTagKey), Value: aws.String(TaskTypeWarm)},
+	}
+	return c.runTask(ctx, service, envPairs(map[string]string{WarmPoolEnv: "1"}), tags, CapacityOnDemand)
+}
+
+// ResizePool starts or stops warm tasks until size are in the pool. Tasks
+// still starting count toward the size; the newest are stopped first.
+func (c *Client) ResizePool(ctx context.Context, size int) (started, stopped []string, err error) {
+	warm, err := c.WarmTasks(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := len(warm); i < size; i++ {
+		task, err := c.RunWarmTask(ctx)
+		if err != nil {
+			return started, stopped, err
+		}
+		started = append(started, TaskID(aws.ToString(task.TaskArn)))
+	}
+	for i := len(warm) - 1; i >= size; i-- {
+		_, err := c.api.StopTask(ctx, &ecs.StopTaskInput{
+			Cluster: aws.String(c.cluster),
+			Task:    aws.String(warm[i].ID),
+			Reason:  aws.String("Warm pool resized"),
+		})
+		if err != nil {
+			return started, stopped, fmt.Errorf("failed to stop warm task %s: %w", warm[i].ID, err)
+		}
+		stopped = append(stopped, warm[i].ID)
+	}
+	return started, stopped, nil
+}
+
+// claimWarmTask takes a ready task from the pool and retags it with tags.
+// It returns nil when no task is ready.
+func (c *Client) claimWarmTask(ctx context.Context, tags []types.Tag) (*types.Task, error) {
+	warm, err := c.WarmTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, candidate := range warm {
+		if !candidate.Ready {
+			continue
+		}
+		token, err := claimToken()
+		if err != nil {
+			return nil, err
+		}
+		if err := c.tagTask(ctx, candidate.Arn, []types.Tag{{Key: aws.String(ClaimTagKey), Value: aws.String(token)}}); err != nil {
+			return nil, err
+		}
+
+		// Another start may have tagged the task at the same time; the last
+		// tag written wins
+		task, err := c.describeTask(ctx, candidate.ID)
+		if err != nil {
+			return nil, err
+		}
+		if TagValue(task.Tags, ClaimTagKey) != token {
+			continue
+		}
+
+		if c.Owner != "" {
+			tags = append(tags, types.Tag{Key: aws.String(OwnerTagKey), Value: aws.String(c.Owner)})
+		}
+		if err := c.tagTask(ctx, candidate.Arn, tags); err != nil {
+			return nil, err
+		}
+		if _, err := c.api.UntagResource(ctx, &ecs.UntagResourceInput{
+			ResourceArn: aws.String(candidate.Arn),
+			TagKeys:     []string{TaskTypeTagKey},
+		}); err != nil {
+			return nil, fmt.Errorf("failed to untag warm task: %w", err)
+		}
+		return task, nil
+	}
+	return nil, nil
+}
+
+// tagTask adds tags to a task
+func (c *Client) tagTask(ctx context.Context, arn string, tags []types.Tag) error {
+	if _, err := c.api.TagResource(ctx, &ecs.TagResourceInput{
+		ResourceArn: aws.String(arn),
+		Tags:        tags,
+	}); err != nil {
+		return fmt.Errorf("failed to tag task %s: %w", TaskID(arn), err)
+	}
+	return nil
+}
+
+// describeTask describes one task with its tags
+func (c *Client) describeTask(ctx context.Context, taskID string) (*types.Task, error) {
+	out, err := c.api.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+		Cluster: aws.String(c.cluster),
+		Tasks:   []string{taskID},
+		Include: []types.TaskField{types.TaskFieldTags},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe task: %w", err)
+	}
+	if len(out.Tasks) == 0 {
+		return nil, fmt.Errorf("task %s not found", taskID)
+	}
+	return &out.Tasks[0], nil
+}
+
+// startWarmTask claims a warm task and bootstraps it with env. It returns
+// nil when no task is ready. A task that can't be bootstrapped is stopped.
+func (c *Client) startWarmTask(ctx context.Context, pool Bootstrapper, env map[string]string, tags []types.Tag) (*types.Task, error) {
+	task, err := c.claimWarmTask(ctx, tags)
+	if err != nil || task == nil {
+		return nil, err
+	}
+
+	taskID := TaskID(aws.ToString(task.TaskArn))
+	if err := pool.Bootstrap(ctx, taskID, env); err != nil {
+		_, _ = c.api.StopTask(ctx, &ecs.StopTaskInput{
+			Cluster: aws.String(c.cluster),
+			Task:    aws.String(taskID),
+			Reason:  aws.String("Warm task bootstrap failed"),
+		})
+		return nil, fmt.Errorf("failed to bootstrap warm task %s: %w", taskID, err)
+	}
+	return task, nil
+}
+
+// claimToken returns a random token for ClaimTagKey
+func claimToken() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
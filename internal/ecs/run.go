@@ -0,0 +1,80 @@
+package ecs
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+const (
+	// TaskTypeTagKey tags a task that isn't an interactive profile task
+	// with what it runs
+	TaskTypeTagKey = "frank-task-type"
+
+	// TaskTypeRun marks a standalone task started by RunTask
+	TaskTypeRun = "run"
+)
+
+// RunSpec describes a standalone task: one that isn't tied to a profile or
+// routed through the ALB
+type RunSpec struct {
+	// Name is the task's CONTAINER_NAME; the entrypoint uses the hostname
+	// when it is empty
+	Name string
+
+	// Repo is cloned into the task's worktree when set
+	Repo   string
+	Branch string // default main
+
+	// Prompt is sent to the agent as its first message
+	Prompt string
+
+	// Env holds extra container environment variables, overriding the ones
+	// the other fields set
+	Env map[string]string
+
+	// Capacity is the Fargate capacity to run on (default on-demand)
+	Capacity Capacity
+}
+
+// RunTask runs a standalone task with the service's task definition and
+// network configuration. With CapacityAuto a task Spot can't place is run
+// on-demand instead; the task's capacity provider says which it got.
+func (c *Client) RunTask(ctx context.Context, spec RunSpec) (*types.Task, error) {
+	service, err := c.describeService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]string)
+	if spec.Name != "" {
+		vars["CONTAINER_NAME"] = spec.Name
+	}
+	if spec.Repo != "" {
+		branch := spec.Branch
+		if branch == "" {
+			branch = "main"
+		}
+		vars["GIT_REPO"] = spec.Repo
+		vars["GIT_BRANCH"] = branch
+	}
+	if spec.Prompt != "" {
+		vars["TASK_PROMPT"] = spec.Prompt
+	}
+	for name, value := range spec.Env {
+		vars[name] = value
+	}
+
+	tags := []types.Tag{
+		{Key: aws.String(TaskTypeTagKey), Value: aws.String(TaskTypeRun)},
+	}
+	if spec.Capacity == CapacityAuto {
+		task, err := c.runTask(ctx, service, envPairs(vars), tags, CapacitySpot)
+		if err == nil || ctx.Err() != nil {
+			return task, err
+		}
+		return c.runTask(ctx, service, envPairs(vars), tags, CapacityOnDemand)
+	}
+	return c.runTask(ctx, service, envPairs(vars), tags, spec.Capacity)
+}
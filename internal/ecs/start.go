@@ -0,0 +1,253 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/barff/frank/internal/alb"
+)
+
+// taskIPTimeout is how long StartProfileTask waits for a task's IP before
+// leaving it unregistered
+const taskIPTimeout = time.Minute
+
+// Router is the ALB side of starting a profile task. *alb.Manager
+// implements it.
+type Router interface {
+	EnsureEndpoints(ctx context.Context, profileName string, hc alb.HealthCheck) (*alb.ProfileEndpoints, error)
+	RegisterTargets(ctx context.Context, endpoints *alb.ProfileEndpoints, ip string) error
+}
+
+// StartSpec describes a profile task to start
+type StartSpec struct {
+	Profile string
+	Repo    string
+	Branch  string // default main
+	Domain  string // The ALB's domain name, for the task's URL
+
+	// Replica 0 is the profile's own task; other replicas run as
+	// <profile>-<n> with their own worktree and share the profile's URL
+	Replica int
+
+	// Resume reattaches the worktree of the profile's last stopped task and
+	// continues the agent's last session
+	Resume bool
+
+	// Env holds extra container environment variables, such as the agent
+	// and provider settings
+	Env map[string]string
+
+	// HealthCheck is the target groups' health check
+	HealthCheck alb.HealthCheck
+
+	// WarmPool, when set, claims a task from the warm pool and bootstraps
+	// it instead of running a new one. A fresh task is run when none is
+	// ready, and a claimed task is replaced in the pool.
+	WarmPool Bootstrapper
+}
+
+// StartedTask describes a task launched for a profile
+type StartedTask struct {
+	Profile   string `json:"profile"`
+	TaskID    string `json:"task_id"`
+	IP        string `json:"ip,omitempty"`
+	Branch    string `json:"branch"`
+	Workspace string `json:"workspace"`
+	URL       string `json:"url"`
+
+	// TargetGroupArn is the profile's web target group, whose health check
+	// decides when the task is up
+	TargetGroupArn string `json:"-"`
+}
+
+// StartProfileTask ensures the profile's ALB routing through router, runs
+// its task with the service's task definition and network configuration,
+// and registers the task in the target group. progress reports each step.
+func (c *Client) StartProfileTask(ctx context.Context, router Router, spec StartSpec, progress func(format string, a ...interface{})) (*StartedTask, error) {
+	profileName := spec.Profile
+	containerName := ReplicaName(profileName, spec.Replica)
+
+	// Ensure ALB infrastructure exists
+	progress("Ensuring ALB endpoints...")
+	endpoints, err := router.EnsureEndpoints(ctx, profileName, spec.HealthCheck)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ensure ALB endpoints: %w", err)
+	}
+
+	service, err := c.describeService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every task records its workspace so it can be resumed after a stop
+	workspace := ProfileWorkspacePath(containerName)
+	if spec.Resume {
+		previous, err := c.FindStoppedWorkspace(ctx, profileName)
+		if err != nil {
+			return nil, err
+		}
+		if previous != "" {
+			workspace = previous
+		}
+		progress("Resuming workspace %s", workspace)
+	}
+
+	branch := spec.Branch
+	if branch == "" {
+		branch = "main"
+	}
+
+	env := map[string]string{
+		"CONTAINER_NAME": containerName,
+		"GIT_REPO":       spec.Repo,
+		"GIT_BRANCH":     branch,
+		"URL_PREFIX":     "/" + profileName,
+		"WORKSPACE_PATH": workspace,
+	}
+	// The entrypoint skips cloning and continues the agent's last session
+	if spec.Resume {
+		env["RESUME_SESSION"] = "1"
+	}
+	for name, value := range spec.Env {
+		env[name] = value
+	}
+
+	tags := []types.Tag{
+		{Key: aws.String(ProfileTagKey), Value: aws.String(profileName)},
+		{Key: aws.String(WorkspaceTagKey), Value: aws.String(workspace)},
+	}
+	if spec.Replica > 0 {
+		tags = append(tags, types.Tag{Key: aws.String(ReplicaTagKey), Value: aws.String(strconv.Itoa(spec.Replica))})
+	}
+
+	var task *types.Task
+	if spec.WarmPool != nil {
+		progress("Claiming a warm task...")
+		task, err = c.startWarmTask(ctx, spec.WarmPool, env, tags)
+		switch {
+		case err != nil:
+			progress("Warning: %v", err)
+		case task == nil:
+			progress("No warm task is ready")
+		default:
+			// Keep the pool at its size
+			if _, err := c.RunWarmTask(ctx); err != nil {
+				progress("Warning: Failed to replace the warm task: %v", err)
+			}
+		}
+	}
+
+	// Start the task
+	if task == nil {
+		progress("Starting ECS task...")
+		task, err = c.runTask(ctx, service, envPairs(env), tags, CapacityOnDemand)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	started := &StartedTask{
+		Profile:        profileName,
+		TaskID:         TaskID(aws.ToString(task.TaskArn)),
+		Branch:         branch,
+		Workspace:      workspace,
+		URL:            fmt.Sprintf("https://%s/%s/", spec.Domain, profileName),
+		TargetGroupArn: endpoints.Web.TargetGroupArn,
+	}
+
+	// Wait for task to get an IP address
+	progress("Waiting for task IP...")
+	taskIP, err := c.WaitForTaskIP(ctx, started.TaskID, taskIPTimeout)
+	if err != nil {
+		progress("Warning: Could not get task IP: %v", err)
+		progress("You may need to manually register the task in the target group")
+		return started, nil
+	}
+	started.IP = taskIP
+
+	// Register task in the endpoints' target groups
+	progress("Registering task in target groups...")
+	if err := router.RegisterTargets(ctx, endpoints, taskIP); err != nil {
+		progress("Warning: Failed to register target: %v", err)
+	}
+	return started, nil
+}
+
+// describeService returns the service whose task definition and network
+// configuration tasks reuse
+func (c *Client) describeService(ctx context.Context) (*types.Service, error) {
+	descService, err := c.api.DescribeServices(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(c.cluster),
+		Services: []string{c.Service},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe service: %w", err)
+	}
+	if len(descService.Services) == 0 {
+		return nil, fmt.Errorf("service %s not found in cluster %s", c.Service, c.cluster)
+	}
+	return &descService.Services[0], nil
+}
+
+// runTask runs the service's task definition on Fargate with env set on the
+// frank container, tagged with the client's owner. Spot capacity goes
+// through the cluster's FARGATE_SPOT capacity provider; anything else uses
+// the Fargate launch type.
+func (c *Client) runTask(ctx context.Context, service *types.Service, env []types.KeyValuePair, tags []types.Tag, capacity Capacity) (*types.Task, error) {
+	if c.Owner != "" {
+		tags = append(tags, types.Tag{Key: aws.String(OwnerTagKey), Value: aws.String(c.Owner)})
+	}
+	input := &ecs.RunTaskInput{
+		Cluster:              aws.String(c.cluster),
+		TaskDefinition:       service.TaskDefinition,
+		NetworkConfiguration: service.NetworkConfiguration,
+		Overrides: &types.TaskOverride{
+			ContainerOverrides: []types.ContainerOverride{
+				{Name: aws.String(ContainerName), Environment: env},
+			},
+		},
+		EnableExecuteCommand: true,
+		Tags:                 tags,
+	}
+	if strategy := capacityStrategy(capacity); strategy != nil {
+		input.CapacityProviderStrategy = strategy
+	} else {
+		input.LaunchType = types.LaunchTypeFargate
+	}
+	runResult, err := c.api.RunTask(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run task: %w", err)
+	}
+
+	if len(runResult.Tasks) == 0 {
+		if len(runResult.Failures) > 0 {
+			return nil, fmt.Errorf("failed to start task: %s - %s",
+				aws.ToString(runResult.Failures[0].Reason),
+				aws.ToString(runResult.Failures[0].Detail))
+		}
+		return nil, fmt.Errorf("failed to start task: no task created")
+	}
+	return &runResult.Tasks[0], nil
+}
+
+// envPairs turns environment variables into container overrides, sorted by
+// name
+func envPairs(vars map[string]string) []types.KeyValuePair {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]types.KeyValuePair, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, types.KeyValuePair{Name: aws.String(name), Value: aws.String(vars[name])})
+	}
+	return pairs
+}
@@ -0,0 +1,188 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/barff/frank/internal/alb"
+)
+
+// fakeRouter records the ALB calls StartProfileTask makes
+type fakeRouter struct {
+	ensured    []string
+	registered []string
+}
+
+func (r *fakeRouter) EnsureEndpoints(ctx context.Context, profileName string, hc alb.HealthCheck) (*alb.ProfileEndpoints, error) {
+	r.ensured = append(r.ensured, profileName)
+	e := alb.NewProfileEndpoints(profileName)
+	e.Web.TargetGroupArn = "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/" + e.Web.TargetGroup + "/1"
+	return e, nil
+}
+
+func (r *fakeRouter) RegisterTargets(ctx context.Context, endpoints *alb.ProfileEndpoints, ip string) error {
+	r.registered = append(r.registered, endpoints.Profile+"@"+ip)
+	return nil
+}
+
+func noProgress(format string, a ...interface{}) {}
+
+// runEnv returns the frank container's environment from a RunTask call
+func runEnv(t *testing.T, api *fakeAPI) map[string]string {
+	t.Helper()
+	if len(api.runs) != 1 {
+		t.Fatalf("ran %d tasks, want 1", len(api.runs))
+	}
+	overrides := api.runs[0].Overrides.ContainerOverrides
+	if len(overrides) != 1 || aws.ToString(overrides[0].Name) != ContainerName {
+		t.Fatalf("container overrides = %+v, want one for %s", overrides, ContainerName)
+	}
+	env := make(map[string]string)
+	for _, pair := range overrides[0].Environment {
+		env[aws.ToString(pair.Name)] = aws.ToString(pair.Value)
+	}
+	return env
+}
+
+func TestStartProfileTask(t *testing.T) {
+	api := &fakeAPI{}
+	router := &fakeRouter{}
+	c := newTestClient(api)
+	c.Owner = "alice"
+
+	started, err := c.StartProfileTask(context.Background(), router, StartSpec{
+		Profile: "demo",
+		Repo:    "https://github.com/org/demo.git",
+		Domain:  "frank.example.com",
+		Env:     map[string]string{"AGENT": "claude", "GIT_BRANCH": "override"},
+	}, noProgress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := StartedTask{
+		Profile:   "demo",
+		TaskID:    "run1",
+		IP:        "10.0.1.1",
+		Branch:    "main",
+		Workspace: ProfileWorkspacePath("demo"),
+		URL:       "https://frank.example.com/demo/",
+	}
+	got := *started
+	got.TargetGroupArn = ""
+	if got != want {
+		t.Errorf("started = %+v\nwant %+v", got, want)
+	}
+	if started.TargetGroupArn == "" {
+		t.Error("started task has no target group")
+	}
+
+	env := runEnv(t, api)
+	for name, value := range map[string]string{
+		"CONTAINER_NAME": "demo",
+		"GIT_REPO":       "https://github.com/org/demo.git",
+		"GIT_BRANCH":     "override", // Spec env wins
+		"URL_PREFIX":     "/demo",
+		"WORKSPACE_PATH": ProfileWorkspacePath("demo"),
+		"AGENT":          "claude",
+	} {
+		if env[name] != value {
+			t.Errorf("%s = %q, want %q", name, env[name], value)
+		}
+	}
+	if _, ok := env["RESUME_SESSION"]; ok {
+		t.Error("RESUME_SESSION set without Resume")
+	}
+
+	run := api.runs[0]
+	if aws.ToString(run.TaskDefinition) != "arn:aws:ecs:us-east-1:123456789012:task-definition/frank:7" {
+		t.Errorf("task definition = %s, want the service's", aws.ToString(run.TaskDefinition))
+	}
+	if !run.EnableExecuteCommand {
+		t.Error("ECS Exec isn't enabled")
+	}
+	for key, value := range map[string]string{
+		ProfileTagKey:   "demo",
+		WorkspaceTagKey: ProfileWorkspacePath("demo"),
+		OwnerTagKey:     "alice",
+		ReplicaTagKey:   "",
+	} {
+		if got := TagValue(run.Tags, key); got != value {
+			t.Errorf("tag %s = %q, want %q", key, got, value)
+		}
+	}
+
+	if fmt.Sprint(router.ensured) != "[demo]" || fmt.Sprint(router.registered) != "[demo@10.0.1.1]" {
+		t.Errorf("router ensured %v and registered %v, want [demo] and [demo@10.0.1.1]", router.ensured, router.registered)
+	}
+}
+
+func TestStartProfileTaskReplica(t *testing.T) {
+	api := &fakeAPI{}
+	started, err := newTestClient(api).StartProfileTask(context.Background(), &fakeRouter{}, StartSpec{
+		Profile: "demo",
+		Branch:  "dev",
+		Domain:  "frank.example.com",
+		Replica: 2,
+	}, noProgress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Replicas share the profile's URL and routing but not its worktree
+	if started.URL != "https://frank.example.com/demo/" {
+		t.Errorf("URL = %s, want the profile's", started.URL)
+	}
+	if started.Workspace != ProfileWorkspacePath("demo-2") {
+		t.Errorf("workspace = %s, want %s", started.Workspace, ProfileWorkspacePath("demo-2"))
+	}
+	env := runEnv(t, api)
+	if env["CONTAINER_NAME"] != "demo-2" || env["URL_PREFIX"] != "/demo" || env["GIT_BRANCH"] != "dev" {
+		t.Errorf("env = %v", env)
+	}
+	if replica := TagValue(api.runs[0].Tags, ReplicaTagKey); replica != "2" {
+		t.Errorf("replica tag = %q, want 2", replica)
+	}
+}
+
+func TestStartProfileTaskResume(t *testing.T) {
+	stopped := newTask("old", "", tag(ProfileTagKey, "demo"), tag(WorkspaceTagKey, "/workspace/repos/demo/previous"))
+	stopped.DesiredStatus = aws.String(string(types.DesiredStatusStopped))
+	stopped.StoppedAt = aws.Time(time.Now())
+	api := &fakeAPI{tasks: []types.Task{stopped}}
+
+	started, err := newTestClient(api).StartProfileTask(context.Background(), &fakeRouter{}, StartSpec{
+		Profile: "demo",
+		Resume:  true,
+	}, noProgress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if started.Workspace != "/workspace/repos/demo/previous" {
+		t.Errorf("workspace = %s, want the stopped task's", started.Workspace)
+	}
+	env := runEnv(t, api)
+	if env["WORKSPACE_PATH"] != "/workspace/repos/demo/previous" || env["RESUME_SESSION"] != "1" {
+		t.Errorf("env = %v, want the previous workspace and RESUME_SESSION=1", env)
+	}
+}
+
+func TestStartProfileTaskNoIP(t *testing.T) {
+	// A task that never reports an IP is returned unregistered
+	api := &fakeAPI{noTask: true}
+	router := &fakeRouter{}
+	started, err := newTestClient(api).StartProfileTask(context.Background(), router, StartSpec{Profile: "demo"}, noProgress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if started.TaskID != "run1" || started.IP != "" {
+		t.Errorf("started = %+v, want run1 with no IP", started)
+	}
+	if len(router.registered) != 0 {
+		t.Errorf("registered %v without an IP", router.registered)
+	}
+}
@@ -0,0 +1,88 @@
+// Package exitcode sorts frank's errors into kinds, each with a documented
+// process exit code, so scripts can tell a missing profile from an expired
+// AWS session without parsing messages.
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind is a category of failure. Its value is the exit code.
+type Kind int
+
+const (
+	General  Kind = 1 // Anything not covered below
+	Config   Kind = 2 // The config file can't be loaded, is invalid, or forbids the command
+	Auth     Kind = 3 // Credentials are missing, expired or not allowed to do this
+	AWS      Kind = 4 // An AWS API call failed
+	Runtime  Kind = 5 // No container runtime, or the runtime failed
+	NotFound Kind = 6 // A profile, container, task or other named thing doesn't exist
+	Usage    Kind = 7 // Unknown command or flag, or wrong arguments
+	Remote   Kind = 8 // A command run in an ECS task over ECS Exec failed
+)
+
+var kindNames = map[Kind]string{
+	General:  "general",
+	Config:   "config",
+	Auth:     "auth",
+	AWS:      "aws",
+	Runtime:  "runtime",
+	NotFound: "not_found",
+	Usage:    "usage",
+	Remote:   "remote",
+}
+
+// String returns the kind's name, as used in JSON error output
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("kind(%d)", int(k))
+}
+
+// Code returns the process exit code for the kind
+func (k Kind) Code() int {
+	return int(k)
+}
+
+// Error is an error with a known kind
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap attaches kind to err. A nil error stays nil, and an error that
+// already has a kind keeps it, so the most specific one wins.
+func Wrap(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// Errorf formats an error of the given kind, like fmt.Errorf
+func Errorf(kind Kind, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// KindOf returns the kind attached to err, and false if there is none
+func KindOf(err error) (Kind, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind, true
+	}
+	return General, false
+}
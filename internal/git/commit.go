@@ -0,0 +1,82 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Fallback identity for auto-commits when the user has no git identity
+// configured, so a missing user.email doesn't leave work uncommitted
+const (
+	autoCommitName  = "frank"
+	autoCommitEmail = "frank@localhost"
+)
+
+// ExpandCommitMessage fills the {{container}} and {{timestamp}} placeholders
+// of an auto-commit message template
+func ExpandCommitMessage(template, containerName string, t time.Time) string {
+	return strings.NewReplacer(
+		"{{container}}", containerName,
+		"{{timestamp}}", t.Format("2006-01-02 15:04:05"),
+	).Replace(template)
+}
+
+// HasChanges reports whether the repository in dir has uncommitted changes,
+// including untracked files
+func HasChanges(dir string) (bool, error) {
+	out, err := run(dir, "status", "--porcelain")
+	if err != nil {
+		return false, fmt.Errorf("failed to check git status: %w", err)
+	}
+	return out != "", nil
+}
+
+// CommitAll stages every change in dir and commits it, returning the new
+// commit's short hash. Hooks are skipped: they may depend on tools that only
+// exist in the container.
+func CommitAll(dir, message string) (string, error) {
+	if _, err := run(dir, "add", "-A"); err != nil {
+		return "", fmt.Errorf("failed to stage changes: %w", err)
+	}
+
+	args := []string{"commit", "--no-verify", "-m", message}
+	if email, _ := run(dir, "config", "user.email"); email == "" {
+		args = append([]string{"-c", "user.name=" + autoCommitName, "-c", "user.email=" + autoCommitEmail}, args...)
+	}
+	if _, err := run(dir, args...); err != nil {
+		return "", fmt.Errorf("failed to commit: %w", err)
+	}
+
+	sha, err := run(dir, "rev-parse", "--short", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit: %w", err)
+	}
+	return sha, nil
+}
+
+// PushHead pushes HEAD of the repository in dir to branch on origin. Git
+// never prompts for credentials.
+func PushHead(dir, branch string) error {
+	if _, err := run(dir, "push", "origin", "HEAD:refs/heads/"+branch); err != nil {
+		return fmt.Errorf("failed to push to %s: %w", branch, err)
+	}
+	return nil
+}
+
+// run runs git in dir and returns its trimmed stdout. Errors include git's
+// stderr.
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
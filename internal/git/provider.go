@@ -0,0 +1,118 @@
+package git
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Provider identifies the hosting service for a git repository
+type Provider string
+
+const (
+	ProviderGitHub    Provider = "github"
+	ProviderGitLab    Provider = "gitlab"
+	ProviderBitbucket Provider = "bitbucket"
+	ProviderUnknown   Provider = "unknown"
+)
+
+// DetectProvider determines the hosting provider from a repository URL.
+// Both HTTPS (https://gitlab.com/org/repo.git) and SCP-style SSH
+// (git@bitbucket.org:org/repo.git) URLs are supported.
+func DetectProvider(repoURL string) Provider {
+	host := RepoHost(repoURL)
+	switch {
+	case host == "":
+		return ProviderUnknown
+	case strings.Contains(host, "github"):
+		return ProviderGitHub
+	case strings.Contains(host, "gitlab"):
+		return ProviderGitLab
+	case strings.Contains(host, "bitbucket"):
+		return ProviderBitbucket
+	default:
+		return ProviderUnknown
+	}
+}
+
+// RepoHost extracts the lowercase host name from a repository URL
+func RepoHost(repoURL string) string {
+	repoURL = strings.TrimSpace(repoURL)
+	if repoURL == "" || isLocalPath(repoURL) {
+		return ""
+	}
+
+	// SCP-style SSH: git@host:org/repo.git
+	if !strings.Contains(repoURL, "://") {
+		if at := strings.Index(repoURL, "@"); at >= 0 {
+			repoURL = repoURL[at+1:]
+		}
+		if colon := strings.Index(repoURL, ":"); colon >= 0 {
+			return strings.ToLower(repoURL[:colon])
+		}
+		return ""
+	}
+
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// CredentialUsername returns the username git should send alongside a token
+// for the provider's HTTPS endpoints
+func (p Provider) CredentialUsername() string {
+	switch p {
+	case ProviderGitLab:
+		return "oauth2"
+	case ProviderBitbucket:
+		return "x-token-auth"
+	default:
+		return "x-access-token"
+	}
+}
+
+// TokenEnvVar returns the environment variable containers use for the provider's token
+func (p Provider) TokenEnvVar() string {
+	switch p {
+	case ProviderGitLab:
+		return "GITLAB_TOKEN"
+	case ProviderBitbucket:
+		return "BITBUCKET_TOKEN"
+	default:
+		return "GH_TOKEN"
+	}
+}
+
+// TokenSecret returns the Secrets Manager secret 'frank auth push' stores the
+// provider's token in, or "" for providers whose token isn't pushed that way
+func (p Provider) TokenSecret() string {
+	switch p {
+	case ProviderGitLab:
+		return "/frank/gitlab-token"
+	case ProviderBitbucket:
+		return "/frank/bitbucket-token"
+	default:
+		return ""
+	}
+}
+
+// CredentialHelperEnv returns environment variables describing a git
+// credential helper for the repository host: GIT_CREDENTIAL_HOST and
+// GIT_CREDENTIAL_HELPER. The container entrypoint installs the helper with
+// 'git config --global', leaving any GIT_CONFIG_* entries alone. The helper
+// reads the token from the provider's TokenEnvVar at runtime, so neither
+// variable holds a secret.
+func CredentialHelperEnv(repoURL string) []string {
+	host := RepoHost(repoURL)
+	if host == "" {
+		return nil
+	}
+	provider := DetectProvider(repoURL)
+	helper := "!f() { echo username=" + provider.CredentialUsername() + "; echo password=$" + provider.TokenEnvVar() + "; }; f"
+
+	return []string{
+		"GIT_CREDENTIAL_HOST=" + host,
+		"GIT_CREDENTIAL_HELPER=" + helper,
+	}
+}
@@ -0,0 +1,40 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// RemoteBranches lists the branches of a remote repository with git
+// ls-remote, along with the branch its HEAD points to (empty if unknown).
+// Git never prompts for credentials; private repositories need a configured
+// credential helper or SSH key.
+func RemoteBranches(repoURL string) (branches []string, defaultBranch string, err error) {
+	cmd := exec.Command("git", "ls-remote", "--symref", repoURL, "HEAD", "refs/heads/*")
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, "", fmt.Errorf("failed to list remote branches: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, "", fmt.Errorf("failed to list remote branches: %w", err)
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		// "ref: refs/heads/main\tHEAD" names the default branch
+		if target, ok := strings.CutPrefix(line, "ref: refs/heads/"); ok {
+			defaultBranch, _, _ = strings.Cut(target, "\t")
+			continue
+		}
+		if _, ref, ok := strings.Cut(line, "\t"); ok {
+			if branch, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+				branches = append(branches, branch)
+			}
+		}
+	}
+	sort.Strings(branches)
+	return branches, defaultBranch, nil
+}
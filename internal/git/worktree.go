@@ -269,6 +269,26 @@ func (w *WorktreeManager) List() ([]WorktreeInfo, error) {
 	return worktrees, nil
 }
 
+// Dirs returns the container names that have a worktree directory on disk
+func (w *WorktreeManager) Dirs() ([]string, error) {
+	entries, err := os.ReadDir(w.baseDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read worktree directory: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		// Skip the shared clone (.main-repo) and other hidden entries
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
 // GetPath returns the path for a container's worktree
 func (w *WorktreeManager) GetPath(containerName string) string {
 	return filepath.Join(w.baseDir, containerName)
@@ -0,0 +1,217 @@
+// Package github is a minimal client for the GitHub REST API endpoints frank uses
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// apiURL is the GitHub REST API base URL
+const apiURL = "https://api.github.com"
+
+// Client calls the GitHub REST API with a personal access token
+type Client struct {
+	token   string
+	baseURL string
+	http    *http.Client
+}
+
+// NewClient creates a client authenticated with token
+func NewClient(token string) *Client {
+	return &Client{
+		token:   token,
+		baseURL: apiURL,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// PullRequestInput describes a pull request to open
+type PullRequestInput struct {
+	Title string `json:"title"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Body  string `json:"body,omitempty"`
+	Draft bool   `json:"draft,omitempty"`
+}
+
+// PullRequest is a created pull request
+type PullRequest struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+}
+
+// Repository is a repository the token can access
+type Repository struct {
+	FullName      string `json:"full_name"`
+	CloneURL      string `json:"clone_url"`
+	Description   string `json:"description"`
+	DefaultBranch string `json:"default_branch"`
+	Private       bool   `json:"private"`
+	Fork          bool   `json:"fork"`
+	Archived      bool   `json:"archived"`
+}
+
+// ListRepositories returns up to 100 repositories the authenticated user
+// owns, collaborates on or can access through an organization, most
+// recently pushed first
+func (c *Client) ListRepositories(ctx context.Context) ([]Repository, error) {
+	var repos []Repository
+	path := "/user/repos?per_page=100&sort=pushed&affiliation=owner,collaborator,organization_member"
+	if err := c.do(ctx, http.MethodGet, path, nil, &repos); err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+	return repos, nil
+}
+
+// ListOrgRepositories returns every repository in an organization that the
+// token can see, sorted by name
+func (c *Client) ListOrgRepositories(ctx context.Context, org string) ([]Repository, error) {
+	var repos []Repository
+	for page := 1; ; page++ {
+		var batch []Repository
+		path := fmt.Sprintf("/orgs/%s/repos?per_page=100&sort=full_name&page=%d", url.PathEscape(org), page)
+		if err := c.do(ctx, http.MethodGet, path, nil, &batch); err != nil {
+			return nil, fmt.Errorf("failed to list repositories for %s: %w", org, err)
+		}
+		repos = append(repos, batch...)
+		if len(batch) < 100 {
+			return repos, nil
+		}
+	}
+}
+
+// DefaultBranch returns the repository's default branch
+func (c *Client) DefaultBranch(ctx context.Context, owner, repo string) (string, error) {
+	var result struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s", owner, repo), nil, &result); err != nil {
+		return "", fmt.Errorf("failed to get repository: %w", err)
+	}
+	return result.DefaultBranch, nil
+}
+
+// CreatePullRequest opens a pull request
+func (c *Client) CreatePullRequest(ctx context.Context, owner, repo string, input PullRequestInput) (*PullRequest, error) {
+	var pr PullRequest
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/pulls", owner, repo), input, &pr); err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	return &pr, nil
+}
+
+// RequestReviewers asks users (or org/team slugs) to review a pull request
+func (c *Client) RequestReviewers(ctx context.Context, owner, repo string, number int, reviewers []string) error {
+	var users, teams []string
+	for _, r := range reviewers {
+		// Teams are written as org/team-slug
+		if i := strings.Index(r, "/"); i >= 0 {
+			teams = append(teams, r[i+1:])
+		} else {
+			users = append(users, r)
+		}
+	}
+
+	body := map[string][]string{"reviewers": users, "team_reviewers": teams}
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d/requested_reviewers", owner, repo, number)
+	if err := c.do(ctx, http.MethodPost, path, body, nil); err != nil {
+		return fmt.Errorf("failed to request reviewers: %w", err)
+	}
+	return nil
+}
+
+// do sends a request and decodes the JSON response into out when non-nil
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return apiError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// apiError turns an error response into a readable message, including the
+// per-field errors GitHub returns for validation failures
+func apiError(resp *http.Response) error {
+	var result struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+			Field   string `json:"field"`
+			Code    string `json:"code"`
+		} `json:"errors"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	if json.Unmarshal(data, &result) != nil || result.Message == "" {
+		return fmt.Errorf("GitHub API returned %s", resp.Status)
+	}
+
+	msg := result.Message
+	for _, e := range result.Errors {
+		switch {
+		case e.Message != "":
+			msg += "; " + e.Message
+		case e.Field != "":
+			msg += fmt.Sprintf("; %s %s", e.Field, e.Code)
+		}
+	}
+	return fmt.Errorf("GitHub API returned %s: %s", resp.Status, msg)
+}
+
+// ParseRepo extracts owner and repository name from a GitHub remote URL.
+// HTTPS (https://github.com/org/repo.git) and SSH (git@github.com:org/repo.git)
+// forms are supported.
+func ParseRepo(remoteURL string) (owner, repo string, err error) {
+	remoteURL = strings.TrimSpace(remoteURL)
+
+	var path string
+	if strings.Contains(remoteURL, "://") {
+		u, parseErr := url.Parse(remoteURL)
+		if parseErr != nil {
+			return "", "", fmt.Errorf("invalid remote URL %q: %w", remoteURL, parseErr)
+		}
+		path = u.Path
+	} else if i := strings.Index(remoteURL, ":"); i >= 0 {
+		path = remoteURL[i+1:]
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(path, ".git"), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("cannot determine GitHub repository from %q", remoteURL)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,221 @@
+// Package log configures frank's shared slog logger from LoggingConfig.
+//
+// Console output keeps frank's plain CLI style: debug and info messages go to
+// stdout as-is, warnings and errors go to stderr with a "Warning:" or "Error:"
+// prefix. With a log file every record at or above the level is also written
+// there with timestamps. The JSON format switches both to slog's JSON output.
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Output formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Options controls logger setup
+type Options struct {
+	Level   string // debug, info, warn, error
+	Verbose bool   // Shorthand for the debug level
+	File    string // Also write records to this file
+	Format  string // text or json
+}
+
+var (
+	mu      sync.Mutex
+	logFile *os.File
+)
+
+func init() {
+	// Usable before Setup runs, e.g. for config load errors
+	slog.SetDefault(slog.New(newConsoleHandler(os.Stdout, os.Stderr, slog.LevelInfo)))
+}
+
+// Setup installs the default slog logger for opts. It replaces any logger
+// installed by an earlier call and closes its file.
+func Setup(opts Options) error {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return err
+	}
+	if opts.Verbose && level > slog.LevelDebug {
+		level = slog.LevelDebug
+	}
+
+	var handlers []slog.Handler
+	switch opts.Format {
+	case "", FormatText:
+		handlers = append(handlers, newConsoleHandler(os.Stdout, os.Stderr, level))
+	case FormatJSON:
+		handlers = append(handlers, slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	default:
+		return fmt.Errorf("invalid log format %q (valid: text, json)", opts.Format)
+	}
+
+	var file *os.File
+	if opts.File != "" {
+		if err := os.MkdirAll(filepath.Dir(opts.File), 0755); err != nil {
+			return fmt.Errorf("failed to create log directory: %w", err)
+		}
+		file, err = os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		handlerOpts := &slog.HandlerOptions{Level: level}
+		if opts.Format == FormatJSON {
+			handlers = append(handlers, slog.NewJSONHandler(file, handlerOpts))
+		} else {
+			handlers = append(handlers, slog.NewTextHandler(file, handlerOpts))
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if logFile != nil {
+		logFile.Close()
+	}
+	logFile = file
+
+	if len(handlers) == 1 {
+		slog.SetDefault(slog.New(handlers[0]))
+	} else {
+		slog.SetDefault(slog.New(multiHandler(handlers)))
+	}
+	return nil
+}
+
+// Close flushes and closes the log file, if any
+func Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if logFile == nil {
+		return nil
+	}
+	err := logFile.Close()
+	logFile = nil
+	return err
+}
+
+// ParseLevel converts a config level name to a slog level. An empty name means info.
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q (valid: debug, info, warn, error)", name)
+	}
+}
+
+// consoleHandler writes records in frank's plain CLI style
+type consoleHandler struct {
+	stdout io.Writer
+	stderr io.Writer
+	level  slog.Level
+	attrs  []slog.Attr
+	mu     *sync.Mutex
+}
+
+func newConsoleHandler(stdout, stderr io.Writer, level slog.Level) *consoleHandler {
+	return &consoleHandler{stdout: stdout, stderr: stderr, level: level, mu: &sync.Mutex{}}
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	out := h.stdout
+	switch {
+	case r.Level >= slog.LevelError:
+		out = h.stderr
+		b.WriteString("Error: ")
+	case r.Level >= slog.LevelWarn:
+		out = h.stderr
+		b.WriteString("Warning: ")
+	}
+	b.WriteString(r.Message)
+
+	writeAttr := func(a slog.Attr) bool {
+		fmt.Fprintf(&b, " %s=%v", a.Key, a.Value)
+		return true
+	}
+	for _, a := range h.attrs {
+		writeAttr(a)
+	}
+	r.Attrs(writeAttr)
+	b.WriteString("\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &clone
+}
+
+// WithGroup is not needed for console output; group names are dropped
+func (h *consoleHandler) WithGroup(_ string) slog.Handler {
+	return h
+}
+
+// multiHandler sends each record to every handler that accepts its level
+type multiHandler []slog.Handler
+
+func (m multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range m {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (m multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(multiHandler, len(m))
+	for i, h := range m {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (m multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make(multiHandler, len(m))
+	for i, h := range m {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
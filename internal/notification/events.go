@@ -0,0 +1,103 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Event types published by the container's /events stream
+const (
+	EventAwaitingInput = "awaiting_input"
+	EventTaskComplete  = "task_complete"
+	EventError         = "error"
+)
+
+// Event is a structured notification event sent by the container
+type Event struct {
+	Type      string    `json:"type"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Describe returns the notification text for an event, or "" for event types
+// that don't warrant a notification
+func (e Event) Describe() string {
+	var prefix string
+	switch e.Type {
+	case EventAwaitingInput:
+		prefix = "Claude is waiting for input"
+	case EventTaskComplete:
+		prefix = "Claude finished its task"
+	case EventError:
+		prefix = "Claude hit an error"
+	default:
+		return ""
+	}
+
+	if e.Message == "" {
+		return prefix
+	}
+	return fmt.Sprintf("%s: %s", prefix, e.Message)
+}
+
+// errEventsUnsupported means the container doesn't serve /events (an older image)
+var errEventsUnsupported = errors.New("event stream not supported by container")
+
+// subscribeEvents reads Server-Sent Events from url and calls handle for each
+// one until the stream ends or ctx is cancelled. onConnect is called once the
+// stream is established.
+func subscribeEvents(ctx context.Context, url string, onConnect func(), handle func(Event)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect to event stream: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errEventsUnsupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("event stream returned %s", resp.Status)
+	}
+	onConnect()
+
+	// SSE frames are "field: value" lines ending with a blank line. Only the
+	// data field matters since the JSON payload repeats the event type.
+	var data strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() > 0 {
+				var event Event
+				if err := json.Unmarshal([]byte(data.String()), &event); err == nil {
+					handle(event)
+				}
+				data.Reset()
+			}
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteString("\n")
+			}
+			data.WriteString(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return scanner.Err()
+}
@@ -3,8 +3,10 @@ package notification
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -16,9 +18,10 @@ import (
 type Monitor struct {
 	containerID   string
 	containerName string
+	statusPort    int
 	runtime       container.Runtime
 	detector      *PatternDetector
-	notifier      *BeeepNotifier
+	notifier      *MultiNotifier
 	cooldown      *CooldownManager
 	cfg           config.NotificationConfig
 
@@ -28,24 +31,43 @@ type Monitor struct {
 	mu           sync.Mutex
 }
 
-// NewMonitor creates a new notification monitor
+// Event stream connection timing
+const (
+	// eventStreamWait is how long to wait for a new container's /events endpoint
+	eventStreamWait = 60 * time.Second
+	// eventRetryInterval is the delay between connection attempts
+	eventRetryInterval = 2 * time.Second
+)
+
+// NewMonitor creates a new notification monitor. Remote backends are chosen
+// by the container's profile. statusPort is the host port of the container's
+// status server; when it serves /events the monitor uses those structured
+// events instead of scraping logs. Pass 0 to always scrape logs.
 func NewMonitor(
 	containerID string,
 	containerName string,
+	profile string,
+	statusPort int,
 	runtime container.Runtime,
 	cfg config.NotificationConfig,
-) *Monitor {
+) (*Monitor, error) {
+	notifier, err := NewProfileNotifier(cfg, profile)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Monitor{
 		containerID:   containerID,
 		containerName: containerName,
+		statusPort:    statusPort,
 		runtime:       runtime,
 		detector:      NewPatternDetector(cfg),
-		notifier:      NewBeeepNotifier(),
+		notifier:      notifier,
 		cooldown:      NewCooldownManager(cfg.Cooldown),
 		cfg:           cfg,
 		lastActivity:  time.Now(),
 		stopChan:      make(chan struct{}),
-	}
+	}, nil
 }
 
 // Start starts the notification monitor
@@ -61,6 +83,22 @@ func (m *Monitor) Start() error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	if m.statusPort > 0 {
+		go func() {
+			select {
+			case <-m.stopChan:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		err := m.streamEvents(ctx, fmt.Sprintf("http://localhost:%d/events", m.statusPort))
+		if !errors.Is(err, errEventsUnsupported) {
+			return err
+		}
+		// Older images have no event stream; fall back to pattern matching on logs
+	}
+
 	// Start inactivity checker
 	go m.checkInactivity(ctx)
 
@@ -101,6 +139,59 @@ func (m *Monitor) Start() error {
 	return nil
 }
 
+// streamEvents notifies on events from the container's /events stream. It waits
+// up to eventStreamWait for the stream to first come up, returning
+// errEventsUnsupported if it never does, and reconnects after drops until the
+// container goes away or the monitor is stopped.
+func (m *Monitor) streamEvents(ctx context.Context, url string) error {
+	connected := false
+	deadline := time.Now().Add(eventStreamWait)
+
+	for {
+		up := false
+		err := subscribeEvents(ctx, url, func() { up = true }, m.processEvent)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, errEventsUnsupported) && !connected {
+			return err
+		}
+		if up {
+			// The stream dropped; give the status server the same grace
+			// period as at startup to come back
+			connected = true
+			deadline = time.Now().Add(eventStreamWait)
+		}
+		if time.Now().After(deadline) {
+			if connected {
+				// The container has stopped
+				return nil
+			}
+			return errEventsUnsupported
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(eventRetryInterval):
+		}
+	}
+}
+
+// processEvent sends a notification for a structured container event
+func (m *Monitor) processEvent(event Event) {
+	if !m.cfg.Enabled {
+		return
+	}
+
+	message := event.Describe()
+	if message == "" || !m.cooldown.CanNotify() {
+		return
+	}
+	m.notify(fmt.Sprintf("Frank - %s", m.containerName), message)
+	m.cooldown.RecordNotification()
+}
+
 // Stop stops the notification monitor
 func (m *Monitor) Stop() {
 	m.mu.Lock()
@@ -138,16 +229,24 @@ func (m *Monitor) processLine(line string) {
 	}
 }
 
-// sendNotification sends a desktop notification
+// sendNotification sends a notification for a matching log line
 func (m *Monitor) sendNotification(line string) {
-	title := fmt.Sprintf("Frank - %s", m.containerName)
-	message := m.detector.ExtractMessage(line)
+	m.notify(fmt.Sprintf("Frank - %s", m.containerName), m.detector.ExtractMessage(line))
+}
 
-	if m.cfg.Sound {
-		m.notifier.SendWithSound(title, message)
-	} else {
-		m.notifier.Send(title, message)
-	}
+// notify sends in the background so slow webhooks don't stall log processing
+func (m *Monitor) notify(title, message string) {
+	go func() {
+		var err error
+		if m.cfg.Sound {
+			err = m.notifier.SendWithSound(title, message)
+		} else {
+			err = m.notifier.Send(title, message)
+		}
+		if err != nil {
+			slog.Debug("notification failed", "container", m.containerName, "error", err)
+		}
+	}()
 }
 
 // checkInactivity monitors for inactivity
@@ -168,14 +267,7 @@ func (m *Monitor) checkInactivity(ctx context.Context) {
 
 			inactiveDuration := time.Since(m.lastActivity)
 			if inactiveDuration > m.cfg.InactivityTimeout && m.cooldown.CanNotify() {
-				title := fmt.Sprintf("Frank - %s", m.containerName)
-				message := "Claude may be waiting for input (inactive)"
-
-				if m.cfg.Sound {
-					m.notifier.SendWithSound(title, message)
-				} else {
-					m.notifier.Send(title, message)
-				}
+				m.notify(fmt.Sprintf("Frank - %s", m.containerName), "Claude may be waiting for input (inactive)")
 				m.cooldown.RecordNotification()
 			}
 		}
@@ -1,12 +1,14 @@
 package notification
 
 import (
+	"errors"
 	"sync"
 
+	"github.com/barff/frank/internal/config"
 	"github.com/gen2brain/beeep"
 )
 
-// Notifier sends desktop notifications
+// Notifier sends notifications to the desktop or a remote channel
 type Notifier interface {
 	Send(title, message string) error
 	SendWithSound(title, message string) error
@@ -83,3 +85,98 @@ func (n *BeeepNotifier) Toggle() bool {
 	n.enabled = !n.enabled
 	return n.enabled
 }
+
+// MultiNotifier fans a notification out to several notifiers
+type MultiNotifier struct {
+	notifiers []Notifier
+	enabled   bool
+	mu        sync.RWMutex
+}
+
+// NewMultiNotifier creates a notifier that sends to all of notifiers
+func NewMultiNotifier(notifiers ...Notifier) *MultiNotifier {
+	return &MultiNotifier{
+		notifiers: notifiers,
+		enabled:   true,
+	}
+}
+
+// NewProfileNotifier creates the desktop notifier plus every configured backend
+// that applies to profile. Backends with an empty profile list apply to all.
+func NewProfileNotifier(cfg config.NotificationConfig, profile string) (*MultiNotifier, error) {
+	notifiers := []Notifier{NewBeeepNotifier()}
+
+	for _, backend := range cfg.Backends {
+		if len(backend.Profiles) > 0 && !containsString(backend.Profiles, profile) {
+			continue
+		}
+		n, err := NewBackendNotifier(backend, profile)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, n)
+	}
+
+	return NewMultiNotifier(notifiers...), nil
+}
+
+// Send sends to every notifier concurrently and returns their combined errors
+func (m *MultiNotifier) Send(title, message string) error {
+	return m.each(func(n Notifier) error { return n.Send(title, message) })
+}
+
+// SendWithSound sends with sound to every notifier concurrently
+func (m *MultiNotifier) SendWithSound(title, message string) error {
+	return m.each(func(n Notifier) error { return n.SendWithSound(title, message) })
+}
+
+func (m *MultiNotifier) each(send func(Notifier) error) error {
+	if !m.IsEnabled() {
+		return nil
+	}
+
+	errs := make([]error, len(m.notifiers))
+	var wg sync.WaitGroup
+	for i, n := range m.notifiers {
+		wg.Add(1)
+		go func(i int, n Notifier) {
+			defer wg.Done()
+			errs[i] = send(n)
+		}(i, n)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// SetEnabled enables or disables all notifications
+func (m *MultiNotifier) SetEnabled(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enabled = enabled
+}
+
+// IsEnabled returns whether notifications are enabled
+func (m *MultiNotifier) IsEnabled() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enabled
+}
+
+// Toggle toggles notifications on/off
+func (m *MultiNotifier) Toggle() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enabled = !m.enabled
+	return m.enabled
+}
+
+// containsString reports whether value is in list
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
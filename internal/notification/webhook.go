@@ -0,0 +1,186 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/barff/frank/internal/config"
+)
+
+// webhookTimeout bounds each remote notification request
+const webhookTimeout = 10 * time.Second
+
+// toggle holds the enabled flag shared by the remote notifiers
+type toggle struct {
+	enabled bool
+	mu      sync.RWMutex
+}
+
+// SetEnabled enables or disables notifications
+func (t *toggle) SetEnabled(enabled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.enabled = enabled
+}
+
+// IsEnabled returns whether notifications are enabled
+func (t *toggle) IsEnabled() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.enabled
+}
+
+// SlackNotifier posts notifications to a Slack incoming webhook
+type SlackNotifier struct {
+	toggle
+	url    string
+	client *http.Client
+}
+
+// NewSlackNotifier creates a notifier for a Slack incoming webhook URL
+func NewSlackNotifier(url string) *SlackNotifier {
+	return &SlackNotifier{
+		toggle: toggle{enabled: true},
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// Send posts a message to the Slack channel
+func (n *SlackNotifier) Send(title, message string) error {
+	if !n.IsEnabled() {
+		return nil
+	}
+	return postJSON(n.client, n.url, nil, map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s", title, message),
+	})
+}
+
+// SendWithSound is the same as Send; Slack decides how to alert
+func (n *SlackNotifier) SendWithSound(title, message string) error {
+	return n.Send(title, message)
+}
+
+// DiscordNotifier posts notifications to a Discord channel webhook
+type DiscordNotifier struct {
+	toggle
+	url    string
+	client *http.Client
+}
+
+// NewDiscordNotifier creates a notifier for a Discord webhook URL
+func NewDiscordNotifier(url string) *DiscordNotifier {
+	return &DiscordNotifier{
+		toggle: toggle{enabled: true},
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// Send posts a message to the Discord channel
+func (n *DiscordNotifier) Send(title, message string) error {
+	if !n.IsEnabled() {
+		return nil
+	}
+	return postJSON(n.client, n.url, nil, map[string]string{
+		"content": fmt.Sprintf("**%s**\n%s", title, message),
+	})
+}
+
+// SendWithSound is the same as Send; Discord decides how to alert
+func (n *DiscordNotifier) SendWithSound(title, message string) error {
+	return n.Send(title, message)
+}
+
+// WebhookNotifier posts a JSON payload to an arbitrary HTTP endpoint
+type WebhookNotifier struct {
+	toggle
+	url     string
+	headers map[string]string
+	profile string
+	client  *http.Client
+}
+
+// webhookPayload is the body sent by WebhookNotifier
+type webhookPayload struct {
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	Profile   string    `json:"profile,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewWebhookNotifier creates a notifier for a generic webhook. Headers are
+// added to every request, e.g. for an Authorization token.
+func NewWebhookNotifier(url string, headers map[string]string, profile string) *WebhookNotifier {
+	return &WebhookNotifier{
+		toggle:  toggle{enabled: true},
+		url:     url,
+		headers: headers,
+		profile: profile,
+		client:  &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// Send posts the notification as JSON
+func (n *WebhookNotifier) Send(title, message string) error {
+	if !n.IsEnabled() {
+		return nil
+	}
+	return postJSON(n.client, n.url, n.headers, webhookPayload{
+		Title:     title,
+		Message:   message,
+		Profile:   n.profile,
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// SendWithSound is the same as Send; the receiver decides how to alert
+func (n *WebhookNotifier) SendWithSound(title, message string) error {
+	return n.Send(title, message)
+}
+
+// postJSON sends body as a JSON POST and treats any non-2xx response as an error
+func postJSON(client *http.Client, url string, headers map[string]string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification rejected: %s", resp.Status)
+	}
+	return nil
+}
+
+// NewBackendNotifier creates the notifier for a configured backend
+func NewBackendNotifier(backend config.NotificationBackend, profile string) (Notifier, error) {
+	switch backend.Type {
+	case "slack":
+		return NewSlackNotifier(backend.URL), nil
+	case "discord":
+		return NewDiscordNotifier(backend.URL), nil
+	case "webhook":
+		return NewWebhookNotifier(backend.URL, backend.Headers, profile), nil
+	default:
+		return nil, fmt.Errorf("unknown notification backend %q", backend.Type)
+	}
+}
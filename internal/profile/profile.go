@@ -1,5 +1,14 @@
 package profile
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/barff/frank/internal/agent"
+)
+
 // Profile represents a Frank ECS profile configuration
 type Profile struct {
 	Name        string `yaml:"name,omitempty" json:"name"`
@@ -7,6 +16,93 @@ type Profile struct {
 	Branch      string `yaml:"branch,omitempty" json:"branch,omitempty"`
 	Description string `yaml:"description,omitempty" json:"description,omitempty"`
 	SiteURL     string `yaml:"site_url,omitempty" json:"site_url,omitempty"`
+	Agent       string `yaml:"agent,omitempty" json:"agent,omitempty"`       // Coding agent run in the terminal (default: claude)
+	Model       string `yaml:"model,omitempty" json:"model,omitempty"`       // Model passed to the agent's --model flag
+	Provider    string `yaml:"provider,omitempty" json:"provider,omitempty"` // Model provider, e.g. bedrock (default: the agent's own)
+	Endpoint    string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"` // Provider endpoint, e.g. an Ollama or Azure OpenAI URL
+
+	PrewarmWorkers int           `yaml:"prewarm_workers,omitempty" json:"prewarm_workers,omitempty"` // Worktrees 'ecs prewarm' creates (default 4)
+	BootTimeout    time.Duration `yaml:"boot_timeout,omitempty" json:"boot_timeout,omitempty"`       // How long 'ecs start' waits for the task to turn healthy (default 5m)
+	HealthPath     string        `yaml:"health_path,omitempty" json:"health_path,omitempty"`         // ALB health check, "/path" or ":port/path" (default /health on 7683)
+	Record         bool          `yaml:"record,omitempty" json:"record,omitempty"`                   // Record the agent terminal and upload it to S3 (see 'frank recordings')
+}
+
+// Agents lists the coding agents available in the frank image
+var Agents = []string{"claude", "codex"}
+
+// DefaultAgent is used when a profile doesn't name an agent
+const DefaultAgent = "claude"
+
+// Models suggests common models for each agent; any model name the agent
+// accepts can be used
+var Models = map[string][]string{
+	"claude": {"sonnet", "opus", "haiku"},
+	"codex":  {"gpt-5-codex", "gpt-5"},
+}
+
+// ValidateAgent returns an error if agent is not one of Agents
+func ValidateAgent(agent string) error {
+	if agent == "" {
+		return nil
+	}
+	for _, a := range Agents {
+		if a == agent {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown agent %q (valid: %s)", agent, strings.Join(Agents, ", "))
+}
+
+// AgentConfig returns the profile's agent and model provider selection
+func (p *Profile) AgentConfig() agent.Config {
+	return agent.Config{
+		Agent:    p.Agent,
+		Provider: p.Provider,
+		Model:    p.Model,
+		Endpoint: p.Endpoint,
+	}
+}
+
+// HealthCheck returns the port and path of the profile's health check.
+// Empty values mean the defaults.
+func (p *Profile) HealthCheck() (port, path string, err error) {
+	return ParseHealthPath(p.HealthPath)
+}
+
+// ParseHealthPath splits a health_path value, "/path" for the status port or
+// ":port/path" for another one
+func ParseHealthPath(spec string) (port, path string, err error) {
+	if spec == "" {
+		return "", "", nil
+	}
+	if !strings.HasPrefix(spec, ":") {
+		if !strings.HasPrefix(spec, "/") {
+			return "", "", fmt.Errorf("invalid health path %q (use /path or :port/path)", spec)
+		}
+		return "", spec, nil
+	}
+
+	slash := strings.Index(spec, "/")
+	if slash < 0 {
+		return "", "", fmt.Errorf("invalid health path %q (use /path or :port/path)", spec)
+	}
+	port, path = spec[1:slash], spec[slash:]
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", "", fmt.Errorf("invalid port in health path %q", spec)
+	}
+	return port, path, nil
+}
+
+// ValidateBoot checks the profile's prewarm and boot settings
+func (p *Profile) ValidateBoot() error {
+	if p.PrewarmWorkers < 0 {
+		return fmt.Errorf("prewarm_workers must not be negative")
+	}
+	if p.BootTimeout < 0 {
+		return fmt.Errorf("boot_timeout must not be negative")
+	}
+	_, _, err := p.HealthCheck()
+	return err
 }
 
 // ProfileConfig holds all profiles
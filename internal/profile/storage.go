@@ -1,6 +1,7 @@
 package profile
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,6 +12,9 @@ import (
 
 const profilesFileName = "profiles.yaml"
 
+// ErrNotFound is returned, wrapped with the name, for a profile that doesn't exist
+var ErrNotFound = errors.New("not found")
+
 // getConfigDir returns the configuration directory based on OS
 func getConfigDir() string {
 	home, _ := os.UserHomeDir()
@@ -47,9 +51,19 @@ func LoadProfiles() (*ProfileConfig, error) {
 		return nil, fmt.Errorf("failed to read profiles file: %w", err)
 	}
 
+	config, err := ParseProfiles(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse profiles file: %w", err)
+	}
+	return config, nil
+}
+
+// ParseProfiles parses profiles in the profiles.yaml format, as written by
+// SaveProfiles and 'frank profile export'
+func ParseProfiles(data []byte) (*ProfileConfig, error) {
 	var config ProfileConfig
 	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse profiles file: %w", err)
+		return nil, err
 	}
 
 	// Initialize map if nil
@@ -98,7 +112,7 @@ func GetProfile(name string) (*Profile, error) {
 
 	profile, ok := config.Profiles[name]
 	if !ok {
-		return nil, fmt.Errorf("profile %q not found", name)
+		return nil, fmt.Errorf("profile %q %w", name, ErrNotFound)
 	}
 
 	return profile, nil
@@ -123,7 +137,7 @@ func RemoveProfile(name string) error {
 	}
 
 	if _, ok := config.Profiles[name]; !ok {
-		return fmt.Errorf("profile %q not found", name)
+		return fmt.Errorf("profile %q %w", name, ErrNotFound)
 	}
 
 	delete(config.Profiles, name)
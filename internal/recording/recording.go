@@ -0,0 +1,253 @@
+// Package recording reads the asciinema v2 cast files frank's terminal
+// recorder writes, replays them and turns them into plain text.
+package recording
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Ext is the file extension of cast files
+const Ext = ".cast"
+
+// Header is the first line of a cast file
+type Header struct {
+	Version   int    `json:"version"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+// Event is one line after the header: output ("o"), input ("i"), a resize
+// ("r", data "COLSxROWS") or a marker ("m"), Time seconds into the recording
+type Event struct {
+	Time float64
+	Type string
+	Data string
+}
+
+// Reader reads a cast file's events in order
+type Reader struct {
+	Header Header
+	r      *bufio.Reader
+	line   int
+}
+
+// NewReader reads the header of a cast file
+func NewReader(r io.Reader) (*Reader, error) {
+	cr := &Reader{r: bufio.NewReader(r)}
+	line, err := cr.readLine()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("recording is empty")
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(line, &cr.Header); err != nil {
+		return nil, fmt.Errorf("invalid recording header: %w", err)
+	}
+	if cr.Header.Version != 2 {
+		return nil, fmt.Errorf("unsupported recording version %d (want asciicast v2)", cr.Header.Version)
+	}
+	return cr, nil
+}
+
+// Next returns the next event, or io.EOF after the last one. A truncated
+// last line, as a recording still being written can have, ends the events.
+func (cr *Reader) Next() (Event, error) {
+	for {
+		line, err := cr.readLine()
+		if err != nil {
+			return Event{}, err
+		}
+		if len(line) == 0 {
+			continue
+		}
+
+		var raw []json.RawMessage
+		if err := json.Unmarshal(line, &raw); err != nil || len(raw) != 3 {
+			if _, err := cr.r.Peek(1); err == io.EOF {
+				return Event{}, io.EOF
+			}
+			return Event{}, fmt.Errorf("invalid event on line %d", cr.line)
+		}
+		var ev Event
+		if json.Unmarshal(raw[0], &ev.Time) != nil || json.Unmarshal(raw[1], &ev.Type) != nil || json.Unmarshal(raw[2], &ev.Data) != nil {
+			return Event{}, fmt.Errorf("invalid event on line %d", cr.line)
+		}
+		return ev, nil
+	}
+}
+
+func (cr *Reader) readLine() ([]byte, error) {
+	line, err := cr.r.ReadBytes('\n')
+	if err != nil && (err != io.EOF || len(line) == 0) {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	cr.line++
+	return bytes.TrimSpace(line), nil
+}
+
+// Duration returns how long the recording at path runs, the time of its last
+// event. Only the end of the file is read.
+func Duration(path string) (time.Duration, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	const tail = 64 * 1024
+	offset := info.Size() - tail
+	if offset < 0 {
+		offset = 0
+	}
+	buf := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(buf, offset); err != nil && err != io.EOF {
+		return 0, err
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf), []byte("\n"))
+	for i := len(lines) - 1; i >= 0; i-- {
+		var raw []json.RawMessage
+		if json.Unmarshal(lines[i], &raw) != nil || len(raw) != 3 {
+			continue
+		}
+		var t float64
+		if json.Unmarshal(raw[0], &t) == nil {
+			return time.Duration(t * float64(time.Second)), nil
+		}
+	}
+	return 0, nil
+}
+
+// Play writes the recording's output to w with its original timing, sped up
+// by speed. Pauses longer than idleLimit are cut to idleLimit when it is set.
+func Play(w io.Writer, cr *Reader, speed float64, idleLimit time.Duration) error {
+	if speed <= 0 {
+		speed = 1
+	}
+	last := 0.0
+	for {
+		ev, err := cr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if ev.Type != "o" {
+			continue
+		}
+
+		wait := time.Duration((ev.Time - last) / speed * float64(time.Second))
+		if idleLimit > 0 && wait > idleLimit {
+			wait = idleLimit
+		}
+		if wait > 0 {
+			time.Sleep(wait)
+		}
+		last = ev.Time
+
+		if _, err := io.WriteString(w, ev.Data); err != nil {
+			return err
+		}
+	}
+}
+
+// WriteText writes the recording's output to w as plain text, without
+// escape sequences or carriage returns
+func WriteText(w io.Writer, cr *Reader) error {
+	bw := bufio.NewWriter(w)
+	var s stripper
+	for {
+		ev, err := cr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if ev.Type == "o" {
+			bw.WriteString(s.strip(ev.Data))
+		}
+	}
+	return bw.Flush()
+}
+
+// stripper removes terminal escape sequences from output that may split a
+// sequence across events
+type stripper struct {
+	state int
+}
+
+const (
+	stateText = iota
+	stateEscape
+	stateCSI
+	stateString // OSC, DCS and friends, ended by BEL or ESC \
+	stateStringEscape
+)
+
+func (s *stripper) strip(data string) string {
+	var b strings.Builder
+	for _, r := range data {
+		switch s.state {
+		case stateText:
+			switch {
+			case r == 0x1b:
+				s.state = stateEscape
+			case r == '\n' || r == '\t':
+				b.WriteRune(r)
+			case r < 0x20 || r == 0x7f:
+				// Other control characters, including \r and BEL
+			default:
+				b.WriteRune(r)
+			}
+		case stateEscape:
+			switch r {
+			case '[':
+				s.state = stateCSI
+			case ']', 'P', '_', '^', 'X':
+				s.state = stateString
+			case '(', ')', '*', '+', '#', '%':
+				// Charset selection takes one more character
+				s.state = stateCSI
+			default:
+				s.state = stateText
+			}
+		case stateCSI:
+			if r >= 0x40 && r <= 0x7e {
+				s.state = stateText
+			}
+		case stateString:
+			switch r {
+			case 0x07:
+				s.state = stateText
+			case 0x1b:
+				s.state = stateStringEscape
+			}
+		case stateStringEscape:
+			if r == '\\' {
+				s.state = stateText
+			} else {
+				s.state = stateString
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+// Package redact masks credentials in text before frank prints it.
+//
+// It recognizes GitHub tokens, OpenAI and Anthropic style sk- keys, pnyx_
+// tokens, AWS access key IDs, and the values of AWS secret key and session
+// token assignments. A masked token keeps its prefix so it's still clear what
+// kind of credential was there.
+package redact
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+)
+
+// Mask replaces the secret part of a matched token
+const Mask = "****"
+
+// tokenPatterns match self-identifying tokens. Group 1 is the prefix kept
+// in the output.
+var tokenPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\b(gh[pousr]_)[A-Za-z0-9]{20,}`),
+	regexp.MustCompile(`\b(github_pat_)[A-Za-z0-9_]{20,}`),
+	regexp.MustCompile(`\b(sk-)[A-Za-z0-9_-]{16,}`),
+	regexp.MustCompile(`\b(pnyx_)[A-Za-z0-9_-]{8,}`),
+	regexp.MustCompile(`\b((?:AKIA|ASIA))[A-Z0-9]{16}\b`),
+}
+
+// assignmentPattern matches AWS secrets that only stand out by the name
+// they're assigned to, in env, ini, JSON and CLI flag form. Group 1 is kept.
+var assignmentPattern = regexp.MustCompile(
+	`(?i)((?:aws_secret_access_key|aws_session_token|secretaccesskey|sessiontoken|--secret-access-key|--session-token)["']?\s*[:=\s]\s*["']?)[A-Za-z0-9/+=]{16,}`)
+
+// String returns s with every recognized credential masked
+func String(s string) string {
+	for _, re := range tokenPatterns {
+		s = re.ReplaceAllString(s, "${1}"+Mask)
+	}
+	return assignmentPattern.ReplaceAllString(s, "${1}"+Mask)
+}
+
+// Writer masks credentials in text written through it. Output is buffered
+// up to each newline so a token split across writes is still caught; Flush
+// writes any trailing partial line.
+type Writer struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewWriter returns a Writer that writes masked text to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write buffers p and writes every complete line, masked
+func (rw *Writer) Write(p []byte) (int, error) {
+	rw.buf = append(rw.buf, p...)
+	end := bytes.LastIndexByte(rw.buf, '\n')
+	if end < 0 {
+		return len(p), nil
+	}
+	lines := rw.buf[:end+1]
+	if _, err := io.WriteString(rw.w, String(string(lines))); err != nil {
+		return 0, err
+	}
+	rw.buf = append(rw.buf[:0], rw.buf[end+1:]...)
+	return len(p), nil
+}
+
+// Flush writes the buffered partial line, masked
+func (rw *Writer) Flush() error {
+	if len(rw.buf) == 0 {
+		return nil
+	}
+	_, err := io.WriteString(rw.w, String(string(rw.buf)))
+	rw.buf = rw.buf[:0]
+	return err
+}
@@ -0,0 +1,319 @@
+// Package render prints command output as an aligned table, or as JSON or
+// YAML records built from the same rows.
+//
+// Tables use frank's borderless layout. Cells longer than their column's
+// MaxWidth are truncated unless NoTrunc is set, and on a terminal too narrow
+// for the whole table the columns with the highest Hide are dropped first,
+// unless Wide is set. JSON and YAML output is never truncated or hidden.
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+)
+
+// Options are how a command was asked to print its output
+type Options struct {
+	Format  string // table, json or yaml
+	NoTrunc bool   // print table cells in full
+	Wide    bool   // never hide columns on narrow terminals
+}
+
+// AddFlags adds --format, --no-trunc and --wide to cmd
+func AddFlags(cmd *cobra.Command, opts *Options) {
+	cmd.Flags().StringVar(&opts.Format, "format", FormatTable, "Output format: table, json, yaml")
+	cmd.Flags().BoolVar(&opts.NoTrunc, "no-trunc", false, "Don't truncate table cells")
+	cmd.Flags().BoolVar(&opts.Wide, "wide", false, "Show every column, even on narrow terminals")
+}
+
+// Validate checks the format is one render knows
+func (o Options) Validate() error {
+	switch o.Format {
+	case "", FormatTable, FormatJSON, FormatYAML:
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q (use table, json or yaml)", o.Format)
+}
+
+// Structured reports whether output is JSON or YAML rather than a table
+func (o Options) Structured() bool {
+	return o.Format == FormatJSON || o.Format == FormatYAML
+}
+
+// Column describes one column of a table
+type Column struct {
+	Header string
+
+	// Key names the field in JSON and YAML records; it defaults to the
+	// header in lower case with spaces as underscores
+	Key string
+
+	// MaxWidth truncates longer cells with "..." unless NoTrunc is set;
+	// 0 never truncates
+	MaxWidth int
+
+	// TruncateLeft keeps the end of a truncated cell, for paths and URLs
+	TruncateLeft bool
+
+	// Hide is the order columns are dropped in when the table is wider than
+	// the terminal, highest first; 0 is never dropped
+	Hide int
+}
+
+func (c Column) key() string {
+	if c.Key != "" {
+		return c.Key
+	}
+	return strings.ReplaceAll(strings.ToLower(c.Header), " ", "_")
+}
+
+// Table collects rows and renders them in the format Options ask for
+type Table struct {
+	opts    Options
+	columns []Column
+	rows    [][]string
+}
+
+// NewTable returns an empty table with columns
+func NewTable(opts Options, columns ...Column) *Table {
+	return &Table{opts: opts, columns: columns}
+}
+
+// Append adds a row; missing cells are left empty and extra ones dropped
+func (t *Table) Append(cells ...string) {
+	row := make([]string, len(t.columns))
+	copy(row, cells)
+	t.rows = append(t.rows, row)
+}
+
+// Len returns the number of rows
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
+// Render writes the table to w
+func (t *Table) Render(w io.Writer) error {
+	if err := t.opts.Validate(); err != nil {
+		return err
+	}
+
+	switch t.opts.Format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(t.records())
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(t.records()); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	rows := make([][]string, len(t.rows))
+	for i, row := range t.rows {
+		rows[i] = make([]string, len(row))
+		for j, cell := range row {
+			rows[i][j] = t.truncate(t.columns[j], cell)
+		}
+	}
+
+	visible := t.visibleColumns(rows, terminalWidth(w))
+
+	table := tablewriter.NewWriter(w)
+	header := make([]string, len(visible))
+	for i, col := range visible {
+		header[i] = t.columns[col].Header
+	}
+	table.SetHeader(header)
+	table.SetBorder(false)
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetTablePadding("  ")
+	table.SetNoWhiteSpace(true)
+
+	for _, row := range rows {
+		cells := make([]string, len(visible))
+		for i, col := range visible {
+			cells[i] = row[col]
+		}
+		table.Append(cells)
+	}
+	table.Render()
+	return nil
+}
+
+// truncate shortens cell to the column's MaxWidth unless NoTrunc is set
+func (t *Table) truncate(col Column, cell string) string {
+	if t.opts.NoTrunc || col.MaxWidth <= 3 || tablewriter.DisplayWidth(cell) <= col.MaxWidth {
+		return cell
+	}
+
+	// Escape codes can't be cut safely, so colored cells lose their color
+	runes := []rune(stripANSI(cell))
+	width := 0
+	if col.TruncateLeft {
+		i := len(runes)
+		for i > 0 && width+tablewriter.DisplayWidth(string(runes[i-1])) <= col.MaxWidth-3 {
+			i--
+			width += tablewriter.DisplayWidth(string(runes[i]))
+		}
+		return "..." + string(runes[i:])
+	}
+	i := 0
+	for i < len(runes) && width+tablewriter.DisplayWidth(string(runes[i])) <= col.MaxWidth-3 {
+		width += tablewriter.DisplayWidth(string(runes[i]))
+		i++
+	}
+	return string(runes[:i]) + "..."
+}
+
+// visibleColumns returns the indexes of the columns that fit in width,
+// dropping the highest Hide first. A width of 0 keeps every column.
+func (t *Table) visibleColumns(rows [][]string, width int) []int {
+	widths := make([]int, len(t.columns))
+	for i, col := range t.columns {
+		widths[i] = tablewriter.DisplayWidth(col.Header)
+		for _, row := range rows {
+			widths[i] = max(widths[i], tablewriter.DisplayWidth(row[i]))
+		}
+	}
+
+	hidden := make([]bool, len(t.columns))
+	for !t.opts.Wide && width > 0 && tableWidth(widths, hidden) > width {
+		drop := -1
+		for i, col := range t.columns {
+			if !hidden[i] && col.Hide > 0 && (drop < 0 || col.Hide > t.columns[drop].Hide) {
+				drop = i
+			}
+		}
+		if drop < 0 {
+			break
+		}
+		hidden[drop] = true
+	}
+
+	var visible []int
+	for i := range t.columns {
+		if !hidden[i] {
+			visible = append(visible, i)
+		}
+	}
+	return visible
+}
+
+// tableWidth is the printed width of the columns not hidden, with two
+// spaces of padding between them
+func tableWidth(widths []int, hidden []bool) int {
+	total, n := 0, 0
+	for i, w := range widths {
+		if !hidden[i] {
+			total += w
+			n++
+		}
+	}
+	if n > 1 {
+		total += 2 * (n - 1)
+	}
+	return total
+}
+
+// terminalWidth returns the width of the terminal w writes to, or 0 when it
+// isn't one
+func terminalWidth(w io.Writer) int {
+	f, ok := w.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return 0
+	}
+	width, _, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// records returns the rows as key/value records in column order, without
+// colors
+func (t *Table) records() []record {
+	records := make([]record, len(t.rows))
+	for i, row := range t.rows {
+		rec := make(record, len(t.columns))
+		for j, col := range t.columns {
+			rec[j] = field{Key: col.key(), Value: stripANSI(row[j])}
+		}
+		records[i] = rec
+	}
+	return records
+}
+
+type field struct {
+	Key   string
+	Value string
+}
+
+// record is a row whose fields marshal in column order
+type record []field
+
+func (r record) MarshalJSON() ([]byte, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, f := range r {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(f.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return []byte(b.String()), nil
+}
+
+func (r record) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, f := range r {
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: f.Key},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: f.Value},
+		)
+	}
+	return node, nil
+}
+
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// stripANSI removes color escape codes
+func stripANSI(s string) string {
+	return ansiEscape.ReplaceAllString(s, "")
+}
@@ -0,0 +1,198 @@
+// Package scan checks container images for known vulnerabilities with
+// Trivy, run as a subprocess against the local image store.
+package scan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Severities from most to least severe, as Trivy reports them
+var Severities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", "UNKNOWN"}
+
+// Vulnerability is one finding in one package
+type Vulnerability struct {
+	ID               string `json:"id"`
+	Severity         string `json:"severity"`
+	Package          string `json:"package"`
+	InstalledVersion string `json:"installed_version"`
+	FixedVersion     string `json:"fixed_version,omitempty"`
+	Title            string `json:"title,omitempty"`
+	Target           string `json:"target"` // OS packages or the file the package came from
+}
+
+// Report is the result of scanning one image
+type Report struct {
+	Image           string          `json:"image"`
+	ImageID         string          `json:"image_id,omitempty"`
+	ScannedAt       time.Time       `json:"scanned_at"`
+	Counts          map[string]int  `json:"counts"` // by severity
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+}
+
+// Options controls Scan
+type Options struct {
+	// Runtime is the container runtime holding the image, docker or podman
+	Runtime string
+
+	// Stderr receives Trivy's progress output, such as database downloads;
+	// nil discards it
+	Stderr io.Writer
+}
+
+// trivyOutput is the part of 'trivy image --format json' that Scan reads
+type trivyOutput struct {
+	Metadata struct {
+		ImageID string `json:"ImageID"`
+	} `json:"Metadata"`
+	Results []struct {
+		Target          string `json:"Target"`
+		Vulnerabilities []struct {
+			VulnerabilityID  string `json:"VulnerabilityID"`
+			PkgName          string `json:"PkgName"`
+			InstalledVersion string `json:"InstalledVersion"`
+			FixedVersion     string `json:"FixedVersion"`
+			Severity         string `json:"Severity"`
+			Title            string `json:"Title"`
+		} `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+// Available reports whether the trivy binary is in PATH
+func Available() bool {
+	_, err := exec.LookPath("trivy")
+	return err == nil
+}
+
+// Scan runs Trivy against a local image and returns its vulnerabilities,
+// most severe first
+func Scan(ctx context.Context, image string, opts Options) (*Report, error) {
+	if !Available() {
+		return nil, errors.New("trivy not found in PATH (install it from https://trivy.dev)")
+	}
+
+	args := []string{"image", "--format", "json", "--scanners", "vuln", "--quiet"}
+	if opts.Runtime == "podman" {
+		args = append(args, "--image-src", "podman")
+	} else {
+		args = append(args, "--image-src", "docker")
+	}
+	args = append(args, image)
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, "trivy", args...)
+	cmd.Stdout = &stdout
+	if opts.Stderr != nil {
+		cmd.Stderr = opts.Stderr
+	}
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("trivy failed: %w", err)
+	}
+
+	var out trivyOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return nil, fmt.Errorf("failed to parse trivy output: %w", err)
+	}
+
+	report := &Report{
+		Image:     image,
+		ImageID:   out.Metadata.ImageID,
+		ScannedAt: time.Now().UTC(),
+		Counts:    map[string]int{},
+	}
+	for _, result := range out.Results {
+		for _, v := range result.Vulnerabilities {
+			severity := strings.ToUpper(v.Severity)
+			if rank(severity) == len(Severities) {
+				severity = "UNKNOWN"
+			}
+			report.Counts[severity]++
+			report.Vulnerabilities = append(report.Vulnerabilities, Vulnerability{
+				ID:               v.VulnerabilityID,
+				Severity:         severity,
+				Package:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+				Title:            v.Title,
+				Target:           result.Target,
+			})
+		}
+	}
+	sort.SliceStable(report.Vulnerabilities, func(i, j int) bool {
+		return rank(report.Vulnerabilities[i].Severity) < rank(report.Vulnerabilities[j].Severity)
+	})
+	return report, nil
+}
+
+// AtLeast returns how many vulnerabilities are at severity or above
+func (r *Report) AtLeast(severity string) int {
+	limit := rank(strings.ToUpper(severity))
+	n := 0
+	for i, s := range Severities {
+		if i <= limit {
+			n += r.Counts[s]
+		}
+	}
+	return n
+}
+
+// Summary returns the counts by severity, e.g. "CRITICAL 1, HIGH 4, MEDIUM 12"
+func (r *Report) Summary() string {
+	var parts []string
+	for _, s := range Severities {
+		if n := r.Counts[s]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", s, n))
+		}
+	}
+	if len(parts) == 0 {
+		return "no vulnerabilities"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Save writes the report to dir as <image-id>.json, or a name derived from
+// the image when Trivy didn't report an ID, and returns the path
+func (r *Report) Save(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	name := strings.TrimPrefix(r.ImageID, "sha256:")
+	if name == "" {
+		name = strings.NewReplacer("/", "_", ":", "_").Replace(r.Image)
+	}
+	path := filepath.Join(dir, name+".json")
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// ValidSeverity reports whether s names a severity, in any case
+func ValidSeverity(s string) bool {
+	return rank(strings.ToUpper(s)) < len(Severities)
+}
+
+// rank is the index of severity in Severities, or len(Severities) if unknown
+func rank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return len(Severities)
+}
@@ -29,6 +29,12 @@ func GenerateSnapshotNameWithTag(repoURL, tag string) string {
 	return "frank-snapshot-" + shortHash + ":" + tag
 }
 
+// SameRepo reports whether two repo URLs/paths name the same repository,
+// ignoring .git suffixes, case and SSH vs HTTPS
+func SameRepo(a, b string) bool {
+	return normalizeRepoURL(a) == normalizeRepoURL(b)
+}
+
 // normalizeRepoURL normalizes a repo URL for consistent hashing
 func normalizeRepoURL(repoURL string) string {
 	// Remove trailing slashes
@@ -0,0 +1,81 @@
+package ssmexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// portForwardDocument is the SSM document that forwards a port on the target
+const portForwardDocument = "AWS-StartPortForwardingSession"
+
+// basicPortClientVersion predates multiplexed port sessions, so the agent
+// carries a single connection's bytes as plain output: one session per
+// connection
+const basicPortClientVersion = "1.1.0.0"
+
+// PortForwardInput describes the port to reach on a task's container
+type PortForwardInput struct {
+	// Target is the SSM target, ecs:<cluster>_<task-id>_<container-runtime-id>
+	Target string
+	Port   int
+}
+
+// ECSTarget returns the SSM target for a container of an ECS task
+func ECSTarget(cluster, taskID, runtimeID string) string {
+	return fmt.Sprintf("ecs:%s_%s_%s", cluster, taskID, runtimeID)
+}
+
+// StartPortForward starts a port forwarding session to the target's port.
+// The session carries a single connection.
+func StartPortForward(ctx context.Context, client *ssm.Client, input PortForwardInput) (*Session, error) {
+	out, err := client.StartSession(ctx, &ssm.StartSessionInput{
+		Target:       aws.String(input.Target),
+		DocumentName: aws.String(portForwardDocument),
+		Parameters: map[string][]string{
+			"portNumber": {strconv.Itoa(input.Port)},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+
+	return open(ctx, aws.ToString(out.SessionId), aws.ToString(out.StreamUrl), aws.ToString(out.TokenValue), basicPortClientVersion)
+}
+
+// Forward copies bytes between conn and the forwarded port until either side
+// closes, then closes conn
+func (s *Session) Forward(ctx context.Context, conn net.Conn) error {
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	err := s.Run(ctx, &cancelOnEOF{r: conn, cancel: cancel}, conn, io.Discard)
+	if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+		// The local side closed the connection
+		return nil
+	}
+	return err
+}
+
+// cancelOnEOF cancels a session once its input is exhausted, since the
+// input pump can't end the session itself
+type cancelOnEOF struct {
+	r      io.Reader
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnEOF) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if err != nil {
+		c.cancel()
+	}
+	return n, err
+}
@@ -0,0 +1,161 @@
+package ssmexec
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Message types used on the SSM data channel
+const (
+	messageTypeInput        = "input_stream_data"
+	messageTypeOutput       = "output_stream_data"
+	messageTypeAcknowledge  = "acknowledge"
+	messageTypeChannelClose = "channel_closed"
+	messageTypeStartPublish = "start_publication"
+	messageTypePausePublish = "pause_publication"
+)
+
+// PayloadType identifies the content of a data channel message
+type PayloadType uint32
+
+const (
+	PayloadOutput            PayloadType = 1
+	PayloadError             PayloadType = 2
+	PayloadSize              PayloadType = 3
+	PayloadParameter         PayloadType = 4
+	PayloadHandshakeRequest  PayloadType = 5
+	PayloadHandshakeResponse PayloadType = 6
+	PayloadHandshakeComplete PayloadType = 7
+	PayloadEncChallengeReq   PayloadType = 8
+	PayloadEncChallengeResp  PayloadType = 9
+	PayloadFlag              PayloadType = 10
+	PayloadStdErr            PayloadType = 11
+	PayloadExitCode          PayloadType = 12
+)
+
+// Binary layout of a data channel message (all integers big endian):
+//
+//	 0  HeaderLength   uint32
+//	 4  MessageType    [32]byte, space padded
+//	36  SchemaVersion  uint32
+//	40  CreatedDate    uint64, epoch millis
+//	48  SequenceNumber int64
+//	56  Flags          uint64
+//	64  MessageId      [16]byte, UUID with the two 8-byte halves swapped
+//	80  PayloadDigest  [32]byte, SHA-256 of payload
+//	112 PayloadType    uint32
+//	116 PayloadLength  uint32
+//	120 Payload
+const (
+	headerLength         = 116
+	messageTypeLength    = 32
+	offsetMessageType    = 4
+	offsetSchemaVersion  = 36
+	offsetCreatedDate    = 40
+	offsetSequenceNumber = 48
+	offsetFlags          = 56
+	offsetMessageID      = 64
+	offsetPayloadDigest  = 80
+	offsetPayloadType    = 112
+	offsetPayloadLength  = 116
+	offsetPayload        = 120
+)
+
+// message is a single frame on the SSM data channel
+type message struct {
+	MessageType    string
+	SchemaVersion  uint32
+	CreatedDate    time.Time
+	SequenceNumber int64
+	Flags          uint64
+	MessageID      [16]byte
+	PayloadType    PayloadType
+	Payload        []byte
+}
+
+// newMessage creates a message with a fresh ID and the current timestamp
+func newMessage(messageType string, seq int64, payloadType PayloadType, payload []byte) *message {
+	return &message{
+		MessageType:    messageType,
+		SchemaVersion:  1,
+		CreatedDate:    time.Now(),
+		SequenceNumber: seq,
+		MessageID:      newUUID(),
+		PayloadType:    payloadType,
+		Payload:        payload,
+	}
+}
+
+// marshal encodes the message into its binary wire format
+func (m *message) marshal() []byte {
+	buf := make([]byte, offsetPayload+len(m.Payload))
+
+	binary.BigEndian.PutUint32(buf[0:], headerLength)
+	copy(buf[offsetMessageType:offsetMessageType+messageTypeLength], bytes.Repeat([]byte(" "), messageTypeLength))
+	copy(buf[offsetMessageType:offsetMessageType+messageTypeLength], m.MessageType)
+	binary.BigEndian.PutUint32(buf[offsetSchemaVersion:], m.SchemaVersion)
+	binary.BigEndian.PutUint64(buf[offsetCreatedDate:], uint64(m.CreatedDate.UnixMilli()))
+	binary.BigEndian.PutUint64(buf[offsetSequenceNumber:], uint64(m.SequenceNumber))
+	binary.BigEndian.PutUint64(buf[offsetFlags:], m.Flags)
+
+	// The agent stores the least significant half of the UUID first
+	copy(buf[offsetMessageID:], m.MessageID[8:])
+	copy(buf[offsetMessageID+8:], m.MessageID[:8])
+
+	digest := sha256.Sum256(m.Payload)
+	copy(buf[offsetPayloadDigest:], digest[:])
+	binary.BigEndian.PutUint32(buf[offsetPayloadType:], uint32(m.PayloadType))
+	binary.BigEndian.PutUint32(buf[offsetPayloadLength:], uint32(len(m.Payload)))
+	copy(buf[offsetPayload:], m.Payload)
+
+	return buf
+}
+
+// unmarshalMessage decodes a binary frame received from the agent
+func unmarshalMessage(data []byte) (*message, error) {
+	if len(data) < offsetPayload {
+		return nil, fmt.Errorf("message too short: %d bytes", len(data))
+	}
+
+	hl := binary.BigEndian.Uint32(data[0:])
+	payloadLength := binary.BigEndian.Uint32(data[offsetPayloadLength:])
+	payloadStart := int(hl) + 4
+	if payloadStart+int(payloadLength) > len(data) {
+		return nil, fmt.Errorf("message payload truncated: want %d bytes, have %d", payloadLength, len(data)-payloadStart)
+	}
+
+	m := &message{
+		MessageType:    strings.TrimRight(string(data[offsetMessageType:offsetMessageType+messageTypeLength]), " \x00"),
+		SchemaVersion:  binary.BigEndian.Uint32(data[offsetSchemaVersion:]),
+		CreatedDate:    time.UnixMilli(int64(binary.BigEndian.Uint64(data[offsetCreatedDate:]))),
+		SequenceNumber: int64(binary.BigEndian.Uint64(data[offsetSequenceNumber:])),
+		Flags:          binary.BigEndian.Uint64(data[offsetFlags:]),
+		PayloadType:    PayloadType(binary.BigEndian.Uint32(data[offsetPayloadType:])),
+		Payload:        data[payloadStart : payloadStart+int(payloadLength)],
+	}
+	copy(m.MessageID[8:], data[offsetMessageID:offsetMessageID+8])
+	copy(m.MessageID[:8], data[offsetMessageID+8:offsetMessageID+16])
+
+	return m, nil
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() [16]byte {
+	var u [16]byte
+	rand.Read(u[:])
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return u
+}
+
+// formatUUID renders a UUID in its canonical 8-4-4-4-12 form
+func formatUUID(u [16]byte) string {
+	h := hex.EncodeToString(u[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
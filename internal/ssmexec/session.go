@@ -0,0 +1,397 @@
+// Package ssmexec implements the client side of an SSM Session Manager data
+// channel so ECS Exec sessions and port forwarding can run without the AWS
+// CLI or the session-manager-plugin binary.
+package ssmexec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/gorilla/websocket"
+)
+
+// clientVersion is reported to the agent during the handshake
+const clientVersion = "1.2.0.0"
+
+// pingInterval keeps idle sessions from being closed by intermediaries
+const pingInterval = 5 * time.Minute
+
+// Handshake action status values
+const (
+	actionSuccess     = 1
+	actionUnsupported = 3
+)
+
+// ExecInput describes the command to run in a task's container
+type ExecInput struct {
+	Cluster   string
+	Task      string
+	Container string
+	Command   string
+}
+
+// Session is an open SSM data channel attached to a command in a container
+type Session struct {
+	ID string
+
+	version string
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	// Sequence number of the next input message
+	inputSeq int64
+
+	// Output ordering: next expected sequence number and early arrivals
+	expectedSeq int64
+	pending     map[int64]*message
+
+	handshakeDone chan struct{}
+	handshakeOnce sync.Once
+}
+
+// Start calls ECS ExecuteCommand and opens the data channel for the returned session
+func Start(ctx context.Context, client *ecs.Client, input ExecInput) (*Session, error) {
+	out, err := client.ExecuteCommand(ctx, &ecs.ExecuteCommandInput{
+		Cluster:     aws.String(input.Cluster),
+		Task:        aws.String(input.Task),
+		Container:   aws.String(input.Container),
+		Command:     aws.String(input.Command),
+		Interactive: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute command: %w", err)
+	}
+	if out.Session == nil {
+		return nil, fmt.Errorf("ExecuteCommand returned no session")
+	}
+
+	return Open(ctx, aws.ToString(out.Session.SessionId), aws.ToString(out.Session.StreamUrl), aws.ToString(out.Session.TokenValue))
+}
+
+// Open connects to a session's stream URL and authenticates with its token
+func Open(ctx context.Context, sessionID, streamURL, token string) (*Session, error) {
+	return open(ctx, sessionID, streamURL, token, clientVersion)
+}
+
+// open connects to a session's stream URL, reporting version as the client
+// version the agent tailors the session to
+func open(ctx context.Context, sessionID, streamURL, token, version string) (*Session, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, streamURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to session stream: %w", err)
+	}
+
+	openInput := map[string]string{
+		"MessageSchemaVersion": "1.0",
+		"RequestId":            formatUUID(newUUID()),
+		"TokenValue":           token,
+		"ClientId":             formatUUID(newUUID()),
+		"ClientVersion":        version,
+	}
+	data, err := json.Marshal(openInput)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to marshal open request: %w", err)
+	}
+	if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open data channel: %w", err)
+	}
+
+	return &Session{
+		ID:            sessionID,
+		version:       version,
+		conn:          conn,
+		pending:       make(map[int64]*message),
+		handshakeDone: make(chan struct{}),
+	}, nil
+}
+
+// Close terminates the data channel
+func (s *Session) Close() error {
+	s.writeMu.Lock()
+	s.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	s.writeMu.Unlock()
+	return s.conn.Close()
+}
+
+// HandshakeDone is closed once the agent has completed the session handshake
+func (s *Session) HandshakeDone() <-chan struct{} {
+	return s.handshakeDone
+}
+
+// Run copies stdin to the remote command and its output to stdout/stderr until
+// the agent closes the channel or ctx is cancelled. stdin may be nil. A
+// command that reports a non-zero exit status returns an *ExitError.
+func (s *Session) Run(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go s.keepAlive(ctx)
+
+	if stdin != nil {
+		go s.pumpInput(ctx, stdin)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- s.readLoop(stdout, stderr) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		s.Close()
+		return ctx.Err()
+	}
+}
+
+// Resize tells the remote pseudo-terminal about a new window size
+func (s *Session) Resize(cols, rows int) error {
+	payload, err := json.Marshal(map[string]int{"cols": cols, "rows": rows})
+	if err != nil {
+		return err
+	}
+	return s.sendInput(PayloadSize, payload)
+}
+
+// sendInput sends an input_stream_data message with the next sequence number
+func (s *Session) sendInput(payloadType PayloadType, payload []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	msg := newMessage(messageTypeInput, s.inputSeq, payloadType, payload)
+	if err := s.conn.WriteMessage(websocket.BinaryMessage, msg.marshal()); err != nil {
+		return fmt.Errorf("failed to send input: %w", err)
+	}
+	s.inputSeq++
+	return nil
+}
+
+// acknowledge confirms receipt of an agent message so it is not resent
+func (s *Session) acknowledge(m *message) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"AcknowledgedMessageType":           m.MessageType,
+		"AcknowledgedMessageId":             formatUUID(m.MessageID),
+		"AcknowledgedMessageSequenceNumber": m.SequenceNumber,
+		"IsSequentialMessage":               true,
+	})
+	if err != nil {
+		return err
+	}
+
+	ack := newMessage(messageTypeAcknowledge, 0, 0, payload)
+	ack.Flags = 3
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.conn.WriteMessage(websocket.BinaryMessage, ack.marshal())
+}
+
+// pumpInput forwards stdin to the agent once the handshake has completed
+func (s *Session) pumpInput(ctx context.Context, stdin io.Reader) {
+	select {
+	case <-s.handshakeDone:
+	case <-ctx.Done():
+		return
+	}
+
+	buf := make([]byte, 1024)
+	for {
+		n, err := stdin.Read(buf)
+		if n > 0 {
+			data := make([]byte, n)
+			copy(data, buf[:n])
+			if sendErr := s.sendInput(PayloadOutput, data); sendErr != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (s *Session) keepAlive(ctx context.Context) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.writeMu.Lock()
+			s.conn.WriteMessage(websocket.PingMessage, nil)
+			s.writeMu.Unlock()
+		}
+	}
+}
+
+// readLoop processes agent messages until the channel is closed
+func (s *Session) readLoop(stdout, stderr io.Writer) error {
+	for {
+		msgType, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				return nil
+			}
+			return fmt.Errorf("session stream closed: %w", err)
+		}
+		if msgType != websocket.BinaryMessage {
+			continue
+		}
+
+		m, err := unmarshalMessage(data)
+		if err != nil {
+			return err
+		}
+
+		switch m.MessageType {
+		case messageTypeOutput:
+			if err := s.acknowledge(m); err != nil {
+				return fmt.Errorf("failed to acknowledge message: %w", err)
+			}
+			if err := s.handleOutput(m, stdout, stderr); err != nil {
+				if errors.Is(err, errSessionEnded) {
+					return nil
+				}
+				return err
+			}
+		case messageTypeChannelClose:
+			var closed struct {
+				Output string `json:"Output"`
+			}
+			if json.Unmarshal(m.Payload, &closed) == nil && closed.Output != "" {
+				fmt.Fprintln(stderr, closed.Output)
+			}
+			return nil
+		case messageTypeAcknowledge, messageTypeStartPublish, messageTypePausePublish:
+			// Input is not retransmitted, so acknowledgements need no bookkeeping
+		}
+	}
+}
+
+var errSessionEnded = errors.New("session ended")
+
+// ExitError is returned when the remote command exits with a non-zero status
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("remote command exited with status %d", e.Code)
+}
+
+// exitStatus turns an exit code payload into the session's result
+func exitStatus(payload []byte) error {
+	code, err := strconv.Atoi(strings.TrimSpace(string(payload)))
+	if err != nil {
+		return fmt.Errorf("invalid exit code %q from the agent", payload)
+	}
+	if code != 0 {
+		return &ExitError{Code: code}
+	}
+	return errSessionEnded
+}
+
+// handleOutput delivers output messages in sequence order, buffering any that
+// arrive early and dropping duplicates the agent resends
+func (s *Session) handleOutput(m *message, stdout, stderr io.Writer) error {
+	if m.SequenceNumber < s.expectedSeq {
+		return nil
+	}
+	if m.SequenceNumber > s.expectedSeq {
+		s.pending[m.SequenceNumber] = m
+		return nil
+	}
+
+	for {
+		if err := s.processOutput(m, stdout, stderr); err != nil {
+			return err
+		}
+		s.expectedSeq++
+
+		next, ok := s.pending[s.expectedSeq]
+		if !ok {
+			return nil
+		}
+		delete(s.pending, s.expectedSeq)
+		m = next
+	}
+}
+
+func (s *Session) processOutput(m *message, stdout, stderr io.Writer) error {
+	switch m.PayloadType {
+	case PayloadOutput:
+		_, err := stdout.Write(m.Payload)
+		return err
+	case PayloadStdErr, PayloadError:
+		_, err := stderr.Write(m.Payload)
+		return err
+	case PayloadHandshakeRequest:
+		return s.respondToHandshake(m.Payload)
+	case PayloadHandshakeComplete:
+		s.handshakeOnce.Do(func() { close(s.handshakeDone) })
+		return nil
+	case PayloadExitCode:
+		return exitStatus(m.Payload)
+	case PayloadEncChallengeReq:
+		return fmt.Errorf("session requires KMS encryption, which is not supported; disable KMS for ECS Exec or use the AWS CLI")
+	default:
+		return nil
+	}
+}
+
+// respondToHandshake accepts the requested session type and declines
+// optional actions such as KMS encryption
+func (s *Session) respondToHandshake(payload []byte) error {
+	var req struct {
+		AgentVersion           string `json:"AgentVersion"`
+		RequestedClientActions []struct {
+			ActionType string `json:"ActionType"`
+		} `json:"RequestedClientActions"`
+	}
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return fmt.Errorf("failed to parse handshake request: %w", err)
+	}
+
+	type processedAction struct {
+		ActionType   string      `json:"ActionType"`
+		ActionStatus int         `json:"ActionStatus"`
+		ActionResult interface{} `json:"ActionResult"`
+		Error        string      `json:"Error"`
+	}
+
+	var processed []processedAction
+	for _, action := range req.RequestedClientActions {
+		status := actionSuccess
+		errMsg := ""
+		if action.ActionType != "SessionType" {
+			status = actionUnsupported
+			errMsg = fmt.Sprintf("%s is not supported by this client", action.ActionType)
+		}
+		processed = append(processed, processedAction{
+			ActionType:   action.ActionType,
+			ActionStatus: status,
+			Error:        errMsg,
+		})
+	}
+
+	resp, err := json.Marshal(map[string]interface{}{
+		"ClientVersion":          s.version,
+		"ProcessedClientActions": processed,
+		"Errors":                 []string{},
+	})
+	if err != nil {
+		return err
+	}
+	return s.sendInput(PayloadHandshakeResponse, resp)
+}
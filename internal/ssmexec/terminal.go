@@ -0,0 +1,62 @@
+package ssmexec
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// resizePollInterval is how often the local terminal size is checked. Polling
+// works on every platform, unlike SIGWINCH.
+const resizePollInterval = 500 * time.Millisecond
+
+// RunInteractive attaches the session to the process's terminal: stdin is put
+// into raw mode and window size changes are forwarded to the remote shell.
+// When stdin is not a terminal it behaves like Run with the standard streams.
+func (s *Session) RunInteractive(ctx context.Context) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return s.Run(ctx, os.Stdin, os.Stdout, os.Stderr)
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return s.Run(ctx, os.Stdin, os.Stdout, os.Stderr)
+	}
+	defer term.Restore(fd, state)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go s.watchSize(ctx, int(os.Stdout.Fd()))
+
+	return s.Run(ctx, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// watchSize sends the terminal size after the handshake and whenever it changes
+func (s *Session) watchSize(ctx context.Context, fd int) {
+	select {
+	case <-s.handshakeDone:
+	case <-ctx.Done():
+		return
+	}
+
+	lastCols, lastRows := 0, 0
+	ticker := time.NewTicker(resizePollInterval)
+	defer ticker.Stop()
+
+	for {
+		if cols, rows, err := term.GetSize(fd); err == nil && (cols != lastCols || rows != lastRows) {
+			if s.Resize(cols, rows) == nil {
+				lastCols, lastRows = cols, rows
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
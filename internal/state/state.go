@@ -0,0 +1,259 @@
+// Package state keeps a local history of the containers and ECS tasks frank
+// has started in a bbolt database. Container labels disappear with the
+// container; the history keeps the repo, ports, snapshot lineage and why
+// each one stopped.
+package state
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Kinds of run
+const (
+	KindContainer = "container"
+	KindTask      = "task"
+)
+
+// openTimeout is how long Open waits for another frank process to release
+// the database
+const openTimeout = 2 * time.Second
+
+var (
+	runsBucket    = []byte("runs")    // sequence number → Run
+	latestBucket  = []byte("latest")  // run key → sequence number of its latest run
+	indexesBucket = []byte("indexes") // profile → highest container index handed out
+)
+
+// Run is one container or task from start to stop
+type Run struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`         // Container name, or the task's profile
+	ID      string `json:"id,omitempty"` // Container ID or ECS task ID
+	Profile string `json:"profile,omitempty"`
+	Repo    string `json:"repo,omitempty"`
+	Branch  string `json:"branch,omitempty"`
+	Image   string `json:"image,omitempty"`
+	Runtime string `json:"runtime,omitempty"` // Container runtime, or the ECS cluster
+	Ports   []int  `json:"ports,omitempty"`
+
+	// FromSnapshot is the snapshot image the container resumed from
+	FromSnapshot string `json:"from_snapshot,omitempty"`
+
+	// Snapshots are the images saved from the container when it stopped
+	Snapshots []string `json:"snapshots,omitempty"`
+
+	StartedAt  time.Time `json:"started_at"`
+	StoppedAt  time.Time `json:"stopped_at,omitempty"`
+	StopReason string    `json:"stop_reason,omitempty"`
+}
+
+// Key identifies the run when it stops: its task ID for tasks, its name for
+// containers
+func (r Run) Key() string {
+	if r.Kind == KindTask {
+		return r.ID
+	}
+	return r.Name
+}
+
+// Stopped reports whether the run has been recorded as stopped
+func (r Run) Stopped() bool {
+	return !r.StoppedAt.IsZero()
+}
+
+// Duration is how long the run lasted, or has lasted so far
+func (r Run) Duration() time.Duration {
+	if r.Stopped() {
+		return r.StoppedAt.Sub(r.StartedAt)
+	}
+	return time.Since(r.StartedAt)
+}
+
+// Store is the history database
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens the database at path, creating it if needed
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{runsBucket, latestBucket, indexesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize %s: %w", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Start records a run starting. StartedAt defaults to now.
+func (s *Store) Start(r Run) error {
+	if r.StartedAt.IsZero() {
+		r.StartedAt = time.Now().UTC()
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(runsBucket)
+		seq, err := runs.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := itob(seq)
+		if err := putRun(runs, key, r); err != nil {
+			return err
+		}
+		return tx.Bucket(latestBucket).Put([]byte(r.Key()), key)
+	})
+}
+
+// Stop records the latest run with key stopping, with the snapshots saved
+// from it. A run that was never recorded, or already stopped, is left alone.
+func (s *Store) Stop(key, reason string, snapshots ...string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		seq := tx.Bucket(latestBucket).Get([]byte(key))
+		if seq == nil {
+			return nil
+		}
+		runs := tx.Bucket(runsBucket)
+		r, err := getRun(runs, seq)
+		if err != nil || r.Stopped() {
+			return err
+		}
+		r.StoppedAt = time.Now().UTC()
+		r.StopReason = reason
+		r.Snapshots = append(r.Snapshots, snapshots...)
+		return putRun(runs, seq, r)
+	})
+}
+
+// Filter selects runs from the history; empty fields match everything
+type Filter struct {
+	Name    string // exact container name or profile
+	Profile string
+	Repo    string // substring of the repo URL or path
+	Kind    string
+	Limit   int // 0 for all
+}
+
+func (f Filter) matches(r Run) bool {
+	return (f.Name == "" || r.Name == f.Name) &&
+		(f.Profile == "" || r.Profile == f.Profile) &&
+		(f.Repo == "" || strings.Contains(r.Repo, f.Repo)) &&
+		(f.Kind == "" || r.Kind == f.Kind)
+}
+
+// List returns the runs matching f, newest first
+func (s *Store) List(f Filter) ([]Run, error) {
+	var runs []Run
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(runsBucket).Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var r Run
+			if err := json.Unmarshal(v, &r); err != nil {
+				return fmt.Errorf("invalid run %d: %w", binary.BigEndian.Uint64(k), err)
+			}
+			if !f.matches(r) {
+				continue
+			}
+			runs = append(runs, r)
+			if f.Limit > 0 && len(runs) == f.Limit {
+				break
+			}
+		}
+		return nil
+	})
+	return runs, err
+}
+
+// Lineage returns the runs r's workspace came from, nearest first: the run
+// that saved the snapshot r resumed from, the run that one resumed from, and
+// so on
+func (s *Store) Lineage(r Run) ([]Run, error) {
+	all, err := s.List(Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	var lineage []Run
+	for r.FromSnapshot != "" && len(lineage) < len(all) {
+		// Repo snapshots keep their tag, so the parent is the latest run
+		// that saved the image before this one started
+		i := slices.IndexFunc(all, func(p Run) bool {
+			return p.Stopped() && p.StoppedAt.Before(r.StartedAt) && slices.Contains(p.Snapshots, r.FromSnapshot)
+		})
+		if i < 0 {
+			break
+		}
+		r = all[i]
+		lineage = append(lineage, r)
+	}
+	return lineage, nil
+}
+
+// NextIndex hands out the next container index for profile, above both
+// every index handed out before and inUse. Indexes aren't reused, so a name
+// always means the same container in the history.
+func (s *Store) NextIndex(profile string, inUse int) (int, error) {
+	var next int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		indexes := tx.Bucket(indexesBucket)
+		last := 0
+		if v := indexes.Get([]byte(profile)); len(v) == 8 {
+			last = int(binary.BigEndian.Uint64(v))
+		}
+		next = max(last, inUse) + 1
+		return indexes.Put([]byte(profile), itob(uint64(next)))
+	})
+	return next, err
+}
+
+func putRun(b *bolt.Bucket, key []byte, r Run) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, data)
+}
+
+func getRun(b *bolt.Bucket, key []byte) (Run, error) {
+	var r Run
+	data := b.Get(key)
+	if data == nil {
+		return r, errors.New("run not found")
+	}
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+// itob encodes a sequence number so keys sort in order
+func itob(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
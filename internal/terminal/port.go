@@ -29,10 +29,8 @@ func (p *PortAllocator) Allocate(containerName string) (int, error) {
 	defer p.mu.Unlock()
 
 	// Check if container already has a port
-	for port, name := range p.used {
-		if name == containerName {
-			return port, nil
-		}
+	if port, ok := p.firstPort(containerName); ok {
+		return port, nil
 	}
 
 	// Find next available port range (we need 4 consecutive ports: web, claude, bash, status)
@@ -92,12 +90,18 @@ func (p *PortAllocator) GetPort(containerName string) (int, bool) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	return p.firstPort(containerName)
+}
+
+// firstPort returns the lowest port used by a container; callers hold mu
+func (p *PortAllocator) firstPort(containerName string) (int, bool) {
+	first := 0
 	for port, name := range p.used {
-		if name == containerName {
-			return port, true
+		if name == containerName && (first == 0 || port < first) {
+			first = port
 		}
 	}
-	return 0, false
+	return first, first != 0
 }
 
 // MarkUsed marks a port as used by a container
@@ -119,6 +123,11 @@ func (p *PortAllocator) GetUsedPorts() map[int]string {
 	return result
 }
 
+// IsPortAvailable reports whether a host port can currently be bound
+func IsPortAvailable(port int) bool {
+	return isPortAvailable(port)
+}
+
 // isPortAvailable checks if a port is available
 func isPortAvailable(port int) bool {
 	addr := fmt.Sprintf(":%d", port)
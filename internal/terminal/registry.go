@@ -0,0 +1,158 @@
+package terminal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// PortsPerContainer is the size of each allocated range (web, claude, bash, status)
+const PortsPerContainer = 4
+
+// DefaultLeaseTTL is how long a port range stays reserved after its
+// container was last seen
+const DefaultLeaseTTL = 7 * 24 * time.Hour
+
+// registryFileName is the registry file inside the frank config directory
+const registryFileName = "ports.json"
+
+// PortLease reserves a container's port range
+type PortLease struct {
+	Port        int       `json:"port"` // First port of the range
+	Container   string    `json:"container"`
+	AllocatedAt time.Time `json:"allocatedAt"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// Expired reports whether the lease has lapsed at now
+func (l PortLease) Expired(now time.Time) bool {
+	return now.After(l.ExpiresAt)
+}
+
+// PortRegistry persists port leases so stopped containers keep their ports
+// across frank invocations
+type PortRegistry struct {
+	path   string
+	Leases []PortLease `json:"leases"`
+}
+
+// RegistryPath returns the registry file path inside configDir
+func RegistryPath(configDir string) string {
+	return filepath.Join(configDir, registryFileName)
+}
+
+// NewPortRegistry creates an empty registry that saves to path
+func NewPortRegistry(path string) *PortRegistry {
+	return &PortRegistry{path: path}
+}
+
+// LoadPortRegistry reads the registry at path. A missing file is an empty registry.
+func LoadPortRegistry(path string) (*PortRegistry, error) {
+	r := NewPortRegistry(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return r, nil
+		}
+		return nil, fmt.Errorf("failed to read port registry: %w", err)
+	}
+
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("failed to parse port registry: %w", err)
+	}
+	r.sort()
+	return r, nil
+}
+
+// Save writes the registry, replacing the file atomically
+func (r *PortRegistry) Save() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	r.sort()
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal port registry: %w", err)
+	}
+
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write port registry: %w", err)
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write port registry: %w", err)
+	}
+	return nil
+}
+
+// Lease reserves port for containerName until ttl from now. An existing
+// lease for the container is renewed and moved to port.
+func (r *PortRegistry) Lease(containerName string, port int, ttl time.Duration) {
+	now := time.Now()
+	for i := range r.Leases {
+		if r.Leases[i].Container == containerName {
+			if r.Leases[i].Port != port {
+				r.Leases[i].Port = port
+				r.Leases[i].AllocatedAt = now
+			}
+			r.Leases[i].ExpiresAt = now.Add(ttl)
+			return
+		}
+	}
+	r.Leases = append(r.Leases, PortLease{
+		Port:        port,
+		Container:   containerName,
+		AllocatedAt: now,
+		ExpiresAt:   now.Add(ttl),
+	})
+}
+
+// Release removes the lease held by a container name or covering a port
+// number, returning the removed lease
+func (r *PortRegistry) Release(nameOrPort string) (PortLease, bool) {
+	port, _ := strconv.Atoi(nameOrPort)
+	for i, l := range r.Leases {
+		if l.Container == nameOrPort || (port > 0 && port >= l.Port && port < l.Port+PortsPerContainer) {
+			r.Leases = append(r.Leases[:i], r.Leases[i+1:]...)
+			return l, true
+		}
+	}
+	return PortLease{}, false
+}
+
+// Prune drops leases that expired before now and returns them
+func (r *PortRegistry) Prune(now time.Time) []PortLease {
+	var kept, expired []PortLease
+	for _, l := range r.Leases {
+		if l.Expired(now) {
+			expired = append(expired, l)
+		} else {
+			kept = append(kept, l)
+		}
+	}
+	r.Leases = kept
+	return expired
+}
+
+// Reserve marks every leased port range as used in the allocator
+func (r *PortRegistry) Reserve(p *PortAllocator) {
+	for _, l := range r.Leases {
+		for i := 0; i < PortsPerContainer; i++ {
+			p.MarkUsed(l.Port+i, l.Container)
+		}
+	}
+}
+
+// sort orders leases by port
+func (r *PortRegistry) sort() {
+	sort.Slice(r.Leases, func(i, j int) bool {
+		return r.Leases[i].Port < r.Leases[j].Port
+	})
+}
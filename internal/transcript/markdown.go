@@ -0,0 +1,109 @@
+package transcript
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Options controls how a session is rendered
+type Options struct {
+	Thinking       bool // Include Claude's thinking
+	MaxOutputLines int  // Lines of each tool's output to keep; zero keeps all
+}
+
+// Markdown writes the session as a Markdown document
+func (s *Session) Markdown(w io.Writer, opts Options) error {
+	var b strings.Builder
+
+	title := s.Summary
+	if title == "" {
+		title = "Claude session " + s.ID
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+
+	meta := [][2]string{
+		{"Session", s.ID},
+		{"Directory", s.Cwd},
+		{"Branch", s.GitBranch},
+		{"Model", s.Model},
+	}
+	if !s.Start.IsZero() {
+		meta = append(meta,
+			[2]string{"Started", s.Start.UTC().Format(time.RFC3339)},
+			[2]string{"Duration", s.End.Sub(s.Start).Round(time.Second).String()})
+	}
+	for _, m := range meta {
+		if m[1] != "" {
+			fmt.Fprintf(&b, "- **%s:** %s\n", m[0], m[1])
+		}
+	}
+
+	for _, turn := range s.Turns {
+		heading := "User"
+		if turn.Role == "assistant" {
+			heading = "Claude"
+		}
+		if !turn.Time.IsZero() {
+			heading += " · " + turn.Time.UTC().Format("15:04:05")
+		}
+		fmt.Fprintf(&b, "\n## %s\n", heading)
+
+		for _, block := range turn.Blocks {
+			switch block.Kind {
+			case Text:
+				fmt.Fprintf(&b, "\n%s\n", strings.TrimSpace(block.Text))
+			case Thinking:
+				if opts.Thinking {
+					fmt.Fprintf(&b, "\n<details><summary>Thinking</summary>\n\n%s\n\n</details>\n", strings.TrimSpace(block.Text))
+				}
+			case ToolUse:
+				fmt.Fprintf(&b, "\n**%s**\n\n", block.Tool)
+				writeFenced(&b, "json", block.Input)
+			case ToolResult:
+				summary := "Output"
+				if block.IsError {
+					summary = "Error"
+				}
+				output := truncateLines(strings.TrimRight(block.Text, "\n"), opts.MaxOutputLines)
+				if output == "" {
+					output = "(no output)"
+				}
+				fmt.Fprintf(&b, "\n<details><summary>%s</summary>\n\n", summary)
+				writeFenced(&b, "", output)
+				b.WriteString("\n</details>\n")
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeFenced writes text in a code fence longer than any backtick run in it
+func writeFenced(b *strings.Builder, lang, text string) {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	fmt.Fprintf(b, "%s%s\n%s\n%s\n", fence, lang, text, fence)
+}
+
+// truncateLines keeps the first n lines of text, noting how many were cut
+func truncateLines(text string, n int) string {
+	if n <= 0 {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) <= n {
+		return text
+	}
+	return strings.Join(lines[:n], "\n") + fmt.Sprintf("\n… %d more lines", len(lines)-n)
+}
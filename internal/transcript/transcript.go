@@ -0,0 +1,225 @@
+// Package transcript reads Claude Code's session transcripts, the JSONL files
+// under ~/.claude/projects, and renders them as Markdown.
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Block kinds
+const (
+	Text       = "text"
+	Thinking   = "thinking"
+	ToolUse    = "tool_use"
+	ToolResult = "tool_result"
+)
+
+// Session is a parsed transcript
+type Session struct {
+	ID        string
+	Summary   string // Title Claude gave the conversation, if any
+	Cwd       string
+	GitBranch string
+	Model     string
+	Start     time.Time
+	End       time.Time
+	Turns     []Turn
+}
+
+// Turn is a run of consecutive messages from one side. Tool results are
+// part of the assistant turn that called the tool.
+type Turn struct {
+	Role   string // user or assistant
+	Time   time.Time
+	Blocks []Block
+}
+
+// Block is one piece of a message
+type Block struct {
+	Kind    string
+	Text    string // Text, thinking or tool output
+	Tool    string // Tool name, for tool_use
+	Input   string // Tool input as indented JSON, for tool_use
+	IsError bool   // The tool failed, for tool_result
+}
+
+// entry is one line of a transcript
+type entry struct {
+	Type        string    `json:"type"`
+	SessionID   string    `json:"sessionId"`
+	Cwd         string    `json:"cwd"`
+	GitBranch   string    `json:"gitBranch"`
+	Timestamp   time.Time `json:"timestamp"`
+	IsMeta      bool      `json:"isMeta"`
+	IsSidechain bool      `json:"isSidechain"`
+	Summary     string    `json:"summary"`
+	Message     *struct {
+		Role    string          `json:"role"`
+		Model   string          `json:"model"`
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// content is a block of a message's content array
+type content struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text"`
+	Thinking  string          `json:"thinking"`
+	Name      string          `json:"name"`
+	Input     json.RawMessage `json:"input"`
+	Content   json.RawMessage `json:"content"`
+	IsError   bool            `json:"is_error"`
+	ToolUseID string          `json:"tool_use_id"`
+}
+
+// Parse reads a transcript. Lines that aren't messages, meta messages and
+// subagent (sidechain) messages are skipped.
+func Parse(r io.Reader) (*Session, error) {
+	s := &Session{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var e entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			continue
+		}
+		s.add(e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	return s, nil
+}
+
+func (s *Session) add(e entry) {
+	if e.Type == "summary" {
+		if e.Summary != "" {
+			s.Summary = e.Summary
+		}
+		return
+	}
+	if (e.Type != "user" && e.Type != "assistant") || e.Message == nil || e.IsMeta || e.IsSidechain {
+		return
+	}
+
+	if s.ID == "" {
+		s.ID = e.SessionID
+	}
+	if e.Cwd != "" {
+		s.Cwd = e.Cwd
+	}
+	if e.GitBranch != "" {
+		s.GitBranch = e.GitBranch
+	}
+	if e.Message.Model != "" && !strings.HasPrefix(e.Message.Model, "<") {
+		s.Model = e.Message.Model
+	}
+	if !e.Timestamp.IsZero() {
+		if s.Start.IsZero() {
+			s.Start = e.Timestamp
+		}
+		s.End = e.Timestamp
+	}
+
+	blocks := parseContent(e.Message.Content)
+	if len(blocks) == 0 {
+		return
+	}
+
+	// Tool results arrive as user messages but answer the assistant
+	role := e.Type
+	if role == "user" && allToolResults(blocks) {
+		role = "assistant"
+	}
+	if n := len(s.Turns); n > 0 && s.Turns[n-1].Role == role {
+		s.Turns[n-1].Blocks = append(s.Turns[n-1].Blocks, blocks...)
+		return
+	}
+	s.Turns = append(s.Turns, Turn{Role: role, Time: e.Timestamp, Blocks: blocks})
+}
+
+// parseContent reads a message's content, a string or an array of blocks
+func parseContent(raw json.RawMessage) []Block {
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		return []Block{{Kind: Text, Text: text}}
+	}
+
+	var parts []content
+	if json.Unmarshal(raw, &parts) != nil {
+		return nil
+	}
+	var blocks []Block
+	for _, p := range parts {
+		switch p.Type {
+		case Text:
+			if strings.TrimSpace(p.Text) != "" {
+				blocks = append(blocks, Block{Kind: Text, Text: p.Text})
+			}
+		case Thinking:
+			if strings.TrimSpace(p.Thinking) != "" {
+				blocks = append(blocks, Block{Kind: Thinking, Text: p.Thinking})
+			}
+		case ToolUse:
+			blocks = append(blocks, Block{Kind: ToolUse, Tool: p.Name, Input: indentJSON(p.Input)})
+		case ToolResult:
+			blocks = append(blocks, Block{Kind: ToolResult, Text: resultText(p.Content), IsError: p.IsError})
+		}
+	}
+	return blocks
+}
+
+// resultText flattens a tool result's content, a string or text blocks
+func resultText(raw json.RawMessage) string {
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return text
+	}
+	var parts []content
+	if json.Unmarshal(raw, &parts) != nil {
+		return ""
+	}
+	var texts []string
+	for _, p := range parts {
+		switch p.Type {
+		case Text:
+			texts = append(texts, p.Text)
+		case "image":
+			texts = append(texts, "[image]")
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func allToolResults(blocks []Block) bool {
+	for _, b := range blocks {
+		if b.Kind != ToolResult {
+			return false
+		}
+	}
+	return true
+}
+
+func indentJSON(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var out bytes.Buffer
+	if json.Indent(&out, raw, "", "  ") != nil {
+		return string(raw)
+	}
+	return out.String()
+}
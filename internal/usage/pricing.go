@@ -0,0 +1,37 @@
+package usage
+
+import "strings"
+
+// Pricing is a model's list price in USD per million tokens and its context
+// window in tokens
+type Pricing struct {
+	Input         float64
+	Output        float64
+	CacheRead     float64
+	CacheWrite    float64
+	ContextWindow int64
+}
+
+// pricing by model family, matched as a substring of the model name in order
+var pricing = []struct {
+	family string
+	price  Pricing
+}{
+	{"opus", Pricing{Input: 15, Output: 75, CacheRead: 1.5, CacheWrite: 18.75, ContextWindow: 200_000}},
+	{"sonnet", Pricing{Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75, ContextWindow: 200_000}},
+	{"haiku", Pricing{Input: 1, Output: 5, CacheRead: 0.1, CacheWrite: 1.25, ContextWindow: 200_000}},
+	{"gpt-5", Pricing{Input: 1.25, Output: 10, CacheRead: 0.125, ContextWindow: 272_000}},
+	{"codex", Pricing{Input: 1.25, Output: 10, CacheRead: 0.125, ContextWindow: 272_000}},
+}
+
+// PricingFor returns the prices of a model, using Sonnet's for unknown or
+// empty model names
+func PricingFor(model string) Pricing {
+	model = strings.ToLower(model)
+	for _, p := range pricing {
+		if strings.Contains(model, p.family) {
+			return p.price
+		}
+	}
+	return pricing[1].price
+}
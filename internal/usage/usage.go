@@ -0,0 +1,225 @@
+// Package usage totals the tokens and cost of agent sessions from the JSON
+// lines Claude Code and Codex write: Claude transcripts and stream-json
+// output, Codex rollouts and exec --json output.
+//
+// Claude reports usage per API response, so tokens are summed across
+// responses. Codex rollouts report a running session total, so the last total
+// of each session counts. Cost is what the agent reported when it did,
+// otherwise an estimate from list prices.
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// SourceSeparator starts a new source within one stream, as printed by
+// tail -n +1 between files
+const SourceSeparator = "==> "
+
+// Usage is the token use and cost of one or more sessions
+type Usage struct {
+	InputTokens         int64   `json:"input_tokens"`
+	OutputTokens        int64   `json:"output_tokens"`
+	CacheReadTokens     int64   `json:"cache_read_tokens"`
+	CacheCreationTokens int64   `json:"cache_creation_tokens"`
+	ReportedCostUSD     float64 `json:"reported_cost_usd,omitempty"`
+	Model               string  `json:"model,omitempty"`
+
+	// ContextTokens is the prompt size of the latest response, i.e. how full
+	// the context window is
+	ContextTokens int64 `json:"context_tokens"`
+}
+
+// Tokens returns all tokens used, cached or not
+func (u Usage) Tokens() int64 {
+	return u.InputTokens + u.OutputTokens + u.CacheReadTokens + u.CacheCreationTokens
+}
+
+// CostUSD returns the reported cost, or an estimate from the model's list
+// prices when the agent didn't report one
+func (u Usage) CostUSD() float64 {
+	if u.ReportedCostUSD > 0 {
+		return u.ReportedCostUSD
+	}
+	p := PricingFor(u.Model)
+	return (float64(u.InputTokens)*p.Input +
+		float64(u.OutputTokens)*p.Output +
+		float64(u.CacheReadTokens)*p.CacheRead +
+		float64(u.CacheCreationTokens)*p.CacheWrite) / 1_000_000
+}
+
+// Estimated reports whether CostUSD is an estimate
+func (u Usage) Estimated() bool {
+	return u.ReportedCostUSD == 0
+}
+
+// ContextPercent returns how full the model's context window is
+func (u Usage) ContextPercent() float64 {
+	return float64(u.ContextTokens) * 100 / float64(PricingFor(u.Model).ContextWindow)
+}
+
+// Tracker accumulates usage from log and transcript lines
+type Tracker struct {
+	total Usage
+
+	// seen holds Claude message IDs already counted; a response split over
+	// several transcript entries repeats its usage in each
+	seen map[string]bool
+
+	// responses counts Claude responses since the last result line, whose
+	// usage repeats theirs
+	responses int
+
+	// session is the running total of the current Codex rollout
+	session Usage
+}
+
+// NewTracker creates an empty tracker
+func NewTracker() *Tracker {
+	return &Tracker{seen: make(map[string]bool)}
+}
+
+// Read feeds every line of r to the tracker, ending the stream's source
+func (t *Tracker) Read(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		t.Line(scanner.Text())
+	}
+	t.endSource()
+	return scanner.Err()
+}
+
+// Line feeds one line to the tracker. Lines without usage are ignored.
+func (t *Tracker) Line(line string) {
+	if strings.HasPrefix(line, SourceSeparator) {
+		t.endSource()
+		return
+	}
+	start := strings.IndexByte(line, '{')
+	if start < 0 {
+		return
+	}
+
+	var entry logEntry
+	if err := json.Unmarshal([]byte(line[start:]), &entry); err != nil {
+		return
+	}
+
+	switch {
+	case entry.Message != nil && entry.Message.Usage != nil:
+		t.claudeResponse(entry.Message)
+	case entry.Type == "result":
+		t.claudeResult(entry)
+	case entry.Type == "turn.completed" && entry.Usage != nil:
+		// codex exec --json reports each turn separately
+		t.add(entry.Usage.usage())
+	case entry.Type == "event_msg" && entry.Payload != nil && entry.Payload.Type == "token_count" && entry.Payload.Info != nil:
+		// Rollouts report a running total and the latest request
+		t.session = entry.Payload.Info.Total.usage()
+		t.total.ContextTokens = entry.Payload.Info.Last.InputTokens
+	case entry.Type == "turn_context" && entry.Payload != nil && entry.Payload.Model != "":
+		t.total.Model = entry.Payload.Model
+	}
+
+	if entry.CostUSD > 0 {
+		t.total.ReportedCostUSD += entry.CostUSD
+	}
+}
+
+// Usage returns the usage seen so far
+func (t *Tracker) Usage() Usage {
+	u := t.total
+	u.InputTokens += t.session.InputTokens
+	u.OutputTokens += t.session.OutputTokens
+	u.CacheReadTokens += t.session.CacheReadTokens
+	return u
+}
+
+func (t *Tracker) claudeResponse(msg *message) {
+	if msg.ID != "" {
+		if t.seen[msg.ID] {
+			return
+		}
+		t.seen[msg.ID] = true
+	}
+	if msg.Model != "" && msg.Model != "<synthetic>" {
+		t.total.Model = msg.Model
+	}
+	u := msg.Usage.usage()
+	t.add(u)
+	t.total.ContextTokens = u.InputTokens + u.CacheReadTokens + u.CacheCreationTokens
+	t.responses++
+}
+
+func (t *Tracker) claudeResult(entry logEntry) {
+	t.total.ReportedCostUSD += entry.TotalCostUSD
+	// With --output-format json the result is the only line with usage
+	if t.responses == 0 && entry.Usage != nil {
+		t.add(entry.Usage.usage())
+	}
+	t.responses = 0
+}
+
+func (t *Tracker) add(u Usage) {
+	t.total.InputTokens += u.InputTokens
+	t.total.OutputTokens += u.OutputTokens
+	t.total.CacheReadTokens += u.CacheReadTokens
+	t.total.CacheCreationTokens += u.CacheCreationTokens
+}
+
+// endSource adds the running Codex session total and resets per-source state
+func (t *Tracker) endSource() {
+	t.add(t.session)
+	t.session = Usage{}
+	t.responses = 0
+}
+
+// logEntry is the union of the line formats that carry usage
+type logEntry struct {
+	Type         string    `json:"type"`
+	Message      *message  `json:"message"`
+	Usage        *rawUsage `json:"usage"`
+	TotalCostUSD float64   `json:"total_cost_usd"`
+	CostUSD      float64   `json:"costUSD"`
+	Payload      *payload  `json:"payload"`
+}
+
+type message struct {
+	ID    string    `json:"id"`
+	Model string    `json:"model"`
+	Usage *rawUsage `json:"usage"`
+}
+
+type payload struct {
+	Type  string     `json:"type"`
+	Model string     `json:"model"`
+	Info  *tokenInfo `json:"info"`
+}
+
+type tokenInfo struct {
+	Total rawUsage `json:"total_token_usage"`
+	Last  rawUsage `json:"last_token_usage"`
+}
+
+// rawUsage holds both agents' field names. Claude counts cached tokens
+// separately from input_tokens; Codex includes them in it.
+type rawUsage struct {
+	InputTokens              int64 `json:"input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CachedInputTokens        int64 `json:"cached_input_tokens"`
+}
+
+func (r rawUsage) usage() Usage {
+	return Usage{
+		InputTokens:         r.InputTokens - r.CachedInputTokens,
+		OutputTokens:        r.OutputTokens,
+		CacheReadTokens:     r.CacheReadInputTokens + r.CachedInputTokens,
+		CacheCreationTokens: r.CacheCreationInputTokens,
+	}
+}